@@ -0,0 +1,204 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Annotation is a comment attached to a page of a PDF file, such as a
+// highlight, a sticky note or a free text box
+type Annotation struct {
+	// Page is the number of the page the annotation is on
+	Page int `json:"page"`
+	// Type is the annotation subtype, e.g. "Highlight", "Text" for sticky
+	// notes or "FreeText"
+	Type string `json:"type"`
+	// Contents is the text of the annotation, e.g. the comment on a
+	// highlight. It is empty for annotations without a comment.
+	Contents string `json:"contents,omitempty"`
+	// Author is the author of the annotation
+	Author string `json:"author,omitempty"`
+	// Subject is the subject of the annotation
+	Subject string `json:"subject,omitempty"`
+	// Rect is the area of the annotation on the unrotated page
+	Rect Rect `json:"rect"`
+}
+
+// ignoredAnnotations are the annotation subtypes that carry no comments:
+// links, form fields and the popup windows showing the contents of other
+// annotations
+var ignoredAnnotations = map[pdfName]bool{"Link": true, "Widget": true, "Popup": true}
+
+// Annotations returns the annotations of a PDF file in page order, since
+// reviewer comments are often the most important text of a document and
+// pdftotext ignores them. Links, form fields and popups are left out. It
+// returns ErrEncrypted for encrypted files, whose annotation text cannot be
+// read without decrypting them.
+func (c *Converter) Annotations(ctx context.Context, inputPath string) ([]Annotation, error) {
+	if _, err := c.confine(ctx, inputPath, ""); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return parseAnnotations(data)
+}
+
+// readCatalog indexes the objects of a PDF file and returns its document
+// catalog. Strings of encrypted files cannot be read, so it returns
+// ErrEncrypted for them.
+func readCatalog(data []byte) (*pdfFile, pdfDict, error) {
+	f := newPDFFile(data)
+	trailer, err := f.trailer()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	if trailer["Encrypt"] != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrPDFOpen, ErrEncrypted)
+	}
+	root, err := f.resolve(trailer["Root"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	catalog, ok := root.(pdfDict)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %w: catalog is not a dictionary", ErrPDFOpen, errPDFSyntax)
+	}
+	return f, catalog, nil
+}
+
+// parseAnnotations reads the annotations of a PDF file
+func parseAnnotations(data []byte) ([]Annotation, error) {
+	f, catalog, err := readCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := []Annotation{}
+	number := 0
+	f.walkPageTree(catalog["Pages"], nil, 0, func(page pdfDict, mediaBox []any) {
+		number++
+		annots, err := f.resolve(page["Annots"])
+		if err != nil {
+			return
+		}
+		list, _ := annots.([]any)
+		for _, a := range list {
+			v, err := f.resolve(a)
+			if err != nil {
+				continue
+			}
+			dict, ok := v.(pdfDict)
+			if !ok {
+				continue
+			}
+			subtype, _ := dict["Subtype"].(pdfName)
+			if subtype == "" || ignoredAnnotations[subtype] {
+				continue
+			}
+			annotations = append(annotations, Annotation{
+				Page:     number,
+				Type:     string(subtype),
+				Contents: f.textString(dict["Contents"]),
+				Author:   f.textString(dict["T"]),
+				Subject:  f.textString(dict["Subj"]),
+				Rect:     f.pageRect(dict["Rect"], mediaBox),
+			})
+		}
+	})
+	return annotations, nil
+}
+
+// walkPageTree calls fn with each page of a page tree in order, and the
+// media box it has or inherits
+func (f *pdfFile) walkPageTree(node any, mediaBox []any, depth int, fn func(page pdfDict, mediaBox []any)) {
+	if depth > maxObjectDepth {
+		return
+	}
+	v, err := f.resolve(node)
+	if err != nil {
+		return
+	}
+	dict, ok := v.(pdfDict)
+	if !ok {
+		return
+	}
+	if box, err := f.resolve(dict["MediaBox"]); err == nil {
+		if box, ok := box.([]any); ok && len(box) == 4 {
+			mediaBox = box
+		}
+	}
+
+	if dict["Type"] == pdfName("Page") || dict["Kids"] == nil {
+		fn(dict, mediaBox)
+		return
+	}
+	kids, err := f.resolve(dict["Kids"])
+	if err != nil {
+		return
+	}
+	list, _ := kids.([]any)
+	for _, kid := range list {
+		f.walkPageTree(kid, mediaBox, depth+1, fn)
+	}
+}
+
+// textString resolves v and decodes it as a text string, or returns an
+// empty string if it is not a string
+func (f *pdfFile) textString(v any) string {
+	v, err := f.resolve(v)
+	if err != nil {
+		return ""
+	}
+	s, _ := v.(string)
+	return decodeTextString(s)
+}
+
+// pageRect converts a PDF rectangle, with the origin at the bottom-left
+// corner, to a Rect with the origin at the top-left corner of the media box
+func (f *pdfFile) pageRect(v any, mediaBox []any) Rect {
+	rect := f.numbers(v)
+	if len(rect) != 4 {
+		return Rect{}
+	}
+	left, top := 0.0, 0.0
+	if box := f.numbers(mediaBox); len(box) == 4 {
+		left, top = min(box[0], box[2]), max(box[1], box[3])
+	}
+	return Rect{
+		XMin: min(rect[0], rect[2]) - left,
+		YMin: top - max(rect[1], rect[3]),
+		XMax: max(rect[0], rect[2]) - left,
+		YMax: top - min(rect[1], rect[3]),
+	}
+}
+
+// numbers resolves v as an array of numbers, or returns nil
+func (f *pdfFile) numbers(v any) []float64 {
+	v, err := f.resolve(v)
+	if err != nil {
+		return nil
+	}
+	array, _ := v.([]any)
+	numbers := make([]float64, 0, len(array))
+	for _, item := range array {
+		item, err := f.resolve(item)
+		if err != nil {
+			return nil
+		}
+		switch n := item.(type) {
+		case int:
+			numbers = append(numbers, float64(n))
+		case float64:
+			numbers = append(numbers, n)
+		default:
+			return nil
+		}
+	}
+	return numbers
+}
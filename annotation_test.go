@@ -0,0 +1,101 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// annotatedPDF is a minimal PDF with a highlight and a link on its first page
+// and a sticky note with a popup on its second, which has its own media box
+const annotatedPDF = `%PDF-1.4
+1 0 obj
+<< /Type /Catalog /Pages 2 0 R >>
+endobj
+2 0 obj
+<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 612 792] >>
+endobj
+3 0 obj
+<< /Type /Page /Parent 2 0 R /Annots [5 0 R << /Type /Annot /Subtype /Link /Rect [0 0 10 10] >>] >>
+endobj
+4 0 obj
+<< /Type /Page /Parent 2 0 R /MediaBox [0 0 400 600] /Annots 6 0 R >>
+endobj
+5 0 obj
+<< /Type /Annot /Subtype /Highlight /Rect [72 700 300.5 714] /Contents <FEFF0043006800650063006B> /T (Alice) /Subj (Review) >>
+endobj
+6 0 obj
+[<< /Type /Annot /Subtype /Text /Rect [350 560 370 580] /Contents (Needs a \(source\).) /Popup 7 0 R >> 7 0 R]
+endobj
+7 0 obj
+<< /Type /Annot /Subtype /Popup /Rect [350 400 500 560] >>
+endobj
+trailer
+<< /Root 1 0 R /Size 8 >>
+%%EOF
+`
+
+func TestParseAnnotations(t *testing.T) {
+	annotations, err := parseAnnotations([]byte(annotatedPDF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Annotation{
+		{Page: 1, Type: "Highlight", Contents: "Check", Author: "Alice", Subject: "Review", Rect: Rect{XMin: 72, YMin: 78, XMax: 300.5, YMax: 92}},
+		{Page: 2, Type: "Text", Contents: "Needs a (source).", Rect: Rect{XMin: 350, YMin: 20, XMax: 370, YMax: 40}},
+	}
+	if !reflect.DeepEqual(annotations, expected) {
+		t.Errorf("expected %+v, got %+v", expected, annotations)
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		expected error
+	}{
+		{
+			name:     "Encrypted",
+			data:     "1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R >>\n",
+			expected: ErrEncrypted,
+		},
+		{
+			name:     "No trailer",
+			data:     "not a PDF",
+			expected: ErrPDFOpen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseAnnotations([]byte(tt.data)); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestConverter_Annotations(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "annotated.pdf")
+	if err := os.WriteFile(input, []byte(annotatedPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	converter, err := New()
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	annotations, err := converter.Annotations(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(annotations) != 2 {
+		t.Errorf("expected 2 annotations, got %+v", annotations)
+	}
+
+	_, err = converter.Annotations(context.Background(), filepath.Join(t.TempDir(), "missing.pdf"))
+	if !errors.Is(err, ErrPDFOpen) {
+		t.Errorf("expected ErrPDFOpen, got %v", err)
+	}
+}
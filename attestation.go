@@ -0,0 +1,181 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+const (
+	// StatementType is the in-toto statement type of attestations
+	StatementType = "https://in-toto.io/Statement/v1"
+	// ProvenancePredicateType is the SLSA provenance predicate type of attestations
+	ProvenancePredicateType = "https://slsa.dev/provenance/v1"
+	// ProvenanceBuildType identifies a pdftotext conversion as the SLSA build type
+	ProvenanceBuildType = "https://github.com/joeychilson/pdftotext/conversion/v1"
+	// EnvelopePayloadType is the DSSE payload type of signed attestations
+	EnvelopePayloadType = "application/vnd.in-toto+json"
+)
+
+// Statement is an in-toto attestation statement
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Provenance           `json:"predicate"`
+}
+
+// ResourceDescriptor describes an artifact in an attestation
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// Provenance is a SLSA v1 provenance predicate describing a conversion
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the tool, parameters and inputs of a conversion
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   map[string]any       `json:"externalParameters"`
+	InternalParameters   map[string]any       `json:"internalParameters,omitempty"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// RunDetails describes the environment a conversion ran in
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the tool that performed a conversion
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// BuildMetadata holds the timing of a conversion
+type BuildMetadata struct {
+	StartedOn  string `json:"startedOn,omitempty"`
+	FinishedOn string `json:"finishedOn,omitempty"`
+}
+
+// Envelope is a DSSE envelope carrying a signed attestation
+type Envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     []byte              `json:"payload"`
+	Signatures  []EnvelopeSignature `json:"signatures"`
+}
+
+// EnvelopeSignature is a signature in a DSSE envelope
+type EnvelopeSignature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+// NewStatement returns the provenance attestation describing the conversion recorded in m
+func NewStatement(m *Manifest) *Statement {
+	binary := ResourceDescriptor{Name: "pdftotext", URI: "file://" + filepath.ToSlash(m.Binary.Path)}
+	if m.Binary.Digest != "" {
+		binary.Digest = map[string]string{m.hashAlgorithm(): m.Binary.Digest}
+	}
+	parameters := map[string]any{"args": m.Args}
+	if len(m.Commands) > 0 {
+		parameters["commands"] = m.Commands
+	}
+	return &Statement{
+		Type: StatementType,
+		Subject: []ResourceDescriptor{{
+			Name:   filepath.Base(m.Output.Path),
+			Digest: map[string]string{m.hashAlgorithm(): m.Output.Digest},
+		}},
+		PredicateType: ProvenancePredicateType,
+		Predicate: Provenance{
+			BuildDefinition: BuildDefinition{
+				BuildType:          ProvenanceBuildType,
+				ExternalParameters: parameters,
+				InternalParameters: map[string]any{
+					"goos":   runtime.GOOS,
+					"goarch": runtime.GOARCH,
+				},
+				ResolvedDependencies: []ResourceDescriptor{
+					{
+						Name:   filepath.Base(m.Input.Path),
+						Digest: map[string]string{m.hashAlgorithm(): m.Input.Digest},
+					},
+					binary,
+				},
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{
+					ID:      ProvenanceBuildType,
+					Version: map[string]string{"pdftotext": m.BinaryVersion},
+				},
+				Metadata: BuildMetadata{
+					StartedOn:  m.StartedAt.Format(time.RFC3339),
+					FinishedOn: m.CreatedAt.Format(time.RFC3339),
+				},
+			},
+		},
+	}
+}
+
+// ConvertToFileWithAttestation converts a PDF file to text like ConvertToFile
+// and writes an in-toto provenance statement next to the output file
+// (outputPath + ".intoto.json"). When signer is not nil the statement is
+// wrapped in a signed DSSE envelope instead.
+func (c *Converter) ConvertToFileWithAttestation(ctx context.Context, inputPath, outputPath string, opts *Options, signer Signer) (*Statement, error) {
+	m, err := c.convertWithManifest(ctx, inputPath, outputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := NewStatement(m)
+	if signer == nil {
+		return statement, writeJSON(ctx, c.output(), outputPath+".intoto.json", statement)
+	}
+
+	envelope, err := SignStatement(statement, signer)
+	if err != nil {
+		return nil, err
+	}
+	return statement, writeJSON(ctx, c.output(), outputPath+".intoto.json", envelope)
+}
+
+// SignStatement signs statement and returns it wrapped in a DSSE envelope
+func SignStatement(statement *Statement, signer Signer) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode statement: %w", err)
+	}
+	sig, err := signer.Sign(PAE(EnvelopePayloadType, payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign statement: %w", err)
+	}
+	return &Envelope{
+		PayloadType: EnvelopePayloadType,
+		Payload:     payload,
+		Signatures:  []EnvelopeSignature{{Sig: sig}},
+	}, nil
+}
+
+// PAE returns the DSSE pre-authentication encoding of payload, which is what
+// envelope signatures are computed over
+func PAE(payloadType string, payload []byte) []byte {
+	b := []byte("DSSEv1 ")
+	b = strconv.AppendInt(b, int64(len(payloadType)), 10)
+	b = append(b, ' ')
+	b = append(b, payloadType...)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(len(payload)), 10)
+	b = append(b, ' ')
+	return append(b, payload...)
+}
@@ -0,0 +1,89 @@
+package pdftotext
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConverter_ConvertToFileWithAttestation(t *testing.T) {
+	binary := writeFakeBinary(t, `if [ "$1" = "-v" ]; then
+	echo "pdftotext version 24.02.0" >&2
+	exit 0
+fi
+for arg in "$@"; do out=$arg; done
+printf 'extracted text\f' > "$out"
+`)
+	converter, err := New(WithBinaryPath(binary))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		signer Signer
+	}{
+		{name: "Unsigned statement"},
+		{name: "Signed envelope", signer: ed25519Signer{private}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "output.txt")
+			statement, err := converter.ConvertToFileWithAttestation(context.Background(), filepath.Join("testdata", "test.pdf"), outputPath, nil, tt.signer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if statement.Type != StatementType || statement.PredicateType != ProvenancePredicateType {
+				t.Errorf("unexpected statement types %q, %q", statement.Type, statement.PredicateType)
+			}
+			if len(statement.Subject) != 1 || statement.Subject[0].Digest["sha256"] != "dcd686803e42a73c8fb9c682760b0576d12d09cf2441a94a1436cbfb56bc7571" {
+				t.Errorf("unexpected subject: %+v", statement.Subject)
+			}
+			if deps := statement.Predicate.BuildDefinition.ResolvedDependencies; len(deps) != 2 {
+				t.Errorf("expected input and binary dependencies, got %+v", deps)
+			}
+
+			data, err := os.ReadFile(outputPath + ".intoto.json")
+			if err != nil {
+				t.Fatalf("failed to read attestation: %v", err)
+			}
+
+			if tt.signer == nil {
+				var written Statement
+				if err := json.Unmarshal(data, &written); err != nil {
+					t.Fatalf("failed to decode statement: %v", err)
+				}
+				if written.Subject[0].Name != "output.txt" {
+					t.Errorf("expected subject name %q, got %q", "output.txt", written.Subject[0].Name)
+				}
+				return
+			}
+
+			var envelope Envelope
+			if err := json.Unmarshal(data, &envelope); err != nil {
+				t.Fatalf("failed to decode envelope: %v", err)
+			}
+			if !ed25519.Verify(public, PAE(envelope.PayloadType, envelope.Payload), envelope.Signatures[0].Sig) {
+				t.Error("expected envelope signature to verify")
+			}
+		})
+	}
+}
+
+func TestPAE(t *testing.T) {
+	got := string(PAE("http://example.com/HelloWorld", []byte("hello world")))
+	expected := "DSSEv1 29 http://example.com/HelloWorld 11 hello world"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,128 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// actorKey is the context key of the actor of ContextWithActor
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx naming the user or service on whose
+// behalf the conversions called with it run, for their audit records
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// AuditOutcome is the outcome of an audited conversion
+type AuditOutcome string
+
+const (
+	// AuditSuccess is a conversion that returned the text
+	AuditSuccess AuditOutcome = "success"
+	// AuditFailure is a conversion that failed
+	AuditFailure AuditOutcome = "failure"
+)
+
+// AuditRecord records who converted which document, when, how and with what
+// outcome
+type AuditRecord struct {
+	// Time is the time the conversion started
+	Time time.Time `json:"time"`
+	// Actor is the actor of ContextWithActor, if any
+	Actor string `json:"actor,omitempty"`
+	// Input is the converted PDF file, without a digest if it could not be
+	// read
+	Input Artifact `json:"input"`
+	// HashAlgorithm is the name of the algorithm of the digests
+	HashAlgorithm string `json:"hash_algorithm"`
+	// Args are the arguments passed to the binary, with passwords masked
+	// and "<input>" in place of the input path
+	Args []string `json:"args"`
+	// OptionsFingerprint is the digest of the plain-valued options that
+	// change the text, computed with HashAlgorithm
+	OptionsFingerprint string `json:"options_fingerprint"`
+	// Outcome is the outcome of the conversion
+	Outcome AuditOutcome `json:"outcome"`
+	// Error is the error message of a failed conversion
+	Error string `json:"error,omitempty"`
+	// OutputDigest is the hash of the text of a successful conversion
+	OutputDigest string `json:"output_digest,omitempty"`
+	// Duration is how long the conversion took
+	Duration time.Duration `json:"duration"`
+}
+
+// AuditSink receives the audit records of conversions, e.g. to append them to
+// a write-once bucket or ship them to a SIEM. Implementations must be safe for
+// concurrent use.
+type AuditSink interface {
+	// WriteAudit records record
+	WriteAudit(ctx context.Context, record *AuditRecord) error
+}
+
+// JSONAuditSink is an AuditSink appending each record as a line of JSON to a
+// writer, such as a file opened with os.O_APPEND
+type JSONAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONAuditSink returns a JSONAuditSink writing to w
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{w: w}
+}
+
+// WriteAudit writes record as a line of JSON in a single write
+func (s *JSONAuditSink) WriteAudit(_ context.Context, record *AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// Audit returns a Middleware writing an AuditRecord to sink for each
+// conversion, successful or not, hashing the input and the text with the
+// hash algorithm of c. A conversion whose record cannot be written fails, so
+// no document is converted without a trace.
+func (c *Converter) Audit(sink AuditSink) Middleware {
+	return func(next ConvertFunc) ConvertFunc {
+		return func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+			// record the options including the overrides of ctx
+			_, effective := applyOverrides(ctx, opts)
+			record := &AuditRecord{
+				Time:               time.Now(),
+				Input:              Artifact{Path: inputPath},
+				HashAlgorithm:      c.hash.Name,
+				Args:               maskPasswords(c.buildArgs(effective, "<input>", "-")),
+				OptionsFingerprint: optionsFingerprint(effective, c.hash),
+			}
+			record.Actor, _ = ctx.Value(actorKey{}).(string)
+			if input, err := hashFile(inputPath, c.hash); err == nil {
+				record.Input = input
+			}
+
+			text, err := next(ctx, inputPath, opts)
+			record.Duration = time.Since(record.Time)
+			if err != nil {
+				record.Outcome, record.Error = AuditFailure, err.Error()
+			} else {
+				h := c.hash.New()
+				io.WriteString(h, text)
+				record.Outcome, record.OutputDigest = AuditSuccess, hex.EncodeToString(h.Sum(nil))
+			}
+			if werr := sink.WriteAudit(ctx, record); werr != nil {
+				return "", fmt.Errorf("failed to write audit record: %w", werr)
+			}
+			return text, err
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConverter_Audit(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if strings.HasSuffix(args[len(args)-2], "broken.pdf") {
+			io.WriteString(stderr, "Syntax Error: Couldn't read xref table")
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, "text")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.pdf")
+	if err := os.WriteFile(input, []byte("%PDF-1.7"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var log bytes.Buffer
+	convert := Chain(converter.Convert, converter.Audit(NewJSONAuditSink(&log)))
+	ctx := ContextWithActor(context.Background(), "alice@example.com")
+	if _, err := convert(ctx, input, &Options{UserPassword: "secret", Layout: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := convert(ctx, filepath.Join(dir, "broken.pdf"), nil); err == nil {
+		t.Fatalf("expected the conversion of broken.pdf to fail")
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(lines))
+	}
+	var success, failure AuditRecord
+	if err := json.Unmarshal([]byte(lines[0]), &success); err != nil {
+		t.Fatalf("failed to decode record: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &failure); err != nil {
+		t.Fatalf("failed to decode record: %v", err)
+	}
+
+	inputSum := sha256.Sum256([]byte("%PDF-1.7"))
+	textSum := sha256.Sum256([]byte("text"))
+	if success.Actor != "alice@example.com" || success.Outcome != AuditSuccess || success.Time.IsZero() {
+		t.Errorf("unexpected record %+v", success)
+	}
+	if success.Input.Digest != hex.EncodeToString(inputSum[:]) || success.Input.Size != 8 || success.HashAlgorithm != "sha256" {
+		t.Errorf("unexpected input %+v", success.Input)
+	}
+	if success.OutputDigest != hex.EncodeToString(textSum[:]) {
+		t.Errorf("unexpected output digest %s", success.OutputDigest)
+	}
+	if !slices.Contains(success.Args, "-layout") || strings.Contains(lines[0], "secret") {
+		t.Errorf("expected the options with the password masked, got %v", success.Args)
+	}
+	if failure.Outcome != AuditFailure || !strings.Contains(failure.Error, "xref") || failure.Input.Digest != "" || failure.OutputDigest != "" {
+		t.Errorf("unexpected record of the failure %+v", failure)
+	}
+
+	t.Run("SinkError", func(t *testing.T) {
+		convert := Chain(converter.Convert, converter.Audit(failingSink{}))
+		if text, err := convert(ctx, input, nil); !errors.Is(err, io.ErrShortWrite) || text != "" {
+			t.Errorf("expected the conversion to fail with the sink, got %q, %v", text, err)
+		}
+	})
+}
+
+// failingSink is an AuditSink failing every write
+type failingSink struct{}
+
+func (failingSink) WriteAudit(context.Context, *AuditRecord) error {
+	return io.ErrShortWrite
+}
@@ -0,0 +1,207 @@
+package pdftotext
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BatchResult is the result of converting one file of a batch
+type BatchResult struct {
+	// Input is the converted file
+	Input string `json:"input"`
+	// Digest is the hex encoded content hash of the file, with the hash
+	// algorithm of the converter. It is empty if the file could not be read.
+	Digest string `json:"digest,omitempty"`
+	// DuplicateOf is the earlier input of the batch with the same contents,
+	// whose text and error the result shares, or empty if the contents
+	// were converted for this input
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	// Text is the converted text
+	Text string `json:"text,omitempty"`
+	// Skipped reports that ConvertBatchMatching did not convert the file
+	// since it contains none of the keywords
+	Skipped bool `json:"skipped,omitempty"`
+	// Label is the label of the document given by Options.Classifier
+	Label string `json:"label,omitempty"`
+	// Canceled reports that the file was not converted, or its conversion
+	// was interrupted, because the context was done. Err is the context
+	// error.
+	Canceled bool `json:"canceled,omitempty"`
+	// Err is the error of a failed conversion
+	Err error `json:"-"`
+}
+
+// BatchSummary counts the results of a batch by their outcome, so an
+// operator can tell where a canceled run stopped
+type BatchSummary struct {
+	// Total is the number of inputs
+	Total int `json:"total"`
+	// Converted is the number of files converted successfully
+	Converted int `json:"converted"`
+	// Failed is the number of files whose conversion failed
+	Failed int `json:"failed"`
+	// Skipped is the number of files ConvertBatchMatching did not convert
+	Skipped int `json:"skipped"`
+	// Canceled is the number of files left unconverted by a canceled batch
+	Canceled int `json:"canceled"`
+	// Duplicates is the number of inputs sharing the result of an earlier
+	// input, counted in the other numbers as well
+	Duplicates int `json:"duplicates"`
+}
+
+// SummarizeBatch counts the results of ConvertBatch or ConvertBatchMatching
+// by their outcome
+func SummarizeBatch(results []BatchResult) BatchSummary {
+	s := BatchSummary{Total: len(results)}
+	for _, r := range results {
+		switch {
+		case r.Canceled:
+			s.Canceled++
+		case r.Err != nil:
+			s.Failed++
+		case r.Skipped:
+			s.Skipped++
+		default:
+			s.Converted++
+		}
+		if r.DuplicateOf != "" {
+			s.Duplicates++
+		}
+	}
+	return s
+}
+
+// ConvertBatch converts PDF files to text like Convert with up to workers
+// concurrent conversions, returning a result for each input in order. Inputs
+// are hashed first and each unique document is converted once, since ingest
+// folders often hold the same attachment under many names. Duplicates share
+// the result of the first input with the same contents. When ctx is done,
+// no further conversions are started and the files left unconverted are
+// marked Canceled, so the results still tell which files were converted.
+func (c *Converter) ConvertBatch(ctx context.Context, inputs []string, workers int, opts *Options) []BatchResult {
+	return c.convertBatch(ctx, inputs, workers, func(result *BatchResult) {
+		result.Text, result.Err = c.Convert(ctx, result.Input, opts)
+		c.classify(ctx, result, opts)
+	})
+}
+
+// ConvertBatchMatching is ConvertBatch converting only the files containing
+// any of keywords, see ContainsAny, and marking the others Skipped. Checking
+// for the keywords stops at the first page containing one, so on corpora
+// where few documents match most of the work is saved.
+func (c *Converter) ConvertBatchMatching(ctx context.Context, inputs []string, keywords []string, workers int, opts *Options) []BatchResult {
+	return c.convertBatch(ctx, inputs, workers, func(result *BatchResult) {
+		matched, err := c.ContainsAny(ctx, result.Input, keywords, opts)
+		switch {
+		case err != nil:
+			result.Err = err
+		case !matched:
+			result.Skipped = true
+		default:
+			result.Text, result.Err = c.Convert(ctx, result.Input, opts)
+			c.classify(ctx, result, opts)
+		}
+	})
+}
+
+// convertBatch calls convert with up to workers concurrent calls for the
+// result of each unique input and copies the results to the duplicates. Once
+// ctx is done, the inputs not yet converted are marked Canceled.
+func (c *Converter) convertBatch(ctx context.Context, inputs []string, workers int, convert func(result *BatchResult)) []BatchResult {
+	results := make([]BatchResult, len(inputs))
+	first := map[string]int{}
+	var unique []int
+	for i, input := range inputs {
+		results[i].Input = input
+		if ctx.Err() != nil {
+			// the remaining inputs are not even hashed
+			unique = append(unique, i)
+			continue
+		}
+		// unreadable files are converted anyway to report the error
+		if artifact, err := hashFile(input, c.hash); err == nil {
+			results[i].Digest = artifact.Digest
+			if j, ok := first[artifact.Digest]; ok {
+				results[i].DuplicateOf = inputs[j]
+				continue
+			}
+			first[artifact.Digest] = i
+		}
+		unique = append(unique, i)
+	}
+
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for _, i := range unique {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				results[i].Canceled, results[i].Err = true, err
+				return
+			}
+			convert(&results[i])
+			if results[i].Err != nil && ctx.Err() != nil {
+				results[i].Canceled = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range results {
+		if results[i].DuplicateOf != "" {
+			j := first[results[i].Digest]
+			results[i].Text, results[i].Label, results[i].Skipped = results[j].Text, results[j].Label, results[j].Skipped
+			results[i].Canceled, results[i].Err = results[j].Canceled, results[j].Err
+		}
+	}
+	return results
+}
+
+// ContainsAny reports whether the text of a PDF file contains any of
+// keywords, ignoring case and differences in whitespace, so line breaks
+// within a phrase still match. It converts the pages one at a time and stops
+// pdftotext at the first page containing a keyword.
+func (c *Converter) ContainsAny(ctx context.Context, inputPath string, keywords []string, opts *Options) (bool, error) {
+	var needles []string
+	for _, keyword := range keywords {
+		if needle := foldKeyword(keyword); needle != "" {
+			needles = append(needles, needle)
+		}
+	}
+	if len(needles) == 0 {
+		return false, nil
+	}
+
+	found := false
+	checkOpts := Options{}
+	if opts != nil {
+		checkOpts = *opts
+	}
+	checkOpts.StopAfter = func(page int, text string) bool {
+		text = foldKeyword(text)
+		found = slices.ContainsFunc(needles, func(needle string) bool {
+			return strings.Contains(text, needle)
+		})
+		return found
+	}
+	for _, err := range c.StreamPages(ctx, inputPath, &checkOpts) {
+		if err != nil {
+			return false, err
+		}
+	}
+	return found, nil
+}
+
+// foldKeyword returns s in lower case with runs of whitespace collapsed into
+// single spaces
+func foldKeyword(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,193 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConverter_ConvertBatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	invoice := write("invoice.pdf", labeledPDF)
+	copied := write("invoice (1).pdf", labeledPDF)
+	other := write("other.pdf", annotatedPDF)
+	missing := filepath.Join(dir, "missing.pdf")
+
+	failed := errors.New("couldn't open file")
+	var mu sync.Mutex
+	converted := map[string]int{}
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		input := args[len(args)-2]
+		mu.Lock()
+		converted[input]++
+		mu.Unlock()
+		if input == missing {
+			return failed
+		}
+		_, err := io.WriteString(stdout, "text of "+filepath.Base(input))
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	results := converter.ConvertBatch(context.Background(), []string{invoice, other, copied, missing, invoice}, 2, nil)
+	expected := []struct {
+		duplicateOf string
+		text        string
+	}{
+		{"", "text of invoice.pdf"},
+		{"", "text of other.pdf"},
+		{invoice, "text of invoice.pdf"},
+		{"", ""},
+		{invoice, "text of invoice.pdf"},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i, e := range expected {
+		if results[i].DuplicateOf != e.duplicateOf || results[i].Text != e.text {
+			t.Errorf("result %d: expected %q duplicating %q, got %+v", i, e.text, e.duplicateOf, results[i])
+		}
+	}
+	if results[0].Digest == "" || results[0].Digest != results[2].Digest || results[0].Digest == results[1].Digest {
+		t.Errorf("unexpected digests %q, %q, %q", results[0].Digest, results[1].Digest, results[2].Digest)
+	}
+	if !errors.Is(results[3].Err, failed) || results[3].Digest != "" {
+		t.Errorf("expected the missing file to be converted and fail, got %+v", results[3])
+	}
+	if converted[invoice] != 1 || converted[copied] != 0 || converted[other] != 1 {
+		t.Errorf("expected each unique document to be converted once, got %v", converted)
+	}
+}
+
+func TestConverter_ConvertBatchMatching(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.pdf")
+	memo := filepath.Join(dir, "memo.pdf")
+	if err := os.WriteFile(report, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(memo, []byte(annotatedPDF), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		output := "intro\fthe Annual\nReport 2024\fappendix\f"
+		if args[len(args)-2] == memo {
+			output = "a short memo\f"
+		}
+		_, err := io.WriteString(stdout, output)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("ContainsAny", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			keywords []string
+			expected bool
+		}{
+			{report, []string{"annual report"}, true},
+			{report, []string{"budget", "APPENDIX"}, true},
+			{memo, []string{"annual report"}, false},
+			{memo, nil, false},
+		}
+		for _, tt := range tests {
+			matched, err := converter.ContainsAny(ctx, tt.input, tt.keywords, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if matched != tt.expected {
+				t.Errorf("expected %v for %v in %s, got %v", tt.expected, tt.keywords, filepath.Base(tt.input), matched)
+			}
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		results := converter.ConvertBatchMatching(ctx, []string{report, memo}, []string{"annual report"}, 2, nil)
+		if results[0].Skipped || results[0].Text != "intro\fthe Annual\nReport 2024\fappendix" {
+			t.Errorf("expected report.pdf to be converted, got %+v", results[0])
+		}
+		if !results[1].Skipped || results[1].Text != "" || results[1].Err != nil {
+			t.Errorf("expected memo.pdf to be skipped, got %+v", results[1])
+		}
+	})
+}
+
+func TestConverter_ConvertBatch_Canceled(t *testing.T) {
+	dir := t.TempDir()
+	var inputs []string
+	for i := range 5 {
+		path := filepath.Join(dir, fmt.Sprintf("%d.pdf", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprint("document ", i)), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+		inputs = append(inputs, path)
+	}
+	inputs = append(inputs, inputs[0])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if calls.Add(1) == 1 {
+			_, err := io.WriteString(stdout, "text")
+			return err
+		}
+		// the second conversion is interrupted
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	results := converter.ConvertBatch(ctx, inputs, 1, nil)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected 2 conversions, got %d", n)
+	}
+	summary := SummarizeBatch(results)
+	if summary.Total != 6 || summary.Converted+summary.Canceled != 6 || summary.Canceled < 4 || summary.Duplicates != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	for _, r := range results {
+		if r.Canceled && !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("expected %s to fail with context.Canceled, got %v", r.Input, r.Err)
+		}
+		if !r.Canceled && (r.Err != nil || r.Text != "text") {
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+}
+
+func TestSummarizeBatch(t *testing.T) {
+	results := []BatchResult{
+		{Input: "a.pdf", Text: "a"},
+		{Input: "b.pdf", Err: ErrPDFOpen},
+		{Input: "c.pdf", Skipped: true},
+		{Input: "d.pdf", Canceled: true, Err: context.Canceled},
+		{Input: "e.pdf", DuplicateOf: "a.pdf", Text: "a"},
+	}
+	expected := BatchSummary{Total: 5, Converted: 2, Failed: 1, Skipped: 1, Canceled: 1, Duplicates: 1}
+	if summary := SummarizeBatch(results); summary != expected {
+		t.Errorf("expected %+v, got %+v", expected, summary)
+	}
+}
@@ -0,0 +1,228 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// Rect is a rectangle in PDF points, with the origin at the top-left corner
+// of the page
+type Rect struct {
+	XMin float64 `json:"x_min"`
+	YMin float64 `json:"y_min"`
+	XMax float64 `json:"x_max"`
+	YMax float64 `json:"y_max"`
+}
+
+// Contains reports whether the point x, y lies inside r
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.XMin && x <= r.XMax && y >= r.YMin && y <= r.YMax
+}
+
+// Center returns the center of r
+func (r Rect) Center() (x, y float64) {
+	return (r.XMin + r.XMax) / 2, (r.YMin + r.YMax) / 2
+}
+
+// Word is a word and its bounding box
+type Word struct {
+	// Text is the word
+	Text string `json:"text"`
+	// Box is the bounding box of the word
+	Box Rect `json:"box"`
+}
+
+// Line is a line of text and its bounding box
+type Line struct {
+	// Text is the text of the line, its words separated by spaces
+	Text string `json:"text"`
+	// Box is the bounding box of the line
+	Box Rect `json:"box"`
+}
+
+// Block is a block of lines, such as a paragraph or the part of a paragraph
+// in one column
+type Block struct {
+	// Box is the bounding box of the block
+	Box Rect `json:"box"`
+	// Lines are the lines of the block
+	Lines []Line `json:"lines"`
+}
+
+// PageGeometry is the size of a page and the position of its words
+type PageGeometry struct {
+	// Number is the page number in the PDF file
+	Number int `json:"number"`
+	// Width is the page width in points
+	Width float64 `json:"width"`
+	// Height is the page height in points
+	Height float64 `json:"height"`
+	// Words are the words of the page in reading order
+	Words []Word `json:"words"`
+	// Lines are the lines of the page in reading order, only set for
+	// -bbox-layout output
+	Lines []Line `json:"lines,omitempty"`
+	// Blocks are the blocks of the page in the order pdftotext found them,
+	// only set for -bbox-layout output
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// ConvertGeometry converts a PDF file with -bbox and returns the words of each
+// page with their bounding boxes. Output options such as Layout and Raw are
+// ignored.
+func (c *Converter) ConvertGeometry(ctx context.Context, inputPath string, opts *Options) ([]PageGeometry, error) {
+	return c.geometry(ctx, inputPath, opts, false)
+}
+
+// geometry converts a PDF file with -bbox, or with -bbox-layout to include
+// the lines of each page
+func (c *Converter) geometry(ctx context.Context, inputPath string, opts *Options, layout bool) ([]PageGeometry, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	bboxOpts := geometryOptions(opts, layout)
+	hookOpts, err := runPreHooks(ctx, inputPath, &bboxOpts)
+	if err != nil {
+		return nil, err
+	}
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, hookOpts)
+	if err != nil {
+		return nil, err
+	}
+	var pages []PageGeometry
+	for _, o := range rangeOpts {
+		var stdout bytes.Buffer
+		if err := c.run(ctx, inputPath, "-", o, &stdout); err != nil {
+			return nil, err
+		}
+		geometry, err := parseBBox(&stdout, o.FirstPage)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, geometry...)
+	}
+	return pages, nil
+}
+
+// geometryOptions returns opts converting with -bbox, or with -bbox-layout,
+// without the options rewriting the text
+func geometryOptions(opts *Options, layout bool) Options {
+	bboxOpts := Options{}
+	if opts != nil {
+		bboxOpts = *opts
+	}
+	bboxOpts.BBox, bboxOpts.BBoxLayout, bboxOpts.HTMLMeta, bboxOpts.TSV = !layout, layout, false, false
+	bboxOpts.PageSeparator = ""
+	bboxOpts.Transformers = nil
+	bboxOpts.Normalize = ""
+	bboxOpts.PostHooks = nil
+	bboxOpts.ReadingOrder = false
+	return bboxOpts
+}
+
+// parseBBox parses the XHTML written by pdftotext -bbox or -bbox-layout,
+// numbering the pages from firstPage
+func parseBBox(r io.Reader, firstPage int) ([]PageGeometry, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+
+	var pages []PageGeometry
+	var line *Line
+	blockStart := -1
+	number := max(firstPage, 1)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return pages, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid bbox output: %w", err)
+		}
+
+		if end, ok := tok.(xml.EndElement); ok {
+			switch end.Name.Local {
+			case "line":
+				line = nil
+			case "block":
+				if blockStart >= 0 {
+					page := &pages[len(pages)-1]
+					page.Blocks[len(page.Blocks)-1].Lines = slices.Clone(page.Lines[blockStart:])
+				}
+				blockStart = -1
+			}
+			continue
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "block":
+			if len(pages) == 0 {
+				return nil, fmt.Errorf("invalid bbox output: block outside of a page")
+			}
+			page := &pages[len(pages)-1]
+			page.Blocks = append(page.Blocks, Block{Box: rectAttrs(start)})
+			blockStart = len(page.Lines)
+		case "line":
+			if len(pages) == 0 {
+				return nil, fmt.Errorf("invalid bbox output: line outside of a page")
+			}
+			page := &pages[len(pages)-1]
+			page.Lines = append(page.Lines, Line{Box: rectAttrs(start)})
+			line = &page.Lines[len(page.Lines)-1]
+		case "page":
+			line, blockStart = nil, -1
+			pages = append(pages, PageGeometry{
+				Number: number,
+				Width:  floatAttr(start, "width"),
+				Height: floatAttr(start, "height"),
+			})
+			number++
+		case "word":
+			var text string
+			if err := dec.DecodeElement(&text, &start); err != nil {
+				return nil, fmt.Errorf("invalid bbox output: %w", err)
+			}
+			if len(pages) == 0 {
+				return nil, fmt.Errorf("invalid bbox output: word outside of a page")
+			}
+			page := &pages[len(pages)-1]
+			page.Words = append(page.Words, Word{Text: text, Box: rectAttrs(start)})
+			if line != nil {
+				if line.Text != "" {
+					line.Text += " "
+				}
+				line.Text += text
+			}
+		}
+	}
+}
+
+// rectAttrs returns the rectangle given by the xMin, yMin, xMax and yMax
+// attributes
+func rectAttrs(start xml.StartElement) Rect {
+	return Rect{
+		XMin: floatAttr(start, "xMin"),
+		YMin: floatAttr(start, "yMin"),
+		XMax: floatAttr(start, "xMax"),
+		YMax: floatAttr(start, "yMax"),
+	}
+}
+
+// floatAttr returns the value of the named attribute, or 0 if it is missing
+// or not a number
+func floatAttr(start xml.StartElement, name string) float64 {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			v, _ := strconv.ParseFloat(attr.Value, 64)
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const bboxOutput = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<title></title>
+<meta name="Producer" content="LibreOffice"/>
+</head>
+<body>
+<doc>
+  <page width="612.000000" height="792.000000">
+    <word xMin="56.800000" yMin="57.208000" xMax="89.440000" yMax="69.208000">Hello</word>
+    <word xMin="92.440000" yMin="57.208000" xMax="125.752000" yMax="69.208000">world</word>
+    <word xMin="56.800000" yMin="71.008000" xMax="96.100000" yMax="83.008000">Second</word>
+    <word xMin="99.100000" yMin="71.008000" xMax="118.000000" yMax="83.008000">line</word>
+  </page>
+  <page width="612.000000" height="792.000000">
+    <word xMin="56.800000" yMin="57.208000" xMax="100.000000" yMax="69.208000">Fish &amp; chips</word>
+  </page>
+</doc>
+</body>
+</html>
+`
+
+func TestParseBBox(t *testing.T) {
+	pages, err := parseBBox(strings.NewReader(bboxOutput), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+
+	first := pages[0]
+	if first.Number != 3 || first.Width != 612 || first.Height != 792 || len(first.Words) != 4 {
+		t.Errorf("unexpected first page %+v", first)
+	}
+	expected := Word{Text: "Hello", Box: Rect{XMin: 56.8, YMin: 57.208, XMax: 89.44, YMax: 69.208}}
+	if !reflect.DeepEqual(first.Words[0], expected) {
+		t.Errorf("expected %+v, got %+v", expected, first.Words[0])
+	}
+	if pages[1].Number != 4 || pages[1].Words[0].Text != "Fish & chips" {
+		t.Errorf("unexpected second page %+v", pages[1])
+	}
+
+	if _, err := parseBBox(strings.NewReader(`<word xMin="1">orphan</word>`), 1); err == nil {
+		t.Error("expected an error for a word outside of a page")
+	}
+}
+
+func TestConverter_ConvertGeometry(t *testing.T) {
+	var gotArgs []string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		gotArgs = args
+		_, err := io.WriteString(stdout, bboxOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	pages, err := converter.ConvertGeometry(context.Background(), "input.pdf", &Options{FirstPage: 2, Layout: true, TSV: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(gotArgs, "-bbox") || slices.Contains(gotArgs, "-tsv") {
+		t.Errorf("expected -bbox without -tsv, got %v", gotArgs)
+	}
+	if len(pages) != 2 || pages[0].Number != 2 {
+		t.Errorf("expected pages numbered from 2, got %+v", pages)
+	}
+}
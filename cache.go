@@ -0,0 +1,336 @@
+package pdftotext
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache stores the results of CacheResults, so it can be backed by a key-value
+// store such as Redis or memcached shared by the replicas of a service.
+// MemoryCache, DiskCache and StorageCache implement it. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	// Get returns the result cached under key, or ErrNotFound
+	Get(ctx context.Context, key string) (string, error)
+	// Set caches text under key for ttl, or without expiry if ttl is zero
+	Set(ctx context.Context, key, text string, ttl time.Duration) error
+}
+
+var (
+	_ Cache = (*MemoryCache)(nil)
+	_ Cache = (*DiskCache)(nil)
+	_ Cache = (*StorageCache)(nil)
+)
+
+// CacheStats are the hit and miss counts of a cache
+type CacheStats struct {
+	// Hits is the number of conversions answered from the cache
+	Hits int64 `json:"hits"`
+	// Misses is the number of conversions that ran because their result
+	// was not cached
+	Misses int64 `json:"misses"`
+	// Entries is the number of cached results
+	Entries int `json:"entries"`
+}
+
+// MemoryCache is an in-memory cache of conversion results that evicts the
+// least recently used result when it is full. It is safe for concurrent use.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	hits       int64
+	misses     int64
+}
+
+// memoryEntry is a cached result, an element of MemoryCache.order
+type memoryEntry struct {
+	key     string
+	text    string
+	expires time.Time
+}
+
+// NewMemoryCache creates a MemoryCache holding up to maxEntries results
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: max(maxEntries, 1), entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// Get returns the result cached under key, counting a hit or a miss
+func (m *MemoryCache) Get(_ context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if ok && expired(e.Value.(*memoryEntry).expires) {
+		m.order.Remove(e)
+		delete(m.entries, key)
+		ok = false
+	}
+	if !ok {
+		m.misses++
+		return "", ErrNotFound
+	}
+	m.hits++
+	m.order.MoveToFront(e)
+	return e.Value.(*memoryEntry).text, nil
+}
+
+// Set caches text under key, evicting the least recently used result if the
+// cache is full
+func (m *MemoryCache) Set(_ context.Context, key, text string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := &memoryEntry{key: key, text: text, expires: expiry(ttl)}
+	if e, ok := m.entries[key]; ok {
+		e.Value = entry
+		m.order.MoveToFront(e)
+		return nil
+	}
+	m.entries[key] = m.order.PushFront(entry)
+	if m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// expiry returns the time a result cached now for ttl expires, or the zero
+// time if ttl is zero
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// expired reports whether a result expiring at expires has expired
+func expired(expires time.Time) bool {
+	return !expires.IsZero() && time.Now().After(expires)
+}
+
+// Stats returns the hit and miss counts and the number of cached results
+func (m *MemoryCache) Stats() CacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return CacheStats{Hits: m.hits, Misses: m.misses, Entries: m.order.Len()}
+}
+
+// StorageCache is a cache of conversion results kept in a Storage, so the
+// replicas of a service sharing the storage, e.g. an S3Storage, share the
+// cache. Expired results are deleted when they are read.
+type StorageCache struct {
+	// Storage holds the results
+	Storage Storage
+	// Prefix is prepended to the storage keys of the results (default
+	// "cache/")
+	Prefix string
+}
+
+// storageCacheEntry is a result stored by a StorageCache
+type storageCacheEntry struct {
+	Text string `json:"text"`
+	// Expires is the expiry time of the result in Unix nanoseconds, 0 if it
+	// does not expire
+	Expires int64 `json:"expires,omitempty"`
+}
+
+// storageKey returns the storage key of the result cached under key, which
+// is hashed so any key is a valid storage key
+func (s *StorageCache) storageKey(key string) string {
+	return cmp.Or(s.Prefix, "cache/") + sha256Hex([]byte(key))
+}
+
+// Get returns the result cached under key, or ErrNotFound
+func (s *StorageCache) Get(ctx context.Context, key string) (string, error) {
+	data, err := s.Storage.Get(ctx, s.storageKey(key))
+	if err != nil {
+		return "", err
+	}
+	var entry storageCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return "", fmt.Errorf("invalid cache entry %s: %w", s.storageKey(key), err)
+	}
+	if entry.Expires > 0 && time.Now().UnixNano() > entry.Expires {
+		s.Storage.Delete(ctx, s.storageKey(key))
+		return "", ErrNotFound
+	}
+	return entry.Text, nil
+}
+
+// Set caches text under key for ttl, or without expiry if ttl is zero
+func (s *StorageCache) Set(ctx context.Context, key, text string, ttl time.Duration) error {
+	entry := storageCacheEntry{Text: text}
+	if expires := expiry(ttl); !expires.IsZero() {
+		entry.Expires = expires.UnixNano()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	return s.Storage.Put(ctx, s.storageKey(key), data)
+}
+
+// CacheResults returns a Middleware answering conversions from cache, keyed by
+// the SHA-256 of the input file and a fingerprint of the options, so
+// repeated conversions of the same document skip pdftotext entirely. Results
+// are cached for ttl, or without expiry if ttl is zero. Failed conversions
+// are not cached. Hooks and middlewares inside it are not called on a hit.
+//
+// Conversions with passwords, a PasswordFunc or a PasswordStore bypass the
+// cache, so text decrypted with a password is never served to a caller
+// without it.
+//
+// Functions and interfaces in the options cannot be fingerprinted, so
+// conversions with Transformers, OCR, GarbledStrategies, StopAfter or
+// PostHooks bypass the cache too. The callbacks that only observe the
+// conversion, such as OnWarning, are ignored.
+//
+// A failing cache is bypassed: errors of Get are misses and errors of Set are
+// ignored, so an unavailable cache server slows conversions down instead of
+// failing them.
+func CacheResults(cache Cache, ttl time.Duration) Middleware {
+	return (&Converter{hash: SHA256}).CacheResults(cache, ttl)
+}
+
+// CacheResults returns a Middleware like the CacheResults function, keyed by
+// digests of the hash algorithm of c instead of SHA-256, so a converter using
+// an HMAC does not expose plain file digests to the cache. The input file is
+// checked against the allowed roots of c before it is read.
+func (c *Converter) CacheResults(cache Cache, ttl time.Duration) Middleware {
+	return func(next ConvertFunc) ConvertFunc {
+		return func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+			// the overrides of ctx are part of the options the text depends on
+			_, keyOpts := applyOverrides(ctx, opts)
+			if hasCredentials(keyOpts) || hasTextCallbacks(keyOpts) {
+				return next(ctx, inputPath, opts)
+			}
+			if _, err := c.confine(ctx, inputPath, ""); err != nil {
+				return "", err
+			}
+			key, err := c.cacheKey(inputPath, keyOpts)
+			if err != nil {
+				// let the conversion report the unreadable file
+				return next(ctx, inputPath, opts)
+			}
+			if text, err := cache.Get(ctx, key); err == nil {
+				return text, nil
+			}
+			text, err := next(ctx, inputPath, opts)
+			if err != nil {
+				return "", err
+			}
+			cache.Set(ctx, key, text, ttl)
+			return text, nil
+		}
+	}
+}
+
+// hasCredentials reports whether opts can decrypt an encrypted PDF file
+func hasCredentials(opts *Options) bool {
+	return opts != nil && (opts.UserPassword != "" || opts.OwnerPassword != "" || opts.PasswordFunc != nil || opts.PasswordStore != nil)
+}
+
+// hasTextCallbacks reports whether opts holds functions or interfaces that
+// change the text, which the options fingerprint cannot tell apart
+func hasTextCallbacks(opts *Options) bool {
+	return opts != nil && (len(opts.Transformers) > 0 || opts.OCR != nil || len(opts.GarbledStrategies) > 0 ||
+		opts.StopAfter != nil || len(opts.PostHooks) > 0)
+}
+
+// cacheKey returns the digests of the input file and of the options that
+// change the text, hashed with the hash algorithm of c and joined by a colon
+func (c *Converter) cacheKey(inputPath string, opts *Options) (string, error) {
+	input, err := hashFile(inputPath, c.hash)
+	if err != nil {
+		return "", err
+	}
+	return input.Digest + ":" + optionsFingerprint(opts, c.hash), nil
+}
+
+// fingerprintedOptions are the fields of Options that change the text and
+// hold plain values, so equal options print the same
+type fingerprintedOptions struct {
+	FirstPage, LastPage, TailPages                  int
+	Pages, ExcludePages                             string
+	PageLabels                                      bool
+	Resolution, CropX, CropY, CropWidth, CropHeight int
+	RotatedCrop                                     RotatedCropMode
+	Layout                                          bool
+	FixedPitch                                      float64
+	Raw                                             bool
+	XpdfMode                                        XpdfMode
+	ReadingOrder, NoDiagonal, HTMLMeta              bool
+	BBox, BBoxLayout, TSV, CropBox                  bool
+	ColSpacing                                      float64
+	Encoding                                        string
+	EncodingFallback, RequireLanguageData           bool
+	EOL                                             EOLType
+	NoPageBreaks                                    bool
+	PageSeparator                                   string
+	Normalize                                       NormalizationForm
+	MinQuality                                      float64
+	MaxInputBytes, MaxOutputBytes                   int64
+	CheckOutput, AllowPartial, PageFallback         bool
+	Deterministic, Quiet                            bool
+}
+
+// optionsFingerprint returns the digest with alg of the options that change
+// the text. Only the fields holding plain values are hashed: functions and
+// interfaces, whose printed form is an address, are left out, and so are the
+// timeouts and the passwords, which must not be recoverable from it.
+func optionsFingerprint(opts *Options, alg HashAlgorithm) string {
+	o := Options{}
+	if opts != nil {
+		o = *opts
+	}
+	h := alg.New()
+	fmt.Fprintf(h, "%#v", fingerprintedOptions{
+		FirstPage:           o.FirstPage,
+		LastPage:            o.LastPage,
+		TailPages:           o.TailPages,
+		Pages:               o.Pages,
+		ExcludePages:        o.ExcludePages,
+		PageLabels:          o.PageLabels,
+		Resolution:          o.Resolution,
+		CropX:               o.CropX,
+		CropY:               o.CropY,
+		CropWidth:           o.CropWidth,
+		CropHeight:          o.CropHeight,
+		RotatedCrop:         o.RotatedCrop,
+		Layout:              o.Layout,
+		FixedPitch:          o.FixedPitch,
+		Raw:                 o.Raw,
+		XpdfMode:            o.XpdfMode,
+		ReadingOrder:        o.ReadingOrder,
+		NoDiagonal:          o.NoDiagonal,
+		HTMLMeta:            o.HTMLMeta,
+		BBox:                o.BBox,
+		BBoxLayout:          o.BBoxLayout,
+		TSV:                 o.TSV,
+		CropBox:             o.CropBox,
+		ColSpacing:          o.ColSpacing,
+		Encoding:            o.Encoding,
+		EncodingFallback:    o.EncodingFallback,
+		RequireLanguageData: o.RequireLanguageData,
+		EOL:                 o.EOL,
+		NoPageBreaks:        o.NoPageBreaks,
+		PageSeparator:       o.PageSeparator,
+		Normalize:           o.Normalize,
+		MinQuality:          o.MinQuality,
+		MaxInputBytes:       o.MaxInputBytes,
+		MaxOutputBytes:      o.MaxOutputBytes,
+		CheckOutput:         o.CheckOutput,
+		AllowPartial:        o.AllowPartial,
+		PageFallback:        o.PageFallback,
+		Deterministic:       o.Deterministic,
+		Quiet:               o.Quiet,
+	})
+	return hex.EncodeToString(h.Sum(nil))
+}
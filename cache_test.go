@@ -0,0 +1,289 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(3)
+	cache.Set(ctx, "a", "one", 0)
+	cache.Set(ctx, "b", "two", 0)
+	cache.Set(ctx, "expired", "gone", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, err := cache.Get(ctx, "expired"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for the expired result, got %v", err)
+	}
+	if text, err := cache.Get(ctx, "a"); err != nil || text != "one" {
+		t.Errorf("expected one, got %q, %v", text, err)
+	}
+	// b is now the least recently used
+	cache.Set(ctx, "c", "three", time.Hour)
+	cache.Set(ctx, "d", "four", time.Hour)
+	if _, err := cache.Get(ctx, "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected b to be evicted, got %v", err)
+	}
+	if text, err := cache.Get(ctx, "c"); err != nil || text != "three" {
+		t.Errorf("expected three, got %q, %v", text, err)
+	}
+
+	expected := CacheStats{Hits: 2, Misses: 2, Entries: 3}
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestStorageCache(t *testing.T) {
+	ctx := context.Background()
+	storage := &MemoryStorage{}
+	cache := &StorageCache{Storage: storage}
+	if _, err := cache.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing result, got %v", err)
+	}
+	if err := cache.Set(ctx, "a", "one", time.Hour); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	if err := cache.Set(ctx, "../b", "two", time.Nanosecond); err != nil {
+		t.Fatalf("failed to set a key that is not a valid storage key: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	// another cache sharing the storage finds the result
+	shared := &StorageCache{Storage: storage}
+	if text, err := shared.Get(ctx, "a"); err != nil || text != "one" {
+		t.Errorf("expected one, got %q, %v", text, err)
+	}
+	if _, err := shared.Get(ctx, "../b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for the expired result, got %v", err)
+	}
+	keys, err := storage.List(ctx, "cache/")
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != cache.storageKey("a") {
+		t.Errorf("expected the expired result to be removed, got keys %v", keys)
+	}
+}
+
+func TestCacheResults(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.pdf")
+	copied := filepath.Join(dir, "copy.pdf")
+	other := filepath.Join(dir, "other.pdf")
+	for path, content := range map[string]string{first: labeledPDF, copied: labeledPDF, other: annotatedPDF} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	var runs int
+	failed := errors.New("failed")
+	cache := NewMemoryCache(10)
+	convert := Chain(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		runs++
+		if opts != nil && opts.Raw {
+			return "", failed
+		}
+		return "text of " + inputPath, nil
+	}, CacheResults(cache, 0))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     *Options
+		expected string
+		runs     int
+	}{
+		{"miss", first, nil, "text of " + first, 1},
+		{"hit", first, &Options{}, "text of " + first, 1},
+		{"same content", copied, nil, "text of " + first, 1},
+		{"other options", first, &Options{Layout: true}, "text of " + first, 2},
+		{"other content", other, nil, "text of " + other, 3},
+		{"observers ignored", first, &Options{OnWarning: func(string) {}}, "text of " + first, 3},
+		{"callbacks bypass", first, &Options{StopAfter: func(int, string) bool { return false }}, "text of " + first, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := convert(ctx, tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("failed to convert: %v", err)
+			}
+			if text != tt.expected || runs != tt.runs {
+				t.Errorf("expected %q after %d runs, got %q after %d", tt.expected, tt.runs, text, runs)
+			}
+		})
+	}
+
+	t.Run("errors not cached", func(t *testing.T) {
+		runs = 0
+		for range 2 {
+			if _, err := convert(ctx, first, &Options{Raw: true}); !errors.Is(err, failed) {
+				t.Errorf("expected the conversion error, got %v", err)
+			}
+		}
+		if runs != 2 {
+			t.Errorf("expected 2 runs, got %d", runs)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		runs = 0
+		if _, err := convert(ctx, filepath.Join(dir, "missing.pdf"), nil); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		if runs != 1 {
+			t.Errorf("expected the conversion to run, got %d runs", runs)
+		}
+	})
+
+	expected := CacheStats{Hits: 3, Misses: 5, Entries: 3}
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+// failingCache is a Cache whose server is unavailable
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func (failingCache) Set(ctx context.Context, key, text string, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func TestCacheResults_FailingCache(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	convert := Chain(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		return "text", nil
+	}, CacheResults(failingCache{}, time.Hour))
+	if text, err := convert(context.Background(), input, nil); err != nil || text != "text" {
+		t.Errorf("expected the cache to be bypassed, got %q, %v", text, err)
+	}
+}
+
+func TestCacheResults_Credentials(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "encrypted.pdf")
+	if err := os.WriteFile(input, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	cache := NewMemoryCache(10)
+	convert := Chain(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		if !hasCredentials(opts) {
+			return "", ErrEncrypted
+		}
+		return "secret text", nil
+	}, CacheResults(cache, 0))
+	ctx := context.Background()
+
+	for _, opts := range []*Options{
+		{UserPassword: "secret"},
+		{OwnerPassword: "secret"},
+		{PasswordFunc: func(string) (string, string, error) { return "secret", "", nil }},
+	} {
+		if text, err := convert(ctx, input, opts); err != nil || text != "secret text" {
+			t.Fatalf("unexpected result %q, %v", text, err)
+		}
+	}
+	if _, err := convert(ctx, input, nil); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("expected the decrypted text not to be served without a password, got %v", err)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Hits != 0 {
+		t.Errorf("expected conversions with credentials to bypass the cache, got %+v", stats)
+	}
+}
+
+func TestConverter_CacheResults_AllowedRoots(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	input := filepath.Join(outside, "input.pdf")
+	if err := os.WriteFile(input, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	converter, err := New(WithRunner(fakeRun("", "", 0)), WithAllowedRoots(root))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	runs := 0
+	cache := NewMemoryCache(10)
+	convert := Chain(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		runs++
+		return "text", nil
+	}, converter.CacheResults(cache, 0))
+	if _, err := convert(context.Background(), input, nil); !errors.Is(err, ErrPathNotAllowed) {
+		t.Errorf("expected error %v, got %v", ErrPathNotAllowed, err)
+	}
+	if stats := cache.Stats(); runs != 0 || stats.Misses != 0 {
+		t.Errorf("expected the file not to be read, got %d runs and %+v", runs, stats)
+	}
+}
+
+func TestConverter_CacheResults_Hash(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	keyed := HMAC(SHA256, []byte("key"))
+	converter, err := New(WithRunner(fakeRun("", "", 0)), WithHash(keyed))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	cache := NewMemoryCache(10)
+	convert := Chain(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		return "text", nil
+	}, converter.CacheResults(cache, 0))
+	if _, err := convert(context.Background(), input, nil); err != nil {
+		t.Fatalf("failed to convert: %v", err)
+	}
+
+	plain, _ := hashFile(input, SHA256)
+	hashed, _ := hashFile(input, keyed)
+	if len(cache.entries) != 1 {
+		t.Fatalf("expected 1 cached result, got %d", len(cache.entries))
+	}
+	for key := range cache.entries {
+		if strings.Contains(key, plain.Digest) || !strings.HasPrefix(key, hashed.Digest+":") {
+			t.Errorf("expected a key of HMAC digests, got %s", key)
+		}
+		if expected := hashed.Digest + ":" + optionsFingerprint(nil, keyed); key != expected {
+			t.Errorf("expected key %s, got %s", expected, key)
+		}
+	}
+}
+
+func TestOptionsFingerprint(t *testing.T) {
+	base := optionsFingerprint(&Options{Layout: true}, SHA256)
+	tests := []struct {
+		name string
+		opts *Options
+		same bool
+	}{
+		{"equal", &Options{Layout: true}, true},
+		{"other func", &Options{Layout: true, StopAfter: func(int, string) bool { return true }}, true},
+		{"other transformer", &Options{Layout: true, Transformers: []Transformer{TransformerFunc(func(_ int, text string) (string, error) {
+			return strings.ToUpper(text), nil
+		})}}, true},
+		{"timeouts", &Options{Layout: true, MaxTimeout: time.Minute}, true},
+		{"password", &Options{Layout: true, UserPassword: "secret"}, true},
+		{"other value", &Options{Layout: true, Raw: true}, false},
+		{"other page", &Options{Layout: true, FirstPage: 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := optionsFingerprint(tt.opts, SHA256); (got == base) != tt.same {
+				t.Errorf("expected same fingerprint %v, got %s and %s", tt.same, got, base)
+			}
+		})
+	}
+}
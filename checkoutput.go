@@ -0,0 +1,93 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkOutput checks the file ConvertToFile wrote for opts.CheckOutput: it
+// must exist and not be empty, and text in UTF-8 must be valid UTF-8. Files
+// compressed with a Compressor that is not a Decompressor are only checked
+// to exist.
+func (c *Converter) checkOutput(ctx context.Context, outputPath string, opts *Options) error {
+	name := outputName(outputPath, opts)
+	f, err := c.output().Open(ctx, name)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%w: %s does not exist", ErrEmptyOutput, name)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputFile, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if opts.Compression != nil {
+		d, ok := opts.Compression.(Decompressor)
+		if !ok {
+			return nil
+		}
+		zr, err := d.NewReader(f)
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: %s", ErrEmptyOutput, name)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrOutputFile, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	n, valid, err := checkUTF8(contextReader{ctx, r})
+	switch {
+	case err != nil:
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%w: %w", ErrOutputFile, err)
+	case n == 0:
+		return fmt.Errorf("%w: %s", ErrEmptyOutput, name)
+	case !valid && (opts.Encoding == "" || strings.EqualFold(opts.Encoding, "UTF-8")):
+		return fmt.Errorf("%w: %s is not valid UTF-8", ErrCommandFailed, name)
+	}
+	return nil
+}
+
+// checkUTF8 reads r to the end and returns the number of bytes read and
+// whether they are valid UTF-8
+func checkUTF8(r io.Reader) (n int64, valid bool, err error) {
+	buf := make([]byte, 64<<10)
+	valid = true
+	pending := 0
+	for {
+		m, err := r.Read(buf[pending:])
+		n += int64(m)
+		data := buf[:pending+m]
+		end := len(data)
+		if err == nil {
+			// keep a rune split across reads for the next one
+			for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+				if utf8.RuneStart(data[len(data)-i]) {
+					if !utf8.FullRune(data[len(data)-i:]) {
+						end = len(data) - i
+					}
+					break
+				}
+			}
+		}
+		if valid && !utf8.Valid(data[:end]) {
+			valid = false
+		}
+		pending = copy(buf, data[end:])
+		if errors.Is(err, io.EOF) {
+			return n, valid, nil
+		}
+		if err != nil {
+			return n, valid, err
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestConverter_CheckOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		opts    Options
+		wantErr error
+	}{
+		{"text", "Hello, 世界\n\f", Options{}, nil},
+		{"empty", "", Options{}, ErrEmptyOutput},
+		{"invalid UTF-8", "caf\xe9\n", Options{}, ErrCommandFailed},
+		{"Latin1", "caf\xe9\n", Options{Encoding: "Latin1"}, nil},
+		{"gzip", "Hello\n", Options{Compression: Gzip{}}, nil},
+		{"empty gzip", "", Options{Compression: Gzip{}}, ErrEmptyOutput},
+		{"split pages", "", Options{SplitPages: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(fakeRun(tt.output, "", 0)), WithOutputFS(StorageFS(&MemoryStorage{})))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			opts := tt.opts
+			opts.CheckOutput = true
+			err = converter.ConvertToFile(context.Background(), "input.pdf", "output.txt", &opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("local file", func(t *testing.T) {
+		converter, err := New(WithRunner(fakeRun("", "", 0)))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+		output := filepath.Join(t.TempDir(), "output.txt")
+		err = converter.ConvertToFile(context.Background(), "input.pdf", output, &Options{CheckOutput: true})
+		if !errors.Is(err, ErrEmptyOutput) {
+			t.Errorf("expected ErrEmptyOutput for the missing file, got %v", err)
+		}
+	})
+}
+
+func TestCheckUTF8(t *testing.T) {
+	text := strings.Repeat("añ€😀", 1000)
+	tests := []struct {
+		name  string
+		data  string
+		valid bool
+	}{
+		{"ascii", "hello", true},
+		{"multibyte", text, true},
+		{"truncated rune", text[:len(text)-1], false},
+		{"invalid byte", "ok\xffok", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// one byte per read splits every multibyte rune
+			n, valid, err := checkUTF8(iotest.OneByteReader(bytes.NewReader([]byte(tt.data))))
+			if err != nil || n != int64(len(tt.data)) || valid != tt.valid {
+				t.Errorf("checkUTF8() = %d, %v, %v, expected %d, %v", n, valid, err, len(tt.data), tt.valid)
+			}
+		})
+	}
+	if _, _, err := checkUTF8(iotest.ErrReader(io.ErrUnexpectedEOF)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected the read error, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package pdftotext
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ChunkBoundary is where Chunk prefers to end chunks
+type ChunkBoundary string
+
+const (
+	// SplitParagraph ends chunks between paragraphs, separated by blank
+	// lines or page breaks
+	SplitParagraph ChunkBoundary = "paragraph"
+	// SplitSentence ends chunks between sentences
+	SplitSentence ChunkBoundary = "sentence"
+	// SplitWord ends chunks between words
+	SplitWord ChunkBoundary = "word"
+)
+
+// ChunkOptions configures Chunk
+type ChunkOptions struct {
+	// MaxTokens is the largest number of tokens of a chunk (default 512). A
+	// single word with more tokens becomes a chunk of its own.
+	MaxTokens int
+	// Overlap is the largest number of tokens at the end of a chunk repeated
+	// at the start of the next one, in whole paragraphs, sentences or words
+	Overlap int
+	// SplitOn is where chunks end (default SplitParagraph). Paragraphs longer
+	// than MaxTokens are split between sentences, and sentences between
+	// words.
+	SplitOn ChunkBoundary
+	// CountTokens returns the number of tokens of a text for the tokenizer
+	// of the embedding model (default EstimateTokens)
+	CountTokens func(text string) int
+}
+
+// TextChunk is a chunk of the text of a Document
+type TextChunk struct {
+	// Text is the text of the chunk, including any form feeds between pages
+	Text string `json:"text"`
+	// Start and End are the byte offsets of the chunk in Document.Text()
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// FirstPage and LastPage are the numbers of the pages the chunk spans
+	FirstPage int `json:"first_page"`
+	LastPage  int `json:"last_page"`
+	// Tokens is the number of tokens of the chunk
+	Tokens int `json:"tokens"`
+}
+
+// defaultMaxTokens is the default ChunkOptions.MaxTokens
+const defaultMaxTokens = 512
+
+// EstimateTokens estimates the number of tokens of text as one per four
+// characters, a common approximation for English text
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+var (
+	// paragraphBreakPattern matches blank lines and page breaks
+	paragraphBreakPattern = regexp.MustCompile(`\n[ \t]*\n\s*|\s*\f\s*`)
+	// sentenceEndPattern matches the whitespace after the end of a sentence
+	sentenceEndPattern = regexp.MustCompile(`[.!?]["')\]]*\s+`)
+	// wordBreakPattern matches the whitespace between words
+	wordBreakPattern = regexp.MustCompile(`\s+`)
+)
+
+// Chunk splits the text of doc into chunks of at most opts.MaxTokens tokens
+// for retrieval-augmented generation, keeping track of the pages and offsets
+// each chunk came from
+func Chunk(doc *Document, opts ChunkOptions) []TextChunk {
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = defaultMaxTokens
+	}
+	if opts.CountTokens == nil {
+		opts.CountTokens = EstimateTokens
+	}
+
+	// the offsets of the pages in doc.Text(), which trims the joined pages
+	var full strings.Builder
+	starts := make([]int, len(doc.Pages))
+	for i, page := range doc.Pages {
+		if i > 0 {
+			full.WriteString("\f")
+		}
+		starts[i] = full.Len()
+		full.WriteString(page.Text)
+	}
+	lead := full.Len() - len(strings.TrimLeft(full.String(), " \t\n\r\f\v"))
+	text := doc.Text()
+	pageAt := func(offset int) int {
+		i := sort.Search(len(starts), func(i int) bool { return starts[i]-lead > offset }) - 1
+		return doc.Pages[max(i, 0)].Number
+	}
+
+	levels := []*regexp.Regexp{paragraphBreakPattern, sentenceEndPattern, wordBreakPattern}
+	switch opts.SplitOn {
+	case SplitSentence:
+		levels = levels[1:]
+	case SplitWord:
+		levels = levels[2:]
+	}
+	var units []textSpan
+	for _, span := range splitSpans(text, textSpan{0, len(text)}, levels[0]) {
+		units = append(units, fitSpans(text, span, levels[1:], opts)...)
+	}
+
+	var chunks []TextChunk
+	for i := 0; i < len(units); {
+		// add units while the chunk fits, but at least one
+		j, tokens := i+1, opts.CountTokens(text[units[i].start:units[i].end])
+		for ; j < len(units); j++ {
+			n := opts.CountTokens(text[units[i].start:units[j].end])
+			if n > opts.MaxTokens {
+				break
+			}
+			tokens = n
+		}
+		start, end := units[i].start, units[j-1].end
+		chunks = append(chunks, TextChunk{
+			Text:      text[start:end],
+			Start:     start,
+			End:       end,
+			FirstPage: pageAt(start),
+			LastPage:  pageAt(end - 1),
+			Tokens:    tokens,
+		})
+		if j == len(units) {
+			break
+		}
+
+		// start the next chunk with the units at the end of this one that
+		// fit into the overlap, while still making progress
+		next := j
+		for next-1 > i && opts.CountTokens(text[units[next-1].start:units[j-1].end]) <= opts.Overlap {
+			next--
+		}
+		i = next
+	}
+	return chunks
+}
+
+// textSpan is a range of byte offsets in a text
+type textSpan struct {
+	start, end int
+}
+
+// splitSpans splits span of text at the separators matching pattern, leaving
+// the separators out for paragraphs and words and in for sentences
+func splitSpans(text string, span textSpan, pattern *regexp.Regexp) []textSpan {
+	var spans []textSpan
+	start := span.start
+	for _, m := range pattern.FindAllStringIndex(text[span.start:span.end], -1) {
+		end := span.start + m[0]
+		if pattern == sentenceEndPattern {
+			// keep the punctuation with its sentence
+			end = span.start + m[0] + len(strings.TrimRight(text[span.start+m[0]:span.start+m[1]], " \t\n\r\f\v"))
+		}
+		if strings.TrimSpace(text[start:end]) != "" {
+			spans = append(spans, textSpan{start, end})
+		}
+		start = span.start + m[1]
+	}
+	if strings.TrimSpace(text[start:span.end]) != "" {
+		spans = append(spans, textSpan{start, span.end})
+	}
+	return spans
+}
+
+// fitSpans splits span further at the finer levels until each part fits into
+// opts.MaxTokens
+func fitSpans(text string, span textSpan, levels []*regexp.Regexp, opts ChunkOptions) []textSpan {
+	if len(levels) == 0 || opts.CountTokens(text[span.start:span.end]) <= opts.MaxTokens {
+		return []textSpan{span}
+	}
+	var spans []textSpan
+	for _, part := range splitSpans(text, span, levels[0]) {
+		spans = append(spans, fitSpans(text, part, levels[1:], opts)...)
+	}
+	return spans
+}
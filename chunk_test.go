@@ -0,0 +1,94 @@
+package pdftotext
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// wordTokens counts one token per word
+func wordTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestChunk(t *testing.T) {
+	doc := &Document{Pages: []Page{
+		{Number: 3, Text: "\nOne two three. Four five.\n\nSix seven eight nine.\n"},
+		{Number: 4, Text: "Ten eleven.\n"},
+	}}
+	text := doc.Text()
+
+	tests := []struct {
+		name     string
+		opts     ChunkOptions
+		expected []TextChunk
+	}{
+		{
+			name: "Paragraphs",
+			opts: ChunkOptions{MaxTokens: 6, CountTokens: wordTokens},
+			expected: []TextChunk{
+				{Text: "One two three. Four five.", FirstPage: 3, LastPage: 3, Tokens: 5},
+				{Text: "Six seven eight nine.\n\fTen eleven.", FirstPage: 3, LastPage: 4, Tokens: 6},
+			},
+		},
+		{
+			name: "Long paragraphs split into sentences",
+			opts: ChunkOptions{MaxTokens: 4, CountTokens: wordTokens},
+			expected: []TextChunk{
+				{Text: "One two three.", FirstPage: 3, LastPage: 3, Tokens: 3},
+				{Text: "Four five.", FirstPage: 3, LastPage: 3, Tokens: 2},
+				{Text: "Six seven eight nine.", FirstPage: 3, LastPage: 3, Tokens: 4},
+				{Text: "Ten eleven.", FirstPage: 4, LastPage: 4, Tokens: 2},
+			},
+		},
+		{
+			name: "Sentences with overlap",
+			opts: ChunkOptions{MaxTokens: 6, Overlap: 2, SplitOn: SplitSentence, CountTokens: wordTokens},
+			expected: []TextChunk{
+				{Text: "One two three. Four five.", FirstPage: 3, LastPage: 3, Tokens: 5},
+				{Text: "Four five.\n\nSix seven eight nine.", FirstPage: 3, LastPage: 3, Tokens: 6},
+				{Text: "Ten eleven.", FirstPage: 4, LastPage: 4, Tokens: 2},
+			},
+		},
+		{
+			name: "Words",
+			opts: ChunkOptions{MaxTokens: 5, Overlap: 1, SplitOn: SplitWord, CountTokens: wordTokens},
+			expected: []TextChunk{
+				{Text: "One two three. Four five.", FirstPage: 3, LastPage: 3, Tokens: 5},
+				{Text: "five.\n\nSix seven eight nine.", FirstPage: 3, LastPage: 3, Tokens: 5},
+				{Text: "nine.\n\fTen eleven.", FirstPage: 3, LastPage: 4, Tokens: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := Chunk(doc, tt.opts)
+			for i := range chunks {
+				if text[chunks[i].Start:chunks[i].End] != chunks[i].Text {
+					t.Errorf("chunk %d offsets %d-%d do not match its text %q", i, chunks[i].Start, chunks[i].End, chunks[i].Text)
+				}
+				chunks[i].Start, chunks[i].End = 0, 0
+			}
+			if !reflect.DeepEqual(chunks, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, chunks)
+			}
+		})
+	}
+}
+
+func TestChunk_Defaults(t *testing.T) {
+	doc := &Document{Pages: []Page{{Number: 1, Text: strings.Repeat("word ", 1000)}}}
+	chunks := Chunk(doc, ChunkOptions{})
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if chunk.Tokens > defaultMaxTokens || chunk.Tokens != EstimateTokens(chunk.Text) {
+			t.Errorf("unexpected chunk tokens %d for %d bytes", chunk.Tokens, len(chunk.Text))
+		}
+	}
+	if Chunk(&Document{}, ChunkOptions{}) != nil {
+		t.Error("expected no chunks for an empty document")
+	}
+}
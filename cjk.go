@@ -0,0 +1,100 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PresetJapanese returns Options for Japanese documents, writing Shift-JIS.
+// Shift-JIS and the Adobe-Japan1 character collection are part of
+// poppler-data, so the conversion falls back to UTF-8 if the encoding is
+// missing and fails with ErrMissingLanguageData if the collection is.
+func PresetJapanese() *Options {
+	return cjkPreset("Shift-JIS")
+}
+
+// PresetSimplifiedChinese returns Options for Simplified Chinese documents,
+// writing GBK, see PresetJapanese
+func PresetSimplifiedChinese() *Options {
+	return cjkPreset("GBK")
+}
+
+// PresetTraditionalChinese returns Options for Traditional Chinese
+// documents, writing Big5, see PresetJapanese
+func PresetTraditionalChinese() *Options {
+	return cjkPreset("Big5")
+}
+
+// PresetKorean returns Options for Korean documents, writing ISO-2022-KR,
+// see PresetJapanese
+func PresetKorean() *Options {
+	return cjkPreset("ISO-2022-KR")
+}
+
+// cjkPreset returns Options writing encoding, requiring the language data
+func cjkPreset(encoding string) *Options {
+	return &Options{
+		Encoding:            encoding,
+		EOL:                 EOLUnix,
+		EncodingFallback:    true,
+		RequireLanguageData: true,
+	}
+}
+
+// Encodings returns the output encodings the pdftotext binary supports, as
+// listed by pdftotext -listenc. Without poppler-data, only the built-in
+// encodings such as UTF-8 and Latin1 are listed.
+func (c *Converter) Encodings(ctx context.Context) ([]string, error) {
+	var stdout bytes.Buffer
+	stderr := c.newStderr()
+	if err := c.runCommand(ctx, c.binaryPath, []string{"-listenc"}, &stdout, stderr); err != nil {
+		return nil, c.handleError(err, stderr.String())
+	}
+
+	// the list follows a heading such as "Available encodings are:"
+	var encodings []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasSuffix(line, ":") {
+			encodings = append(encodings, line)
+		}
+	}
+	return encodings, nil
+}
+
+// CheckLanguageData returns ErrMissingLanguageData if the pdftotext binary
+// does not support the output encoding of opts, so a missing poppler-data
+// installation is reported before converting a corpus. Missing character
+// collections can only be detected while converting, see
+// Options.RequireLanguageData.
+func (c *Converter) CheckLanguageData(ctx context.Context, opts *Options) error {
+	if opts == nil || opts.Encoding == "" {
+		return nil
+	}
+	encodings, err := c.Encodings(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(encodings, func(e string) bool { return strings.EqualFold(e, opts.Encoding) }) {
+		return fmt.Errorf("%w: encoding %s is not available, available encodings are %s", ErrMissingLanguageData, opts.Encoding, strings.Join(encodings, ", "))
+	}
+	return nil
+}
+
+// isMissingEncoding reports whether stderr indicates that the output
+// encoding is not installed
+func isMissingEncoding(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	return strings.Contains(stderr, "couldn't get text encoding") || strings.Contains(stderr, "unicodemap file")
+}
+
+// isMissingLanguageData reports whether a message of pdftotext indicates a
+// missing character collection or CMap
+func isMissingLanguageData(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "unknown character collection") ||
+		strings.Contains(message, "missing language pack") ||
+		strings.Contains(message, "cmap file for")
+}
@@ -0,0 +1,149 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// cjkRunner answers like pdftotext with poppler-data missing: only UTF-8 and
+// Latin1 are available and the Adobe-Japan1 collection is unknown
+func cjkRunner(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	if slices.Contains(args, "-listenc") {
+		_, err := io.WriteString(stdout, "Available encodings are:\nUTF-8\nLatin1\n")
+		return err
+	}
+	if i := slices.Index(args, "-enc"); i >= 0 && args[i+1] != "UTF-8" {
+		io.WriteString(stderr, "Error: Couldn't find unicodeMap file for the '"+args[i+1]+"' encoding\nError: Couldn't get text encoding\n")
+		return &ExitError{Code: 99}
+	}
+	io.WriteString(stderr, "Syntax Error: Missing language pack for 'Adobe-Japan1' mapping\n")
+	_, err := io.WriteString(stdout, "Report 2024\f")
+	return err
+}
+
+func TestConverter_LanguageData(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(cjkRunner)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		opts             *Options
+		expected         string
+		expectedError    error
+		expectedWarnings int
+	}{
+		{
+			name:             "Missing encoding",
+			opts:             &Options{Encoding: "Shift-JIS"},
+			expectedError:    ErrMissingLanguageData,
+			expectedWarnings: 2,
+		},
+		{
+			name:             "Missing collection ignored",
+			opts:             &Options{Encoding: "UTF-8"},
+			expected:         "Report 2024",
+			expectedWarnings: 1,
+		},
+		{
+			name:             "Missing collection required",
+			opts:             &Options{Encoding: "UTF-8", RequireLanguageData: true},
+			expectedError:    ErrMissingLanguageData,
+			expectedWarnings: 1,
+		},
+		{
+			name:             "Fallback",
+			opts:             &Options{Encoding: "Shift-JIS", EncodingFallback: true},
+			expected:         "Report 2024",
+			expectedWarnings: 4,
+		},
+		{
+			name:             "Preset",
+			opts:             PresetJapanese(),
+			expectedError:    ErrMissingLanguageData,
+			expectedWarnings: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings []string
+			tt.opts.OnWarning = func(message string) {
+				warnings = append(warnings, message)
+			}
+			text, err := converter.Convert(ctx, "input.pdf", tt.opts)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+			if len(warnings) != tt.expectedWarnings {
+				t.Errorf("expected %d warnings, got %q", tt.expectedWarnings, warnings)
+			}
+		})
+	}
+
+	t.Run("Fallback warning", func(t *testing.T) {
+		var warnings []string
+		opts := &Options{Encoding: "Shift-JIS", EncodingFallback: true, OnWarning: func(message string) {
+			warnings = append(warnings, message)
+		}}
+		if _, err := converter.Convert(ctx, "input.pdf", opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(warnings, "encoding Shift-JIS is not installed, converting with UTF-8") {
+			t.Errorf("expected a fallback warning, got %q", warnings)
+		}
+	})
+
+	t.Run("Missing encoding is a command failure", func(t *testing.T) {
+		_, err := converter.Convert(ctx, "input.pdf", &Options{Encoding: "GBK"})
+		if !errors.Is(err, ErrCommandFailed) || ErrorKind(err) != "language_data" {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
+
+func TestConverter_CheckLanguageData(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(cjkRunner)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	encodings, err := converter.Encodings(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(encodings, []string{"UTF-8", "Latin1"}) {
+		t.Errorf("unexpected encodings %v", encodings)
+	}
+
+	if err := converter.CheckLanguageData(ctx, &Options{Encoding: "latin1"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := converter.CheckLanguageData(ctx, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = converter.CheckLanguageData(ctx, PresetKorean())
+	if !errors.Is(err, ErrMissingLanguageData) || !strings.Contains(err.Error(), "ISO-2022-KR") {
+		t.Errorf("expected ErrMissingLanguageData for ISO-2022-KR, got %v", err)
+	}
+}
+
+func TestCommand_LanguageData(t *testing.T) {
+	converter, err := New()
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if _, err := converter.Command("input.pdf", "-", PresetSimplifiedChinese()); !errors.Is(err, ErrCommandFailed) {
+		t.Errorf("expected ErrCommandFailed, got %v", err)
+	}
+}
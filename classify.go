@@ -0,0 +1,40 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Classifier labels documents, e.g. "invoice", "contract" or "report", so
+// ingestion can route them to different handlers
+type Classifier interface {
+	// Classify returns the label of a document from the text of its first
+	// page and its document information
+	Classify(ctx context.Context, firstPage string, info *Info) (string, error)
+}
+
+// ClassifierFunc adapts a function to a Classifier
+type ClassifierFunc func(ctx context.Context, firstPage string, info *Info) (string, error)
+
+// Classify calls f(ctx, firstPage, info)
+func (f ClassifierFunc) Classify(ctx context.Context, firstPage string, info *Info) (string, error) {
+	return f(ctx, firstPage, info)
+}
+
+// classify labels the converted file of result with opts.Classifier, if it
+// is set. A failure is reported in result.Err, keeping the text.
+func (c *Converter) classify(ctx context.Context, result *BatchResult, opts *Options) {
+	if opts == nil || opts.Classifier == nil || result.Err != nil || result.Skipped {
+		return
+	}
+	info, err := c.Info(ctx, result.Input, opts)
+	if err != nil {
+		result.Err = fmt.Errorf("classifying %s: %w", result.Input, err)
+		return
+	}
+	firstPage, _, _ := strings.Cut(result.Text, "\f")
+	if result.Label, err = opts.Classifier.Classify(ctx, firstPage, info); err != nil {
+		result.Err = fmt.Errorf("classifying %s: %w", result.Input, err)
+	}
+}
@@ -0,0 +1,81 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConverter_ConvertBatch_Classifier(t *testing.T) {
+	dir := t.TempDir()
+	var inputs []string
+	for _, name := range []string{"invoice.pdf", "contract.pdf", "broken.pdf"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		inputs = append(inputs, path)
+	}
+	inputs = append(inputs, inputs[0])
+
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		input := filepath.Base(args[len(args)-1])
+		if filepath.Base(name) == "pdfinfo" {
+			_, err := io.WriteString(stdout, "Title:           "+strings.TrimSuffix(input, ".pdf")+"\nPages:           2\n")
+			return err
+		}
+		input = filepath.Base(args[len(args)-2])
+		if input == "broken.pdf" {
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, "Page one of "+input+"\fPage two mentions an invoice\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	var firstPages []string
+	classifier := ClassifierFunc(func(ctx context.Context, firstPage string, info *Info) (string, error) {
+		firstPages = append(firstPages, firstPage)
+		if strings.Contains(firstPage, "contract") {
+			return "", errors.New("model unavailable")
+		}
+		return info.Title, nil
+	})
+	results := converter.ConvertBatch(context.Background(), inputs, 1, &Options{Classifier: classifier})
+
+	tests := []struct {
+		name  string
+		label string
+		err   bool
+	}{
+		{"invoice.pdf", "invoice", false},
+		{"contract.pdf", "", true},
+		{"broken.pdf", "", true},
+		{"invoice.pdf duplicate", "invoice", false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results[i]
+			if r.Label != tt.label || (r.Err != nil) != tt.err {
+				t.Errorf("unexpected result %+v", r)
+			}
+		})
+	}
+	if len(firstPages) != 2 {
+		t.Fatalf("expected 2 classifications, got %q", firstPages)
+	}
+	for _, page := range firstPages {
+		if strings.Contains(page, "Page two") {
+			t.Errorf("expected only the first page, got %q", page)
+		}
+	}
+	if results[1].Text == "" {
+		t.Error("expected a failed classification to keep the text")
+	}
+}
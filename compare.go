@@ -0,0 +1,245 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ModeResult is the output of one extraction mode compared by CompareModes
+type ModeResult struct {
+	// Options are the options of the mode
+	Options Options
+	// Text is the extracted text
+	Text string
+	// Words is the number of whitespace-separated words of Text
+	Words int
+	// Lines is the number of non-blank lines of Text
+	Lines int
+	// Duration is how long the extraction took
+	Duration time.Duration
+}
+
+// ModeDiff compares the output of two extraction modes
+type ModeDiff struct {
+	// A and B are the indexes of the compared modes
+	A, B int
+	// Similarity is the Similarity of the two texts, 1 if they have the same
+	// words
+	Similarity float64
+	// Diff is the line diff from the text of A to the text of B, with the
+	// whitespace of each line collapsed and blank lines left out
+	Diff []DiffLine
+}
+
+// Comparison is the result of CompareModes
+type Comparison struct {
+	// Modes are the results of the modes in the order given
+	Modes []ModeResult
+	// Diffs compare each pair of modes, A before B
+	Diffs []ModeDiff
+}
+
+// DiffOp is the operation of a DiffLine
+type DiffOp int
+
+const (
+	// DiffEqual lines are in both texts
+	DiffEqual DiffOp = iota
+	// DiffDelete lines are only in the first text
+	DiffDelete
+	// DiffInsert lines are only in the second text
+	DiffInsert
+)
+
+// DiffLine is a line of a diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// CompareModes extracts the text of a PDF file with each of modes, e.g. with
+// Layout, with Raw and with neither, and compares the results pairwise, to
+// help choose the options for a class of documents
+func (c *Converter) CompareModes(ctx context.Context, inputPath string, modes ...Options) (Comparison, error) {
+	var comparison Comparison
+	for i, mode := range modes {
+		start := time.Now()
+		text, err := c.Convert(ctx, inputPath, &mode)
+		if err != nil {
+			return Comparison{}, fmt.Errorf("mode %d: %w", i, err)
+		}
+		comparison.Modes = append(comparison.Modes, ModeResult{
+			Options:  mode,
+			Text:     text,
+			Words:    len(strings.Fields(text)),
+			Lines:    len(diffableLines(text)),
+			Duration: time.Since(start),
+		})
+	}
+
+	for i := range comparison.Modes {
+		for j := i + 1; j < len(comparison.Modes); j++ {
+			a, b := comparison.Modes[i].Text, comparison.Modes[j].Text
+			comparison.Diffs = append(comparison.Diffs, ModeDiff{
+				A:          i,
+				B:          j,
+				Similarity: Similarity(a, b),
+				Diff:       Diff(a, b),
+			})
+		}
+	}
+	return comparison, nil
+}
+
+// Diff returns the line diff from a to b, with the whitespace of each line
+// collapsed and blank lines left out, so differences in spacing alone are
+// ignored
+func Diff(a, b string) []DiffLine {
+	return diffLines(diffableLines(a), diffableLines(b))
+}
+
+// FormatDiff formats diff with "  ", "- " and "+ " line prefixes
+func FormatDiff(diff []DiffLine) string {
+	var sb strings.Builder
+	for _, line := range diff {
+		sb.WriteString([...]string{"  ", "- ", "+ "}[line.Op])
+		sb.WriteString(line.Text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// diffableLines returns the non-blank lines of text with their whitespace
+// collapsed
+func diffableLines(text string) []string {
+	var lines []string
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\f' }) {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script from a to b with the Myers
+// algorithm
+func diffLines(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(a, b, trace, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrackDiff follows the furthest reaching paths recorded in trace back
+// from the end of a and b
+func backtrackDiff(a, b []string, trace [][]int, offset int) []DiffLine {
+	var diff []DiffLine
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			diff = append(diff, DiffLine{DiffEqual, a[x-1]})
+			x, y = x-1, y-1
+		}
+		if x == prevX {
+			diff = append(diff, DiffLine{DiffInsert, b[y-1]})
+			y--
+		} else {
+			diff = append(diff, DiffLine{DiffDelete, a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		diff = append(diff, DiffLine{DiffEqual, a[x-1]})
+		x, y = x-1, y-1
+	}
+	slices.Reverse(diff)
+	return diff
+}
+
+// BinaryDiff compares the text two converters extract from a file, returned
+// by CompareBinaries
+type BinaryDiff struct {
+	// Input is the compared file
+	Input string `json:"input"`
+	// Identical reports whether both converters extracted exactly the same
+	// text
+	Identical bool `json:"identical"`
+	// Similarity is the Similarity of the two texts, 0 if a conversion
+	// failed
+	Similarity float64 `json:"similarity"`
+	// Diff is the line diff from the baseline text to the candidate text,
+	// with the whitespace of each line collapsed and blank lines left out
+	Diff []DiffLine `json:"-"`
+	// BaselineErr and CandidateErr are the errors of failed conversions
+	BaselineErr  error `json:"-"`
+	CandidateErr error `json:"-"`
+}
+
+// CompareBinaries converts each of inputs with baseline and candidate, with
+// up to workers files at a time, and compares the texts, returning a
+// BinaryDiff for each input in order. Baseline and candidate are typically
+// converters using the pdftotext binaries of the installed and of an
+// upgraded poppler, see WithBinaryPath, so an upgrade can be validated on a
+// corpus before it is rolled out.
+func CompareBinaries(ctx context.Context, baseline, candidate *Converter, inputs []string, workers int, opts *Options) []BinaryDiff {
+	diffs := make([]BinaryDiff, len(inputs))
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			d := &diffs[i]
+			d.Input = input
+			var a, b string
+			a, d.BaselineErr = baseline.Convert(ctx, input, opts)
+			b, d.CandidateErr = candidate.Convert(ctx, input, opts)
+			if d.BaselineErr != nil || d.CandidateErr != nil {
+				return
+			}
+			d.Identical = a == b
+			d.Similarity = Similarity(a, b)
+			if !d.Identical {
+				d.Diff = Diff(a, b)
+			}
+		}()
+	}
+	wg.Wait()
+	return diffs
+}
@@ -0,0 +1,128 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			name:     "Equal up to whitespace",
+			a:        "one  two\n\nthree",
+			b:        "one two\nthree   ",
+			expected: "  one two\n  three\n",
+		},
+		{
+			name:     "Changes",
+			a:        "title\nleft right\nfooter",
+			b:        "title\nleft\nright\nfooter\nextra",
+			expected: "  title\n- left right\n+ left\n+ right\n  footer\n+ extra\n",
+		},
+		{
+			name:     "Empty",
+			a:        "",
+			b:        "only\fpages",
+			expected: "+ only\n+ pages\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDiff(Diff(tt.a, tt.b)); got != tt.expected {
+				t.Errorf("expected\n%s\ngot\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConverter_CompareModes(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		switch {
+		case slices.Contains(args, "-layout"):
+			io.WriteString(stdout, "Name      Amount\nWidget    10\n")
+		case slices.Contains(args, "-raw"):
+			io.WriteString(stdout, "Name\nAmount\nWidget\n10\n")
+		case slices.Contains(args, "-nodiag"):
+			return &ExitError{Code: 3}
+		default:
+			io.WriteString(stdout, "Name Amount\nWidget 10\n")
+		}
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	comparison, err := converter.CompareModes(ctx, "input.pdf", Options{Layout: true}, Options{Raw: true}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comparison.Modes) != 3 || comparison.Modes[1].Lines != 4 || comparison.Modes[0].Words != 4 {
+		t.Errorf("unexpected modes %+v", comparison.Modes)
+	}
+	if len(comparison.Diffs) != 3 {
+		t.Fatalf("expected 3 diffs, got %d", len(comparison.Diffs))
+	}
+	for _, d := range comparison.Diffs {
+		if d.Similarity != 1 {
+			t.Errorf("expected modes %d and %d to have the same words, got %g", d.A, d.B, d.Similarity)
+		}
+	}
+	if layoutPlain := comparison.Diffs[1]; layoutPlain.A != 0 || layoutPlain.B != 2 || FormatDiff(layoutPlain.Diff) != "  Name Amount\n  Widget 10\n" {
+		t.Errorf("unexpected diff %+v", layoutPlain)
+	}
+
+	_, err = converter.CompareModes(ctx, "input.pdf", Options{}, Options{NoDiagonal: true})
+	if !errors.Is(err, ErrPermissions) {
+		t.Errorf("expected ErrPermissions, got %v", err)
+	}
+}
+
+func TestCompareBinaries(t *testing.T) {
+	// the candidate binary extracts a ligature differently and fails on
+	// broken.pdf
+	newConverter := func(candidate bool) *Converter {
+		converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			switch input := args[len(args)-2]; {
+			case input == "broken.pdf" && candidate:
+				io.WriteString(stderr, "Syntax Error: Couldn't read xref table")
+				return &ExitError{Code: 1}
+			case input == "ligature.pdf" && candidate:
+				io.WriteString(stdout, "the ﬁrst ﬁle\nends here\n")
+			case input == "ligature.pdf":
+				io.WriteString(stdout, "the first file\nends here\n")
+			default:
+				io.WriteString(stdout, "same text\n")
+			}
+			return nil
+		})))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+		return converter
+	}
+
+	inputs := []string{"same.pdf", "ligature.pdf", "broken.pdf"}
+	diffs := CompareBinaries(context.Background(), newConverter(false), newConverter(true), inputs, 2, nil)
+	if len(diffs) != len(inputs) {
+		t.Fatalf("expected %d diffs, got %d", len(inputs), len(diffs))
+	}
+	if d := diffs[0]; d.Input != "same.pdf" || !d.Identical || d.Similarity != 1 || d.Diff != nil {
+		t.Errorf("unexpected diff of the same text %+v", d)
+	}
+	if d := diffs[1]; d.Identical || d.Similarity != 0.6 || FormatDiff(d.Diff) != "- the first file\n+ the ﬁrst ﬁle\n  ends here\n" {
+		t.Errorf("unexpected diff of the ligatures %+v", d)
+	}
+	if d := diffs[2]; d.BaselineErr != nil || !errors.Is(d.CandidateErr, ErrPDFOpen) || d.Identical {
+		t.Errorf("expected the candidate to fail, got %+v", d)
+	}
+}
@@ -0,0 +1,48 @@
+package pdftotext
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// Compressor compresses the files written by ConvertToFile, see
+// Options.Compression
+type Compressor interface {
+	// Extension is the file extension of the compressed files, e.g. ".gz"
+	Extension() string
+	// NewWriter returns a writer compressing to w. Closing it flushes the
+	// compressed data but must not close w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+// Decompressor is implemented by Compressors that can read their files back,
+// for Options.CheckOutput
+type Decompressor interface {
+	// NewReader returns a reader decompressing r
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// Gzip is a Compressor writing gzip files
+type Gzip struct {
+	// Level is the compression level, gzip.DefaultCompression if zero
+	Level int
+}
+
+// Extension returns ".gz"
+func (Gzip) Extension() string {
+	return ".gz"
+}
+
+// NewWriter returns a gzip writer
+func (g Gzip) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	level := g.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// NewReader returns a gzip reader
+func (Gzip) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
@@ -0,0 +1,83 @@
+package pdftotext
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConverter_Compression(t *testing.T) {
+	converter := &Converter{binaryPath: "pdftotext", runner: runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if args[len(args)-2] == "broken.pdf" {
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, "page 1\f")
+		return err
+	})}
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		input         string
+		output        string
+		expectedPath  string
+		expectedError error
+	}{
+		{
+			name:         "Extension appended",
+			input:        "input.pdf",
+			output:       "output.txt",
+			expectedPath: "output.txt.gz",
+		},
+		{
+			name:         "Extension present",
+			input:        "input.pdf",
+			output:       "output.gz",
+			expectedPath: "output.gz",
+		},
+		{
+			name:          "Failed conversion",
+			input:         "broken.pdf",
+			output:        "output.txt",
+			expectedError: ErrPDFOpen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := converter.ConvertToFile(ctx, tt.input, filepath.Join(dir, tt.output), &Options{Compression: Gzip{Level: gzip.BestSpeed}})
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+					t.Errorf("expected no output file, got %v", entries)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to convert: %v", err)
+			}
+			f, err := os.Open(filepath.Join(dir, tt.expectedPath))
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer f.Close()
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatalf("failed to read gzip header: %v", err)
+			}
+			text, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatalf("failed to decompress: %v", err)
+			}
+			if string(text) != "page 1\f" {
+				t.Errorf("expected %q, got %q", "page 1\f", text)
+			}
+		})
+	}
+}
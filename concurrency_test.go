@@ -0,0 +1,117 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Run with -race to check that conversions sharing a Converter and Options
+// do not race
+func TestConverter_Concurrent(t *testing.T) {
+	converter, err := New(WithRetry(1, 0), WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		first, last := 1, 3
+		for i, arg := range args {
+			switch arg {
+			case "-f":
+				fmt.Sscan(args[i+1], &first)
+			case "-l":
+				fmt.Sscan(args[i+1], &last)
+			}
+		}
+		for page := first; page <= last; page++ {
+			fmt.Fprintf(stdout, "page %d\f", page)
+		}
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	// one Options value shared by all conversions
+	opts := &Options{
+		Pages:         "1,3",
+		PageSeparator: "\n--\n",
+		Transformers: []Transformer{TransformerFunc(func(page int, text string) (string, error) {
+			return strings.ToUpper(text), nil
+		})},
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 48)
+	for range 16 {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			text, err := converter.Convert(ctx, "input.pdf", opts)
+			if err == nil && text != "PAGE 1\n--\nPAGE 3" {
+				err = fmt.Errorf("unexpected text %q", text)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			pages, err := converter.ConvertPages(ctx, "input.pdf", opts)
+			if err == nil && (len(pages) != 2 || pages[1].Text != "PAGE 3") {
+				err = fmt.Errorf("unexpected pages %q", pages)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			n := 0
+			for _, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+				if err != nil {
+					errs <- err
+					return
+				}
+				n++
+			}
+			if n != 2 {
+				errs <- fmt.Errorf("expected 2 pages, got %d", n)
+				return
+			}
+			errs <- nil
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if opts.Pages != "1,3" || opts.PageSeparator != "\n--\n" || len(opts.Transformers) != 1 {
+		t.Errorf("expected the options to be unchanged, got %+v", opts)
+	}
+}
+
+func TestConverter_Clone(t *testing.T) {
+	failing := errors.New("failing runner")
+	converter, err := New(WithRunner(fakeRun("original", "", 0)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	clone, err := converter.Clone(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		return failing
+	})))
+	if err != nil {
+		t.Fatalf("failed to clone converter: %v", err)
+	}
+
+	ctx := context.Background()
+	if text, err := converter.Convert(ctx, "input.pdf", nil); err != nil || text != "original" {
+		t.Errorf("expected the original converter to be unchanged, got %q, %v", text, err)
+	}
+	if _, err := clone.Convert(ctx, "input.pdf", nil); !errors.Is(err, failing) {
+		t.Errorf("expected the clone to use the new runner, got %v", err)
+	}
+
+	if _, err := converter.Clone(WithBinaryPath("/nonexistent/pdftotext"), WithRunner(ExecRunner{})); !errors.Is(err, ErrBinaryNotFound) {
+		t.Errorf("expected ErrBinaryNotFound, got %v", err)
+	}
+}
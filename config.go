@@ -0,0 +1,134 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Config is the fully resolved configuration of a Converter and the options
+// of a conversion, with passwords masked, so support requests and audits can
+// capture exactly how a service converts documents
+type Config struct {
+	// BinaryPath is the resolved path of the pdftotext binary
+	BinaryPath string `json:"binary_path"`
+	// BinaryVersion is the version reported by the binary
+	BinaryVersion string `json:"binary_version"`
+	// Runner is the Go type of the Runner executing the binary
+	Runner string `json:"runner"`
+	// Retries and RetryBackoff are the retry settings of WithRetry
+	Retries      int           `json:"retries"`
+	RetryBackoff time.Duration `json:"retry_backoff"`
+	// RetryClassifier is "IsTransient" or "custom"
+	RetryClassifier string `json:"retry_classifier"`
+	// HashAlgorithm is the name of the content hash algorithm
+	HashAlgorithm string `json:"hash_algorithm"`
+	// MaxFileSize is the largest file Validate accepts, see WithMaxFileSize
+	MaxFileSize int64 `json:"max_file_size"`
+	// MaxStderr is the number of bytes of stderr kept, see WithMaxStderr
+	MaxStderr int `json:"max_stderr"`
+
+	// Resolution, ColSpacing, Encoding and EOL are the output settings with
+	// the pdftotext defaults filled in
+	Resolution int     `json:"resolution"`
+	ColSpacing float64 `json:"col_spacing"`
+	Encoding   string  `json:"encoding"`
+	EOL        EOLType `json:"eol"`
+	// Args are the arguments passed to the binary for a conversion to
+	// stdout, with "<input>" in place of the input path
+	Args []string `json:"args"`
+	// Pages, ExcludePages and TailPages select the converted pages
+	Pages        string `json:"pages,omitempty"`
+	ExcludePages string `json:"exclude_pages,omitempty"`
+	TailPages    int    `json:"tail_pages,omitempty"`
+	// MaxInputBytes is the largest input converted, 0 for no limit
+	MaxInputBytes int64 `json:"max_input_bytes,omitempty"`
+	// MaxOutputBytes is the largest text ConvertEx returns, 0 for no limit
+	MaxOutputBytes int64 `json:"max_output_bytes,omitempty"`
+	// TimeoutPerPage, TimeoutPerMB, MinTimeout and MaxTimeout bound the
+	// time a conversion may take
+	TimeoutPerPage time.Duration `json:"timeout_per_page,omitempty"`
+	TimeoutPerMB   time.Duration `json:"timeout_per_mb,omitempty"`
+	MinTimeout     time.Duration `json:"min_timeout,omitempty"`
+	MaxTimeout     time.Duration `json:"max_timeout,omitempty"`
+	// PreHooks and PostHooks are the numbers of pre and post hooks
+	PreHooks  int `json:"pre_hooks,omitempty"`
+	PostHooks int `json:"post_hooks,omitempty"`
+
+	// Normalize is the Unicode normalization form of the output
+	Normalize NormalizationForm `json:"normalize,omitempty"`
+	// Transformers are the Go types of the transformers, in order
+	Transformers []string `json:"transformers,omitempty"`
+	// LanguageDetector is the Go type of the language detector, if any
+	LanguageDetector string `json:"language_detector,omitempty"`
+	// EntityExtractor is the Go type of the entity extractor, if any
+	EntityExtractor string `json:"entity_extractor,omitempty"`
+	// Classifier is the Go type of the classifier, if any
+	Classifier string `json:"classifier,omitempty"`
+	// PageSeparator replaces the form feeds between pages
+	PageSeparator string `json:"page_separator,omitempty"`
+}
+
+// EffectiveConfig returns the configuration the converter uses to convert a
+// document with opts. It runs the binary to look up its version.
+func (c *Converter) EffectiveConfig(ctx context.Context, opts *Options) (*Config, error) {
+	version, err := c.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	eol := cmp.Or(opts.EOL, EOLUnix)
+	if eol == EOLAuto {
+		eol = hostEOL
+	}
+	cfg := &Config{
+		BinaryPath:      c.binaryPath,
+		BinaryVersion:   version,
+		Runner:          fmt.Sprintf("%T", c.runner),
+		Retries:         c.retries,
+		RetryBackoff:    c.retryBackoff,
+		RetryClassifier: "custom",
+		HashAlgorithm:   c.hash.Name,
+		MaxFileSize:     c.maxFileSize,
+		MaxStderr:       c.maxStderr,
+		Resolution:      cmp.Or(opts.Resolution, 72),
+		ColSpacing:      cmp.Or(opts.ColSpacing, 0.7),
+		Encoding:        cmp.Or(opts.Encoding, "UTF-8"),
+		EOL:             eol,
+		Args:            maskPasswords(c.buildArgs(opts, "<input>", "-")),
+		Pages:           opts.Pages,
+		ExcludePages:    opts.ExcludePages,
+		TailPages:       opts.TailPages,
+		MaxInputBytes:   opts.MaxInputBytes,
+		MaxOutputBytes:  opts.MaxOutputBytes,
+		TimeoutPerPage:  opts.TimeoutPerPage,
+		TimeoutPerMB:    opts.TimeoutPerMB,
+		MinTimeout:      opts.MinTimeout,
+		MaxTimeout:      opts.MaxTimeout,
+		PreHooks:        len(opts.PreHooks),
+		PostHooks:       len(opts.PostHooks),
+		Normalize:       opts.Normalize,
+		PageSeparator:   opts.PageSeparator,
+	}
+	if c.retryable != nil && reflect.ValueOf(c.retryable).Pointer() == reflect.ValueOf(IsTransient).Pointer() {
+		cfg.RetryClassifier = "IsTransient"
+	}
+	for _, t := range opts.Transformers {
+		cfg.Transformers = append(cfg.Transformers, fmt.Sprintf("%T", t))
+	}
+	if opts.LanguageDetector != nil {
+		cfg.LanguageDetector = fmt.Sprintf("%T", opts.LanguageDetector)
+	}
+	if opts.EntityExtractor != nil {
+		cfg.EntityExtractor = fmt.Sprintf("%T", opts.EntityExtractor)
+	}
+	if opts.Classifier != nil {
+		cfg.Classifier = fmt.Sprintf("%T", opts.Classifier)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,83 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConverter_EffectiveConfig(t *testing.T) {
+	runner := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "pdftotext version 24.02.0\n")
+		return nil
+	})
+	converter, err := New(WithRunner(runner), WithBinaryPath("/usr/bin/pdftotext"), WithRetry(2, time.Second))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	opts := &Options{
+		Layout:        true,
+		UserPassword:  "secret",
+		Pages:         "1-3",
+		Normalize:     NFKC,
+		Transformers:  []Transformer{Dehyphenator{}, WhitespaceNormalizer{}},
+		MaxInputBytes: 1 << 20,
+		MaxTimeout:    time.Minute,
+	}
+	cfg, err := converter.EffectiveConfig(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &Config{
+		BinaryPath:      "/usr/bin/pdftotext",
+		BinaryVersion:   "24.02.0",
+		Runner:          "pdftotext.runnerFunc",
+		Retries:         2,
+		RetryBackoff:    time.Second,
+		RetryClassifier: "IsTransient",
+		HashAlgorithm:   "sha256",
+		MaxFileSize:     DefaultMaxFileSize,
+		MaxStderr:       DefaultMaxStderr,
+		Resolution:      72,
+		ColSpacing:      0.7,
+		Encoding:        "UTF-8",
+		EOL:             EOLUnix,
+		Args:            platformArgs("-layout", "-upw", "***", "<input>", "-"),
+		Pages:           "1-3",
+		MaxInputBytes:   1 << 20,
+		MaxTimeout:      time.Minute,
+		Normalize:       NFKC,
+		Transformers:    []string{"pdftotext.Dehyphenator", "pdftotext.WhitespaceNormalizer"},
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cfg)
+	}
+	if _, err := json.Marshal(cfg); err != nil {
+		t.Errorf("failed to encode config: %v", err)
+	}
+
+	converter, err = New(WithRunner(runner), WithRetryClassifier(func(error) bool { return false }))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if cfg, err := converter.EffectiveConfig(context.Background(), nil); err != nil || cfg.RetryClassifier != "custom" {
+		t.Errorf("expected custom retry classifier, got %+v, %v", cfg, err)
+	}
+
+	failing := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		return &ExitError{Code: 99}
+	})
+	converter, err = New(WithRunner(failing))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if _, err := converter.EffectiveConfig(context.Background(), nil); !errors.Is(err, ErrCommandFailed) {
+		t.Errorf("expected error %v, got %v", ErrCommandFailed, err)
+	}
+}
@@ -0,0 +1,106 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// confinedKey is the context key marking the paths of a conversion as
+// checked against the allowed roots
+type confinedKey struct{}
+
+// WithAllowedRoots confines the converter to files below the given
+// directories, for services accepting client-supplied paths: the input and
+// output paths of conversions, Validate, the pdfinfo-based APIs and the APIs
+// parsing the file themselves, such as Annotations, FormFields and
+// IsEncrypted, are resolved to absolute paths without symbolic links and fail
+// with ErrPathNotAllowed if they are outside every root. New fails if a root
+// does not exist. Output paths are not checked when the output is written to
+// an OutputFS. A symbolic link replaced between the check and the conversion
+// is not detected, so the roots must not be writable by the clients.
+func WithAllowedRoots(dirs ...string) ConverterOption {
+	return func(c *Converter) {
+		c.allowedRoots = append(c.allowedRoots, dirs...)
+	}
+}
+
+// resolveRoots resolves the allowed roots of c to absolute paths without
+// symbolic links
+func (c *Converter) resolveRoots() error {
+	for i, root := range c.allowedRoots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("allowed root %s: %w", root, err)
+		}
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return fmt.Errorf("allowed root %s: %w", root, err)
+		}
+		c.allowedRoots[i] = resolved
+	}
+	return nil
+}
+
+// confine checks that the paths, except "-" for stdout, are below the
+// allowed roots of c. It returns a context marking the paths as checked, so
+// the conversions a conversion is made of can use temporary files.
+func (c *Converter) confine(ctx context.Context, inputPath, outputPath string) (context.Context, error) {
+	if len(c.allowedRoots) == 0 || ctx.Value(confinedKey{}) != nil {
+		return ctx, nil
+	}
+	paths := []string{inputPath}
+	if outputPath != "" && outputPath != "-" && c.outputFS == nil {
+		paths = append(paths, outputPath)
+	}
+	for _, path := range paths {
+		if err := c.checkAllowed(path); err != nil {
+			return ctx, err
+		}
+	}
+	return trusted(ctx), nil
+}
+
+// trusted returns a context marking the paths of a conversion as checked,
+// for the temporary files the converter creates itself
+func trusted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, confinedKey{}, true)
+}
+
+// checkAllowed returns ErrPathNotAllowed if path resolves to a file outside
+// the allowed roots
+func (c *Converter) checkAllowed(path string) error {
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrPathNotAllowed, path, err)
+	}
+	for _, root := range c.allowedRoots {
+		rel, err := filepath.Rel(root, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is outside the allowed roots", ErrPathNotAllowed, path)
+}
+
+// resolvePath returns the absolute path of path with the symbolic links
+// resolved. A file that does not exist yet, such as an output file, is
+// resolved through its directory.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if errors.Is(err, fs.ErrNotExist) {
+		dir, dirErr := filepath.EvalSymlinks(filepath.Dir(abs))
+		if dirErr != nil {
+			return "", dirErr
+		}
+		return filepath.Join(dir, filepath.Base(abs)), nil
+	}
+	return resolved, err
+}
@@ -0,0 +1,117 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithAllowedRoots(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	for _, name := range []string{"doc.pdf", "-upw.pdf"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("%PDF-1.7\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	secret := filepath.Join(outside, "secret.pdf")
+	if err := os.WriteFile(secret, []byte("%PDF-1.7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(root, "link.pdf")); err != nil {
+		t.Skip("symbolic links are not supported:", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "dir")); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(WithRunner(fakeRun("text", "", 0)), WithAllowedRoots(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		input   string
+		output  string
+		wantErr bool
+	}{
+		{"inside", filepath.Join(root, "doc.pdf"), "-", false},
+		{"dash name", filepath.Join(root, "-upw.pdf"), "-", false},
+		{"new output", filepath.Join(root, "doc.pdf"), filepath.Join(root, "doc.txt"), false},
+		{"outside", secret, "-", true},
+		{"dot dot", filepath.Join(root, "..", filepath.Base(outside), "secret.pdf"), "-", true},
+		{"symlink", filepath.Join(root, "link.pdf"), "-", true},
+		{"output outside", filepath.Join(root, "doc.pdf"), filepath.Join(outside, "doc.txt"), true},
+		{"output through symlink", filepath.Join(root, "doc.pdf"), filepath.Join(root, "dir", "doc.txt"), true},
+		{"missing directory", filepath.Join(root, "missing", "doc.pdf"), "-", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.ConvertToFile(ctx, tt.input, tt.output, nil)
+			if got := errors.Is(err, ErrPathNotAllowed); got != tt.wantErr {
+				t.Fatalf("ConvertToFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := c.Info(ctx, tt.input, nil); tt.output == "-" && errors.Is(err, ErrPathNotAllowed) != tt.wantErr {
+				t.Errorf("Info() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		if err := c.Validate(ctx, secret); !errors.Is(err, ErrPathNotAllowed) {
+			t.Errorf("Validate() error = %v, want ErrPathNotAllowed", err)
+		}
+	})
+
+	entryPoints := []struct {
+		name string
+		call func() error
+	}{
+		{"Annotations", func() error { _, err := c.Annotations(ctx, secret); return err }},
+		{"FormFields", func() error { _, err := c.FormFields(ctx, secret); return err }},
+		{"IsEncrypted", func() error { _, err := c.IsEncrypted(ctx, secret); return err }},
+		{"ConvertToFileWithManifest", func() error {
+			_, err := c.ConvertToFileWithManifest(ctx, secret, filepath.Join(root, "secret.txt"), nil, nil)
+			return err
+		}},
+		{"ChargeFile", func() error { _, err := (&Quota{}).ChargeFile(ctx, c, "acme", secret, nil); return err }},
+		{"ConvertPages", func() error {
+			_, err := c.ConvertPages(ctx, secret, &Options{PageLabels: true})
+			return err
+		}},
+		{"StreamPages", func() error {
+			for _, err := range c.StreamPages(ctx, secret, &Options{PageLabels: true}) {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+	}
+	for _, tt := range entryPoints {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrPathNotAllowed) {
+				t.Errorf("%s() error = %v, want ErrPathNotAllowed", tt.name, err)
+			}
+		})
+	}
+
+	t.Run("ConvertReader", func(t *testing.T) {
+		text, err := c.ConvertReader(ctx, strings.NewReader("%PDF-1.7\n"), nil)
+		if err != nil || text != "text" {
+			t.Errorf("ConvertReader() = %q, %v", text, err)
+		}
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		_, err := New(WithRunner(fakeRun("", "", 0)), WithAllowedRoots(filepath.Join(root, "missing")))
+		if err == nil {
+			t.Error("New() succeeded with a missing root")
+		}
+	})
+}
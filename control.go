@@ -0,0 +1,51 @@
+package pdftotext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ControlStripper is a Transformer removing the characters poppler emits for
+// text in broken encodings, which corrupt JSON payloads and database inserts
+// downstream: control characters other than tabs, line breaks and form feeds,
+// U+FFFD replacement characters and invalid UTF-8.
+type ControlStripper struct {
+	// Replacement is written in place of each removed character, e.g. "?".
+	// The characters are dropped if it is empty.
+	Replacement string
+}
+
+// Transform strips the control characters from text
+func (s ControlStripper) Transform(page int, text string) (string, error) {
+	i := strings.IndexFunc(text, stripped)
+	if i < 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	b.WriteString(text[:i])
+	for text = text[i:]; text != ""; {
+		r, size := utf8.DecodeRuneInString(text)
+		if stripped(r) {
+			b.WriteString(s.Replacement)
+		} else {
+			b.WriteString(text[:size])
+		}
+		text = text[size:]
+	}
+	return b.String(), nil
+}
+
+// stripped reports whether a ControlStripper removes r. Invalid UTF-8 is
+// decoded as utf8.RuneError, the replacement character.
+func stripped(r rune) bool {
+	switch r {
+	case '\t', '\n', '\r', '\f':
+		return false
+	case utf8.RuneError:
+		return true
+	}
+	return unicode.IsControl(r)
+}
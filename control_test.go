@@ -0,0 +1,56 @@
+package pdftotext
+
+import "testing"
+
+func TestControlStripper_Transform(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        ControlStripper
+		input    string
+		expected string
+	}{
+		{
+			name:     "clean text",
+			input:    "tab\tand\r\nlines\f",
+			expected: "tab\tand\r\nlines\f",
+		},
+		{
+			name:     "control characters",
+			input:    "a\x00b\x1bc\x7fd\u0085e",
+			expected: "abcde",
+		},
+		{
+			name:     "replacement characters",
+			input:    "caf\uFFFD \uFFFD\uFFFD",
+			expected: "caf ",
+		},
+		{
+			name:     "invalid utf-8",
+			input:    "a\xffb\xc3",
+			expected: "ab",
+		},
+		{
+			name:     "replacement",
+			s:        ControlStripper{Replacement: "?"},
+			input:    "a\x00b\uFFFDc\xff",
+			expected: "a?b?c?",
+		},
+		{
+			name:     "unicode text",
+			input:    "naïve\x01 日本",
+			expected: "naïve 日本",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Transform(1, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %+q, got %+q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package pdftotext
+
+import (
+	"context"
+	"strings"
+)
+
+// CoverageReport tells which pages of a document have a text layer and which
+// are image-only, to estimate the OCR work of a document before committing
+// to it
+type CoverageReport struct {
+	// Pages is the number of pages
+	Pages int `json:"pages"`
+	// TextPages is the number of pages with a text layer
+	TextPages int `json:"text_pages"`
+	// ImagePages is the number of pages without text. Blank pages count as
+	// image-only, since pdftotext cannot tell them apart.
+	ImagePages int `json:"image_pages"`
+	// TextPercent and ImagePercent are TextPages and ImagePages in percent
+	// of Pages
+	TextPercent  float64 `json:"text_percent"`
+	ImagePercent float64 `json:"image_percent"`
+	// TextRanges and ImageRanges are the ranges of pages with and without
+	// text, in order
+	TextRanges  []PageRange `json:"text_ranges"`
+	ImageRanges []PageRange `json:"image_ranges"`
+}
+
+// TextCoverage converts a PDF file page by page and reports the ranges of
+// pages with a text layer and without one. The OCR, re-extraction and
+// Transformers of opts are not applied, so the report reflects the text
+// layer as it is.
+func (c *Converter) TextCoverage(ctx context.Context, inputPath string, opts *Options) (*CoverageReport, error) {
+	coverageOpts := Options{}
+	if opts != nil {
+		coverageOpts = *opts
+	}
+	coverageOpts.OCR = nil
+	coverageOpts.MinQuality = 0
+	coverageOpts.GarbledStrategies = nil
+	coverageOpts.Transformers = nil
+	coverageOpts.StopAfter = nil
+	coverageOpts.EntityExtractor = nil
+	coverageOpts.LanguageDetector = nil
+
+	report := &CoverageReport{TextRanges: []PageRange{}, ImageRanges: []PageRange{}}
+	for page, err := range c.StreamPages(ctx, inputPath, &coverageOpts) {
+		if err != nil {
+			return nil, err
+		}
+		report.add(page.Number, strings.TrimSpace(page.Text) != "")
+	}
+	if report.Pages > 0 {
+		report.TextPercent = 100 * float64(report.TextPages) / float64(report.Pages)
+		report.ImagePercent = 100 * float64(report.ImagePages) / float64(report.Pages)
+	}
+	return report, nil
+}
+
+// add counts a page, extending the last range of its kind if it follows it
+func (r *CoverageReport) add(number int, hasText bool) {
+	r.Pages++
+	ranges := &r.ImageRanges
+	if hasText {
+		r.TextPages++
+		ranges = &r.TextRanges
+	} else {
+		r.ImagePages++
+	}
+	if n := len(*ranges); n > 0 && (*ranges)[n-1].Last == number-1 {
+		(*ranges)[n-1].Last = number
+		return
+	}
+	*ranges = append(*ranges, PageRange{First: number, Last: number})
+}
@@ -0,0 +1,39 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestConverter_TextCoverage(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "one\ftwo\f\f \n\ffive\f\fseven\feight\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	// OCR is not applied, so the scanned pages are reported as they are
+	ocr := OCRFunc(func(ctx context.Context, inputPath string, page int) (string, error) {
+		return "recognized", nil
+	})
+	report, err := converter.TextCoverage(context.Background(), "input.pdf", &Options{OCR: ocr})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Pages != 8 || report.TextPages != 5 || report.ImagePages != 3 {
+		t.Errorf("unexpected counts %+v", report)
+	}
+	if report.TextPercent != 62.5 || report.ImagePercent != 37.5 {
+		t.Errorf("unexpected percentages %v and %v", report.TextPercent, report.ImagePercent)
+	}
+	if expected := []PageRange{{1, 2}, {5, 5}, {7, 8}}; !slices.Equal(report.TextRanges, expected) {
+		t.Errorf("expected text ranges %v, got %v", expected, report.TextRanges)
+	}
+	if expected := []PageRange{{3, 4}, {6, 6}}; !slices.Equal(report.ImageRanges, expected) {
+		t.Errorf("expected image ranges %v, got %v", expected, report.ImageRanges)
+	}
+}
@@ -0,0 +1,142 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// CSVColumn is a column of the word CSV written by WriteWordsCSV
+type CSVColumn string
+
+// The columns of the word CSV, named like the columns of WriteWordsParquet
+const (
+	// CSVPath is the path of the PDF file
+	CSVPath CSVColumn = "path"
+	// CSVPage is the page number
+	CSVPage CSVColumn = "page"
+	// CSVWord is the position of the word on its page, counted from 0
+	CSVWord CSVColumn = "word"
+	// CSVText is the word
+	CSVText CSVColumn = "text"
+	// CSVXMin, CSVYMin, CSVXMax and CSVYMax are the bounding box of the
+	// word in points
+	CSVXMin CSVColumn = "x_min"
+	CSVYMin CSVColumn = "y_min"
+	CSVXMax CSVColumn = "x_max"
+	CSVYMax CSVColumn = "y_max"
+	// CSVPageWidth and CSVPageHeight are the size of the page in points
+	CSVPageWidth  CSVColumn = "page_width"
+	CSVPageHeight CSVColumn = "page_height"
+)
+
+// csvColumns are the columns written by default, in order
+var csvColumns = []CSVColumn{CSVPath, CSVPage, CSVWord, CSVText, CSVXMin, CSVYMin, CSVXMax, CSVYMax, CSVPageWidth, CSVPageHeight}
+
+// CSVOptions configure the word CSV
+type CSVOptions struct {
+	// Columns are the columns to write, in order. All columns are written if
+	// it is empty.
+	Columns []CSVColumn
+	// Comma is the field delimiter, ',' if it is zero. Spreadsheets in
+	// locales with a decimal comma expect ';'.
+	Comma rune
+	// BOM starts the file with a UTF-8 byte order mark, which Excel needs to
+	// read non-ASCII text as UTF-8
+	BOM bool
+}
+
+// ConvertWordsCSV converts a PDF file with ConvertGeometry and writes one row
+// per word with its bounding box to w as CSV, see WriteWordsCSV
+func (c *Converter) ConvertWordsCSV(ctx context.Context, inputPath string, w io.Writer, opts *Options, csvOpts *CSVOptions) error {
+	pages, err := c.ConvertGeometry(ctx, inputPath, opts)
+	if err != nil {
+		return err
+	}
+	return WriteWordsCSV(w, inputPath, pages, csvOpts)
+}
+
+// WriteWordsCSV writes the words of pages to w as CSV with a header row and
+// one row per word, quoting fields as RFC 4180 requires. Coordinates are
+// written in points with as many digits as needed and a decimal point.
+func WriteWordsCSV(w io.Writer, path string, pages []PageGeometry, csvOpts *CSVOptions) error {
+	columns := csvColumns
+	var comma rune
+	var bom bool
+	if csvOpts != nil {
+		if len(csvOpts.Columns) > 0 {
+			columns = csvOpts.Columns
+		}
+		comma, bom = csvOpts.Comma, csvOpts.BOM
+	}
+	for _, column := range columns {
+		if !slices.Contains(csvColumns, column) {
+			return fmt.Errorf("unknown CSV column %q", column)
+		}
+	}
+
+	if bom {
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return err
+		}
+	}
+	cw := csv.NewWriter(w)
+	if comma != 0 {
+		cw.Comma = comma
+	}
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = string(column)
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		for n, word := range page.Words {
+			for i, column := range columns {
+				record[i] = wordField(column, path, page, n, word)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// wordField returns the value of a column for the nth word of page
+func wordField(column CSVColumn, path string, page PageGeometry, n int, word Word) string {
+	switch column {
+	case CSVPath:
+		return path
+	case CSVPage:
+		return strconv.Itoa(page.Number)
+	case CSVWord:
+		return strconv.Itoa(n)
+	case CSVText:
+		return word.Text
+	case CSVXMin:
+		return formatPoints(word.Box.XMin)
+	case CSVYMin:
+		return formatPoints(word.Box.YMin)
+	case CSVXMax:
+		return formatPoints(word.Box.XMax)
+	case CSVYMax:
+		return formatPoints(word.Box.YMax)
+	case CSVPageWidth:
+		return formatPoints(page.Width)
+	case CSVPageHeight:
+		return formatPoints(page.Height)
+	}
+	return ""
+}
+
+// formatPoints formats a coordinate in points
+func formatPoints(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
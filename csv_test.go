@@ -0,0 +1,66 @@
+package pdftotext
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteWordsCSV(t *testing.T) {
+	pages := []PageGeometry{
+		{Number: 1, Width: 612, Height: 792, Words: []Word{
+			{Text: "Total:", Box: Rect{XMin: 72, YMin: 100.5, XMax: 110.25, YMax: 112}},
+			{Text: `"1,234"`, Box: Rect{XMin: 115, YMin: 100.5, XMax: 150, YMax: 112}},
+		}},
+		{Number: 2, Width: 612, Height: 792, Words: []Word{
+			{Text: "Übersicht", Box: Rect{XMin: 72, YMin: 80, XMax: 130, YMax: 92}},
+		}},
+	}
+
+	tests := []struct {
+		name          string
+		options       *CSVOptions
+		expected      string
+		expectedError bool
+	}{
+		{
+			name: "All columns",
+			expected: "path,page,word,text,x_min,y_min,x_max,y_max,page_width,page_height\n" +
+				"a.pdf,1,0,Total:,72,100.5,110.25,112,612,792\n" +
+				"a.pdf,1,1,\"\"\"1,234\"\"\",115,100.5,150,112,612,792\n" +
+				"a.pdf,2,0,Übersicht,72,80,130,92,612,792\n",
+		},
+		{
+			name:    "Selected columns",
+			options: &CSVOptions{Columns: []CSVColumn{CSVPage, CSVText, CSVXMin}},
+			expected: "page,text,x_min\n" +
+				"1,Total:,72\n" +
+				"1,\"\"\"1,234\"\"\",115\n" +
+				"2,Übersicht,72\n",
+		},
+		{
+			name:    "Semicolon and BOM",
+			options: &CSVOptions{Columns: []CSVColumn{CSVText, CSVXMax}, Comma: ';', BOM: true},
+			expected: "\ufefftext;x_max\n" +
+				"Total:;110.25\n" +
+				"\"\"\"1,234\"\"\";150\n" +
+				"Übersicht;130\n",
+		},
+		{
+			name:          "Unknown column",
+			options:       &CSVOptions{Columns: []CSVColumn{"font"}},
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteWordsCSV(&buf, "a.pdf", pages, tt.options)
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("expected\n%q\ngot\n%q", tt.expected, buf.String())
+			}
+		})
+	}
+}
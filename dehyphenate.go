@@ -0,0 +1,163 @@
+package pdftotext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Dehyphenator is a Transformer rejoining words hyphenated at line breaks, so
+// "infor-\nmation is" becomes "information\nis". The completed word is moved
+// to the first line, keeping the number of lines where possible.
+//
+// A hyphen is kept, and only the line break removed, where it is likely part
+// of the word: before a capital letter ("Anti-\nAmerican"), after a single
+// letter ("e-\nmail") and after prefixes that take a hyphen in the language,
+// such as "self-" in English. Suspended hyphens followed by a conjunction,
+// as in "pre- and post-war" or "Ein- und Ausgang", and dashes after a space
+// are left alone. Soft hyphens are always removed.
+type Dehyphenator struct {
+	// Language is the BCP 47 tag of the text, e.g. "en" or "de-CH", selecting
+	// language-specific safeguards. English rules are used if it is empty or
+	// unknown.
+	Language string
+	// IsWord optionally reports whether a word is in a dictionary. When set,
+	// a hyphenated word is only joined if IsWord accepts the result, and the
+	// hyphen is kept otherwise.
+	IsWord func(word string) bool
+}
+
+// hyphenRules are the language-specific safeguards of a Dehyphenator
+type hyphenRules struct {
+	// prefixes keep their hyphen when they end a line
+	prefixes []string
+	// conjunctions follow suspended hyphens, as in "pre- and post-war"
+	conjunctions []string
+	// suffixes keep the hyphen before them, such as French clitic pronouns
+	suffixes []string
+}
+
+var dehyphenationRules = map[string]hyphenRules{
+	"en": {
+		prefixes:     []string{"all", "cross", "ex", "half", "quasi", "self", "well"},
+		conjunctions: []string{"and", "nor", "or", "to"},
+	},
+	"de": {
+		conjunctions: []string{"als", "bis", "bzw", "oder", "sowie", "und"},
+	},
+	"nl": {
+		conjunctions: []string{"en", "of", "tot"},
+	},
+	"fr": {
+		prefixes:     []string{"après", "arrière", "avant", "ex", "sous"},
+		conjunctions: []string{"et", "ou"},
+		suffixes:     []string{"ce", "ci", "elle", "elles", "il", "ils", "je", "là", "même", "moi", "nous", "on", "t", "toi", "tu", "vous"},
+	},
+	"es": {
+		conjunctions: []string{"e", "o", "u", "y"},
+	},
+	"it": {
+		conjunctions: []string{"e", "ed", "o", "od"},
+	},
+}
+
+// hyphenAction is what a Dehyphenator does with a hyphen at a line break
+type hyphenAction int
+
+const (
+	hyphenLeave hyphenAction = iota
+	hyphenJoin
+	hyphenKeep
+)
+
+// Transform rejoins the words of text hyphenated at line breaks
+func (d Dehyphenator) Transform(page int, text string) (string, error) {
+	rules, ok := dehyphenationRules[primaryLanguage(d.Language)]
+	if !ok {
+		rules = dehyphenationRules["en"]
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines)-1; i++ {
+		eol := ""
+		if strings.HasSuffix(lines[i], "\r") {
+			eol = "\r"
+		}
+		line := strings.TrimRight(lines[i], " \t\r")
+		hyphen, size := utf8.DecodeLastRuneInString(line)
+		if hyphen != '-' && hyphen != '\u00ad' && hyphen != '\u2010' {
+			continue
+		}
+		head := line[:len(line)-size]
+		fragment := head[len(strings.TrimRightFunc(head, unicode.IsLetter)):]
+
+		next := strings.TrimRight(lines[i+1], "\r")
+		body := strings.TrimLeft(next, " \t")
+		indent := next[:len(next)-len(body)]
+		token, rest, _ := strings.Cut(body, " ")
+		cont := token[:len(token)-len(strings.TrimLeftFunc(token, unicode.IsLetter))]
+		if fragment == "" || cont == "" {
+			continue
+		}
+
+		switch d.action(rules, hyphen, fragment, cont) {
+		case hyphenLeave:
+			continue
+		case hyphenJoin:
+			lines[i] = head + token + eol
+		case hyphenKeep:
+			lines[i] = head + string(hyphen) + token + eol
+		}
+
+		if rest = strings.TrimLeft(rest, " \t"); rest != "" {
+			lines[i+1] = indent + rest + eol
+			continue
+		}
+		// the line only held the end of the word, which may itself be
+		// hyphenated again
+		lines = append(lines[:i+1], lines[i+2:]...)
+		i--
+	}
+	return strings.ReplaceAll(strings.Join(lines, "\n"), "\u00ad", ""), nil
+}
+
+// action decides what to do with the hyphen between fragment and cont
+func (d Dehyphenator) action(rules hyphenRules, hyphen rune, fragment, cont string) hyphenAction {
+	if hyphen == '\u00ad' {
+		return hyphenJoin
+	}
+
+	lowerCont := strings.ToLower(cont)
+	for _, c := range rules.conjunctions {
+		if lowerCont == c {
+			return hyphenLeave
+		}
+	}
+
+	first, _ := utf8.DecodeRuneInString(cont)
+	if unicode.IsUpper(first) || utf8.RuneCountInString(fragment) == 1 {
+		return hyphenKeep
+	}
+	lowerFragment := strings.ToLower(fragment)
+	for _, p := range rules.prefixes {
+		if lowerFragment == p {
+			return hyphenKeep
+		}
+	}
+	for _, s := range rules.suffixes {
+		if lowerCont == s {
+			return hyphenKeep
+		}
+	}
+
+	if d.IsWord != nil && !d.IsWord(fragment+cont) {
+		return hyphenKeep
+	}
+	return hyphenJoin
+}
+
+// primaryLanguage returns the lower case primary subtag of a BCP 47 tag
+func primaryLanguage(tag string) string {
+	tag, _, _ = strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	return strings.ToLower(tag)
+}
@@ -0,0 +1,129 @@
+package pdftotext
+
+import (
+	"testing"
+)
+
+func TestDehyphenator_Transform(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Dehyphenator
+		input    string
+		expected string
+	}{
+		{
+			name:     "joins hyphenated word",
+			input:    "the infor-\nmation is here\n",
+			expected: "the information\nis here\n",
+		},
+		{
+			name:     "keeps indentation and punctuation",
+			input:    "  the infor-  \n  mation, then\n",
+			expected: "  the information,\n  then\n",
+		},
+		{
+			name:     "removes emptied line",
+			input:    "a long infor-\nmation\nnext line",
+			expected: "a long information\nnext line",
+		},
+		{
+			name:     "repeated hyphenation",
+			input:    "inter-\nnation-\nalization works",
+			expected: "internationalization\nworks",
+		},
+		{
+			name:     "CRLF line endings",
+			input:    "infor-\r\nmation is\r\n",
+			expected: "information\r\nis\r\n",
+		},
+		{
+			name:     "capitalized continuation keeps hyphen",
+			input:    "anti-\nAmerican views",
+			expected: "anti-American\nviews",
+		},
+		{
+			name:     "single letter keeps hyphen",
+			input:    "send an e-\nmail today",
+			expected: "send an e-mail\ntoday",
+		},
+		{
+			name:     "English prefix keeps hyphen",
+			input:    "a self-\ncontained unit",
+			expected: "a self-contained\nunit",
+		},
+		{
+			name:     "suspended hyphen",
+			input:    "pre-\nand post-war",
+			expected: "pre-\nand post-war",
+		},
+		{
+			name:     "German suspended hyphen",
+			d:        Dehyphenator{Language: "de-CH"},
+			input:    "Ein-\nund Ausgang",
+			expected: "Ein-\nund Ausgang",
+		},
+		{
+			name:     "German compound",
+			d:        Dehyphenator{Language: "de"},
+			input:    "die Zusammen-\nfassung und Donau-\nDampfschiff",
+			expected: "die Zusammenfassung\nund Donau-Dampfschiff",
+		},
+		{
+			name:     "French clitic",
+			d:        Dehyphenator{Language: "fr_FR"},
+			input:    "que dit-\nil ici",
+			expected: "que dit-il\nici",
+		},
+		{
+			name:     "dash after space",
+			input:    "a pause -\nthen more",
+			expected: "a pause -\nthen more",
+		},
+		{
+			name:     "numbers",
+			input:    "from 1990-\n1995 onwards",
+			expected: "from 1990-\n1995 onwards",
+		},
+		{
+			name:     "soft hyphen",
+			input:    "Anti\u00ad\nAmerican and soft\u00adware",
+			expected: "AntiAmerican\nand software",
+		},
+		{
+			name:     "Unicode hyphen",
+			input:    "co\u2010\noperation",
+			expected: "cooperation",
+		},
+		{
+			name:     "dictionary",
+			d:        Dehyphenator{IsWord: func(word string) bool { return word == "information" }},
+			input:    "infor-\nmation and hand-\nmade",
+			expected: "information\nand hand-made",
+		},
+		{
+			name:     "last line",
+			input:    "ends with a hyphen-",
+			expected: "ends with a hyphen-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.Transform(1, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	for tag, expected := range map[string]string{"": "", "en": "en", "de-CH": "de", "fr_FR": "fr", "PT-br": "pt"} {
+		if got := primaryLanguage(tag); got != expected {
+			t.Errorf("primaryLanguage(%q): expected %q, got %q", tag, expected, got)
+		}
+	}
+}
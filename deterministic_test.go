@@ -0,0 +1,53 @@
+package pdftotext
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestConverter_Deterministic(t *testing.T) {
+	t.Run("Args", func(t *testing.T) {
+		converter, err := New(WithRunner(runnerFunc(nil)))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+		args := converter.buildArgs(&Options{Deterministic: true}, "input.pdf", "-")
+		if expected := []string{"-enc", "UTF-8", "-eol", "unix", "input.pdf", "-"}; !slices.Equal(args, expected) {
+			t.Errorf("expected args %v, got %v", expected, args)
+		}
+		args = converter.buildArgs(&Options{Deterministic: true, Encoding: "Latin1", EOL: EOLDos}, "input.pdf", "-")
+		if expected := []string{"-enc", "Latin1", "-eol", "dos", "input.pdf", "-"}; !slices.Equal(args, expected) {
+			t.Errorf("expected args %v, got %v", expected, args)
+		}
+	})
+
+	t.Run("Environment", func(t *testing.T) {
+		binary := writeFakeBinary(t, `printf '%s %s\f' "$LC_ALL" "$TZ"`)
+		converter, err := New(WithBinaryPath(binary))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+		t.Setenv("LC_ALL", "de_DE.UTF-8")
+		t.Setenv("TZ", "Europe/Berlin")
+
+		result, err := converter.ConvertEx(context.Background(), "input.pdf", &Options{Deterministic: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Text != "C UTC" {
+			t.Errorf("expected the C locale in UTC, got %q", result.Text)
+		}
+		if result.Stats.Duration != 0 {
+			t.Errorf("expected no duration, got %v", result.Stats.Duration)
+		}
+
+		text, err := converter.Convert(context.Background(), "input.pdf", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != "de_DE.UTF-8 Europe/Berlin" {
+			t.Errorf("expected the environment of the caller, got %q", text)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// diskCacheExt is the extension of the result files of a DiskCache
+const diskCacheExt = ".txt"
+
+// DiskCache is a cache of conversion results stored as files in a directory,
+// so the cache survives restarts of long-running services. Results expire
+// after a time to live, and the oldest results are removed when the files
+// exceed a total size. Each file starts with a line holding the expiry time
+// of the result in Unix nanoseconds, 0 if it does not expire. The cache keeps
+// track of the size it writes and scans the directory only when the size
+// exceeds the limit or the results may have expired, so the files other
+// processes write are counted at the next scan. It is safe for concurrent
+// use, also by several processes sharing the directory.
+type DiskCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	// mu serializes the evictions of this process and guards size and swept
+	mu sync.Mutex
+	// size is the total size of the results as of the last scan, plus the
+	// results written since
+	size int64
+	// swept is the time of the last scan, zero before the first one
+	swept  time.Time
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewDiskCache creates a DiskCache in dir, creating the directory if needed.
+// Results older than ttl are not returned, whatever the TTL they were cached
+// with, and the oldest results are removed when the results take more than
+// maxBytes. Zero disables either limit.
+func NewDiskCache(dir string, ttl time.Duration, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir, ttl: ttl, maxBytes: maxBytes}, nil
+}
+
+// path returns the file holding the result cached under key
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskCacheExt)
+}
+
+// tooOld reports whether a result written at modTime is older than the time
+// to live of the cache
+func (d *DiskCache) tooOld(modTime time.Time) bool {
+	return d.ttl > 0 && time.Since(modTime) > d.ttl
+}
+
+// Get returns the result cached under key, or ErrNotFound, counting a hit or
+// a miss
+func (d *DiskCache) Get(_ context.Context, key string) (string, error) {
+	text, err := d.read(d.path(key))
+	if err != nil {
+		d.misses.Add(1)
+		return "", err
+	}
+	d.hits.Add(1)
+	return text, nil
+}
+
+// read returns the text of a result file, removing it and returning
+// ErrNotFound if it expired
+func (d *DiskCache) read(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	header, text, ok := strings.Cut(string(data), "\n")
+	expires, err := strconv.ParseInt(header, 10, 64)
+	if !ok || err != nil {
+		return "", fmt.Errorf("invalid cache file %s", name)
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return "", err
+	}
+	if d.tooOld(info.ModTime()) || (expires > 0 && time.Now().UnixNano() > expires) {
+		os.Remove(name)
+		return "", ErrNotFound
+	}
+	return text, nil
+}
+
+// Set writes text atomically to the file for key and removes expired and,
+// beyond the size limit, the oldest results
+func (d *DiskCache) Set(_ context.Context, key, text string, ttl time.Duration) error {
+	var expires int64
+	if ttl > 0 {
+		expires = time.Now().Add(ttl).UnixNano()
+	}
+	f, err := os.CreateTemp(d.dir, tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	written, err := fmt.Fprintf(f, "%d\n%s", expires, text)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	var replaced int64
+	if info, statErr := os.Stat(d.path(key)); statErr == nil {
+		replaced = info.Size()
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	d.grow(int64(written) - replaced)
+	return nil
+}
+
+// grow adds delta to the tracked size and evicts results if the size
+// exceeds maxBytes, if the results may have expired since the last scan, or
+// if the directory was never scanned
+func (d *DiskCache) grow(delta int64) {
+	if d.ttl <= 0 && d.maxBytes <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.size += delta
+	if d.swept.IsZero() || (d.maxBytes > 0 && d.size > d.maxBytes) || d.tooOld(d.swept) {
+		d.evictLocked()
+	}
+}
+
+// diskEntry is a result file of a DiskCache
+type diskEntry struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+// entries returns the result files of the cache
+func (d *DiskCache) entries() []diskEntry {
+	dirEntries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil
+	}
+	var entries []diskEntry
+	for _, e := range dirEntries {
+		if !e.Type().IsRegular() || !strings.HasSuffix(e.Name(), diskCacheExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, diskEntry{name: filepath.Join(d.dir, e.Name()), size: info.Size(), modTime: info.ModTime()})
+	}
+	return entries
+}
+
+// evict removes the expired results, then the oldest results until the
+// results fit in maxBytes
+func (d *DiskCache) evict() {
+	if d.ttl <= 0 && d.maxBytes <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.evictLocked()
+}
+
+// evictLocked scans the directory for evict and resets the tracked size to
+// the size of the remaining results. d.mu must be held.
+func (d *DiskCache) evictLocked() {
+	entries := d.entries()
+	slices.SortFunc(entries, func(a, b diskEntry) int {
+		return cmp.Compare(b.modTime.UnixNano(), a.modTime.UnixNano())
+	})
+	var total, kept int64
+	for _, e := range entries {
+		if d.tooOld(e.modTime) {
+			os.Remove(e.name)
+			continue
+		}
+		total += e.size
+		if d.maxBytes > 0 && total > d.maxBytes {
+			os.Remove(e.name)
+			continue
+		}
+		kept += e.size
+	}
+	d.size = kept
+	d.swept = time.Now()
+}
+
+// Stats returns the hit and miss counts and the number of cached results
+func (d *DiskCache) Stats() CacheStats {
+	return CacheStats{Hits: d.hits.Load(), Misses: d.misses.Load(), Entries: len(d.entries())}
+}
@@ -0,0 +1,119 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache, err := NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	ctx := context.Background()
+	if err := cache.Set(ctx, "a", "one", 0); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	if text, err := cache.Get(ctx, "a"); err != nil || text != "one" {
+		t.Errorf("expected one, got %q, %v", text, err)
+	}
+
+	// a new cache on the same directory finds the result
+	reopened, err := NewDiskCache(dir, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("failed to reopen cache: %v", err)
+	}
+	if text, err := reopened.Get(ctx, "a"); err != nil || text != "one" {
+		t.Errorf("expected one after reopening, got %q, %v", text, err)
+	}
+
+	// results older than the time to live expire
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(cache.path("a"), old, old); err != nil {
+		t.Fatalf("failed to age result: %v", err)
+	}
+	if _, err := cache.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for the expired result, got %v", err)
+	}
+	if _, err := os.Stat(cache.path("a")); !os.IsNotExist(err) {
+		t.Errorf("expected the expired result to be removed, got %v", err)
+	}
+
+	// results also expire after the TTL they were cached with
+	if err := cache.Set(ctx, "b", "two", time.Nanosecond); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := cache.Get(ctx, "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for the expired result, got %v", err)
+	}
+
+	expected := CacheStats{Hits: 1, Misses: 2, Entries: 0}
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestDiskCache_MaxBytes(t *testing.T) {
+	// each file holds a 2 byte header and 4 bytes of text
+	cache, err := NewDiskCache(t.TempDir(), 0, 12)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	for i, key := range []string{"a", "b", "c"} {
+		if err := cache.Set(context.Background(), key, strings.Repeat(key, 4), 0); err != nil {
+			t.Fatalf("failed to set: %v", err)
+		}
+		// order the writes even on file systems with coarse timestamps
+		at := time.Now().Add(time.Duration(i-3) * time.Minute)
+		if err := os.Chtimes(cache.path(key), at, at); err != nil {
+			t.Fatalf("failed to set time: %v", err)
+		}
+	}
+	cache.evict()
+
+	if _, err := cache.Get(context.Background(), "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the oldest result to be evicted, got %v", err)
+	}
+	for _, key := range []string{"b", "c"} {
+		if text, err := cache.Get(context.Background(), key); err != nil || text != strings.Repeat(key, 4) {
+			t.Errorf("expected %s to be cached, got %q, %v", key, text, err)
+		}
+	}
+}
+
+func TestDiskCache_TracksSize(t *testing.T) {
+	// each file holds a 2 byte header and 4 bytes of text
+	cache, err := NewDiskCache(t.TempDir(), 0, 12)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	ctx := context.Background()
+	if err := cache.Set(ctx, "a", "aaaa", 0); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	swept := cache.swept
+	if swept.IsZero() {
+		t.Fatal("expected the first write to scan the directory")
+	}
+	for _, key := range []string{"a", "b"} {
+		if err := cache.Set(ctx, key, strings.Repeat(key, 4), 0); err != nil {
+			t.Fatalf("failed to set: %v", err)
+		}
+	}
+	if cache.swept != swept || cache.size != 12 {
+		t.Errorf("expected writes within the limit not to scan, got size %d", cache.size)
+	}
+	if err := cache.Set(ctx, "c", "cccc", 0); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	if cache.swept == swept || cache.size > 12 {
+		t.Errorf("expected a write beyond the limit to evict, got size %d", cache.size)
+	}
+}
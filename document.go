@@ -0,0 +1,157 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"strings"
+)
+
+// Page is the extracted text of a single page
+type Page struct {
+	// Number is the page number in the PDF file
+	Number int `json:"number"`
+	// Label is the page label defined by the PDF, e.g. "iv" or "A-1", when
+	// requested with Options.PageLabels
+	Label string `json:"label,omitempty"`
+	// Text is the extracted text of the page
+	Text string `json:"text"`
+	// Language is the language of the page as a BCP 47 tag, when detected
+	// with Options.LanguageDetector
+	Language string `json:"language,omitempty"`
+	// Source is where the text came from, SourceTextLayer, SourceOCR or the
+	// Source of a GarbledStrategy, when Options.OCR or Options.MinQuality is
+	// set
+	Source PageSource `json:"source,omitempty"`
+}
+
+// Document is the extracted text of a PDF file, split into pages
+type Document struct {
+	// Path is the path of the PDF file
+	Path string `json:"path"`
+	// Metadata holds document metadata such as the title and author, when
+	// known. ConvertDocument leaves it empty, callers can set it to the map
+	// returned by Converter.Metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Language is the language of most of the text as a BCP 47 tag, when
+	// detected with Options.LanguageDetector
+	Language string `json:"language,omitempty"`
+	// Pages are the converted pages in order
+	Pages []Page `json:"pages"`
+	// Entities are the named entities of the pages in order, when extracted
+	// with Options.EntityExtractor
+	Entities []Entity `json:"entities,omitempty"`
+	// Warnings are the messages pdftotext printed while converting
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// Text returns the text of all pages separated by form feeds, like Convert
+func (d *Document) Text() string {
+	texts := make([]string, len(d.Pages))
+	for i, page := range d.Pages {
+		texts[i] = page.Text
+	}
+	return strings.TrimSpace(strings.Join(texts, "\f"))
+}
+
+// ConvertPages converts a PDF file to text and returns the text of each page.
+// Page breaks are always inserted, since they are used to split the pages.
+func (c *Converter) ConvertPages(ctx context.Context, inputPath string, opts *Options) ([]Page, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return nil, err
+	}
+	if opts != nil && opts.StopAfter != nil {
+		var pages []Page
+		for page, err := range c.StreamPages(ctx, inputPath, opts) {
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, page)
+		}
+		return pages, nil
+	}
+
+	pageOpts := Options{}
+	if opts != nil {
+		pageOpts = *opts
+	}
+	pageOpts.NoPageBreaks = false
+	pageOpts.PageSeparator = ""
+	pageOpts.Transformers = nil
+	pageOpts.Normalize = ""
+	pageOpts.PostHooks = nil
+	pageOpts.OCR = nil
+	pageOpts.MinQuality = 0
+	pageOpts.GarbledStrategies = nil
+
+	hookOpts, err := runPreHooks(ctx, inputPath, &pageOpts)
+	if err != nil {
+		return nil, err
+	}
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, hookOpts)
+	if err != nil {
+		return nil, err
+	}
+	var pages []Page
+	for _, o := range rangeOpts {
+		var stdout bytes.Buffer
+		if err := c.run(ctx, inputPath, "-", o, &stdout); err != nil {
+			return nil, err
+		}
+		pages = append(pages, splitPages(stdout.String(), o.FirstPage)...)
+	}
+	if opts != nil {
+		if err := recoverPages(ctx, opts, inputPath, pages); err != nil {
+			return nil, err
+		}
+		if err := transformPages(transformers(opts), pages); err != nil {
+			return nil, err
+		}
+		detectPageLanguages(opts.LanguageDetector, pages)
+	}
+
+	if pageOpts.PageLabels {
+		labels, err := ReadPageLabels(inputPath)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pages {
+			pages[i].Label = labels.Label(pages[i].Number)
+		}
+	}
+	return pages, nil
+}
+
+// ConvertDocument converts a PDF file to text and returns it as a Document
+func (c *Converter) ConvertDocument(ctx context.Context, inputPath string, opts *Options) (*Document, error) {
+	collecting, warnings := collectWarnings(opts)
+	pages, err := c.ConvertPages(ctx, inputPath, collecting)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{Path: inputPath, Language: documentLanguage(pages), Pages: pages, Warnings: warnings()}
+	if opts != nil && opts.EntityExtractor != nil {
+		if err := ExtractEntities(ctx, opts.EntityExtractor, doc); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// splitPages splits pdftotext output at the form feeds ending each page
+func splitPages(output string, firstPage int) []Page {
+	if firstPage < 1 {
+		firstPage = 1
+	}
+	texts := strings.Split(output, "\f")
+	if last := len(texts) - 1; strings.TrimSpace(texts[last]) == "" {
+		texts = texts[:last]
+	}
+
+	pages := make([]Page, len(texts))
+	for i, text := range texts {
+		pages[i] = Page{Number: firstPage + i, Text: text}
+	}
+	return pages
+}
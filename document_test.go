@@ -0,0 +1,73 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestConverter_ConvertPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		options       *Options
+		expectedPages []Page
+	}{
+		{
+			name:   "Multiple pages",
+			output: "first page\n\fsecond page\n\f",
+			expectedPages: []Page{
+				{Number: 1, Text: "first page\n"},
+				{Number: 2, Text: "second page\n"},
+			},
+		},
+		{
+			name:    "Page range",
+			output:  "third page\n\f\ffifth page\n\f",
+			options: &Options{FirstPage: 3, LastPage: 5, NoPageBreaks: true},
+			expectedPages: []Page{
+				{Number: 3, Text: "third page\n"},
+				{Number: 4, Text: ""},
+				{Number: 5, Text: "fifth page\n"},
+			},
+		},
+		{
+			name:          "Empty output",
+			output:        "",
+			expectedPages: []Page{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, a []string, stdout, stderr io.Writer) error {
+				args = a
+				_, err := io.WriteString(stdout, tt.output)
+				return err
+			})))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			pages, err := converter.ConvertPages(context.Background(), "input.pdf", tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if slices.Contains(args, "-nopgbrk") {
+				t.Errorf("expected page breaks to be kept, got args %v", args)
+			}
+			if !slices.Equal(pages, tt.expectedPages) {
+				t.Errorf("expected pages %q, got %q", tt.expectedPages, pages)
+			}
+		})
+	}
+}
+
+func TestDocument_Text(t *testing.T) {
+	doc := &Document{Pages: []Page{{Number: 1, Text: "first\n"}, {Number: 2, Text: "second\n"}}}
+	if got, expected := doc.Text(), "first\n\fsecond"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
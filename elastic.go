@@ -0,0 +1,265 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ElasticMapping is the index mapping created by ElasticIndexer.CreateIndex.
+// The text is stored with term vectors so highlighting can use the fast
+// vector highlighter on long pages.
+const ElasticMapping = `{
+  "mappings": {
+    "properties": {
+      "path": {"type": "keyword"},
+      "page": {"type": "integer"},
+      "text": {"type": "text", "term_vector": "with_positions_offsets"},
+      "metadata": {"type": "object", "dynamic": true},
+      "indexed_at": {"type": "date"}
+    }
+  }
+}`
+
+// PageDocument is the search document indexed for a page
+type PageDocument struct {
+	// Path is the path of the PDF file
+	Path string `json:"path"`
+	// Page is the page number
+	Page int `json:"page"`
+	// Text is the text of the page
+	Text string `json:"text"`
+	// Metadata holds document metadata such as the title and author
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// IndexedAt is when the page was added to the indexer
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+// ID returns the document id, unique per page of a file so reindexing a file
+// replaces its pages
+func (d PageDocument) ID() string {
+	return fmt.Sprintf("%s#%d", d.Path, d.Page)
+}
+
+// ElasticIndexer bulk-indexes pages into Elasticsearch or OpenSearch. Pages
+// are buffered and sent in batches with the _bulk API; Add blocks while a
+// full batch is being sent, so producers are slowed down to the speed of the
+// cluster. Requests and documents rejected with 429 Too Many Requests or a
+// server error are retried with exponential backoff.
+type ElasticIndexer struct {
+	// URL is the base URL of the cluster, e.g. "http://localhost:9200"
+	URL string
+	// Index is the index the pages are written to
+	Index string
+	// Username and Password enable basic authentication
+	Username string
+	Password string
+	// APIKey enables API key authentication, taking precedence over basic
+	// authentication
+	APIKey string
+	// BatchSize is the number of pages per bulk request (default 500)
+	BatchSize int
+	// Retries is the number of times a rejected batch is retried (default 3)
+	Retries int
+	// Backoff is the wait before the first retry, doubled after each one
+	// (default 500ms)
+	Backoff time.Duration
+	// Client is the HTTP client used for requests (default http.DefaultClient)
+	Client *http.Client
+
+	mu      sync.Mutex
+	pending []PageDocument
+}
+
+// CreateIndex creates the index with ElasticMapping unless it already exists
+func (ix *ElasticIndexer) CreateIndex(ctx context.Context) error {
+	resp, err := ix.do(ctx, http.MethodPut, "/"+ix.Index, "application/json", []byte(ElasticMapping))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusBadRequest && strings.Contains(string(body), "resource_already_exists_exception") {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to create index %s: %s: %s", ix.Index, resp.Status, body)
+	}
+	return nil
+}
+
+// IndexDocument adds every page of doc
+func (ix *ElasticIndexer) IndexDocument(ctx context.Context, doc *Document) error {
+	for _, page := range doc.Pages {
+		err := ix.Add(ctx, PageDocument{Path: doc.Path, Page: page.Number, Text: page.Text, Metadata: doc.Metadata})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add buffers a page, sending the batch once it is full
+func (ix *ElasticIndexer) Add(ctx context.Context, doc PageDocument) error {
+	if doc.IndexedAt.IsZero() {
+		doc.IndexedAt = time.Now().UTC()
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.pending = append(ix.pending, doc)
+	if len(ix.pending) < ix.batchSize() {
+		return nil
+	}
+	return ix.flush(ctx)
+}
+
+// Flush sends the buffered pages. The pages of a batch that cannot be
+// indexed are dropped and reported in the error.
+func (ix *ElasticIndexer) Flush(ctx context.Context) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.flush(ctx)
+}
+
+func (ix *ElasticIndexer) flush(ctx context.Context) error {
+	docs := ix.pending
+	ix.pending = nil
+
+	backoff := ix.Backoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	retries := ix.Retries
+	if retries == 0 {
+		retries = 3
+	}
+
+	for attempt := 0; len(docs) > 0; attempt++ {
+		retry, err := ix.bulk(ctx, docs)
+		if err != nil && !errors.Is(err, errRetryable) {
+			return err
+		}
+		if len(retry) == 0 && err == nil {
+			return nil
+		}
+		if attempt >= retries {
+			return fmt.Errorf("%w: %d pages still rejected after %d retries", ErrRejected, len(retry), retries)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		docs = retry
+	}
+	return nil
+}
+
+// errRetryable marks a bulk request that was rejected as a whole and may
+// succeed when retried
+var errRetryable = errors.New("retryable bulk failure")
+
+// bulkResponse is the part of a _bulk response needed to find failed items
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// bulk sends docs in one _bulk request and returns the documents to retry
+func (ix *ElasticIndexer) bulk(ctx context.Context, docs []PageDocument) ([]PageDocument, error) {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, doc := range docs {
+		action := map[string]any{"index": map[string]string{"_index": ix.Index, "_id": doc.ID()}}
+		if err := enc.Encode(action); err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := ix.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body.Bytes())
+	if err != nil {
+		return docs, fmt.Errorf("%w: %w", errRetryable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return docs, fmt.Errorf("%w: %w", errRetryable, err)
+	}
+	if isRetryableStatus(resp.StatusCode) {
+		return docs, errRetryable
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: %s: %s", ErrRejected, resp.Status, data)
+	}
+
+	var result bulkResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil, nil
+	}
+
+	var retry []PageDocument
+	for i, item := range result.Items[:min(len(result.Items), len(docs))] {
+		for _, r := range item {
+			switch {
+			case r.Status < 300:
+			case isRetryableStatus(r.Status):
+				retry = append(retry, docs[i])
+			default:
+				return nil, fmt.Errorf("%w: %s: %s", ErrRejected, docs[i].ID(), r.Error)
+			}
+		}
+	}
+	return retry, nil
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+func (ix *ElasticIndexer) batchSize() int {
+	if ix.BatchSize > 0 {
+		return ix.BatchSize
+	}
+	return 500
+}
+
+func (ix *ElasticIndexer) do(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(ix.URL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	switch {
+	case ix.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+ix.APIKey)
+	case ix.Username != "":
+		req.SetBasicAuth(ix.Username, ix.Password)
+	}
+
+	client := ix.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,193 @@
+package pdftotext
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCluster answers _bulk requests, rejecting documents according to reject
+type fakeCluster struct {
+	mu       sync.Mutex
+	requests int
+	indexed  map[string]PageDocument
+	// reject returns the status for a document in the given request, or 0 to
+	// reject the whole request with 429
+	reject func(request int, id string) int
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	if r.URL.Path == "/pages" {
+		if _, ok := f.indexed[""]; ok {
+			http.Error(w, `{"error":{"type":"resource_already_exists_exception"}}`, http.StatusBadRequest)
+			return
+		}
+		f.indexed[""] = PageDocument{}
+		return
+	}
+	if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" || r.Header.Get("Authorization") != "ApiKey secret" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+
+	var items []map[string]any
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		var action struct {
+			Index struct {
+				Index string `json:"_index"`
+				ID    string `json:"_id"`
+			} `json:"index"`
+		}
+		var doc PageDocument
+		json.Unmarshal(scanner.Bytes(), &action)
+		scanner.Scan()
+		json.Unmarshal(scanner.Bytes(), &doc)
+
+		status := http.StatusCreated
+		if f.reject != nil {
+			status = f.reject(f.requests, action.Index.ID)
+		}
+		if status == 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if status < 300 {
+			f.indexed[action.Index.ID] = doc
+		}
+		items = append(items, map[string]any{"index": map[string]any{"status": status, "error": map[string]string{"type": "test"}}})
+	}
+	json.NewEncoder(w).Encode(map[string]any{"errors": true, "items": items})
+}
+
+func TestElasticIndexer(t *testing.T) {
+	doc := &Document{
+		Path:     "report.pdf",
+		Metadata: map[string]string{"title": "Report"},
+		Pages:    []Page{{Number: 1, Text: "one"}, {Number: 2, Text: "two"}, {Number: 3, Text: "three"}},
+	}
+
+	tests := []struct {
+		name             string
+		reject           func(request int, id string) int
+		expectedRequests int
+		expectedIndexed  int
+		expectedError    error
+	}{
+		{
+			name:             "Batches",
+			expectedRequests: 2,
+			expectedIndexed:  3,
+		},
+		{
+			name: "Request rejected then accepted",
+			reject: func(request int, id string) int {
+				if request == 1 {
+					return 0
+				}
+				return http.StatusCreated
+			},
+			expectedRequests: 3,
+			expectedIndexed:  3,
+		},
+		{
+			name: "Page retried",
+			reject: func(request int, id string) int {
+				if request == 1 && id == "report.pdf#2" {
+					return http.StatusTooManyRequests
+				}
+				return http.StatusCreated
+			},
+			expectedRequests: 3,
+			expectedIndexed:  3,
+		},
+		{
+			name: "Page rejected",
+			reject: func(request int, id string) int {
+				if id == "report.pdf#3" {
+					return http.StatusBadRequest
+				}
+				return http.StatusCreated
+			},
+			expectedRequests: 2,
+			expectedIndexed:  2,
+			expectedError:    ErrRejected,
+		},
+		{
+			name:             "Retries exhausted",
+			reject:           func(request int, id string) int { return http.StatusServiceUnavailable },
+			expectedRequests: 3,
+			expectedIndexed:  0,
+			expectedError:    ErrRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &fakeCluster{indexed: map[string]PageDocument{}, reject: tt.reject}
+			server := httptest.NewServer(cluster)
+			defer server.Close()
+
+			indexer := &ElasticIndexer{URL: server.URL + "/", Index: "pages", APIKey: "secret", BatchSize: 2, Retries: 2, Backoff: time.Millisecond}
+			err := indexer.IndexDocument(context.Background(), doc)
+			if err == nil {
+				err = indexer.Flush(context.Background())
+			}
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if cluster.requests != tt.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tt.expectedRequests, cluster.requests)
+			}
+			if len(cluster.indexed) != tt.expectedIndexed {
+				t.Errorf("expected %d indexed pages, got %d", tt.expectedIndexed, len(cluster.indexed))
+			}
+			if page, ok := cluster.indexed["report.pdf#1"]; ok && (page.Text != "one" || page.Metadata["title"] != "Report" || page.IndexedAt.IsZero()) {
+				t.Errorf("unexpected page %+v", page)
+			}
+		})
+	}
+}
+
+func TestElasticIndexer_CreateIndex(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if user, pass, _ := r.BasicAuth(); r.Method != http.MethodPut || user != "elastic" || pass != "changeme" {
+			http.Error(w, "unexpected request", http.StatusUnauthorized)
+			return
+		}
+		if len(bodies) > 1 {
+			http.Error(w, `{"error":{"type":"resource_already_exists_exception"}}`, http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	indexer := &ElasticIndexer{URL: server.URL, Index: "pages", Username: "elastic", Password: "changeme"}
+	for i := range 2 {
+		if err := indexer.CreateIndex(context.Background()); err != nil {
+			t.Fatalf("attempt %d: unexpected error: %v", i, err)
+		}
+	}
+	if !strings.Contains(bodies[0], `"term_vector": "with_positions_offsets"`) {
+		t.Errorf("expected the mapping to be sent, got %s", bodies[0])
+	}
+
+	indexer.Password = "wrong"
+	if err := indexer.CreateIndex(context.Background()); err == nil {
+		t.Errorf("expected an error for status %d", http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,75 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Entity is a named entity, such as a person, organization or place, found
+// in the text of a page
+type Entity struct {
+	// Type is the type of the entity as named by the EntityExtractor, e.g.
+	// "PERSON" or "ORG"
+	Type string `json:"type"`
+	// Text is the text of the entity
+	Text string `json:"text"`
+	// Page is the page number
+	Page int `json:"page"`
+	// Start and End are the byte offsets of the entity in the page text
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Score is the confidence of the EntityExtractor, if it reports one
+	Score float64 `json:"score,omitempty"`
+}
+
+// EntityExtractor finds the named entities in text, e.g. with a local model
+// or a remote NER service
+type EntityExtractor interface {
+	// ExtractEntities returns the entities of text with their Type, Start and
+	// End set. Text and Page are filled in by the caller.
+	ExtractEntities(ctx context.Context, text string) ([]Entity, error)
+}
+
+// EntityExtractorFunc adapts a function to an EntityExtractor
+type EntityExtractorFunc func(ctx context.Context, text string) ([]Entity, error)
+
+// ExtractEntities calls f(ctx, text)
+func (f EntityExtractorFunc) ExtractEntities(ctx context.Context, text string) ([]Entity, error) {
+	return f(ctx, text)
+}
+
+// ExtractEntities sets the Entities of doc using extractor, for documents
+// converted without Options.EntityExtractor
+func ExtractEntities(ctx context.Context, extractor EntityExtractor, doc *Document) error {
+	doc.Entities = nil
+	for _, page := range doc.Pages {
+		entities, err := pageEntities(ctx, extractor, page)
+		if err != nil {
+			return err
+		}
+		doc.Entities = append(doc.Entities, entities...)
+	}
+	return nil
+}
+
+// pageEntities returns the entities of page found by extractor, or nil if
+// extractor is nil or the page is blank
+func pageEntities(ctx context.Context, extractor EntityExtractor, page Page) ([]Entity, error) {
+	if extractor == nil || strings.TrimSpace(page.Text) == "" {
+		return nil, nil
+	}
+	entities, err := extractor.ExtractEntities(ctx, page.Text)
+	if err != nil {
+		return nil, fmt.Errorf("extracting entities of page %d: %w", page.Number, err)
+	}
+	for i := range entities {
+		e := &entities[i]
+		if e.Start < 0 || e.End < e.Start || e.End > len(page.Text) {
+			return nil, fmt.Errorf("extracting entities of page %d: entity %q at invalid offsets %d-%d", page.Number, e.Type, e.Start, e.End)
+		}
+		e.Page = page.Number
+		e.Text = page.Text[e.Start:e.End]
+	}
+	return entities, nil
+}
@@ -0,0 +1,96 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+)
+
+// capitalizedExtractor finds capitalized words as entities of type "NAME"
+var capitalizedExtractor = EntityExtractorFunc(func(ctx context.Context, text string) ([]Entity, error) {
+	var entities []Entity
+	for _, loc := range regexp.MustCompile(`\p{Lu}\p{Ll}+`).FindAllStringIndex(text, -1) {
+		entities = append(entities, Entity{Type: "NAME", Start: loc[0], End: loc[1], Score: 0.5})
+	}
+	return entities, nil
+})
+
+func TestConverter_EntityExtractor(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "Ada met Émile\f  \fin Zürich\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("ConvertDocument", func(t *testing.T) {
+		doc, err := converter.ConvertDocument(ctx, "input.pdf", &Options{EntityExtractor: capitalizedExtractor})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []Entity{
+			{Type: "NAME", Text: "Ada", Page: 1, Start: 0, End: 3, Score: 0.5},
+			{Type: "NAME", Text: "Émile", Page: 1, Start: 8, End: 14, Score: 0.5},
+			{Type: "NAME", Text: "Zürich", Page: 3, Start: 3, End: 10, Score: 0.5},
+		}
+		if len(doc.Entities) != len(expected) {
+			t.Fatalf("expected %d entities, got %+v", len(expected), doc.Entities)
+		}
+		for i, e := range doc.Entities {
+			if e != expected[i] {
+				t.Errorf("expected %+v, got %+v", expected[i], e)
+			}
+		}
+	})
+
+	t.Run("ConvertJSONL", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := converter.ConvertJSONL(ctx, "input.pdf", &Options{EntityExtractor: capitalizedExtractor}, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var record JSONLRecord
+		if err := json.NewDecoder(&buf).Decode(&record); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(record.Entities) != 2 || record.Entities[1].Text != "Émile" {
+			t.Errorf("unexpected entities %+v", record.Entities)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		extractor EntityExtractor
+		expected  error
+	}{
+		{
+			name: "Extractor error",
+			extractor: EntityExtractorFunc(func(ctx context.Context, text string) ([]Entity, error) {
+				return nil, context.DeadlineExceeded
+			}),
+			expected: context.DeadlineExceeded,
+		},
+		{
+			name: "Invalid offsets",
+			extractor: EntityExtractorFunc(func(ctx context.Context, text string) ([]Entity, error) {
+				return []Entity{{Type: "NAME", Start: 2, End: len(text) + 1}}, nil
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := converter.ConvertDocument(ctx, "input.pdf", &Options{EntityExtractor: tt.extractor})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package pdftotext
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Event is reported while converting a PDF file with ConvertWithEvents or
+// Events. It is one of ConversionStarted, PageExtracted, WarningRaised,
+// Completed or Failed.
+type Event interface {
+	event()
+}
+
+// ConversionStarted is the first event of a conversion
+type ConversionStarted struct {
+	// Input is the path of the PDF file
+	Input string
+	// TotalPages is the number of pages that will be converted, or 0 if unknown
+	TotalPages int
+	// Time is when the conversion started
+	Time time.Time
+}
+
+// PageExtracted is reported as soon as the text of a page is available
+type PageExtracted struct {
+	// Input is the path of the PDF file
+	Input string
+	// Page is the extracted page
+	Page Page
+}
+
+// WarningRaised is reported for each message pdftotext prints while converting
+type WarningRaised struct {
+	// Input is the path of the PDF file
+	Input string
+	// Message is the message, e.g. "Syntax Warning: Invalid Font Weight"
+	Message string
+}
+
+// Completed is the last event of a successful conversion
+type Completed struct {
+	// Input is the path of the PDF file
+	Input string
+	// Pages is the number of pages extracted
+	Pages int
+	// Duration is how long the conversion took
+	Duration time.Duration
+}
+
+// Failed is the last event of a failed conversion
+type Failed struct {
+	// Input is the path of the PDF file
+	Input string
+	// Err is the conversion error
+	Err error
+	// Duration is how long the conversion ran before failing
+	Duration time.Duration
+}
+
+func (ConversionStarted) event() {}
+func (PageExtracted) event()     {}
+func (WarningRaised) event()     {}
+func (Completed) event()         {}
+func (Failed) event()            {}
+
+// ConvertWithEvents converts a PDF file page by page and reports its progress
+// to handle, so applications can update their UI while a large document is
+// being converted. handle receives a ConversionStarted event, a PageExtracted
+// event per page interleaved with WarningRaised events, and finally Completed
+// or Failed. It is never called concurrently.
+func (c *Converter) ConvertWithEvents(ctx context.Context, inputPath string, opts *Options, handle func(Event)) (*Document, error) {
+	var mu sync.Mutex
+	emit := func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		handle(e)
+	}
+
+	collecting, warnings := collectWarnings(opts)
+	eventOpts := *collecting
+	onWarning := eventOpts.OnWarning
+	eventOpts.OnWarning = func(message string) {
+		if onWarning != nil {
+			onWarning(message)
+		}
+		emit(WarningRaised{Input: inputPath, Message: message})
+	}
+
+	start := time.Now()
+	emit(ConversionStarted{Input: inputPath, TotalPages: c.totalPages(ctx, inputPath, &eventOpts), Time: start})
+
+	doc := &Document{Path: inputPath}
+	for page, err := range c.StreamPages(ctx, inputPath, &eventOpts) {
+		if err != nil {
+			emit(Failed{Input: inputPath, Err: err, Duration: time.Since(start)})
+			return nil, err
+		}
+		doc.Pages = append(doc.Pages, page)
+		emit(PageExtracted{Input: inputPath, Page: page})
+	}
+	doc.Language = documentLanguage(doc.Pages)
+	doc.Warnings = warnings()
+	emit(Completed{Input: inputPath, Pages: len(doc.Pages), Duration: time.Since(start)})
+	return doc, nil
+}
+
+// Events converts a PDF file in the background and returns a channel of the
+// events described in ConvertWithEvents. The channel is closed after the
+// Completed or Failed event. Callers must receive until the channel is closed
+// or cancel ctx, which stops the conversion and closes the channel.
+func (c *Converter) Events(ctx context.Context, inputPath string, opts *Options) <-chan Event {
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		c.ConvertWithEvents(ctx, inputPath, opts, func(e Event) {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return ch
+}
+
+// totalPages returns the number of pages opts selects, looking up the page
+// count with pdfinfo when a range is open-ended. It returns 0 if unknown.
+func (c *Converter) totalPages(ctx context.Context, inputPath string, opts *Options) int {
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, opts)
+	if err != nil {
+		return 0
+	}
+
+	total, count := 0, 0
+	for _, o := range rangeOpts {
+		first, last := max(o.FirstPage, 1), o.LastPage
+		if last == 0 {
+			if count == 0 {
+				if count, err = c.PageCount(ctx, inputPath, opts); err != nil {
+					return 0
+				}
+			}
+			last = count
+		}
+		total += max(last-first+1, 0)
+	}
+	return total
+}
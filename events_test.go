@@ -0,0 +1,131 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func eventsRunner(code int) Runner {
+	return runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if name == "pdfinfo" {
+			_, err := io.WriteString(stdout, "Pages:           2\n")
+			return err
+		}
+		io.WriteString(stderr, "Syntax Warning: Invalid Font Weight\nSyntax Error (42): ")
+		if code != 0 {
+			io.WriteString(stderr, "Couldn't read xref table\n")
+			return &ExitError{Code: code}
+		}
+		io.WriteString(stderr, "Illegal character\n")
+		_, err := io.WriteString(stdout, "one\ftwo\f")
+		return err
+	})
+}
+
+// eventKinds describes events without their times and durations
+func eventKinds(events []Event) []string {
+	kinds := make([]string, len(events))
+	for i, e := range events {
+		switch e := e.(type) {
+		case ConversionStarted:
+			kinds[i] = fmt.Sprintf("started %d", e.TotalPages)
+		case PageExtracted:
+			kinds[i] = fmt.Sprintf("page %d %s", e.Page.Number, e.Page.Text)
+		case WarningRaised:
+			kinds[i] = "warning " + e.Message
+		case Completed:
+			kinds[i] = fmt.Sprintf("completed %d", e.Pages)
+		case Failed:
+			kinds[i] = "failed"
+		}
+	}
+	return kinds
+}
+
+func TestConverter_ConvertWithEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          int
+		expected      []string
+		expectedError error
+	}{
+		{
+			name: "Success",
+			expected: []string{
+				"started 2",
+				"warning Syntax Warning: Invalid Font Weight",
+				"warning Syntax Error (42): Illegal character",
+				"page 1 one",
+				"page 2 two",
+				"completed 2",
+			},
+		},
+		{
+			name: "Failure",
+			code: 1,
+			expected: []string{
+				"started 2",
+				"warning Syntax Warning: Invalid Font Weight",
+				"warning Syntax Error (42): Couldn't read xref table",
+				"failed",
+			},
+			expectedError: ErrPDFOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(eventsRunner(tt.code)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			var events []Event
+			doc, err := converter.ConvertWithEvents(context.Background(), "input.pdf", nil, func(e Event) {
+				events = append(events, e)
+			})
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if got := eventKinds(events); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected events %q, got %q", tt.expected, got)
+			}
+			if tt.expectedError != nil {
+				if failed, ok := events[len(events)-1].(Failed); !ok || !errors.Is(failed.Err, tt.expectedError) {
+					t.Errorf("expected Failed event with %v, got %#v", tt.expectedError, events[len(events)-1])
+				}
+				return
+			}
+			if len(doc.Pages) != 2 {
+				t.Errorf("expected 2 pages, got %d", len(doc.Pages))
+			}
+		})
+	}
+}
+
+func TestConverter_Events(t *testing.T) {
+	converter, err := New(WithRunner(eventsRunner(0)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	var events []Event
+	for e := range converter.Events(context.Background(), "input.pdf", &Options{FirstPage: 2, LastPage: 3}) {
+		events = append(events, e)
+	}
+	expected := []string{
+		"started 2",
+		"warning Syntax Warning: Invalid Font Weight",
+		"warning Syntax Error (42): Illegal character",
+		"page 2 one",
+		"page 3 two",
+		"completed 2",
+	}
+	if got := eventKinds(events); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected events %q, got %q", expected, got)
+	}
+}
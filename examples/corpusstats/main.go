@@ -0,0 +1,100 @@
+// Command corpusstats summarizes the conversion sidecars written by
+// ConvertToFileWithSidecar below a directory, reporting page totals, failure
+// rates, the language mix and the quality score distribution of an
+// ingestion run:
+//
+//	corpusstats -dir /data/extracted
+//	corpusstats -dir /data/extracted -json > report.json
+//
+// With -min-group, it prints redacted statistics as JSON instead, leaving out
+// file paths and every group of fewer documents, so the report can be shared
+// outside the team holding the data:
+//
+//	corpusstats -dir /data/extracted -min-group 10 > shared.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory containing conversion sidecars")
+	asJSON := flag.Bool("json", false, "print the statistics as JSON")
+	minGroup := flag.Int("min-group", 0, "print redacted statistics as JSON, suppressing groups smaller than this")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	stats, err := pdftotext.AggregateSidecars(ctx, *dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *asJSON || *minGroup > 0 {
+		var v any = stats
+		if *minGroup > 0 {
+			v = stats.Redacted(*minGroup)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	report(os.Stdout, stats)
+}
+
+// report prints stats as a plain text report
+func report(w io.Writer, stats *pdftotext.CorpusStats) {
+	fmt.Fprintf(w, "Documents:    %d\n", stats.Documents)
+	fmt.Fprintf(w, "Pages:        %d\n", stats.Pages)
+	fmt.Fprintf(w, "Failed:       %d (%.1f%%)\n", stats.Failed, 100*stats.FailureRate)
+	fmt.Fprintf(w, "Duration:     %s\n", stats.Duration)
+	if len(stats.Invalid) > 0 {
+		fmt.Fprintf(w, "Invalid:      %d sidecars\n", len(stats.Invalid))
+	}
+
+	counts(w, "Failures", stats.Failures)
+	counts(w, "Languages", stats.Languages)
+
+	q := stats.Quality
+	if q.Scored == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nQuality (%d scored): min %.2f, mean %.2f, max %.2f\n", q.Scored, q.Min, q.Mean, q.Max)
+	for i, n := range q.Histogram {
+		bar := strings.Repeat("#", (n*40+q.Scored-1)/q.Scored)
+		fmt.Fprintf(w, "  %.1f-%.1f %6d %s\n", float64(i)/10, float64(i+1)/10, n, bar)
+	}
+}
+
+// counts prints m sorted by descending count
+func counts(w io.Writer, title string, m map[string]int) {
+	if len(m) == 0 {
+		return
+	}
+	keys := slices.SortedFunc(maps.Keys(m), func(a, b string) int {
+		if m[a] != m[b] {
+			return m[b] - m[a]
+		}
+		return strings.Compare(a, b)
+	})
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, k := range keys {
+		fmt.Fprintf(w, "  %-14s %d\n", k, m[k])
+	}
+}
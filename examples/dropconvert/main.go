@@ -0,0 +1,118 @@
+// Command dropconvert is a drag-and-drop helper for people who don't use a
+// terminal: dropping PDF files onto its icon converts each of them to a .txt
+// file next to the PDF, using a preset from presets.json, and opens the result
+// in the default text editor.
+//
+// Presets are read from presets.json in the directory of the executable and
+// map names to conversion options:
+//
+//	{
+//	  "default": {"Layout": true},
+//	  "invoices": {"Layout": true, "FirstPage": 1, "LastPage": 1}
+//	}
+//
+// Copies of the executable can be renamed after a preset, e.g.
+// "dropconvert-invoices", to get one drop target per preset.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+func main() {
+	exe, err := os.Executable()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	configPath := flag.String("config", filepath.Join(filepath.Dir(exe), "presets.json"), "presets file")
+	preset := flag.String("preset", presetFromName(exe), "preset to convert with")
+	open := flag.Bool("open", true, "open the converted text files")
+	flag.Parse()
+
+	opts, err := loadPreset(*configPath, *preset)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	converter, err := pdftotext.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		output := strings.TrimSuffix(path, filepath.Ext(path)) + ".txt"
+		if err := converter.ConvertToFile(context.Background(), path, output, opts); err != nil {
+			log.Printf("%s: %v", path, err)
+			failed = true
+			continue
+		}
+		if *open {
+			if err := openFile(output); err != nil {
+				log.Printf("%s: %v", output, err)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// presetFromName returns the preset named by an executable called
+// "dropconvert-<preset>", or "default"
+func presetFromName(exe string) string {
+	name := strings.TrimSuffix(filepath.Base(exe), filepath.Ext(exe))
+	if _, preset, ok := strings.Cut(name, "-"); ok && preset != "" {
+		return preset
+	}
+	return "default"
+}
+
+// loadPreset reads the named preset from the presets file. A missing file
+// yields the default options.
+func loadPreset(path, name string) (*pdftotext.Options, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && name == "default" {
+		return &pdftotext.Options{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var presets map[string]*pdftotext.Options
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	opts, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: no preset named %q", path, name)
+	}
+	return opts, nil
+}
+
+// openFile opens path with the default application of the desktop
+func openFile(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,11 @@
+module github.com/joeychilson/pdftotext/examples
+
+go 1.23.2
+
+require (
+	github.com/aws/aws-lambda-go v1.49.0
+	github.com/joeychilson/pdftotext v0.0.0-00010101000000-000000000000
+	github.com/mattn/go-sqlite3 v1.14.33
+)
+
+replace github.com/joeychilson/pdftotext => ../
@@ -0,0 +1,35 @@
+// Command mcpserver serves pdftotext as Model Context Protocol tools over
+// stdio, for LLM agents and IDE assistants. Register it with an MCP client as
+// a stdio server, e.g.:
+//
+//	{"mcpServers": {"pdftotext": {"command": "mcpserver", "args": ["-root", "/home/me/papers"]}}}
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/joeychilson/pdftotext"
+	"github.com/joeychilson/pdftotext/mcp"
+)
+
+func main() {
+	root := flag.String("root", "", "directory the tools may read PDF files from (default: anywhere)")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	converter, err := pdftotext.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// stdout carries the protocol, so logs go to stderr
+	if err := mcp.NewServer(converter, *root, nil).Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+// Command popplerdiff validates a poppler upgrade before it is rolled out. It
+// converts every PDF below a directory with the installed and with the
+// upgraded pdftotext binary and reports the files whose text changed, with
+// their similarity and, with -diff, their line diffs:
+//
+//	popplerdiff -baseline /usr/bin/pdftotext -candidate /opt/poppler-24.08/bin/pdftotext -dir /data/corpus
+//	popplerdiff -baseline ... -candidate ... -dir /data/corpus -min-similarity 0.99 -diff
+//	popplerdiff -baseline ... -candidate ... -dir /data/corpus -json > report.json
+//
+// It exits with status 1 if a file is less similar than -min-similarity or
+// fails only with the candidate binary.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// result is a line of the JSON report
+type result struct {
+	pdftotext.BinaryDiff
+	BaselineError  string `json:"baseline_error,omitempty"`
+	CandidateError string `json:"candidate_error,omitempty"`
+	Regressed      bool   `json:"regressed"`
+}
+
+func main() {
+	baselinePath := flag.String("baseline", "", "path of the installed pdftotext binary")
+	candidatePath := flag.String("candidate", "", "path of the upgraded pdftotext binary")
+	dir := flag.String("dir", ".", "directory containing the PDF files to compare")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of files compared at a time")
+	minSimilarity := flag.Float64("min-similarity", 1, "similarity below which a changed file is a regression")
+	showDiff := flag.Bool("diff", false, "print the line diffs of the changed files")
+	asJSON := flag.Bool("json", false, "print a JSON line for each file")
+	layout := flag.Bool("layout", false, "compare the text extracted with -layout")
+	flag.Parse()
+	if *baselinePath == "" || *candidatePath == "" {
+		log.Fatal("both -baseline and -candidate are required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	baseline, err := pdftotext.New(pdftotext.WithBinaryPath(*baselinePath))
+	if err != nil {
+		log.Fatal(err)
+	}
+	candidate, err := pdftotext.New(pdftotext.WithBinaryPath(*candidatePath))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var inputs []string
+	err = filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".pdf") {
+			inputs = append(inputs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	diffs := pdftotext.CompareBinaries(ctx, baseline, candidate, inputs, *workers, &pdftotext.Options{Layout: *layout})
+	regressions := 0
+	enc := json.NewEncoder(os.Stdout)
+	for _, d := range diffs {
+		r := result{BinaryDiff: d, Regressed: regressed(d, *minSimilarity)}
+		if r.Regressed {
+			regressions++
+		}
+		if *asJSON {
+			if d.BaselineErr != nil {
+				r.BaselineError = d.BaselineErr.Error()
+			}
+			if d.CandidateErr != nil {
+				r.CandidateError = d.CandidateErr.Error()
+			}
+			if err := enc.Encode(r); err != nil {
+				log.Fatal(err)
+			}
+			continue
+		}
+		report(os.Stdout, r, *showDiff)
+	}
+	if !*asJSON {
+		fmt.Printf("\n%d files, %d regressions\n", len(diffs), regressions)
+	}
+	if regressions > 0 {
+		os.Exit(1)
+	}
+}
+
+// regressed reports whether d is a regression: the candidate fails where the
+// baseline did not, or the texts are less similar than minSimilarity
+func regressed(d pdftotext.BinaryDiff, minSimilarity float64) bool {
+	if d.BaselineErr != nil {
+		return false
+	}
+	return d.CandidateErr != nil || !d.Identical && d.Similarity < minSimilarity
+}
+
+// report prints the outcome of a changed or failed file
+func report(w io.Writer, r result, showDiff bool) {
+	switch {
+	case r.BaselineErr != nil && r.CandidateErr != nil:
+		fmt.Fprintf(w, "FAILED BOTH %s: %v\n", r.Input, r.CandidateErr)
+	case r.BaselineErr != nil:
+		fmt.Fprintf(w, "FIXED       %s: %v\n", r.Input, r.BaselineErr)
+	case r.CandidateErr != nil:
+		fmt.Fprintf(w, "REGRESSED   %s: %v\n", r.Input, r.CandidateErr)
+	case !r.Identical:
+		status := "CHANGED"
+		if r.Regressed {
+			status = "REGRESSED"
+		}
+		fmt.Fprintf(w, "%-11s %s: similarity %.4f\n", status, r.Input, r.Similarity)
+		if showDiff {
+			fmt.Fprint(w, pdftotext.FormatDiff(r.Diff))
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Command s3lambda is an AWS Lambda function that extracts the text of every
+// PDF uploaded to an S3 bucket and writes it to OUTPUT_BUCKET as a .txt object.
+//
+// The pdftotext binary is expected in a Lambda layer at PDFTOTEXT_PATH
+// (default /opt/bin/pdftotext).
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/joeychilson/pdftotext"
+)
+
+func main() {
+	binaryPath := os.Getenv("PDFTOTEXT_PATH")
+	if binaryPath == "" {
+		binaryPath = "/opt/bin/pdftotext"
+	}
+	converter, err := pdftotext.New(pdftotext.WithBinaryPath(binaryPath))
+	if err != nil {
+		panic(err)
+	}
+
+	lambda.Start(func(ctx context.Context, event events.S3Event) error {
+		for _, record := range event.Records {
+			if err := handle(ctx, converter, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// handle converts the PDF object of record and uploads the extracted text
+func handle(ctx context.Context, converter *pdftotext.Converter, record events.S3EventRecord) error {
+	key, err := url.QueryUnescape(record.S3.Object.Key)
+	if err != nil {
+		return fmt.Errorf("invalid object key %q: %w", record.S3.Object.Key, err)
+	}
+
+	input := bucket(record.AWSRegion, record.S3.Bucket.Name)
+	data, err := input.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	inputPath := filepath.Join(os.TempDir(), filepath.Base(key))
+	if err := os.WriteFile(inputPath, data, 0o600); err != nil {
+		return err
+	}
+	defer os.Remove(inputPath)
+
+	text, err := converter.Convert(ctx, inputPath, &pdftotext.Options{Encoding: "UTF-8"})
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %w", key, err)
+	}
+
+	output := bucket(record.AWSRegion, os.Getenv("OUTPUT_BUCKET"))
+	return output.Put(ctx, strings.TrimSuffix(key, filepath.Ext(key))+".txt", []byte(text))
+}
+
+// bucket returns the storage for an S3 bucket using the function's credentials
+func bucket(region, name string) *pdftotext.S3Storage {
+	return &pdftotext.S3Storage{
+		Endpoint:        "https://s3." + region + ".amazonaws.com",
+		Region:          region,
+		Bucket:          name,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
@@ -0,0 +1,146 @@
+// Command watchfolder watches a directory for PDF files, extracts their text,
+// splits it into chunks and indexes the chunks in an SQLite FTS5 table.
+//
+// Build with FTS5 support enabled in the SQLite driver:
+//
+//	go build -tags sqlite_fts5 ./watchfolder
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joeychilson/pdftotext"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS documents (path TEXT PRIMARY KEY, mod_time INTEGER NOT NULL);
+CREATE VIRTUAL TABLE IF NOT EXISTS chunks USING fts5(path UNINDEXED, page UNINDEXED, text);
+`
+
+func main() {
+	dir := flag.String("dir", ".", "directory to watch for PDF files")
+	dbPath := flag.String("db", "index.db", "SQLite database to index into")
+	interval := flag.Duration("interval", 5*time.Second, "polling interval")
+	chunkSize := flag.Int("chunk-size", 1000, "maximum chunk size in characters")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	converter, err := pdftotext.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite3", *dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		log.Fatal(err)
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if err := scan(ctx, converter, db, *dir, *chunkSize); err != nil && ctx.Err() == nil {
+			log.Print(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan indexes every PDF in dir that is new or changed since it was last indexed
+func scan(ctx context.Context, converter *pdftotext.Converter, db *sql.DB, dir string, chunkSize int) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pdf"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		var indexed int64
+		err = db.QueryRowContext(ctx, "SELECT mod_time FROM documents WHERE path = ?", path).Scan(&indexed)
+		if err == nil && indexed == info.ModTime().UnixNano() {
+			continue
+		}
+
+		text, err := converter.Convert(ctx, path, &pdftotext.Options{Encoding: "UTF-8"})
+		if err != nil {
+			log.Printf("skipping %s: %v", path, err)
+			continue
+		}
+		if err := index(ctx, db, path, info.ModTime(), text, chunkSize); err != nil {
+			return err
+		}
+		log.Printf("indexed %s", path)
+	}
+	return nil
+}
+
+// index replaces the chunks of path with the chunks of text
+func index(ctx context.Context, db *sql.DB, path string, modTime time.Time, text string, chunkSize int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM chunks WHERE path = ?", path); err != nil {
+		return err
+	}
+	for i, page := range strings.Split(text, "\f") {
+		for _, chunk := range chunk(page, chunkSize) {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO chunks (path, page, text) VALUES (?, ?, ?)", path, i+1, chunk); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO documents (path, mod_time) VALUES (?, ?)", path, modTime.UnixNano()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// chunk splits text at paragraph boundaries into chunks of at most size
+// characters; longer paragraphs become chunks of their own
+func chunk(text string, size int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if current.Len() > 0 && current.Len()+len(paragraph) > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(paragraph)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
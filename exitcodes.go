@@ -0,0 +1,80 @@
+package pdftotext
+
+import "strings"
+
+// Variant is the implementation of a pdftotext binary
+type Variant string
+
+const (
+	// VariantPoppler is the pdftotext of poppler, installed by most Linux
+	// distributions and Homebrew
+	VariantPoppler Variant = "poppler"
+	// VariantXpdf is the pdftotext of the Xpdf tools by Glyph & Cog
+	VariantXpdf Variant = "xpdf"
+)
+
+// ExitCodeTable maps the exit codes of a pdftotext binary to the errors
+// conversions failing with them wrap. Exit codes missing from the table
+// wrap ErrCommandFailed.
+type ExitCodeTable map[int]error
+
+var (
+	// PopplerExitCodes are the exit codes of poppler's pdftotext
+	PopplerExitCodes = ExitCodeTable{
+		1:  ErrPDFOpen,
+		2:  ErrOutputFile,
+		3:  ErrPermissions,
+		99: ErrCommandFailed,
+	}
+	// XpdfExitCodes are the exit codes of Xpdf's pdftotext, which also
+	// exits with 98 when it runs out of memory
+	XpdfExitCodes = ExitCodeTable{
+		1:  ErrPDFOpen,
+		2:  ErrOutputFile,
+		3:  ErrPermissions,
+		98: ErrOutOfMemory,
+		99: ErrCommandFailed,
+	}
+)
+
+// WithExitCodes sets the table mapping the exit codes of the binary to
+// errors, for binaries whose exit codes differ from those of their variant.
+// By default the table of the variant detected by WithStartupCheck is used,
+// or PopplerExitCodes without a startup check.
+func WithExitCodes(table ExitCodeTable) ConverterOption {
+	return func(c *Converter) {
+		c.exitCodeTable = table
+	}
+}
+
+// Variant returns the implementation of the binary detected by the startup
+// check of WithStartupCheck, or "" if it was not run
+func (c *Converter) Variant() Variant {
+	return c.variant
+}
+
+// exitCodes returns the exit code table of the binary
+func (c *Converter) exitCodes() ExitCodeTable {
+	switch {
+	case c.exitCodeTable != nil:
+		return c.exitCodeTable
+	case c.variant == VariantXpdf:
+		return XpdfExitCodes
+	default:
+		return PopplerExitCodes
+	}
+}
+
+// parseVariant returns the variant of the binary printing output for -v
+func parseVariant(output string) Variant {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "poppler"):
+		return VariantPoppler
+	case strings.Contains(lower, "glyph & cog"):
+		// poppler credits Glyph & Cog too, but after naming itself
+		return VariantXpdf
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,104 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestConverter_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		variant  Variant
+		table    ExitCodeTable
+		code     int
+		stderr   string
+		expected []error
+	}{
+		{"PopplerOpen", VariantPoppler, nil, 1, "Syntax Error: Couldn't find trailer dictionary", []error{ErrPDFOpen}},
+		{"PopplerPassword", VariantPoppler, nil, 1, "Command Line Error: Incorrect password", []error{ErrPDFOpen, ErrEncrypted}},
+		{"PopplerOutput", VariantPoppler, nil, 2, "", []error{ErrOutputFile}},
+		{"PopplerPermissions", VariantPoppler, nil, 3, "", []error{ErrPermissions}},
+		{"PopplerOther", VariantPoppler, nil, 99, "", []error{ErrCommandFailed}},
+		{"PopplerUnknown", VariantPoppler, nil, 98, "", []error{ErrCommandFailed}},
+		{"XpdfOutOfMemory", VariantXpdf, nil, 98, "Out of memory", []error{ErrCommandFailed, ErrOutOfMemory}},
+		{"XpdfOther", VariantXpdf, nil, 99, "", []error{ErrCommandFailed}},
+		{"UndetectedVariant", "", nil, 3, "", []error{ErrPermissions}},
+		{"CustomTable", VariantPoppler, ExitCodeTable{4: ErrPermissions}, 4, "", []error{ErrPermissions}},
+		{"CustomTableMissingCode", VariantPoppler, ExitCodeTable{4: ErrPermissions}, 1, "", []error{ErrCommandFailed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{variant: tt.variant, exitCodeTable: tt.table}
+			err := c.handleError(&ExitError{Code: tt.code}, tt.stderr)
+			for _, expected := range tt.expected {
+				if !errors.Is(err, expected) {
+					t.Errorf("expected %v, got %v", expected, err)
+				}
+			}
+			var exitErr *ExitError
+			if wrapsExit := errors.As(err, &exitErr); wrapsExit != errors.Is(err, ErrCommandFailed) {
+				t.Errorf("expected the exit error to be wrapped only with ErrCommandFailed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseVariant(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected Variant
+	}{
+		{"pdftotext version 24.02.0\nCopyright 2005-2024 The Poppler Developers - http://poppler.freedesktop.org\nCopyright 1996-2011, 2022 Glyph & Cog, LLC\n", VariantPoppler},
+		{"pdftotext version 4.04 [www.xpdfreader.com]\nCopyright 1996-2022 Glyph & Cog, LLC\n", VariantXpdf},
+		{"pdftotext version 1.0\n", ""},
+	}
+	for _, tt := range tests {
+		if got := parseVariant(tt.output); got != tt.expected {
+			t.Errorf("parseVariant(%q): expected %q, got %q", tt.output, tt.expected, got)
+		}
+	}
+}
+
+func TestWithStartupCheck_Variant(t *testing.T) {
+	converter, err := New(WithStartupCheck(), WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if args[0] == "-v" {
+			_, err := io.WriteString(stdout, "pdftotext version 4.04 [www.xpdfreader.com]\nCopyright 1996-2022 Glyph & Cog, LLC\n")
+			return err
+		}
+		return &ExitError{Code: 98}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if converter.Variant() != VariantXpdf {
+		t.Errorf("expected the xpdf variant, got %q", converter.Variant())
+	}
+	_, err = converter.Convert(context.Background(), "input.pdf", nil)
+	if !errors.Is(err, ErrOutOfMemory) {
+		t.Errorf("expected ErrOutOfMemory, got %v", err)
+	}
+	if kind := ErrorKind(err); kind != "out_of_memory" {
+		t.Errorf("expected the out_of_memory kind, got %q", kind)
+	}
+
+	// a clone running the same binary keeps the variant and its exit codes
+	clone, err := converter.Clone(WithMaxStderr(1 << 10))
+	if err != nil {
+		t.Fatalf("failed to clone converter: %v", err)
+	}
+	if clone.Variant() != VariantXpdf {
+		t.Errorf("expected the clone to keep the xpdf variant, got %q", clone.Variant())
+	}
+	if _, err := clone.Convert(context.Background(), "input.pdf", nil); !errors.Is(err, ErrOutOfMemory) {
+		t.Errorf("expected ErrOutOfMemory from the clone, got %v", err)
+	}
+	clone, err = converter.Clone(WithRunner(fakeRun("", "", 0)))
+	if err != nil {
+		t.Fatalf("failed to clone converter: %v", err)
+	}
+	if clone.Variant() != "" {
+		t.Errorf("expected a clone with another runner to drop the variant, got %q", clone.Variant())
+	}
+}
@@ -0,0 +1,69 @@
+package pdftotext
+
+import (
+	"expvar"
+	"os"
+	"time"
+)
+
+// ExpvarName is the name the runtime counters of this package are published
+// under with expvar, so they are served by the /debug/vars endpoint of
+// net/http/pprof and expvar. The published map holds the number of
+// pdftotext runs in "conversions", the failed runs by ErrorKind in
+// "failures", the runs in progress in "in_flight", the bytes of text
+// extracted in "bytes_extracted" and the total run time in "exec_seconds".
+const ExpvarName = "pdftotext"
+
+// counters are the runtime counters of all converters of the process
+var counters = newExpvarCounters()
+
+// expvarCounters are the variables of the published expvar map
+type expvarCounters struct {
+	conversions    expvar.Int
+	failures       expvar.Map
+	inFlight       expvar.Int
+	bytesExtracted expvar.Int
+	execSeconds    expvar.Float
+}
+
+// newExpvarCounters publishes the counters as ExpvarName
+func newExpvarCounters() *expvarCounters {
+	ec := &expvarCounters{}
+	m := expvar.NewMap(ExpvarName)
+	m.Set("conversions", &ec.conversions)
+	m.Set("failures", ec.failures.Init())
+	m.Set("in_flight", &ec.inFlight)
+	m.Set("bytes_extracted", &ec.bytesExtracted)
+	m.Set("exec_seconds", &ec.execSeconds)
+	return ec
+}
+
+// start counts a run of pdftotext starting now and returns the function
+// counting its end, with the bytes of text it produced and its error
+func (ec *expvarCounters) start() func(extracted int64, err error) {
+	start := time.Now()
+	ec.conversions.Add(1)
+	ec.inFlight.Add(1)
+	return func(extracted int64, err error) {
+		ec.inFlight.Add(-1)
+		ec.execSeconds.Add(time.Since(start).Seconds())
+		if err != nil {
+			ec.failures.Add(ErrorKind(err), 1)
+			return
+		}
+		ec.bytesExtracted.Add(extracted)
+	}
+}
+
+// outputSize returns the size of the text a successful run with args wrote
+// to its output file, or 0 if it wrote to stdout
+func outputSize(args []string) int64 {
+	if len(args) == 0 || args[len(args)-1] == "-" {
+		return 0
+	}
+	stat, err := os.Stat(args[len(args)-1])
+	if err != nil {
+		return 0
+	}
+	return stat.Size()
+}
@@ -0,0 +1,69 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// expvarInt returns the integer counter name of the published map
+func expvarInt(t *testing.T, name string) int64 {
+	t.Helper()
+	v, ok := expvar.Get(ExpvarName).(*expvar.Map).Get(name).(*expvar.Int)
+	if !ok {
+		t.Fatalf("expected %s to be published", name)
+	}
+	return v.Value()
+}
+
+func TestExpvarCounters(t *testing.T) {
+	fail := false
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if expvarInt(t, "in_flight") < 1 {
+			t.Error("expected the run to be in flight")
+		}
+		if fail {
+			return errors.New("boom")
+		}
+		if output := args[len(args)-1]; output != "-" {
+			return os.WriteFile(output, []byte("some text"), 0o644)
+		}
+		_, err := io.WriteString(stdout, "some text")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	conversions := expvarInt(t, "conversions")
+	extracted := expvarInt(t, "bytes_extracted")
+	if _, err := converter.Convert(ctx, "input.pdf", nil); err != nil {
+		t.Fatalf("failed to convert: %v", err)
+	}
+	if err := converter.ConvertToFile(ctx, "input.pdf", filepath.Join(t.TempDir(), "output.txt"), nil); err != nil {
+		t.Fatalf("failed to convert: %v", err)
+	}
+	fail = true
+	if _, err := converter.Convert(ctx, "input.pdf", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if n := expvarInt(t, "conversions") - conversions; n != 3 {
+		t.Errorf("expected 3 conversions, got %d", n)
+	}
+	if n := expvarInt(t, "bytes_extracted") - extracted; n != 2*int64(len("some text")) {
+		t.Errorf("expected the bytes of two outputs, got %d", n)
+	}
+	if n := expvarInt(t, "in_flight"); n != 0 {
+		t.Errorf("expected no runs in flight, got %d", n)
+	}
+	failures := expvar.Get(ExpvarName).(*expvar.Map).Get("failures").(*expvar.Map)
+	if v, ok := failures.Get("other").(*expvar.Int); !ok || v.Value() < 1 {
+		t.Errorf("expected a failure of kind other, got %v", failures)
+	}
+}
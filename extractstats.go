@@ -0,0 +1,108 @@
+package pdftotext
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Stats are the characteristics of the output of a conversion, for
+// monitoring corpora and spotting anomalies such as scanned documents
+// without a text layer
+type Stats struct {
+	// Pages is the number of pages of the output, counted by the form feeds
+	// ending them, so it is 1 with NoPageBreaks or a PageSeparator
+	Pages int `json:"pages"`
+	// EmptyPages is the number of pages without any text
+	EmptyPages int `json:"empty_pages"`
+	// Words is the number of whitespace-separated words
+	Words int `json:"words"`
+	// Chars is the number of characters, excluding whitespace
+	Chars int `json:"chars"`
+	// Bytes is the size of the output in bytes
+	Bytes int `json:"bytes"`
+	// PageChars is the number of characters of each page, excluding
+	// whitespace
+	PageChars []int `json:"page_chars"`
+	// MinPageChars, MedianPageChars and MaxPageChars summarize PageChars
+	MinPageChars    int `json:"min_page_chars"`
+	MedianPageChars int `json:"median_page_chars"`
+	MaxPageChars    int `json:"max_page_chars"`
+	// Duration is how long the conversion took
+	Duration time.Duration `json:"duration"`
+	// PageTimings are how long each page took in the page-wise ConvertEx,
+	// in order, measured from the previous page to the arrival of the page,
+	// including its OCR or re-extraction. The first page includes starting
+	// pdftotext. Pages come in blocks when pdftotext buffers its output, so
+	// the timings of short pages are approximate.
+	PageTimings []PageTiming `json:"page_timings,omitempty"`
+	// SlowestPages are the slowest of PageTimings, slowest first, to find
+	// the pages that dominate the conversion time
+	SlowestPages []PageTiming `json:"slowest_pages,omitempty"`
+}
+
+// PageTiming is how long a page took to convert
+type PageTiming struct {
+	// Page is the page number
+	Page int `json:"page"`
+	// Duration is how long the page took
+	Duration time.Duration `json:"duration"`
+}
+
+// slowestPagesCount is the number of SlowestPages
+const slowestPagesCount = 5
+
+// slowestPages returns the n slowest of timings, slowest first, and the
+// earliest pages first among equally slow ones
+func slowestPages(timings []PageTiming, n int) []PageTiming {
+	slowest := slices.Clone(timings)
+	slices.SortStableFunc(slowest, func(a, b PageTiming) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+	return slowest[:min(n, len(slowest))]
+}
+
+// ConvertWithStats converts a PDF file to text like Convert and returns the
+// statistics of the output along with the text
+func (c *Converter) ConvertWithStats(ctx context.Context, inputPath string, opts *Options) (string, *Stats, error) {
+	start := time.Now()
+	var stdout bytes.Buffer
+	if err := c.ConvertTo(ctx, inputPath, &stdout, opts); err != nil {
+		return "", nil, err
+	}
+	stats := ComputeStats(stdout.String())
+	if opts == nil || !opts.Deterministic {
+		stats.Duration = time.Since(start)
+	}
+	return strings.TrimSpace(stdout.String()), stats, nil
+}
+
+// ComputeStats returns the statistics of pdftotext output, whose pages are
+// ended by form feeds. Duration is left zero.
+func ComputeStats(output string) *Stats {
+	stats := &Stats{Bytes: len(output), PageChars: []int{}}
+	for _, page := range splitPages(output, 1) {
+		chars := 0
+		for _, word := range strings.Fields(page.Text) {
+			stats.Words++
+			chars += utf8.RuneCountInString(word)
+		}
+		stats.Chars += chars
+		stats.PageChars = append(stats.PageChars, chars)
+		if chars == 0 {
+			stats.EmptyPages++
+		}
+	}
+	stats.Pages = len(stats.PageChars)
+	if stats.Pages > 0 {
+		sorted := slices.Sorted(slices.Values(stats.PageChars))
+		stats.MinPageChars = sorted[0]
+		stats.MedianPageChars = sorted[len(sorted)/2]
+		stats.MaxPageChars = sorted[len(sorted)-1]
+	}
+	return stats
+}
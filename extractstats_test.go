@@ -0,0 +1,69 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected Stats
+	}{
+		{
+			name:   "Pages",
+			output: "one two\f\f  three  fünf\nsix\f",
+			expected: Stats{
+				Pages: 3, EmptyPages: 1, Words: 5, Chars: 18, Bytes: 28,
+				PageChars:    []int{6, 0, 12},
+				MinPageChars: 0, MedianPageChars: 6, MaxPageChars: 12,
+			},
+		},
+		{
+			name:   "No page breaks",
+			output: "one two three",
+			expected: Stats{
+				Pages: 1, Words: 3, Chars: 11, Bytes: 13,
+				PageChars:    []int{11},
+				MinPageChars: 11, MedianPageChars: 11, MaxPageChars: 11,
+			},
+		},
+		{
+			name:     "Empty",
+			output:   "",
+			expected: Stats{PageChars: []int{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeStats(tt.output); !reflect.DeepEqual(*got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, *got)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertWithStats(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "hello world\fgoodbye\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	text, stats, err := converter.ConvertWithStats(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "hello world\fgoodbye" {
+		t.Errorf("unexpected text %q", text)
+	}
+	if stats.Pages != 2 || stats.Words != 3 || stats.Bytes != 20 || stats.Duration <= 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,78 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+// runPageFallback converts the page range of opts into memory and, if the
+// conversion crashes, converts it again page by page, leaving the pages that
+// fail empty and reporting them to opts.OnPageSkipped
+func (c *Converter) runPageFallback(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	direct := *opts
+	direct.PageFallback = false
+	var buf bytes.Buffer
+	if err := c.run(ctx, inputPath, "-", &direct, &buf); err != nil {
+		if !pageFallbackApplies(ctx, err) {
+			return err
+		}
+		buf.Reset()
+		if err := c.convertPageByPage(ctx, inputPath, &direct, &buf, err); err != nil {
+			return err
+		}
+	}
+	return writeOutput(outputPath, stdout, func(w io.Writer) error {
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+}
+
+// convertPageByPage converts each page of the range of opts separately to
+// buf, skipping the pages that fail. It returns failure, the error of the
+// whole conversion, if the page count cannot be looked up or no page can be
+// converted.
+func (c *Converter) convertPageByPage(ctx context.Context, inputPath string, opts *Options, buf *bytes.Buffer, failure error) error {
+	first, last := max(opts.FirstPage, 1), opts.LastPage
+	if last == 0 {
+		count, err := c.PageCount(ctx, inputPath, opts)
+		if err != nil {
+			return failure
+		}
+		last = count
+	}
+
+	converted := false
+	for number := first; number <= last; number++ {
+		pageOpts := *opts
+		pageOpts.FirstPage, pageOpts.LastPage = number, number
+		var page bytes.Buffer
+		if err := c.run(ctx, inputPath, "-", &pageOpts, &page); err != nil {
+			if !pageFallbackApplies(ctx, err) {
+				return err
+			}
+			if opts.OnPageSkipped != nil {
+				opts.OnPageSkipped(number, err)
+			}
+			// an empty page keeps the numbering of the following pages
+			page.Reset()
+			if !opts.NoPageBreaks {
+				page.WriteByte('\f')
+			}
+		} else {
+			converted = true
+		}
+		buf.Write(page.Bytes())
+	}
+	if !converted {
+		return failure
+	}
+	return nil
+}
+
+// pageFallbackApplies reports whether err may be caused by a single page, such
+// as a crash, rather than by the whole file or the caller
+func pageFallbackApplies(ctx context.Context, err error) bool {
+	return errors.Is(err, ErrCommandFailed) && ctx.Err() == nil && !errors.Is(err, ErrMissingLanguageData)
+}
@@ -0,0 +1,125 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// crashingRunner converts pages 1 to 4 like pdftotext, crashing on the pages
+// in crash
+func crashingRunner(crash ...int) runnerFunc {
+	return func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if name == "pdfinfo" {
+			_, err := io.WriteString(stdout, "Pages:          4\n")
+			return err
+		}
+		first, last := 1, 4
+		if i := slices.Index(args, "-f"); i >= 0 {
+			first, _ = strconv.Atoi(args[i+1])
+		}
+		if i := slices.Index(args, "-l"); i >= 0 {
+			last, _ = strconv.Atoi(args[i+1])
+		}
+		for page := first; page <= last; page++ {
+			if slices.Contains(crash, page) {
+				return &ExitError{Code: -1}
+			}
+			io.WriteString(stdout, "page "+strconv.Itoa(page)+"\f")
+		}
+		return nil
+	}
+}
+
+func TestConverter_PageFallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		crash         []int
+		options       *Options
+		expectedText  string
+		expectedError error
+		skipped       []int
+	}{
+		{
+			name:         "No crash",
+			options:      &Options{PageFallback: true},
+			expectedText: "page 1\fpage 2\fpage 3\fpage 4",
+		},
+		{
+			name:         "Crashing page",
+			crash:        []int{3},
+			options:      &Options{PageFallback: true},
+			expectedText: "page 1\fpage 2\f\fpage 4",
+			skipped:      []int{3},
+		},
+		{
+			name:         "Page range",
+			crash:        []int{2},
+			options:      &Options{PageFallback: true, FirstPage: 2, LastPage: 3},
+			expectedText: "page 3",
+			skipped:      []int{2},
+		},
+		{
+			name:          "Without fallback",
+			crash:         []int{3},
+			expectedError: ErrCommandFailed,
+		},
+		{
+			name:          "Every page crashes",
+			crash:         []int{1, 2, 3, 4},
+			options:       &Options{PageFallback: true},
+			expectedError: ErrCommandFailed,
+			skipped:       []int{1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(crashingRunner(tt.crash...)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			var skipped []int
+			if tt.options != nil {
+				tt.options.OnPageSkipped = func(page int, err error) {
+					if !errors.Is(err, ErrCommandFailed) {
+						t.Errorf("expected error %v, got %v", ErrCommandFailed, err)
+					}
+					skipped = append(skipped, page)
+				}
+			}
+
+			text, err := converter.Convert(context.Background(), "input.pdf", tt.options)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, text)
+			}
+			if !reflect.DeepEqual(skipped, tt.skipped) {
+				t.Errorf("expected skipped pages %v, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+
+	t.Run("ConvertEx", func(t *testing.T) {
+		converter, err := New(WithRunner(crashingRunner(2)))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+		result, err := converter.ConvertEx(context.Background(), "input.pdf", &Options{PageFallback: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(result.SkippedPages, []int{2}) || len(result.Pages) != 4 {
+			t.Errorf("expected page 2 to be skipped, got %+v", result)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// FallbackChain is a list of conversion options tried in order until one
+// gives acceptable text, e.g. Layout, then Raw, then OCR, so documents that
+// defeat the first attempt are tried harder instead of returning garbage
+type FallbackChain struct {
+	// Attempts are the options tried in order
+	Attempts []Options
+	// Accept reports whether the text of an attempt is good enough to end
+	// the chain. By default text is accepted if it is not blank and its
+	// ScoreQuality is at least 0.5.
+	Accept func(text string) bool
+}
+
+// FallbackResult is the text returned by ConvertFallback
+type FallbackResult struct {
+	// Text is the text of the accepted attempt, or of the best scoring one
+	// if no attempt was accepted
+	Text string `json:"text"`
+	// Attempt is the index of the attempt Text came from
+	Attempt int `json:"attempt"`
+	// Accepted reports whether Accept accepted Text
+	Accepted bool `json:"accepted"`
+	// Errors are the errors of the attempts that failed, nil for the others
+	Errors []error `json:"-"`
+}
+
+// defaultAcceptQuality is the ScoreQuality score accepted by a FallbackChain
+// without Accept
+const defaultAcceptQuality = 0.5
+
+// ConvertFallback converts a PDF file with each of the attempts of chain in
+// turn until one is accepted. If none is, the result holds the text of the
+// attempt with the best ScoreQuality score. It fails only if every attempt
+// fails, with their errors joined, or if ctx is done.
+func (c *Converter) ConvertFallback(ctx context.Context, inputPath string, chain FallbackChain) (*FallbackResult, error) {
+	if len(chain.Attempts) == 0 {
+		return nil, errors.New("fallback chain without attempts")
+	}
+	accept := chain.Accept
+	if accept == nil {
+		accept = func(text string) bool {
+			return strings.TrimSpace(text) != "" && ScoreQuality(text).Score >= defaultAcceptQuality
+		}
+	}
+
+	result := &FallbackResult{Attempt: -1, Errors: make([]error, len(chain.Attempts))}
+	best := -1.0
+	for i := range chain.Attempts {
+		text, err := c.Convert(ctx, inputPath, &chain.Attempts[i])
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			result.Errors[i] = err
+			continue
+		}
+		if accept(text) {
+			result.Text, result.Attempt, result.Accepted = text, i, true
+			return result, nil
+		}
+		if score := ScoreQuality(text).Score; score > best {
+			result.Text, result.Attempt, best = text, i, score
+		}
+	}
+	if result.Attempt < 0 {
+		return nil, errors.Join(result.Errors...)
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConverter_ConvertFallback(t *testing.T) {
+	// the runner fails with -layout, garbles with -raw and converts cleanly
+	// otherwise
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		switch {
+		case slices.Contains(args, "-layout"):
+			return &ExitError{Code: 1}
+		case slices.Contains(args, "-raw"):
+			_, err := io.WriteString(stdout, "ǂ%# ǂǂ$ ¤¤¤ ǂ#ǂ")
+			return err
+		}
+		_, err := io.WriteString(stdout, "The quarterly report shows growing revenue.")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	layout, raw, plain := Options{Layout: true}, Options{Raw: true}, Options{}
+
+	tests := []struct {
+		name     string
+		chain    FallbackChain
+		text     string
+		attempt  int
+		accepted bool
+		failed   []bool
+		err      error
+	}{
+		{
+			name:     "First accepted",
+			chain:    FallbackChain{Attempts: []Options{plain, raw}},
+			text:     "The quarterly report shows growing revenue.",
+			accepted: true,
+			failed:   []bool{false, false},
+		},
+		{
+			name:     "Failed and garbled attempts",
+			chain:    FallbackChain{Attempts: []Options{layout, raw, plain}},
+			text:     "The quarterly report shows growing revenue.",
+			attempt:  2,
+			accepted: true,
+			failed:   []bool{true, false, false},
+		},
+		{
+			name:    "None accepted",
+			chain:   FallbackChain{Attempts: []Options{raw, plain}, Accept: func(text string) bool { return strings.Contains(text, "invoice") }},
+			text:    "The quarterly report shows growing revenue.",
+			attempt: 1,
+			failed:  []bool{false, false},
+		},
+		{
+			name:  "All failed",
+			chain: FallbackChain{Attempts: []Options{layout}},
+			err:   ErrPDFOpen,
+		},
+	}
+	if _, err := converter.ConvertFallback(ctx, "input.pdf", FallbackChain{}); err == nil {
+		t.Error("expected an error for a chain without attempts")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.ConvertFallback(ctx, "input.pdf", tt.chain)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Text != tt.text || result.Attempt != tt.attempt || result.Accepted != tt.accepted {
+				t.Errorf("unexpected result %+v", result)
+			}
+			var failed []bool
+			for _, err := range result.Errors {
+				failed = append(failed, err != nil)
+			}
+			if !slices.Equal(failed, tt.failed) {
+				t.Errorf("expected failed attempts %v, got %v", tt.failed, failed)
+			}
+		})
+	}
+}
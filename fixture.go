@@ -0,0 +1,153 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FixtureMode selects how a FixtureRunner uses its fixtures
+type FixtureMode int
+
+const (
+	// FixtureReplay replays recorded fixtures and fails when one is missing
+	FixtureReplay FixtureMode = iota
+	// FixtureRecord runs every command and records its result
+	FixtureRecord
+	// FixtureAuto replays recorded fixtures and records missing ones
+	FixtureAuto
+)
+
+// FixtureRunner is a Runner that records real pdftotext results to Dir and
+// replays them, so integration-style tests are deterministic and fast while
+// still derived from real poppler behavior. Fixtures are keyed by a hash of
+// the arguments, with the output file path left out so tests may write to
+// temporary directories.
+type FixtureRunner struct {
+	// Dir is the directory holding the fixtures
+	Dir string
+	// Mode selects between replaying and recording
+	Mode FixtureMode
+	// Runner runs the commands being recorded (default ExecRunner)
+	Runner Runner
+}
+
+// fixture is the recorded result of a command
+type fixture struct {
+	Args     []string `json:"args"`
+	Stdout   []byte   `json:"stdout"`
+	Stderr   []byte   `json:"stderr"`
+	ExitCode int      `json:"exit_code"`
+	Output   []byte   `json:"output,omitempty"`
+}
+
+// Run replays or records the command
+func (r *FixtureRunner) Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	key, outputPath := fixtureKey(args)
+	path := filepath.Join(r.Dir, key+".json")
+
+	if r.Mode != FixtureRecord {
+		f, err := readFixture(path)
+		if err == nil {
+			return f.replay(stdout, stderr, outputPath)
+		}
+		if r.Mode == FixtureReplay || !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+
+	f, runErr := r.record(ctx, name, args, outputPath)
+	if runErr != nil {
+		return runErr
+	}
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := writeJSON(ctx, OSFS{}, path, f); err != nil {
+		return err
+	}
+	return f.replay(stdout, stderr, outputPath)
+}
+
+// record runs the command and captures its result
+func (r *FixtureRunner) record(ctx context.Context, name string, args []string, outputPath string) (*fixture, error) {
+	runner := r.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+
+	var stdout, stderr bytes.Buffer
+	f := &fixture{Args: maskPasswords(args)}
+	if err := runner.Run(ctx, name, args, &stdout, &stderr); err != nil {
+		var exitErr exitCoder
+		if !errors.As(err, &exitErr) || ctx.Err() != nil {
+			return nil, err
+		}
+		f.ExitCode = exitErr.ExitCode()
+	}
+	f.Stdout, f.Stderr = stdout.Bytes(), stderr.Bytes()
+
+	if outputPath != "" && f.ExitCode == 0 {
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record output file: %w", err)
+		}
+		f.Output = output
+	}
+	return f, nil
+}
+
+// replay writes the recorded result and returns the recorded exit status
+func (f *fixture) replay(stdout, stderr io.Writer, outputPath string) error {
+	if stdout != nil {
+		if _, err := stdout.Write(f.Stdout); err != nil {
+			return err
+		}
+	}
+	if _, err := stderr.Write(f.Stderr); err != nil {
+		return err
+	}
+	if outputPath != "" && f.ExitCode == 0 {
+		if err := os.WriteFile(outputPath, f.Output, 0o644); err != nil {
+			return err
+		}
+	}
+	if f.ExitCode != 0 {
+		return &ExitError{Code: f.ExitCode}
+	}
+	return nil
+}
+
+// fixtureKey returns the fixture key for args and the output file path, if any
+func fixtureKey(args []string) (key, outputPath string) {
+	keyArgs := append([]string(nil), args...)
+	if files := fileArgs(args); len(files) == 2 && args[files[1]] != "-" {
+		outputPath = args[files[1]]
+		keyArgs[files[1]] = "<output>"
+	}
+	sum := sha256.Sum256([]byte(strings.Join(keyArgs, "\x00")))
+	return hex.EncodeToString(sum[:]), outputPath
+}
+
+func readFixture(path string) (*fixture, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: fixture %s", ErrNotFound, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
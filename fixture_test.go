@@ -0,0 +1,107 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtureRunner(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	calls := 0
+	real := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		calls++
+		if args[len(args)-2] == "broken.pdf" {
+			io.WriteString(stderr, "Syntax Error: Couldn't find trailer dictionary")
+			return &ExitError{Code: 1}
+		}
+		text := "text of " + strings.Join(args, " ") + "\f"
+		if output := args[len(args)-1]; output != "-" {
+			return os.WriteFile(output, []byte(text), 0o644)
+		}
+		_, err := io.WriteString(stdout, text)
+		return err
+	})
+
+	recorder, err := New(WithRunner(&FixtureRunner{Dir: dir, Mode: FixtureRecord, Runner: real}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	replayer, err := New(WithRunner(&FixtureRunner{Dir: dir, Mode: FixtureReplay}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	// record
+	recorded, err := recorder.Convert(ctx, "input.pdf", &Options{Layout: true})
+	if err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+	if _, err := recorder.Convert(ctx, "broken.pdf", nil); !errors.Is(err, ErrPDFOpen) {
+		t.Fatalf("expected error %v while recording, got %v", ErrPDFOpen, err)
+	}
+	if err := recorder.ConvertToFile(ctx, "input.pdf", filepath.Join(t.TempDir(), "first.txt"), nil); err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 real runs while recording, got %d", calls)
+	}
+
+	// replay
+	replayed, err := replayer.Convert(ctx, "input.pdf", &Options{Layout: true})
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if replayed != recorded {
+		t.Errorf("expected replayed text %q, got %q", recorded, replayed)
+	}
+
+	_, err = replayer.Convert(ctx, "broken.pdf", nil)
+	if !errors.Is(err, ErrPDFOpen) || !strings.Contains(err.Error(), "trailer dictionary") {
+		t.Errorf("expected replayed error %v with stderr, got %v", ErrPDFOpen, err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "second.txt")
+	if err := replayer.ConvertToFile(ctx, "input.pdf", outputPath, nil); err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil || !strings.HasPrefix(string(content), "text of input.pdf ") {
+		t.Errorf("expected replayed output file, got %q (%v)", content, err)
+	}
+
+	if _, err := replayer.Convert(ctx, "other.pdf", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected error %v for missing fixture, got %v", ErrNotFound, err)
+	}
+	if calls != 3 {
+		t.Errorf("expected no real runs while replaying, got %d", calls-3)
+	}
+}
+
+func TestFixtureRunner_Auto(t *testing.T) {
+	calls := 0
+	real := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		calls++
+		_, err := io.WriteString(stdout, "text")
+		return err
+	})
+	converter, err := New(WithRunner(&FixtureRunner{Dir: t.TempDir(), Mode: FixtureAuto, Runner: real}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if text, err := converter.Convert(context.Background(), "input.pdf", nil); err != nil || text != "text" {
+			t.Fatalf("unexpected result %q, %v", text, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected a single real run, got %d", calls)
+	}
+}
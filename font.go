@@ -0,0 +1,104 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+)
+
+// FontInfo is a font used by a PDF file as reported by pdffonts
+type FontInfo struct {
+	// Name is the font name, with a "ABCDEF+" prefix for subsets, or
+	// "[none]" for unnamed Type 3 fonts
+	Name string `json:"name"`
+	// Type is the font type, e.g. "Type 1", "TrueType" or "CID Type 0C"
+	Type string `json:"type"`
+	// Encoding is the font encoding, e.g. "WinAnsi", "Custom" or
+	// "Identity-H"
+	Encoding string `json:"encoding"`
+	// Embedded reports whether the font program is embedded in the file
+	Embedded bool `json:"embedded"`
+	// Subset reports whether only the glyphs used are embedded
+	Subset bool `json:"subset"`
+	// Unicode reports whether the font has a ToUnicode map from its glyphs
+	// to Unicode text
+	Unicode bool `json:"unicode"`
+	// ObjectID is the object number and generation of the font, e.g. "12 0"
+	ObjectID string `json:"object_id"`
+}
+
+// UnreliableText reports whether text extracted from the font is likely
+// garbled: it has no ToUnicode map and a custom or Identity encoding, so
+// pdftotext can only guess the characters behind its glyphs
+func (f FontInfo) UnreliableText() bool {
+	return !f.Unicode && (f.Encoding == "Custom" || strings.HasPrefix(f.Encoding, "Identity"))
+}
+
+// Fonts returns the fonts of a PDF file using pdffonts, to detect fonts
+// without a ToUnicode map, the most common cause of garbled text. Only the
+// page and password options of opts are used.
+func (c *Converter) Fonts(ctx context.Context, inputPath string, opts *Options) ([]FontInfo, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return nil, err
+	}
+	var args []string
+	if opts != nil {
+		if opts.FirstPage > 0 {
+			args = append(args, "-f", strconv.Itoa(opts.FirstPage))
+		}
+		if opts.LastPage > 0 {
+			args = append(args, "-l", strconv.Itoa(opts.LastPage))
+		}
+	}
+	args = append(args, passwordArgs(opts)...)
+	args = append(args, escapePath(inputPath))
+
+	var stdout bytes.Buffer
+	stderr := c.newStderr()
+	if err := c.runCommand(ctx, c.toolPath("pdffonts"), args, &stdout, stderr); err != nil {
+		return nil, c.handleError(err, stderr.String())
+	}
+	return parseFonts(stdout.String()), nil
+}
+
+// parseFonts parses the table printed by pdffonts. The columns after the
+// type are single words, so they are split off the end of each line, and
+// the name is taken from its column under the dashes of the header unless it
+// overflows it.
+func parseFonts(output string) []FontInfo {
+	fonts := []FontInfo{}
+	nameWidth := -1
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if nameWidth < 0 {
+			if strings.HasPrefix(line, "---") {
+				nameWidth = strings.IndexByte(line, ' ')
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		n := len(fields)
+		if n < 8 {
+			continue
+		}
+		font := FontInfo{
+			Encoding: fields[n-6],
+			Embedded: fields[n-5] == "yes",
+			Subset:   fields[n-4] == "yes",
+			Unicode:  fields[n-3] == "yes",
+			ObjectID: fields[n-2] + " " + fields[n-1],
+		}
+		// the name and the type, which may both contain spaces
+		rest := line[:strings.LastIndex(line, font.Encoding)]
+		if nameWidth > 0 && nameWidth < len(rest) && rest[nameWidth] == ' ' {
+			font.Name, font.Type = strings.TrimSpace(rest[:nameWidth]), strings.TrimSpace(rest[nameWidth:])
+		} else {
+			font.Name, font.Type = fields[0], strings.Join(fields[1:n-6], " ")
+		}
+		fonts = append(fonts, font)
+	}
+	return fonts
+}
@@ -0,0 +1,80 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+const pdffontsOutput = `name                                 type              encoding         emb sub uni object ID
+------------------------------------ ----------------- ---------------- --- --- --- ---------
+BAAAAA+LiberationSerif               TrueType          WinAnsi          yes yes yes     10  0
+Helvetica                            Type 1            Standard         no  no  no      12  0
+CAAAAA+NotoSansCJKjp-Regular         CID Type 0C       Identity-H       yes yes no      14  0
+[none]                               Type 3            Custom           yes no  no      16  0
+DAAAAA+AVeryLongFontNameOverflowingItsColumn CID TrueType      Identity-H       yes yes yes     18  0
+`
+
+func TestParseFonts(t *testing.T) {
+	expected := []FontInfo{
+		{Name: "BAAAAA+LiberationSerif", Type: "TrueType", Encoding: "WinAnsi", Embedded: true, Subset: true, Unicode: true, ObjectID: "10 0"},
+		{Name: "Helvetica", Type: "Type 1", Encoding: "Standard", ObjectID: "12 0"},
+		{Name: "CAAAAA+NotoSansCJKjp-Regular", Type: "CID Type 0C", Encoding: "Identity-H", Embedded: true, Subset: true, ObjectID: "14 0"},
+		{Name: "[none]", Type: "Type 3", Encoding: "Custom", Embedded: true, ObjectID: "16 0"},
+		{Name: "DAAAAA+AVeryLongFontNameOverflowingItsColumn", Type: "CID TrueType", Encoding: "Identity-H", Embedded: true, Subset: true, Unicode: true, ObjectID: "18 0"},
+	}
+	fonts := parseFonts(pdffontsOutput)
+	if !reflect.DeepEqual(fonts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, fonts)
+	}
+
+	var unreliable []string
+	for _, font := range fonts {
+		if font.UnreliableText() {
+			unreliable = append(unreliable, font.Name)
+		}
+	}
+	if !reflect.DeepEqual(unreliable, []string{"CAAAAA+NotoSansCJKjp-Regular", "[none]"}) {
+		t.Errorf("unexpected fonts with unreliable text %v", unreliable)
+	}
+
+	if fonts := parseFonts("name type encoding emb sub uni object ID\n"); len(fonts) != 0 {
+		t.Errorf("expected no fonts, got %+v", fonts)
+	}
+}
+
+func TestConverter_Fonts(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		gotName, gotArgs = name, args
+		if args[len(args)-1] == "locked.pdf" {
+			io.WriteString(stderr, "Command Line Error: Incorrect password\n")
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, pdffontsOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	fonts, err := converter.Fonts(context.Background(), "input.pdf", &Options{FirstPage: 2, UserPassword: "secret", Layout: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fonts) != 5 {
+		t.Errorf("expected 5 fonts, got %d", len(fonts))
+	}
+	expectedArgs := []string{"-f", "2", "-upw", "secret", "input.pdf"}
+	if gotName != "pdffonts" || !slices.Equal(gotArgs, expectedArgs) {
+		t.Errorf("expected pdffonts %v, got %s %v", expectedArgs, gotName, gotArgs)
+	}
+
+	if _, err := converter.Fonts(context.Background(), "locked.pdf", nil); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("expected error %v, got %v", ErrEncrypted, err)
+	}
+}
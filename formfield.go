@@ -0,0 +1,278 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// FieldType is the type of a form field
+type FieldType string
+
+const (
+	// FieldText is a text field
+	FieldText FieldType = "text"
+	// FieldCheckbox is a check box, whose value is the name of its on state,
+	// usually "Yes", or "Off"
+	FieldCheckbox FieldType = "checkbox"
+	// FieldRadio is a group of radio buttons, whose value is the name of the
+	// selected button, or "Off"
+	FieldRadio FieldType = "radio"
+	// FieldButton is a push button, which has no value
+	FieldButton FieldType = "button"
+	// FieldComboBox is a drop-down list, optionally editable
+	FieldComboBox FieldType = "combo"
+	// FieldListBox is a scrollable list, optionally allowing several
+	// selected values
+	FieldListBox FieldType = "list"
+	// FieldSignature is a signature field
+	FieldSignature FieldType = "signature"
+)
+
+// FormField is a field of the interactive form of a PDF file
+type FormField struct {
+	// Name is the fully qualified name of the field, the names of its
+	// ancestors and its own joined by periods, e.g. "applicant.name"
+	Name string `json:"name"`
+	// Type is the type of the field, empty if it is unknown
+	Type FieldType `json:"type,omitempty"`
+	// Value is the filled value of the field, empty if it is not filled
+	Value string `json:"value,omitempty"`
+	// Values are the selected values of a list box allowing several
+	// selected values, with Value the first of them
+	Values []string `json:"values,omitempty"`
+	// Options are the values offered by a combo or list box
+	Options []string `json:"options,omitempty"`
+	// ReadOnly and Required are the field flags of the same names
+	ReadOnly bool `json:"read_only,omitempty"`
+	Required bool `json:"required,omitempty"`
+	// Page is the number of the page showing the field, 0 if it is not
+	// shown on any page
+	Page int `json:"page,omitempty"`
+}
+
+// Field flags, see the PDF specification, section 12.7.3.1
+const (
+	fieldReadOnly   = 1 << 0
+	fieldRequired   = 1 << 1
+	fieldRadio      = 1 << 15
+	fieldPushbutton = 1 << 16
+	fieldCombo      = 1 << 17
+)
+
+// FormFields returns the fields of the interactive form of a PDF file with
+// their filled values, since filled forms often carry the real data of a
+// document while the page text holds just the labels. It returns an empty
+// slice for documents without a form and ErrEncrypted for encrypted files.
+// XFA forms are not read.
+func (c *Converter) FormFields(ctx context.Context, inputPath string) ([]FormField, error) {
+	if _, err := c.confine(ctx, inputPath, ""); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return parseFormFields(data)
+}
+
+// fieldAttributes are the attributes a form field inherits from its
+// ancestors
+type fieldAttributes struct {
+	name  string
+	ft    pdfName
+	flags int
+	value any
+	opt   any
+}
+
+// parseFormFields reads the form fields of a PDF file
+func parseFormFields(data []byte) ([]FormField, error) {
+	f, catalog, err := readCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// widgets are annotations, so the pages listing them show the fields
+	pages := map[int]int{}
+	number := 0
+	f.walkPageTree(catalog["Pages"], nil, 0, func(page pdfDict, _ []any) {
+		number++
+		annots, err := f.resolve(page["Annots"])
+		if err != nil {
+			return
+		}
+		list, _ := annots.([]any)
+		for _, a := range list {
+			if ref, ok := a.(pdfRef); ok {
+				pages[ref.num] = number
+			}
+		}
+	})
+
+	fields := []FormField{}
+	var walk func(node any, attrs fieldAttributes, depth int)
+	walk = func(node any, attrs fieldAttributes, depth int) {
+		if depth > maxObjectDepth {
+			return
+		}
+		v, err := f.resolve(node)
+		if err != nil {
+			return
+		}
+		dict, ok := v.(pdfDict)
+		if !ok {
+			return
+		}
+		if name := f.textString(dict["T"]); name != "" {
+			if attrs.name != "" {
+				attrs.name += "."
+			}
+			attrs.name += name
+		}
+		if ft, ok := dict["FT"].(pdfName); ok {
+			attrs.ft = ft
+		}
+		if ff, err := f.resolve(dict["Ff"]); err == nil {
+			if ff, ok := ff.(int); ok {
+				attrs.flags = ff
+			}
+		}
+		if dict["V"] != nil {
+			attrs.value = dict["V"]
+		}
+		if dict["Opt"] != nil {
+			attrs.opt = dict["Opt"]
+		}
+
+		// kids with names are fields, kids without are its widgets
+		var widgets []any
+		terminal := true
+		kids, _ := f.resolve(dict["Kids"])
+		list, _ := kids.([]any)
+		for _, kid := range list {
+			if kd, err := f.resolve(kid); err == nil {
+				if kd, ok := kd.(pdfDict); ok && kd["T"] != nil {
+					walk(kid, attrs, depth+1)
+					terminal = false
+					continue
+				}
+			}
+			widgets = append(widgets, kid)
+		}
+		if !terminal {
+			return
+		}
+		if len(list) == 0 {
+			widgets = []any{node}
+		}
+
+		field := f.formField(attrs)
+		for _, w := range widgets {
+			if ref, ok := w.(pdfRef); ok && pages[ref.num] > 0 {
+				field.Page = pages[ref.num]
+				break
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	acroForm, err := f.resolve(catalog["AcroForm"])
+	if err != nil {
+		return fields, nil
+	}
+	form, _ := acroForm.(pdfDict)
+	roots, _ := f.resolve(form["Fields"])
+	list, _ := roots.([]any)
+	for _, root := range list {
+		walk(root, fieldAttributes{}, 0)
+	}
+	return fields, nil
+}
+
+// formField returns the FormField of a terminal field with the given
+// attributes
+func (f *pdfFile) formField(attrs fieldAttributes) FormField {
+	field := FormField{
+		Name:     attrs.name,
+		ReadOnly: attrs.flags&fieldReadOnly != 0,
+		Required: attrs.flags&fieldRequired != 0,
+	}
+	switch attrs.ft {
+	case "Tx":
+		field.Type = FieldText
+	case "Btn":
+		switch {
+		case attrs.flags&fieldPushbutton != 0:
+			field.Type = FieldButton
+		case attrs.flags&fieldRadio != 0:
+			field.Type = FieldRadio
+		default:
+			field.Type = FieldCheckbox
+		}
+	case "Ch":
+		field.Type = FieldListBox
+		if attrs.flags&fieldCombo != 0 {
+			field.Type = FieldComboBox
+		}
+	case "Sig":
+		field.Type = FieldSignature
+	}
+
+	value, err := f.resolve(attrs.value)
+	if err != nil {
+		value = nil
+	}
+	switch value := value.(type) {
+	case string:
+		field.Value = decodeTextString(value)
+	case pdfName:
+		field.Value = string(value)
+	case []any:
+		for _, v := range value {
+			if s := f.nameOrText(v); s != "" {
+				field.Values = append(field.Values, s)
+			}
+		}
+		if len(field.Values) > 0 {
+			field.Value = field.Values[0]
+		}
+	}
+	if field.Type == FieldSignature {
+		// the value of a signature field is the signature dictionary
+		field.Value = ""
+	}
+
+	opt, err := f.resolve(attrs.opt)
+	if err != nil {
+		opt = nil
+	}
+	options, _ := opt.([]any)
+	for _, o := range options {
+		// an option is a text or a pair of an export value and a text
+		if pair, err := f.resolve(o); err == nil {
+			if pair, ok := pair.([]any); ok && len(pair) == 2 {
+				o = pair[1]
+			}
+		}
+		field.Options = append(field.Options, f.nameOrText(o))
+	}
+	return field
+}
+
+// nameOrText resolves v and returns it as a string if it is a name or a text
+// string
+func (f *pdfFile) nameOrText(v any) string {
+	v, err := f.resolve(v)
+	if err != nil {
+		return ""
+	}
+	if name, ok := v.(pdfName); ok {
+		return string(name)
+	}
+	s, _ := v.(string)
+	return decodeTextString(s)
+}
@@ -0,0 +1,104 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// formPDF is a minimal PDF with a filled form: fields merged with their
+// widgets, a field with child fields, a radio group with a widget kid and
+// choice fields
+const formPDF = `%PDF-1.4
+1 0 obj
+<< /Type /Catalog /Pages 2 0 R /AcroForm << /Fields [4 0 R 5 0 R 8 0 R 11 0 R 9 0 R 10 0 R] >> >>
+endobj
+2 0 obj
+<< /Type /Pages /Kids [3 0 R] /Count 1 >>
+endobj
+3 0 obj
+<< /Type /Page /Parent 2 0 R /Annots [4 0 R 6 0 R 7 0 R 8 0 R 12 0 R 10 0 R] >>
+endobj
+4 0 obj
+<< /Type /Annot /Subtype /Widget /FT /Tx /T (name) /V <FEFF005A006F00EB> /Ff 2 >>
+endobj
+5 0 obj
+<< /T (address) /FT /Tx /Kids [6 0 R 7 0 R] >>
+endobj
+6 0 obj
+<< /Type /Annot /Subtype /Widget /Parent 5 0 R /T (street) /V (Main St) >>
+endobj
+7 0 obj
+<< /Type /Annot /Subtype /Widget /Parent 5 0 R /T (city) /Ff 1 >>
+endobj
+8 0 obj
+<< /Type /Annot /Subtype /Widget /FT /Btn /T (agree) /V /Yes >>
+endobj
+9 0 obj
+<< /FT /Ch /Ff 131072 /T (country) /V (de) /Opt [[(de) (Germany)] [(fr) (France)]] >>
+endobj
+10 0 obj
+<< /Type /Annot /Subtype /Widget /FT /Ch /Ff 2097152 /T (topics) /V [(tax) (audit)] /Opt [(tax) (audit) (legal)] >>
+endobj
+11 0 obj
+<< /FT /Btn /Ff 32768 /T (size) /V /M /Kids [12 0 R] >>
+endobj
+12 0 obj
+<< /Type /Annot /Subtype /Widget /Parent 11 0 R /AS /M >>
+endobj
+trailer
+<< /Root 1 0 R /Size 13 >>
+%%EOF
+`
+
+func TestParseFormFields(t *testing.T) {
+	fields, err := parseFormFields([]byte(formPDF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []FormField{
+		{Name: "name", Type: FieldText, Value: "Zoë", Required: true, Page: 1},
+		{Name: "address.street", Type: FieldText, Value: "Main St", Page: 1},
+		{Name: "address.city", Type: FieldText, ReadOnly: true, Page: 1},
+		{Name: "agree", Type: FieldCheckbox, Value: "Yes", Page: 1},
+		{Name: "size", Type: FieldRadio, Value: "M", Page: 1},
+		{Name: "country", Type: FieldComboBox, Value: "de", Options: []string{"Germany", "France"}},
+		{Name: "topics", Type: FieldListBox, Value: "tax", Values: []string{"tax", "audit"}, Options: []string{"tax", "audit", "legal"}, Page: 1},
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %+v, got %+v", expected, fields)
+	}
+
+	fields, err = parseFormFields([]byte(labeledPDF))
+	if err != nil || len(fields) != 0 {
+		t.Errorf("expected no fields, got %+v, %v", fields, err)
+	}
+}
+
+func TestConverter_FormFields(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "form.pdf")
+	if err := os.WriteFile(input, []byte(formPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	converter, err := New()
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	fields, err := converter.FormFields(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fields) != 7 {
+		t.Errorf("expected 7 fields, got %+v", fields)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := converter.FormFields(ctx, input); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package pdftotext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"time"
+)
+
+// FS returns the document as a read-only file system holding one text file
+// per page (page-0001.txt, ...) and a meta.json describing the document, so
+// it can be consumed by anything walking an fs.FS, such as archivers,
+// indexers or http.FileServer.
+func (d *Document) FS() fs.FS {
+	type pageMeta struct {
+		Number int    `json:"number"`
+		File   string `json:"file"`
+		Chars  int    `json:"chars"`
+	}
+	meta := struct {
+		Path      string     `json:"path"`
+		PageCount int        `json:"page_count"`
+		Pages     []pageMeta `json:"pages"`
+	}{Path: d.Path, PageCount: len(d.Pages), Pages: []pageMeta{}}
+
+	files := make(map[string][]byte, len(d.Pages)+1)
+	for _, page := range d.Pages {
+		name := PageFileName(page.Number)
+		files[name] = []byte(page.Text)
+		meta.Pages = append(meta.Pages, pageMeta{Number: page.Number, File: name, Chars: len([]rune(page.Text))})
+	}
+	files["meta.json"], _ = json.MarshalIndent(meta, "", "  ")
+	return memFS(files)
+}
+
+// PageFileName returns the name of the file holding a page in Document.FS
+func PageFileName(number int) string {
+	return fmt.Sprintf("page-%04d.txt", number)
+}
+
+// memFS is a flat read-only in-memory file system
+type memFS map[string][]byte
+
+// Open opens the named file, or the root directory "."
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &memDir{fs: m}, nil
+	}
+	data, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memFile{Reader: bytes.NewReader(data), info: memInfo{name: name, size: int64(len(data))}}, nil
+}
+
+// ReadFile returns the contents of the named file
+func (m memFS) ReadFile(name string) ([]byte, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// ReadDir lists the root directory
+func (m memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return m.entries(), nil
+}
+
+func (m memFS) entries() []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(m))
+	for name, data := range m {
+		entries = append(entries, fs.FileInfoToDirEntry(memInfo{name: name, size: int64(len(data))}))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+// memFile is an open file of a memFS
+type memFile struct {
+	*bytes.Reader
+	info memInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+// memDir is the open root directory of a memFS
+type memDir struct {
+	fs     memFS
+	offset int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return memInfo{name: ".", dir: true}, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: fs.ErrInvalid}
+}
+
+// ReadDir returns the next n entries, or all remaining ones if n <= 0
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := d.fs.entries()[d.offset:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		entries = entries[:min(n, len(entries))]
+	}
+	d.offset += len(entries)
+	return entries, nil
+}
+
+// memInfo describes a file or the root directory of a memFS
+type memInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) ModTime() time.Time { return time.Time{} }
+func (i memInfo) IsDir() bool        { return i.dir }
+func (i memInfo) Sys() any           { return nil }
+
+func (i memInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
@@ -0,0 +1,47 @@
+package pdftotext
+
+import (
+	"encoding/json"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDocument_FS(t *testing.T) {
+	doc := &Document{
+		Path: "input.pdf",
+		Pages: []Page{
+			{Number: 1, Text: "first page\n"},
+			{Number: 2, Text: "second page\n"},
+		},
+	}
+	fsys := doc.FS()
+
+	if err := fstest.TestFS(fsys, "meta.json", "page-0001.txt", "page-0002.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := fs.ReadFile(fsys, "page-0002.txt")
+	if err != nil || string(page) != "second page\n" {
+		t.Errorf("expected %q, got %q (%v)", "second page\n", page, err)
+	}
+
+	data, err := fs.ReadFile(fsys, "meta.json")
+	if err != nil {
+		t.Fatalf("failed to read meta.json: %v", err)
+	}
+	var meta struct {
+		Path      string `json:"path"`
+		PageCount int    `json:"page_count"`
+		Pages     []struct {
+			File  string `json:"file"`
+			Chars int    `json:"chars"`
+		} `json:"pages"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatalf("failed to decode meta.json: %v", err)
+	}
+	if meta.Path != "input.pdf" || meta.PageCount != 2 || meta.Pages[0].File != "page-0001.txt" || meta.Pages[0].Chars != 11 {
+		t.Errorf("unexpected meta.json %s", data)
+	}
+}
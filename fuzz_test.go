@@ -0,0 +1,151 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The fuzz targets feed malformed tool output, such as the truncated output
+// of a killed process, to everything parsing it. Run one with e.g.
+//
+//	go test -fuzz=FuzzConvertOutput
+
+func FuzzParseVersion(f *testing.F) {
+	f.Add("pdftotext version 24.02.0\nCopyright 2005-2024 The Poppler Developers - http://poppler.freedesktop.org\n")
+	f.Add("pdftotext version 4.04 [2022-Apr-18]\nCopyright 1996-2022 Glyph & Cog, LLC\n")
+	f.Add("pdftotext version")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, output string) {
+		version := parseVersion(output)
+		if strings.ContainsAny(version, " \t\n") {
+			t.Errorf("version %q contains whitespace", version)
+		}
+		compareVersions(version, "24.02.0")
+	})
+}
+
+func FuzzParseInfo(f *testing.F) {
+	f.Add(pdfinfoOutput)
+	f.Add("Pages: many\nEncrypted:")
+	f.Add("Title:\n:\n::")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, output string) {
+		info := parseInfo(output)
+		if info.Pages < 0 && info.Raw["Pages"] == "" {
+			t.Errorf("negative page count %d without a Pages field", info.Pages)
+		}
+	})
+}
+
+func FuzzParseBBox(f *testing.F) {
+	f.Add(bboxOutput)
+	f.Add(`<page width="x"><word>a</word></page>`)
+	f.Add(`<word xMin="1">orphan</word>`)
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, output string) {
+		pages, err := parseBBox(strings.NewReader(output), 1)
+		if err != nil {
+			return
+		}
+		for i, page := range pages {
+			if page.Number != i+1 {
+				t.Errorf("page %d numbered %d", i, page.Number)
+			}
+		}
+	})
+}
+
+func FuzzParseTSV(f *testing.F) {
+	f.Add(tsvOutput)
+	f.Add(strings.Join(tsvColumns, "\t") + "\n5\t1\t0\t0\t0\t0\t1\t1\t1\t1\t100\t\n")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, output string) {
+		rows, err := parseTSV(strings.NewReader(output), 1)
+		if err != nil {
+			return
+		}
+		for i, row := range rows {
+			if row.Page < 1 || i > 0 && row.Page < rows[i-1].Page {
+				t.Errorf("row %d on page %d", i, row.Page)
+			}
+		}
+	})
+}
+
+func FuzzParsePageLabels(f *testing.F) {
+	f.Add([]byte(labeledPDF))
+	f.Add([]byte("1 0 obj << /PageLabels 1 0 R >> endobj trailer << /Root 1 0 R >>"))
+	f.Add([]byte("1 0 obj << /Type /ObjStm /N 9 /First 2 /Filter /FlateDecode >> stream\nxx endstream"))
+	f.Add([]byte("trailer << /Root [[[[( >>"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		labels := parsePageLabels(data)
+		for page := 1; page <= 3; page++ {
+			labels.Label(page)
+		}
+	})
+}
+
+func FuzzConvertOutput(f *testing.F) {
+	f.Add("This is a test PDF document.\f", "", 0)
+	f.Add("", "Command Line Error: Incorrect password", 1)
+	f.Add("truncated out", "Syntax Error (1234): Illegal character", -1)
+	f.Add("\f\f\f", "Syntax Warning: Invalid Font Weight\n", 0)
+	f.Add("", "", 99)
+
+	f.Fuzz(func(t *testing.T, stdout, stderr string, code int) {
+		converter, err := New(WithRunner(fakeRun(stdout, stderr, code)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = converter.Convert(context.Background(), "input.pdf", nil)
+		if code == 0 && err != nil {
+			t.Errorf("unexpected error for exit code 0: %v", err)
+		}
+		if code != 0 && err == nil {
+			t.Errorf("expected error for exit code %d", code)
+		}
+		for _, sentinel := range []error{ErrPDFOpen, ErrOutputFile, ErrPermissions, ErrCommandFailed} {
+			if errors.Is(err, sentinel) {
+				return
+			}
+		}
+		if err != nil {
+			t.Errorf("error %v does not match any sentinel error", err)
+		}
+
+		converter.Version(context.Background())
+	})
+}
+
+func FuzzFixture(f *testing.F) {
+	f.Add([]byte(`{"args":["-layout","input.pdf","-"],"stdout":"dGV4dAw=","stderr":"","exit_code":0}`))
+	f.Add([]byte(`{"args":["input.pdf","<output>"],"stdout":null,"stderr":"","exit_code":0,"output":"dGV4dAw="}`))
+	f.Add([]byte(`{"args":[],"exit_code":3`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		path := filepath.Join(t.TempDir(), "fixture.json")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		fx, err := readFixture(path)
+		if err != nil {
+			return
+		}
+		var stdout bytes.Buffer
+		fx.replay(&stdout, io.Discard, filepath.Join(t.TempDir(), "output.txt"))
+	})
+}
@@ -0,0 +1,52 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+)
+
+// GarbledStrategy is a way to extract the text of a garbled page again, e.g.
+// OCR or another PDF library that reads the glyph shapes instead of the
+// broken ToUnicode map
+type GarbledStrategy struct {
+	// Source names the strategy in Page.Source, e.g. SourceOCR or "mupdf"
+	Source PageSource
+	// Extractor extracts the text of a page
+	Extractor OCR
+}
+
+// garbledStrategies returns the strategies re-extracting the garbled pages
+// of opts
+func garbledStrategies(opts *Options) []GarbledStrategy {
+	if len(opts.GarbledStrategies) > 0 {
+		return opts.GarbledStrategies
+	}
+	if opts.OCR != nil {
+		return []GarbledStrategy{{Source: SourceOCR, Extractor: opts.OCR}}
+	}
+	return nil
+}
+
+// reextractGarbled extracts the text of page again with the strategies of
+// opts if it scores below opts.MinQuality, keeping the best scoring text
+func reextractGarbled(ctx context.Context, opts *Options, inputPath string, page *Page) error {
+	if opts.MinQuality <= 0 {
+		return nil
+	}
+	best := ScoreQuality(page.Text).Score
+	for _, strategy := range garbledStrategies(opts) {
+		if best >= opts.MinQuality {
+			break
+		}
+		text, err := strategy.Extractor.RecognizePage(ctx, inputPath, page.Number)
+		if err != nil {
+			return fmt.Errorf("%s of page %d: %w", strategy.Source, page.Number, err)
+		}
+		if score := ScoreQuality(text).Score; score > best {
+			best = score
+			page.Text = text
+			page.Source = strategy.Source
+		}
+	}
+	return nil
+}
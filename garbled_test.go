@@ -0,0 +1,108 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestConverter_GarbledStrategies(t *testing.T) {
+	// page 2 was extracted through a broken ToUnicode map
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "the first page\f§a¤b €c$d ¤¤¤¤ ^e~f\fthe third page\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	extractor := func(text string, calls *[]int) OCR {
+		return OCRFunc(func(ctx context.Context, inputPath string, page int) (string, error) {
+			*calls = append(*calls, page)
+			return text, nil
+		})
+	}
+
+	t.Run("Strategies", func(t *testing.T) {
+		var first, second []int
+		opts := &Options{MinQuality: 0.5, GarbledStrategies: []GarbledStrategy{
+			{Source: "glyphs", Extractor: extractor("^a~b ¤¤¤¤", &first)},
+			{Source: SourceOCR, Extractor: extractor("the second page", &second)},
+		}}
+		pages, err := converter.ConvertPages(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []Page{
+			{Number: 1, Text: "the first page", Source: SourceTextLayer},
+			{Number: 2, Text: "the second page", Source: SourceOCR},
+			{Number: 3, Text: "the third page", Source: SourceTextLayer},
+		}
+		if fmt.Sprint(pages) != fmt.Sprint(expected) {
+			t.Errorf("expected %v, got %v", expected, pages)
+		}
+		if fmt.Sprint(first) != "[2]" || fmt.Sprint(second) != "[2]" {
+			t.Errorf("expected both strategies to be tried on page 2, got %v and %v", first, second)
+		}
+	})
+
+	t.Run("StopsAtGoodText", func(t *testing.T) {
+		var first, second []int
+		opts := &Options{MinQuality: 0.5, GarbledStrategies: []GarbledStrategy{
+			{Source: "glyphs", Extractor: extractor("the second page", &first)},
+			{Source: SourceOCR, Extractor: extractor("the second page", &second)},
+		}}
+		var sources []string
+		for page, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sources = append(sources, string(page.Source))
+		}
+		if strings.Join(sources, ",") != "text,glyphs,text" {
+			t.Errorf("unexpected sources %v", sources)
+		}
+		if len(second) != 0 {
+			t.Errorf("expected the second strategy not to be tried, got %v", second)
+		}
+	})
+
+	t.Run("KeepsBestText", func(t *testing.T) {
+		var calls []int
+		opts := &Options{MinQuality: 0.5, GarbledStrategies: []GarbledStrategy{
+			{Source: "glyphs", Extractor: extractor("¤¤¤¤ ¤¤¤¤ ¤¤¤¤ ¤¤¤¤", &calls)},
+		}}
+		text, err := converter.Convert(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != "the first page\f§a¤b €c$d ¤¤¤¤ ^e~f\fthe third page" {
+			t.Errorf("expected the original text to be kept, got %q", text)
+		}
+	})
+
+	t.Run("DefaultsToOCR", func(t *testing.T) {
+		var calls []int
+		text, err := converter.Convert(ctx, "input.pdf", &Options{MinQuality: 0.5, OCR: extractor("the second page", &calls)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != "the first page\fthe second page\fthe third page" {
+			t.Errorf("unexpected text %q", text)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		failing := OCRFunc(func(ctx context.Context, inputPath string, page int) (string, error) {
+			return "", context.DeadlineExceeded
+		})
+		opts := &Options{MinQuality: 0.5, GarbledStrategies: []GarbledStrategy{{Source: "glyphs", Extractor: failing}}}
+		_, err := converter.ConvertPages(ctx, "input.pdf", opts)
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "glyphs of page 2") {
+			t.Errorf("expected the error of page 2, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+//go:build ignore
+
+// gen_normalize generates normalize_tables.go from the Unicode Character
+// Database. Run it with go generate, or point -ucd at a directory holding
+// UnicodeData.txt and CompositionExclusions.txt to generate offline:
+//
+//	go run gen_normalize.go -version 15.1.0
+//	go run gen_normalize.go -version 15.1.0 -ucd ./ucd
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	version := flag.String("version", unicode.Version, "Unicode version of the tables")
+	ucd := flag.String("ucd", "", "directory with the UCD files, downloaded from unicode.org if empty")
+	output := flag.String("o", "normalize_tables.go", "output file")
+	flag.Parse()
+
+	combining := map[rune]uint8{}
+	mappings := map[rune][]rune{}
+	compat := map[rune]bool{}
+	err := readUCD(*ucd, *version, "UnicodeData.txt", func(fields []string) error {
+		r, err := parseRune(fields[0])
+		if err != nil {
+			return err
+		}
+		if len(fields) < 6 {
+			return fmt.Errorf("%U: missing fields", r)
+		}
+		ccc, err := strconv.ParseUint(fields[3], 10, 8)
+		if err != nil {
+			return err
+		}
+		if ccc != 0 {
+			combining[r] = uint8(ccc)
+		}
+		decomposition := fields[5]
+		if decomposition == "" {
+			return nil
+		}
+		if strings.HasPrefix(decomposition, "<") {
+			compat[r] = true
+			_, decomposition, _ = strings.Cut(decomposition, "> ")
+		}
+		for _, s := range strings.Fields(decomposition) {
+			d, err := parseRune(s)
+			if err != nil {
+				return err
+			}
+			mappings[r] = append(mappings[r], d)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	excluded := map[rune]bool{}
+	err = readUCD(*ucd, *version, "CompositionExclusions.txt", func(fields []string) error {
+		r, err := parseRune(fields[0])
+		excluded[r] = true
+		return err
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// fully decompose every mapping, with canonical mappings only for NFD
+	var decompose func(r rune, withCompat bool) []rune
+	decompose = func(r rune, withCompat bool) []rune {
+		m, ok := mappings[r]
+		if !ok || compat[r] && !withCompat {
+			return []rune{r}
+		}
+		var out []rune
+		for _, d := range m {
+			out = append(out, decompose(d, withCompat)...)
+		}
+		return out
+	}
+
+	canonical := map[rune]string{}
+	compatibility := map[rune]string{}
+	compositions := map[[2]rune]rune{}
+	for r, m := range mappings {
+		if nfd := string(decompose(r, false)); nfd != string(r) {
+			canonical[r] = nfd
+		}
+		if nfkd := string(decompose(r, true)); nfkd != string(r) && nfkd != canonical[r] {
+			compatibility[r] = nfkd
+		}
+		// primary composites, leaving out the full composition exclusions
+		if !compat[r] && len(m) == 2 && !excluded[r] && combining[r] == 0 && combining[m[0]] == 0 {
+			compositions[[2]rune{m[0], m[1]}] = r
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_normalize.go from Unicode %s. DO NOT EDIT.\n\n", *version)
+	b.WriteString("package pdftotext\n\n")
+
+	b.WriteString("// combiningClass is the canonical combining class of the non-starters\n")
+	b.WriteString("var combiningClass = map[rune]uint8{\n")
+	writeEntries(&b, slices.Sorted(maps.Keys(combining)), func(r rune) string {
+		return fmt.Sprintf("%#04x: %d", r, combining[r])
+	})
+	b.WriteString("}\n\n")
+
+	b.WriteString("// canonicalDecompositions are the full canonical decompositions\n")
+	b.WriteString("var canonicalDecompositions = map[rune]string{\n")
+	writeEntries(&b, slices.Sorted(maps.Keys(canonical)), func(r rune) string {
+		return fmt.Sprintf("%#04x: %+q", r, canonical[r])
+	})
+	b.WriteString("}\n\n")
+
+	b.WriteString("// compatibilityDecompositions are the full compatibility decompositions\n")
+	b.WriteString("// differing from the canonical ones\n")
+	b.WriteString("var compatibilityDecompositions = map[rune]string{\n")
+	writeEntries(&b, slices.Sorted(maps.Keys(compatibility)), func(r rune) string {
+		return fmt.Sprintf("%#04x: %+q", r, compatibility[r])
+	})
+	b.WriteString("}\n\n")
+
+	b.WriteString("// compositions are the primary composites by their canonical decomposition\n")
+	b.WriteString("var compositions = map[[2]rune]rune{\n")
+	pairs := slices.SortedFunc(maps.Keys(compositions), func(a, b [2]rune) int {
+		if a[0] != b[0] {
+			return int(a[0] - b[0])
+		}
+		return int(a[1] - b[1])
+	})
+	writeEntries(&b, pairs, func(p [2]rune) string {
+		return fmt.Sprintf("{%#04x, %#04x}: %#04x", p[0], p[1], compositions[p])
+	})
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readUCD calls fn with the fields of each data line of a UCD file
+func readUCD(dir, version, name string, fn func(fields []string) error) error {
+	var r io.Reader
+	if dir != "" {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	} else {
+		resp, err := http.Get("https://www.unicode.org/Public/" + version + "/ucd/" + name)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s: %s", name, resp.Status)
+		}
+		r = resp.Body
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if err := fn(fields); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return sc.Err()
+}
+
+func parseRune(s string) (rune, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	return rune(v), err
+}
+
+// writeEntries writes the map entries four to a line
+func writeEntries[K any](b *bytes.Buffer, keys []K, entry func(K) string) {
+	for i, k := range keys {
+		b.WriteString(entry(k))
+		if i%4 == 3 || i == len(keys)-1 {
+			b.WriteString(",\n")
+		} else {
+			b.WriteString(", ")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package pdftotext
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+)
+
+// HashAlgorithm is the hash function used for content hashes, e.g. of the
+// files recorded in manifests
+type HashAlgorithm struct {
+	// Name identifies the algorithm in manifests and attestations, e.g.
+	// "sha256" or "blake3"
+	Name string
+	// New returns a new hash
+	New func() hash.Hash
+}
+
+// SHA256 is the default HashAlgorithm
+var SHA256 = HashAlgorithm{Name: "sha256", New: sha256.New}
+
+// HMAC returns a HashAlgorithm computing keyed hashes with alg, so content
+// hashes cannot be matched against known documents without the key
+func HMAC(alg HashAlgorithm, key []byte) HashAlgorithm {
+	key = append([]byte(nil), key...)
+	return HashAlgorithm{
+		Name: "hmac-" + alg.Name,
+		New: func() hash.Hash {
+			return hmac.New(alg.New, key)
+		},
+	}
+}
+
+// sha256Hex returns the hex encoded SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// WithHash sets the algorithm used for content hashes (default SHA256)
+func WithHash(alg HashAlgorithm) ConverterOption {
+	return func(c *Converter) {
+		c.hash = alg
+	}
+}
@@ -0,0 +1,67 @@
+package pdftotext
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+)
+
+func TestHMAC(t *testing.T) {
+	key := []byte("secret")
+	alg := HMAC(SHA256, key)
+	if alg.Name != "hmac-sha256" {
+		t.Errorf("expected name %q, got %q", "hmac-sha256", alg.Name)
+	}
+
+	h := alg.New()
+	h.Write([]byte("text"))
+	expected := hmac.New(sha256.New, key)
+	expected.Write([]byte("text"))
+	if !hmac.Equal(h.Sum(nil), expected.Sum(nil)) {
+		t.Error("expected keyed hash to match HMAC-SHA256")
+	}
+
+	key[0] = 'S'
+	h = alg.New()
+	h.Write([]byte("text"))
+	if !hmac.Equal(h.Sum(nil), expected.Sum(nil)) {
+		t.Error("expected the key to be copied")
+	}
+}
+
+func TestConverter_WithHash(t *testing.T) {
+	binary := writeFakeBinary(t, `if [ "$1" = "-v" ]; then
+	echo "pdftotext version 24.02.0" >&2
+	exit 0
+fi
+for arg in "$@"; do out=$arg; done
+printf 'extracted text\f' > "$out"
+`)
+	key := []byte("tenant key")
+	converter, err := New(WithBinaryPath(binary), WithHash(HMAC(SHA256, key)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "output.txt")
+	m, err := converter.ConvertToFileWithManifest(context.Background(), filepath.Join("testdata", "test.pdf"), outputPath, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("extracted text\f"))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if m.HashAlgorithm != "hmac-sha256" || m.Output.Digest != expected {
+		t.Errorf("expected hmac-sha256 digest %s, got %s %s", expected, m.HashAlgorithm, m.Output.Digest)
+	}
+	if m.Output.SHA256 != "" || m.Input.SHA256 != "" {
+		t.Errorf("expected no SHA-256 hashes, got %+v %+v", m.Input, m.Output)
+	}
+	if digest := NewStatement(m).Subject[0].Digest; digest["hmac-sha256"] != expected {
+		t.Errorf("expected statement digest %s, got %v", expected, digest)
+	}
+}
@@ -0,0 +1,68 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+)
+
+// healthCheckText is the text of the PDF converted by HealthCheck
+const healthCheckText = "pdftotext health check"
+
+// HealthCheck converts a tiny PDF generated in memory and verifies the text,
+// for the readiness probes of services embedding this package. It catches a
+// missing or broken binary, an unwritable temporary directory and missing
+// language data at startup rather than on user traffic.
+func (c *Converter) HealthCheck(ctx context.Context) error {
+	f, err := c.createTemp("pdftotext-health-*.pdf")
+	if err != nil {
+		return fmt.Errorf("health check: failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(minimalPDF(healthCheckText))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("health check: failed to write temporary file: %w", err)
+	}
+
+	text, err := c.Convert(trusted(ctx), f.Name(), &Options{RequireLanguageData: true})
+	if err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+	if text != healthCheckText {
+		return fmt.Errorf("%w: health check converted %q, expected %q", ErrCommandFailed, text, healthCheckText)
+	}
+	return nil
+}
+
+// minimalPDF returns a single page PDF showing text in Helvetica, which text
+// extraction needs no font files for. text must not contain parentheses or
+// backslashes.
+func minimalPDF(text string) []byte {
+	content := fmt.Sprintf("BT /F1 12 Tf 20 50 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 300 100] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
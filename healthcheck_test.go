@@ -0,0 +1,69 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestMinimalPDF(t *testing.T) {
+	data := minimalPDF(healthCheckText)
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+		t.Errorf("expected a PDF header, got %q", data[:10])
+	}
+	f, catalog, err := readCatalog(data)
+	if err != nil {
+		t.Fatalf("failed to read catalog: %v", err)
+	}
+	var pages int
+	f.walkPageTree(catalog["Pages"], nil, 0, func(page pdfDict, mediaBox []any) {
+		pages++
+	})
+	if pages != 1 {
+		t.Errorf("expected 1 page, got %d", pages)
+	}
+	if !bytes.Contains(data, []byte("("+healthCheckText+") Tj")) {
+		t.Error("expected the page to show the health check text")
+	}
+}
+
+func TestConverter_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		runErr   error
+		expected error
+	}{
+		{name: "healthy", output: healthCheckText + "\n\f"},
+		{name: "wrong text", output: "garbage\f", expected: ErrCommandFailed},
+		{name: "failing binary", runErr: &ExitError{Code: 99}, expected: ErrCommandFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+				data, err := os.ReadFile(args[len(args)-2])
+				if err != nil || !bytes.Equal(data, minimalPDF(healthCheckText)) {
+					t.Errorf("expected the health check PDF, got %v", err)
+				}
+				if tt.runErr != nil {
+					return tt.runErr
+				}
+				_, err = io.WriteString(stdout, tt.output)
+				return err
+			})))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			err = converter.HealthCheck(context.Background())
+			if tt.expected == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
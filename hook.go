@@ -0,0 +1,85 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PreHook is called with the input file before it is converted, e.g. to scan
+// it for viruses, log an audit record or check a quota. Returning an error
+// vetoes the conversion.
+type PreHook func(ctx context.Context, inputPath string) error
+
+// PostHook is called with the result of a conversion before it is returned
+// or written, e.g. to record an audit trail, redact personal data or emit
+// metrics. It may replace result.Text. Returning an error fails the
+// conversion.
+type PostHook func(ctx context.Context, result *ConversionResult) error
+
+// ConversionResult is the result of a conversion passed to the PostHooks
+type ConversionResult struct {
+	// InputPath is the converted PDF file
+	InputPath string
+	// OutputPath is the output file, or "-" when the text is returned
+	OutputPath string
+	// Text is the converted text after the normalization, the Transformers
+	// and the PageSeparator were applied
+	Text string
+	// Warnings are the messages pdftotext printed to stderr
+	Warnings []Warning
+	// Duration is how long the conversion took
+	Duration time.Duration
+}
+
+// runPreHooks calls the pre hooks of opts in order and returns opts without
+// them, so the conversions a conversion is made of do not call them again.
+// The first error stops the conversion and is returned wrapped in ErrVetoed.
+func runPreHooks(ctx context.Context, inputPath string, opts *Options) (*Options, error) {
+	if opts == nil || len(opts.PreHooks) == 0 {
+		return opts, nil
+	}
+	for _, hook := range opts.PreHooks {
+		if err := hook(ctx, inputPath); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrVetoed, err)
+		}
+	}
+	hookless := *opts
+	hookless.PreHooks = nil
+	return &hookless, nil
+}
+
+// runPostHooked converts the pages selected by opts into memory, passes the
+// result through opts.PostHooks and then writes the text they leave
+func (c *Converter) runPostHooked(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	result := &ConversionResult{InputPath: inputPath, OutputPath: outputPath}
+	start := time.Now()
+	hookless, warnings := collectWarnings(opts)
+	hookless.PostHooks = nil
+	var buf bytes.Buffer
+	if err := c.run(ctx, inputPath, "-", hookless, &buf); err != nil {
+		return err
+	}
+	result.Text = buf.String()
+	result.Warnings = warnings()
+	result.Duration = time.Since(start)
+	if err := runPostHooks(ctx, opts.PostHooks, result); err != nil {
+		return err
+	}
+	return writeOutput(outputPath, stdout, func(w io.Writer) error {
+		_, err := io.WriteString(w, result.Text)
+		return err
+	})
+}
+
+// runPostHooks calls hooks in order, stopping at the first error
+func runPostHooks(ctx context.Context, hooks []PostHook, result *ConversionResult) error {
+	for _, hook := range hooks {
+		if err := hook(ctx, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConverter_PreHooks(t *testing.T) {
+	var runs int
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		runs++
+		_, err := io.WriteString(stdout, "page\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("veto", func(t *testing.T) {
+		runs = 0
+		infected := errors.New("infected")
+		var calls []string
+		opts := &Options{PreHooks: []PreHook{
+			func(ctx context.Context, inputPath string) error {
+				calls = append(calls, "scan "+inputPath)
+				return infected
+			},
+			func(ctx context.Context, inputPath string) error {
+				calls = append(calls, "audit "+inputPath)
+				return nil
+			},
+		}}
+		_, err := converter.Convert(ctx, "input.pdf", opts)
+		if !errors.Is(err, ErrVetoed) || !errors.Is(err, infected) {
+			t.Errorf("expected ErrVetoed wrapping the hook error, got %v", err)
+		}
+		if len(calls) != 1 || calls[0] != "scan input.pdf" {
+			t.Errorf("expected only the first hook to be called, got %v", calls)
+		}
+		if runs != 0 {
+			t.Errorf("expected no runs, got %d", runs)
+		}
+	})
+
+	t.Run("once per conversion", func(t *testing.T) {
+		runs = 0
+		var calls int
+		opts := &Options{
+			Pages: "1,3",
+			PreHooks: []PreHook{func(ctx context.Context, inputPath string) error {
+				calls++
+				return nil
+			}},
+		}
+		if _, err := converter.Convert(ctx, "input.pdf", opts); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		if _, err := converter.ConvertPages(ctx, "input.pdf", opts); err != nil {
+			t.Fatalf("failed to convert pages: %v", err)
+		}
+		for _, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+			if err != nil {
+				t.Fatalf("failed to stream pages: %v", err)
+			}
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 calls, got %d", calls)
+		}
+		if runs != 6 {
+			t.Errorf("expected 6 runs, got %d", runs)
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		opts := &Options{PreHooks: []PreHook{func(ctx context.Context, inputPath string) error { return nil }}}
+		if _, err := converter.Command("input.pdf", "-", opts); !errors.Is(err, ErrCommandFailed) {
+			t.Errorf("expected ErrCommandFailed, got %v", err)
+		}
+	})
+}
+
+func TestConverter_PostHooks(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Syntax Warning: bad xref\n")
+		_, err := io.WriteString(stdout, "mail jane@example.com\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	var seen ConversionResult
+	opts := &Options{
+		Transformers: []Transformer{TransformerFunc(func(page int, text string) (string, error) {
+			return strings.ToUpper(text), nil
+		})},
+		PostHooks: []PostHook{
+			func(ctx context.Context, result *ConversionResult) error {
+				result.Text = strings.ReplaceAll(result.Text, "JANE@EXAMPLE.COM", "[email]")
+				return nil
+			},
+			func(ctx context.Context, result *ConversionResult) error {
+				seen = *result
+				return nil
+			},
+		},
+	}
+
+	t.Run("convert", func(t *testing.T) {
+		text, err := converter.Convert(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		if text != "MAIL [email]" {
+			t.Errorf("expected the redacted text, got %q", text)
+		}
+		if seen.InputPath != "input.pdf" || seen.OutputPath != "-" || seen.Text != "MAIL [email]\f" {
+			t.Errorf("unexpected result %+v", seen)
+		}
+		expected := Warning{Category: "Syntax Warning", Offset: -1, Message: "bad xref"}
+		if len(seen.Warnings) != 1 || seen.Warnings[0] != expected {
+			t.Errorf("expected the warning, got %+v", seen.Warnings)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "output.txt")
+		if err := converter.ConvertToFile(ctx, "input.pdf", output, opts); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		if string(data) != "MAIL [email]\f" || seen.OutputPath != output {
+			t.Errorf("expected the redacted text in %s, got %q", seen.OutputPath, data)
+		}
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		var calls int
+		opts := &Options{FirstPage: 1, LastPage: 2, PostHooks: []PostHook{func(ctx context.Context, result *ConversionResult) error {
+			calls++
+			return nil
+		}}}
+		if _, err := converter.ConvertParallel(ctx, "input.pdf", 2, opts); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		failed := errors.New("audit log unavailable")
+		output := filepath.Join(t.TempDir(), "output.txt")
+		opts := &Options{PostHooks: []PostHook{func(ctx context.Context, result *ConversionResult) error {
+			return failed
+		}}}
+		if err := converter.ConvertToFile(ctx, "input.pdf", output, opts); !errors.Is(err, failed) {
+			t.Errorf("expected the hook error, got %v", err)
+		}
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Errorf("expected no output file, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// HTMLMeta is the document information pdftotext -htmlmeta writes to the
+// head of its HTML output
+type HTMLMeta struct {
+	// Title is the document title
+	Title string `json:"title,omitempty"`
+	// Author is the document author
+	Author string `json:"author,omitempty"`
+	// Subject is the document subject
+	Subject string `json:"subject,omitempty"`
+	// Keywords are the document keywords
+	Keywords string `json:"keywords,omitempty"`
+	// Creator is the application that created the original document
+	Creator string `json:"creator,omitempty"`
+	// Producer is the application that produced the PDF
+	Producer string `json:"producer,omitempty"`
+	// CreationDate and ModDate are the raw dates, which pdftotext writes
+	// without the "D:" prefix
+	CreationDate string `json:"creation_date,omitempty"`
+	ModDate      string `json:"mod_date,omitempty"`
+	// CreationTime and ModTime are CreationDate and ModDate parsed with
+	// ParseDate, nil if they are missing or cannot be parsed
+	CreationTime *time.Time `json:"creation_time,omitempty"`
+	ModTime      *time.Time `json:"mod_time,omitempty"`
+	// Raw holds the content of every meta element by name
+	Raw map[string]string `json:"raw,omitempty"`
+}
+
+var (
+	htmlTitle = regexp.MustCompile(`(?s)<title>(.*?)</title>`)
+	htmlMetas = regexp.MustCompile(`<meta name="([^"]*)" content="([^"]*)"\s*/?>`)
+)
+
+// ConvertHTMLMeta converts a PDF file with -htmlmeta and returns the document
+// information of the HTML head and the text of the body, instead of the HTML
+// itself. Pages selected with several ranges are converted range by range,
+// with the information of the first. Output options such as BBox and TSV and
+// the text post-processing options are ignored.
+func (c *Converter) ConvertHTMLMeta(ctx context.Context, inputPath string, opts *Options) (*HTMLMeta, string, error) {
+	metaOpts := Options{}
+	if opts != nil {
+		metaOpts = *opts
+	}
+	metaOpts.HTMLMeta, metaOpts.BBox, metaOpts.BBoxLayout, metaOpts.TSV = true, false, false, false
+	metaOpts.PageSeparator = ""
+	metaOpts.Transformers = nil
+	metaOpts.Normalize = ""
+	metaOpts.PostHooks = nil
+	metaOpts.ReadingOrder = false
+
+	hookOpts, err := runPreHooks(ctx, inputPath, &metaOpts)
+	if err != nil {
+		return nil, "", err
+	}
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, hookOpts)
+	if err != nil {
+		return nil, "", err
+	}
+	var meta *HTMLMeta
+	var text strings.Builder
+	for _, o := range rangeOpts {
+		var stdout bytes.Buffer
+		if err := c.run(ctx, inputPath, "-", o, &stdout); err != nil {
+			return nil, "", err
+		}
+		rangeMeta, rangeText, err := ParseHTMLMeta(stdout.String())
+		if err != nil {
+			return nil, "", err
+		}
+		if meta == nil {
+			meta = rangeMeta
+		}
+		text.WriteString(rangeText)
+	}
+	return meta, text.String(), nil
+}
+
+// ParseHTMLMeta splits the output of pdftotext -htmlmeta into the document
+// information of the head and the text of the body. pdftotext escapes the
+// information but writes the text as extracted, so only the information is
+// unescaped. It returns ErrCommandFailed if output has no head or no body.
+func ParseHTMLMeta(output string) (*HTMLMeta, string, error) {
+	head, body, ok := strings.Cut(output, "</head>")
+	if !ok {
+		return nil, "", fmt.Errorf("%w: no head in the -htmlmeta output", ErrCommandFailed)
+	}
+	_, text, ok := strings.Cut(body, "<pre>\n")
+	if !ok {
+		return nil, "", fmt.Errorf("%w: no body text in the -htmlmeta output", ErrCommandFailed)
+	}
+	if i := strings.LastIndex(text, "</pre>"); i >= 0 {
+		text = text[:i]
+	}
+
+	meta := &HTMLMeta{Raw: make(map[string]string)}
+	if m := htmlTitle.FindStringSubmatch(head); m != nil {
+		meta.Title = html.UnescapeString(m[1])
+	}
+	for _, m := range htmlMetas.FindAllStringSubmatch(head, -1) {
+		name, content := m[1], html.UnescapeString(m[2])
+		meta.Raw[name] = content
+		switch name {
+		case "Author":
+			meta.Author = content
+		case "Subject":
+			meta.Subject = content
+		case "Keywords":
+			meta.Keywords = content
+		case "Creator":
+			meta.Creator = content
+		case "Producer":
+			meta.Producer = content
+		case "CreationDate":
+			meta.CreationDate = content
+			meta.CreationTime = parseDatePtr(content)
+		case "ModDate":
+			meta.ModDate = content
+			meta.ModTime = parseDatePtr(content)
+		}
+	}
+	return meta, text, nil
+}
@@ -0,0 +1,74 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+const htmlMetaOutput = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<title>Q3 Report &amp; Outlook</title>
+<meta name="Author" content="Jane &quot;JD&quot; Doe"/>
+<meta name="Creator" content="Writer"/>
+<meta name="Producer" content="LibreOffice 7.6"/>
+<meta name="CreationDate" content="20240301120000+01'00'"/>
+</head>
+<body>
+<pre>
+Revenue <up> & costs
+` + "\f" + `Page two
+</pre>
+</body>
+</html>
+`
+
+func TestParseHTMLMeta(t *testing.T) {
+	meta, text, err := ParseHTMLMeta(htmlMetaOutput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.Title != "Q3 Report & Outlook" || meta.Author != `Jane "JD" Doe` || meta.Producer != "LibreOffice 7.6" || meta.Raw["Creator"] != "Writer" {
+		t.Errorf("unexpected meta %+v", meta)
+	}
+	want := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+	if meta.CreationTime == nil || !meta.CreationTime.Equal(want) || meta.ModTime != nil {
+		t.Errorf("expected creation time %v and no mod time, got %v, %v", want, meta.CreationTime, meta.ModTime)
+	}
+	if text != "Revenue <up> & costs\n\fPage two\n" {
+		t.Errorf("unexpected text %q", text)
+	}
+
+	for _, output := range []string{"", "Revenue", "<html><head></head><body></body></html>"} {
+		if _, _, err := ParseHTMLMeta(output); !errors.Is(err, ErrCommandFailed) {
+			t.Errorf("expected ErrCommandFailed for %q, got %v", output, err)
+		}
+	}
+}
+
+func TestConverter_ConvertHTMLMeta(t *testing.T) {
+	var calls [][]string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		calls = append(calls, args)
+		_, err := io.WriteString(stdout, htmlMetaOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	meta, text, err := converter.ConvertHTMLMeta(context.Background(), "input.pdf", &Options{BBox: true, PageSeparator: "---"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.Title != "Q3 Report & Outlook" || text != "Revenue <up> & costs\n\fPage two\n" {
+		t.Errorf("unexpected result %+v, %q", meta, text)
+	}
+	if len(calls) != 1 || !slices.Contains(calls[0], "-htmlmeta") || slices.Contains(calls[0], "-bbox") {
+		t.Errorf("expected a single -htmlmeta run without -bbox, got %v", calls)
+	}
+}
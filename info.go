@@ -0,0 +1,221 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Info is the document information reported by pdfinfo
+type Info struct {
+	// Title is the document title
+	Title string `json:"title,omitempty"`
+	// Author is the document author
+	Author string `json:"author,omitempty"`
+	// Subject is the document subject
+	Subject string `json:"subject,omitempty"`
+	// Keywords are the document keywords
+	Keywords string `json:"keywords,omitempty"`
+	// Creator is the application that created the original document
+	Creator string `json:"creator,omitempty"`
+	// Producer is the application that produced the PDF
+	Producer string `json:"producer,omitempty"`
+	// CreationDate is the raw PDF creation date (D:YYYYMMDDHHmmSS+HH'mm')
+	CreationDate string `json:"creation_date,omitempty"`
+	// ModDate is the raw PDF modification date
+	ModDate string `json:"mod_date,omitempty"`
+	// CreationTime and ModTime are CreationDate and ModDate parsed with
+	// ParseDate, nil if they are missing or cannot be parsed
+	CreationTime *time.Time `json:"creation_time,omitempty"`
+	ModTime      *time.Time `json:"mod_time,omitempty"`
+	// Pages is the number of pages
+	Pages int `json:"pages"`
+	// Encrypted reports whether the PDF is encrypted
+	Encrypted bool `json:"encrypted"`
+	// PageSize is the size of the first page, e.g. "612 x 792 pts (letter)"
+	PageSize string `json:"page_size,omitempty"`
+	// PDFVersion is the PDF version, e.g. "1.7"
+	PDFVersion string `json:"pdf_version,omitempty"`
+	// Subtype is the ISO standard the document claims to conform to, e.g.
+	// "PDF/A-2b" or "PDF/UA-1", empty if it claims none
+	Subtype string `json:"subtype,omitempty"`
+	// PDFA is the PDF/A conformance the document claims, nil if it is not
+	// a PDF/A document
+	PDFA *PDFAConformance `json:"pdfa,omitempty"`
+	// Raw holds every field reported by pdfinfo
+	Raw map[string]string `json:"raw,omitempty"`
+}
+
+// Info returns the document information of a PDF file using pdfinfo. Only the
+// password options of opts are used. The PDF/A conformance is read from the
+// XMP metadata with a second pdfinfo run when pdfinfo is too old to report
+// the PDF subtype.
+func (c *Converter) Info(ctx context.Context, inputPath string, opts *Options) (*Info, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"-rawdates"}, passwordArgs(opts)...)
+	args = append(args, escapePath(inputPath))
+
+	var stdout bytes.Buffer
+	stderr := c.newStderr()
+	if err := c.runCommand(ctx, c.toolPath("pdfinfo"), args, &stdout, stderr); err != nil {
+		return nil, c.handleError(err, stderr.String())
+	}
+	info := parseInfo(stdout.String())
+	if _, ok := info.Raw["PDF subtype"]; !ok && info.Raw["Metadata"] == "yes" {
+		pdfa, err := c.xmpConformance(ctx, inputPath, opts)
+		if err != nil {
+			return nil, err
+		}
+		if info.PDFA = pdfa; pdfa != nil {
+			info.Subtype = pdfa.String()
+		}
+	}
+	return info, nil
+}
+
+// passwordArgs returns the password flags of opts for the poppler tools
+func passwordArgs(opts *Options) []string {
+	var args []string
+	if opts != nil {
+		if opts.OwnerPassword != "" {
+			args = append(args, "-opw", opts.OwnerPassword)
+		}
+		if opts.UserPassword != "" {
+			args = append(args, "-upw", opts.UserPassword)
+		}
+	}
+	return args
+}
+
+// PageCount returns the number of pages of a PDF file using pdfinfo
+func (c *Converter) PageCount(ctx context.Context, inputPath string, opts *Options) (int, error) {
+	info, err := c.Info(ctx, inputPath, opts)
+	if err != nil {
+		return 0, err
+	}
+	return info.Pages, nil
+}
+
+// Metadata returns the document information of a PDF file using pdfinfo, as
+// Info.Metadata returns it
+func (c *Converter) Metadata(ctx context.Context, inputPath string, opts *Options) (map[string]string, error) {
+	info, err := c.Info(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return info.Metadata(), nil
+}
+
+// Metadata returns the title, author, subject, keywords, creator, producer,
+// creation_date and mod_date of info that are not empty, the keys of the
+// metadata of a Document or a PageDocument
+func (info *Info) Metadata() map[string]string {
+	metadata := make(map[string]string)
+	for key, value := range map[string]string{
+		"title":         info.Title,
+		"author":        info.Author,
+		"subject":       info.Subject,
+		"keywords":      info.Keywords,
+		"creator":       info.Creator,
+		"producer":      info.Producer,
+		"creation_date": info.CreationDate,
+		"mod_date":      info.ModDate,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	return metadata
+}
+
+// toolPath returns the path of a poppler companion tool such as pdfinfo,
+// preferring the one installed next to the pdftotext binary
+func (c *Converter) toolPath(name string) string {
+	if _, ok := c.runner.(ExecRunner); !ok {
+		return name
+	}
+	if filepath.IsAbs(c.binaryPath) {
+		sibling := filepath.Join(filepath.Dir(c.binaryPath), name+filepath.Ext(c.binaryPath))
+		if path, err := exec.LookPath(sibling); err == nil {
+			return path
+		}
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	return name
+}
+
+// parseInfo parses the "Key: value" lines printed by pdfinfo. Indented lines
+// describe the field above them, like the "Title" of the standard under "PDF
+// subtype", and are kept in Raw only, prefixed with the key of that field.
+func parseInfo(output string) *Info {
+	info := &Info{Raw: make(map[string]string)}
+	var parent string
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		indented := strings.TrimLeft(key, " \t") != key
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if indented && parent != "" {
+			info.Raw[parent+" "+key] = value
+			continue
+		}
+		parent = key
+		info.Raw[key] = value
+
+		switch key {
+		case "Title":
+			info.Title = value
+		case "Author":
+			info.Author = value
+		case "Subject":
+			info.Subject = value
+		case "Keywords":
+			info.Keywords = value
+		case "Creator":
+			info.Creator = value
+		case "Producer":
+			info.Producer = value
+		case "CreationDate":
+			info.CreationDate = value
+			info.CreationTime = parseDatePtr(value)
+		case "ModDate":
+			info.ModDate = value
+			info.ModTime = parseDatePtr(value)
+		case "Pages":
+			info.Pages, _ = strconv.Atoi(value)
+		case "Encrypted":
+			info.Encrypted = strings.HasPrefix(value, "yes")
+		case "Page size":
+			info.PageSize = value
+		case "PDF version":
+			info.PDFVersion = value
+		case "PDF subtype":
+			if value != "None" {
+				info.Subtype = value
+				info.PDFA = parsePDFASubtype(value)
+			}
+		}
+	}
+	return info
+}
+
+// parseDatePtr parses a PDF date, returning nil if it cannot be parsed
+func parseDatePtr(value string) *time.Time {
+	t, err := ParseDate(value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
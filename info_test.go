@@ -0,0 +1,127 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const pdfinfoOutput = `Title:           Annual Report
+Author:          Jane Doe
+Creator:         Writer
+Producer:        LibreOffice 7.5
+CreationDate:    D:20240131120000+01'00'
+ModDate:         D:20240201093000Z
+Custom Metadata: no
+Tagged:          no
+Pages:           12
+Encrypted:       yes (print:yes copy:no change:no addNotes:no algorithm:AES-256)
+Page size:       595.276 x 841.89 pts (A4)
+Page rot:        0
+File size:       123456 bytes
+PDF version:     1.7
+`
+
+func TestConverter_Info(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		gotName, gotArgs = name, args
+		if args[len(args)-1] == "missing.pdf" {
+			io.WriteString(stderr, "I/O Error: Couldn't open file 'missing.pdf'")
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, pdfinfoOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	info, err := converter.Info(context.Background(), "input.pdf", &Options{UserPassword: "secret", Layout: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotName != "pdfinfo" {
+		t.Errorf("expected pdfinfo to run, got %q", gotName)
+	}
+	if expected := []string{"-rawdates", "-upw", "secret", "input.pdf"}; !slices.Equal(gotArgs, expected) {
+		t.Errorf("expected args %v, got %v", expected, gotArgs)
+	}
+
+	created := time.Date(2024, 1, 31, 12, 0, 0, 0, time.FixedZone("", 3600))
+	modified := time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC)
+	expected := Info{
+		Title:        "Annual Report",
+		Author:       "Jane Doe",
+		Creator:      "Writer",
+		Producer:     "LibreOffice 7.5",
+		CreationDate: "D:20240131120000+01'00'",
+		ModDate:      "D:20240201093000Z",
+		CreationTime: &created,
+		ModTime:      &modified,
+		Pages:        12,
+		Encrypted:    true,
+		PageSize:     "595.276 x 841.89 pts (A4)",
+		PDFVersion:   "1.7",
+	}
+	got := *info
+	got.Raw = nil
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	if info.Raw["File size"] != "123456 bytes" {
+		t.Errorf("expected raw fields, got %v", info.Raw)
+	}
+
+	// pdfinfo ends lines with CRLF on Windows
+	crlf := parseInfo(strings.ReplaceAll(pdfinfoOutput, "\n", "\r\n"))
+	crlf.Raw = nil
+	if !reflect.DeepEqual(*crlf, expected) {
+		t.Errorf("expected %+v from CRLF output, got %+v", expected, *crlf)
+	}
+
+	if _, err := converter.PageCount(context.Background(), "missing.pdf", nil); !errors.Is(err, ErrPDFOpen) {
+		t.Errorf("expected error %v, got %v", ErrPDFOpen, err)
+	}
+}
+
+func TestConverter_Metadata(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if args[len(args)-1] == "missing.pdf" {
+			io.WriteString(stderr, "I/O Error: Couldn't open file 'missing.pdf'")
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, pdfinfoOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	metadata, err := converter.Metadata(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"title":         "Annual Report",
+		"author":        "Jane Doe",
+		"creator":       "Writer",
+		"producer":      "LibreOffice 7.5",
+		"creation_date": "D:20240131120000+01'00'",
+		"mod_date":      "D:20240201093000Z",
+	}
+	if !maps.Equal(metadata, expected) {
+		t.Errorf("expected %v, got %v", expected, metadata)
+	}
+
+	if _, err := converter.Metadata(context.Background(), "missing.pdf", nil); !errors.Is(err, ErrPDFOpen) {
+		t.Errorf("expected error %v, got %v", ErrPDFOpen, err)
+	}
+}
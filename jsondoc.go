@@ -0,0 +1,82 @@
+package pdftotext
+
+import (
+	"context"
+	"strings"
+)
+
+// JSONSchemaVersion is the version of the JSONDocument schema. It is
+// incremented when a field is renamed, removed or changes meaning, not when a
+// field is added.
+const JSONSchemaVersion = 1
+
+// JSONDocument is the metadata, text and layout of a PDF file in one document
+// with a stable JSON encoding, for consumers outside of Go
+type JSONDocument struct {
+	// SchemaVersion is JSONSchemaVersion
+	SchemaVersion int `json:"schema_version"`
+	// Path is the path of the PDF file
+	Path string `json:"path"`
+	// Info is the document information reported by pdfinfo
+	Info *Info `json:"info"`
+	// Pages are the converted pages in order
+	Pages []JSONPage `json:"pages"`
+}
+
+// JSONPage is a page of a JSONDocument
+type JSONPage struct {
+	// Number is the page number in the PDF file
+	Number int `json:"number"`
+	// Width is the page width in points
+	Width float64 `json:"width"`
+	// Height is the page height in points
+	Height float64 `json:"height"`
+	// Text is the text of the page, with the lines of each block separated
+	// by newlines and the blocks by blank lines
+	Text string `json:"text"`
+	// Blocks are the blocks of the page with their lines
+	Blocks []Block `json:"blocks"`
+	// Words are the words of the page with their bounding boxes
+	Words []Word `json:"words"`
+}
+
+// ConvertJSON returns the document information of a PDF file from pdfinfo
+// and its blocks, lines and words from -bbox-layout as a JSONDocument.
+// Transformers and output options such as Layout and Raw are ignored.
+func (c *Converter) ConvertJSON(ctx context.Context, inputPath string, opts *Options) (*JSONDocument, error) {
+	info, err := c.Info(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := c.geometry(ctx, inputPath, opts, true)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &JSONDocument{SchemaVersion: JSONSchemaVersion, Path: inputPath, Info: info, Pages: make([]JSONPage, len(pages))}
+	for i, page := range pages {
+		blocks := make([]string, len(page.Blocks))
+		for j, block := range page.Blocks {
+			lines := make([]string, len(block.Lines))
+			for k, line := range block.Lines {
+				lines[k] = line.Text
+			}
+			blocks[j] = strings.Join(lines, "\n")
+		}
+		doc.Pages[i] = JSONPage{
+			Number: page.Number,
+			Width:  page.Width,
+			Height: page.Height,
+			Text:   strings.Join(blocks, "\n\n"),
+			Blocks: page.Blocks,
+			Words:  page.Words,
+		}
+		if doc.Pages[i].Blocks == nil {
+			doc.Pages[i].Blocks = []Block{}
+		}
+		if doc.Pages[i].Words == nil {
+			doc.Pages[i].Words = []Word{}
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,54 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestConverter_ConvertJSON(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		output := blockLayoutOutput(paperPages)
+		if name == "pdfinfo" {
+			output = pdfinfoOutput
+		}
+		_, err := io.WriteString(stdout, output)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	doc, err := converter.ConvertJSON(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.SchemaVersion != JSONSchemaVersion || doc.Path != "input.pdf" || doc.Info.Title != "Annual Report" {
+		t.Errorf("unexpected document %+v", doc)
+	}
+	if len(doc.Pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(doc.Pages))
+	}
+	page := doc.Pages[1]
+	if page.Number != 2 || page.Width != 612 || len(page.Blocks) != 3 || len(page.Words) != 15 {
+		t.Errorf("unexpected page %+v", page)
+	}
+	if expected := "2 Results\n\nWe found many things, all of\nthem interesting in some way.\n\nPage 2"; page.Text != expected {
+		t.Errorf("expected text %q, got %q", expected, page.Text)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{"schema_version", "path", "info", "pages"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("expected key %q in %s", key, data)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// JSONLRecord is the JSON object written by ConvertJSONL for each page
+type JSONLRecord struct {
+	// Path is the path of the PDF file
+	Path string `json:"path"`
+	// Page is the page number in the PDF file
+	Page int `json:"page"`
+	// Label is the page label, when requested with Options.PageLabels
+	Label string `json:"label,omitempty"`
+	// Text is the extracted text of the page
+	Text string `json:"text"`
+	// Language is the language of the page, when detected with
+	// Options.LanguageDetector
+	Language string `json:"language,omitempty"`
+	// Entities are the named entities of the page, when extracted with
+	// Options.EntityExtractor
+	Entities []Entity `json:"entities,omitempty"`
+	// Chars is the number of characters of Text
+	Chars int `json:"chars"`
+	// Warnings are the messages pdftotext printed while converting the page
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ConvertJSONL converts a PDF file page by page and writes a JSONLRecord per
+// page to w as JSON Lines, for loading into data pipelines. A warning is
+// attributed to the first page not yet written when pdftotext prints it, so
+// warnings printed after the last page belong to the last page. Pages are
+// written as they are converted, so w holds the pages before the error if
+// the conversion fails.
+func (c *Converter) ConvertJSONL(ctx context.Context, inputPath string, opts *Options, w io.Writer) error {
+	var mu sync.Mutex
+	var warnings []string
+	jsonlOpts := Options{}
+	if opts != nil {
+		jsonlOpts = *opts
+	}
+	onWarning := jsonlOpts.OnWarning
+	jsonlOpts.OnWarning = func(message string) {
+		if onWarning != nil {
+			onWarning(message)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		warnings = append(warnings, message)
+	}
+	takeWarnings := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		taken := warnings
+		warnings = nil
+		return taken
+	}
+
+	// each page is held back until the next one so that it gets the
+	// warnings printed after it, if it is the last one
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	var pending *JSONLRecord
+	for page, err := range c.StreamPages(ctx, inputPath, &jsonlOpts) {
+		if err != nil {
+			if pending != nil {
+				if err := enc.Encode(pending); err != nil {
+					return err
+				}
+			}
+			return err
+		}
+		if pending != nil {
+			if err := enc.Encode(pending); err != nil {
+				return err
+			}
+		}
+		entities, err := pageEntities(ctx, jsonlOpts.EntityExtractor, page)
+		if err != nil {
+			return err
+		}
+		pending = &JSONLRecord{
+			Path:     inputPath,
+			Page:     page.Number,
+			Label:    page.Label,
+			Text:     page.Text,
+			Language: page.Language,
+			Entities: entities,
+			Chars:    utf8.RuneCountInString(page.Text),
+			Warnings: takeWarnings(),
+		}
+	}
+	if pending == nil {
+		return nil
+	}
+	pending.Warnings = append(pending.Warnings, takeWarnings()...)
+	return enc.Encode(pending)
+}
@@ -0,0 +1,72 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConverter_ConvertJSONL(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          int
+		expected      []JSONLRecord
+		expectedError error
+	}{
+		{
+			name: "Success",
+			expected: []JSONLRecord{
+				{Path: "input.pdf", Page: 1, Text: "one", Chars: 3, Warnings: []string{
+					"Syntax Warning: Invalid Font Weight",
+					"Syntax Error (42): Illegal character",
+				}},
+				{Path: "input.pdf", Page: 2, Text: "two", Chars: 3},
+			},
+		},
+		{
+			name:          "Failure",
+			code:          1,
+			expectedError: ErrPDFOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(eventsRunner(tt.code)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			var warned []string
+			var out bytes.Buffer
+			err = converter.ConvertJSONL(context.Background(), "input.pdf", &Options{OnWarning: func(message string) {
+				warned = append(warned, message)
+			}}, &out)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if len(warned) != 2 {
+				t.Errorf("expected OnWarning to still be called, got %v", warned)
+			}
+
+			var records []JSONLRecord
+			for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+				if line == "" {
+					continue
+				}
+				var record JSONLRecord
+				if err := json.Unmarshal([]byte(line), &record); err != nil {
+					t.Fatalf("invalid JSON line %q: %v", line, err)
+				}
+				records = append(records, record)
+			}
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("expected records %+v, got %+v", tt.expected, records)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package pdftotext
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// KilledError is returned when pdftotext or a companion tool was killed by a
+// signal, e.g. by the OOM killer or because the context of the conversion
+// was done, so retries can tell a crash of the host from a broken PDF. It
+// matches ErrKilled and the error of the runner.
+type KilledError struct {
+	// Signal is the name of the signal, e.g. "killed", empty if the runner
+	// does not report it
+	Signal string
+	// Partial reports whether pdftotext wrote some output before it was
+	// killed, which is then incomplete
+	Partial bool
+	// Err is the error of the runner
+	Err error
+}
+
+// Error returns the signal and whether output was written
+func (e *KilledError) Error() string {
+	msg := ErrKilled.Error()
+	if e.Signal != "" {
+		msg += " (" + e.Signal + ")"
+	}
+	if e.Partial {
+		msg += ", the partial output is incomplete"
+	}
+	return msg
+}
+
+// Unwrap returns ErrKilled and the error of the runner
+func (e *KilledError) Unwrap() []error {
+	return []error{ErrKilled, e.Err}
+}
+
+// asKilled returns a KilledError for the error of a command killed by a
+// signal, or nil. Custom runners report a killed process with ExitError code
+// -1.
+func asKilled(err error) *KilledError {
+	var killed *KilledError
+	if errors.As(err, &killed) {
+		return killed
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		signal, ok := exitSignal(execErr.ProcessState)
+		if !ok {
+			return nil
+		}
+		return &KilledError{Signal: signal, Err: err}
+	}
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == -1 {
+		return &KilledError{Err: err}
+	}
+	return nil
+}
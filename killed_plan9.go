@@ -0,0 +1,9 @@
+package pdftotext
+
+import "os"
+
+// exitSignal reports false, since Plan 9 delivers notes rather than signals
+// and the exit status does not tell a killed process apart
+func exitSignal(state *os.ProcessState) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,18 @@
+//go:build !plan9
+
+package pdftotext
+
+import (
+	"os"
+	"syscall"
+)
+
+// exitSignal returns the name of the signal that killed the process of state,
+// and whether it was killed by one
+func exitSignal(state *os.ProcessState) (string, bool) {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return "", false
+	}
+	return status.Signal().String(), true
+}
@@ -0,0 +1,77 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKilledError(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		toFile      bool
+		timeout     time.Duration
+		wantSignal  string
+		wantPartial bool
+	}{
+		{"oom killed", "kill -KILL $$\n", false, 0, "killed", false},
+		{"partial stdout", "echo partial\nkill -KILL $$\n", false, 0, "killed", true},
+		{"partial file", "for arg in \"$@\"; do out=$arg; done\necho partial > \"$out\"\nkill -TERM $$\n", true, 0, "terminated", true},
+		{"timeout", "exec sleep 5\n", false, 100 * time.Millisecond, "killed", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithBinaryPath(writeFakeBinary(t, tt.script)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			if tt.toFile {
+				err = converter.ConvertToFile(ctx, "input.pdf", filepath.Join(t.TempDir(), "output.txt"), nil)
+			} else {
+				_, err = converter.Convert(ctx, "input.pdf", nil)
+			}
+			var killed *KilledError
+			if !errors.As(err, &killed) || !errors.Is(err, ErrKilled) || !errors.Is(err, ErrCommandFailed) {
+				t.Fatalf("expected a KilledError, got %v", err)
+			}
+			if killed.Signal != tt.wantSignal || killed.Partial != tt.wantPartial {
+				t.Errorf("expected signal %q and partial %v, got %+v", tt.wantSignal, tt.wantPartial, killed)
+			}
+			if incomplete := strings.Contains(err.Error(), "partial output is incomplete"); incomplete != tt.wantPartial {
+				t.Errorf("expected the message to report partial output %v, got %q", tt.wantPartial, err)
+			}
+			if tt.timeout > 0 && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected context.DeadlineExceeded, got %v", err)
+			}
+			if kind := ErrorKind(err); kind != "killed" {
+				t.Errorf("expected kind killed, got %q", kind)
+			}
+			if !IsTransient(err) {
+				t.Error("expected a killed process to be transient")
+			}
+		})
+	}
+
+	t.Run("custom runner", func(t *testing.T) {
+		converter, err := New(WithRunner(fakeRun("", "", -1)))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+		_, err = converter.Convert(context.Background(), "input.pdf", nil)
+		var killed *KilledError
+		if !errors.As(err, &killed) || killed.Signal != "" || killed.Partial {
+			t.Errorf("expected a KilledError without signal and output, got %v", err)
+		}
+	})
+}
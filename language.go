@@ -0,0 +1,64 @@
+package pdftotext
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LanguageDetector detects the language of text. The optional
+// github.com/joeychilson/pdftotext/pdflingua module provides one backed by
+// lingua-go.
+type LanguageDetector interface {
+	// DetectLanguage returns the language of text as a BCP 47 tag such as
+	// "en" or "de", or "" if it is unknown
+	DetectLanguage(text string) string
+}
+
+// LanguageDetectorFunc adapts a function to a LanguageDetector
+type LanguageDetectorFunc func(text string) string
+
+// DetectLanguage calls f(text)
+func (f LanguageDetectorFunc) DetectLanguage(text string) string {
+	return f(text)
+}
+
+// DetectLanguages sets the Language of each page of doc and of doc itself
+// using detector
+func DetectLanguages(detector LanguageDetector, doc *Document) {
+	detectPageLanguages(detector, doc.Pages)
+	doc.Language = documentLanguage(doc.Pages)
+}
+
+// detectPageLanguages sets the Language of each page using detector, leaving
+// pages without text undetected
+func detectPageLanguages(detector LanguageDetector, pages []Page) {
+	for i := range pages {
+		pages[i].Language = pageLanguage(detector, pages[i].Text)
+	}
+}
+
+// pageLanguage returns the language of text, or "" if detector is nil or
+// text is blank
+func pageLanguage(detector LanguageDetector, text string) string {
+	if detector == nil || strings.TrimSpace(text) == "" {
+		return ""
+	}
+	return detector.DetectLanguage(text)
+}
+
+// documentLanguage returns the language of the most text among pages, or ""
+// if no page has a known language
+func documentLanguage(pages []Page) string {
+	chars := map[string]int{}
+	var best string
+	for _, page := range pages {
+		if page.Language == "" {
+			continue
+		}
+		chars[page.Language] += utf8.RuneCountInString(page.Text)
+		if best == "" || chars[page.Language] > chars[best] {
+			best = page.Language
+		}
+	}
+	return best
+}
@@ -0,0 +1,92 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// keywordDetector detects German by "und" and English by "and"
+var keywordDetector = LanguageDetectorFunc(func(text string) string {
+	switch {
+	case strings.Contains(text, " und "):
+		return "de"
+	case strings.Contains(text, " and "):
+		return "en"
+	}
+	return ""
+})
+
+func TestDetectLanguages(t *testing.T) {
+	doc := &Document{Pages: []Page{
+		{Number: 1, Text: "salt and pepper"},
+		{Number: 2, Text: "Salz und Pfeffer, Zucker und Zimt, Brot und Butter"},
+		{Number: 3, Text: "  \n"},
+		{Number: 4, Text: "fish and chips"},
+	}}
+	DetectLanguages(keywordDetector, doc)
+
+	expected := []string{"en", "de", "", "en"}
+	for i, page := range doc.Pages {
+		if page.Language != expected[i] {
+			t.Errorf("expected page %d in %q, got %q", page.Number, expected[i], page.Language)
+		}
+	}
+	// the German page has more text than the two English pages
+	if doc.Language != "de" {
+		t.Errorf("expected document in de, got %q", doc.Language)
+	}
+}
+
+func TestConverter_LanguageDetector(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		output := "salt and pepper\fSalz und Pfeffer\fbread and butter\f"
+		if args[len(args)-1] != "-" {
+			return os.WriteFile(args[len(args)-1], []byte(output), 0o644)
+		}
+		_, err := io.WriteString(stdout, output)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	opts := &Options{LanguageDetector: keywordDetector}
+
+	t.Run("ConvertDocument", func(t *testing.T) {
+		doc, err := converter.ConvertDocument(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if doc.Language != "en" || doc.Pages[0].Language != "en" || doc.Pages[1].Language != "de" {
+			t.Errorf("unexpected languages %+v", doc)
+		}
+	})
+
+	t.Run("StreamPages", func(t *testing.T) {
+		var languages []string
+		for page, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			languages = append(languages, page.Language)
+		}
+		if strings.Join(languages, ",") != "en,de,en" {
+			t.Errorf("unexpected languages %v", languages)
+		}
+	})
+
+	t.Run("Sidecar", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "output.txt")
+		sidecar, err := converter.ConvertToFileWithSidecar(ctx, "input.pdf", output, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sidecar.Language != "en" {
+			t.Errorf("expected sidecar language en, got %q", sidecar.Language)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package pdftotext
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Layout is the text of a document with its geometry: the lines of every
+// page as text runs in reading order, with their positions, estimated font
+// sizes and heading levels. It is the model Markdown and the document
+// structure are rendered from.
+type Layout struct {
+	// Pages are the sizes of the pages
+	Pages []LayoutPage `json:"pages"`
+	// Runs are the text runs of all pages in reading order
+	Runs []TextRun `json:"runs"`
+}
+
+// LayoutPage is the size of a page of a Layout
+type LayoutPage struct {
+	// Number is the page number in the PDF file
+	Number int `json:"number"`
+	// Width is the page width in points
+	Width float64 `json:"width"`
+	// Height is the page height in points
+	Height float64 `json:"height"`
+}
+
+// TextRun is a line of text of a Layout
+type TextRun struct {
+	// Page is the page number of the run
+	Page int `json:"page"`
+	// Order is the position of the run in the reading order of the document
+	Order int `json:"order"`
+	// Block numbers the blocks of the document in reading order. The runs of
+	// a block are consecutive.
+	Block int `json:"block"`
+	// Text is the text of the line, its words separated by spaces
+	Text string `json:"text"`
+	// Box is the bounding box of the line
+	Box Rect `json:"box"`
+	// BlockBox is the bounding box of the block of the run
+	BlockBox Rect `json:"block_box"`
+	// FontSize is the font size in points estimated from the line height,
+	// since pdftotext reports no fonts
+	FontSize float64 `json:"font_size"`
+	// HeadingLevel is the level of the heading the run is part of, 1 for the
+	// top-level headings, or 0 for body text
+	HeadingLevel int `json:"heading_level,omitempty"`
+	// Running reports whether the run is a running header or footer, see
+	// FindRunningLines
+	Running bool `json:"running,omitempty"`
+}
+
+// Layout converts a PDF file with -bbox-layout and returns its text runs in
+// reading order, see BuildLayout. Transformers and output options such as
+// Layout and Raw are ignored.
+func (c *Converter) Layout(ctx context.Context, inputPath string, opts *Options) (*Layout, error) {
+	pages, err := c.geometry(ctx, inputPath, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return BuildLayout(pages), nil
+}
+
+// BuildLayout orders the blocks of pages, as produced by -bbox-layout, as in
+// ReadingOrder and returns their lines as text runs. Running headers and
+// footers are flagged, and the other blocks are classified into headings
+// and body text as described for BuildStructure.
+func BuildLayout(pages []PageGeometry) *Layout {
+	var heights []float64
+	for _, page := range pages {
+		for _, line := range page.Lines {
+			heights = append(heights, line.Box.YMax-line.Box.YMin)
+		}
+	}
+	body := median(heights)
+	running := FindRunningLines(pages, 0.5)
+
+	type block struct {
+		page    int
+		box     Rect
+		lines   []Line
+		running []bool
+		size    float64
+		heading bool
+		depth   int
+		level   int
+	}
+	layout := &Layout{}
+	var blocks []block
+	var sizes []float64
+	for _, page := range pages {
+		layout.Pages = append(layout.Pages, LayoutPage{Number: page.Number, Width: page.Width, Height: page.Height})
+		for _, b := range ReadingOrder(page.Blocks) {
+			blk := block{page: page.Number, box: b.Box, lines: b.Lines}
+			var content []Line
+			for _, line := range b.Lines {
+				isRunning := isRunningLine(running, page.Number, line)
+				blk.running = append(blk.running, isRunning)
+				if !isRunning {
+					content = append(content, line)
+				}
+			}
+			if len(content) == 0 {
+				blocks = append(blocks, blk)
+				continue
+			}
+
+			var lineHeights []float64
+			words := 0
+			for _, line := range content {
+				lineHeights = append(lineHeights, line.Box.YMax-line.Box.YMin)
+				words += len(strings.Fields(line.Text))
+			}
+			blk.size = math.Round(median(lineHeights)*2) / 2
+			if m := numberedHeadingPattern.FindStringSubmatch(content[0].Text); m != nil && len(content) == 1 {
+				blk.depth = strings.Count(m[1], ".") + 1
+			}
+			switch {
+			case words > maxHeadingWords || len(content) > 3:
+			case body > 0 && blk.size >= headingScale*body:
+				blk.heading = true
+				if !slices.Contains(sizes, blk.size) {
+					sizes = append(sizes, blk.size)
+				}
+			case blk.depth > 0:
+				blk.heading = true
+			}
+			blocks = append(blocks, blk)
+		}
+	}
+	slices.Sort(sizes)
+	slices.Reverse(sizes)
+
+	// numbered maps numbering depths to the levels of their headings
+	numbered := map[int]int{}
+	for i, blk := range blocks {
+		if blk.heading {
+			level := slices.Index(sizes, blk.size) + 1
+			if level == 0 {
+				switch {
+				case numbered[blk.depth] > 0:
+					level = numbered[blk.depth]
+				case numbered[blk.depth-1] > 0:
+					level = numbered[blk.depth-1] + 1
+				default:
+					level = len(sizes) + blk.depth
+				}
+			}
+			if blk.depth > 0 && numbered[blk.depth] == 0 {
+				numbered[blk.depth] = level
+			}
+			blk.level = level
+		}
+		for j, line := range blk.lines {
+			run := TextRun{
+				Page:     blk.page,
+				Order:    len(layout.Runs),
+				Block:    i,
+				Text:     line.Text,
+				Box:      line.Box,
+				BlockBox: blk.box,
+				FontSize: math.Round((line.Box.YMax-line.Box.YMin)*2) / 2,
+				Running:  blk.running[j],
+			}
+			if !run.Running {
+				run.HeadingLevel = blk.level
+			}
+			layout.Runs = append(layout.Runs, run)
+		}
+	}
+	return layout
+}
@@ -0,0 +1,67 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestConverter_Layout(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, blockLayoutOutput(paperPages))
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	layout, err := converter.Layout(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(layout.Pages) != 2 || layout.Pages[1].Number != 2 {
+		t.Fatalf("unexpected pages %+v", layout.Pages)
+	}
+	if len(layout.Runs) != 14 {
+		t.Fatalf("expected 14 runs, got %d", len(layout.Runs))
+	}
+
+	tests := []struct {
+		text     string
+		page     int
+		fontSize float64
+		level    int
+		running  bool
+	}{
+		{"A Study of Things", 1, 24, 1, false},
+		{"This paper studies things in", 1, 10, 0, false},
+		{"1 Introduction", 1, 14, 2, false},
+		{"1.1 Background", 1, 10, 3, false},
+		{"Page 1", 1, 10, 0, true},
+		{"2 Results", 2, 14, 2, false},
+		{"Page 2", 2, 10, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			for _, run := range layout.Runs {
+				if run.Text != tt.text {
+					continue
+				}
+				if run.Page != tt.page || run.FontSize != tt.fontSize || run.HeadingLevel != tt.level || run.Running != tt.running {
+					t.Errorf("unexpected run %+v", run)
+				}
+				return
+			}
+			t.Errorf("run %q not found", tt.text)
+		})
+	}
+
+	for i, run := range layout.Runs {
+		if run.Order != i {
+			t.Errorf("run %d has order %d", i, run.Order)
+		}
+		if i > 0 && run.Block < layout.Runs[i-1].Block {
+			t.Errorf("run %d of block %d follows block %d", i, run.Block, layout.Runs[i-1].Block)
+		}
+	}
+}
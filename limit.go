@@ -0,0 +1,85 @@
+package pdftotext
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// semaphore limits the number of commands running at the same time. A nil
+// semaphore does not limit them.
+type semaphore chan struct{}
+
+// newSemaphore returns a semaphore admitting n commands, or nil if n is not
+// positive
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+// acquire waits until a command may run or ctx is done
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release ends a command admitted by acquire
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
+
+// processLimit is the semaphore of SetMaxConcurrent shared by all converters
+var processLimit atomic.Pointer[semaphore]
+
+// SetMaxConcurrent limits the number of child processes run at the same time
+// by all Converters of the process, e.g. to stay within the PID and CPU
+// limits of a container when several independent components convert PDFs.
+// Conversions wait for a free slot until their context is done. Zero or a
+// negative n removes the limit. Commands already waiting or running are
+// counted against the limit they started with. A streaming conversion holds
+// its slot until the stream ends, so with small limits the consumer of a
+// stream should not start conversions of its own.
+func SetMaxConcurrent(n int) {
+	s := newSemaphore(n)
+	processLimit.Store(&s)
+}
+
+// WithMaxConcurrent limits the number of child processes the Converter and
+// its clones run at the same time, within the process-wide limit of
+// SetMaxConcurrent. Conversions wait for a free slot until their context is
+// done.
+func WithMaxConcurrent(n int) ConverterOption {
+	return func(c *Converter) {
+		c.limit = newSemaphore(n)
+	}
+}
+
+// acquireSlots waits for a slot in the process-wide and in the converter
+// limit and returns the function releasing them
+func (c *Converter) acquireSlots(ctx context.Context) (func(), error) {
+	var process semaphore
+	if s := processLimit.Load(); s != nil {
+		process = *s
+	}
+	if err := process.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.limit.acquire(ctx); err != nil {
+		process.release()
+		return nil, err
+	}
+	return func() {
+		c.limit.release()
+		process.release()
+	}, nil
+}
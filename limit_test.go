@@ -0,0 +1,91 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyRunner records the highest number of commands running at once
+type concurrencyRunner struct {
+	running, peak atomic.Int32
+}
+
+func (r *concurrencyRunner) Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	n := r.running.Add(1)
+	defer r.running.Add(-1)
+	for {
+		peak := r.peak.Load()
+		if n <= peak || r.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	_, err := io.WriteString(stdout, "text")
+	return err
+}
+
+func TestWithMaxConcurrent(t *testing.T) {
+	tests := []struct {
+		name       string
+		global     int
+		converters []ConverterOption
+		expected   int32
+	}{
+		{name: "Converter", converters: []ConverterOption{WithMaxConcurrent(2), WithMaxConcurrent(2)}, expected: 4},
+		{name: "Process", global: 3, converters: []ConverterOption{WithMaxConcurrent(0), WithMaxConcurrent(5)}, expected: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxConcurrent(tt.global)
+			t.Cleanup(func() { SetMaxConcurrent(0) })
+
+			runner := &concurrencyRunner{}
+			var wg sync.WaitGroup
+			for _, opt := range tt.converters {
+				converter, err := New(WithRunner(runner), opt)
+				if err != nil {
+					t.Fatalf("failed to create converter: %v", err)
+				}
+				for range 8 {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						if _, err := converter.Convert(context.Background(), "input.pdf", nil); err != nil {
+							t.Errorf("unexpected error: %v", err)
+						}
+					}()
+				}
+			}
+			wg.Wait()
+			if peak := runner.peak.Load(); peak > tt.expected {
+				t.Errorf("expected at most %d concurrent commands, got %d", tt.expected, peak)
+			}
+		})
+	}
+}
+
+func TestWithMaxConcurrent_Wait(t *testing.T) {
+	started, done := make(chan struct{}), make(chan struct{})
+	converter, err := New(WithMaxConcurrent(1), WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		close(started)
+		<-done
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	go converter.Convert(context.Background(), "first.pdf", nil)
+	<-started
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := converter.Convert(ctx, "second.pdf", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the second conversion to time out waiting, got %v", err)
+	}
+}
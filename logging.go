@@ -0,0 +1,93 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// maxLoggedStderr is the number of bytes of the stderr of a command that are
+// logged
+const maxLoggedStderr = 1024
+
+// WithLogger sets a logger receiving the command line, duration, exit code
+// and stderr of each command the Converter runs at debug level, so failures
+// in production can be diagnosed from the logs. Passwords are masked, and
+// stderr is truncated to 1 KiB.
+func WithLogger(logger *slog.Logger) ConverterOption {
+	return func(c *Converter) {
+		c.logger = logger
+	}
+}
+
+// runCommand runs a command with the runner of the converter once the
+// concurrency limits admit it, logging it when debug logging is enabled.
+// Passwords in args are masked in the log and in the returned error.
+func (c *Converter) runCommand(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	release, err := c.acquireSlots(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	secrets := passwordValues(args)
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return redactError(c.runner.Run(ctx, name, args, stdout, stderr), secrets)
+	}
+
+	head := &headWriter{limit: maxLoggedStderr}
+	errOut := io.Writer(head)
+	if stderr != nil {
+		errOut = io.MultiWriter(stderr, head)
+	}
+	start := time.Now()
+	err = redactError(c.runner.Run(ctx, name, args, stdout, errOut), secrets)
+
+	attrs := []slog.Attr{
+		slog.String("command", name),
+		slog.Any("args", maskPasswords(args)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	var exitErr exitCoder
+	switch {
+	case err == nil:
+		attrs = append(attrs, slog.Int("exit_code", 0))
+	case errors.As(err, &exitErr):
+		attrs = append(attrs, slog.Int("exit_code", exitErr.ExitCode()))
+	default:
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if stderr := head.String(); stderr != "" {
+		attrs = append(attrs, slog.String("stderr", redact(stderr, secrets)))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "ran command", attrs...)
+	return err
+}
+
+// headWriter keeps the first limit bytes written to it
+type headWriter struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (hw *headWriter) Write(p []byte) (int, error) {
+	if room := hw.limit - hw.buf.Len(); len(p) > room {
+		hw.buf.Write(p[:max(room, 0)])
+		hw.truncated = true
+	} else {
+		hw.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// String returns the kept bytes, ending with "…" if some were dropped
+func (hw *headWriter) String() string {
+	if hw.truncated {
+		return hw.buf.String() + "…"
+	}
+	return hw.buf.String()
+}
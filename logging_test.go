@@ -0,0 +1,72 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	converter, err := New(WithLogger(logger), WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Syntax Error: "+strings.Repeat("x", 2*maxLoggedStderr))
+		return &ExitError{Code: 1}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	_, err = converter.Convert(context.Background(), "input.pdf", &Options{UserPassword: "secret"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	var entry struct {
+		Msg      string   `json:"msg"`
+		Command  string   `json:"command"`
+		Args     []string `json:"args"`
+		ExitCode *int     `json:"exit_code"`
+		Stderr   string   `json:"stderr"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log entry %q: %v", logs.String(), err)
+	}
+	if entry.Msg != "ran command" || entry.Command != "pdftotext" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if strings.Contains(logs.String(), "secret") || !strings.Contains(strings.Join(entry.Args, " "), "-upw *** input.pdf -") {
+		t.Errorf("expected the masked command line, got %q", entry.Args)
+	}
+	if entry.ExitCode == nil || *entry.ExitCode != 1 {
+		t.Errorf("expected exit code 1, got %v", entry.ExitCode)
+	}
+	if !strings.HasPrefix(entry.Stderr, "Syntax Error: ") || !strings.HasSuffix(entry.Stderr, "…") || len(entry.Stderr) != maxLoggedStderr+len("…") {
+		t.Errorf("expected stderr truncated to %d bytes, got %d bytes", maxLoggedStderr, len(entry.Stderr))
+	}
+	if !strings.Contains(err.Error(), strings.Repeat("x", 2*maxLoggedStderr)) {
+		t.Error("expected the error to keep the whole stderr")
+	}
+}
+
+func TestWithLogger_Disabled(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	converter, err := New(WithLogger(logger), WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "text")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if _, err := converter.Convert(context.Background(), "input.pdf", nil); err != nil {
+		t.Fatalf("failed to convert: %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected no logs above debug level, got %q", logs.String())
+	}
+}
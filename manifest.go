@@ -0,0 +1,253 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ManifestVersion is the schema version of manifests produced by this package
+const ManifestVersion = 1
+
+// Signer signs legal-hold manifests, e.g. with an HSM or KMS backed key
+type Signer interface {
+	// Sign returns a signature over the manifest payload
+	Sign(payload []byte) ([]byte, error)
+}
+
+// Artifact identifies a file taking part in a conversion by its content hash
+type Artifact struct {
+	// Path is the path of the file
+	Path string `json:"path"`
+	// SHA256 is the hex encoded SHA-256 hash of the file contents, set when
+	// the manifest uses the SHA256 algorithm
+	SHA256 string `json:"sha256,omitempty"`
+	// Digest is the hex encoded hash of the file contents with the hash
+	// algorithm of the manifest
+	Digest string `json:"digest"`
+	// Size is the size of the file in bytes
+	Size int64 `json:"size"`
+}
+
+// Manifest freezes the exact backend, arguments, input and output of a
+// conversion so the extraction can be reproduced and verified later
+type Manifest struct {
+	// Version is the manifest schema version
+	Version int `json:"version"`
+	// StartedAt is the time the conversion started
+	StartedAt time.Time `json:"started_at"`
+	// CreatedAt is the time the conversion finished
+	CreatedAt time.Time `json:"created_at"`
+	// Binary is the pdftotext binary that performed the conversion
+	Binary Artifact `json:"binary"`
+	// HashAlgorithm is the name of the algorithm of the artifact digests
+	HashAlgorithm string `json:"hash_algorithm"`
+	// BinaryVersion is the version reported by the binary
+	BinaryVersion string `json:"binary_version"`
+	// Args are the arguments passed to the binary, with passwords masked, or
+	// those of the first process when several converted the pages
+	Args []string `json:"args"`
+	// Commands are the pdftotext processes that produced the output in
+	// order, one for each page range of Options.Pages, Options.ExcludePages
+	// or Options.TailPages
+	Commands []Command `json:"commands,omitempty"`
+	// Input is the converted PDF file
+	Input Artifact `json:"input"`
+	// Output is the produced text file
+	Output Artifact `json:"output"`
+	// Signature is the signer's signature over Payload, if a signer was used
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Command is a pdftotext process run by a conversion
+type Command struct {
+	// Args are the arguments passed to the binary, with passwords masked
+	Args []string `json:"args"`
+	// Env are the environment variables added to the environment of the
+	// process, e.g. for Options.Deterministic
+	Env []string `json:"env,omitempty"`
+}
+
+// commandsKey is the context key of the commandRecorder of a conversion
+type commandsKey struct{}
+
+// commandRecorder collects the pdftotext processes of a conversion that
+// succeeded
+type commandRecorder struct {
+	mu       sync.Mutex
+	commands []Command
+}
+
+// withCommandRecorder returns ctx recording the pdftotext processes run with
+// it in r
+func withCommandRecorder(ctx context.Context, r *commandRecorder) context.Context {
+	return context.WithValue(ctx, commandsKey{}, r)
+}
+
+// recordCommand records a pdftotext process that succeeded with args in the
+// recorder of ctx, if any
+func (c *Converter) recordCommand(ctx context.Context, args []string) {
+	r, ok := ctx.Value(commandsKey{}).(*commandRecorder)
+	if !ok {
+		return
+	}
+	command := Command{Args: maskPasswords(args)}
+	if _, ok := c.runner.(ExecRunner); ok {
+		env, _ := ctx.Value(envKey{}).([]string)
+		command.Env = slices.Clone(env)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, command)
+}
+
+// Payload returns the canonical bytes covered by the manifest signature
+func (m *Manifest) Payload() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	return json.Marshal(&unsigned)
+}
+
+// hashAlgorithm returns the name of the hash algorithm of the artifact
+// digests, which is SHA256 for manifests predating HashAlgorithm
+func (m *Manifest) hashAlgorithm() string {
+	return cmp.Or(m.HashAlgorithm, SHA256.Name)
+}
+
+// ConvertToFileWithManifest converts a PDF file to text like ConvertToFile and
+// writes a manifest next to the output file (outputPath + ".manifest.json").
+// The manifest is signed when signer is not nil.
+func (c *Converter) ConvertToFileWithManifest(ctx context.Context, inputPath, outputPath string, opts *Options, signer Signer) (*Manifest, error) {
+	m, err := c.convertWithManifest(ctx, inputPath, outputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	if signer != nil {
+		payload, err := m.Payload()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		if m.Signature, err = signer.Sign(payload); err != nil {
+			return nil, fmt.Errorf("failed to sign manifest: %w", err)
+		}
+	}
+
+	if err := writeJSON(ctx, c.output(), outputPath+".manifest.json", m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// convertWithManifest runs ConvertToFile and returns the unsigned manifest describing it
+func (c *Converter) convertWithManifest(ctx context.Context, inputPath, outputPath string, opts *Options) (*Manifest, error) {
+	// the input is hashed before it is converted, so it is checked first
+	ctx, err := c.confine(ctx, inputPath, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	// a custom runner may not run a binary of this host, so only its path is
+	// recorded
+	binary := Artifact{Path: c.binaryPath}
+	if _, ok := c.runner.(ExecRunner); ok {
+		if binary, err = hashFile(c.binaryPath, c.hash); err != nil {
+			return nil, fmt.Errorf("failed to hash pdftotext binary: %w", err)
+		}
+	}
+	version, err := c.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	input, err := hashFile(inputPath, c.hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPDFOpen, err)
+	}
+
+	startedAt := time.Now().UTC()
+	recorder := &commandRecorder{}
+	if err := c.ConvertToFile(withCommandRecorder(ctx, recorder), inputPath, outputPath, opts); err != nil {
+		return nil, err
+	}
+
+	output, err := c.hashOutput(ctx, outputName(outputPath, opts))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+
+	m := &Manifest{
+		Version:       ManifestVersion,
+		StartedAt:     startedAt,
+		CreatedAt:     time.Now().UTC(),
+		Binary:        binary,
+		HashAlgorithm: c.hash.Name,
+		BinaryVersion: version,
+		Commands:      recorder.commands,
+		Input:         input,
+		Output:        output,
+	}
+	if len(m.Commands) > 0 {
+		m.Args = m.Commands[0].Args
+	}
+	return m, nil
+}
+
+// hashOutput returns the artifact describing the named output file
+func (c *Converter) hashOutput(ctx context.Context, name string) (Artifact, error) {
+	f, err := c.output().Open(ctx, name)
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer f.Close()
+	return hashReader(name, f, c.hash)
+}
+
+// writeJSON writes v as indented JSON to path on fsys
+func writeJSON(ctx context.Context, fsys OutputFS, path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	f, err := fsys.Create(ctx, path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+	return nil
+}
+
+// hashFile returns the artifact describing the file at path, hashed with alg
+func hashFile(path string, alg HashAlgorithm) (Artifact, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer f.Close()
+	return hashReader(path, f, alg)
+}
+
+// hashReader returns the artifact describing the file at path read from r,
+// hashed with alg
+func hashReader(path string, r io.Reader, alg HashAlgorithm) (Artifact, error) {
+	h := alg.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return Artifact{}, err
+	}
+	a := Artifact{Path: path, Digest: hex.EncodeToString(h.Sum(nil)), Size: n}
+	if alg.Name == SHA256.Name {
+		a.SHA256 = a.Digest
+	}
+	return a, nil
+}
@@ -0,0 +1,146 @@
+package pdftotext
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+type ed25519Signer struct{ key ed25519.PrivateKey }
+
+func (s ed25519Signer) Sign(payload []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, payload), nil
+}
+
+func TestConverter_ConvertToFileWithManifest(t *testing.T) {
+	binary := writeFakeBinary(t, `if [ "$1" = "-v" ]; then
+	echo "pdftotext version 24.02.0" >&2
+	exit 0
+fi
+for arg in "$@"; do out=$arg; done
+printf 'extracted text\f' > "$out"
+`)
+	converter, err := New(WithBinaryPath(binary))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	inputPath := filepath.Join("testdata", "test.pdf")
+	outputPath := filepath.Join(t.TempDir(), "output.txt")
+	opts := &Options{Layout: true, UserPassword: "secret", Deterministic: true}
+
+	m, err := converter.ConvertToFileWithManifest(context.Background(), inputPath, outputPath, opts, ed25519Signer{private})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if m.BinaryVersion != "24.02.0" {
+		t.Errorf("expected binary version %q, got %q", "24.02.0", m.BinaryVersion)
+	}
+	if m.HashAlgorithm != "sha256" || m.Input.Digest != m.Input.SHA256 {
+		t.Errorf("expected sha256 digests, got %s %+v", m.HashAlgorithm, m.Input)
+	}
+	if m.Input.Path != inputPath || len(m.Input.SHA256) != 64 || m.Input.Size == 0 {
+		t.Errorf("unexpected input artifact: %+v", m.Input)
+	}
+	// sha256("extracted text\f")
+	if m.Output.SHA256 != "dcd686803e42a73c8fb9c682760b0576d12d09cf2441a94a1436cbfb56bc7571" || m.Output.Size != 15 {
+		t.Errorf("unexpected output artifact: %+v", m.Output)
+	}
+	if m.Binary.Path != binary || m.Binary.Digest == "" {
+		t.Errorf("unexpected binary artifact: %+v", m.Binary)
+	}
+	if len(m.Commands) != 1 || !slices.Equal(m.Commands[0].Env, deterministicEnv) {
+		t.Errorf("expected one run with the deterministic environment, got %+v", m.Commands)
+	}
+	for _, arg := range m.Args {
+		if arg == "secret" {
+			t.Errorf("expected password to be masked, got args %v", m.Args)
+		}
+	}
+
+	payload, err := m.Payload()
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	if !ed25519.Verify(public, payload, m.Signature) {
+		t.Error("expected manifest signature to verify")
+	}
+
+	data, err := os.ReadFile(outputPath + ".manifest.json")
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var written Manifest
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+	writtenPayload, err := written.Payload()
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	if !ed25519.Verify(public, writtenPayload, written.Signature) {
+		t.Error("expected written manifest signature to verify")
+	}
+}
+
+func TestConverter_ConvertToFileWithManifest_Commands(t *testing.T) {
+	// a custom runner converting pages 1 to 10, encrypted with the password
+	// returned by PasswordFunc
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if slices.Contains(args, "-v") {
+			io.WriteString(stderr, "pdftotext version 24.02.0\n")
+			return nil
+		}
+		if !slices.Contains(args, "secret") {
+			io.WriteString(stderr, "Command Line Error: Incorrect password\n")
+			return &ExitError{Code: 1}
+		}
+		_, err := fmt.Fprintf(stdout, "pages %s-%s\f", flagValue(args, "-f"), flagValue(args, "-l"))
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "output.txt")
+	opts := &Options{
+		Pages:         "1-2,5",
+		Deterministic: true,
+		PasswordFunc:  func(string) (string, string, error) { return "secret", "", nil },
+	}
+	m, err := converter.ConvertToFileWithManifest(context.Background(), filepath.Join("testdata", "test.pdf"), outputPath, opts, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if m.Binary.Path != "pdftotext" || m.Binary.Digest != "" {
+		t.Errorf("expected only the path of the binary of a custom runner, got %+v", m.Binary)
+	}
+	if len(m.Commands) != 2 {
+		t.Fatalf("expected the 2 successful runs, got %+v", m.Commands)
+	}
+	for i, pages := range [][2]string{{"1", "2"}, {"5", "5"}} {
+		args := m.Commands[i].Args
+		if flagValue(args, "-f") != pages[0] || flagValue(args, "-l") != pages[1] {
+			t.Errorf("expected run %d to convert pages %s-%s, got %v", i, pages[0], pages[1], args)
+		}
+		if slices.Contains(args, "secret") || flagValue(args, "-upw") == "" {
+			t.Errorf("expected the password to be recorded masked, got %v", args)
+		}
+	}
+	if !slices.Equal(m.Args, m.Commands[0].Args) {
+		t.Errorf("expected the args of the first run, got %v", m.Args)
+	}
+}
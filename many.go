@@ -0,0 +1,49 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ConvertMany converts PDF files in order and streams their text into w as
+// one output, e.g. to build a combined corpus or a court bundle. Each
+// document is preceded by opts.DocumentHeader and separated from the
+// previous one by opts.DocumentSeparator. The first failing document stops
+// the conversion, with an error naming it.
+func (c *Converter) ConvertMany(ctx context.Context, inputs []string, w io.Writer, opts *Options) error {
+	var header, separator string
+	if opts != nil {
+		header, separator = opts.DocumentHeader, opts.DocumentSeparator
+	}
+	for i, input := range inputs {
+		if i > 0 && separator != "" {
+			if _, err := io.WriteString(w, expandDocument(separator, i+1, input)); err != nil {
+				return err
+			}
+		}
+		if header != "" {
+			if _, err := io.WriteString(w, expandDocument(header, i+1, input)); err != nil {
+				return err
+			}
+		}
+		if err := c.ConvertTo(ctx, input, w, opts); err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+	}
+	return nil
+}
+
+// expandDocument replaces the placeholders of a document header or
+// separator: {n} with the number of the document, {path} with its path and
+// {name} with its file name
+func expandDocument(s string, n int, path string) string {
+	return strings.NewReplacer(
+		"{n}", strconv.Itoa(n),
+		"{path}", path,
+		"{name}", filepath.Base(path),
+	).Replace(s)
+}
@@ -0,0 +1,75 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConverter_ConvertMany(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		input := args[len(args)-2]
+		if strings.Contains(input, "broken") {
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, strings.TrimSuffix(filepath.Base(input), ".pdf")+"\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		inputs        []string
+		options       *Options
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "Concatenated",
+			inputs:   []string{"a.pdf", "b.pdf"},
+			expected: "a\fb\f",
+		},
+		{
+			name:     "Separator",
+			inputs:   []string{"a.pdf", "b.pdf", "c.pdf"},
+			options:  &Options{DocumentSeparator: "\n--- {n} ---\n"},
+			expected: "a\f\n--- 2 ---\nb\f\n--- 3 ---\nc\f",
+		},
+		{
+			name:     "Header",
+			inputs:   []string{"dir/a.pdf", "b.pdf"},
+			options:  &Options{DocumentHeader: "# {name} ({path})\n"},
+			expected: "# a.pdf (dir/a.pdf)\na\f# b.pdf (b.pdf)\nb\f",
+		},
+		{
+			name:          "Failing document",
+			inputs:        []string{"a.pdf", "broken.pdf", "c.pdf"},
+			expected:      "a\f",
+			expectedError: ErrPDFOpen,
+		},
+		{
+			name: "No inputs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := converter.ConvertMany(context.Background(), tt.inputs, &buf, tt.options)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "broken.pdf") {
+				t.Errorf("expected the error to name the document, got %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package pdftotext
+
+import (
+	"context"
+	"strings"
+)
+
+// ConvertMarkdown converts a PDF file with -bbox-layout and returns the text
+// as Markdown, see RenderMarkdown. Transformers and output options such as
+// Layout and Raw are ignored.
+func (c *Converter) ConvertMarkdown(ctx context.Context, inputPath string, opts *Options) (string, error) {
+	pages, err := c.geometry(ctx, inputPath, opts, true)
+	if err != nil {
+		return "", err
+	}
+	return RenderMarkdown(pages), nil
+}
+
+// RenderMarkdown renders pages, as produced by -bbox-layout, as Markdown.
+// Headings are detected as in BuildStructure, the lines of the other blocks
+// are reflowed into paragraphs, tables found as in FindTables become pipe
+// tables and pages are separated by horizontal rules. Running headers and
+// footers are left out.
+func RenderMarkdown(pages []PageGeometry) string {
+	tables := map[int][]Table{}
+	for _, page := range pages {
+		rows := make([]TSVRow, len(page.Words))
+		for i, word := range page.Words {
+			rows[i] = TSVRow{Level: TSVWord, Page: page.Number, Box: word.Box, Text: word.Text}
+		}
+		tables[page.Number] = FindTables(rows)
+	}
+
+	var b strings.Builder
+	emitted := map[*Table]bool{}
+	write := func(block string) {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(block)
+	}
+	flushTables := func(page int) {
+		for i := range tables[page] {
+			if t := &tables[page][i]; !emitted[t] {
+				write(markdownTable(t))
+				emitted[t] = true
+			}
+		}
+	}
+
+	page := 0
+	for _, blk := range classifyBlocks(pages) {
+		if blk.page != page {
+			if page != 0 {
+				flushTables(page)
+				write("---")
+			}
+			page = blk.page
+		}
+
+		// blocks inside a table are replaced by the table
+		x, y := blk.block.Box.Center()
+		inTable := false
+		for i := range tables[page] {
+			if t := &tables[page][i]; t.Box.Contains(x, y) {
+				if !emitted[t] {
+					write(markdownTable(t))
+					emitted[t] = true
+				}
+				inTable = true
+				break
+			}
+		}
+		if inTable {
+			continue
+		}
+
+		var lines []string
+		for _, line := range blk.block.Lines {
+			lines = append(lines, line.Text)
+		}
+		text := strings.Join(lines, " ")
+		if blk.level > 0 {
+			write(strings.Repeat("#", min(blk.level, 6)) + " " + text)
+			continue
+		}
+		if strings.HasPrefix(text, "#") {
+			text = `\` + text
+		}
+		write(text)
+	}
+	if page != 0 {
+		flushTables(page)
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return b.String() + "\n"
+}
+
+// markdownTable renders t as a pipe table with its first row as the header
+func markdownTable(t *Table) string {
+	var b strings.Builder
+	for i, row := range t.Rows {
+		b.WriteString("|")
+		for _, cell := range row {
+			b.WriteString(" " + strings.ReplaceAll(cell, "|", `\|`) + " |")
+		}
+		if i == 0 {
+			b.WriteString("\n|" + strings.Repeat(" --- |", len(row)))
+		}
+		if i < len(t.Rows)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
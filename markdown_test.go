@@ -0,0 +1,102 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+// tablePage returns a page with a paragraph followed by a table of rows of
+// cells, each cell a word 100 points right of the previous one
+func tablePage(rows ...[]string) PageGeometry {
+	page := PageGeometry{Number: 1, Width: 612, Height: 792}
+	intro := Line{Text: "Prices as of today:", Box: Rect{XMin: 50, YMin: 50, XMax: 150, YMax: 60}}
+	page.Words = append(page.Words,
+		Word{Text: "Prices", Box: Rect{XMin: 50, YMin: 50, XMax: 80, YMax: 60}},
+		Word{Text: "as", Box: Rect{XMin: 84, YMin: 50, XMax: 94, YMax: 60}},
+		Word{Text: "of", Box: Rect{XMin: 98, YMin: 50, XMax: 108, YMax: 60}},
+		Word{Text: "today:", Box: Rect{XMin: 112, YMin: 50, XMax: 150, YMax: 60}},
+	)
+	page.Lines = append(page.Lines, intro)
+	page.Blocks = append(page.Blocks, Block{Box: intro.Box, Lines: []Line{intro}})
+	for i, row := range rows {
+		y := 100 + 15*float64(i)
+		line := Line{Text: strings.Join(row, " "), Box: Rect{XMin: 50, YMin: y, XMax: 50 + 100*float64(len(row)-1) + 40, YMax: y + 10}}
+		for j, cell := range row {
+			x := 50 + 100*float64(j)
+			page.Words = append(page.Words, Word{Text: cell, Box: Rect{XMin: x, YMin: y, XMax: x + 40, YMax: y + 10}})
+		}
+		page.Lines = append(page.Lines, line)
+		page.Blocks = append(page.Blocks, Block{Box: line.Box, Lines: []Line{line}})
+	}
+	return page
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Run("headings and paragraphs", func(t *testing.T) {
+		pages, err := parseBBox(strings.NewReader(blockLayoutOutput(paperPages)), 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "# A Study of Things\n\n" +
+			"This paper studies things in great detail and at length.\n\n" +
+			"## 1 Introduction\n\n" +
+			"Things are everywhere and we want to know more about them.\n\n" +
+			"### 1.1 Background\n\n" +
+			"Earlier work looked at fewer things than we do here.\n\n" +
+			"---\n\n" +
+			"## 2 Results\n\n" +
+			"We found many things, all of them interesting in some way.\n"
+		if got := RenderMarkdown(pages); got != expected {
+			t.Errorf("expected\n%s\ngot\n%s", expected, got)
+		}
+	})
+
+	t.Run("tables", func(t *testing.T) {
+		page := tablePage(
+			[]string{"Item", "Qty", "Price"},
+			[]string{"Widget", "2", "$10|20"},
+			[]string{"Bolt", "1", "$.50"},
+		)
+		expected := "Prices as of today:\n\n" +
+			"| Item | Qty | Price |\n" +
+			"| --- | --- | --- |\n" +
+			"| Widget | 2 | $10\\|20 |\n" +
+			"| Bolt | 1 | $.50 |\n"
+		if got := RenderMarkdown([]PageGeometry{page}); got != expected {
+			t.Errorf("expected\n%s\ngot\n%s", expected, got)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if got := RenderMarkdown(nil); got != "" {
+			t.Errorf("expected empty output, got %q", got)
+		}
+	})
+}
+
+func TestConverter_ConvertMarkdown(t *testing.T) {
+	var gotArgs []string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		gotArgs = args
+		_, err := io.WriteString(stdout, blockLayoutOutput(paperPages))
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	markdown, err := converter.ConvertMarkdown(context.Background(), "input.pdf", &Options{Transformers: []Transformer{TransformerFunc(func(page int, text string) (string, error) {
+		return strings.ToUpper(text), nil
+	})}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(markdown, "# A Study of Things\n") {
+		t.Errorf("unexpected markdown %q", markdown)
+	}
+	if !strings.Contains(strings.Join(gotArgs, " "), "-bbox-layout") {
+		t.Errorf("expected -bbox-layout in %v", gotArgs)
+	}
+}
@@ -0,0 +1,330 @@
+// Package mcp serves pdftotext as Model Context Protocol tools, so LLM agents
+// and IDE assistants can read PDF files through a standard protocol.
+//
+// The server speaks JSON-RPC 2.0 over newline-delimited messages, as used by
+// the MCP stdio transport, and exposes these tools:
+//
+//	extract_text   text of a PDF file, optionally limited to a page range
+//	extract_pages  text of each page of a PDF file
+//	pdf_info       document metadata and page count
+//	search_pdf     lines of a PDF file containing a query, with page numbers
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// ProtocolVersion is the MCP protocol version implemented by the server
+const ProtocolVersion = "2025-06-18"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// defaultMaxResults limits the matches returned by search_pdf
+const defaultMaxResults = 50
+
+// Server answers MCP requests by converting PDF files with pdftotext
+type Server struct {
+	converter *pdftotext.Converter
+	root      string
+	opts      *pdftotext.Options
+}
+
+// NewServer returns a Server converting PDF files with converter and opts.
+// When root is not empty, tool paths are resolved relative to root and may
+// not lead outside of it.
+func NewServer(converter *pdftotext.Converter, root string, opts *pdftotext.Options) *Server {
+	return &Server{converter: converter, root: root, opts: opts}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r and writes responses to w until r is exhausted.
+// Requests are handled in order; ctx bounds the conversions they run.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	enc := json.NewEncoder(w)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			// the stream cannot be resynchronized after malformed JSON
+			enc.Encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+			if err := enc.Encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeInvalidRequest, Message: "invalid request"}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, rpcErr := s.handle(ctx, req)
+		if req.ID == nil {
+			// notifications are not answered
+			continue
+		}
+		if err := enc.Encode(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		version, err := s.converter.Version(ctx)
+		if err != nil || version == "" {
+			version = "unknown"
+		}
+		return map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "pdftotext", "version": version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.call(ctx, params.Name, params.Arguments)
+	}
+	if strings.HasPrefix(req.Method, "notifications/") {
+		return nil, nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+// tool describes a tool in the tools/list result
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// schema returns an object schema with the given properties, requiring path
+func schema(properties map[string]any, required ...string) map[string]any {
+	properties["path"] = map[string]any{"type": "string", "description": "Path of the PDF file"}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   append([]string{"path"}, required...),
+	}
+}
+
+var pagesProperty = map[string]any{"type": "string", "description": `Pages to extract, e.g. "1-3,7,12-"`}
+
+var tools = []tool{
+	{
+		Name:        "extract_text",
+		Description: "Extract the text of a PDF file. Pages are separated by form feeds.",
+		InputSchema: schema(map[string]any{
+			"pages":  pagesProperty,
+			"layout": map[string]any{"type": "boolean", "description": "Maintain the original physical layout"},
+		}),
+	},
+	{
+		Name:        "extract_pages",
+		Description: "Extract the text of each page of a PDF file as a JSON array of {number, text} objects.",
+		InputSchema: schema(map[string]any{"pages": pagesProperty}),
+	},
+	{
+		Name:        "pdf_info",
+		Description: "Get the metadata, page count and encryption status of a PDF file.",
+		InputSchema: schema(map[string]any{}),
+	},
+	{
+		Name:        "search_pdf",
+		Description: "Search a PDF file for lines containing a query, case-insensitively, returning page numbers and lines.",
+		InputSchema: schema(map[string]any{
+			"query":       map[string]any{"type": "string", "description": "Text to search for"},
+			"max_results": map[string]any{"type": "integer", "description": "Maximum number of matches (default 50)"},
+		}, "query"),
+	},
+}
+
+// arguments are the arguments of all tools
+type arguments struct {
+	Path       string `json:"path"`
+	Pages      string `json:"pages"`
+	Layout     bool   `json:"layout"`
+	Query      string `json:"query"`
+	MaxResults int    `json:"max_results"`
+}
+
+// Match is a line of a PDF file found by search_pdf
+type Match struct {
+	// Page is the page number
+	Page int `json:"page"`
+	// Line is the line number within the page, starting at 1
+	Line int `json:"line"`
+	// Text is the line
+	Text string `json:"text"`
+}
+
+// call runs a tool. Tool failures are reported in the result so the model
+// can see them, while unknown tools and bad arguments are protocol errors.
+func (s *Server) call(ctx context.Context, name string, rawArgs json.RawMessage) (any, *rpcError) {
+	if !slices.ContainsFunc(tools, func(t tool) bool { return t.Name == name }) {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + name}
+	}
+
+	var args arguments
+	if len(rawArgs) > 0 {
+		if err := json.Unmarshal(rawArgs, &args); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+	if args.Path == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "path is required"}
+	}
+	path, err := s.resolve(args.Path)
+	if err != nil {
+		return toolError(err), nil
+	}
+
+	opts := &pdftotext.Options{}
+	if s.opts != nil {
+		*opts = *s.opts
+	}
+	if args.Pages != "" {
+		opts.FirstPage, opts.LastPage = 0, 0
+		opts.Pages = args.Pages
+	}
+
+	switch name {
+	case "extract_text":
+		opts.Layout = opts.Layout || args.Layout
+		text, err := s.converter.Convert(ctx, path, opts)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return textResult(text), nil
+	case "extract_pages":
+		pages, err := s.converter.ConvertPages(ctx, path, opts)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return jsonResult(map[string]any{"pages": pages})
+	case "pdf_info":
+		info, err := s.converter.Info(ctx, path, opts)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return jsonResult(info)
+	case "search_pdf":
+		if args.Query == "" {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "query is required"}
+		}
+		if args.MaxResults <= 0 {
+			args.MaxResults = defaultMaxResults
+		}
+		pages, err := s.converter.ConvertPages(ctx, path, opts)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return jsonResult(map[string]any{"matches": search(pages, args.Query, args.MaxResults)})
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + name}
+	}
+}
+
+// resolve maps a tool path into the server root
+func (s *Server) resolve(path string) (string, error) {
+	if s.root == "" {
+		return path, nil
+	}
+	rel := path
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(s.root, path); err != nil {
+			return "", err
+		}
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s is outside of %s", path, s.root)
+	}
+	return filepath.Join(s.root, rel), nil
+}
+
+// search returns up to limit lines of pages containing query, ignoring case
+func search(pages []pdftotext.Page, query string, limit int) []Match {
+	query = strings.ToLower(query)
+	matches := []Match{}
+	for _, page := range pages {
+		for i, line := range strings.Split(page.Text, "\n") {
+			if !strings.Contains(strings.ToLower(line), query) {
+				continue
+			}
+			matches = append(matches, Match{Page: page.Number, Line: i + 1, Text: strings.TrimSpace(line)})
+			if len(matches) == limit {
+				return matches
+			}
+		}
+	}
+	return matches
+}
+
+func textResult(text string) map[string]any {
+	return map[string]any{"content": []map[string]any{{"type": "text", "text": text}}}
+}
+
+// jsonResult returns v as structured content, and as JSON text for clients
+// that only read the text content
+func jsonResult(v any) (any, *rpcError) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return toolError(err), nil
+	}
+	result := textResult(string(data))
+	result["structuredContent"] = v
+	return result, nil
+}
+
+func toolError(err error) map[string]any {
+	result := textResult(err.Error())
+	result["isError"] = true
+	return result
+}
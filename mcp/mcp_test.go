@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// fakeRunner answers like pdfinfo and pdftotext for a two page document
+type fakeRunner struct{}
+
+func (fakeRunner) Run(_ context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	if args[0] == "-v" {
+		io.WriteString(stderr, "pdftotext version 24.02.0\n")
+		return nil
+	}
+	if strings.Contains(args[len(args)-1], "missing") || strings.Contains(args[len(args)-2], "missing") {
+		io.WriteString(stderr, "I/O Error: Couldn't open file")
+		return &pdftotext.ExitError{Code: 1}
+	}
+	if filepath.Base(name) == "pdfinfo" {
+		io.WriteString(stdout, "Title:           Invoice\nPages:           2\n")
+		return nil
+	}
+	io.WriteString(stdout, "Invoice total 42\nShipping\n\fTerms\nTotal due in 30 days\n\f")
+	return nil
+}
+
+// session sends requests to a server and returns its responses by id
+func session(t *testing.T, root string, requests ...string) map[string]map[string]any {
+	t.Helper()
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(NewServer(converter, root, nil).Serve(context.Background(), strings.NewReader(strings.Join(requests, "\n")), pw))
+	}()
+
+	responses := map[string]map[string]any{}
+	scanner := bufio.NewScanner(pr)
+	for scanner.Scan() {
+		var resp map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", scanner.Text(), err)
+		}
+		if resp["jsonrpc"] != "2.0" {
+			t.Errorf("expected a JSON-RPC 2.0 response, got %v", resp)
+		}
+		id, _ := json.Marshal(resp["id"])
+		responses[string(id)] = resp
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to read responses: %v", err)
+	}
+	return responses
+}
+
+func TestServer(t *testing.T) {
+	responses := session(t, "",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"extract_text","arguments":{"path":"doc.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"extract_pages","arguments":{"path":"doc.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"pdf_info","arguments":{"path":"doc.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"search_pdf","arguments":{"path":"doc.pdf","query":"TOTAL"}}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"extract_text","arguments":{"path":"missing.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"ocr","arguments":{"path":"doc.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":9,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":"ten","method":"ping"}`,
+	)
+	if len(responses) != 10 {
+		t.Errorf("expected 10 responses, got %d", len(responses))
+	}
+
+	result := func(id string) map[string]any {
+		t.Helper()
+		result, ok := responses[id]["result"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected a result for request %s, got %v", id, responses[id])
+		}
+		return result
+	}
+	text := func(id string) string {
+		t.Helper()
+		content := result(id)["content"].([]any)
+		return content[0].(map[string]any)["text"].(string)
+	}
+	errorCode := func(id string) float64 {
+		t.Helper()
+		rpcErr, ok := responses[id]["error"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected an error for request %s, got %v", id, responses[id])
+		}
+		return rpcErr["code"].(float64)
+	}
+
+	if info := result("1")["serverInfo"].(map[string]any); info["version"] != "24.02.0" {
+		t.Errorf("expected server version 24.02.0, got %v", info)
+	}
+	if tools := result("2")["tools"].([]any); len(tools) != 4 {
+		t.Errorf("expected 4 tools, got %d", len(tools))
+	}
+	if got := text("3"); got != "Invoice total 42\nShipping\n\fTerms\nTotal due in 30 days" {
+		t.Errorf("unexpected text %q", got)
+	}
+	if pages := result("4")["structuredContent"].(map[string]any)["pages"].([]any); len(pages) != 2 {
+		t.Errorf("expected 2 pages, got %v", pages)
+	}
+	if info := result("5")["structuredContent"].(map[string]any); info["pages"] != 2.0 || info["title"] != "Invoice" {
+		t.Errorf("unexpected info %v", info)
+	}
+	if got := text("6"); got != `{"matches":[{"page":1,"line":1,"text":"Invoice total 42"},{"page":2,"line":2,"text":"Total due in 30 days"}]}` {
+		t.Errorf("unexpected matches %s", got)
+	}
+	if result("7")["isError"] != true || !strings.Contains(text("7"), "error opening PDF file") {
+		t.Errorf("expected a tool error, got %v", result("7"))
+	}
+	if code := errorCode("8"); code != codeInvalidParams {
+		t.Errorf("expected error code %d, got %v", codeInvalidParams, code)
+	}
+	if code := errorCode("9"); code != codeMethodNotFound {
+		t.Errorf("expected error code %d, got %v", codeMethodNotFound, code)
+	}
+	result(`"ten"`)
+}
+
+func TestServer_Root(t *testing.T) {
+	root := t.TempDir()
+	absPath, _ := json.Marshal(filepath.Join(root, "doc.pdf"))
+	responses := session(t, root,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"extract_text","arguments":{"path":"../secret.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"extract_text","arguments":{"path":"/etc/secret.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"extract_text","arguments":{"path":"docs/doc.pdf"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"extract_text","arguments":{"path":`+string(absPath)+`}}}`,
+	)
+
+	for id, expectedError := range map[string]bool{"1": true, "2": true, "3": false, "4": false} {
+		result := responses[id]["result"].(map[string]any)
+		if isError := result["isError"] == true; isError != expectedError {
+			t.Errorf("request %s: expected isError %v, got %v", id, expectedError, result)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package pdftotext
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// memfdCloexec is MFD_CLOEXEC, see memfd_create(2)
+const memfdCloexec = 1
+
+// sysMemfdCreate is the number of the memfd_create system call, which the
+// syscall package only defines for some architectures
+var sysMemfdCreate = map[string]uintptr{"amd64": 319, "arm64": 279}[runtime.GOARCH]
+
+// createMemFile creates an anonymous file in memory with memfd_create
+func createMemFile(name string) (*os.File, error) {
+	if sysMemfdCreate == 0 {
+		return nil, errMemFileUnsupported
+	}
+	p, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	fd, _, errno := syscall.Syscall(sysMemfdCreate, uintptr(unsafe.Pointer(p)), memfdCloexec, 0)
+	if errno != 0 {
+		return nil, os.NewSyscallError("memfd_create", errno)
+	}
+	return os.NewFile(fd, name), nil
+}
+
+// memFilePath returns a path other processes of the same user, such as
+// pdftotext, can open f with. /proc/self would refer to the opening process.
+func memFilePath(f *os.File) string {
+	return "/proc/" + strconv.Itoa(os.Getpid()) + "/fd/" + strconv.FormatUint(uint64(f.Fd()), 10)
+}
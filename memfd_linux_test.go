@@ -0,0 +1,58 @@
+package pdftotext
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithMemoryStaging(t *testing.T) {
+	if f, err := createMemFile("probe"); err != nil {
+		t.Skip("memfd_create is not available:", err)
+	} else {
+		f.Close()
+	}
+
+	// The fake prints the input, which must be a memory file
+	binary := writeFakeBinary(t, `for arg in "$@"; do input=$prev; prev=$arg; done
+case "$input" in
+/proc/*) cat "$input" ;;
+*) echo "not a memory file: $input" >&2; exit 1 ;;
+esac
+`)
+	tempDir := t.TempDir()
+	converter, err := New(WithBinaryPath(binary), WithTempDir(tempDir), WithMemoryStaging())
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	text, err := converter.ConvertReader(context.Background(), strings.NewReader("%PDF-1.7 staged in memory"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "%PDF-1.7 staged in memory" {
+		t.Errorf("expected the staged input, got %q", text)
+	}
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+		t.Errorf("expected no temporary files, got %v", entries)
+	}
+
+	path, remove, err := converter.stageReader(context.Background(), strings.NewReader("data"), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "data" {
+		t.Errorf("expected the staged data at %s, got %q, %v", path, data, err)
+	}
+	if err := remove(); err != nil {
+		t.Fatalf("failed to remove the memory file: %v", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Errorf("expected %s to be gone", path)
+	}
+
+	if _, _, err := converter.stageReader(context.Background(), strings.NewReader("too large"), 3); err == nil {
+		t.Error("expected the limit to be enforced")
+	}
+}
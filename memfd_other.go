@@ -0,0 +1,14 @@
+//go:build !linux
+
+package pdftotext
+
+import "os"
+
+// createMemFile is only supported on Linux
+func createMemFile(name string) (*os.File, error) {
+	return nil, errMemFileUnsupported
+}
+
+func memFilePath(f *os.File) string {
+	return ""
+}
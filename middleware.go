@@ -0,0 +1,48 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// ConvertFunc converts a PDF file to text like Converter.Convert. It
+// implements TextConverter, so a Converter wrapped in middleware can be used
+// wherever a Converter is.
+type ConvertFunc func(ctx context.Context, inputPath string, opts *Options) (string, error)
+
+var _ TextConverter = ConvertFunc(nil)
+
+// Convert calls f(ctx, inputPath, opts)
+func (f ConvertFunc) Convert(ctx context.Context, inputPath string, opts *Options) (string, error) {
+	return f(ctx, inputPath, opts)
+}
+
+// ConvertToFile calls f(ctx, inputPath, opts) and writes the text to
+// outputPath
+func (f ConvertFunc) ConvertToFile(ctx context.Context, inputPath, outputPath string, opts *Options) error {
+	text, err := f(ctx, inputPath, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputPath, []byte(text), 0o644); err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputFile, err)
+	}
+	return nil
+}
+
+// Middleware wraps a ConvertFunc with a cross-cutting concern such as
+// caching, metrics or logging, like an http.Handler wrapping another
+type Middleware func(next ConvertFunc) ConvertFunc
+
+// Chain wraps convert in middlewares. The first middleware is the outermost,
+// so it sees each call first and each result last.
+//
+//	convert := pdftotext.Chain(converter.Convert, logging, metrics)
+//	text, err := convert(ctx, "input.pdf", nil)
+func Chain(convert ConvertFunc, middlewares ...Middleware) ConvertFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		convert = middlewares[i](convert)
+	}
+	return convert
+}
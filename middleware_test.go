@@ -0,0 +1,61 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next ConvertFunc) ConvertFunc {
+			return func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+				calls = append(calls, name+" before")
+				text, err := next(ctx, inputPath, opts)
+				calls = append(calls, name+" after")
+				return text, err
+			}
+		}
+	}
+	convert := Chain(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		calls = append(calls, "convert "+inputPath)
+		return "text", nil
+	}, trace("outer"), trace("inner"))
+
+	text, err := convert.Convert(context.Background(), "input.pdf", nil)
+	if err != nil || text != "text" {
+		t.Fatalf("expected text, got %q, %v", text, err)
+	}
+	expected := []string{"outer before", "inner before", "convert input.pdf", "inner after", "outer after"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %q, got %q", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("call %d: expected %q, got %q", i, expected[i], calls[i])
+		}
+	}
+}
+
+func TestConvertFunc_ConvertToFile(t *testing.T) {
+	dir := t.TempDir()
+	convert := ConvertFunc(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		return "text", nil
+	})
+
+	output := filepath.Join(dir, "output.txt")
+	if err := convert.ConvertToFile(context.Background(), "input.pdf", output, nil); err != nil {
+		t.Fatalf("failed to convert: %v", err)
+	}
+	if data, err := os.ReadFile(output); err != nil || string(data) != "text" {
+		t.Errorf("expected text, got %q, %v", data, err)
+	}
+
+	missing := filepath.Join(dir, "missing", "output.txt")
+	if err := convert.ConvertToFile(context.Background(), "input.pdf", missing, nil); !errors.Is(err, ErrOutputFile) {
+		t.Errorf("expected ErrOutputFile, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package pdftotext
+
+import (
+	"fmt"
+	"strings"
+)
+
+//go:generate go run gen_normalize.go
+
+// NormalizationForm is a Unicode normalization form. It is also a
+// Transformer normalizing the text of each page.
+type NormalizationForm string
+
+const (
+	// NFC composes characters with their combining marks, e.g. "é"
+	// becomes "é", so decomposed accents match in exact-match search
+	NFC NormalizationForm = "NFC"
+	// NFKC is NFC that also replaces compatibility characters with their
+	// plain equivalents, e.g. the ligature "ﬁ" becomes "fi", "²" becomes "2"
+	// and full-width letters become ASCII
+	NFKC NormalizationForm = "NFKC"
+)
+
+// Hangul syllables are composed and decomposed algorithmically
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+// Transform normalizes text to the normalization form
+func (f NormalizationForm) Transform(page int, text string) (string, error) {
+	if f != NFC && f != NFKC {
+		return "", fmt.Errorf("unknown normalization form %q", string(f))
+	}
+	return f.normalize(text), nil
+}
+
+// normalize returns s in the normalization form, which must be NFC or NFKC
+func (f NormalizationForm) normalize(s string) string {
+	ascii := true
+	for i := 0; i < len(s) && ascii; i++ {
+		ascii = s[i] < 0x80
+	}
+	if ascii {
+		return s
+	}
+
+	// decompose
+	var runes []rune
+	for _, r := range s {
+		if i := r - hangulSBase; i >= 0 && i < hangulSCount {
+			runes = append(runes, hangulLBase+i/hangulNCount, hangulVBase+i%hangulNCount/hangulTCount)
+			if t := i % hangulTCount; t > 0 {
+				runes = append(runes, hangulTBase+t)
+			}
+			continue
+		}
+		if d, ok := compatibilityDecompositions[r]; ok && f == NFKC {
+			runes = append(runes, []rune(d)...)
+		} else if d, ok := canonicalDecompositions[r]; ok {
+			runes = append(runes, []rune(d)...)
+		} else {
+			runes = append(runes, r)
+		}
+	}
+
+	// order the combining marks following each starter by combining class
+	for i := 1; i < len(runes); i++ {
+		class := combiningClass[runes[i]]
+		for j := i; j > 0 && class != 0 && combiningClass[runes[j-1]] > class; j-- {
+			runes[j-1], runes[j] = runes[j], runes[j-1]
+		}
+	}
+
+	// compose each character with the last starter unless a character in
+	// between blocks it
+	out := runes[:0]
+	starter, lastClass := -1, uint8(0)
+	for _, r := range runes {
+		class := combiningClass[r]
+		if starter >= 0 && (starter == len(out)-1 || lastClass != 0 && lastClass < class) {
+			if c, ok := compose(out[starter], r); ok {
+				out[starter] = c
+				continue
+			}
+		}
+		if class == 0 {
+			starter = len(out)
+		}
+		lastClass = class
+		out = append(out, r)
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range out {
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// compose returns the primary composite of a and b, if any
+func compose(a, b rune) (rune, bool) {
+	if l, v := a-hangulLBase, b-hangulVBase; l >= 0 && l < hangulLCount && v >= 0 && v < hangulVCount {
+		return hangulSBase + (l*hangulVCount+v)*hangulTCount, true
+	}
+	if s, t := a-hangulSBase, b-hangulTBase; s >= 0 && s < hangulSCount && s%hangulTCount == 0 && t > 0 && t < hangulTCount {
+		return a + t, true
+	}
+	c, ok := compositions[[2]rune{a, b}]
+	return c, ok
+}
@@ -0,0 +1,1938 @@
+// Code generated by gen_normalize.go from Unicode 14.0.0. DO NOT EDIT.
+
+package pdftotext
+
+// combiningClass is the canonical combining class of the non-starters
+var combiningClass = map[rune]uint8{
+	0x0300: 230, 0x0301: 230, 0x0302: 230, 0x0303: 230,
+	0x0304: 230, 0x0305: 230, 0x0306: 230, 0x0307: 230,
+	0x0308: 230, 0x0309: 230, 0x030a: 230, 0x030b: 230,
+	0x030c: 230, 0x030d: 230, 0x030e: 230, 0x030f: 230,
+	0x0310: 230, 0x0311: 230, 0x0312: 230, 0x0313: 230,
+	0x0314: 230, 0x0315: 232, 0x0316: 220, 0x0317: 220,
+	0x0318: 220, 0x0319: 220, 0x031a: 232, 0x031b: 216,
+	0x031c: 220, 0x031d: 220, 0x031e: 220, 0x031f: 220,
+	0x0320: 220, 0x0321: 202, 0x0322: 202, 0x0323: 220,
+	0x0324: 220, 0x0325: 220, 0x0326: 220, 0x0327: 202,
+	0x0328: 202, 0x0329: 220, 0x032a: 220, 0x032b: 220,
+	0x032c: 220, 0x032d: 220, 0x032e: 220, 0x032f: 220,
+	0x0330: 220, 0x0331: 220, 0x0332: 220, 0x0333: 220,
+	0x0334: 1, 0x0335: 1, 0x0336: 1, 0x0337: 1,
+	0x0338: 1, 0x0339: 220, 0x033a: 220, 0x033b: 220,
+	0x033c: 220, 0x033d: 230, 0x033e: 230, 0x033f: 230,
+	0x0340: 230, 0x0341: 230, 0x0342: 230, 0x0343: 230,
+	0x0344: 230, 0x0345: 240, 0x0346: 230, 0x0347: 220,
+	0x0348: 220, 0x0349: 220, 0x034a: 230, 0x034b: 230,
+	0x034c: 230, 0x034d: 220, 0x034e: 220, 0x0350: 230,
+	0x0351: 230, 0x0352: 230, 0x0353: 220, 0x0354: 220,
+	0x0355: 220, 0x0356: 220, 0x0357: 230, 0x0358: 232,
+	0x0359: 220, 0x035a: 220, 0x035b: 230, 0x035c: 233,
+	0x035d: 234, 0x035e: 234, 0x035f: 233, 0x0360: 234,
+	0x0361: 234, 0x0362: 233, 0x0363: 230, 0x0364: 230,
+	0x0365: 230, 0x0366: 230, 0x0367: 230, 0x0368: 230,
+	0x0369: 230, 0x036a: 230, 0x036b: 230, 0x036c: 230,
+	0x036d: 230, 0x036e: 230, 0x036f: 230, 0x0483: 230,
+	0x0484: 230, 0x0485: 230, 0x0486: 230, 0x0487: 230,
+	0x0591: 220, 0x0592: 230, 0x0593: 230, 0x0594: 230,
+	0x0595: 230, 0x0596: 220, 0x0597: 230, 0x0598: 230,
+	0x0599: 230, 0x059a: 222, 0x059b: 220, 0x059c: 230,
+	0x059d: 230, 0x059e: 230, 0x059f: 230, 0x05a0: 230,
+	0x05a1: 230, 0x05a2: 220, 0x05a3: 220, 0x05a4: 220,
+	0x05a5: 220, 0x05a6: 220, 0x05a7: 220, 0x05a8: 230,
+	0x05a9: 230, 0x05aa: 220, 0x05ab: 230, 0x05ac: 230,
+	0x05ad: 222, 0x05ae: 228, 0x05af: 230, 0x05b0: 10,
+	0x05b1: 11, 0x05b2: 12, 0x05b3: 13, 0x05b4: 14,
+	0x05b5: 15, 0x05b6: 16, 0x05b7: 17, 0x05b8: 18,
+	0x05b9: 19, 0x05ba: 19, 0x05bb: 20, 0x05bc: 21,
+	0x05bd: 22, 0x05bf: 23, 0x05c1: 24, 0x05c2: 25,
+	0x05c4: 230, 0x05c5: 220, 0x05c7: 18, 0x0610: 230,
+	0x0611: 230, 0x0612: 230, 0x0613: 230, 0x0614: 230,
+	0x0615: 230, 0x0616: 230, 0x0617: 230, 0x0618: 30,
+	0x0619: 31, 0x061a: 32, 0x064b: 27, 0x064c: 28,
+	0x064d: 29, 0x064e: 30, 0x064f: 31, 0x0650: 32,
+	0x0651: 33, 0x0652: 34, 0x0653: 230, 0x0654: 230,
+	0x0655: 220, 0x0656: 220, 0x0657: 230, 0x0658: 230,
+	0x0659: 230, 0x065a: 230, 0x065b: 230, 0x065c: 220,
+	0x065d: 230, 0x065e: 230, 0x065f: 220, 0x0670: 35,
+	0x06d6: 230, 0x06d7: 230, 0x06d8: 230, 0x06d9: 230,
+	0x06da: 230, 0x06db: 230, 0x06dc: 230, 0x06df: 230,
+	0x06e0: 230, 0x06e1: 230, 0x06e2: 230, 0x06e3: 220,
+	0x06e4: 230, 0x06e7: 230, 0x06e8: 230, 0x06ea: 220,
+	0x06eb: 230, 0x06ec: 230, 0x06ed: 220, 0x0711: 36,
+	0x0730: 230, 0x0731: 220, 0x0732: 230, 0x0733: 230,
+	0x0734: 220, 0x0735: 230, 0x0736: 230, 0x0737: 220,
+	0x0738: 220, 0x0739: 220, 0x073a: 230, 0x073b: 220,
+	0x073c: 220, 0x073d: 230, 0x073e: 220, 0x073f: 230,
+	0x0740: 230, 0x0741: 230, 0x0742: 220, 0x0743: 230,
+	0x0744: 220, 0x0745: 230, 0x0746: 220, 0x0747: 230,
+	0x0748: 220, 0x0749: 230, 0x074a: 230, 0x07eb: 230,
+	0x07ec: 230, 0x07ed: 230, 0x07ee: 230, 0x07ef: 230,
+	0x07f0: 230, 0x07f1: 230, 0x07f2: 220, 0x07f3: 230,
+	0x07fd: 220, 0x0816: 230, 0x0817: 230, 0x0818: 230,
+	0x0819: 230, 0x081b: 230, 0x081c: 230, 0x081d: 230,
+	0x081e: 230, 0x081f: 230, 0x0820: 230, 0x0821: 230,
+	0x0822: 230, 0x0823: 230, 0x0825: 230, 0x0826: 230,
+	0x0827: 230, 0x0829: 230, 0x082a: 230, 0x082b: 230,
+	0x082c: 230, 0x082d: 230, 0x0859: 220, 0x085a: 220,
+	0x085b: 220, 0x0898: 230, 0x0899: 220, 0x089a: 220,
+	0x089b: 220, 0x089c: 230, 0x089d: 230, 0x089e: 230,
+	0x089f: 230, 0x08ca: 230, 0x08cb: 230, 0x08cc: 230,
+	0x08cd: 230, 0x08ce: 230, 0x08cf: 220, 0x08d0: 220,
+	0x08d1: 220, 0x08d2: 220, 0x08d3: 220, 0x08d4: 230,
+	0x08d5: 230, 0x08d6: 230, 0x08d7: 230, 0x08d8: 230,
+	0x08d9: 230, 0x08da: 230, 0x08db: 230, 0x08dc: 230,
+	0x08dd: 230, 0x08de: 230, 0x08df: 230, 0x08e0: 230,
+	0x08e1: 230, 0x08e3: 220, 0x08e4: 230, 0x08e5: 230,
+	0x08e6: 220, 0x08e7: 230, 0x08e8: 230, 0x08e9: 220,
+	0x08ea: 230, 0x08eb: 230, 0x08ec: 230, 0x08ed: 220,
+	0x08ee: 220, 0x08ef: 220, 0x08f0: 27, 0x08f1: 28,
+	0x08f2: 29, 0x08f3: 230, 0x08f4: 230, 0x08f5: 230,
+	0x08f6: 220, 0x08f7: 230, 0x08f8: 230, 0x08f9: 220,
+	0x08fa: 220, 0x08fb: 230, 0x08fc: 230, 0x08fd: 230,
+	0x08fe: 230, 0x08ff: 230, 0x093c: 7, 0x094d: 9,
+	0x0951: 230, 0x0952: 220, 0x0953: 230, 0x0954: 230,
+	0x09bc: 7, 0x09cd: 9, 0x09fe: 230, 0x0a3c: 7,
+	0x0a4d: 9, 0x0abc: 7, 0x0acd: 9, 0x0b3c: 7,
+	0x0b4d: 9, 0x0bcd: 9, 0x0c3c: 7, 0x0c4d: 9,
+	0x0c55: 84, 0x0c56: 91, 0x0cbc: 7, 0x0ccd: 9,
+	0x0d3b: 9, 0x0d3c: 9, 0x0d4d: 9, 0x0dca: 9,
+	0x0e38: 103, 0x0e39: 103, 0x0e3a: 9, 0x0e48: 107,
+	0x0e49: 107, 0x0e4a: 107, 0x0e4b: 107, 0x0eb8: 118,
+	0x0eb9: 118, 0x0eba: 9, 0x0ec8: 122, 0x0ec9: 122,
+	0x0eca: 122, 0x0ecb: 122, 0x0f18: 220, 0x0f19: 220,
+	0x0f35: 220, 0x0f37: 220, 0x0f39: 216, 0x0f71: 129,
+	0x0f72: 130, 0x0f74: 132, 0x0f7a: 130, 0x0f7b: 130,
+	0x0f7c: 130, 0x0f7d: 130, 0x0f80: 130, 0x0f82: 230,
+	0x0f83: 230, 0x0f84: 9, 0x0f86: 230, 0x0f87: 230,
+	0x0fc6: 220, 0x1037: 7, 0x1039: 9, 0x103a: 9,
+	0x108d: 220, 0x135d: 230, 0x135e: 230, 0x135f: 230,
+	0x1714: 9, 0x1715: 9, 0x1734: 9, 0x17d2: 9,
+	0x17dd: 230, 0x18a9: 228, 0x1939: 222, 0x193a: 230,
+	0x193b: 220, 0x1a17: 230, 0x1a18: 220, 0x1a60: 9,
+	0x1a75: 230, 0x1a76: 230, 0x1a77: 230, 0x1a78: 230,
+	0x1a79: 230, 0x1a7a: 230, 0x1a7b: 230, 0x1a7c: 230,
+	0x1a7f: 220, 0x1ab0: 230, 0x1ab1: 230, 0x1ab2: 230,
+	0x1ab3: 230, 0x1ab4: 230, 0x1ab5: 220, 0x1ab6: 220,
+	0x1ab7: 220, 0x1ab8: 220, 0x1ab9: 220, 0x1aba: 220,
+	0x1abb: 230, 0x1abc: 230, 0x1abd: 220, 0x1abf: 220,
+	0x1ac0: 220, 0x1ac1: 230, 0x1ac2: 230, 0x1ac3: 220,
+	0x1ac4: 220, 0x1ac5: 230, 0x1ac6: 230, 0x1ac7: 230,
+	0x1ac8: 230, 0x1ac9: 230, 0x1aca: 220, 0x1acb: 230,
+	0x1acc: 230, 0x1acd: 230, 0x1ace: 230, 0x1b34: 7,
+	0x1b44: 9, 0x1b6b: 230, 0x1b6c: 220, 0x1b6d: 230,
+	0x1b6e: 230, 0x1b6f: 230, 0x1b70: 230, 0x1b71: 230,
+	0x1b72: 230, 0x1b73: 230, 0x1baa: 9, 0x1bab: 9,
+	0x1be6: 7, 0x1bf2: 9, 0x1bf3: 9, 0x1c37: 7,
+	0x1cd0: 230, 0x1cd1: 230, 0x1cd2: 230, 0x1cd4: 1,
+	0x1cd5: 220, 0x1cd6: 220, 0x1cd7: 220, 0x1cd8: 220,
+	0x1cd9: 220, 0x1cda: 230, 0x1cdb: 230, 0x1cdc: 220,
+	0x1cdd: 220, 0x1cde: 220, 0x1cdf: 220, 0x1ce0: 230,
+	0x1ce2: 1, 0x1ce3: 1, 0x1ce4: 1, 0x1ce5: 1,
+	0x1ce6: 1, 0x1ce7: 1, 0x1ce8: 1, 0x1ced: 220,
+	0x1cf4: 230, 0x1cf8: 230, 0x1cf9: 230, 0x1dc0: 230,
+	0x1dc1: 230, 0x1dc2: 220, 0x1dc3: 230, 0x1dc4: 230,
+	0x1dc5: 230, 0x1dc6: 230, 0x1dc7: 230, 0x1dc8: 230,
+	0x1dc9: 230, 0x1dca: 220, 0x1dcb: 230, 0x1dcc: 230,
+	0x1dcd: 234, 0x1dce: 214, 0x1dcf: 220, 0x1dd0: 202,
+	0x1dd1: 230, 0x1dd2: 230, 0x1dd3: 230, 0x1dd4: 230,
+	0x1dd5: 230, 0x1dd6: 230, 0x1dd7: 230, 0x1dd8: 230,
+	0x1dd9: 230, 0x1dda: 230, 0x1ddb: 230, 0x1ddc: 230,
+	0x1ddd: 230, 0x1dde: 230, 0x1ddf: 230, 0x1de0: 230,
+	0x1de1: 230, 0x1de2: 230, 0x1de3: 230, 0x1de4: 230,
+	0x1de5: 230, 0x1de6: 230, 0x1de7: 230, 0x1de8: 230,
+	0x1de9: 230, 0x1dea: 230, 0x1deb: 230, 0x1dec: 230,
+	0x1ded: 230, 0x1dee: 230, 0x1def: 230, 0x1df0: 230,
+	0x1df1: 230, 0x1df2: 230, 0x1df3: 230, 0x1df4: 230,
+	0x1df5: 230, 0x1df6: 232, 0x1df7: 228, 0x1df8: 228,
+	0x1df9: 220, 0x1dfa: 218, 0x1dfb: 230, 0x1dfc: 233,
+	0x1dfd: 220, 0x1dfe: 230, 0x1dff: 220, 0x20d0: 230,
+	0x20d1: 230, 0x20d2: 1, 0x20d3: 1, 0x20d4: 230,
+	0x20d5: 230, 0x20d6: 230, 0x20d7: 230, 0x20d8: 1,
+	0x20d9: 1, 0x20da: 1, 0x20db: 230, 0x20dc: 230,
+	0x20e1: 230, 0x20e5: 1, 0x20e6: 1, 0x20e7: 230,
+	0x20e8: 220, 0x20e9: 230, 0x20ea: 1, 0x20eb: 1,
+	0x20ec: 220, 0x20ed: 220, 0x20ee: 220, 0x20ef: 220,
+	0x20f0: 230, 0x2cef: 230, 0x2cf0: 230, 0x2cf1: 230,
+	0x2d7f: 9, 0x2de0: 230, 0x2de1: 230, 0x2de2: 230,
+	0x2de3: 230, 0x2de4: 230, 0x2de5: 230, 0x2de6: 230,
+	0x2de7: 230, 0x2de8: 230, 0x2de9: 230, 0x2dea: 230,
+	0x2deb: 230, 0x2dec: 230, 0x2ded: 230, 0x2dee: 230,
+	0x2def: 230, 0x2df0: 230, 0x2df1: 230, 0x2df2: 230,
+	0x2df3: 230, 0x2df4: 230, 0x2df5: 230, 0x2df6: 230,
+	0x2df7: 230, 0x2df8: 230, 0x2df9: 230, 0x2dfa: 230,
+	0x2dfb: 230, 0x2dfc: 230, 0x2dfd: 230, 0x2dfe: 230,
+	0x2dff: 230, 0x302a: 218, 0x302b: 228, 0x302c: 232,
+	0x302d: 222, 0x302e: 224, 0x302f: 224, 0x3099: 8,
+	0x309a: 8, 0xa66f: 230, 0xa674: 230, 0xa675: 230,
+	0xa676: 230, 0xa677: 230, 0xa678: 230, 0xa679: 230,
+	0xa67a: 230, 0xa67b: 230, 0xa67c: 230, 0xa67d: 230,
+	0xa69e: 230, 0xa69f: 230, 0xa6f0: 230, 0xa6f1: 230,
+	0xa806: 9, 0xa82c: 9, 0xa8c4: 9, 0xa8e0: 230,
+	0xa8e1: 230, 0xa8e2: 230, 0xa8e3: 230, 0xa8e4: 230,
+	0xa8e5: 230, 0xa8e6: 230, 0xa8e7: 230, 0xa8e8: 230,
+	0xa8e9: 230, 0xa8ea: 230, 0xa8eb: 230, 0xa8ec: 230,
+	0xa8ed: 230, 0xa8ee: 230, 0xa8ef: 230, 0xa8f0: 230,
+	0xa8f1: 230, 0xa92b: 220, 0xa92c: 220, 0xa92d: 220,
+	0xa953: 9, 0xa9b3: 7, 0xa9c0: 9, 0xaab0: 230,
+	0xaab2: 230, 0xaab3: 230, 0xaab4: 220, 0xaab7: 230,
+	0xaab8: 230, 0xaabe: 230, 0xaabf: 230, 0xaac1: 230,
+	0xaaf6: 9, 0xabed: 9, 0xfb1e: 26, 0xfe20: 230,
+	0xfe21: 230, 0xfe22: 230, 0xfe23: 230, 0xfe24: 230,
+	0xfe25: 230, 0xfe26: 230, 0xfe27: 220, 0xfe28: 220,
+	0xfe29: 220, 0xfe2a: 220, 0xfe2b: 220, 0xfe2c: 220,
+	0xfe2d: 220, 0xfe2e: 230, 0xfe2f: 230, 0x101fd: 220,
+	0x102e0: 220, 0x10376: 230, 0x10377: 230, 0x10378: 230,
+	0x10379: 230, 0x1037a: 230, 0x10a0d: 220, 0x10a0f: 230,
+	0x10a38: 230, 0x10a39: 1, 0x10a3a: 220, 0x10a3f: 9,
+	0x10ae5: 230, 0x10ae6: 220, 0x10d24: 230, 0x10d25: 230,
+	0x10d26: 230, 0x10d27: 230, 0x10eab: 230, 0x10eac: 230,
+	0x10f46: 220, 0x10f47: 220, 0x10f48: 230, 0x10f49: 230,
+	0x10f4a: 230, 0x10f4b: 220, 0x10f4c: 230, 0x10f4d: 220,
+	0x10f4e: 220, 0x10f4f: 220, 0x10f50: 220, 0x10f82: 230,
+	0x10f83: 220, 0x10f84: 230, 0x10f85: 220, 0x11046: 9,
+	0x11070: 9, 0x1107f: 9, 0x110b9: 9, 0x110ba: 7,
+	0x11100: 230, 0x11101: 230, 0x11102: 230, 0x11133: 9,
+	0x11134: 9, 0x11173: 7, 0x111c0: 9, 0x111ca: 7,
+	0x11235: 9, 0x11236: 7, 0x112e9: 7, 0x112ea: 9,
+	0x1133b: 7, 0x1133c: 7, 0x1134d: 9, 0x11366: 230,
+	0x11367: 230, 0x11368: 230, 0x11369: 230, 0x1136a: 230,
+	0x1136b: 230, 0x1136c: 230, 0x11370: 230, 0x11371: 230,
+	0x11372: 230, 0x11373: 230, 0x11374: 230, 0x11442: 9,
+	0x11446: 7, 0x1145e: 230, 0x114c2: 9, 0x114c3: 7,
+	0x115bf: 9, 0x115c0: 7, 0x1163f: 9, 0x116b6: 9,
+	0x116b7: 7, 0x1172b: 9, 0x11839: 9, 0x1183a: 7,
+	0x1193d: 9, 0x1193e: 9, 0x11943: 7, 0x119e0: 9,
+	0x11a34: 9, 0x11a47: 9, 0x11a99: 9, 0x11c3f: 9,
+	0x11d42: 7, 0x11d44: 9, 0x11d45: 9, 0x11d97: 9,
+	0x16af0: 1, 0x16af1: 1, 0x16af2: 1, 0x16af3: 1,
+	0x16af4: 1, 0x16b30: 230, 0x16b31: 230, 0x16b32: 230,
+	0x16b33: 230, 0x16b34: 230, 0x16b35: 230, 0x16b36: 230,
+	0x16ff0: 6, 0x16ff1: 6, 0x1bc9e: 1, 0x1d165: 216,
+	0x1d166: 216, 0x1d167: 1, 0x1d168: 1, 0x1d169: 1,
+	0x1d16d: 226, 0x1d16e: 216, 0x1d16f: 216, 0x1d170: 216,
+	0x1d171: 216, 0x1d172: 216, 0x1d17b: 220, 0x1d17c: 220,
+	0x1d17d: 220, 0x1d17e: 220, 0x1d17f: 220, 0x1d180: 220,
+	0x1d181: 220, 0x1d182: 220, 0x1d185: 230, 0x1d186: 230,
+	0x1d187: 230, 0x1d188: 230, 0x1d189: 230, 0x1d18a: 220,
+	0x1d18b: 220, 0x1d1aa: 230, 0x1d1ab: 230, 0x1d1ac: 230,
+	0x1d1ad: 230, 0x1d242: 230, 0x1d243: 230, 0x1d244: 230,
+	0x1e000: 230, 0x1e001: 230, 0x1e002: 230, 0x1e003: 230,
+	0x1e004: 230, 0x1e005: 230, 0x1e006: 230, 0x1e008: 230,
+	0x1e009: 230, 0x1e00a: 230, 0x1e00b: 230, 0x1e00c: 230,
+	0x1e00d: 230, 0x1e00e: 230, 0x1e00f: 230, 0x1e010: 230,
+	0x1e011: 230, 0x1e012: 230, 0x1e013: 230, 0x1e014: 230,
+	0x1e015: 230, 0x1e016: 230, 0x1e017: 230, 0x1e018: 230,
+	0x1e01b: 230, 0x1e01c: 230, 0x1e01d: 230, 0x1e01e: 230,
+	0x1e01f: 230, 0x1e020: 230, 0x1e021: 230, 0x1e023: 230,
+	0x1e024: 230, 0x1e026: 230, 0x1e027: 230, 0x1e028: 230,
+	0x1e029: 230, 0x1e02a: 230, 0x1e130: 230, 0x1e131: 230,
+	0x1e132: 230, 0x1e133: 230, 0x1e134: 230, 0x1e135: 230,
+	0x1e136: 230, 0x1e2ae: 230, 0x1e2ec: 230, 0x1e2ed: 230,
+	0x1e2ee: 230, 0x1e2ef: 230, 0x1e8d0: 220, 0x1e8d1: 220,
+	0x1e8d2: 220, 0x1e8d3: 220, 0x1e8d4: 220, 0x1e8d5: 220,
+	0x1e8d6: 220, 0x1e944: 230, 0x1e945: 230, 0x1e946: 230,
+	0x1e947: 230, 0x1e948: 230, 0x1e949: 230, 0x1e94a: 7,
+}
+
+// canonicalDecompositions are the full canonical decompositions
+var canonicalDecompositions = map[rune]string{
+	0x00c0: "A\u0300", 0x00c1: "A\u0301", 0x00c2: "A\u0302", 0x00c3: "A\u0303",
+	0x00c4: "A\u0308", 0x00c5: "A\u030a", 0x00c7: "C\u0327", 0x00c8: "E\u0300",
+	0x00c9: "E\u0301", 0x00ca: "E\u0302", 0x00cb: "E\u0308", 0x00cc: "I\u0300",
+	0x00cd: "I\u0301", 0x00ce: "I\u0302", 0x00cf: "I\u0308", 0x00d1: "N\u0303",
+	0x00d2: "O\u0300", 0x00d3: "O\u0301", 0x00d4: "O\u0302", 0x00d5: "O\u0303",
+	0x00d6: "O\u0308", 0x00d9: "U\u0300", 0x00da: "U\u0301", 0x00db: "U\u0302",
+	0x00dc: "U\u0308", 0x00dd: "Y\u0301", 0x00e0: "a\u0300", 0x00e1: "a\u0301",
+	0x00e2: "a\u0302", 0x00e3: "a\u0303", 0x00e4: "a\u0308", 0x00e5: "a\u030a",
+	0x00e7: "c\u0327", 0x00e8: "e\u0300", 0x00e9: "e\u0301", 0x00ea: "e\u0302",
+	0x00eb: "e\u0308", 0x00ec: "i\u0300", 0x00ed: "i\u0301", 0x00ee: "i\u0302",
+	0x00ef: "i\u0308", 0x00f1: "n\u0303", 0x00f2: "o\u0300", 0x00f3: "o\u0301",
+	0x00f4: "o\u0302", 0x00f5: "o\u0303", 0x00f6: "o\u0308", 0x00f9: "u\u0300",
+	0x00fa: "u\u0301", 0x00fb: "u\u0302", 0x00fc: "u\u0308", 0x00fd: "y\u0301",
+	0x00ff: "y\u0308", 0x0100: "A\u0304", 0x0101: "a\u0304", 0x0102: "A\u0306",
+	0x0103: "a\u0306", 0x0104: "A\u0328", 0x0105: "a\u0328", 0x0106: "C\u0301",
+	0x0107: "c\u0301", 0x0108: "C\u0302", 0x0109: "c\u0302", 0x010a: "C\u0307",
+	0x010b: "c\u0307", 0x010c: "C\u030c", 0x010d: "c\u030c", 0x010e: "D\u030c",
+	0x010f: "d\u030c", 0x0112: "E\u0304", 0x0113: "e\u0304", 0x0114: "E\u0306",
+	0x0115: "e\u0306", 0x0116: "E\u0307", 0x0117: "e\u0307", 0x0118: "E\u0328",
+	0x0119: "e\u0328", 0x011a: "E\u030c", 0x011b: "e\u030c", 0x011c: "G\u0302",
+	0x011d: "g\u0302", 0x011e: "G\u0306", 0x011f: "g\u0306", 0x0120: "G\u0307",
+	0x0121: "g\u0307", 0x0122: "G\u0327", 0x0123: "g\u0327", 0x0124: "H\u0302",
+	0x0125: "h\u0302", 0x0128: "I\u0303", 0x0129: "i\u0303", 0x012a: "I\u0304",
+	0x012b: "i\u0304", 0x012c: "I\u0306", 0x012d: "i\u0306", 0x012e: "I\u0328",
+	0x012f: "i\u0328", 0x0130: "I\u0307", 0x0134: "J\u0302", 0x0135: "j\u0302",
+	0x0136: "K\u0327", 0x0137: "k\u0327", 0x0139: "L\u0301", 0x013a: "l\u0301",
+	0x013b: "L\u0327", 0x013c: "l\u0327", 0x013d: "L\u030c", 0x013e: "l\u030c",
+	0x0143: "N\u0301", 0x0144: "n\u0301", 0x0145: "N\u0327", 0x0146: "n\u0327",
+	0x0147: "N\u030c", 0x0148: "n\u030c", 0x014c: "O\u0304", 0x014d: "o\u0304",
+	0x014e: "O\u0306", 0x014f: "o\u0306", 0x0150: "O\u030b", 0x0151: "o\u030b",
+	0x0154: "R\u0301", 0x0155: "r\u0301", 0x0156: "R\u0327", 0x0157: "r\u0327",
+	0x0158: "R\u030c", 0x0159: "r\u030c", 0x015a: "S\u0301", 0x015b: "s\u0301",
+	0x015c: "S\u0302", 0x015d: "s\u0302", 0x015e: "S\u0327", 0x015f: "s\u0327",
+	0x0160: "S\u030c", 0x0161: "s\u030c", 0x0162: "T\u0327", 0x0163: "t\u0327",
+	0x0164: "T\u030c", 0x0165: "t\u030c", 0x0168: "U\u0303", 0x0169: "u\u0303",
+	0x016a: "U\u0304", 0x016b: "u\u0304", 0x016c: "U\u0306", 0x016d: "u\u0306",
+	0x016e: "U\u030a", 0x016f: "u\u030a", 0x0170: "U\u030b", 0x0171: "u\u030b",
+	0x0172: "U\u0328", 0x0173: "u\u0328", 0x0174: "W\u0302", 0x0175: "w\u0302",
+	0x0176: "Y\u0302", 0x0177: "y\u0302", 0x0178: "Y\u0308", 0x0179: "Z\u0301",
+	0x017a: "z\u0301", 0x017b: "Z\u0307", 0x017c: "z\u0307", 0x017d: "Z\u030c",
+	0x017e: "z\u030c", 0x01a0: "O\u031b", 0x01a1: "o\u031b", 0x01af: "U\u031b",
+	0x01b0: "u\u031b", 0x01cd: "A\u030c", 0x01ce: "a\u030c", 0x01cf: "I\u030c",
+	0x01d0: "i\u030c", 0x01d1: "O\u030c", 0x01d2: "o\u030c", 0x01d3: "U\u030c",
+	0x01d4: "u\u030c", 0x01d5: "U\u0308\u0304", 0x01d6: "u\u0308\u0304", 0x01d7: "U\u0308\u0301",
+	0x01d8: "u\u0308\u0301", 0x01d9: "U\u0308\u030c", 0x01da: "u\u0308\u030c", 0x01db: "U\u0308\u0300",
+	0x01dc: "u\u0308\u0300", 0x01de: "A\u0308\u0304", 0x01df: "a\u0308\u0304", 0x01e0: "A\u0307\u0304",
+	0x01e1: "a\u0307\u0304", 0x01e2: "\u00c6\u0304", 0x01e3: "\u00e6\u0304", 0x01e6: "G\u030c",
+	0x01e7: "g\u030c", 0x01e8: "K\u030c", 0x01e9: "k\u030c", 0x01ea: "O\u0328",
+	0x01eb: "o\u0328", 0x01ec: "O\u0328\u0304", 0x01ed: "o\u0328\u0304", 0x01ee: "\u01b7\u030c",
+	0x01ef: "\u0292\u030c", 0x01f0: "j\u030c", 0x01f4: "G\u0301", 0x01f5: "g\u0301",
+	0x01f8: "N\u0300", 0x01f9: "n\u0300", 0x01fa: "A\u030a\u0301", 0x01fb: "a\u030a\u0301",
+	0x01fc: "\u00c6\u0301", 0x01fd: "\u00e6\u0301", 0x01fe: "\u00d8\u0301", 0x01ff: "\u00f8\u0301",
+	0x0200: "A\u030f", 0x0201: "a\u030f", 0x0202: "A\u0311", 0x0203: "a\u0311",
+	0x0204: "E\u030f", 0x0205: "e\u030f", 0x0206: "E\u0311", 0x0207: "e\u0311",
+	0x0208: "I\u030f", 0x0209: "i\u030f", 0x020a: "I\u0311", 0x020b: "i\u0311",
+	0x020c: "O\u030f", 0x020d: "o\u030f", 0x020e: "O\u0311", 0x020f: "o\u0311",
+	0x0210: "R\u030f", 0x0211: "r\u030f", 0x0212: "R\u0311", 0x0213: "r\u0311",
+	0x0214: "U\u030f", 0x0215: "u\u030f", 0x0216: "U\u0311", 0x0217: "u\u0311",
+	0x0218: "S\u0326", 0x0219: "s\u0326", 0x021a: "T\u0326", 0x021b: "t\u0326",
+	0x021e: "H\u030c", 0x021f: "h\u030c", 0x0226: "A\u0307", 0x0227: "a\u0307",
+	0x0228: "E\u0327", 0x0229: "e\u0327", 0x022a: "O\u0308\u0304", 0x022b: "o\u0308\u0304",
+	0x022c: "O\u0303\u0304", 0x022d: "o\u0303\u0304", 0x022e: "O\u0307", 0x022f: "o\u0307",
+	0x0230: "O\u0307\u0304", 0x0231: "o\u0307\u0304", 0x0232: "Y\u0304", 0x0233: "y\u0304",
+	0x0340: "\u0300", 0x0341: "\u0301", 0x0343: "\u0313", 0x0344: "\u0308\u0301",
+	0x0374: "\u02b9", 0x037e: ";", 0x0385: "\u00a8\u0301", 0x0386: "\u0391\u0301",
+	0x0387: "\u00b7", 0x0388: "\u0395\u0301", 0x0389: "\u0397\u0301", 0x038a: "\u0399\u0301",
+	0x038c: "\u039f\u0301", 0x038e: "\u03a5\u0301", 0x038f: "\u03a9\u0301", 0x0390: "\u03b9\u0308\u0301",
+	0x03aa: "\u0399\u0308", 0x03ab: "\u03a5\u0308", 0x03ac: "\u03b1\u0301", 0x03ad: "\u03b5\u0301",
+	0x03ae: "\u03b7\u0301", 0x03af: "\u03b9\u0301", 0x03b0: "\u03c5\u0308\u0301", 0x03ca: "\u03b9\u0308",
+	0x03cb: "\u03c5\u0308", 0x03cc: "\u03bf\u0301", 0x03cd: "\u03c5\u0301", 0x03ce: "\u03c9\u0301",
+	0x03d3: "\u03d2\u0301", 0x03d4: "\u03d2\u0308", 0x0400: "\u0415\u0300", 0x0401: "\u0415\u0308",
+	0x0403: "\u0413\u0301", 0x0407: "\u0406\u0308", 0x040c: "\u041a\u0301", 0x040d: "\u0418\u0300",
+	0x040e: "\u0423\u0306", 0x0419: "\u0418\u0306", 0x0439: "\u0438\u0306", 0x0450: "\u0435\u0300",
+	0x0451: "\u0435\u0308", 0x0453: "\u0433\u0301", 0x0457: "\u0456\u0308", 0x045c: "\u043a\u0301",
+	0x045d: "\u0438\u0300", 0x045e: "\u0443\u0306", 0x0476: "\u0474\u030f", 0x0477: "\u0475\u030f",
+	0x04c1: "\u0416\u0306", 0x04c2: "\u0436\u0306", 0x04d0: "\u0410\u0306", 0x04d1: "\u0430\u0306",
+	0x04d2: "\u0410\u0308", 0x04d3: "\u0430\u0308", 0x04d6: "\u0415\u0306", 0x04d7: "\u0435\u0306",
+	0x04da: "\u04d8\u0308", 0x04db: "\u04d9\u0308", 0x04dc: "\u0416\u0308", 0x04dd: "\u0436\u0308",
+	0x04de: "\u0417\u0308", 0x04df: "\u0437\u0308", 0x04e2: "\u0418\u0304", 0x04e3: "\u0438\u0304",
+	0x04e4: "\u0418\u0308", 0x04e5: "\u0438\u0308", 0x04e6: "\u041e\u0308", 0x04e7: "\u043e\u0308",
+	0x04ea: "\u04e8\u0308", 0x04eb: "\u04e9\u0308", 0x04ec: "\u042d\u0308", 0x04ed: "\u044d\u0308",
+	0x04ee: "\u0423\u0304", 0x04ef: "\u0443\u0304", 0x04f0: "\u0423\u0308", 0x04f1: "\u0443\u0308",
+	0x04f2: "\u0423\u030b", 0x04f3: "\u0443\u030b", 0x04f4: "\u0427\u0308", 0x04f5: "\u0447\u0308",
+	0x04f8: "\u042b\u0308", 0x04f9: "\u044b\u0308", 0x0622: "\u0627\u0653", 0x0623: "\u0627\u0654",
+	0x0624: "\u0648\u0654", 0x0625: "\u0627\u0655", 0x0626: "\u064a\u0654", 0x06c0: "\u06d5\u0654",
+	0x06c2: "\u06c1\u0654", 0x06d3: "\u06d2\u0654", 0x0929: "\u0928\u093c", 0x0931: "\u0930\u093c",
+	0x0934: "\u0933\u093c", 0x0958: "\u0915\u093c", 0x0959: "\u0916\u093c", 0x095a: "\u0917\u093c",
+	0x095b: "\u091c\u093c", 0x095c: "\u0921\u093c", 0x095d: "\u0922\u093c", 0x095e: "\u092b\u093c",
+	0x095f: "\u092f\u093c", 0x09cb: "\u09c7\u09be", 0x09cc: "\u09c7\u09d7", 0x09dc: "\u09a1\u09bc",
+	0x09dd: "\u09a2\u09bc", 0x09df: "\u09af\u09bc", 0x0a33: "\u0a32\u0a3c", 0x0a36: "\u0a38\u0a3c",
+	0x0a59: "\u0a16\u0a3c", 0x0a5a: "\u0a17\u0a3c", 0x0a5b: "\u0a1c\u0a3c", 0x0a5e: "\u0a2b\u0a3c",
+	0x0b48: "\u0b47\u0b56", 0x0b4b: "\u0b47\u0b3e", 0x0b4c: "\u0b47\u0b57", 0x0b5c: "\u0b21\u0b3c",
+	0x0b5d: "\u0b22\u0b3c", 0x0b94: "\u0b92\u0bd7", 0x0bca: "\u0bc6\u0bbe", 0x0bcb: "\u0bc7\u0bbe",
+	0x0bcc: "\u0bc6\u0bd7", 0x0c48: "\u0c46\u0c56", 0x0cc0: "\u0cbf\u0cd5", 0x0cc7: "\u0cc6\u0cd5",
+	0x0cc8: "\u0cc6\u0cd6", 0x0cca: "\u0cc6\u0cc2", 0x0ccb: "\u0cc6\u0cc2\u0cd5", 0x0d4a: "\u0d46\u0d3e",
+	0x0d4b: "\u0d47\u0d3e", 0x0d4c: "\u0d46\u0d57", 0x0dda: "\u0dd9\u0dca", 0x0ddc: "\u0dd9\u0dcf",
+	0x0ddd: "\u0dd9\u0dcf\u0dca", 0x0dde: "\u0dd9\u0ddf", 0x0f43: "\u0f42\u0fb7", 0x0f4d: "\u0f4c\u0fb7",
+	0x0f52: "\u0f51\u0fb7", 0x0f57: "\u0f56\u0fb7", 0x0f5c: "\u0f5b\u0fb7", 0x0f69: "\u0f40\u0fb5",
+	0x0f73: "\u0f71\u0f72", 0x0f75: "\u0f71\u0f74", 0x0f76: "\u0fb2\u0f80", 0x0f78: "\u0fb3\u0f80",
+	0x0f81: "\u0f71\u0f80", 0x0f93: "\u0f92\u0fb7", 0x0f9d: "\u0f9c\u0fb7", 0x0fa2: "\u0fa1\u0fb7",
+	0x0fa7: "\u0fa6\u0fb7", 0x0fac: "\u0fab\u0fb7", 0x0fb9: "\u0f90\u0fb5", 0x1026: "\u1025\u102e",
+	0x1b06: "\u1b05\u1b35", 0x1b08: "\u1b07\u1b35", 0x1b0a: "\u1b09\u1b35", 0x1b0c: "\u1b0b\u1b35",
+	0x1b0e: "\u1b0d\u1b35", 0x1b12: "\u1b11\u1b35", 0x1b3b: "\u1b3a\u1b35", 0x1b3d: "\u1b3c\u1b35",
+	0x1b40: "\u1b3e\u1b35", 0x1b41: "\u1b3f\u1b35", 0x1b43: "\u1b42\u1b35", 0x1e00: "A\u0325",
+	0x1e01: "a\u0325", 0x1e02: "B\u0307", 0x1e03: "b\u0307", 0x1e04: "B\u0323",
+	0x1e05: "b\u0323", 0x1e06: "B\u0331", 0x1e07: "b\u0331", 0x1e08: "C\u0327\u0301",
+	0x1e09: "c\u0327\u0301", 0x1e0a: "D\u0307", 0x1e0b: "d\u0307", 0x1e0c: "D\u0323",
+	0x1e0d: "d\u0323", 0x1e0e: "D\u0331", 0x1e0f: "d\u0331", 0x1e10: "D\u0327",
+	0x1e11: "d\u0327", 0x1e12: "D\u032d", 0x1e13: "d\u032d", 0x1e14: "E\u0304\u0300",
+	0x1e15: "e\u0304\u0300", 0x1e16: "E\u0304\u0301", 0x1e17: "e\u0304\u0301", 0x1e18: "E\u032d",
+	0x1e19: "e\u032d", 0x1e1a: "E\u0330", 0x1e1b: "e\u0330", 0x1e1c: "E\u0327\u0306",
+	0x1e1d: "e\u0327\u0306", 0x1e1e: "F\u0307", 0x1e1f: "f\u0307", 0x1e20: "G\u0304",
+	0x1e21: "g\u0304", 0x1e22: "H\u0307", 0x1e23: "h\u0307", 0x1e24: "H\u0323",
+	0x1e25: "h\u0323", 0x1e26: "H\u0308", 0x1e27: "h\u0308", 0x1e28: "H\u0327",
+	0x1e29: "h\u0327", 0x1e2a: "H\u032e", 0x1e2b: "h\u032e", 0x1e2c: "I\u0330",
+	0x1e2d: "i\u0330", 0x1e2e: "I\u0308\u0301", 0x1e2f: "i\u0308\u0301", 0x1e30: "K\u0301",
+	0x1e31: "k\u0301", 0x1e32: "K\u0323", 0x1e33: "k\u0323", 0x1e34: "K\u0331",
+	0x1e35: "k\u0331", 0x1e36: "L\u0323", 0x1e37: "l\u0323", 0x1e38: "L\u0323\u0304",
+	0x1e39: "l\u0323\u0304", 0x1e3a: "L\u0331", 0x1e3b: "l\u0331", 0x1e3c: "L\u032d",
+	0x1e3d: "l\u032d", 0x1e3e: "M\u0301", 0x1e3f: "m\u0301", 0x1e40: "M\u0307",
+	0x1e41: "m\u0307", 0x1e42: "M\u0323", 0x1e43: "m\u0323", 0x1e44: "N\u0307",
+	0x1e45: "n\u0307", 0x1e46: "N\u0323", 0x1e47: "n\u0323", 0x1e48: "N\u0331",
+	0x1e49: "n\u0331", 0x1e4a: "N\u032d", 0x1e4b: "n\u032d", 0x1e4c: "O\u0303\u0301",
+	0x1e4d: "o\u0303\u0301", 0x1e4e: "O\u0303\u0308", 0x1e4f: "o\u0303\u0308", 0x1e50: "O\u0304\u0300",
+	0x1e51: "o\u0304\u0300", 0x1e52: "O\u0304\u0301", 0x1e53: "o\u0304\u0301", 0x1e54: "P\u0301",
+	0x1e55: "p\u0301", 0x1e56: "P\u0307", 0x1e57: "p\u0307", 0x1e58: "R\u0307",
+	0x1e59: "r\u0307", 0x1e5a: "R\u0323", 0x1e5b: "r\u0323", 0x1e5c: "R\u0323\u0304",
+	0x1e5d: "r\u0323\u0304", 0x1e5e: "R\u0331", 0x1e5f: "r\u0331", 0x1e60: "S\u0307",
+	0x1e61: "s\u0307", 0x1e62: "S\u0323", 0x1e63: "s\u0323", 0x1e64: "S\u0301\u0307",
+	0x1e65: "s\u0301\u0307", 0x1e66: "S\u030c\u0307", 0x1e67: "s\u030c\u0307", 0x1e68: "S\u0323\u0307",
+	0x1e69: "s\u0323\u0307", 0x1e6a: "T\u0307", 0x1e6b: "t\u0307", 0x1e6c: "T\u0323",
+	0x1e6d: "t\u0323", 0x1e6e: "T\u0331", 0x1e6f: "t\u0331", 0x1e70: "T\u032d",
+	0x1e71: "t\u032d", 0x1e72: "U\u0324", 0x1e73: "u\u0324", 0x1e74: "U\u0330",
+	0x1e75: "u\u0330", 0x1e76: "U\u032d", 0x1e77: "u\u032d", 0x1e78: "U\u0303\u0301",
+	0x1e79: "u\u0303\u0301", 0x1e7a: "U\u0304\u0308", 0x1e7b: "u\u0304\u0308", 0x1e7c: "V\u0303",
+	0x1e7d: "v\u0303", 0x1e7e: "V\u0323", 0x1e7f: "v\u0323", 0x1e80: "W\u0300",
+	0x1e81: "w\u0300", 0x1e82: "W\u0301", 0x1e83: "w\u0301", 0x1e84: "W\u0308",
+	0x1e85: "w\u0308", 0x1e86: "W\u0307", 0x1e87: "w\u0307", 0x1e88: "W\u0323",
+	0x1e89: "w\u0323", 0x1e8a: "X\u0307", 0x1e8b: "x\u0307", 0x1e8c: "X\u0308",
+	0x1e8d: "x\u0308", 0x1e8e: "Y\u0307", 0x1e8f: "y\u0307", 0x1e90: "Z\u0302",
+	0x1e91: "z\u0302", 0x1e92: "Z\u0323", 0x1e93: "z\u0323", 0x1e94: "Z\u0331",
+	0x1e95: "z\u0331", 0x1e96: "h\u0331", 0x1e97: "t\u0308", 0x1e98: "w\u030a",
+	0x1e99: "y\u030a", 0x1e9b: "\u017f\u0307", 0x1ea0: "A\u0323", 0x1ea1: "a\u0323",
+	0x1ea2: "A\u0309", 0x1ea3: "a\u0309", 0x1ea4: "A\u0302\u0301", 0x1ea5: "a\u0302\u0301",
+	0x1ea6: "A\u0302\u0300", 0x1ea7: "a\u0302\u0300", 0x1ea8: "A\u0302\u0309", 0x1ea9: "a\u0302\u0309",
+	0x1eaa: "A\u0302\u0303", 0x1eab: "a\u0302\u0303", 0x1eac: "A\u0323\u0302", 0x1ead: "a\u0323\u0302",
+	0x1eae: "A\u0306\u0301", 0x1eaf: "a\u0306\u0301", 0x1eb0: "A\u0306\u0300", 0x1eb1: "a\u0306\u0300",
+	0x1eb2: "A\u0306\u0309", 0x1eb3: "a\u0306\u0309", 0x1eb4: "A\u0306\u0303", 0x1eb5: "a\u0306\u0303",
+	0x1eb6: "A\u0323\u0306", 0x1eb7: "a\u0323\u0306", 0x1eb8: "E\u0323", 0x1eb9: "e\u0323",
+	0x1eba: "E\u0309", 0x1ebb: "e\u0309", 0x1ebc: "E\u0303", 0x1ebd: "e\u0303",
+	0x1ebe: "E\u0302\u0301", 0x1ebf: "e\u0302\u0301", 0x1ec0: "E\u0302\u0300", 0x1ec1: "e\u0302\u0300",
+	0x1ec2: "E\u0302\u0309", 0x1ec3: "e\u0302\u0309", 0x1ec4: "E\u0302\u0303", 0x1ec5: "e\u0302\u0303",
+	0x1ec6: "E\u0323\u0302", 0x1ec7: "e\u0323\u0302", 0x1ec8: "I\u0309", 0x1ec9: "i\u0309",
+	0x1eca: "I\u0323", 0x1ecb: "i\u0323", 0x1ecc: "O\u0323", 0x1ecd: "o\u0323",
+	0x1ece: "O\u0309", 0x1ecf: "o\u0309", 0x1ed0: "O\u0302\u0301", 0x1ed1: "o\u0302\u0301",
+	0x1ed2: "O\u0302\u0300", 0x1ed3: "o\u0302\u0300", 0x1ed4: "O\u0302\u0309", 0x1ed5: "o\u0302\u0309",
+	0x1ed6: "O\u0302\u0303", 0x1ed7: "o\u0302\u0303", 0x1ed8: "O\u0323\u0302", 0x1ed9: "o\u0323\u0302",
+	0x1eda: "O\u031b\u0301", 0x1edb: "o\u031b\u0301", 0x1edc: "O\u031b\u0300", 0x1edd: "o\u031b\u0300",
+	0x1ede: "O\u031b\u0309", 0x1edf: "o\u031b\u0309", 0x1ee0: "O\u031b\u0303", 0x1ee1: "o\u031b\u0303",
+	0x1ee2: "O\u031b\u0323", 0x1ee3: "o\u031b\u0323", 0x1ee4: "U\u0323", 0x1ee5: "u\u0323",
+	0x1ee6: "U\u0309", 0x1ee7: "u\u0309", 0x1ee8: "U\u031b\u0301", 0x1ee9: "u\u031b\u0301",
+	0x1eea: "U\u031b\u0300", 0x1eeb: "u\u031b\u0300", 0x1eec: "U\u031b\u0309", 0x1eed: "u\u031b\u0309",
+	0x1eee: "U\u031b\u0303", 0x1eef: "u\u031b\u0303", 0x1ef0: "U\u031b\u0323", 0x1ef1: "u\u031b\u0323",
+	0x1ef2: "Y\u0300", 0x1ef3: "y\u0300", 0x1ef4: "Y\u0323", 0x1ef5: "y\u0323",
+	0x1ef6: "Y\u0309", 0x1ef7: "y\u0309", 0x1ef8: "Y\u0303", 0x1ef9: "y\u0303",
+	0x1f00: "\u03b1\u0313", 0x1f01: "\u03b1\u0314", 0x1f02: "\u03b1\u0313\u0300", 0x1f03: "\u03b1\u0314\u0300",
+	0x1f04: "\u03b1\u0313\u0301", 0x1f05: "\u03b1\u0314\u0301", 0x1f06: "\u03b1\u0313\u0342", 0x1f07: "\u03b1\u0314\u0342",
+	0x1f08: "\u0391\u0313", 0x1f09: "\u0391\u0314", 0x1f0a: "\u0391\u0313\u0300", 0x1f0b: "\u0391\u0314\u0300",
+	0x1f0c: "\u0391\u0313\u0301", 0x1f0d: "\u0391\u0314\u0301", 0x1f0e: "\u0391\u0313\u0342", 0x1f0f: "\u0391\u0314\u0342",
+	0x1f10: "\u03b5\u0313", 0x1f11: "\u03b5\u0314", 0x1f12: "\u03b5\u0313\u0300", 0x1f13: "\u03b5\u0314\u0300",
+	0x1f14: "\u03b5\u0313\u0301", 0x1f15: "\u03b5\u0314\u0301", 0x1f18: "\u0395\u0313", 0x1f19: "\u0395\u0314",
+	0x1f1a: "\u0395\u0313\u0300", 0x1f1b: "\u0395\u0314\u0300", 0x1f1c: "\u0395\u0313\u0301", 0x1f1d: "\u0395\u0314\u0301",
+	0x1f20: "\u03b7\u0313", 0x1f21: "\u03b7\u0314", 0x1f22: "\u03b7\u0313\u0300", 0x1f23: "\u03b7\u0314\u0300",
+	0x1f24: "\u03b7\u0313\u0301", 0x1f25: "\u03b7\u0314\u0301", 0x1f26: "\u03b7\u0313\u0342", 0x1f27: "\u03b7\u0314\u0342",
+	0x1f28: "\u0397\u0313", 0x1f29: "\u0397\u0314", 0x1f2a: "\u0397\u0313\u0300", 0x1f2b: "\u0397\u0314\u0300",
+	0x1f2c: "\u0397\u0313\u0301", 0x1f2d: "\u0397\u0314\u0301", 0x1f2e: "\u0397\u0313\u0342", 0x1f2f: "\u0397\u0314\u0342",
+	0x1f30: "\u03b9\u0313", 0x1f31: "\u03b9\u0314", 0x1f32: "\u03b9\u0313\u0300", 0x1f33: "\u03b9\u0314\u0300",
+	0x1f34: "\u03b9\u0313\u0301", 0x1f35: "\u03b9\u0314\u0301", 0x1f36: "\u03b9\u0313\u0342", 0x1f37: "\u03b9\u0314\u0342",
+	0x1f38: "\u0399\u0313", 0x1f39: "\u0399\u0314", 0x1f3a: "\u0399\u0313\u0300", 0x1f3b: "\u0399\u0314\u0300",
+	0x1f3c: "\u0399\u0313\u0301", 0x1f3d: "\u0399\u0314\u0301", 0x1f3e: "\u0399\u0313\u0342", 0x1f3f: "\u0399\u0314\u0342",
+	0x1f40: "\u03bf\u0313", 0x1f41: "\u03bf\u0314", 0x1f42: "\u03bf\u0313\u0300", 0x1f43: "\u03bf\u0314\u0300",
+	0x1f44: "\u03bf\u0313\u0301", 0x1f45: "\u03bf\u0314\u0301", 0x1f48: "\u039f\u0313", 0x1f49: "\u039f\u0314",
+	0x1f4a: "\u039f\u0313\u0300", 0x1f4b: "\u039f\u0314\u0300", 0x1f4c: "\u039f\u0313\u0301", 0x1f4d: "\u039f\u0314\u0301",
+	0x1f50: "\u03c5\u0313", 0x1f51: "\u03c5\u0314", 0x1f52: "\u03c5\u0313\u0300", 0x1f53: "\u03c5\u0314\u0300",
+	0x1f54: "\u03c5\u0313\u0301", 0x1f55: "\u03c5\u0314\u0301", 0x1f56: "\u03c5\u0313\u0342", 0x1f57: "\u03c5\u0314\u0342",
+	0x1f59: "\u03a5\u0314", 0x1f5b: "\u03a5\u0314\u0300", 0x1f5d: "\u03a5\u0314\u0301", 0x1f5f: "\u03a5\u0314\u0342",
+	0x1f60: "\u03c9\u0313", 0x1f61: "\u03c9\u0314", 0x1f62: "\u03c9\u0313\u0300", 0x1f63: "\u03c9\u0314\u0300",
+	0x1f64: "\u03c9\u0313\u0301", 0x1f65: "\u03c9\u0314\u0301", 0x1f66: "\u03c9\u0313\u0342", 0x1f67: "\u03c9\u0314\u0342",
+	0x1f68: "\u03a9\u0313", 0x1f69: "\u03a9\u0314", 0x1f6a: "\u03a9\u0313\u0300", 0x1f6b: "\u03a9\u0314\u0300",
+	0x1f6c: "\u03a9\u0313\u0301", 0x1f6d: "\u03a9\u0314\u0301", 0x1f6e: "\u03a9\u0313\u0342", 0x1f6f: "\u03a9\u0314\u0342",
+	0x1f70: "\u03b1\u0300", 0x1f71: "\u03b1\u0301", 0x1f72: "\u03b5\u0300", 0x1f73: "\u03b5\u0301",
+	0x1f74: "\u03b7\u0300", 0x1f75: "\u03b7\u0301", 0x1f76: "\u03b9\u0300", 0x1f77: "\u03b9\u0301",
+	0x1f78: "\u03bf\u0300", 0x1f79: "\u03bf\u0301", 0x1f7a: "\u03c5\u0300", 0x1f7b: "\u03c5\u0301",
+	0x1f7c: "\u03c9\u0300", 0x1f7d: "\u03c9\u0301", 0x1f80: "\u03b1\u0313\u0345", 0x1f81: "\u03b1\u0314\u0345",
+	0x1f82: "\u03b1\u0313\u0300\u0345", 0x1f83: "\u03b1\u0314\u0300\u0345", 0x1f84: "\u03b1\u0313\u0301\u0345", 0x1f85: "\u03b1\u0314\u0301\u0345",
+	0x1f86: "\u03b1\u0313\u0342\u0345", 0x1f87: "\u03b1\u0314\u0342\u0345", 0x1f88: "\u0391\u0313\u0345", 0x1f89: "\u0391\u0314\u0345",
+	0x1f8a: "\u0391\u0313\u0300\u0345", 0x1f8b: "\u0391\u0314\u0300\u0345", 0x1f8c: "\u0391\u0313\u0301\u0345", 0x1f8d: "\u0391\u0314\u0301\u0345",
+	0x1f8e: "\u0391\u0313\u0342\u0345", 0x1f8f: "\u0391\u0314\u0342\u0345", 0x1f90: "\u03b7\u0313\u0345", 0x1f91: "\u03b7\u0314\u0345",
+	0x1f92: "\u03b7\u0313\u0300\u0345", 0x1f93: "\u03b7\u0314\u0300\u0345", 0x1f94: "\u03b7\u0313\u0301\u0345", 0x1f95: "\u03b7\u0314\u0301\u0345",
+	0x1f96: "\u03b7\u0313\u0342\u0345", 0x1f97: "\u03b7\u0314\u0342\u0345", 0x1f98: "\u0397\u0313\u0345", 0x1f99: "\u0397\u0314\u0345",
+	0x1f9a: "\u0397\u0313\u0300\u0345", 0x1f9b: "\u0397\u0314\u0300\u0345", 0x1f9c: "\u0397\u0313\u0301\u0345", 0x1f9d: "\u0397\u0314\u0301\u0345",
+	0x1f9e: "\u0397\u0313\u0342\u0345", 0x1f9f: "\u0397\u0314\u0342\u0345", 0x1fa0: "\u03c9\u0313\u0345", 0x1fa1: "\u03c9\u0314\u0345",
+	0x1fa2: "\u03c9\u0313\u0300\u0345", 0x1fa3: "\u03c9\u0314\u0300\u0345", 0x1fa4: "\u03c9\u0313\u0301\u0345", 0x1fa5: "\u03c9\u0314\u0301\u0345",
+	0x1fa6: "\u03c9\u0313\u0342\u0345", 0x1fa7: "\u03c9\u0314\u0342\u0345", 0x1fa8: "\u03a9\u0313\u0345", 0x1fa9: "\u03a9\u0314\u0345",
+	0x1faa: "\u03a9\u0313\u0300\u0345", 0x1fab: "\u03a9\u0314\u0300\u0345", 0x1fac: "\u03a9\u0313\u0301\u0345", 0x1fad: "\u03a9\u0314\u0301\u0345",
+	0x1fae: "\u03a9\u0313\u0342\u0345", 0x1faf: "\u03a9\u0314\u0342\u0345", 0x1fb0: "\u03b1\u0306", 0x1fb1: "\u03b1\u0304",
+	0x1fb2: "\u03b1\u0300\u0345", 0x1fb3: "\u03b1\u0345", 0x1fb4: "\u03b1\u0301\u0345", 0x1fb6: "\u03b1\u0342",
+	0x1fb7: "\u03b1\u0342\u0345", 0x1fb8: "\u0391\u0306", 0x1fb9: "\u0391\u0304", 0x1fba: "\u0391\u0300",
+	0x1fbb: "\u0391\u0301", 0x1fbc: "\u0391\u0345", 0x1fbe: "\u03b9", 0x1fc1: "\u00a8\u0342",
+	0x1fc2: "\u03b7\u0300\u0345", 0x1fc3: "\u03b7\u0345", 0x1fc4: "\u03b7\u0301\u0345", 0x1fc6: "\u03b7\u0342",
+	0x1fc7: "\u03b7\u0342\u0345", 0x1fc8: "\u0395\u0300", 0x1fc9: "\u0395\u0301", 0x1fca: "\u0397\u0300",
+	0x1fcb: "\u0397\u0301", 0x1fcc: "\u0397\u0345", 0x1fcd: "\u1fbf\u0300", 0x1fce: "\u1fbf\u0301",
+	0x1fcf: "\u1fbf\u0342", 0x1fd0: "\u03b9\u0306", 0x1fd1: "\u03b9\u0304", 0x1fd2: "\u03b9\u0308\u0300",
+	0x1fd3: "\u03b9\u0308\u0301", 0x1fd6: "\u03b9\u0342", 0x1fd7: "\u03b9\u0308\u0342", 0x1fd8: "\u0399\u0306",
+	0x1fd9: "\u0399\u0304", 0x1fda: "\u0399\u0300", 0x1fdb: "\u0399\u0301", 0x1fdd: "\u1ffe\u0300",
+	0x1fde: "\u1ffe\u0301", 0x1fdf: "\u1ffe\u0342", 0x1fe0: "\u03c5\u0306", 0x1fe1: "\u03c5\u0304",
+	0x1fe2: "\u03c5\u0308\u0300", 0x1fe3: "\u03c5\u0308\u0301", 0x1fe4: "\u03c1\u0313", 0x1fe5: "\u03c1\u0314",
+	0x1fe6: "\u03c5\u0342", 0x1fe7: "\u03c5\u0308\u0342", 0x1fe8: "\u03a5\u0306", 0x1fe9: "\u03a5\u0304",
+	0x1fea: "\u03a5\u0300", 0x1feb: "\u03a5\u0301", 0x1fec: "\u03a1\u0314", 0x1fed: "\u00a8\u0300",
+	0x1fee: "\u00a8\u0301", 0x1fef: "`", 0x1ff2: "\u03c9\u0300\u0345", 0x1ff3: "\u03c9\u0345",
+	0x1ff4: "\u03c9\u0301\u0345", 0x1ff6: "\u03c9\u0342", 0x1ff7: "\u03c9\u0342\u0345", 0x1ff8: "\u039f\u0300",
+	0x1ff9: "\u039f\u0301", 0x1ffa: "\u03a9\u0300", 0x1ffb: "\u03a9\u0301", 0x1ffc: "\u03a9\u0345",
+	0x1ffd: "\u00b4", 0x2000: "\u2002", 0x2001: "\u2003", 0x2126: "\u03a9",
+	0x212a: "K", 0x212b: "A\u030a", 0x219a: "\u2190\u0338", 0x219b: "\u2192\u0338",
+	0x21ae: "\u2194\u0338", 0x21cd: "\u21d0\u0338", 0x21ce: "\u21d4\u0338", 0x21cf: "\u21d2\u0338",
+	0x2204: "\u2203\u0338", 0x2209: "\u2208\u0338", 0x220c: "\u220b\u0338", 0x2224: "\u2223\u0338",
+	0x2226: "\u2225\u0338", 0x2241: "\u223c\u0338", 0x2244: "\u2243\u0338", 0x2247: "\u2245\u0338",
+	0x2249: "\u2248\u0338", 0x2260: "=\u0338", 0x2262: "\u2261\u0338", 0x226d: "\u224d\u0338",
+	0x226e: "<\u0338", 0x226f: ">\u0338", 0x2270: "\u2264\u0338", 0x2271: "\u2265\u0338",
+	0x2274: "\u2272\u0338", 0x2275: "\u2273\u0338", 0x2278: "\u2276\u0338", 0x2279: "\u2277\u0338",
+	0x2280: "\u227a\u0338", 0x2281: "\u227b\u0338", 0x2284: "\u2282\u0338", 0x2285: "\u2283\u0338",
+	0x2288: "\u2286\u0338", 0x2289: "\u2287\u0338", 0x22ac: "\u22a2\u0338", 0x22ad: "\u22a8\u0338",
+	0x22ae: "\u22a9\u0338", 0x22af: "\u22ab\u0338", 0x22e0: "\u227c\u0338", 0x22e1: "\u227d\u0338",
+	0x22e2: "\u2291\u0338", 0x22e3: "\u2292\u0338", 0x22ea: "\u22b2\u0338", 0x22eb: "\u22b3\u0338",
+	0x22ec: "\u22b4\u0338", 0x22ed: "\u22b5\u0338", 0x2329: "\u3008", 0x232a: "\u3009",
+	0x2adc: "\u2add\u0338", 0x304c: "\u304b\u3099", 0x304e: "\u304d\u3099", 0x3050: "\u304f\u3099",
+	0x3052: "\u3051\u3099", 0x3054: "\u3053\u3099", 0x3056: "\u3055\u3099", 0x3058: "\u3057\u3099",
+	0x305a: "\u3059\u3099", 0x305c: "\u305b\u3099", 0x305e: "\u305d\u3099", 0x3060: "\u305f\u3099",
+	0x3062: "\u3061\u3099", 0x3065: "\u3064\u3099", 0x3067: "\u3066\u3099", 0x3069: "\u3068\u3099",
+	0x3070: "\u306f\u3099", 0x3071: "\u306f\u309a", 0x3073: "\u3072\u3099", 0x3074: "\u3072\u309a",
+	0x3076: "\u3075\u3099", 0x3077: "\u3075\u309a", 0x3079: "\u3078\u3099", 0x307a: "\u3078\u309a",
+	0x307c: "\u307b\u3099", 0x307d: "\u307b\u309a", 0x3094: "\u3046\u3099", 0x309e: "\u309d\u3099",
+	0x30ac: "\u30ab\u3099", 0x30ae: "\u30ad\u3099", 0x30b0: "\u30af\u3099", 0x30b2: "\u30b1\u3099",
+	0x30b4: "\u30b3\u3099", 0x30b6: "\u30b5\u3099", 0x30b8: "\u30b7\u3099", 0x30ba: "\u30b9\u3099",
+	0x30bc: "\u30bb\u3099", 0x30be: "\u30bd\u3099", 0x30c0: "\u30bf\u3099", 0x30c2: "\u30c1\u3099",
+	0x30c5: "\u30c4\u3099", 0x30c7: "\u30c6\u3099", 0x30c9: "\u30c8\u3099", 0x30d0: "\u30cf\u3099",
+	0x30d1: "\u30cf\u309a", 0x30d3: "\u30d2\u3099", 0x30d4: "\u30d2\u309a", 0x30d6: "\u30d5\u3099",
+	0x30d7: "\u30d5\u309a", 0x30d9: "\u30d8\u3099", 0x30da: "\u30d8\u309a", 0x30dc: "\u30db\u3099",
+	0x30dd: "\u30db\u309a", 0x30f4: "\u30a6\u3099", 0x30f7: "\u30ef\u3099", 0x30f8: "\u30f0\u3099",
+	0x30f9: "\u30f1\u3099", 0x30fa: "\u30f2\u3099", 0x30fe: "\u30fd\u3099", 0xf900: "\u8c48",
+	0xf901: "\u66f4", 0xf902: "\u8eca", 0xf903: "\u8cc8", 0xf904: "\u6ed1",
+	0xf905: "\u4e32", 0xf906: "\u53e5", 0xf907: "\u9f9c", 0xf908: "\u9f9c",
+	0xf909: "\u5951", 0xf90a: "\u91d1", 0xf90b: "\u5587", 0xf90c: "\u5948",
+	0xf90d: "\u61f6", 0xf90e: "\u7669", 0xf90f: "\u7f85", 0xf910: "\u863f",
+	0xf911: "\u87ba", 0xf912: "\u88f8", 0xf913: "\u908f", 0xf914: "\u6a02",
+	0xf915: "\u6d1b", 0xf916: "\u70d9", 0xf917: "\u73de", 0xf918: "\u843d",
+	0xf919: "\u916a", 0xf91a: "\u99f1", 0xf91b: "\u4e82", 0xf91c: "\u5375",
+	0xf91d: "\u6b04", 0xf91e: "\u721b", 0xf91f: "\u862d", 0xf920: "\u9e1e",
+	0xf921: "\u5d50", 0xf922: "\u6feb", 0xf923: "\u85cd", 0xf924: "\u8964",
+	0xf925: "\u62c9", 0xf926: "\u81d8", 0xf927: "\u881f", 0xf928: "\u5eca",
+	0xf929: "\u6717", 0xf92a: "\u6d6a", 0xf92b: "\u72fc", 0xf92c: "\u90ce",
+	0xf92d: "\u4f86", 0xf92e: "\u51b7", 0xf92f: "\u52de", 0xf930: "\u64c4",
+	0xf931: "\u6ad3", 0xf932: "\u7210", 0xf933: "\u76e7", 0xf934: "\u8001",
+	0xf935: "\u8606", 0xf936: "\u865c", 0xf937: "\u8def", 0xf938: "\u9732",
+	0xf939: "\u9b6f", 0xf93a: "\u9dfa", 0xf93b: "\u788c", 0xf93c: "\u797f",
+	0xf93d: "\u7da0", 0xf93e: "\u83c9", 0xf93f: "\u9304", 0xf940: "\u9e7f",
+	0xf941: "\u8ad6", 0xf942: "\u58df", 0xf943: "\u5f04", 0xf944: "\u7c60",
+	0xf945: "\u807e", 0xf946: "\u7262", 0xf947: "\u78ca", 0xf948: "\u8cc2",
+	0xf949: "\u96f7", 0xf94a: "\u58d8", 0xf94b: "\u5c62", 0xf94c: "\u6a13",
+	0xf94d: "\u6dda", 0xf94e: "\u6f0f", 0xf94f: "\u7d2f", 0xf950: "\u7e37",
+	0xf951: "\u964b", 0xf952: "\u52d2", 0xf953: "\u808b", 0xf954: "\u51dc",
+	0xf955: "\u51cc", 0xf956: "\u7a1c", 0xf957: "\u7dbe", 0xf958: "\u83f1",
+	0xf959: "\u9675", 0xf95a: "\u8b80", 0xf95b: "\u62cf", 0xf95c: "\u6a02",
+	0xf95d: "\u8afe", 0xf95e: "\u4e39", 0xf95f: "\u5be7", 0xf960: "\u6012",
+	0xf961: "\u7387", 0xf962: "\u7570", 0xf963: "\u5317", 0xf964: "\u78fb",
+	0xf965: "\u4fbf", 0xf966: "\u5fa9", 0xf967: "\u4e0d", 0xf968: "\u6ccc",
+	0xf969: "\u6578", 0xf96a: "\u7d22", 0xf96b: "\u53c3", 0xf96c: "\u585e",
+	0xf96d: "\u7701", 0xf96e: "\u8449", 0xf96f: "\u8aaa", 0xf970: "\u6bba",
+	0xf971: "\u8fb0", 0xf972: "\u6c88", 0xf973: "\u62fe", 0xf974: "\u82e5",
+	0xf975: "\u63a0", 0xf976: "\u7565", 0xf977: "\u4eae", 0xf978: "\u5169",
+	0xf979: "\u51c9", 0xf97a: "\u6881", 0xf97b: "\u7ce7", 0xf97c: "\u826f",
+	0xf97d: "\u8ad2", 0xf97e: "\u91cf", 0xf97f: "\u52f5", 0xf980: "\u5442",
+	0xf981: "\u5973", 0xf982: "\u5eec", 0xf983: "\u65c5", 0xf984: "\u6ffe",
+	0xf985: "\u792a", 0xf986: "\u95ad", 0xf987: "\u9a6a", 0xf988: "\u9e97",
+	0xf989: "\u9ece", 0xf98a: "\u529b", 0xf98b: "\u66c6", 0xf98c: "\u6b77",
+	0xf98d: "\u8f62", 0xf98e: "\u5e74", 0xf98f: "\u6190", 0xf990: "\u6200",
+	0xf991: "\u649a", 0xf992: "\u6f23", 0xf993: "\u7149", 0xf994: "\u7489",
+	0xf995: "\u79ca", 0xf996: "\u7df4", 0xf997: "\u806f", 0xf998: "\u8f26",
+	0xf999: "\u84ee", 0xf99a: "\u9023", 0xf99b: "\u934a", 0xf99c: "\u5217",
+	0xf99d: "\u52a3", 0xf99e: "\u54bd", 0xf99f: "\u70c8", 0xf9a0: "\u88c2",
+	0xf9a1: "\u8aaa", 0xf9a2: "\u5ec9", 0xf9a3: "\u5ff5", 0xf9a4: "\u637b",
+	0xf9a5: "\u6bae", 0xf9a6: "\u7c3e", 0xf9a7: "\u7375", 0xf9a8: "\u4ee4",
+	0xf9a9: "\u56f9", 0xf9aa: "\u5be7", 0xf9ab: "\u5dba", 0xf9ac: "\u601c",
+	0xf9ad: "\u73b2", 0xf9ae: "\u7469", 0xf9af: "\u7f9a", 0xf9b0: "\u8046",
+	0xf9b1: "\u9234", 0xf9b2: "\u96f6", 0xf9b3: "\u9748", 0xf9b4: "\u9818",
+	0xf9b5: "\u4f8b", 0xf9b6: "\u79ae", 0xf9b7: "\u91b4", 0xf9b8: "\u96b8",
+	0xf9b9: "\u60e1", 0xf9ba: "\u4e86", 0xf9bb: "\u50da", 0xf9bc: "\u5bee",
+	0xf9bd: "\u5c3f", 0xf9be: "\u6599", 0xf9bf: "\u6a02", 0xf9c0: "\u71ce",
+	0xf9c1: "\u7642", 0xf9c2: "\u84fc", 0xf9c3: "\u907c", 0xf9c4: "\u9f8d",
+	0xf9c5: "\u6688", 0xf9c6: "\u962e", 0xf9c7: "\u5289", 0xf9c8: "\u677b",
+	0xf9c9: "\u67f3", 0xf9ca: "\u6d41", 0xf9cb: "\u6e9c", 0xf9cc: "\u7409",
+	0xf9cd: "\u7559", 0xf9ce: "\u786b", 0xf9cf: "\u7d10", 0xf9d0: "\u985e",
+	0xf9d1: "\u516d", 0xf9d2: "\u622e", 0xf9d3: "\u9678", 0xf9d4: "\u502b",
+	0xf9d5: "\u5d19", 0xf9d6: "\u6dea", 0xf9d7: "\u8f2a", 0xf9d8: "\u5f8b",
+	0xf9d9: "\u6144", 0xf9da: "\u6817", 0xf9db: "\u7387", 0xf9dc: "\u9686",
+	0xf9dd: "\u5229", 0xf9de: "\u540f", 0xf9df: "\u5c65", 0xf9e0: "\u6613",
+	0xf9e1: "\u674e", 0xf9e2: "\u68a8", 0xf9e3: "\u6ce5", 0xf9e4: "\u7406",
+	0xf9e5: "\u75e2", 0xf9e6: "\u7f79", 0xf9e7: "\u88cf", 0xf9e8: "\u88e1",
+	0xf9e9: "\u91cc", 0xf9ea: "\u96e2", 0xf9eb: "\u533f", 0xf9ec: "\u6eba",
+	0xf9ed: "\u541d", 0xf9ee: "\u71d0", 0xf9ef: "\u7498", 0xf9f0: "\u85fa",
+	0xf9f1: "\u96a3", 0xf9f2: "\u9c57", 0xf9f3: "\u9e9f", 0xf9f4: "\u6797",
+	0xf9f5: "\u6dcb", 0xf9f6: "\u81e8", 0xf9f7: "\u7acb", 0xf9f8: "\u7b20",
+	0xf9f9: "\u7c92", 0xf9fa: "\u72c0", 0xf9fb: "\u7099", 0xf9fc: "\u8b58",
+	0xf9fd: "\u4ec0", 0xf9fe: "\u8336", 0xf9ff: "\u523a", 0xfa00: "\u5207",
+	0xfa01: "\u5ea6", 0xfa02: "\u62d3", 0xfa03: "\u7cd6", 0xfa04: "\u5b85",
+	0xfa05: "\u6d1e", 0xfa06: "\u66b4", 0xfa07: "\u8f3b", 0xfa08: "\u884c",
+	0xfa09: "\u964d", 0xfa0a: "\u898b", 0xfa0b: "\u5ed3", 0xfa0c: "\u5140",
+	0xfa0d: "\u55c0", 0xfa10: "\u585a", 0xfa12: "\u6674", 0xfa15: "\u51de",
+	0xfa16: "\u732a", 0xfa17: "\u76ca", 0xfa18: "\u793c", 0xfa19: "\u795e",
+	0xfa1a: "\u7965", 0xfa1b: "\u798f", 0xfa1c: "\u9756", 0xfa1d: "\u7cbe",
+	0xfa1e: "\u7fbd", 0xfa20: "\u8612", 0xfa22: "\u8af8", 0xfa25: "\u9038",
+	0xfa26: "\u90fd", 0xfa2a: "\u98ef", 0xfa2b: "\u98fc", 0xfa2c: "\u9928",
+	0xfa2d: "\u9db4", 0xfa2e: "\u90de", 0xfa2f: "\u96b7", 0xfa30: "\u4fae",
+	0xfa31: "\u50e7", 0xfa32: "\u514d", 0xfa33: "\u52c9", 0xfa34: "\u52e4",
+	0xfa35: "\u5351", 0xfa36: "\u559d", 0xfa37: "\u5606", 0xfa38: "\u5668",
+	0xfa39: "\u5840", 0xfa3a: "\u58a8", 0xfa3b: "\u5c64", 0xfa3c: "\u5c6e",
+	0xfa3d: "\u6094", 0xfa3e: "\u6168", 0xfa3f: "\u618e", 0xfa40: "\u61f2",
+	0xfa41: "\u654f", 0xfa42: "\u65e2", 0xfa43: "\u6691", 0xfa44: "\u6885",
+	0xfa45: "\u6d77", 0xfa46: "\u6e1a", 0xfa47: "\u6f22", 0xfa48: "\u716e",
+	0xfa49: "\u722b", 0xfa4a: "\u7422", 0xfa4b: "\u7891", 0xfa4c: "\u793e",
+	0xfa4d: "\u7949", 0xfa4e: "\u7948", 0xfa4f: "\u7950", 0xfa50: "\u7956",
+	0xfa51: "\u795d", 0xfa52: "\u798d", 0xfa53: "\u798e", 0xfa54: "\u7a40",
+	0xfa55: "\u7a81", 0xfa56: "\u7bc0", 0xfa57: "\u7df4", 0xfa58: "\u7e09",
+	0xfa59: "\u7e41", 0xfa5a: "\u7f72", 0xfa5b: "\u8005", 0xfa5c: "\u81ed",
+	0xfa5d: "\u8279", 0xfa5e: "\u8279", 0xfa5f: "\u8457", 0xfa60: "\u8910",
+	0xfa61: "\u8996", 0xfa62: "\u8b01", 0xfa63: "\u8b39", 0xfa64: "\u8cd3",
+	0xfa65: "\u8d08", 0xfa66: "\u8fb6", 0xfa67: "\u9038", 0xfa68: "\u96e3",
+	0xfa69: "\u97ff", 0xfa6a: "\u983b", 0xfa6b: "\u6075", 0xfa6c: "\U000242ee",
+	0xfa6d: "\u8218", 0xfa70: "\u4e26", 0xfa71: "\u51b5", 0xfa72: "\u5168",
+	0xfa73: "\u4f80", 0xfa74: "\u5145", 0xfa75: "\u5180", 0xfa76: "\u52c7",
+	0xfa77: "\u52fa", 0xfa78: "\u559d", 0xfa79: "\u5555", 0xfa7a: "\u5599",
+	0xfa7b: "\u55e2", 0xfa7c: "\u585a", 0xfa7d: "\u58b3", 0xfa7e: "\u5944",
+	0xfa7f: "\u5954", 0xfa80: "\u5a62", 0xfa81: "\u5b28", 0xfa82: "\u5ed2",
+	0xfa83: "\u5ed9", 0xfa84: "\u5f69", 0xfa85: "\u5fad", 0xfa86: "\u60d8",
+	0xfa87: "\u614e", 0xfa88: "\u6108", 0xfa89: "\u618e", 0xfa8a: "\u6160",
+	0xfa8b: "\u61f2", 0xfa8c: "\u6234", 0xfa8d: "\u63c4", 0xfa8e: "\u641c",
+	0xfa8f: "\u6452", 0xfa90: "\u6556", 0xfa91: "\u6674", 0xfa92: "\u6717",
+	0xfa93: "\u671b", 0xfa94: "\u6756", 0xfa95: "\u6b79", 0xfa96: "\u6bba",
+	0xfa97: "\u6d41", 0xfa98: "\u6edb", 0xfa99: "\u6ecb", 0xfa9a: "\u6f22",
+	0xfa9b: "\u701e", 0xfa9c: "\u716e", 0xfa9d: "\u77a7", 0xfa9e: "\u7235",
+	0xfa9f: "\u72af", 0xfaa0: "\u732a", 0xfaa1: "\u7471", 0xfaa2: "\u7506",
+	0xfaa3: "\u753b", 0xfaa4: "\u761d", 0xfaa5: "\u761f", 0xfaa6: "\u76ca",
+	0xfaa7: "\u76db", 0xfaa8: "\u76f4", 0xfaa9: "\u774a", 0xfaaa: "\u7740",
+	0xfaab: "\u78cc", 0xfaac: "\u7ab1", 0xfaad: "\u7bc0", 0xfaae: "\u7c7b",
+	0xfaaf: "\u7d5b", 0xfab0: "\u7df4", 0xfab1: "\u7f3e", 0xfab2: "\u8005",
+	0xfab3: "\u8352", 0xfab4: "\u83ef", 0xfab5: "\u8779", 0xfab6: "\u8941",
+	0xfab7: "\u8986", 0xfab8: "\u8996", 0xfab9: "\u8abf", 0xfaba: "\u8af8",
+	0xfabb: "\u8acb", 0xfabc: "\u8b01", 0xfabd: "\u8afe", 0xfabe: "\u8aed",
+	0xfabf: "\u8b39", 0xfac0: "\u8b8a", 0xfac1: "\u8d08", 0xfac2: "\u8f38",
+	0xfac3: "\u9072", 0xfac4: "\u9199", 0xfac5: "\u9276", 0xfac6: "\u967c",
+	0xfac7: "\u96e3", 0xfac8: "\u9756", 0xfac9: "\u97db", 0xfaca: "\u97ff",
+	0xfacb: "\u980b", 0xfacc: "\u983b", 0xfacd: "\u9b12", 0xface: "\u9f9c",
+	0xfacf: "\U0002284a", 0xfad0: "\U00022844", 0xfad1: "\U000233d5", 0xfad2: "\u3b9d",
+	0xfad3: "\u4018", 0xfad4: "\u4039", 0xfad5: "\U00025249", 0xfad6: "\U00025cd0",
+	0xfad7: "\U00027ed3", 0xfad8: "\u9f43", 0xfad9: "\u9f8e", 0xfb1d: "\u05d9\u05b4",
+	0xfb1f: "\u05f2\u05b7", 0xfb2a: "\u05e9\u05c1", 0xfb2b: "\u05e9\u05c2", 0xfb2c: "\u05e9\u05bc\u05c1",
+	0xfb2d: "\u05e9\u05bc\u05c2", 0xfb2e: "\u05d0\u05b7", 0xfb2f: "\u05d0\u05b8", 0xfb30: "\u05d0\u05bc",
+	0xfb31: "\u05d1\u05bc", 0xfb32: "\u05d2\u05bc", 0xfb33: "\u05d3\u05bc", 0xfb34: "\u05d4\u05bc",
+	0xfb35: "\u05d5\u05bc", 0xfb36: "\u05d6\u05bc", 0xfb38: "\u05d8\u05bc", 0xfb39: "\u05d9\u05bc",
+	0xfb3a: "\u05da\u05bc", 0xfb3b: "\u05db\u05bc", 0xfb3c: "\u05dc\u05bc", 0xfb3e: "\u05de\u05bc",
+	0xfb40: "\u05e0\u05bc", 0xfb41: "\u05e1\u05bc", 0xfb43: "\u05e3\u05bc", 0xfb44: "\u05e4\u05bc",
+	0xfb46: "\u05e6\u05bc", 0xfb47: "\u05e7\u05bc", 0xfb48: "\u05e8\u05bc", 0xfb49: "\u05e9\u05bc",
+	0xfb4a: "\u05ea\u05bc", 0xfb4b: "\u05d5\u05b9", 0xfb4c: "\u05d1\u05bf", 0xfb4d: "\u05db\u05bf",
+	0xfb4e: "\u05e4\u05bf", 0x1109a: "\U00011099\U000110ba", 0x1109c: "\U0001109b\U000110ba", 0x110ab: "\U000110a5\U000110ba",
+	0x1112e: "\U00011131\U00011127", 0x1112f: "\U00011132\U00011127", 0x1134b: "\U00011347\U0001133e", 0x1134c: "\U00011347\U00011357",
+	0x114bb: "\U000114b9\U000114ba", 0x114bc: "\U000114b9\U000114b0", 0x114be: "\U000114b9\U000114bd", 0x115ba: "\U000115b8\U000115af",
+	0x115bb: "\U000115b9\U000115af", 0x11938: "\U00011935\U00011930", 0x1d15e: "\U0001d157\U0001d165", 0x1d15f: "\U0001d158\U0001d165",
+	0x1d160: "\U0001d158\U0001d165\U0001d16e", 0x1d161: "\U0001d158\U0001d165\U0001d16f", 0x1d162: "\U0001d158\U0001d165\U0001d170", 0x1d163: "\U0001d158\U0001d165\U0001d171",
+	0x1d164: "\U0001d158\U0001d165\U0001d172", 0x1d1bb: "\U0001d1b9\U0001d165", 0x1d1bc: "\U0001d1ba\U0001d165", 0x1d1bd: "\U0001d1b9\U0001d165\U0001d16e",
+	0x1d1be: "\U0001d1ba\U0001d165\U0001d16e", 0x1d1bf: "\U0001d1b9\U0001d165\U0001d16f", 0x1d1c0: "\U0001d1ba\U0001d165\U0001d16f", 0x2f800: "\u4e3d",
+	0x2f801: "\u4e38", 0x2f802: "\u4e41", 0x2f803: "\U00020122", 0x2f804: "\u4f60",
+	0x2f805: "\u4fae", 0x2f806: "\u4fbb", 0x2f807: "\u5002", 0x2f808: "\u507a",
+	0x2f809: "\u5099", 0x2f80a: "\u50e7", 0x2f80b: "\u50cf", 0x2f80c: "\u349e",
+	0x2f80d: "\U0002063a", 0x2f80e: "\u514d", 0x2f80f: "\u5154", 0x2f810: "\u5164",
+	0x2f811: "\u5177", 0x2f812: "\U0002051c", 0x2f813: "\u34b9", 0x2f814: "\u5167",
+	0x2f815: "\u518d", 0x2f816: "\U0002054b", 0x2f817: "\u5197", 0x2f818: "\u51a4",
+	0x2f819: "\u4ecc", 0x2f81a: "\u51ac", 0x2f81b: "\u51b5", 0x2f81c: "\U000291df",
+	0x2f81d: "\u51f5", 0x2f81e: "\u5203", 0x2f81f: "\u34df", 0x2f820: "\u523b",
+	0x2f821: "\u5246", 0x2f822: "\u5272", 0x2f823: "\u5277", 0x2f824: "\u3515",
+	0x2f825: "\u52c7", 0x2f826: "\u52c9", 0x2f827: "\u52e4", 0x2f828: "\u52fa",
+	0x2f829: "\u5305", 0x2f82a: "\u5306", 0x2f82b: "\u5317", 0x2f82c: "\u5349",
+	0x2f82d: "\u5351", 0x2f82e: "\u535a", 0x2f82f: "\u5373", 0x2f830: "\u537d",
+	0x2f831: "\u537f", 0x2f832: "\u537f", 0x2f833: "\u537f", 0x2f834: "\U00020a2c",
+	0x2f835: "\u7070", 0x2f836: "\u53ca", 0x2f837: "\u53df", 0x2f838: "\U00020b63",
+	0x2f839: "\u53eb", 0x2f83a: "\u53f1", 0x2f83b: "\u5406", 0x2f83c: "\u549e",
+	0x2f83d: "\u5438", 0x2f83e: "\u5448", 0x2f83f: "\u5468", 0x2f840: "\u54a2",
+	0x2f841: "\u54f6", 0x2f842: "\u5510", 0x2f843: "\u5553", 0x2f844: "\u5563",
+	0x2f845: "\u5584", 0x2f846: "\u5584", 0x2f847: "\u5599", 0x2f848: "\u55ab",
+	0x2f849: "\u55b3", 0x2f84a: "\u55c2", 0x2f84b: "\u5716", 0x2f84c: "\u5606",
+	0x2f84d: "\u5717", 0x2f84e: "\u5651", 0x2f84f: "\u5674", 0x2f850: "\u5207",
+	0x2f851: "\u58ee", 0x2f852: "\u57ce", 0x2f853: "\u57f4", 0x2f854: "\u580d",
+	0x2f855: "\u578b", 0x2f856: "\u5832", 0x2f857: "\u5831", 0x2f858: "\u58ac",
+	0x2f859: "\U000214e4", 0x2f85a: "\u58f2", 0x2f85b: "\u58f7", 0x2f85c: "\u5906",
+	0x2f85d: "\u591a", 0x2f85e: "\u5922", 0x2f85f: "\u5962", 0x2f860: "\U000216a8",
+	0x2f861: "\U000216ea", 0x2f862: "\u59ec", 0x2f863: "\u5a1b", 0x2f864: "\u5a27",
+	0x2f865: "\u59d8", 0x2f866: "\u5a66", 0x2f867: "\u36ee", 0x2f868: "\u36fc",
+	0x2f869: "\u5b08", 0x2f86a: "\u5b3e", 0x2f86b: "\u5b3e", 0x2f86c: "\U000219c8",
+	0x2f86d: "\u5bc3", 0x2f86e: "\u5bd8", 0x2f86f: "\u5be7", 0x2f870: "\u5bf3",
+	0x2f871: "\U00021b18", 0x2f872: "\u5bff", 0x2f873: "\u5c06", 0x2f874: "\u5f53",
+	0x2f875: "\u5c22", 0x2f876: "\u3781", 0x2f877: "\u5c60", 0x2f878: "\u5c6e",
+	0x2f879: "\u5cc0", 0x2f87a: "\u5c8d", 0x2f87b: "\U00021de4", 0x2f87c: "\u5d43",
+	0x2f87d: "\U00021de6", 0x2f87e: "\u5d6e", 0x2f87f: "\u5d6b", 0x2f880: "\u5d7c",
+	0x2f881: "\u5de1", 0x2f882: "\u5de2", 0x2f883: "\u382f", 0x2f884: "\u5dfd",
+	0x2f885: "\u5e28", 0x2f886: "\u5e3d", 0x2f887: "\u5e69", 0x2f888: "\u3862",
+	0x2f889: "\U00022183", 0x2f88a: "\u387c", 0x2f88b: "\u5eb0", 0x2f88c: "\u5eb3",
+	0x2f88d: "\u5eb6", 0x2f88e: "\u5eca", 0x2f88f: "\U0002a392", 0x2f890: "\u5efe",
+	0x2f891: "\U00022331", 0x2f892: "\U00022331", 0x2f893: "\u8201", 0x2f894: "\u5f22",
+	0x2f895: "\u5f22", 0x2f896: "\u38c7", 0x2f897: "\U000232b8", 0x2f898: "\U000261da",
+	0x2f899: "\u5f62", 0x2f89a: "\u5f6b", 0x2f89b: "\u38e3", 0x2f89c: "\u5f9a",
+	0x2f89d: "\u5fcd", 0x2f89e: "\u5fd7", 0x2f89f: "\u5ff9", 0x2f8a0: "\u6081",
+	0x2f8a1: "\u393a", 0x2f8a2: "\u391c", 0x2f8a3: "\u6094", 0x2f8a4: "\U000226d4",
+	0x2f8a5: "\u60c7", 0x2f8a6: "\u6148", 0x2f8a7: "\u614c", 0x2f8a8: "\u614e",
+	0x2f8a9: "\u614c", 0x2f8aa: "\u617a", 0x2f8ab: "\u618e", 0x2f8ac: "\u61b2",
+	0x2f8ad: "\u61a4", 0x2f8ae: "\u61af", 0x2f8af: "\u61de", 0x2f8b0: "\u61f2",
+	0x2f8b1: "\u61f6", 0x2f8b2: "\u6210", 0x2f8b3: "\u621b", 0x2f8b4: "\u625d",
+	0x2f8b5: "\u62b1", 0x2f8b6: "\u62d4", 0x2f8b7: "\u6350", 0x2f8b8: "\U00022b0c",
+	0x2f8b9: "\u633d", 0x2f8ba: "\u62fc", 0x2f8bb: "\u6368", 0x2f8bc: "\u6383",
+	0x2f8bd: "\u63e4", 0x2f8be: "\U00022bf1", 0x2f8bf: "\u6422", 0x2f8c0: "\u63c5",
+	0x2f8c1: "\u63a9", 0x2f8c2: "\u3a2e", 0x2f8c3: "\u6469", 0x2f8c4: "\u647e",
+	0x2f8c5: "\u649d", 0x2f8c6: "\u6477", 0x2f8c7: "\u3a6c", 0x2f8c8: "\u654f",
+	0x2f8c9: "\u656c", 0x2f8ca: "\U0002300a", 0x2f8cb: "\u65e3", 0x2f8cc: "\u66f8",
+	0x2f8cd: "\u6649", 0x2f8ce: "\u3b19", 0x2f8cf: "\u6691", 0x2f8d0: "\u3b08",
+	0x2f8d1: "\u3ae4", 0x2f8d2: "\u5192", 0x2f8d3: "\u5195", 0x2f8d4: "\u6700",
+	0x2f8d5: "\u669c", 0x2f8d6: "\u80ad", 0x2f8d7: "\u43d9", 0x2f8d8: "\u6717",
+	0x2f8d9: "\u671b", 0x2f8da: "\u6721", 0x2f8db: "\u675e", 0x2f8dc: "\u6753",
+	0x2f8dd: "\U000233c3", 0x2f8de: "\u3b49", 0x2f8df: "\u67fa", 0x2f8e0: "\u6785",
+	0x2f8e1: "\u6852", 0x2f8e2: "\u6885", 0x2f8e3: "\U0002346d", 0x2f8e4: "\u688e",
+	0x2f8e5: "\u681f", 0x2f8e6: "\u6914", 0x2f8e7: "\u3b9d", 0x2f8e8: "\u6942",
+	0x2f8e9: "\u69a3", 0x2f8ea: "\u69ea", 0x2f8eb: "\u6aa8", 0x2f8ec: "\U000236a3",
+	0x2f8ed: "\u6adb", 0x2f8ee: "\u3c18", 0x2f8ef: "\u6b21", 0x2f8f0: "\U000238a7",
+	0x2f8f1: "\u6b54", 0x2f8f2: "\u3c4e", 0x2f8f3: "\u6b72", 0x2f8f4: "\u6b9f",
+	0x2f8f5: "\u6bba", 0x2f8f6: "\u6bbb", 0x2f8f7: "\U00023a8d", 0x2f8f8: "\U00021d0b",
+	0x2f8f9: "\U00023afa", 0x2f8fa: "\u6c4e", 0x2f8fb: "\U00023cbc", 0x2f8fc: "\u6cbf",
+	0x2f8fd: "\u6ccd", 0x2f8fe: "\u6c67", 0x2f8ff: "\u6d16", 0x2f900: "\u6d3e",
+	0x2f901: "\u6d77", 0x2f902: "\u6d41", 0x2f903: "\u6d69", 0x2f904: "\u6d78",
+	0x2f905: "\u6d85", 0x2f906: "\U00023d1e", 0x2f907: "\u6d34", 0x2f908: "\u6e2f",
+	0x2f909: "\u6e6e", 0x2f90a: "\u3d33", 0x2f90b: "\u6ecb", 0x2f90c: "\u6ec7",
+	0x2f90d: "\U00023ed1", 0x2f90e: "\u6df9", 0x2f90f: "\u6f6e", 0x2f910: "\U00023f5e",
+	0x2f911: "\U00023f8e", 0x2f912: "\u6fc6", 0x2f913: "\u7039", 0x2f914: "\u701e",
+	0x2f915: "\u701b", 0x2f916: "\u3d96", 0x2f917: "\u704a", 0x2f918: "\u707d",
+	0x2f919: "\u7077", 0x2f91a: "\u70ad", 0x2f91b: "\U00020525", 0x2f91c: "\u7145",
+	0x2f91d: "\U00024263", 0x2f91e: "\u719c", 0x2f91f: "\U000243ab", 0x2f920: "\u7228",
+	0x2f921: "\u7235", 0x2f922: "\u7250", 0x2f923: "\U00024608", 0x2f924: "\u7280",
+	0x2f925: "\u7295", 0x2f926: "\U00024735", 0x2f927: "\U00024814", 0x2f928: "\u737a",
+	0x2f929: "\u738b", 0x2f92a: "\u3eac", 0x2f92b: "\u73a5", 0x2f92c: "\u3eb8",
+	0x2f92d: "\u3eb8", 0x2f92e: "\u7447", 0x2f92f: "\u745c", 0x2f930: "\u7471",
+	0x2f931: "\u7485", 0x2f932: "\u74ca", 0x2f933: "\u3f1b", 0x2f934: "\u7524",
+	0x2f935: "\U00024c36", 0x2f936: "\u753e", 0x2f937: "\U00024c92", 0x2f938: "\u7570",
+	0x2f939: "\U0002219f", 0x2f93a: "\u7610", 0x2f93b: "\U00024fa1", 0x2f93c: "\U00024fb8",
+	0x2f93d: "\U00025044", 0x2f93e: "\u3ffc", 0x2f93f: "\u4008", 0x2f940: "\u76f4",
+	0x2f941: "\U000250f3", 0x2f942: "\U000250f2", 0x2f943: "\U00025119", 0x2f944: "\U00025133",
+	0x2f945: "\u771e", 0x2f946: "\u771f", 0x2f947: "\u771f", 0x2f948: "\u774a",
+	0x2f949: "\u4039", 0x2f94a: "\u778b", 0x2f94b: "\u4046", 0x2f94c: "\u4096",
+	0x2f94d: "\U0002541d", 0x2f94e: "\u784e", 0x2f94f: "\u788c", 0x2f950: "\u78cc",
+	0x2f951: "\u40e3", 0x2f952: "\U00025626", 0x2f953: "\u7956", 0x2f954: "\U0002569a",
+	0x2f955: "\U000256c5", 0x2f956: "\u798f", 0x2f957: "\u79eb", 0x2f958: "\u412f",
+	0x2f959: "\u7a40", 0x2f95a: "\u7a4a", 0x2f95b: "\u7a4f", 0x2f95c: "\U0002597c",
+	0x2f95d: "\U00025aa7", 0x2f95e: "\U00025aa7", 0x2f95f: "\u7aee", 0x2f960: "\u4202",
+	0x2f961: "\U00025bab", 0x2f962: "\u7bc6", 0x2f963: "\u7bc9", 0x2f964: "\u4227",
+	0x2f965: "\U00025c80", 0x2f966: "\u7cd2", 0x2f967: "\u42a0", 0x2f968: "\u7ce8",
+	0x2f969: "\u7ce3", 0x2f96a: "\u7d00", 0x2f96b: "\U00025f86", 0x2f96c: "\u7d63",
+	0x2f96d: "\u4301", 0x2f96e: "\u7dc7", 0x2f96f: "\u7e02", 0x2f970: "\u7e45",
+	0x2f971: "\u4334", 0x2f972: "\U00026228", 0x2f973: "\U00026247", 0x2f974: "\u4359",
+	0x2f975: "\U000262d9", 0x2f976: "\u7f7a", 0x2f977: "\U0002633e", 0x2f978: "\u7f95",
+	0x2f979: "\u7ffa", 0x2f97a: "\u8005", 0x2f97b: "\U000264da", 0x2f97c: "\U00026523",
+	0x2f97d: "\u8060", 0x2f97e: "\U000265a8", 0x2f97f: "\u8070", 0x2f980: "\U0002335f",
+	0x2f981: "\u43d5", 0x2f982: "\u80b2", 0x2f983: "\u8103", 0x2f984: "\u440b",
+	0x2f985: "\u813e", 0x2f986: "\u5ab5", 0x2f987: "\U000267a7", 0x2f988: "\U000267b5",
+	0x2f989: "\U00023393", 0x2f98a: "\U0002339c", 0x2f98b: "\u8201", 0x2f98c: "\u8204",
+	0x2f98d: "\u8f9e", 0x2f98e: "\u446b", 0x2f98f: "\u8291", 0x2f990: "\u828b",
+	0x2f991: "\u829d", 0x2f992: "\u52b3", 0x2f993: "\u82b1", 0x2f994: "\u82b3",
+	0x2f995: "\u82bd", 0x2f996: "\u82e6", 0x2f997: "\U00026b3c", 0x2f998: "\u82e5",
+	0x2f999: "\u831d", 0x2f99a: "\u8363", 0x2f99b: "\u83ad", 0x2f99c: "\u8323",
+	0x2f99d: "\u83bd", 0x2f99e: "\u83e7", 0x2f99f: "\u8457", 0x2f9a0: "\u8353",
+	0x2f9a1: "\u83ca", 0x2f9a2: "\u83cc", 0x2f9a3: "\u83dc", 0x2f9a4: "\U00026c36",
+	0x2f9a5: "\U00026d6b", 0x2f9a6: "\U00026cd5", 0x2f9a7: "\u452b", 0x2f9a8: "\u84f1",
+	0x2f9a9: "\u84f3", 0x2f9aa: "\u8516", 0x2f9ab: "\U000273ca", 0x2f9ac: "\u8564",
+	0x2f9ad: "\U00026f2c", 0x2f9ae: "\u455d", 0x2f9af: "\u4561", 0x2f9b0: "\U00026fb1",
+	0x2f9b1: "\U000270d2", 0x2f9b2: "\u456b", 0x2f9b3: "\u8650", 0x2f9b4: "\u865c",
+	0x2f9b5: "\u8667", 0x2f9b6: "\u8669", 0x2f9b7: "\u86a9", 0x2f9b8: "\u8688",
+	0x2f9b9: "\u870e", 0x2f9ba: "\u86e2", 0x2f9bb: "\u8779", 0x2f9bc: "\u8728",
+	0x2f9bd: "\u876b", 0x2f9be: "\u8786", 0x2f9bf: "\u45d7", 0x2f9c0: "\u87e1",
+	0x2f9c1: "\u8801", 0x2f9c2: "\u45f9", 0x2f9c3: "\u8860", 0x2f9c4: "\u8863",
+	0x2f9c5: "\U00027667", 0x2f9c6: "\u88d7", 0x2f9c7: "\u88de", 0x2f9c8: "\u4635",
+	0x2f9c9: "\u88fa", 0x2f9ca: "\u34bb", 0x2f9cb: "\U000278ae", 0x2f9cc: "\U00027966",
+	0x2f9cd: "\u46be", 0x2f9ce: "\u46c7", 0x2f9cf: "\u8aa0", 0x2f9d0: "\u8aed",
+	0x2f9d1: "\u8b8a", 0x2f9d2: "\u8c55", 0x2f9d3: "\U00027ca8", 0x2f9d4: "\u8cab",
+	0x2f9d5: "\u8cc1", 0x2f9d6: "\u8d1b", 0x2f9d7: "\u8d77", 0x2f9d8: "\U00027f2f",
+	0x2f9d9: "\U00020804", 0x2f9da: "\u8dcb", 0x2f9db: "\u8dbc", 0x2f9dc: "\u8df0",
+	0x2f9dd: "\U000208de", 0x2f9de: "\u8ed4", 0x2f9df: "\u8f38", 0x2f9e0: "\U000285d2",
+	0x2f9e1: "\U000285ed", 0x2f9e2: "\u9094", 0x2f9e3: "\u90f1", 0x2f9e4: "\u9111",
+	0x2f9e5: "\U0002872e", 0x2f9e6: "\u911b", 0x2f9e7: "\u9238", 0x2f9e8: "\u92d7",
+	0x2f9e9: "\u92d8", 0x2f9ea: "\u927c", 0x2f9eb: "\u93f9", 0x2f9ec: "\u9415",
+	0x2f9ed: "\U00028bfa", 0x2f9ee: "\u958b", 0x2f9ef: "\u4995", 0x2f9f0: "\u95b7",
+	0x2f9f1: "\U00028d77", 0x2f9f2: "\u49e6", 0x2f9f3: "\u96c3", 0x2f9f4: "\u5db2",
+	0x2f9f5: "\u9723", 0x2f9f6: "\U00029145", 0x2f9f7: "\U0002921a", 0x2f9f8: "\u4a6e",
+	0x2f9f9: "\u4a76", 0x2f9fa: "\u97e0", 0x2f9fb: "\U0002940a", 0x2f9fc: "\u4ab2",
+	0x2f9fd: "\U00029496", 0x2f9fe: "\u980b", 0x2f9ff: "\u980b", 0x2fa00: "\u9829",
+	0x2fa01: "\U000295b6", 0x2fa02: "\u98e2", 0x2fa03: "\u4b33", 0x2fa04: "\u9929",
+	0x2fa05: "\u99a7", 0x2fa06: "\u99c2", 0x2fa07: "\u99fe", 0x2fa08: "\u4bce",
+	0x2fa09: "\U00029b30", 0x2fa0a: "\u9b12", 0x2fa0b: "\u9c40", 0x2fa0c: "\u9cfd",
+	0x2fa0d: "\u4cce", 0x2fa0e: "\u4ced", 0x2fa0f: "\u9d67", 0x2fa10: "\U0002a0ce",
+	0x2fa11: "\u4cf8", 0x2fa12: "\U0002a105", 0x2fa13: "\U0002a20e", 0x2fa14: "\U0002a291",
+	0x2fa15: "\u9ebb", 0x2fa16: "\u4d56", 0x2fa17: "\u9ef9", 0x2fa18: "\u9efe",
+	0x2fa19: "\u9f05", 0x2fa1a: "\u9f0f", 0x2fa1b: "\u9f16", 0x2fa1c: "\u9f3b",
+	0x2fa1d: "\U0002a600",
+}
+
+// compatibilityDecompositions are the full compatibility decompositions
+// differing from the canonical ones
+var compatibilityDecompositions = map[rune]string{
+	0x00a0: " ", 0x00a8: " \u0308", 0x00aa: "a", 0x00af: " \u0304",
+	0x00b2: "2", 0x00b3: "3", 0x00b4: " \u0301", 0x00b5: "\u03bc",
+	0x00b8: " \u0327", 0x00b9: "1", 0x00ba: "o", 0x00bc: "1\u20444",
+	0x00bd: "1\u20442", 0x00be: "3\u20444", 0x0132: "IJ", 0x0133: "ij",
+	0x013f: "L\u00b7", 0x0140: "l\u00b7", 0x0149: "\u02bcn", 0x017f: "s",
+	0x01c4: "DZ\u030c", 0x01c5: "Dz\u030c", 0x01c6: "dz\u030c", 0x01c7: "LJ",
+	0x01c8: "Lj", 0x01c9: "lj", 0x01ca: "NJ", 0x01cb: "Nj",
+	0x01cc: "nj", 0x01f1: "DZ", 0x01f2: "Dz", 0x01f3: "dz",
+	0x02b0: "h", 0x02b1: "\u0266", 0x02b2: "j", 0x02b3: "r",
+	0x02b4: "\u0279", 0x02b5: "\u027b", 0x02b6: "\u0281", 0x02b7: "w",
+	0x02b8: "y", 0x02d8: " \u0306", 0x02d9: " \u0307", 0x02da: " \u030a",
+	0x02db: " \u0328", 0x02dc: " \u0303", 0x02dd: " \u030b", 0x02e0: "\u0263",
+	0x02e1: "l", 0x02e2: "s", 0x02e3: "x", 0x02e4: "\u0295",
+	0x037a: " \u0345", 0x0384: " \u0301", 0x0385: " \u0308\u0301", 0x03d0: "\u03b2",
+	0x03d1: "\u03b8", 0x03d2: "\u03a5", 0x03d3: "\u03a5\u0301", 0x03d4: "\u03a5\u0308",
+	0x03d5: "\u03c6", 0x03d6: "\u03c0", 0x03f0: "\u03ba", 0x03f1: "\u03c1",
+	0x03f2: "\u03c2", 0x03f4: "\u0398", 0x03f5: "\u03b5", 0x03f9: "\u03a3",
+	0x0587: "\u0565\u0582", 0x0675: "\u0627\u0674", 0x0676: "\u0648\u0674", 0x0677: "\u06c7\u0674",
+	0x0678: "\u064a\u0674", 0x0e33: "\u0e4d\u0e32", 0x0eb3: "\u0ecd\u0eb2", 0x0edc: "\u0eab\u0e99",
+	0x0edd: "\u0eab\u0ea1", 0x0f0c: "\u0f0b", 0x0f77: "\u0fb2\u0f71\u0f80", 0x0f79: "\u0fb3\u0f71\u0f80",
+	0x10fc: "\u10dc", 0x1d2c: "A", 0x1d2d: "\u00c6", 0x1d2e: "B",
+	0x1d30: "D", 0x1d31: "E", 0x1d32: "\u018e", 0x1d33: "G",
+	0x1d34: "H", 0x1d35: "I", 0x1d36: "J", 0x1d37: "K",
+	0x1d38: "L", 0x1d39: "M", 0x1d3a: "N", 0x1d3c: "O",
+	0x1d3d: "\u0222", 0x1d3e: "P", 0x1d3f: "R", 0x1d40: "T",
+	0x1d41: "U", 0x1d42: "W", 0x1d43: "a", 0x1d44: "\u0250",
+	0x1d45: "\u0251", 0x1d46: "\u1d02", 0x1d47: "b", 0x1d48: "d",
+	0x1d49: "e", 0x1d4a: "\u0259", 0x1d4b: "\u025b", 0x1d4c: "\u025c",
+	0x1d4d: "g", 0x1d4f: "k", 0x1d50: "m", 0x1d51: "\u014b",
+	0x1d52: "o", 0x1d53: "\u0254", 0x1d54: "\u1d16", 0x1d55: "\u1d17",
+	0x1d56: "p", 0x1d57: "t", 0x1d58: "u", 0x1d59: "\u1d1d",
+	0x1d5a: "\u026f", 0x1d5b: "v", 0x1d5c: "\u1d25", 0x1d5d: "\u03b2",
+	0x1d5e: "\u03b3", 0x1d5f: "\u03b4", 0x1d60: "\u03c6", 0x1d61: "\u03c7",
+	0x1d62: "i", 0x1d63: "r", 0x1d64: "u", 0x1d65: "v",
+	0x1d66: "\u03b2", 0x1d67: "\u03b3", 0x1d68: "\u03c1", 0x1d69: "\u03c6",
+	0x1d6a: "\u03c7", 0x1d78: "\u043d", 0x1d9b: "\u0252", 0x1d9c: "c",
+	0x1d9d: "\u0255", 0x1d9e: "\u00f0", 0x1d9f: "\u025c", 0x1da0: "f",
+	0x1da1: "\u025f", 0x1da2: "\u0261", 0x1da3: "\u0265", 0x1da4: "\u0268",
+	0x1da5: "\u0269", 0x1da6: "\u026a", 0x1da7: "\u1d7b", 0x1da8: "\u029d",
+	0x1da9: "\u026d", 0x1daa: "\u1d85", 0x1dab: "\u029f", 0x1dac: "\u0271",
+	0x1dad: "\u0270", 0x1dae: "\u0272", 0x1daf: "\u0273", 0x1db0: "\u0274",
+	0x1db1: "\u0275", 0x1db2: "\u0278", 0x1db3: "\u0282", 0x1db4: "\u0283",
+	0x1db5: "\u01ab", 0x1db6: "\u0289", 0x1db7: "\u028a", 0x1db8: "\u1d1c",
+	0x1db9: "\u028b", 0x1dba: "\u028c", 0x1dbb: "z", 0x1dbc: "\u0290",
+	0x1dbd: "\u0291", 0x1dbe: "\u0292", 0x1dbf: "\u03b8", 0x1e9a: "a\u02be",
+	0x1e9b: "s\u0307", 0x1fbd: " \u0313", 0x1fbf: " \u0313", 0x1fc0: " \u0342",
+	0x1fc1: " \u0308\u0342", 0x1fcd: " \u0313\u0300", 0x1fce: " \u0313\u0301", 0x1fcf: " \u0313\u0342",
+	0x1fdd: " \u0314\u0300", 0x1fde: " \u0314\u0301", 0x1fdf: " \u0314\u0342", 0x1fed: " \u0308\u0300",
+	0x1fee: " \u0308\u0301", 0x1ffd: " \u0301", 0x1ffe: " \u0314", 0x2000: " ",
+	0x2001: " ", 0x2002: " ", 0x2003: " ", 0x2004: " ",
+	0x2005: " ", 0x2006: " ", 0x2007: " ", 0x2008: " ",
+	0x2009: " ", 0x200a: " ", 0x2011: "\u2010", 0x2017: " \u0333",
+	0x2024: ".", 0x2025: "..", 0x2026: "...", 0x202f: " ",
+	0x2033: "\u2032\u2032", 0x2034: "\u2032\u2032\u2032", 0x2036: "\u2035\u2035", 0x2037: "\u2035\u2035\u2035",
+	0x203c: "!!", 0x203e: " \u0305", 0x2047: "??", 0x2048: "?!",
+	0x2049: "!?", 0x2057: "\u2032\u2032\u2032\u2032", 0x205f: " ", 0x2070: "0",
+	0x2071: "i", 0x2074: "4", 0x2075: "5", 0x2076: "6",
+	0x2077: "7", 0x2078: "8", 0x2079: "9", 0x207a: "+",
+	0x207b: "\u2212", 0x207c: "=", 0x207d: "(", 0x207e: ")",
+	0x207f: "n", 0x2080: "0", 0x2081: "1", 0x2082: "2",
+	0x2083: "3", 0x2084: "4", 0x2085: "5", 0x2086: "6",
+	0x2087: "7", 0x2088: "8", 0x2089: "9", 0x208a: "+",
+	0x208b: "\u2212", 0x208c: "=", 0x208d: "(", 0x208e: ")",
+	0x2090: "a", 0x2091: "e", 0x2092: "o", 0x2093: "x",
+	0x2094: "\u0259", 0x2095: "h", 0x2096: "k", 0x2097: "l",
+	0x2098: "m", 0x2099: "n", 0x209a: "p", 0x209b: "s",
+	0x209c: "t", 0x20a8: "Rs", 0x2100: "a/c", 0x2101: "a/s",
+	0x2102: "C", 0x2103: "\u00b0C", 0x2105: "c/o", 0x2106: "c/u",
+	0x2107: "\u0190", 0x2109: "\u00b0F", 0x210a: "g", 0x210b: "H",
+	0x210c: "H", 0x210d: "H", 0x210e: "h", 0x210f: "\u0127",
+	0x2110: "I", 0x2111: "I", 0x2112: "L", 0x2113: "l",
+	0x2115: "N", 0x2116: "No", 0x2119: "P", 0x211a: "Q",
+	0x211b: "R", 0x211c: "R", 0x211d: "R", 0x2120: "SM",
+	0x2121: "TEL", 0x2122: "TM", 0x2124: "Z", 0x2128: "Z",
+	0x212c: "B", 0x212d: "C", 0x212f: "e", 0x2130: "E",
+	0x2131: "F", 0x2133: "M", 0x2134: "o", 0x2135: "\u05d0",
+	0x2136: "\u05d1", 0x2137: "\u05d2", 0x2138: "\u05d3", 0x2139: "i",
+	0x213b: "FAX", 0x213c: "\u03c0", 0x213d: "\u03b3", 0x213e: "\u0393",
+	0x213f: "\u03a0", 0x2140: "\u2211", 0x2145: "D", 0x2146: "d",
+	0x2147: "e", 0x2148: "i", 0x2149: "j", 0x2150: "1\u20447",
+	0x2151: "1\u20449", 0x2152: "1\u204410", 0x2153: "1\u20443", 0x2154: "2\u20443",
+	0x2155: "1\u20445", 0x2156: "2\u20445", 0x2157: "3\u20445", 0x2158: "4\u20445",
+	0x2159: "1\u20446", 0x215a: "5\u20446", 0x215b: "1\u20448", 0x215c: "3\u20448",
+	0x215d: "5\u20448", 0x215e: "7\u20448", 0x215f: "1\u2044", 0x2160: "I",
+	0x2161: "II", 0x2162: "III", 0x2163: "IV", 0x2164: "V",
+	0x2165: "VI", 0x2166: "VII", 0x2167: "VIII", 0x2168: "IX",
+	0x2169: "X", 0x216a: "XI", 0x216b: "XII", 0x216c: "L",
+	0x216d: "C", 0x216e: "D", 0x216f: "M", 0x2170: "i",
+	0x2171: "ii", 0x2172: "iii", 0x2173: "iv", 0x2174: "v",
+	0x2175: "vi", 0x2176: "vii", 0x2177: "viii", 0x2178: "ix",
+	0x2179: "x", 0x217a: "xi", 0x217b: "xii", 0x217c: "l",
+	0x217d: "c", 0x217e: "d", 0x217f: "m", 0x2189: "0\u20443",
+	0x222c: "\u222b\u222b", 0x222d: "\u222b\u222b\u222b", 0x222f: "\u222e\u222e", 0x2230: "\u222e\u222e\u222e",
+	0x2460: "1", 0x2461: "2", 0x2462: "3", 0x2463: "4",
+	0x2464: "5", 0x2465: "6", 0x2466: "7", 0x2467: "8",
+	0x2468: "9", 0x2469: "10", 0x246a: "11", 0x246b: "12",
+	0x246c: "13", 0x246d: "14", 0x246e: "15", 0x246f: "16",
+	0x2470: "17", 0x2471: "18", 0x2472: "19", 0x2473: "20",
+	0x2474: "(1)", 0x2475: "(2)", 0x2476: "(3)", 0x2477: "(4)",
+	0x2478: "(5)", 0x2479: "(6)", 0x247a: "(7)", 0x247b: "(8)",
+	0x247c: "(9)", 0x247d: "(10)", 0x247e: "(11)", 0x247f: "(12)",
+	0x2480: "(13)", 0x2481: "(14)", 0x2482: "(15)", 0x2483: "(16)",
+	0x2484: "(17)", 0x2485: "(18)", 0x2486: "(19)", 0x2487: "(20)",
+	0x2488: "1.", 0x2489: "2.", 0x248a: "3.", 0x248b: "4.",
+	0x248c: "5.", 0x248d: "6.", 0x248e: "7.", 0x248f: "8.",
+	0x2490: "9.", 0x2491: "10.", 0x2492: "11.", 0x2493: "12.",
+	0x2494: "13.", 0x2495: "14.", 0x2496: "15.", 0x2497: "16.",
+	0x2498: "17.", 0x2499: "18.", 0x249a: "19.", 0x249b: "20.",
+	0x249c: "(a)", 0x249d: "(b)", 0x249e: "(c)", 0x249f: "(d)",
+	0x24a0: "(e)", 0x24a1: "(f)", 0x24a2: "(g)", 0x24a3: "(h)",
+	0x24a4: "(i)", 0x24a5: "(j)", 0x24a6: "(k)", 0x24a7: "(l)",
+	0x24a8: "(m)", 0x24a9: "(n)", 0x24aa: "(o)", 0x24ab: "(p)",
+	0x24ac: "(q)", 0x24ad: "(r)", 0x24ae: "(s)", 0x24af: "(t)",
+	0x24b0: "(u)", 0x24b1: "(v)", 0x24b2: "(w)", 0x24b3: "(x)",
+	0x24b4: "(y)", 0x24b5: "(z)", 0x24b6: "A", 0x24b7: "B",
+	0x24b8: "C", 0x24b9: "D", 0x24ba: "E", 0x24bb: "F",
+	0x24bc: "G", 0x24bd: "H", 0x24be: "I", 0x24bf: "J",
+	0x24c0: "K", 0x24c1: "L", 0x24c2: "M", 0x24c3: "N",
+	0x24c4: "O", 0x24c5: "P", 0x24c6: "Q", 0x24c7: "R",
+	0x24c8: "S", 0x24c9: "T", 0x24ca: "U", 0x24cb: "V",
+	0x24cc: "W", 0x24cd: "X", 0x24ce: "Y", 0x24cf: "Z",
+	0x24d0: "a", 0x24d1: "b", 0x24d2: "c", 0x24d3: "d",
+	0x24d4: "e", 0x24d5: "f", 0x24d6: "g", 0x24d7: "h",
+	0x24d8: "i", 0x24d9: "j", 0x24da: "k", 0x24db: "l",
+	0x24dc: "m", 0x24dd: "n", 0x24de: "o", 0x24df: "p",
+	0x24e0: "q", 0x24e1: "r", 0x24e2: "s", 0x24e3: "t",
+	0x24e4: "u", 0x24e5: "v", 0x24e6: "w", 0x24e7: "x",
+	0x24e8: "y", 0x24e9: "z", 0x24ea: "0", 0x2a0c: "\u222b\u222b\u222b\u222b",
+	0x2a74: "::=", 0x2a75: "==", 0x2a76: "===", 0x2c7c: "j",
+	0x2c7d: "V", 0x2d6f: "\u2d61", 0x2e9f: "\u6bcd", 0x2ef3: "\u9f9f",
+	0x2f00: "\u4e00", 0x2f01: "\u4e28", 0x2f02: "\u4e36", 0x2f03: "\u4e3f",
+	0x2f04: "\u4e59", 0x2f05: "\u4e85", 0x2f06: "\u4e8c", 0x2f07: "\u4ea0",
+	0x2f08: "\u4eba", 0x2f09: "\u513f", 0x2f0a: "\u5165", 0x2f0b: "\u516b",
+	0x2f0c: "\u5182", 0x2f0d: "\u5196", 0x2f0e: "\u51ab", 0x2f0f: "\u51e0",
+	0x2f10: "\u51f5", 0x2f11: "\u5200", 0x2f12: "\u529b", 0x2f13: "\u52f9",
+	0x2f14: "\u5315", 0x2f15: "\u531a", 0x2f16: "\u5338", 0x2f17: "\u5341",
+	0x2f18: "\u535c", 0x2f19: "\u5369", 0x2f1a: "\u5382", 0x2f1b: "\u53b6",
+	0x2f1c: "\u53c8", 0x2f1d: "\u53e3", 0x2f1e: "\u56d7", 0x2f1f: "\u571f",
+	0x2f20: "\u58eb", 0x2f21: "\u5902", 0x2f22: "\u590a", 0x2f23: "\u5915",
+	0x2f24: "\u5927", 0x2f25: "\u5973", 0x2f26: "\u5b50", 0x2f27: "\u5b80",
+	0x2f28: "\u5bf8", 0x2f29: "\u5c0f", 0x2f2a: "\u5c22", 0x2f2b: "\u5c38",
+	0x2f2c: "\u5c6e", 0x2f2d: "\u5c71", 0x2f2e: "\u5ddb", 0x2f2f: "\u5de5",
+	0x2f30: "\u5df1", 0x2f31: "\u5dfe", 0x2f32: "\u5e72", 0x2f33: "\u5e7a",
+	0x2f34: "\u5e7f", 0x2f35: "\u5ef4", 0x2f36: "\u5efe", 0x2f37: "\u5f0b",
+	0x2f38: "\u5f13", 0x2f39: "\u5f50", 0x2f3a: "\u5f61", 0x2f3b: "\u5f73",
+	0x2f3c: "\u5fc3", 0x2f3d: "\u6208", 0x2f3e: "\u6236", 0x2f3f: "\u624b",
+	0x2f40: "\u652f", 0x2f41: "\u6534", 0x2f42: "\u6587", 0x2f43: "\u6597",
+	0x2f44: "\u65a4", 0x2f45: "\u65b9", 0x2f46: "\u65e0", 0x2f47: "\u65e5",
+	0x2f48: "\u66f0", 0x2f49: "\u6708", 0x2f4a: "\u6728", 0x2f4b: "\u6b20",
+	0x2f4c: "\u6b62", 0x2f4d: "\u6b79", 0x2f4e: "\u6bb3", 0x2f4f: "\u6bcb",
+	0x2f50: "\u6bd4", 0x2f51: "\u6bdb", 0x2f52: "\u6c0f", 0x2f53: "\u6c14",
+	0x2f54: "\u6c34", 0x2f55: "\u706b", 0x2f56: "\u722a", 0x2f57: "\u7236",
+	0x2f58: "\u723b", 0x2f59: "\u723f", 0x2f5a: "\u7247", 0x2f5b: "\u7259",
+	0x2f5c: "\u725b", 0x2f5d: "\u72ac", 0x2f5e: "\u7384", 0x2f5f: "\u7389",
+	0x2f60: "\u74dc", 0x2f61: "\u74e6", 0x2f62: "\u7518", 0x2f63: "\u751f",
+	0x2f64: "\u7528", 0x2f65: "\u7530", 0x2f66: "\u758b", 0x2f67: "\u7592",
+	0x2f68: "\u7676", 0x2f69: "\u767d", 0x2f6a: "\u76ae", 0x2f6b: "\u76bf",
+	0x2f6c: "\u76ee", 0x2f6d: "\u77db", 0x2f6e: "\u77e2", 0x2f6f: "\u77f3",
+	0x2f70: "\u793a", 0x2f71: "\u79b8", 0x2f72: "\u79be", 0x2f73: "\u7a74",
+	0x2f74: "\u7acb", 0x2f75: "\u7af9", 0x2f76: "\u7c73", 0x2f77: "\u7cf8",
+	0x2f78: "\u7f36", 0x2f79: "\u7f51", 0x2f7a: "\u7f8a", 0x2f7b: "\u7fbd",
+	0x2f7c: "\u8001", 0x2f7d: "\u800c", 0x2f7e: "\u8012", 0x2f7f: "\u8033",
+	0x2f80: "\u807f", 0x2f81: "\u8089", 0x2f82: "\u81e3", 0x2f83: "\u81ea",
+	0x2f84: "\u81f3", 0x2f85: "\u81fc", 0x2f86: "\u820c", 0x2f87: "\u821b",
+	0x2f88: "\u821f", 0x2f89: "\u826e", 0x2f8a: "\u8272", 0x2f8b: "\u8278",
+	0x2f8c: "\u864d", 0x2f8d: "\u866b", 0x2f8e: "\u8840", 0x2f8f: "\u884c",
+	0x2f90: "\u8863", 0x2f91: "\u897e", 0x2f92: "\u898b", 0x2f93: "\u89d2",
+	0x2f94: "\u8a00", 0x2f95: "\u8c37", 0x2f96: "\u8c46", 0x2f97: "\u8c55",
+	0x2f98: "\u8c78", 0x2f99: "\u8c9d", 0x2f9a: "\u8d64", 0x2f9b: "\u8d70",
+	0x2f9c: "\u8db3", 0x2f9d: "\u8eab", 0x2f9e: "\u8eca", 0x2f9f: "\u8f9b",
+	0x2fa0: "\u8fb0", 0x2fa1: "\u8fb5", 0x2fa2: "\u9091", 0x2fa3: "\u9149",
+	0x2fa4: "\u91c6", 0x2fa5: "\u91cc", 0x2fa6: "\u91d1", 0x2fa7: "\u9577",
+	0x2fa8: "\u9580", 0x2fa9: "\u961c", 0x2faa: "\u96b6", 0x2fab: "\u96b9",
+	0x2fac: "\u96e8", 0x2fad: "\u9751", 0x2fae: "\u975e", 0x2faf: "\u9762",
+	0x2fb0: "\u9769", 0x2fb1: "\u97cb", 0x2fb2: "\u97ed", 0x2fb3: "\u97f3",
+	0x2fb4: "\u9801", 0x2fb5: "\u98a8", 0x2fb6: "\u98db", 0x2fb7: "\u98df",
+	0x2fb8: "\u9996", 0x2fb9: "\u9999", 0x2fba: "\u99ac", 0x2fbb: "\u9aa8",
+	0x2fbc: "\u9ad8", 0x2fbd: "\u9adf", 0x2fbe: "\u9b25", 0x2fbf: "\u9b2f",
+	0x2fc0: "\u9b32", 0x2fc1: "\u9b3c", 0x2fc2: "\u9b5a", 0x2fc3: "\u9ce5",
+	0x2fc4: "\u9e75", 0x2fc5: "\u9e7f", 0x2fc6: "\u9ea5", 0x2fc7: "\u9ebb",
+	0x2fc8: "\u9ec3", 0x2fc9: "\u9ecd", 0x2fca: "\u9ed1", 0x2fcb: "\u9ef9",
+	0x2fcc: "\u9efd", 0x2fcd: "\u9f0e", 0x2fce: "\u9f13", 0x2fcf: "\u9f20",
+	0x2fd0: "\u9f3b", 0x2fd1: "\u9f4a", 0x2fd2: "\u9f52", 0x2fd3: "\u9f8d",
+	0x2fd4: "\u9f9c", 0x2fd5: "\u9fa0", 0x3000: " ", 0x3036: "\u3012",
+	0x3038: "\u5341", 0x3039: "\u5344", 0x303a: "\u5345", 0x309b: " \u3099",
+	0x309c: " \u309a", 0x309f: "\u3088\u308a", 0x30ff: "\u30b3\u30c8", 0x3131: "\u1100",
+	0x3132: "\u1101", 0x3133: "\u11aa", 0x3134: "\u1102", 0x3135: "\u11ac",
+	0x3136: "\u11ad", 0x3137: "\u1103", 0x3138: "\u1104", 0x3139: "\u1105",
+	0x313a: "\u11b0", 0x313b: "\u11b1", 0x313c: "\u11b2", 0x313d: "\u11b3",
+	0x313e: "\u11b4", 0x313f: "\u11b5", 0x3140: "\u111a", 0x3141: "\u1106",
+	0x3142: "\u1107", 0x3143: "\u1108", 0x3144: "\u1121", 0x3145: "\u1109",
+	0x3146: "\u110a", 0x3147: "\u110b", 0x3148: "\u110c", 0x3149: "\u110d",
+	0x314a: "\u110e", 0x314b: "\u110f", 0x314c: "\u1110", 0x314d: "\u1111",
+	0x314e: "\u1112", 0x314f: "\u1161", 0x3150: "\u1162", 0x3151: "\u1163",
+	0x3152: "\u1164", 0x3153: "\u1165", 0x3154: "\u1166", 0x3155: "\u1167",
+	0x3156: "\u1168", 0x3157: "\u1169", 0x3158: "\u116a", 0x3159: "\u116b",
+	0x315a: "\u116c", 0x315b: "\u116d", 0x315c: "\u116e", 0x315d: "\u116f",
+	0x315e: "\u1170", 0x315f: "\u1171", 0x3160: "\u1172", 0x3161: "\u1173",
+	0x3162: "\u1174", 0x3163: "\u1175", 0x3164: "\u1160", 0x3165: "\u1114",
+	0x3166: "\u1115", 0x3167: "\u11c7", 0x3168: "\u11c8", 0x3169: "\u11cc",
+	0x316a: "\u11ce", 0x316b: "\u11d3", 0x316c: "\u11d7", 0x316d: "\u11d9",
+	0x316e: "\u111c", 0x316f: "\u11dd", 0x3170: "\u11df", 0x3171: "\u111d",
+	0x3172: "\u111e", 0x3173: "\u1120", 0x3174: "\u1122", 0x3175: "\u1123",
+	0x3176: "\u1127", 0x3177: "\u1129", 0x3178: "\u112b", 0x3179: "\u112c",
+	0x317a: "\u112d", 0x317b: "\u112e", 0x317c: "\u112f", 0x317d: "\u1132",
+	0x317e: "\u1136", 0x317f: "\u1140", 0x3180: "\u1147", 0x3181: "\u114c",
+	0x3182: "\u11f1", 0x3183: "\u11f2", 0x3184: "\u1157", 0x3185: "\u1158",
+	0x3186: "\u1159", 0x3187: "\u1184", 0x3188: "\u1185", 0x3189: "\u1188",
+	0x318a: "\u1191", 0x318b: "\u1192", 0x318c: "\u1194", 0x318d: "\u119e",
+	0x318e: "\u11a1", 0x3192: "\u4e00", 0x3193: "\u4e8c", 0x3194: "\u4e09",
+	0x3195: "\u56db", 0x3196: "\u4e0a", 0x3197: "\u4e2d", 0x3198: "\u4e0b",
+	0x3199: "\u7532", 0x319a: "\u4e59", 0x319b: "\u4e19", 0x319c: "\u4e01",
+	0x319d: "\u5929", 0x319e: "\u5730", 0x319f: "\u4eba", 0x3200: "(\u1100)",
+	0x3201: "(\u1102)", 0x3202: "(\u1103)", 0x3203: "(\u1105)", 0x3204: "(\u1106)",
+	0x3205: "(\u1107)", 0x3206: "(\u1109)", 0x3207: "(\u110b)", 0x3208: "(\u110c)",
+	0x3209: "(\u110e)", 0x320a: "(\u110f)", 0x320b: "(\u1110)", 0x320c: "(\u1111)",
+	0x320d: "(\u1112)", 0x320e: "(\u1100\u1161)", 0x320f: "(\u1102\u1161)", 0x3210: "(\u1103\u1161)",
+	0x3211: "(\u1105\u1161)", 0x3212: "(\u1106\u1161)", 0x3213: "(\u1107\u1161)", 0x3214: "(\u1109\u1161)",
+	0x3215: "(\u110b\u1161)", 0x3216: "(\u110c\u1161)", 0x3217: "(\u110e\u1161)", 0x3218: "(\u110f\u1161)",
+	0x3219: "(\u1110\u1161)", 0x321a: "(\u1111\u1161)", 0x321b: "(\u1112\u1161)", 0x321c: "(\u110c\u116e)",
+	0x321d: "(\u110b\u1169\u110c\u1165\u11ab)", 0x321e: "(\u110b\u1169\u1112\u116e)", 0x3220: "(\u4e00)", 0x3221: "(\u4e8c)",
+	0x3222: "(\u4e09)", 0x3223: "(\u56db)", 0x3224: "(\u4e94)", 0x3225: "(\u516d)",
+	0x3226: "(\u4e03)", 0x3227: "(\u516b)", 0x3228: "(\u4e5d)", 0x3229: "(\u5341)",
+	0x322a: "(\u6708)", 0x322b: "(\u706b)", 0x322c: "(\u6c34)", 0x322d: "(\u6728)",
+	0x322e: "(\u91d1)", 0x322f: "(\u571f)", 0x3230: "(\u65e5)", 0x3231: "(\u682a)",
+	0x3232: "(\u6709)", 0x3233: "(\u793e)", 0x3234: "(\u540d)", 0x3235: "(\u7279)",
+	0x3236: "(\u8ca1)", 0x3237: "(\u795d)", 0x3238: "(\u52b4)", 0x3239: "(\u4ee3)",
+	0x323a: "(\u547c)", 0x323b: "(\u5b66)", 0x323c: "(\u76e3)", 0x323d: "(\u4f01)",
+	0x323e: "(\u8cc7)", 0x323f: "(\u5354)", 0x3240: "(\u796d)", 0x3241: "(\u4f11)",
+	0x3242: "(\u81ea)", 0x3243: "(\u81f3)", 0x3244: "\u554f", 0x3245: "\u5e7c",
+	0x3246: "\u6587", 0x3247: "\u7b8f", 0x3250: "PTE", 0x3251: "21",
+	0x3252: "22", 0x3253: "23", 0x3254: "24", 0x3255: "25",
+	0x3256: "26", 0x3257: "27", 0x3258: "28", 0x3259: "29",
+	0x325a: "30", 0x325b: "31", 0x325c: "32", 0x325d: "33",
+	0x325e: "34", 0x325f: "35", 0x3260: "\u1100", 0x3261: "\u1102",
+	0x3262: "\u1103", 0x3263: "\u1105", 0x3264: "\u1106", 0x3265: "\u1107",
+	0x3266: "\u1109", 0x3267: "\u110b", 0x3268: "\u110c", 0x3269: "\u110e",
+	0x326a: "\u110f", 0x326b: "\u1110", 0x326c: "\u1111", 0x326d: "\u1112",
+	0x326e: "\u1100\u1161", 0x326f: "\u1102\u1161", 0x3270: "\u1103\u1161", 0x3271: "\u1105\u1161",
+	0x3272: "\u1106\u1161", 0x3273: "\u1107\u1161", 0x3274: "\u1109\u1161", 0x3275: "\u110b\u1161",
+	0x3276: "\u110c\u1161", 0x3277: "\u110e\u1161", 0x3278: "\u110f\u1161", 0x3279: "\u1110\u1161",
+	0x327a: "\u1111\u1161", 0x327b: "\u1112\u1161", 0x327c: "\u110e\u1161\u11b7\u1100\u1169", 0x327d: "\u110c\u116e\u110b\u1174",
+	0x327e: "\u110b\u116e", 0x3280: "\u4e00", 0x3281: "\u4e8c", 0x3282: "\u4e09",
+	0x3283: "\u56db", 0x3284: "\u4e94", 0x3285: "\u516d", 0x3286: "\u4e03",
+	0x3287: "\u516b", 0x3288: "\u4e5d", 0x3289: "\u5341", 0x328a: "\u6708",
+	0x328b: "\u706b", 0x328c: "\u6c34", 0x328d: "\u6728", 0x328e: "\u91d1",
+	0x328f: "\u571f", 0x3290: "\u65e5", 0x3291: "\u682a", 0x3292: "\u6709",
+	0x3293: "\u793e", 0x3294: "\u540d", 0x3295: "\u7279", 0x3296: "\u8ca1",
+	0x3297: "\u795d", 0x3298: "\u52b4", 0x3299: "\u79d8", 0x329a: "\u7537",
+	0x329b: "\u5973", 0x329c: "\u9069", 0x329d: "\u512a", 0x329e: "\u5370",
+	0x329f: "\u6ce8", 0x32a0: "\u9805", 0x32a1: "\u4f11", 0x32a2: "\u5199",
+	0x32a3: "\u6b63", 0x32a4: "\u4e0a", 0x32a5: "\u4e2d", 0x32a6: "\u4e0b",
+	0x32a7: "\u5de6", 0x32a8: "\u53f3", 0x32a9: "\u533b", 0x32aa: "\u5b97",
+	0x32ab: "\u5b66", 0x32ac: "\u76e3", 0x32ad: "\u4f01", 0x32ae: "\u8cc7",
+	0x32af: "\u5354", 0x32b0: "\u591c", 0x32b1: "36", 0x32b2: "37",
+	0x32b3: "38", 0x32b4: "39", 0x32b5: "40", 0x32b6: "41",
+	0x32b7: "42", 0x32b8: "43", 0x32b9: "44", 0x32ba: "45",
+	0x32bb: "46", 0x32bc: "47", 0x32bd: "48", 0x32be: "49",
+	0x32bf: "50", 0x32c0: "1\u6708", 0x32c1: "2\u6708", 0x32c2: "3\u6708",
+	0x32c3: "4\u6708", 0x32c4: "5\u6708", 0x32c5: "6\u6708", 0x32c6: "7\u6708",
+	0x32c7: "8\u6708", 0x32c8: "9\u6708", 0x32c9: "10\u6708", 0x32ca: "11\u6708",
+	0x32cb: "12\u6708", 0x32cc: "Hg", 0x32cd: "erg", 0x32ce: "eV",
+	0x32cf: "LTD", 0x32d0: "\u30a2", 0x32d1: "\u30a4", 0x32d2: "\u30a6",
+	0x32d3: "\u30a8", 0x32d4: "\u30aa", 0x32d5: "\u30ab", 0x32d6: "\u30ad",
+	0x32d7: "\u30af", 0x32d8: "\u30b1", 0x32d9: "\u30b3", 0x32da: "\u30b5",
+	0x32db: "\u30b7", 0x32dc: "\u30b9", 0x32dd: "\u30bb", 0x32de: "\u30bd",
+	0x32df: "\u30bf", 0x32e0: "\u30c1", 0x32e1: "\u30c4", 0x32e2: "\u30c6",
+	0x32e3: "\u30c8", 0x32e4: "\u30ca", 0x32e5: "\u30cb", 0x32e6: "\u30cc",
+	0x32e7: "\u30cd", 0x32e8: "\u30ce", 0x32e9: "\u30cf", 0x32ea: "\u30d2",
+	0x32eb: "\u30d5", 0x32ec: "\u30d8", 0x32ed: "\u30db", 0x32ee: "\u30de",
+	0x32ef: "\u30df", 0x32f0: "\u30e0", 0x32f1: "\u30e1", 0x32f2: "\u30e2",
+	0x32f3: "\u30e4", 0x32f4: "\u30e6", 0x32f5: "\u30e8", 0x32f6: "\u30e9",
+	0x32f7: "\u30ea", 0x32f8: "\u30eb", 0x32f9: "\u30ec", 0x32fa: "\u30ed",
+	0x32fb: "\u30ef", 0x32fc: "\u30f0", 0x32fd: "\u30f1", 0x32fe: "\u30f2",
+	0x32ff: "\u4ee4\u548c", 0x3300: "\u30a2\u30cf\u309a\u30fc\u30c8", 0x3301: "\u30a2\u30eb\u30d5\u30a1", 0x3302: "\u30a2\u30f3\u30d8\u309a\u30a2",
+	0x3303: "\u30a2\u30fc\u30eb", 0x3304: "\u30a4\u30cb\u30f3\u30af\u3099", 0x3305: "\u30a4\u30f3\u30c1", 0x3306: "\u30a6\u30a9\u30f3",
+	0x3307: "\u30a8\u30b9\u30af\u30fc\u30c8\u3099", 0x3308: "\u30a8\u30fc\u30ab\u30fc", 0x3309: "\u30aa\u30f3\u30b9", 0x330a: "\u30aa\u30fc\u30e0",
+	0x330b: "\u30ab\u30a4\u30ea", 0x330c: "\u30ab\u30e9\u30c3\u30c8", 0x330d: "\u30ab\u30ed\u30ea\u30fc", 0x330e: "\u30ab\u3099\u30ed\u30f3",
+	0x330f: "\u30ab\u3099\u30f3\u30de", 0x3310: "\u30ad\u3099\u30ab\u3099", 0x3311: "\u30ad\u3099\u30cb\u30fc", 0x3312: "\u30ad\u30e5\u30ea\u30fc",
+	0x3313: "\u30ad\u3099\u30eb\u30bf\u3099\u30fc", 0x3314: "\u30ad\u30ed", 0x3315: "\u30ad\u30ed\u30af\u3099\u30e9\u30e0", 0x3316: "\u30ad\u30ed\u30e1\u30fc\u30c8\u30eb",
+	0x3317: "\u30ad\u30ed\u30ef\u30c3\u30c8", 0x3318: "\u30af\u3099\u30e9\u30e0", 0x3319: "\u30af\u3099\u30e9\u30e0\u30c8\u30f3", 0x331a: "\u30af\u30eb\u30bb\u3099\u30a4\u30ed",
+	0x331b: "\u30af\u30ed\u30fc\u30cd", 0x331c: "\u30b1\u30fc\u30b9", 0x331d: "\u30b3\u30eb\u30ca", 0x331e: "\u30b3\u30fc\u30db\u309a",
+	0x331f: "\u30b5\u30a4\u30af\u30eb", 0x3320: "\u30b5\u30f3\u30c1\u30fc\u30e0", 0x3321: "\u30b7\u30ea\u30f3\u30af\u3099", 0x3322: "\u30bb\u30f3\u30c1",
+	0x3323: "\u30bb\u30f3\u30c8", 0x3324: "\u30bf\u3099\u30fc\u30b9", 0x3325: "\u30c6\u3099\u30b7", 0x3326: "\u30c8\u3099\u30eb",
+	0x3327: "\u30c8\u30f3", 0x3328: "\u30ca\u30ce", 0x3329: "\u30ce\u30c3\u30c8", 0x332a: "\u30cf\u30a4\u30c4",
+	0x332b: "\u30cf\u309a\u30fc\u30bb\u30f3\u30c8", 0x332c: "\u30cf\u309a\u30fc\u30c4", 0x332d: "\u30cf\u3099\u30fc\u30ec\u30eb", 0x332e: "\u30d2\u309a\u30a2\u30b9\u30c8\u30eb",
+	0x332f: "\u30d2\u309a\u30af\u30eb", 0x3330: "\u30d2\u309a\u30b3", 0x3331: "\u30d2\u3099\u30eb", 0x3332: "\u30d5\u30a1\u30e9\u30c3\u30c8\u3099",
+	0x3333: "\u30d5\u30a3\u30fc\u30c8", 0x3334: "\u30d5\u3099\u30c3\u30b7\u30a7\u30eb", 0x3335: "\u30d5\u30e9\u30f3", 0x3336: "\u30d8\u30af\u30bf\u30fc\u30eb",
+	0x3337: "\u30d8\u309a\u30bd", 0x3338: "\u30d8\u309a\u30cb\u30d2", 0x3339: "\u30d8\u30eb\u30c4", 0x333a: "\u30d8\u309a\u30f3\u30b9",
+	0x333b: "\u30d8\u309a\u30fc\u30b7\u3099", 0x333c: "\u30d8\u3099\u30fc\u30bf", 0x333d: "\u30db\u309a\u30a4\u30f3\u30c8", 0x333e: "\u30db\u3099\u30eb\u30c8",
+	0x333f: "\u30db\u30f3", 0x3340: "\u30db\u309a\u30f3\u30c8\u3099", 0x3341: "\u30db\u30fc\u30eb", 0x3342: "\u30db\u30fc\u30f3",
+	0x3343: "\u30de\u30a4\u30af\u30ed", 0x3344: "\u30de\u30a4\u30eb", 0x3345: "\u30de\u30c3\u30cf", 0x3346: "\u30de\u30eb\u30af",
+	0x3347: "\u30de\u30f3\u30b7\u30e7\u30f3", 0x3348: "\u30df\u30af\u30ed\u30f3", 0x3349: "\u30df\u30ea", 0x334a: "\u30df\u30ea\u30cf\u3099\u30fc\u30eb",
+	0x334b: "\u30e1\u30ab\u3099", 0x334c: "\u30e1\u30ab\u3099\u30c8\u30f3", 0x334d: "\u30e1\u30fc\u30c8\u30eb", 0x334e: "\u30e4\u30fc\u30c8\u3099",
+	0x334f: "\u30e4\u30fc\u30eb", 0x3350: "\u30e6\u30a2\u30f3", 0x3351: "\u30ea\u30c3\u30c8\u30eb", 0x3352: "\u30ea\u30e9",
+	0x3353: "\u30eb\u30d2\u309a\u30fc", 0x3354: "\u30eb\u30fc\u30d5\u3099\u30eb", 0x3355: "\u30ec\u30e0", 0x3356: "\u30ec\u30f3\u30c8\u30b1\u3099\u30f3",
+	0x3357: "\u30ef\u30c3\u30c8", 0x3358: "0\u70b9", 0x3359: "1\u70b9", 0x335a: "2\u70b9",
+	0x335b: "3\u70b9", 0x335c: "4\u70b9", 0x335d: "5\u70b9", 0x335e: "6\u70b9",
+	0x335f: "7\u70b9", 0x3360: "8\u70b9", 0x3361: "9\u70b9", 0x3362: "10\u70b9",
+	0x3363: "11\u70b9", 0x3364: "12\u70b9", 0x3365: "13\u70b9", 0x3366: "14\u70b9",
+	0x3367: "15\u70b9", 0x3368: "16\u70b9", 0x3369: "17\u70b9", 0x336a: "18\u70b9",
+	0x336b: "19\u70b9", 0x336c: "20\u70b9", 0x336d: "21\u70b9", 0x336e: "22\u70b9",
+	0x336f: "23\u70b9", 0x3370: "24\u70b9", 0x3371: "hPa", 0x3372: "da",
+	0x3373: "AU", 0x3374: "bar", 0x3375: "oV", 0x3376: "pc",
+	0x3377: "dm", 0x3378: "dm2", 0x3379: "dm3", 0x337a: "IU",
+	0x337b: "\u5e73\u6210", 0x337c: "\u662d\u548c", 0x337d: "\u5927\u6b63", 0x337e: "\u660e\u6cbb",
+	0x337f: "\u682a\u5f0f\u4f1a\u793e", 0x3380: "pA", 0x3381: "nA", 0x3382: "\u03bcA",
+	0x3383: "mA", 0x3384: "kA", 0x3385: "KB", 0x3386: "MB",
+	0x3387: "GB", 0x3388: "cal", 0x3389: "kcal", 0x338a: "pF",
+	0x338b: "nF", 0x338c: "\u03bcF", 0x338d: "\u03bcg", 0x338e: "mg",
+	0x338f: "kg", 0x3390: "Hz", 0x3391: "kHz", 0x3392: "MHz",
+	0x3393: "GHz", 0x3394: "THz", 0x3395: "\u03bcl", 0x3396: "ml",
+	0x3397: "dl", 0x3398: "kl", 0x3399: "fm", 0x339a: "nm",
+	0x339b: "\u03bcm", 0x339c: "mm", 0x339d: "cm", 0x339e: "km",
+	0x339f: "mm2", 0x33a0: "cm2", 0x33a1: "m2", 0x33a2: "km2",
+	0x33a3: "mm3", 0x33a4: "cm3", 0x33a5: "m3", 0x33a6: "km3",
+	0x33a7: "m\u2215s", 0x33a8: "m\u2215s2", 0x33a9: "Pa", 0x33aa: "kPa",
+	0x33ab: "MPa", 0x33ac: "GPa", 0x33ad: "rad", 0x33ae: "rad\u2215s",
+	0x33af: "rad\u2215s2", 0x33b0: "ps", 0x33b1: "ns", 0x33b2: "\u03bcs",
+	0x33b3: "ms", 0x33b4: "pV", 0x33b5: "nV", 0x33b6: "\u03bcV",
+	0x33b7: "mV", 0x33b8: "kV", 0x33b9: "MV", 0x33ba: "pW",
+	0x33bb: "nW", 0x33bc: "\u03bcW", 0x33bd: "mW", 0x33be: "kW",
+	0x33bf: "MW", 0x33c0: "k\u03a9", 0x33c1: "M\u03a9", 0x33c2: "a.m.",
+	0x33c3: "Bq", 0x33c4: "cc", 0x33c5: "cd", 0x33c6: "C\u2215kg",
+	0x33c7: "Co.", 0x33c8: "dB", 0x33c9: "Gy", 0x33ca: "ha",
+	0x33cb: "HP", 0x33cc: "in", 0x33cd: "KK", 0x33ce: "KM",
+	0x33cf: "kt", 0x33d0: "lm", 0x33d1: "ln", 0x33d2: "log",
+	0x33d3: "lx", 0x33d4: "mb", 0x33d5: "mil", 0x33d6: "mol",
+	0x33d7: "PH", 0x33d8: "p.m.", 0x33d9: "PPM", 0x33da: "PR",
+	0x33db: "sr", 0x33dc: "Sv", 0x33dd: "Wb", 0x33de: "V\u2215m",
+	0x33df: "A\u2215m", 0x33e0: "1\u65e5", 0x33e1: "2\u65e5", 0x33e2: "3\u65e5",
+	0x33e3: "4\u65e5", 0x33e4: "5\u65e5", 0x33e5: "6\u65e5", 0x33e6: "7\u65e5",
+	0x33e7: "8\u65e5", 0x33e8: "9\u65e5", 0x33e9: "10\u65e5", 0x33ea: "11\u65e5",
+	0x33eb: "12\u65e5", 0x33ec: "13\u65e5", 0x33ed: "14\u65e5", 0x33ee: "15\u65e5",
+	0x33ef: "16\u65e5", 0x33f0: "17\u65e5", 0x33f1: "18\u65e5", 0x33f2: "19\u65e5",
+	0x33f3: "20\u65e5", 0x33f4: "21\u65e5", 0x33f5: "22\u65e5", 0x33f6: "23\u65e5",
+	0x33f7: "24\u65e5", 0x33f8: "25\u65e5", 0x33f9: "26\u65e5", 0x33fa: "27\u65e5",
+	0x33fb: "28\u65e5", 0x33fc: "29\u65e5", 0x33fd: "30\u65e5", 0x33fe: "31\u65e5",
+	0x33ff: "gal", 0xa69c: "\u044a", 0xa69d: "\u044c", 0xa770: "\ua76f",
+	0xa7f2: "C", 0xa7f3: "F", 0xa7f4: "Q", 0xa7f8: "\u0126",
+	0xa7f9: "\u0153", 0xab5c: "\ua727", 0xab5d: "\uab37", 0xab5e: "\u026b",
+	0xab5f: "\uab52", 0xab69: "\u028d", 0xfb00: "ff", 0xfb01: "fi",
+	0xfb02: "fl", 0xfb03: "ffi", 0xfb04: "ffl", 0xfb05: "st",
+	0xfb06: "st", 0xfb13: "\u0574\u0576", 0xfb14: "\u0574\u0565", 0xfb15: "\u0574\u056b",
+	0xfb16: "\u057e\u0576", 0xfb17: "\u0574\u056d", 0xfb20: "\u05e2", 0xfb21: "\u05d0",
+	0xfb22: "\u05d3", 0xfb23: "\u05d4", 0xfb24: "\u05db", 0xfb25: "\u05dc",
+	0xfb26: "\u05dd", 0xfb27: "\u05e8", 0xfb28: "\u05ea", 0xfb29: "+",
+	0xfb4f: "\u05d0\u05dc", 0xfb50: "\u0671", 0xfb51: "\u0671", 0xfb52: "\u067b",
+	0xfb53: "\u067b", 0xfb54: "\u067b", 0xfb55: "\u067b", 0xfb56: "\u067e",
+	0xfb57: "\u067e", 0xfb58: "\u067e", 0xfb59: "\u067e", 0xfb5a: "\u0680",
+	0xfb5b: "\u0680", 0xfb5c: "\u0680", 0xfb5d: "\u0680", 0xfb5e: "\u067a",
+	0xfb5f: "\u067a", 0xfb60: "\u067a", 0xfb61: "\u067a", 0xfb62: "\u067f",
+	0xfb63: "\u067f", 0xfb64: "\u067f", 0xfb65: "\u067f", 0xfb66: "\u0679",
+	0xfb67: "\u0679", 0xfb68: "\u0679", 0xfb69: "\u0679", 0xfb6a: "\u06a4",
+	0xfb6b: "\u06a4", 0xfb6c: "\u06a4", 0xfb6d: "\u06a4", 0xfb6e: "\u06a6",
+	0xfb6f: "\u06a6", 0xfb70: "\u06a6", 0xfb71: "\u06a6", 0xfb72: "\u0684",
+	0xfb73: "\u0684", 0xfb74: "\u0684", 0xfb75: "\u0684", 0xfb76: "\u0683",
+	0xfb77: "\u0683", 0xfb78: "\u0683", 0xfb79: "\u0683", 0xfb7a: "\u0686",
+	0xfb7b: "\u0686", 0xfb7c: "\u0686", 0xfb7d: "\u0686", 0xfb7e: "\u0687",
+	0xfb7f: "\u0687", 0xfb80: "\u0687", 0xfb81: "\u0687", 0xfb82: "\u068d",
+	0xfb83: "\u068d", 0xfb84: "\u068c", 0xfb85: "\u068c", 0xfb86: "\u068e",
+	0xfb87: "\u068e", 0xfb88: "\u0688", 0xfb89: "\u0688", 0xfb8a: "\u0698",
+	0xfb8b: "\u0698", 0xfb8c: "\u0691", 0xfb8d: "\u0691", 0xfb8e: "\u06a9",
+	0xfb8f: "\u06a9", 0xfb90: "\u06a9", 0xfb91: "\u06a9", 0xfb92: "\u06af",
+	0xfb93: "\u06af", 0xfb94: "\u06af", 0xfb95: "\u06af", 0xfb96: "\u06b3",
+	0xfb97: "\u06b3", 0xfb98: "\u06b3", 0xfb99: "\u06b3", 0xfb9a: "\u06b1",
+	0xfb9b: "\u06b1", 0xfb9c: "\u06b1", 0xfb9d: "\u06b1", 0xfb9e: "\u06ba",
+	0xfb9f: "\u06ba", 0xfba0: "\u06bb", 0xfba1: "\u06bb", 0xfba2: "\u06bb",
+	0xfba3: "\u06bb", 0xfba4: "\u06d5\u0654", 0xfba5: "\u06d5\u0654", 0xfba6: "\u06c1",
+	0xfba7: "\u06c1", 0xfba8: "\u06c1", 0xfba9: "\u06c1", 0xfbaa: "\u06be",
+	0xfbab: "\u06be", 0xfbac: "\u06be", 0xfbad: "\u06be", 0xfbae: "\u06d2",
+	0xfbaf: "\u06d2", 0xfbb0: "\u06d2\u0654", 0xfbb1: "\u06d2\u0654", 0xfbd3: "\u06ad",
+	0xfbd4: "\u06ad", 0xfbd5: "\u06ad", 0xfbd6: "\u06ad", 0xfbd7: "\u06c7",
+	0xfbd8: "\u06c7", 0xfbd9: "\u06c6", 0xfbda: "\u06c6", 0xfbdb: "\u06c8",
+	0xfbdc: "\u06c8", 0xfbdd: "\u06c7\u0674", 0xfbde: "\u06cb", 0xfbdf: "\u06cb",
+	0xfbe0: "\u06c5", 0xfbe1: "\u06c5", 0xfbe2: "\u06c9", 0xfbe3: "\u06c9",
+	0xfbe4: "\u06d0", 0xfbe5: "\u06d0", 0xfbe6: "\u06d0", 0xfbe7: "\u06d0",
+	0xfbe8: "\u0649", 0xfbe9: "\u0649", 0xfbea: "\u064a\u0654\u0627", 0xfbeb: "\u064a\u0654\u0627",
+	0xfbec: "\u064a\u0654\u06d5", 0xfbed: "\u064a\u0654\u06d5", 0xfbee: "\u064a\u0654\u0648", 0xfbef: "\u064a\u0654\u0648",
+	0xfbf0: "\u064a\u0654\u06c7", 0xfbf1: "\u064a\u0654\u06c7", 0xfbf2: "\u064a\u0654\u06c6", 0xfbf3: "\u064a\u0654\u06c6",
+	0xfbf4: "\u064a\u0654\u06c8", 0xfbf5: "\u064a\u0654\u06c8", 0xfbf6: "\u064a\u0654\u06d0", 0xfbf7: "\u064a\u0654\u06d0",
+	0xfbf8: "\u064a\u0654\u06d0", 0xfbf9: "\u064a\u0654\u0649", 0xfbfa: "\u064a\u0654\u0649", 0xfbfb: "\u064a\u0654\u0649",
+	0xfbfc: "\u06cc", 0xfbfd: "\u06cc", 0xfbfe: "\u06cc", 0xfbff: "\u06cc",
+	0xfc00: "\u064a\u0654\u062c", 0xfc01: "\u064a\u0654\u062d", 0xfc02: "\u064a\u0654\u0645", 0xfc03: "\u064a\u0654\u0649",
+	0xfc04: "\u064a\u0654\u064a", 0xfc05: "\u0628\u062c", 0xfc06: "\u0628\u062d", 0xfc07: "\u0628\u062e",
+	0xfc08: "\u0628\u0645", 0xfc09: "\u0628\u0649", 0xfc0a: "\u0628\u064a", 0xfc0b: "\u062a\u062c",
+	0xfc0c: "\u062a\u062d", 0xfc0d: "\u062a\u062e", 0xfc0e: "\u062a\u0645", 0xfc0f: "\u062a\u0649",
+	0xfc10: "\u062a\u064a", 0xfc11: "\u062b\u062c", 0xfc12: "\u062b\u0645", 0xfc13: "\u062b\u0649",
+	0xfc14: "\u062b\u064a", 0xfc15: "\u062c\u062d", 0xfc16: "\u062c\u0645", 0xfc17: "\u062d\u062c",
+	0xfc18: "\u062d\u0645", 0xfc19: "\u062e\u062c", 0xfc1a: "\u062e\u062d", 0xfc1b: "\u062e\u0645",
+	0xfc1c: "\u0633\u062c", 0xfc1d: "\u0633\u062d", 0xfc1e: "\u0633\u062e", 0xfc1f: "\u0633\u0645",
+	0xfc20: "\u0635\u062d", 0xfc21: "\u0635\u0645", 0xfc22: "\u0636\u062c", 0xfc23: "\u0636\u062d",
+	0xfc24: "\u0636\u062e", 0xfc25: "\u0636\u0645", 0xfc26: "\u0637\u062d", 0xfc27: "\u0637\u0645",
+	0xfc28: "\u0638\u0645", 0xfc29: "\u0639\u062c", 0xfc2a: "\u0639\u0645", 0xfc2b: "\u063a\u062c",
+	0xfc2c: "\u063a\u0645", 0xfc2d: "\u0641\u062c", 0xfc2e: "\u0641\u062d", 0xfc2f: "\u0641\u062e",
+	0xfc30: "\u0641\u0645", 0xfc31: "\u0641\u0649", 0xfc32: "\u0641\u064a", 0xfc33: "\u0642\u062d",
+	0xfc34: "\u0642\u0645", 0xfc35: "\u0642\u0649", 0xfc36: "\u0642\u064a", 0xfc37: "\u0643\u0627",
+	0xfc38: "\u0643\u062c", 0xfc39: "\u0643\u062d", 0xfc3a: "\u0643\u062e", 0xfc3b: "\u0643\u0644",
+	0xfc3c: "\u0643\u0645", 0xfc3d: "\u0643\u0649", 0xfc3e: "\u0643\u064a", 0xfc3f: "\u0644\u062c",
+	0xfc40: "\u0644\u062d", 0xfc41: "\u0644\u062e", 0xfc42: "\u0644\u0645", 0xfc43: "\u0644\u0649",
+	0xfc44: "\u0644\u064a", 0xfc45: "\u0645\u062c", 0xfc46: "\u0645\u062d", 0xfc47: "\u0645\u062e",
+	0xfc48: "\u0645\u0645", 0xfc49: "\u0645\u0649", 0xfc4a: "\u0645\u064a", 0xfc4b: "\u0646\u062c",
+	0xfc4c: "\u0646\u062d", 0xfc4d: "\u0646\u062e", 0xfc4e: "\u0646\u0645", 0xfc4f: "\u0646\u0649",
+	0xfc50: "\u0646\u064a", 0xfc51: "\u0647\u062c", 0xfc52: "\u0647\u0645", 0xfc53: "\u0647\u0649",
+	0xfc54: "\u0647\u064a", 0xfc55: "\u064a\u062c", 0xfc56: "\u064a\u062d", 0xfc57: "\u064a\u062e",
+	0xfc58: "\u064a\u0645", 0xfc59: "\u064a\u0649", 0xfc5a: "\u064a\u064a", 0xfc5b: "\u0630\u0670",
+	0xfc5c: "\u0631\u0670", 0xfc5d: "\u0649\u0670", 0xfc5e: " \u064c\u0651", 0xfc5f: " \u064d\u0651",
+	0xfc60: " \u064e\u0651", 0xfc61: " \u064f\u0651", 0xfc62: " \u0650\u0651", 0xfc63: " \u0651\u0670",
+	0xfc64: "\u064a\u0654\u0631", 0xfc65: "\u064a\u0654\u0632", 0xfc66: "\u064a\u0654\u0645", 0xfc67: "\u064a\u0654\u0646",
+	0xfc68: "\u064a\u0654\u0649", 0xfc69: "\u064a\u0654\u064a", 0xfc6a: "\u0628\u0631", 0xfc6b: "\u0628\u0632",
+	0xfc6c: "\u0628\u0645", 0xfc6d: "\u0628\u0646", 0xfc6e: "\u0628\u0649", 0xfc6f: "\u0628\u064a",
+	0xfc70: "\u062a\u0631", 0xfc71: "\u062a\u0632", 0xfc72: "\u062a\u0645", 0xfc73: "\u062a\u0646",
+	0xfc74: "\u062a\u0649", 0xfc75: "\u062a\u064a", 0xfc76: "\u062b\u0631", 0xfc77: "\u062b\u0632",
+	0xfc78: "\u062b\u0645", 0xfc79: "\u062b\u0646", 0xfc7a: "\u062b\u0649", 0xfc7b: "\u062b\u064a",
+	0xfc7c: "\u0641\u0649", 0xfc7d: "\u0641\u064a", 0xfc7e: "\u0642\u0649", 0xfc7f: "\u0642\u064a",
+	0xfc80: "\u0643\u0627", 0xfc81: "\u0643\u0644", 0xfc82: "\u0643\u0645", 0xfc83: "\u0643\u0649",
+	0xfc84: "\u0643\u064a", 0xfc85: "\u0644\u0645", 0xfc86: "\u0644\u0649", 0xfc87: "\u0644\u064a",
+	0xfc88: "\u0645\u0627", 0xfc89: "\u0645\u0645", 0xfc8a: "\u0646\u0631", 0xfc8b: "\u0646\u0632",
+	0xfc8c: "\u0646\u0645", 0xfc8d: "\u0646\u0646", 0xfc8e: "\u0646\u0649", 0xfc8f: "\u0646\u064a",
+	0xfc90: "\u0649\u0670", 0xfc91: "\u064a\u0631", 0xfc92: "\u064a\u0632", 0xfc93: "\u064a\u0645",
+	0xfc94: "\u064a\u0646", 0xfc95: "\u064a\u0649", 0xfc96: "\u064a\u064a", 0xfc97: "\u064a\u0654\u062c",
+	0xfc98: "\u064a\u0654\u062d", 0xfc99: "\u064a\u0654\u062e", 0xfc9a: "\u064a\u0654\u0645", 0xfc9b: "\u064a\u0654\u0647",
+	0xfc9c: "\u0628\u062c", 0xfc9d: "\u0628\u062d", 0xfc9e: "\u0628\u062e", 0xfc9f: "\u0628\u0645",
+	0xfca0: "\u0628\u0647", 0xfca1: "\u062a\u062c", 0xfca2: "\u062a\u062d", 0xfca3: "\u062a\u062e",
+	0xfca4: "\u062a\u0645", 0xfca5: "\u062a\u0647", 0xfca6: "\u062b\u0645", 0xfca7: "\u062c\u062d",
+	0xfca8: "\u062c\u0645", 0xfca9: "\u062d\u062c", 0xfcaa: "\u062d\u0645", 0xfcab: "\u062e\u062c",
+	0xfcac: "\u062e\u0645", 0xfcad: "\u0633\u062c", 0xfcae: "\u0633\u062d", 0xfcaf: "\u0633\u062e",
+	0xfcb0: "\u0633\u0645", 0xfcb1: "\u0635\u062d", 0xfcb2: "\u0635\u062e", 0xfcb3: "\u0635\u0645",
+	0xfcb4: "\u0636\u062c", 0xfcb5: "\u0636\u062d", 0xfcb6: "\u0636\u062e", 0xfcb7: "\u0636\u0645",
+	0xfcb8: "\u0637\u062d", 0xfcb9: "\u0638\u0645", 0xfcba: "\u0639\u062c", 0xfcbb: "\u0639\u0645",
+	0xfcbc: "\u063a\u062c", 0xfcbd: "\u063a\u0645", 0xfcbe: "\u0641\u062c", 0xfcbf: "\u0641\u062d",
+	0xfcc0: "\u0641\u062e", 0xfcc1: "\u0641\u0645", 0xfcc2: "\u0642\u062d", 0xfcc3: "\u0642\u0645",
+	0xfcc4: "\u0643\u062c", 0xfcc5: "\u0643\u062d", 0xfcc6: "\u0643\u062e", 0xfcc7: "\u0643\u0644",
+	0xfcc8: "\u0643\u0645", 0xfcc9: "\u0644\u062c", 0xfcca: "\u0644\u062d", 0xfccb: "\u0644\u062e",
+	0xfccc: "\u0644\u0645", 0xfccd: "\u0644\u0647", 0xfcce: "\u0645\u062c", 0xfccf: "\u0645\u062d",
+	0xfcd0: "\u0645\u062e", 0xfcd1: "\u0645\u0645", 0xfcd2: "\u0646\u062c", 0xfcd3: "\u0646\u062d",
+	0xfcd4: "\u0646\u062e", 0xfcd5: "\u0646\u0645", 0xfcd6: "\u0646\u0647", 0xfcd7: "\u0647\u062c",
+	0xfcd8: "\u0647\u0645", 0xfcd9: "\u0647\u0670", 0xfcda: "\u064a\u062c", 0xfcdb: "\u064a\u062d",
+	0xfcdc: "\u064a\u062e", 0xfcdd: "\u064a\u0645", 0xfcde: "\u064a\u0647", 0xfcdf: "\u064a\u0654\u0645",
+	0xfce0: "\u064a\u0654\u0647", 0xfce1: "\u0628\u0645", 0xfce2: "\u0628\u0647", 0xfce3: "\u062a\u0645",
+	0xfce4: "\u062a\u0647", 0xfce5: "\u062b\u0645", 0xfce6: "\u062b\u0647", 0xfce7: "\u0633\u0645",
+	0xfce8: "\u0633\u0647", 0xfce9: "\u0634\u0645", 0xfcea: "\u0634\u0647", 0xfceb: "\u0643\u0644",
+	0xfcec: "\u0643\u0645", 0xfced: "\u0644\u0645", 0xfcee: "\u0646\u0645", 0xfcef: "\u0646\u0647",
+	0xfcf0: "\u064a\u0645", 0xfcf1: "\u064a\u0647", 0xfcf2: "\u0640\u064e\u0651", 0xfcf3: "\u0640\u064f\u0651",
+	0xfcf4: "\u0640\u0650\u0651", 0xfcf5: "\u0637\u0649", 0xfcf6: "\u0637\u064a", 0xfcf7: "\u0639\u0649",
+	0xfcf8: "\u0639\u064a", 0xfcf9: "\u063a\u0649", 0xfcfa: "\u063a\u064a", 0xfcfb: "\u0633\u0649",
+	0xfcfc: "\u0633\u064a", 0xfcfd: "\u0634\u0649", 0xfcfe: "\u0634\u064a", 0xfcff: "\u062d\u0649",
+	0xfd00: "\u062d\u064a", 0xfd01: "\u062c\u0649", 0xfd02: "\u062c\u064a", 0xfd03: "\u062e\u0649",
+	0xfd04: "\u062e\u064a", 0xfd05: "\u0635\u0649", 0xfd06: "\u0635\u064a", 0xfd07: "\u0636\u0649",
+	0xfd08: "\u0636\u064a", 0xfd09: "\u0634\u062c", 0xfd0a: "\u0634\u062d", 0xfd0b: "\u0634\u062e",
+	0xfd0c: "\u0634\u0645", 0xfd0d: "\u0634\u0631", 0xfd0e: "\u0633\u0631", 0xfd0f: "\u0635\u0631",
+	0xfd10: "\u0636\u0631", 0xfd11: "\u0637\u0649", 0xfd12: "\u0637\u064a", 0xfd13: "\u0639\u0649",
+	0xfd14: "\u0639\u064a", 0xfd15: "\u063a\u0649", 0xfd16: "\u063a\u064a", 0xfd17: "\u0633\u0649",
+	0xfd18: "\u0633\u064a", 0xfd19: "\u0634\u0649", 0xfd1a: "\u0634\u064a", 0xfd1b: "\u062d\u0649",
+	0xfd1c: "\u062d\u064a", 0xfd1d: "\u062c\u0649", 0xfd1e: "\u062c\u064a", 0xfd1f: "\u062e\u0649",
+	0xfd20: "\u062e\u064a", 0xfd21: "\u0635\u0649", 0xfd22: "\u0635\u064a", 0xfd23: "\u0636\u0649",
+	0xfd24: "\u0636\u064a", 0xfd25: "\u0634\u062c", 0xfd26: "\u0634\u062d", 0xfd27: "\u0634\u062e",
+	0xfd28: "\u0634\u0645", 0xfd29: "\u0634\u0631", 0xfd2a: "\u0633\u0631", 0xfd2b: "\u0635\u0631",
+	0xfd2c: "\u0636\u0631", 0xfd2d: "\u0634\u062c", 0xfd2e: "\u0634\u062d", 0xfd2f: "\u0634\u062e",
+	0xfd30: "\u0634\u0645", 0xfd31: "\u0633\u0647", 0xfd32: "\u0634\u0647", 0xfd33: "\u0637\u0645",
+	0xfd34: "\u0633\u062c", 0xfd35: "\u0633\u062d", 0xfd36: "\u0633\u062e", 0xfd37: "\u0634\u062c",
+	0xfd38: "\u0634\u062d", 0xfd39: "\u0634\u062e", 0xfd3a: "\u0637\u0645", 0xfd3b: "\u0638\u0645",
+	0xfd3c: "\u0627\u064b", 0xfd3d: "\u0627\u064b", 0xfd50: "\u062a\u062c\u0645", 0xfd51: "\u062a\u062d\u062c",
+	0xfd52: "\u062a\u062d\u062c", 0xfd53: "\u062a\u062d\u0645", 0xfd54: "\u062a\u062e\u0645", 0xfd55: "\u062a\u0645\u062c",
+	0xfd56: "\u062a\u0645\u062d", 0xfd57: "\u062a\u0645\u062e", 0xfd58: "\u062c\u0645\u062d", 0xfd59: "\u062c\u0645\u062d",
+	0xfd5a: "\u062d\u0645\u064a", 0xfd5b: "\u062d\u0645\u0649", 0xfd5c: "\u0633\u062d\u062c", 0xfd5d: "\u0633\u062c\u062d",
+	0xfd5e: "\u0633\u062c\u0649", 0xfd5f: "\u0633\u0645\u062d", 0xfd60: "\u0633\u0645\u062d", 0xfd61: "\u0633\u0645\u062c",
+	0xfd62: "\u0633\u0645\u0645", 0xfd63: "\u0633\u0645\u0645", 0xfd64: "\u0635\u062d\u062d", 0xfd65: "\u0635\u062d\u062d",
+	0xfd66: "\u0635\u0645\u0645", 0xfd67: "\u0634\u062d\u0645", 0xfd68: "\u0634\u062d\u0645", 0xfd69: "\u0634\u062c\u064a",
+	0xfd6a: "\u0634\u0645\u062e", 0xfd6b: "\u0634\u0645\u062e", 0xfd6c: "\u0634\u0645\u0645", 0xfd6d: "\u0634\u0645\u0645",
+	0xfd6e: "\u0636\u062d\u0649", 0xfd6f: "\u0636\u062e\u0645", 0xfd70: "\u0636\u062e\u0645", 0xfd71: "\u0637\u0645\u062d",
+	0xfd72: "\u0637\u0645\u062d", 0xfd73: "\u0637\u0645\u0645", 0xfd74: "\u0637\u0645\u064a", 0xfd75: "\u0639\u062c\u0645",
+	0xfd76: "\u0639\u0645\u0645", 0xfd77: "\u0639\u0645\u0645", 0xfd78: "\u0639\u0645\u0649", 0xfd79: "\u063a\u0645\u0645",
+	0xfd7a: "\u063a\u0645\u064a", 0xfd7b: "\u063a\u0645\u0649", 0xfd7c: "\u0641\u062e\u0645", 0xfd7d: "\u0641\u062e\u0645",
+	0xfd7e: "\u0642\u0645\u062d", 0xfd7f: "\u0642\u0645\u0645", 0xfd80: "\u0644\u062d\u0645", 0xfd81: "\u0644\u062d\u064a",
+	0xfd82: "\u0644\u062d\u0649", 0xfd83: "\u0644\u062c\u062c", 0xfd84: "\u0644\u062c\u062c", 0xfd85: "\u0644\u062e\u0645",
+	0xfd86: "\u0644\u062e\u0645", 0xfd87: "\u0644\u0645\u062d", 0xfd88: "\u0644\u0645\u062d", 0xfd89: "\u0645\u062d\u062c",
+	0xfd8a: "\u0645\u062d\u0645", 0xfd8b: "\u0645\u062d\u064a", 0xfd8c: "\u0645\u062c\u062d", 0xfd8d: "\u0645\u062c\u0645",
+	0xfd8e: "\u0645\u062e\u062c", 0xfd8f: "\u0645\u062e\u0645", 0xfd92: "\u0645\u062c\u062e", 0xfd93: "\u0647\u0645\u062c",
+	0xfd94: "\u0647\u0645\u0645", 0xfd95: "\u0646\u062d\u0645", 0xfd96: "\u0646\u062d\u0649", 0xfd97: "\u0646\u062c\u0645",
+	0xfd98: "\u0646\u062c\u0645", 0xfd99: "\u0646\u062c\u0649", 0xfd9a: "\u0646\u0645\u064a", 0xfd9b: "\u0646\u0645\u0649",
+	0xfd9c: "\u064a\u0645\u0645", 0xfd9d: "\u064a\u0645\u0645", 0xfd9e: "\u0628\u062e\u064a", 0xfd9f: "\u062a\u062c\u064a",
+	0xfda0: "\u062a\u062c\u0649", 0xfda1: "\u062a\u062e\u064a", 0xfda2: "\u062a\u062e\u0649", 0xfda3: "\u062a\u0645\u064a",
+	0xfda4: "\u062a\u0645\u0649", 0xfda5: "\u062c\u0645\u064a", 0xfda6: "\u062c\u062d\u0649", 0xfda7: "\u062c\u0645\u0649",
+	0xfda8: "\u0633\u062e\u0649", 0xfda9: "\u0635\u062d\u064a", 0xfdaa: "\u0634\u062d\u064a", 0xfdab: "\u0636\u062d\u064a",
+	0xfdac: "\u0644\u062c\u064a", 0xfdad: "\u0644\u0645\u064a", 0xfdae: "\u064a\u062d\u064a", 0xfdaf: "\u064a\u062c\u064a",
+	0xfdb0: "\u064a\u0645\u064a", 0xfdb1: "\u0645\u0645\u064a", 0xfdb2: "\u0642\u0645\u064a", 0xfdb3: "\u0646\u062d\u064a",
+	0xfdb4: "\u0642\u0645\u062d", 0xfdb5: "\u0644\u062d\u0645", 0xfdb6: "\u0639\u0645\u064a", 0xfdb7: "\u0643\u0645\u064a",
+	0xfdb8: "\u0646\u062c\u062d", 0xfdb9: "\u0645\u062e\u064a", 0xfdba: "\u0644\u062c\u0645", 0xfdbb: "\u0643\u0645\u0645",
+	0xfdbc: "\u0644\u062c\u0645", 0xfdbd: "\u0646\u062c\u062d", 0xfdbe: "\u062c\u062d\u064a", 0xfdbf: "\u062d\u062c\u064a",
+	0xfdc0: "\u0645\u062c\u064a", 0xfdc1: "\u0641\u0645\u064a", 0xfdc2: "\u0628\u062d\u064a", 0xfdc3: "\u0643\u0645\u0645",
+	0xfdc4: "\u0639\u062c\u0645", 0xfdc5: "\u0635\u0645\u0645", 0xfdc6: "\u0633\u062e\u064a", 0xfdc7: "\u0646\u062c\u064a",
+	0xfdf0: "\u0635\u0644\u06d2", 0xfdf1: "\u0642\u0644\u06d2", 0xfdf2: "\u0627\u0644\u0644\u0647", 0xfdf3: "\u0627\u0643\u0628\u0631",
+	0xfdf4: "\u0645\u062d\u0645\u062f", 0xfdf5: "\u0635\u0644\u0639\u0645", 0xfdf6: "\u0631\u0633\u0648\u0644", 0xfdf7: "\u0639\u0644\u064a\u0647",
+	0xfdf8: "\u0648\u0633\u0644\u0645", 0xfdf9: "\u0635\u0644\u0649", 0xfdfa: "\u0635\u0644\u0649 \u0627\u0644\u0644\u0647 \u0639\u0644\u064a\u0647 \u0648\u0633\u0644\u0645", 0xfdfb: "\u062c\u0644 \u062c\u0644\u0627\u0644\u0647",
+	0xfdfc: "\u0631\u06cc\u0627\u0644", 0xfe10: ",", 0xfe11: "\u3001", 0xfe12: "\u3002",
+	0xfe13: ":", 0xfe14: ";", 0xfe15: "!", 0xfe16: "?",
+	0xfe17: "\u3016", 0xfe18: "\u3017", 0xfe19: "...", 0xfe30: "..",
+	0xfe31: "\u2014", 0xfe32: "\u2013", 0xfe33: "_", 0xfe34: "_",
+	0xfe35: "(", 0xfe36: ")", 0xfe37: "{", 0xfe38: "}",
+	0xfe39: "\u3014", 0xfe3a: "\u3015", 0xfe3b: "\u3010", 0xfe3c: "\u3011",
+	0xfe3d: "\u300a", 0xfe3e: "\u300b", 0xfe3f: "\u3008", 0xfe40: "\u3009",
+	0xfe41: "\u300c", 0xfe42: "\u300d", 0xfe43: "\u300e", 0xfe44: "\u300f",
+	0xfe47: "[", 0xfe48: "]", 0xfe49: " \u0305", 0xfe4a: " \u0305",
+	0xfe4b: " \u0305", 0xfe4c: " \u0305", 0xfe4d: "_", 0xfe4e: "_",
+	0xfe4f: "_", 0xfe50: ",", 0xfe51: "\u3001", 0xfe52: ".",
+	0xfe54: ";", 0xfe55: ":", 0xfe56: "?", 0xfe57: "!",
+	0xfe58: "\u2014", 0xfe59: "(", 0xfe5a: ")", 0xfe5b: "{",
+	0xfe5c: "}", 0xfe5d: "\u3014", 0xfe5e: "\u3015", 0xfe5f: "#",
+	0xfe60: "&", 0xfe61: "*", 0xfe62: "+", 0xfe63: "-",
+	0xfe64: "<", 0xfe65: ">", 0xfe66: "=", 0xfe68: "\\",
+	0xfe69: "$", 0xfe6a: "%", 0xfe6b: "@", 0xfe70: " \u064b",
+	0xfe71: "\u0640\u064b", 0xfe72: " \u064c", 0xfe74: " \u064d", 0xfe76: " \u064e",
+	0xfe77: "\u0640\u064e", 0xfe78: " \u064f", 0xfe79: "\u0640\u064f", 0xfe7a: " \u0650",
+	0xfe7b: "\u0640\u0650", 0xfe7c: " \u0651", 0xfe7d: "\u0640\u0651", 0xfe7e: " \u0652",
+	0xfe7f: "\u0640\u0652", 0xfe80: "\u0621", 0xfe81: "\u0627\u0653", 0xfe82: "\u0627\u0653",
+	0xfe83: "\u0627\u0654", 0xfe84: "\u0627\u0654", 0xfe85: "\u0648\u0654", 0xfe86: "\u0648\u0654",
+	0xfe87: "\u0627\u0655", 0xfe88: "\u0627\u0655", 0xfe89: "\u064a\u0654", 0xfe8a: "\u064a\u0654",
+	0xfe8b: "\u064a\u0654", 0xfe8c: "\u064a\u0654", 0xfe8d: "\u0627", 0xfe8e: "\u0627",
+	0xfe8f: "\u0628", 0xfe90: "\u0628", 0xfe91: "\u0628", 0xfe92: "\u0628",
+	0xfe93: "\u0629", 0xfe94: "\u0629", 0xfe95: "\u062a", 0xfe96: "\u062a",
+	0xfe97: "\u062a", 0xfe98: "\u062a", 0xfe99: "\u062b", 0xfe9a: "\u062b",
+	0xfe9b: "\u062b", 0xfe9c: "\u062b", 0xfe9d: "\u062c", 0xfe9e: "\u062c",
+	0xfe9f: "\u062c", 0xfea0: "\u062c", 0xfea1: "\u062d", 0xfea2: "\u062d",
+	0xfea3: "\u062d", 0xfea4: "\u062d", 0xfea5: "\u062e", 0xfea6: "\u062e",
+	0xfea7: "\u062e", 0xfea8: "\u062e", 0xfea9: "\u062f", 0xfeaa: "\u062f",
+	0xfeab: "\u0630", 0xfeac: "\u0630", 0xfead: "\u0631", 0xfeae: "\u0631",
+	0xfeaf: "\u0632", 0xfeb0: "\u0632", 0xfeb1: "\u0633", 0xfeb2: "\u0633",
+	0xfeb3: "\u0633", 0xfeb4: "\u0633", 0xfeb5: "\u0634", 0xfeb6: "\u0634",
+	0xfeb7: "\u0634", 0xfeb8: "\u0634", 0xfeb9: "\u0635", 0xfeba: "\u0635",
+	0xfebb: "\u0635", 0xfebc: "\u0635", 0xfebd: "\u0636", 0xfebe: "\u0636",
+	0xfebf: "\u0636", 0xfec0: "\u0636", 0xfec1: "\u0637", 0xfec2: "\u0637",
+	0xfec3: "\u0637", 0xfec4: "\u0637", 0xfec5: "\u0638", 0xfec6: "\u0638",
+	0xfec7: "\u0638", 0xfec8: "\u0638", 0xfec9: "\u0639", 0xfeca: "\u0639",
+	0xfecb: "\u0639", 0xfecc: "\u0639", 0xfecd: "\u063a", 0xfece: "\u063a",
+	0xfecf: "\u063a", 0xfed0: "\u063a", 0xfed1: "\u0641", 0xfed2: "\u0641",
+	0xfed3: "\u0641", 0xfed4: "\u0641", 0xfed5: "\u0642", 0xfed6: "\u0642",
+	0xfed7: "\u0642", 0xfed8: "\u0642", 0xfed9: "\u0643", 0xfeda: "\u0643",
+	0xfedb: "\u0643", 0xfedc: "\u0643", 0xfedd: "\u0644", 0xfede: "\u0644",
+	0xfedf: "\u0644", 0xfee0: "\u0644", 0xfee1: "\u0645", 0xfee2: "\u0645",
+	0xfee3: "\u0645", 0xfee4: "\u0645", 0xfee5: "\u0646", 0xfee6: "\u0646",
+	0xfee7: "\u0646", 0xfee8: "\u0646", 0xfee9: "\u0647", 0xfeea: "\u0647",
+	0xfeeb: "\u0647", 0xfeec: "\u0647", 0xfeed: "\u0648", 0xfeee: "\u0648",
+	0xfeef: "\u0649", 0xfef0: "\u0649", 0xfef1: "\u064a", 0xfef2: "\u064a",
+	0xfef3: "\u064a", 0xfef4: "\u064a", 0xfef5: "\u0644\u0627\u0653", 0xfef6: "\u0644\u0627\u0653",
+	0xfef7: "\u0644\u0627\u0654", 0xfef8: "\u0644\u0627\u0654", 0xfef9: "\u0644\u0627\u0655", 0xfefa: "\u0644\u0627\u0655",
+	0xfefb: "\u0644\u0627", 0xfefc: "\u0644\u0627", 0xff01: "!", 0xff02: "\"",
+	0xff03: "#", 0xff04: "$", 0xff05: "%", 0xff06: "&",
+	0xff07: "'", 0xff08: "(", 0xff09: ")", 0xff0a: "*",
+	0xff0b: "+", 0xff0c: ",", 0xff0d: "-", 0xff0e: ".",
+	0xff0f: "/", 0xff10: "0", 0xff11: "1", 0xff12: "2",
+	0xff13: "3", 0xff14: "4", 0xff15: "5", 0xff16: "6",
+	0xff17: "7", 0xff18: "8", 0xff19: "9", 0xff1a: ":",
+	0xff1b: ";", 0xff1c: "<", 0xff1d: "=", 0xff1e: ">",
+	0xff1f: "?", 0xff20: "@", 0xff21: "A", 0xff22: "B",
+	0xff23: "C", 0xff24: "D", 0xff25: "E", 0xff26: "F",
+	0xff27: "G", 0xff28: "H", 0xff29: "I", 0xff2a: "J",
+	0xff2b: "K", 0xff2c: "L", 0xff2d: "M", 0xff2e: "N",
+	0xff2f: "O", 0xff30: "P", 0xff31: "Q", 0xff32: "R",
+	0xff33: "S", 0xff34: "T", 0xff35: "U", 0xff36: "V",
+	0xff37: "W", 0xff38: "X", 0xff39: "Y", 0xff3a: "Z",
+	0xff3b: "[", 0xff3c: "\\", 0xff3d: "]", 0xff3e: "^",
+	0xff3f: "_", 0xff40: "`", 0xff41: "a", 0xff42: "b",
+	0xff43: "c", 0xff44: "d", 0xff45: "e", 0xff46: "f",
+	0xff47: "g", 0xff48: "h", 0xff49: "i", 0xff4a: "j",
+	0xff4b: "k", 0xff4c: "l", 0xff4d: "m", 0xff4e: "n",
+	0xff4f: "o", 0xff50: "p", 0xff51: "q", 0xff52: "r",
+	0xff53: "s", 0xff54: "t", 0xff55: "u", 0xff56: "v",
+	0xff57: "w", 0xff58: "x", 0xff59: "y", 0xff5a: "z",
+	0xff5b: "{", 0xff5c: "|", 0xff5d: "}", 0xff5e: "~",
+	0xff5f: "\u2985", 0xff60: "\u2986", 0xff61: "\u3002", 0xff62: "\u300c",
+	0xff63: "\u300d", 0xff64: "\u3001", 0xff65: "\u30fb", 0xff66: "\u30f2",
+	0xff67: "\u30a1", 0xff68: "\u30a3", 0xff69: "\u30a5", 0xff6a: "\u30a7",
+	0xff6b: "\u30a9", 0xff6c: "\u30e3", 0xff6d: "\u30e5", 0xff6e: "\u30e7",
+	0xff6f: "\u30c3", 0xff70: "\u30fc", 0xff71: "\u30a2", 0xff72: "\u30a4",
+	0xff73: "\u30a6", 0xff74: "\u30a8", 0xff75: "\u30aa", 0xff76: "\u30ab",
+	0xff77: "\u30ad", 0xff78: "\u30af", 0xff79: "\u30b1", 0xff7a: "\u30b3",
+	0xff7b: "\u30b5", 0xff7c: "\u30b7", 0xff7d: "\u30b9", 0xff7e: "\u30bb",
+	0xff7f: "\u30bd", 0xff80: "\u30bf", 0xff81: "\u30c1", 0xff82: "\u30c4",
+	0xff83: "\u30c6", 0xff84: "\u30c8", 0xff85: "\u30ca", 0xff86: "\u30cb",
+	0xff87: "\u30cc", 0xff88: "\u30cd", 0xff89: "\u30ce", 0xff8a: "\u30cf",
+	0xff8b: "\u30d2", 0xff8c: "\u30d5", 0xff8d: "\u30d8", 0xff8e: "\u30db",
+	0xff8f: "\u30de", 0xff90: "\u30df", 0xff91: "\u30e0", 0xff92: "\u30e1",
+	0xff93: "\u30e2", 0xff94: "\u30e4", 0xff95: "\u30e6", 0xff96: "\u30e8",
+	0xff97: "\u30e9", 0xff98: "\u30ea", 0xff99: "\u30eb", 0xff9a: "\u30ec",
+	0xff9b: "\u30ed", 0xff9c: "\u30ef", 0xff9d: "\u30f3", 0xff9e: "\u3099",
+	0xff9f: "\u309a", 0xffa0: "\u1160", 0xffa1: "\u1100", 0xffa2: "\u1101",
+	0xffa3: "\u11aa", 0xffa4: "\u1102", 0xffa5: "\u11ac", 0xffa6: "\u11ad",
+	0xffa7: "\u1103", 0xffa8: "\u1104", 0xffa9: "\u1105", 0xffaa: "\u11b0",
+	0xffab: "\u11b1", 0xffac: "\u11b2", 0xffad: "\u11b3", 0xffae: "\u11b4",
+	0xffaf: "\u11b5", 0xffb0: "\u111a", 0xffb1: "\u1106", 0xffb2: "\u1107",
+	0xffb3: "\u1108", 0xffb4: "\u1121", 0xffb5: "\u1109", 0xffb6: "\u110a",
+	0xffb7: "\u110b", 0xffb8: "\u110c", 0xffb9: "\u110d", 0xffba: "\u110e",
+	0xffbb: "\u110f", 0xffbc: "\u1110", 0xffbd: "\u1111", 0xffbe: "\u1112",
+	0xffc2: "\u1161", 0xffc3: "\u1162", 0xffc4: "\u1163", 0xffc5: "\u1164",
+	0xffc6: "\u1165", 0xffc7: "\u1166", 0xffca: "\u1167", 0xffcb: "\u1168",
+	0xffcc: "\u1169", 0xffcd: "\u116a", 0xffce: "\u116b", 0xffcf: "\u116c",
+	0xffd2: "\u116d", 0xffd3: "\u116e", 0xffd4: "\u116f", 0xffd5: "\u1170",
+	0xffd6: "\u1171", 0xffd7: "\u1172", 0xffda: "\u1173", 0xffdb: "\u1174",
+	0xffdc: "\u1175", 0xffe0: "\u00a2", 0xffe1: "\u00a3", 0xffe2: "\u00ac",
+	0xffe3: " \u0304", 0xffe4: "\u00a6", 0xffe5: "\u00a5", 0xffe6: "\u20a9",
+	0xffe8: "\u2502", 0xffe9: "\u2190", 0xffea: "\u2191", 0xffeb: "\u2192",
+	0xffec: "\u2193", 0xffed: "\u25a0", 0xffee: "\u25cb", 0x10781: "\u02d0",
+	0x10782: "\u02d1", 0x10783: "\u00e6", 0x10784: "\u0299", 0x10785: "\u0253",
+	0x10787: "\u02a3", 0x10788: "\uab66", 0x10789: "\u02a5", 0x1078a: "\u02a4",
+	0x1078b: "\u0256", 0x1078c: "\u0257", 0x1078d: "\u1d91", 0x1078e: "\u0258",
+	0x1078f: "\u025e", 0x10790: "\u02a9", 0x10791: "\u0264", 0x10792: "\u0262",
+	0x10793: "\u0260", 0x10794: "\u029b", 0x10795: "\u0127", 0x10796: "\u029c",
+	0x10797: "\u0267", 0x10798: "\u0284", 0x10799: "\u02aa", 0x1079a: "\u02ab",
+	0x1079b: "\u026c", 0x1079c: "\U0001df04", 0x1079d: "\ua78e", 0x1079e: "\u026e",
+	0x1079f: "\U0001df05", 0x107a0: "\u028e", 0x107a1: "\U0001df06", 0x107a2: "\u00f8",
+	0x107a3: "\u0276", 0x107a4: "\u0277", 0x107a5: "q", 0x107a6: "\u027a",
+	0x107a7: "\U0001df08", 0x107a8: "\u027d", 0x107a9: "\u027e", 0x107aa: "\u0280",
+	0x107ab: "\u02a8", 0x107ac: "\u02a6", 0x107ad: "\uab67", 0x107ae: "\u02a7",
+	0x107af: "\u0288", 0x107b0: "\u2c71", 0x107b2: "\u028f", 0x107b3: "\u02a1",
+	0x107b4: "\u02a2", 0x107b5: "\u0298", 0x107b6: "\u01c0", 0x107b7: "\u01c1",
+	0x107b8: "\u01c2", 0x107b9: "\U0001df0a", 0x107ba: "\U0001df1e", 0x1d400: "A",
+	0x1d401: "B", 0x1d402: "C", 0x1d403: "D", 0x1d404: "E",
+	0x1d405: "F", 0x1d406: "G", 0x1d407: "H", 0x1d408: "I",
+	0x1d409: "J", 0x1d40a: "K", 0x1d40b: "L", 0x1d40c: "M",
+	0x1d40d: "N", 0x1d40e: "O", 0x1d40f: "P", 0x1d410: "Q",
+	0x1d411: "R", 0x1d412: "S", 0x1d413: "T", 0x1d414: "U",
+	0x1d415: "V", 0x1d416: "W", 0x1d417: "X", 0x1d418: "Y",
+	0x1d419: "Z", 0x1d41a: "a", 0x1d41b: "b", 0x1d41c: "c",
+	0x1d41d: "d", 0x1d41e: "e", 0x1d41f: "f", 0x1d420: "g",
+	0x1d421: "h", 0x1d422: "i", 0x1d423: "j", 0x1d424: "k",
+	0x1d425: "l", 0x1d426: "m", 0x1d427: "n", 0x1d428: "o",
+	0x1d429: "p", 0x1d42a: "q", 0x1d42b: "r", 0x1d42c: "s",
+	0x1d42d: "t", 0x1d42e: "u", 0x1d42f: "v", 0x1d430: "w",
+	0x1d431: "x", 0x1d432: "y", 0x1d433: "z", 0x1d434: "A",
+	0x1d435: "B", 0x1d436: "C", 0x1d437: "D", 0x1d438: "E",
+	0x1d439: "F", 0x1d43a: "G", 0x1d43b: "H", 0x1d43c: "I",
+	0x1d43d: "J", 0x1d43e: "K", 0x1d43f: "L", 0x1d440: "M",
+	0x1d441: "N", 0x1d442: "O", 0x1d443: "P", 0x1d444: "Q",
+	0x1d445: "R", 0x1d446: "S", 0x1d447: "T", 0x1d448: "U",
+	0x1d449: "V", 0x1d44a: "W", 0x1d44b: "X", 0x1d44c: "Y",
+	0x1d44d: "Z", 0x1d44e: "a", 0x1d44f: "b", 0x1d450: "c",
+	0x1d451: "d", 0x1d452: "e", 0x1d453: "f", 0x1d454: "g",
+	0x1d456: "i", 0x1d457: "j", 0x1d458: "k", 0x1d459: "l",
+	0x1d45a: "m", 0x1d45b: "n", 0x1d45c: "o", 0x1d45d: "p",
+	0x1d45e: "q", 0x1d45f: "r", 0x1d460: "s", 0x1d461: "t",
+	0x1d462: "u", 0x1d463: "v", 0x1d464: "w", 0x1d465: "x",
+	0x1d466: "y", 0x1d467: "z", 0x1d468: "A", 0x1d469: "B",
+	0x1d46a: "C", 0x1d46b: "D", 0x1d46c: "E", 0x1d46d: "F",
+	0x1d46e: "G", 0x1d46f: "H", 0x1d470: "I", 0x1d471: "J",
+	0x1d472: "K", 0x1d473: "L", 0x1d474: "M", 0x1d475: "N",
+	0x1d476: "O", 0x1d477: "P", 0x1d478: "Q", 0x1d479: "R",
+	0x1d47a: "S", 0x1d47b: "T", 0x1d47c: "U", 0x1d47d: "V",
+	0x1d47e: "W", 0x1d47f: "X", 0x1d480: "Y", 0x1d481: "Z",
+	0x1d482: "a", 0x1d483: "b", 0x1d484: "c", 0x1d485: "d",
+	0x1d486: "e", 0x1d487: "f", 0x1d488: "g", 0x1d489: "h",
+	0x1d48a: "i", 0x1d48b: "j", 0x1d48c: "k", 0x1d48d: "l",
+	0x1d48e: "m", 0x1d48f: "n", 0x1d490: "o", 0x1d491: "p",
+	0x1d492: "q", 0x1d493: "r", 0x1d494: "s", 0x1d495: "t",
+	0x1d496: "u", 0x1d497: "v", 0x1d498: "w", 0x1d499: "x",
+	0x1d49a: "y", 0x1d49b: "z", 0x1d49c: "A", 0x1d49e: "C",
+	0x1d49f: "D", 0x1d4a2: "G", 0x1d4a5: "J", 0x1d4a6: "K",
+	0x1d4a9: "N", 0x1d4aa: "O", 0x1d4ab: "P", 0x1d4ac: "Q",
+	0x1d4ae: "S", 0x1d4af: "T", 0x1d4b0: "U", 0x1d4b1: "V",
+	0x1d4b2: "W", 0x1d4b3: "X", 0x1d4b4: "Y", 0x1d4b5: "Z",
+	0x1d4b6: "a", 0x1d4b7: "b", 0x1d4b8: "c", 0x1d4b9: "d",
+	0x1d4bb: "f", 0x1d4bd: "h", 0x1d4be: "i", 0x1d4bf: "j",
+	0x1d4c0: "k", 0x1d4c1: "l", 0x1d4c2: "m", 0x1d4c3: "n",
+	0x1d4c5: "p", 0x1d4c6: "q", 0x1d4c7: "r", 0x1d4c8: "s",
+	0x1d4c9: "t", 0x1d4ca: "u", 0x1d4cb: "v", 0x1d4cc: "w",
+	0x1d4cd: "x", 0x1d4ce: "y", 0x1d4cf: "z", 0x1d4d0: "A",
+	0x1d4d1: "B", 0x1d4d2: "C", 0x1d4d3: "D", 0x1d4d4: "E",
+	0x1d4d5: "F", 0x1d4d6: "G", 0x1d4d7: "H", 0x1d4d8: "I",
+	0x1d4d9: "J", 0x1d4da: "K", 0x1d4db: "L", 0x1d4dc: "M",
+	0x1d4dd: "N", 0x1d4de: "O", 0x1d4df: "P", 0x1d4e0: "Q",
+	0x1d4e1: "R", 0x1d4e2: "S", 0x1d4e3: "T", 0x1d4e4: "U",
+	0x1d4e5: "V", 0x1d4e6: "W", 0x1d4e7: "X", 0x1d4e8: "Y",
+	0x1d4e9: "Z", 0x1d4ea: "a", 0x1d4eb: "b", 0x1d4ec: "c",
+	0x1d4ed: "d", 0x1d4ee: "e", 0x1d4ef: "f", 0x1d4f0: "g",
+	0x1d4f1: "h", 0x1d4f2: "i", 0x1d4f3: "j", 0x1d4f4: "k",
+	0x1d4f5: "l", 0x1d4f6: "m", 0x1d4f7: "n", 0x1d4f8: "o",
+	0x1d4f9: "p", 0x1d4fa: "q", 0x1d4fb: "r", 0x1d4fc: "s",
+	0x1d4fd: "t", 0x1d4fe: "u", 0x1d4ff: "v", 0x1d500: "w",
+	0x1d501: "x", 0x1d502: "y", 0x1d503: "z", 0x1d504: "A",
+	0x1d505: "B", 0x1d507: "D", 0x1d508: "E", 0x1d509: "F",
+	0x1d50a: "G", 0x1d50d: "J", 0x1d50e: "K", 0x1d50f: "L",
+	0x1d510: "M", 0x1d511: "N", 0x1d512: "O", 0x1d513: "P",
+	0x1d514: "Q", 0x1d516: "S", 0x1d517: "T", 0x1d518: "U",
+	0x1d519: "V", 0x1d51a: "W", 0x1d51b: "X", 0x1d51c: "Y",
+	0x1d51e: "a", 0x1d51f: "b", 0x1d520: "c", 0x1d521: "d",
+	0x1d522: "e", 0x1d523: "f", 0x1d524: "g", 0x1d525: "h",
+	0x1d526: "i", 0x1d527: "j", 0x1d528: "k", 0x1d529: "l",
+	0x1d52a: "m", 0x1d52b: "n", 0x1d52c: "o", 0x1d52d: "p",
+	0x1d52e: "q", 0x1d52f: "r", 0x1d530: "s", 0x1d531: "t",
+	0x1d532: "u", 0x1d533: "v", 0x1d534: "w", 0x1d535: "x",
+	0x1d536: "y", 0x1d537: "z", 0x1d538: "A", 0x1d539: "B",
+	0x1d53b: "D", 0x1d53c: "E", 0x1d53d: "F", 0x1d53e: "G",
+	0x1d540: "I", 0x1d541: "J", 0x1d542: "K", 0x1d543: "L",
+	0x1d544: "M", 0x1d546: "O", 0x1d54a: "S", 0x1d54b: "T",
+	0x1d54c: "U", 0x1d54d: "V", 0x1d54e: "W", 0x1d54f: "X",
+	0x1d550: "Y", 0x1d552: "a", 0x1d553: "b", 0x1d554: "c",
+	0x1d555: "d", 0x1d556: "e", 0x1d557: "f", 0x1d558: "g",
+	0x1d559: "h", 0x1d55a: "i", 0x1d55b: "j", 0x1d55c: "k",
+	0x1d55d: "l", 0x1d55e: "m", 0x1d55f: "n", 0x1d560: "o",
+	0x1d561: "p", 0x1d562: "q", 0x1d563: "r", 0x1d564: "s",
+	0x1d565: "t", 0x1d566: "u", 0x1d567: "v", 0x1d568: "w",
+	0x1d569: "x", 0x1d56a: "y", 0x1d56b: "z", 0x1d56c: "A",
+	0x1d56d: "B", 0x1d56e: "C", 0x1d56f: "D", 0x1d570: "E",
+	0x1d571: "F", 0x1d572: "G", 0x1d573: "H", 0x1d574: "I",
+	0x1d575: "J", 0x1d576: "K", 0x1d577: "L", 0x1d578: "M",
+	0x1d579: "N", 0x1d57a: "O", 0x1d57b: "P", 0x1d57c: "Q",
+	0x1d57d: "R", 0x1d57e: "S", 0x1d57f: "T", 0x1d580: "U",
+	0x1d581: "V", 0x1d582: "W", 0x1d583: "X", 0x1d584: "Y",
+	0x1d585: "Z", 0x1d586: "a", 0x1d587: "b", 0x1d588: "c",
+	0x1d589: "d", 0x1d58a: "e", 0x1d58b: "f", 0x1d58c: "g",
+	0x1d58d: "h", 0x1d58e: "i", 0x1d58f: "j", 0x1d590: "k",
+	0x1d591: "l", 0x1d592: "m", 0x1d593: "n", 0x1d594: "o",
+	0x1d595: "p", 0x1d596: "q", 0x1d597: "r", 0x1d598: "s",
+	0x1d599: "t", 0x1d59a: "u", 0x1d59b: "v", 0x1d59c: "w",
+	0x1d59d: "x", 0x1d59e: "y", 0x1d59f: "z", 0x1d5a0: "A",
+	0x1d5a1: "B", 0x1d5a2: "C", 0x1d5a3: "D", 0x1d5a4: "E",
+	0x1d5a5: "F", 0x1d5a6: "G", 0x1d5a7: "H", 0x1d5a8: "I",
+	0x1d5a9: "J", 0x1d5aa: "K", 0x1d5ab: "L", 0x1d5ac: "M",
+	0x1d5ad: "N", 0x1d5ae: "O", 0x1d5af: "P", 0x1d5b0: "Q",
+	0x1d5b1: "R", 0x1d5b2: "S", 0x1d5b3: "T", 0x1d5b4: "U",
+	0x1d5b5: "V", 0x1d5b6: "W", 0x1d5b7: "X", 0x1d5b8: "Y",
+	0x1d5b9: "Z", 0x1d5ba: "a", 0x1d5bb: "b", 0x1d5bc: "c",
+	0x1d5bd: "d", 0x1d5be: "e", 0x1d5bf: "f", 0x1d5c0: "g",
+	0x1d5c1: "h", 0x1d5c2: "i", 0x1d5c3: "j", 0x1d5c4: "k",
+	0x1d5c5: "l", 0x1d5c6: "m", 0x1d5c7: "n", 0x1d5c8: "o",
+	0x1d5c9: "p", 0x1d5ca: "q", 0x1d5cb: "r", 0x1d5cc: "s",
+	0x1d5cd: "t", 0x1d5ce: "u", 0x1d5cf: "v", 0x1d5d0: "w",
+	0x1d5d1: "x", 0x1d5d2: "y", 0x1d5d3: "z", 0x1d5d4: "A",
+	0x1d5d5: "B", 0x1d5d6: "C", 0x1d5d7: "D", 0x1d5d8: "E",
+	0x1d5d9: "F", 0x1d5da: "G", 0x1d5db: "H", 0x1d5dc: "I",
+	0x1d5dd: "J", 0x1d5de: "K", 0x1d5df: "L", 0x1d5e0: "M",
+	0x1d5e1: "N", 0x1d5e2: "O", 0x1d5e3: "P", 0x1d5e4: "Q",
+	0x1d5e5: "R", 0x1d5e6: "S", 0x1d5e7: "T", 0x1d5e8: "U",
+	0x1d5e9: "V", 0x1d5ea: "W", 0x1d5eb: "X", 0x1d5ec: "Y",
+	0x1d5ed: "Z", 0x1d5ee: "a", 0x1d5ef: "b", 0x1d5f0: "c",
+	0x1d5f1: "d", 0x1d5f2: "e", 0x1d5f3: "f", 0x1d5f4: "g",
+	0x1d5f5: "h", 0x1d5f6: "i", 0x1d5f7: "j", 0x1d5f8: "k",
+	0x1d5f9: "l", 0x1d5fa: "m", 0x1d5fb: "n", 0x1d5fc: "o",
+	0x1d5fd: "p", 0x1d5fe: "q", 0x1d5ff: "r", 0x1d600: "s",
+	0x1d601: "t", 0x1d602: "u", 0x1d603: "v", 0x1d604: "w",
+	0x1d605: "x", 0x1d606: "y", 0x1d607: "z", 0x1d608: "A",
+	0x1d609: "B", 0x1d60a: "C", 0x1d60b: "D", 0x1d60c: "E",
+	0x1d60d: "F", 0x1d60e: "G", 0x1d60f: "H", 0x1d610: "I",
+	0x1d611: "J", 0x1d612: "K", 0x1d613: "L", 0x1d614: "M",
+	0x1d615: "N", 0x1d616: "O", 0x1d617: "P", 0x1d618: "Q",
+	0x1d619: "R", 0x1d61a: "S", 0x1d61b: "T", 0x1d61c: "U",
+	0x1d61d: "V", 0x1d61e: "W", 0x1d61f: "X", 0x1d620: "Y",
+	0x1d621: "Z", 0x1d622: "a", 0x1d623: "b", 0x1d624: "c",
+	0x1d625: "d", 0x1d626: "e", 0x1d627: "f", 0x1d628: "g",
+	0x1d629: "h", 0x1d62a: "i", 0x1d62b: "j", 0x1d62c: "k",
+	0x1d62d: "l", 0x1d62e: "m", 0x1d62f: "n", 0x1d630: "o",
+	0x1d631: "p", 0x1d632: "q", 0x1d633: "r", 0x1d634: "s",
+	0x1d635: "t", 0x1d636: "u", 0x1d637: "v", 0x1d638: "w",
+	0x1d639: "x", 0x1d63a: "y", 0x1d63b: "z", 0x1d63c: "A",
+	0x1d63d: "B", 0x1d63e: "C", 0x1d63f: "D", 0x1d640: "E",
+	0x1d641: "F", 0x1d642: "G", 0x1d643: "H", 0x1d644: "I",
+	0x1d645: "J", 0x1d646: "K", 0x1d647: "L", 0x1d648: "M",
+	0x1d649: "N", 0x1d64a: "O", 0x1d64b: "P", 0x1d64c: "Q",
+	0x1d64d: "R", 0x1d64e: "S", 0x1d64f: "T", 0x1d650: "U",
+	0x1d651: "V", 0x1d652: "W", 0x1d653: "X", 0x1d654: "Y",
+	0x1d655: "Z", 0x1d656: "a", 0x1d657: "b", 0x1d658: "c",
+	0x1d659: "d", 0x1d65a: "e", 0x1d65b: "f", 0x1d65c: "g",
+	0x1d65d: "h", 0x1d65e: "i", 0x1d65f: "j", 0x1d660: "k",
+	0x1d661: "l", 0x1d662: "m", 0x1d663: "n", 0x1d664: "o",
+	0x1d665: "p", 0x1d666: "q", 0x1d667: "r", 0x1d668: "s",
+	0x1d669: "t", 0x1d66a: "u", 0x1d66b: "v", 0x1d66c: "w",
+	0x1d66d: "x", 0x1d66e: "y", 0x1d66f: "z", 0x1d670: "A",
+	0x1d671: "B", 0x1d672: "C", 0x1d673: "D", 0x1d674: "E",
+	0x1d675: "F", 0x1d676: "G", 0x1d677: "H", 0x1d678: "I",
+	0x1d679: "J", 0x1d67a: "K", 0x1d67b: "L", 0x1d67c: "M",
+	0x1d67d: "N", 0x1d67e: "O", 0x1d67f: "P", 0x1d680: "Q",
+	0x1d681: "R", 0x1d682: "S", 0x1d683: "T", 0x1d684: "U",
+	0x1d685: "V", 0x1d686: "W", 0x1d687: "X", 0x1d688: "Y",
+	0x1d689: "Z", 0x1d68a: "a", 0x1d68b: "b", 0x1d68c: "c",
+	0x1d68d: "d", 0x1d68e: "e", 0x1d68f: "f", 0x1d690: "g",
+	0x1d691: "h", 0x1d692: "i", 0x1d693: "j", 0x1d694: "k",
+	0x1d695: "l", 0x1d696: "m", 0x1d697: "n", 0x1d698: "o",
+	0x1d699: "p", 0x1d69a: "q", 0x1d69b: "r", 0x1d69c: "s",
+	0x1d69d: "t", 0x1d69e: "u", 0x1d69f: "v", 0x1d6a0: "w",
+	0x1d6a1: "x", 0x1d6a2: "y", 0x1d6a3: "z", 0x1d6a4: "\u0131",
+	0x1d6a5: "\u0237", 0x1d6a8: "\u0391", 0x1d6a9: "\u0392", 0x1d6aa: "\u0393",
+	0x1d6ab: "\u0394", 0x1d6ac: "\u0395", 0x1d6ad: "\u0396", 0x1d6ae: "\u0397",
+	0x1d6af: "\u0398", 0x1d6b0: "\u0399", 0x1d6b1: "\u039a", 0x1d6b2: "\u039b",
+	0x1d6b3: "\u039c", 0x1d6b4: "\u039d", 0x1d6b5: "\u039e", 0x1d6b6: "\u039f",
+	0x1d6b7: "\u03a0", 0x1d6b8: "\u03a1", 0x1d6b9: "\u0398", 0x1d6ba: "\u03a3",
+	0x1d6bb: "\u03a4", 0x1d6bc: "\u03a5", 0x1d6bd: "\u03a6", 0x1d6be: "\u03a7",
+	0x1d6bf: "\u03a8", 0x1d6c0: "\u03a9", 0x1d6c1: "\u2207", 0x1d6c2: "\u03b1",
+	0x1d6c3: "\u03b2", 0x1d6c4: "\u03b3", 0x1d6c5: "\u03b4", 0x1d6c6: "\u03b5",
+	0x1d6c7: "\u03b6", 0x1d6c8: "\u03b7", 0x1d6c9: "\u03b8", 0x1d6ca: "\u03b9",
+	0x1d6cb: "\u03ba", 0x1d6cc: "\u03bb", 0x1d6cd: "\u03bc", 0x1d6ce: "\u03bd",
+	0x1d6cf: "\u03be", 0x1d6d0: "\u03bf", 0x1d6d1: "\u03c0", 0x1d6d2: "\u03c1",
+	0x1d6d3: "\u03c2", 0x1d6d4: "\u03c3", 0x1d6d5: "\u03c4", 0x1d6d6: "\u03c5",
+	0x1d6d7: "\u03c6", 0x1d6d8: "\u03c7", 0x1d6d9: "\u03c8", 0x1d6da: "\u03c9",
+	0x1d6db: "\u2202", 0x1d6dc: "\u03b5", 0x1d6dd: "\u03b8", 0x1d6de: "\u03ba",
+	0x1d6df: "\u03c6", 0x1d6e0: "\u03c1", 0x1d6e1: "\u03c0", 0x1d6e2: "\u0391",
+	0x1d6e3: "\u0392", 0x1d6e4: "\u0393", 0x1d6e5: "\u0394", 0x1d6e6: "\u0395",
+	0x1d6e7: "\u0396", 0x1d6e8: "\u0397", 0x1d6e9: "\u0398", 0x1d6ea: "\u0399",
+	0x1d6eb: "\u039a", 0x1d6ec: "\u039b", 0x1d6ed: "\u039c", 0x1d6ee: "\u039d",
+	0x1d6ef: "\u039e", 0x1d6f0: "\u039f", 0x1d6f1: "\u03a0", 0x1d6f2: "\u03a1",
+	0x1d6f3: "\u0398", 0x1d6f4: "\u03a3", 0x1d6f5: "\u03a4", 0x1d6f6: "\u03a5",
+	0x1d6f7: "\u03a6", 0x1d6f8: "\u03a7", 0x1d6f9: "\u03a8", 0x1d6fa: "\u03a9",
+	0x1d6fb: "\u2207", 0x1d6fc: "\u03b1", 0x1d6fd: "\u03b2", 0x1d6fe: "\u03b3",
+	0x1d6ff: "\u03b4", 0x1d700: "\u03b5", 0x1d701: "\u03b6", 0x1d702: "\u03b7",
+	0x1d703: "\u03b8", 0x1d704: "\u03b9", 0x1d705: "\u03ba", 0x1d706: "\u03bb",
+	0x1d707: "\u03bc", 0x1d708: "\u03bd", 0x1d709: "\u03be", 0x1d70a: "\u03bf",
+	0x1d70b: "\u03c0", 0x1d70c: "\u03c1", 0x1d70d: "\u03c2", 0x1d70e: "\u03c3",
+	0x1d70f: "\u03c4", 0x1d710: "\u03c5", 0x1d711: "\u03c6", 0x1d712: "\u03c7",
+	0x1d713: "\u03c8", 0x1d714: "\u03c9", 0x1d715: "\u2202", 0x1d716: "\u03b5",
+	0x1d717: "\u03b8", 0x1d718: "\u03ba", 0x1d719: "\u03c6", 0x1d71a: "\u03c1",
+	0x1d71b: "\u03c0", 0x1d71c: "\u0391", 0x1d71d: "\u0392", 0x1d71e: "\u0393",
+	0x1d71f: "\u0394", 0x1d720: "\u0395", 0x1d721: "\u0396", 0x1d722: "\u0397",
+	0x1d723: "\u0398", 0x1d724: "\u0399", 0x1d725: "\u039a", 0x1d726: "\u039b",
+	0x1d727: "\u039c", 0x1d728: "\u039d", 0x1d729: "\u039e", 0x1d72a: "\u039f",
+	0x1d72b: "\u03a0", 0x1d72c: "\u03a1", 0x1d72d: "\u0398", 0x1d72e: "\u03a3",
+	0x1d72f: "\u03a4", 0x1d730: "\u03a5", 0x1d731: "\u03a6", 0x1d732: "\u03a7",
+	0x1d733: "\u03a8", 0x1d734: "\u03a9", 0x1d735: "\u2207", 0x1d736: "\u03b1",
+	0x1d737: "\u03b2", 0x1d738: "\u03b3", 0x1d739: "\u03b4", 0x1d73a: "\u03b5",
+	0x1d73b: "\u03b6", 0x1d73c: "\u03b7", 0x1d73d: "\u03b8", 0x1d73e: "\u03b9",
+	0x1d73f: "\u03ba", 0x1d740: "\u03bb", 0x1d741: "\u03bc", 0x1d742: "\u03bd",
+	0x1d743: "\u03be", 0x1d744: "\u03bf", 0x1d745: "\u03c0", 0x1d746: "\u03c1",
+	0x1d747: "\u03c2", 0x1d748: "\u03c3", 0x1d749: "\u03c4", 0x1d74a: "\u03c5",
+	0x1d74b: "\u03c6", 0x1d74c: "\u03c7", 0x1d74d: "\u03c8", 0x1d74e: "\u03c9",
+	0x1d74f: "\u2202", 0x1d750: "\u03b5", 0x1d751: "\u03b8", 0x1d752: "\u03ba",
+	0x1d753: "\u03c6", 0x1d754: "\u03c1", 0x1d755: "\u03c0", 0x1d756: "\u0391",
+	0x1d757: "\u0392", 0x1d758: "\u0393", 0x1d759: "\u0394", 0x1d75a: "\u0395",
+	0x1d75b: "\u0396", 0x1d75c: "\u0397", 0x1d75d: "\u0398", 0x1d75e: "\u0399",
+	0x1d75f: "\u039a", 0x1d760: "\u039b", 0x1d761: "\u039c", 0x1d762: "\u039d",
+	0x1d763: "\u039e", 0x1d764: "\u039f", 0x1d765: "\u03a0", 0x1d766: "\u03a1",
+	0x1d767: "\u0398", 0x1d768: "\u03a3", 0x1d769: "\u03a4", 0x1d76a: "\u03a5",
+	0x1d76b: "\u03a6", 0x1d76c: "\u03a7", 0x1d76d: "\u03a8", 0x1d76e: "\u03a9",
+	0x1d76f: "\u2207", 0x1d770: "\u03b1", 0x1d771: "\u03b2", 0x1d772: "\u03b3",
+	0x1d773: "\u03b4", 0x1d774: "\u03b5", 0x1d775: "\u03b6", 0x1d776: "\u03b7",
+	0x1d777: "\u03b8", 0x1d778: "\u03b9", 0x1d779: "\u03ba", 0x1d77a: "\u03bb",
+	0x1d77b: "\u03bc", 0x1d77c: "\u03bd", 0x1d77d: "\u03be", 0x1d77e: "\u03bf",
+	0x1d77f: "\u03c0", 0x1d780: "\u03c1", 0x1d781: "\u03c2", 0x1d782: "\u03c3",
+	0x1d783: "\u03c4", 0x1d784: "\u03c5", 0x1d785: "\u03c6", 0x1d786: "\u03c7",
+	0x1d787: "\u03c8", 0x1d788: "\u03c9", 0x1d789: "\u2202", 0x1d78a: "\u03b5",
+	0x1d78b: "\u03b8", 0x1d78c: "\u03ba", 0x1d78d: "\u03c6", 0x1d78e: "\u03c1",
+	0x1d78f: "\u03c0", 0x1d790: "\u0391", 0x1d791: "\u0392", 0x1d792: "\u0393",
+	0x1d793: "\u0394", 0x1d794: "\u0395", 0x1d795: "\u0396", 0x1d796: "\u0397",
+	0x1d797: "\u0398", 0x1d798: "\u0399", 0x1d799: "\u039a", 0x1d79a: "\u039b",
+	0x1d79b: "\u039c", 0x1d79c: "\u039d", 0x1d79d: "\u039e", 0x1d79e: "\u039f",
+	0x1d79f: "\u03a0", 0x1d7a0: "\u03a1", 0x1d7a1: "\u0398", 0x1d7a2: "\u03a3",
+	0x1d7a3: "\u03a4", 0x1d7a4: "\u03a5", 0x1d7a5: "\u03a6", 0x1d7a6: "\u03a7",
+	0x1d7a7: "\u03a8", 0x1d7a8: "\u03a9", 0x1d7a9: "\u2207", 0x1d7aa: "\u03b1",
+	0x1d7ab: "\u03b2", 0x1d7ac: "\u03b3", 0x1d7ad: "\u03b4", 0x1d7ae: "\u03b5",
+	0x1d7af: "\u03b6", 0x1d7b0: "\u03b7", 0x1d7b1: "\u03b8", 0x1d7b2: "\u03b9",
+	0x1d7b3: "\u03ba", 0x1d7b4: "\u03bb", 0x1d7b5: "\u03bc", 0x1d7b6: "\u03bd",
+	0x1d7b7: "\u03be", 0x1d7b8: "\u03bf", 0x1d7b9: "\u03c0", 0x1d7ba: "\u03c1",
+	0x1d7bb: "\u03c2", 0x1d7bc: "\u03c3", 0x1d7bd: "\u03c4", 0x1d7be: "\u03c5",
+	0x1d7bf: "\u03c6", 0x1d7c0: "\u03c7", 0x1d7c1: "\u03c8", 0x1d7c2: "\u03c9",
+	0x1d7c3: "\u2202", 0x1d7c4: "\u03b5", 0x1d7c5: "\u03b8", 0x1d7c6: "\u03ba",
+	0x1d7c7: "\u03c6", 0x1d7c8: "\u03c1", 0x1d7c9: "\u03c0", 0x1d7ca: "\u03dc",
+	0x1d7cb: "\u03dd", 0x1d7ce: "0", 0x1d7cf: "1", 0x1d7d0: "2",
+	0x1d7d1: "3", 0x1d7d2: "4", 0x1d7d3: "5", 0x1d7d4: "6",
+	0x1d7d5: "7", 0x1d7d6: "8", 0x1d7d7: "9", 0x1d7d8: "0",
+	0x1d7d9: "1", 0x1d7da: "2", 0x1d7db: "3", 0x1d7dc: "4",
+	0x1d7dd: "5", 0x1d7de: "6", 0x1d7df: "7", 0x1d7e0: "8",
+	0x1d7e1: "9", 0x1d7e2: "0", 0x1d7e3: "1", 0x1d7e4: "2",
+	0x1d7e5: "3", 0x1d7e6: "4", 0x1d7e7: "5", 0x1d7e8: "6",
+	0x1d7e9: "7", 0x1d7ea: "8", 0x1d7eb: "9", 0x1d7ec: "0",
+	0x1d7ed: "1", 0x1d7ee: "2", 0x1d7ef: "3", 0x1d7f0: "4",
+	0x1d7f1: "5", 0x1d7f2: "6", 0x1d7f3: "7", 0x1d7f4: "8",
+	0x1d7f5: "9", 0x1d7f6: "0", 0x1d7f7: "1", 0x1d7f8: "2",
+	0x1d7f9: "3", 0x1d7fa: "4", 0x1d7fb: "5", 0x1d7fc: "6",
+	0x1d7fd: "7", 0x1d7fe: "8", 0x1d7ff: "9", 0x1ee00: "\u0627",
+	0x1ee01: "\u0628", 0x1ee02: "\u062c", 0x1ee03: "\u062f", 0x1ee05: "\u0648",
+	0x1ee06: "\u0632", 0x1ee07: "\u062d", 0x1ee08: "\u0637", 0x1ee09: "\u064a",
+	0x1ee0a: "\u0643", 0x1ee0b: "\u0644", 0x1ee0c: "\u0645", 0x1ee0d: "\u0646",
+	0x1ee0e: "\u0633", 0x1ee0f: "\u0639", 0x1ee10: "\u0641", 0x1ee11: "\u0635",
+	0x1ee12: "\u0642", 0x1ee13: "\u0631", 0x1ee14: "\u0634", 0x1ee15: "\u062a",
+	0x1ee16: "\u062b", 0x1ee17: "\u062e", 0x1ee18: "\u0630", 0x1ee19: "\u0636",
+	0x1ee1a: "\u0638", 0x1ee1b: "\u063a", 0x1ee1c: "\u066e", 0x1ee1d: "\u06ba",
+	0x1ee1e: "\u06a1", 0x1ee1f: "\u066f", 0x1ee21: "\u0628", 0x1ee22: "\u062c",
+	0x1ee24: "\u0647", 0x1ee27: "\u062d", 0x1ee29: "\u064a", 0x1ee2a: "\u0643",
+	0x1ee2b: "\u0644", 0x1ee2c: "\u0645", 0x1ee2d: "\u0646", 0x1ee2e: "\u0633",
+	0x1ee2f: "\u0639", 0x1ee30: "\u0641", 0x1ee31: "\u0635", 0x1ee32: "\u0642",
+	0x1ee34: "\u0634", 0x1ee35: "\u062a", 0x1ee36: "\u062b", 0x1ee37: "\u062e",
+	0x1ee39: "\u0636", 0x1ee3b: "\u063a", 0x1ee42: "\u062c", 0x1ee47: "\u062d",
+	0x1ee49: "\u064a", 0x1ee4b: "\u0644", 0x1ee4d: "\u0646", 0x1ee4e: "\u0633",
+	0x1ee4f: "\u0639", 0x1ee51: "\u0635", 0x1ee52: "\u0642", 0x1ee54: "\u0634",
+	0x1ee57: "\u062e", 0x1ee59: "\u0636", 0x1ee5b: "\u063a", 0x1ee5d: "\u06ba",
+	0x1ee5f: "\u066f", 0x1ee61: "\u0628", 0x1ee62: "\u062c", 0x1ee64: "\u0647",
+	0x1ee67: "\u062d", 0x1ee68: "\u0637", 0x1ee69: "\u064a", 0x1ee6a: "\u0643",
+	0x1ee6c: "\u0645", 0x1ee6d: "\u0646", 0x1ee6e: "\u0633", 0x1ee6f: "\u0639",
+	0x1ee70: "\u0641", 0x1ee71: "\u0635", 0x1ee72: "\u0642", 0x1ee74: "\u0634",
+	0x1ee75: "\u062a", 0x1ee76: "\u062b", 0x1ee77: "\u062e", 0x1ee79: "\u0636",
+	0x1ee7a: "\u0638", 0x1ee7b: "\u063a", 0x1ee7c: "\u066e", 0x1ee7e: "\u06a1",
+	0x1ee80: "\u0627", 0x1ee81: "\u0628", 0x1ee82: "\u062c", 0x1ee83: "\u062f",
+	0x1ee84: "\u0647", 0x1ee85: "\u0648", 0x1ee86: "\u0632", 0x1ee87: "\u062d",
+	0x1ee88: "\u0637", 0x1ee89: "\u064a", 0x1ee8b: "\u0644", 0x1ee8c: "\u0645",
+	0x1ee8d: "\u0646", 0x1ee8e: "\u0633", 0x1ee8f: "\u0639", 0x1ee90: "\u0641",
+	0x1ee91: "\u0635", 0x1ee92: "\u0642", 0x1ee93: "\u0631", 0x1ee94: "\u0634",
+	0x1ee95: "\u062a", 0x1ee96: "\u062b", 0x1ee97: "\u062e", 0x1ee98: "\u0630",
+	0x1ee99: "\u0636", 0x1ee9a: "\u0638", 0x1ee9b: "\u063a", 0x1eea1: "\u0628",
+	0x1eea2: "\u062c", 0x1eea3: "\u062f", 0x1eea5: "\u0648", 0x1eea6: "\u0632",
+	0x1eea7: "\u062d", 0x1eea8: "\u0637", 0x1eea9: "\u064a", 0x1eeab: "\u0644",
+	0x1eeac: "\u0645", 0x1eead: "\u0646", 0x1eeae: "\u0633", 0x1eeaf: "\u0639",
+	0x1eeb0: "\u0641", 0x1eeb1: "\u0635", 0x1eeb2: "\u0642", 0x1eeb3: "\u0631",
+	0x1eeb4: "\u0634", 0x1eeb5: "\u062a", 0x1eeb6: "\u062b", 0x1eeb7: "\u062e",
+	0x1eeb8: "\u0630", 0x1eeb9: "\u0636", 0x1eeba: "\u0638", 0x1eebb: "\u063a",
+	0x1f100: "0.", 0x1f101: "0,", 0x1f102: "1,", 0x1f103: "2,",
+	0x1f104: "3,", 0x1f105: "4,", 0x1f106: "5,", 0x1f107: "6,",
+	0x1f108: "7,", 0x1f109: "8,", 0x1f10a: "9,", 0x1f110: "(A)",
+	0x1f111: "(B)", 0x1f112: "(C)", 0x1f113: "(D)", 0x1f114: "(E)",
+	0x1f115: "(F)", 0x1f116: "(G)", 0x1f117: "(H)", 0x1f118: "(I)",
+	0x1f119: "(J)", 0x1f11a: "(K)", 0x1f11b: "(L)", 0x1f11c: "(M)",
+	0x1f11d: "(N)", 0x1f11e: "(O)", 0x1f11f: "(P)", 0x1f120: "(Q)",
+	0x1f121: "(R)", 0x1f122: "(S)", 0x1f123: "(T)", 0x1f124: "(U)",
+	0x1f125: "(V)", 0x1f126: "(W)", 0x1f127: "(X)", 0x1f128: "(Y)",
+	0x1f129: "(Z)", 0x1f12a: "\u3014S\u3015", 0x1f12b: "C", 0x1f12c: "R",
+	0x1f12d: "CD", 0x1f12e: "WZ", 0x1f130: "A", 0x1f131: "B",
+	0x1f132: "C", 0x1f133: "D", 0x1f134: "E", 0x1f135: "F",
+	0x1f136: "G", 0x1f137: "H", 0x1f138: "I", 0x1f139: "J",
+	0x1f13a: "K", 0x1f13b: "L", 0x1f13c: "M", 0x1f13d: "N",
+	0x1f13e: "O", 0x1f13f: "P", 0x1f140: "Q", 0x1f141: "R",
+	0x1f142: "S", 0x1f143: "T", 0x1f144: "U", 0x1f145: "V",
+	0x1f146: "W", 0x1f147: "X", 0x1f148: "Y", 0x1f149: "Z",
+	0x1f14a: "HV", 0x1f14b: "MV", 0x1f14c: "SD", 0x1f14d: "SS",
+	0x1f14e: "PPV", 0x1f14f: "WC", 0x1f16a: "MC", 0x1f16b: "MD",
+	0x1f16c: "MR", 0x1f190: "DJ", 0x1f200: "\u307b\u304b", 0x1f201: "\u30b3\u30b3",
+	0x1f202: "\u30b5", 0x1f210: "\u624b", 0x1f211: "\u5b57", 0x1f212: "\u53cc",
+	0x1f213: "\u30c6\u3099", 0x1f214: "\u4e8c", 0x1f215: "\u591a", 0x1f216: "\u89e3",
+	0x1f217: "\u5929", 0x1f218: "\u4ea4", 0x1f219: "\u6620", 0x1f21a: "\u7121",
+	0x1f21b: "\u6599", 0x1f21c: "\u524d", 0x1f21d: "\u5f8c", 0x1f21e: "\u518d",
+	0x1f21f: "\u65b0", 0x1f220: "\u521d", 0x1f221: "\u7d42", 0x1f222: "\u751f",
+	0x1f223: "\u8ca9", 0x1f224: "\u58f0", 0x1f225: "\u5439", 0x1f226: "\u6f14",
+	0x1f227: "\u6295", 0x1f228: "\u6355", 0x1f229: "\u4e00", 0x1f22a: "\u4e09",
+	0x1f22b: "\u904a", 0x1f22c: "\u5de6", 0x1f22d: "\u4e2d", 0x1f22e: "\u53f3",
+	0x1f22f: "\u6307", 0x1f230: "\u8d70", 0x1f231: "\u6253", 0x1f232: "\u7981",
+	0x1f233: "\u7a7a", 0x1f234: "\u5408", 0x1f235: "\u6e80", 0x1f236: "\u6709",
+	0x1f237: "\u6708", 0x1f238: "\u7533", 0x1f239: "\u5272", 0x1f23a: "\u55b6",
+	0x1f23b: "\u914d", 0x1f240: "\u3014\u672c\u3015", 0x1f241: "\u3014\u4e09\u3015", 0x1f242: "\u3014\u4e8c\u3015",
+	0x1f243: "\u3014\u5b89\u3015", 0x1f244: "\u3014\u70b9\u3015", 0x1f245: "\u3014\u6253\u3015", 0x1f246: "\u3014\u76d7\u3015",
+	0x1f247: "\u3014\u52dd\u3015", 0x1f248: "\u3014\u6557\u3015", 0x1f250: "\u5f97", 0x1f251: "\u53ef",
+	0x1fbf0: "0", 0x1fbf1: "1", 0x1fbf2: "2", 0x1fbf3: "3",
+	0x1fbf4: "4", 0x1fbf5: "5", 0x1fbf6: "6", 0x1fbf7: "7",
+	0x1fbf8: "8", 0x1fbf9: "9",
+}
+
+// compositions are the primary composites by their canonical decomposition
+var compositions = map[[2]rune]rune{
+	{0x003c, 0x0338}: 0x226e, {0x003d, 0x0338}: 0x2260, {0x003e, 0x0338}: 0x226f, {0x0041, 0x0300}: 0x00c0,
+	{0x0041, 0x0301}: 0x00c1, {0x0041, 0x0302}: 0x00c2, {0x0041, 0x0303}: 0x00c3, {0x0041, 0x0304}: 0x0100,
+	{0x0041, 0x0306}: 0x0102, {0x0041, 0x0307}: 0x0226, {0x0041, 0x0308}: 0x00c4, {0x0041, 0x0309}: 0x1ea2,
+	{0x0041, 0x030a}: 0x00c5, {0x0041, 0x030c}: 0x01cd, {0x0041, 0x030f}: 0x0200, {0x0041, 0x0311}: 0x0202,
+	{0x0041, 0x0323}: 0x1ea0, {0x0041, 0x0325}: 0x1e00, {0x0041, 0x0328}: 0x0104, {0x0042, 0x0307}: 0x1e02,
+	{0x0042, 0x0323}: 0x1e04, {0x0042, 0x0331}: 0x1e06, {0x0043, 0x0301}: 0x0106, {0x0043, 0x0302}: 0x0108,
+	{0x0043, 0x0307}: 0x010a, {0x0043, 0x030c}: 0x010c, {0x0043, 0x0327}: 0x00c7, {0x0044, 0x0307}: 0x1e0a,
+	{0x0044, 0x030c}: 0x010e, {0x0044, 0x0323}: 0x1e0c, {0x0044, 0x0327}: 0x1e10, {0x0044, 0x032d}: 0x1e12,
+	{0x0044, 0x0331}: 0x1e0e, {0x0045, 0x0300}: 0x00c8, {0x0045, 0x0301}: 0x00c9, {0x0045, 0x0302}: 0x00ca,
+	{0x0045, 0x0303}: 0x1ebc, {0x0045, 0x0304}: 0x0112, {0x0045, 0x0306}: 0x0114, {0x0045, 0x0307}: 0x0116,
+	{0x0045, 0x0308}: 0x00cb, {0x0045, 0x0309}: 0x1eba, {0x0045, 0x030c}: 0x011a, {0x0045, 0x030f}: 0x0204,
+	{0x0045, 0x0311}: 0x0206, {0x0045, 0x0323}: 0x1eb8, {0x0045, 0x0327}: 0x0228, {0x0045, 0x0328}: 0x0118,
+	{0x0045, 0x032d}: 0x1e18, {0x0045, 0x0330}: 0x1e1a, {0x0046, 0x0307}: 0x1e1e, {0x0047, 0x0301}: 0x01f4,
+	{0x0047, 0x0302}: 0x011c, {0x0047, 0x0304}: 0x1e20, {0x0047, 0x0306}: 0x011e, {0x0047, 0x0307}: 0x0120,
+	{0x0047, 0x030c}: 0x01e6, {0x0047, 0x0327}: 0x0122, {0x0048, 0x0302}: 0x0124, {0x0048, 0x0307}: 0x1e22,
+	{0x0048, 0x0308}: 0x1e26, {0x0048, 0x030c}: 0x021e, {0x0048, 0x0323}: 0x1e24, {0x0048, 0x0327}: 0x1e28,
+	{0x0048, 0x032e}: 0x1e2a, {0x0049, 0x0300}: 0x00cc, {0x0049, 0x0301}: 0x00cd, {0x0049, 0x0302}: 0x00ce,
+	{0x0049, 0x0303}: 0x0128, {0x0049, 0x0304}: 0x012a, {0x0049, 0x0306}: 0x012c, {0x0049, 0x0307}: 0x0130,
+	{0x0049, 0x0308}: 0x00cf, {0x0049, 0x0309}: 0x1ec8, {0x0049, 0x030c}: 0x01cf, {0x0049, 0x030f}: 0x0208,
+	{0x0049, 0x0311}: 0x020a, {0x0049, 0x0323}: 0x1eca, {0x0049, 0x0328}: 0x012e, {0x0049, 0x0330}: 0x1e2c,
+	{0x004a, 0x0302}: 0x0134, {0x004b, 0x0301}: 0x1e30, {0x004b, 0x030c}: 0x01e8, {0x004b, 0x0323}: 0x1e32,
+	{0x004b, 0x0327}: 0x0136, {0x004b, 0x0331}: 0x1e34, {0x004c, 0x0301}: 0x0139, {0x004c, 0x030c}: 0x013d,
+	{0x004c, 0x0323}: 0x1e36, {0x004c, 0x0327}: 0x013b, {0x004c, 0x032d}: 0x1e3c, {0x004c, 0x0331}: 0x1e3a,
+	{0x004d, 0x0301}: 0x1e3e, {0x004d, 0x0307}: 0x1e40, {0x004d, 0x0323}: 0x1e42, {0x004e, 0x0300}: 0x01f8,
+	{0x004e, 0x0301}: 0x0143, {0x004e, 0x0303}: 0x00d1, {0x004e, 0x0307}: 0x1e44, {0x004e, 0x030c}: 0x0147,
+	{0x004e, 0x0323}: 0x1e46, {0x004e, 0x0327}: 0x0145, {0x004e, 0x032d}: 0x1e4a, {0x004e, 0x0331}: 0x1e48,
+	{0x004f, 0x0300}: 0x00d2, {0x004f, 0x0301}: 0x00d3, {0x004f, 0x0302}: 0x00d4, {0x004f, 0x0303}: 0x00d5,
+	{0x004f, 0x0304}: 0x014c, {0x004f, 0x0306}: 0x014e, {0x004f, 0x0307}: 0x022e, {0x004f, 0x0308}: 0x00d6,
+	{0x004f, 0x0309}: 0x1ece, {0x004f, 0x030b}: 0x0150, {0x004f, 0x030c}: 0x01d1, {0x004f, 0x030f}: 0x020c,
+	{0x004f, 0x0311}: 0x020e, {0x004f, 0x031b}: 0x01a0, {0x004f, 0x0323}: 0x1ecc, {0x004f, 0x0328}: 0x01ea,
+	{0x0050, 0x0301}: 0x1e54, {0x0050, 0x0307}: 0x1e56, {0x0052, 0x0301}: 0x0154, {0x0052, 0x0307}: 0x1e58,
+	{0x0052, 0x030c}: 0x0158, {0x0052, 0x030f}: 0x0210, {0x0052, 0x0311}: 0x0212, {0x0052, 0x0323}: 0x1e5a,
+	{0x0052, 0x0327}: 0x0156, {0x0052, 0x0331}: 0x1e5e, {0x0053, 0x0301}: 0x015a, {0x0053, 0x0302}: 0x015c,
+	{0x0053, 0x0307}: 0x1e60, {0x0053, 0x030c}: 0x0160, {0x0053, 0x0323}: 0x1e62, {0x0053, 0x0326}: 0x0218,
+	{0x0053, 0x0327}: 0x015e, {0x0054, 0x0307}: 0x1e6a, {0x0054, 0x030c}: 0x0164, {0x0054, 0x0323}: 0x1e6c,
+	{0x0054, 0x0326}: 0x021a, {0x0054, 0x0327}: 0x0162, {0x0054, 0x032d}: 0x1e70, {0x0054, 0x0331}: 0x1e6e,
+	{0x0055, 0x0300}: 0x00d9, {0x0055, 0x0301}: 0x00da, {0x0055, 0x0302}: 0x00db, {0x0055, 0x0303}: 0x0168,
+	{0x0055, 0x0304}: 0x016a, {0x0055, 0x0306}: 0x016c, {0x0055, 0x0308}: 0x00dc, {0x0055, 0x0309}: 0x1ee6,
+	{0x0055, 0x030a}: 0x016e, {0x0055, 0x030b}: 0x0170, {0x0055, 0x030c}: 0x01d3, {0x0055, 0x030f}: 0x0214,
+	{0x0055, 0x0311}: 0x0216, {0x0055, 0x031b}: 0x01af, {0x0055, 0x0323}: 0x1ee4, {0x0055, 0x0324}: 0x1e72,
+	{0x0055, 0x0328}: 0x0172, {0x0055, 0x032d}: 0x1e76, {0x0055, 0x0330}: 0x1e74, {0x0056, 0x0303}: 0x1e7c,
+	{0x0056, 0x0323}: 0x1e7e, {0x0057, 0x0300}: 0x1e80, {0x0057, 0x0301}: 0x1e82, {0x0057, 0x0302}: 0x0174,
+	{0x0057, 0x0307}: 0x1e86, {0x0057, 0x0308}: 0x1e84, {0x0057, 0x0323}: 0x1e88, {0x0058, 0x0307}: 0x1e8a,
+	{0x0058, 0x0308}: 0x1e8c, {0x0059, 0x0300}: 0x1ef2, {0x0059, 0x0301}: 0x00dd, {0x0059, 0x0302}: 0x0176,
+	{0x0059, 0x0303}: 0x1ef8, {0x0059, 0x0304}: 0x0232, {0x0059, 0x0307}: 0x1e8e, {0x0059, 0x0308}: 0x0178,
+	{0x0059, 0x0309}: 0x1ef6, {0x0059, 0x0323}: 0x1ef4, {0x005a, 0x0301}: 0x0179, {0x005a, 0x0302}: 0x1e90,
+	{0x005a, 0x0307}: 0x017b, {0x005a, 0x030c}: 0x017d, {0x005a, 0x0323}: 0x1e92, {0x005a, 0x0331}: 0x1e94,
+	{0x0061, 0x0300}: 0x00e0, {0x0061, 0x0301}: 0x00e1, {0x0061, 0x0302}: 0x00e2, {0x0061, 0x0303}: 0x00e3,
+	{0x0061, 0x0304}: 0x0101, {0x0061, 0x0306}: 0x0103, {0x0061, 0x0307}: 0x0227, {0x0061, 0x0308}: 0x00e4,
+	{0x0061, 0x0309}: 0x1ea3, {0x0061, 0x030a}: 0x00e5, {0x0061, 0x030c}: 0x01ce, {0x0061, 0x030f}: 0x0201,
+	{0x0061, 0x0311}: 0x0203, {0x0061, 0x0323}: 0x1ea1, {0x0061, 0x0325}: 0x1e01, {0x0061, 0x0328}: 0x0105,
+	{0x0062, 0x0307}: 0x1e03, {0x0062, 0x0323}: 0x1e05, {0x0062, 0x0331}: 0x1e07, {0x0063, 0x0301}: 0x0107,
+	{0x0063, 0x0302}: 0x0109, {0x0063, 0x0307}: 0x010b, {0x0063, 0x030c}: 0x010d, {0x0063, 0x0327}: 0x00e7,
+	{0x0064, 0x0307}: 0x1e0b, {0x0064, 0x030c}: 0x010f, {0x0064, 0x0323}: 0x1e0d, {0x0064, 0x0327}: 0x1e11,
+	{0x0064, 0x032d}: 0x1e13, {0x0064, 0x0331}: 0x1e0f, {0x0065, 0x0300}: 0x00e8, {0x0065, 0x0301}: 0x00e9,
+	{0x0065, 0x0302}: 0x00ea, {0x0065, 0x0303}: 0x1ebd, {0x0065, 0x0304}: 0x0113, {0x0065, 0x0306}: 0x0115,
+	{0x0065, 0x0307}: 0x0117, {0x0065, 0x0308}: 0x00eb, {0x0065, 0x0309}: 0x1ebb, {0x0065, 0x030c}: 0x011b,
+	{0x0065, 0x030f}: 0x0205, {0x0065, 0x0311}: 0x0207, {0x0065, 0x0323}: 0x1eb9, {0x0065, 0x0327}: 0x0229,
+	{0x0065, 0x0328}: 0x0119, {0x0065, 0x032d}: 0x1e19, {0x0065, 0x0330}: 0x1e1b, {0x0066, 0x0307}: 0x1e1f,
+	{0x0067, 0x0301}: 0x01f5, {0x0067, 0x0302}: 0x011d, {0x0067, 0x0304}: 0x1e21, {0x0067, 0x0306}: 0x011f,
+	{0x0067, 0x0307}: 0x0121, {0x0067, 0x030c}: 0x01e7, {0x0067, 0x0327}: 0x0123, {0x0068, 0x0302}: 0x0125,
+	{0x0068, 0x0307}: 0x1e23, {0x0068, 0x0308}: 0x1e27, {0x0068, 0x030c}: 0x021f, {0x0068, 0x0323}: 0x1e25,
+	{0x0068, 0x0327}: 0x1e29, {0x0068, 0x032e}: 0x1e2b, {0x0068, 0x0331}: 0x1e96, {0x0069, 0x0300}: 0x00ec,
+	{0x0069, 0x0301}: 0x00ed, {0x0069, 0x0302}: 0x00ee, {0x0069, 0x0303}: 0x0129, {0x0069, 0x0304}: 0x012b,
+	{0x0069, 0x0306}: 0x012d, {0x0069, 0x0308}: 0x00ef, {0x0069, 0x0309}: 0x1ec9, {0x0069, 0x030c}: 0x01d0,
+	{0x0069, 0x030f}: 0x0209, {0x0069, 0x0311}: 0x020b, {0x0069, 0x0323}: 0x1ecb, {0x0069, 0x0328}: 0x012f,
+	{0x0069, 0x0330}: 0x1e2d, {0x006a, 0x0302}: 0x0135, {0x006a, 0x030c}: 0x01f0, {0x006b, 0x0301}: 0x1e31,
+	{0x006b, 0x030c}: 0x01e9, {0x006b, 0x0323}: 0x1e33, {0x006b, 0x0327}: 0x0137, {0x006b, 0x0331}: 0x1e35,
+	{0x006c, 0x0301}: 0x013a, {0x006c, 0x030c}: 0x013e, {0x006c, 0x0323}: 0x1e37, {0x006c, 0x0327}: 0x013c,
+	{0x006c, 0x032d}: 0x1e3d, {0x006c, 0x0331}: 0x1e3b, {0x006d, 0x0301}: 0x1e3f, {0x006d, 0x0307}: 0x1e41,
+	{0x006d, 0x0323}: 0x1e43, {0x006e, 0x0300}: 0x01f9, {0x006e, 0x0301}: 0x0144, {0x006e, 0x0303}: 0x00f1,
+	{0x006e, 0x0307}: 0x1e45, {0x006e, 0x030c}: 0x0148, {0x006e, 0x0323}: 0x1e47, {0x006e, 0x0327}: 0x0146,
+	{0x006e, 0x032d}: 0x1e4b, {0x006e, 0x0331}: 0x1e49, {0x006f, 0x0300}: 0x00f2, {0x006f, 0x0301}: 0x00f3,
+	{0x006f, 0x0302}: 0x00f4, {0x006f, 0x0303}: 0x00f5, {0x006f, 0x0304}: 0x014d, {0x006f, 0x0306}: 0x014f,
+	{0x006f, 0x0307}: 0x022f, {0x006f, 0x0308}: 0x00f6, {0x006f, 0x0309}: 0x1ecf, {0x006f, 0x030b}: 0x0151,
+	{0x006f, 0x030c}: 0x01d2, {0x006f, 0x030f}: 0x020d, {0x006f, 0x0311}: 0x020f, {0x006f, 0x031b}: 0x01a1,
+	{0x006f, 0x0323}: 0x1ecd, {0x006f, 0x0328}: 0x01eb, {0x0070, 0x0301}: 0x1e55, {0x0070, 0x0307}: 0x1e57,
+	{0x0072, 0x0301}: 0x0155, {0x0072, 0x0307}: 0x1e59, {0x0072, 0x030c}: 0x0159, {0x0072, 0x030f}: 0x0211,
+	{0x0072, 0x0311}: 0x0213, {0x0072, 0x0323}: 0x1e5b, {0x0072, 0x0327}: 0x0157, {0x0072, 0x0331}: 0x1e5f,
+	{0x0073, 0x0301}: 0x015b, {0x0073, 0x0302}: 0x015d, {0x0073, 0x0307}: 0x1e61, {0x0073, 0x030c}: 0x0161,
+	{0x0073, 0x0323}: 0x1e63, {0x0073, 0x0326}: 0x0219, {0x0073, 0x0327}: 0x015f, {0x0074, 0x0307}: 0x1e6b,
+	{0x0074, 0x0308}: 0x1e97, {0x0074, 0x030c}: 0x0165, {0x0074, 0x0323}: 0x1e6d, {0x0074, 0x0326}: 0x021b,
+	{0x0074, 0x0327}: 0x0163, {0x0074, 0x032d}: 0x1e71, {0x0074, 0x0331}: 0x1e6f, {0x0075, 0x0300}: 0x00f9,
+	{0x0075, 0x0301}: 0x00fa, {0x0075, 0x0302}: 0x00fb, {0x0075, 0x0303}: 0x0169, {0x0075, 0x0304}: 0x016b,
+	{0x0075, 0x0306}: 0x016d, {0x0075, 0x0308}: 0x00fc, {0x0075, 0x0309}: 0x1ee7, {0x0075, 0x030a}: 0x016f,
+	{0x0075, 0x030b}: 0x0171, {0x0075, 0x030c}: 0x01d4, {0x0075, 0x030f}: 0x0215, {0x0075, 0x0311}: 0x0217,
+	{0x0075, 0x031b}: 0x01b0, {0x0075, 0x0323}: 0x1ee5, {0x0075, 0x0324}: 0x1e73, {0x0075, 0x0328}: 0x0173,
+	{0x0075, 0x032d}: 0x1e77, {0x0075, 0x0330}: 0x1e75, {0x0076, 0x0303}: 0x1e7d, {0x0076, 0x0323}: 0x1e7f,
+	{0x0077, 0x0300}: 0x1e81, {0x0077, 0x0301}: 0x1e83, {0x0077, 0x0302}: 0x0175, {0x0077, 0x0307}: 0x1e87,
+	{0x0077, 0x0308}: 0x1e85, {0x0077, 0x030a}: 0x1e98, {0x0077, 0x0323}: 0x1e89, {0x0078, 0x0307}: 0x1e8b,
+	{0x0078, 0x0308}: 0x1e8d, {0x0079, 0x0300}: 0x1ef3, {0x0079, 0x0301}: 0x00fd, {0x0079, 0x0302}: 0x0177,
+	{0x0079, 0x0303}: 0x1ef9, {0x0079, 0x0304}: 0x0233, {0x0079, 0x0307}: 0x1e8f, {0x0079, 0x0308}: 0x00ff,
+	{0x0079, 0x0309}: 0x1ef7, {0x0079, 0x030a}: 0x1e99, {0x0079, 0x0323}: 0x1ef5, {0x007a, 0x0301}: 0x017a,
+	{0x007a, 0x0302}: 0x1e91, {0x007a, 0x0307}: 0x017c, {0x007a, 0x030c}: 0x017e, {0x007a, 0x0323}: 0x1e93,
+	{0x007a, 0x0331}: 0x1e95, {0x00a8, 0x0300}: 0x1fed, {0x00a8, 0x0301}: 0x0385, {0x00a8, 0x0342}: 0x1fc1,
+	{0x00c2, 0x0300}: 0x1ea6, {0x00c2, 0x0301}: 0x1ea4, {0x00c2, 0x0303}: 0x1eaa, {0x00c2, 0x0309}: 0x1ea8,
+	{0x00c4, 0x0304}: 0x01de, {0x00c5, 0x0301}: 0x01fa, {0x00c6, 0x0301}: 0x01fc, {0x00c6, 0x0304}: 0x01e2,
+	{0x00c7, 0x0301}: 0x1e08, {0x00ca, 0x0300}: 0x1ec0, {0x00ca, 0x0301}: 0x1ebe, {0x00ca, 0x0303}: 0x1ec4,
+	{0x00ca, 0x0309}: 0x1ec2, {0x00cf, 0x0301}: 0x1e2e, {0x00d4, 0x0300}: 0x1ed2, {0x00d4, 0x0301}: 0x1ed0,
+	{0x00d4, 0x0303}: 0x1ed6, {0x00d4, 0x0309}: 0x1ed4, {0x00d5, 0x0301}: 0x1e4c, {0x00d5, 0x0304}: 0x022c,
+	{0x00d5, 0x0308}: 0x1e4e, {0x00d6, 0x0304}: 0x022a, {0x00d8, 0x0301}: 0x01fe, {0x00dc, 0x0300}: 0x01db,
+	{0x00dc, 0x0301}: 0x01d7, {0x00dc, 0x0304}: 0x01d5, {0x00dc, 0x030c}: 0x01d9, {0x00e2, 0x0300}: 0x1ea7,
+	{0x00e2, 0x0301}: 0x1ea5, {0x00e2, 0x0303}: 0x1eab, {0x00e2, 0x0309}: 0x1ea9, {0x00e4, 0x0304}: 0x01df,
+	{0x00e5, 0x0301}: 0x01fb, {0x00e6, 0x0301}: 0x01fd, {0x00e6, 0x0304}: 0x01e3, {0x00e7, 0x0301}: 0x1e09,
+	{0x00ea, 0x0300}: 0x1ec1, {0x00ea, 0x0301}: 0x1ebf, {0x00ea, 0x0303}: 0x1ec5, {0x00ea, 0x0309}: 0x1ec3,
+	{0x00ef, 0x0301}: 0x1e2f, {0x00f4, 0x0300}: 0x1ed3, {0x00f4, 0x0301}: 0x1ed1, {0x00f4, 0x0303}: 0x1ed7,
+	{0x00f4, 0x0309}: 0x1ed5, {0x00f5, 0x0301}: 0x1e4d, {0x00f5, 0x0304}: 0x022d, {0x00f5, 0x0308}: 0x1e4f,
+	{0x00f6, 0x0304}: 0x022b, {0x00f8, 0x0301}: 0x01ff, {0x00fc, 0x0300}: 0x01dc, {0x00fc, 0x0301}: 0x01d8,
+	{0x00fc, 0x0304}: 0x01d6, {0x00fc, 0x030c}: 0x01da, {0x0102, 0x0300}: 0x1eb0, {0x0102, 0x0301}: 0x1eae,
+	{0x0102, 0x0303}: 0x1eb4, {0x0102, 0x0309}: 0x1eb2, {0x0103, 0x0300}: 0x1eb1, {0x0103, 0x0301}: 0x1eaf,
+	{0x0103, 0x0303}: 0x1eb5, {0x0103, 0x0309}: 0x1eb3, {0x0112, 0x0300}: 0x1e14, {0x0112, 0x0301}: 0x1e16,
+	{0x0113, 0x0300}: 0x1e15, {0x0113, 0x0301}: 0x1e17, {0x014c, 0x0300}: 0x1e50, {0x014c, 0x0301}: 0x1e52,
+	{0x014d, 0x0300}: 0x1e51, {0x014d, 0x0301}: 0x1e53, {0x015a, 0x0307}: 0x1e64, {0x015b, 0x0307}: 0x1e65,
+	{0x0160, 0x0307}: 0x1e66, {0x0161, 0x0307}: 0x1e67, {0x0168, 0x0301}: 0x1e78, {0x0169, 0x0301}: 0x1e79,
+	{0x016a, 0x0308}: 0x1e7a, {0x016b, 0x0308}: 0x1e7b, {0x017f, 0x0307}: 0x1e9b, {0x01a0, 0x0300}: 0x1edc,
+	{0x01a0, 0x0301}: 0x1eda, {0x01a0, 0x0303}: 0x1ee0, {0x01a0, 0x0309}: 0x1ede, {0x01a0, 0x0323}: 0x1ee2,
+	{0x01a1, 0x0300}: 0x1edd, {0x01a1, 0x0301}: 0x1edb, {0x01a1, 0x0303}: 0x1ee1, {0x01a1, 0x0309}: 0x1edf,
+	{0x01a1, 0x0323}: 0x1ee3, {0x01af, 0x0300}: 0x1eea, {0x01af, 0x0301}: 0x1ee8, {0x01af, 0x0303}: 0x1eee,
+	{0x01af, 0x0309}: 0x1eec, {0x01af, 0x0323}: 0x1ef0, {0x01b0, 0x0300}: 0x1eeb, {0x01b0, 0x0301}: 0x1ee9,
+	{0x01b0, 0x0303}: 0x1eef, {0x01b0, 0x0309}: 0x1eed, {0x01b0, 0x0323}: 0x1ef1, {0x01b7, 0x030c}: 0x01ee,
+	{0x01ea, 0x0304}: 0x01ec, {0x01eb, 0x0304}: 0x01ed, {0x0226, 0x0304}: 0x01e0, {0x0227, 0x0304}: 0x01e1,
+	{0x0228, 0x0306}: 0x1e1c, {0x0229, 0x0306}: 0x1e1d, {0x022e, 0x0304}: 0x0230, {0x022f, 0x0304}: 0x0231,
+	{0x0292, 0x030c}: 0x01ef, {0x0391, 0x0300}: 0x1fba, {0x0391, 0x0301}: 0x0386, {0x0391, 0x0304}: 0x1fb9,
+	{0x0391, 0x0306}: 0x1fb8, {0x0391, 0x0313}: 0x1f08, {0x0391, 0x0314}: 0x1f09, {0x0391, 0x0345}: 0x1fbc,
+	{0x0395, 0x0300}: 0x1fc8, {0x0395, 0x0301}: 0x0388, {0x0395, 0x0313}: 0x1f18, {0x0395, 0x0314}: 0x1f19,
+	{0x0397, 0x0300}: 0x1fca, {0x0397, 0x0301}: 0x0389, {0x0397, 0x0313}: 0x1f28, {0x0397, 0x0314}: 0x1f29,
+	{0x0397, 0x0345}: 0x1fcc, {0x0399, 0x0300}: 0x1fda, {0x0399, 0x0301}: 0x038a, {0x0399, 0x0304}: 0x1fd9,
+	{0x0399, 0x0306}: 0x1fd8, {0x0399, 0x0308}: 0x03aa, {0x0399, 0x0313}: 0x1f38, {0x0399, 0x0314}: 0x1f39,
+	{0x039f, 0x0300}: 0x1ff8, {0x039f, 0x0301}: 0x038c, {0x039f, 0x0313}: 0x1f48, {0x039f, 0x0314}: 0x1f49,
+	{0x03a1, 0x0314}: 0x1fec, {0x03a5, 0x0300}: 0x1fea, {0x03a5, 0x0301}: 0x038e, {0x03a5, 0x0304}: 0x1fe9,
+	{0x03a5, 0x0306}: 0x1fe8, {0x03a5, 0x0308}: 0x03ab, {0x03a5, 0x0314}: 0x1f59, {0x03a9, 0x0300}: 0x1ffa,
+	{0x03a9, 0x0301}: 0x038f, {0x03a9, 0x0313}: 0x1f68, {0x03a9, 0x0314}: 0x1f69, {0x03a9, 0x0345}: 0x1ffc,
+	{0x03ac, 0x0345}: 0x1fb4, {0x03ae, 0x0345}: 0x1fc4, {0x03b1, 0x0300}: 0x1f70, {0x03b1, 0x0301}: 0x03ac,
+	{0x03b1, 0x0304}: 0x1fb1, {0x03b1, 0x0306}: 0x1fb0, {0x03b1, 0x0313}: 0x1f00, {0x03b1, 0x0314}: 0x1f01,
+	{0x03b1, 0x0342}: 0x1fb6, {0x03b1, 0x0345}: 0x1fb3, {0x03b5, 0x0300}: 0x1f72, {0x03b5, 0x0301}: 0x03ad,
+	{0x03b5, 0x0313}: 0x1f10, {0x03b5, 0x0314}: 0x1f11, {0x03b7, 0x0300}: 0x1f74, {0x03b7, 0x0301}: 0x03ae,
+	{0x03b7, 0x0313}: 0x1f20, {0x03b7, 0x0314}: 0x1f21, {0x03b7, 0x0342}: 0x1fc6, {0x03b7, 0x0345}: 0x1fc3,
+	{0x03b9, 0x0300}: 0x1f76, {0x03b9, 0x0301}: 0x03af, {0x03b9, 0x0304}: 0x1fd1, {0x03b9, 0x0306}: 0x1fd0,
+	{0x03b9, 0x0308}: 0x03ca, {0x03b9, 0x0313}: 0x1f30, {0x03b9, 0x0314}: 0x1f31, {0x03b9, 0x0342}: 0x1fd6,
+	{0x03bf, 0x0300}: 0x1f78, {0x03bf, 0x0301}: 0x03cc, {0x03bf, 0x0313}: 0x1f40, {0x03bf, 0x0314}: 0x1f41,
+	{0x03c1, 0x0313}: 0x1fe4, {0x03c1, 0x0314}: 0x1fe5, {0x03c5, 0x0300}: 0x1f7a, {0x03c5, 0x0301}: 0x03cd,
+	{0x03c5, 0x0304}: 0x1fe1, {0x03c5, 0x0306}: 0x1fe0, {0x03c5, 0x0308}: 0x03cb, {0x03c5, 0x0313}: 0x1f50,
+	{0x03c5, 0x0314}: 0x1f51, {0x03c5, 0x0342}: 0x1fe6, {0x03c9, 0x0300}: 0x1f7c, {0x03c9, 0x0301}: 0x03ce,
+	{0x03c9, 0x0313}: 0x1f60, {0x03c9, 0x0314}: 0x1f61, {0x03c9, 0x0342}: 0x1ff6, {0x03c9, 0x0345}: 0x1ff3,
+	{0x03ca, 0x0300}: 0x1fd2, {0x03ca, 0x0301}: 0x0390, {0x03ca, 0x0342}: 0x1fd7, {0x03cb, 0x0300}: 0x1fe2,
+	{0x03cb, 0x0301}: 0x03b0, {0x03cb, 0x0342}: 0x1fe7, {0x03ce, 0x0345}: 0x1ff4, {0x03d2, 0x0301}: 0x03d3,
+	{0x03d2, 0x0308}: 0x03d4, {0x0406, 0x0308}: 0x0407, {0x0410, 0x0306}: 0x04d0, {0x0410, 0x0308}: 0x04d2,
+	{0x0413, 0x0301}: 0x0403, {0x0415, 0x0300}: 0x0400, {0x0415, 0x0306}: 0x04d6, {0x0415, 0x0308}: 0x0401,
+	{0x0416, 0x0306}: 0x04c1, {0x0416, 0x0308}: 0x04dc, {0x0417, 0x0308}: 0x04de, {0x0418, 0x0300}: 0x040d,
+	{0x0418, 0x0304}: 0x04e2, {0x0418, 0x0306}: 0x0419, {0x0418, 0x0308}: 0x04e4, {0x041a, 0x0301}: 0x040c,
+	{0x041e, 0x0308}: 0x04e6, {0x0423, 0x0304}: 0x04ee, {0x0423, 0x0306}: 0x040e, {0x0423, 0x0308}: 0x04f0,
+	{0x0423, 0x030b}: 0x04f2, {0x0427, 0x0308}: 0x04f4, {0x042b, 0x0308}: 0x04f8, {0x042d, 0x0308}: 0x04ec,
+	{0x0430, 0x0306}: 0x04d1, {0x0430, 0x0308}: 0x04d3, {0x0433, 0x0301}: 0x0453, {0x0435, 0x0300}: 0x0450,
+	{0x0435, 0x0306}: 0x04d7, {0x0435, 0x0308}: 0x0451, {0x0436, 0x0306}: 0x04c2, {0x0436, 0x0308}: 0x04dd,
+	{0x0437, 0x0308}: 0x04df, {0x0438, 0x0300}: 0x045d, {0x0438, 0x0304}: 0x04e3, {0x0438, 0x0306}: 0x0439,
+	{0x0438, 0x0308}: 0x04e5, {0x043a, 0x0301}: 0x045c, {0x043e, 0x0308}: 0x04e7, {0x0443, 0x0304}: 0x04ef,
+	{0x0443, 0x0306}: 0x045e, {0x0443, 0x0308}: 0x04f1, {0x0443, 0x030b}: 0x04f3, {0x0447, 0x0308}: 0x04f5,
+	{0x044b, 0x0308}: 0x04f9, {0x044d, 0x0308}: 0x04ed, {0x0456, 0x0308}: 0x0457, {0x0474, 0x030f}: 0x0476,
+	{0x0475, 0x030f}: 0x0477, {0x04d8, 0x0308}: 0x04da, {0x04d9, 0x0308}: 0x04db, {0x04e8, 0x0308}: 0x04ea,
+	{0x04e9, 0x0308}: 0x04eb, {0x0627, 0x0653}: 0x0622, {0x0627, 0x0654}: 0x0623, {0x0627, 0x0655}: 0x0625,
+	{0x0648, 0x0654}: 0x0624, {0x064a, 0x0654}: 0x0626, {0x06c1, 0x0654}: 0x06c2, {0x06d2, 0x0654}: 0x06d3,
+	{0x06d5, 0x0654}: 0x06c0, {0x0928, 0x093c}: 0x0929, {0x0930, 0x093c}: 0x0931, {0x0933, 0x093c}: 0x0934,
+	{0x09c7, 0x09be}: 0x09cb, {0x09c7, 0x09d7}: 0x09cc, {0x0b47, 0x0b3e}: 0x0b4b, {0x0b47, 0x0b56}: 0x0b48,
+	{0x0b47, 0x0b57}: 0x0b4c, {0x0b92, 0x0bd7}: 0x0b94, {0x0bc6, 0x0bbe}: 0x0bca, {0x0bc6, 0x0bd7}: 0x0bcc,
+	{0x0bc7, 0x0bbe}: 0x0bcb, {0x0c46, 0x0c56}: 0x0c48, {0x0cbf, 0x0cd5}: 0x0cc0, {0x0cc6, 0x0cc2}: 0x0cca,
+	{0x0cc6, 0x0cd5}: 0x0cc7, {0x0cc6, 0x0cd6}: 0x0cc8, {0x0cca, 0x0cd5}: 0x0ccb, {0x0d46, 0x0d3e}: 0x0d4a,
+	{0x0d46, 0x0d57}: 0x0d4c, {0x0d47, 0x0d3e}: 0x0d4b, {0x0dd9, 0x0dca}: 0x0dda, {0x0dd9, 0x0dcf}: 0x0ddc,
+	{0x0dd9, 0x0ddf}: 0x0dde, {0x0ddc, 0x0dca}: 0x0ddd, {0x1025, 0x102e}: 0x1026, {0x1b05, 0x1b35}: 0x1b06,
+	{0x1b07, 0x1b35}: 0x1b08, {0x1b09, 0x1b35}: 0x1b0a, {0x1b0b, 0x1b35}: 0x1b0c, {0x1b0d, 0x1b35}: 0x1b0e,
+	{0x1b11, 0x1b35}: 0x1b12, {0x1b3a, 0x1b35}: 0x1b3b, {0x1b3c, 0x1b35}: 0x1b3d, {0x1b3e, 0x1b35}: 0x1b40,
+	{0x1b3f, 0x1b35}: 0x1b41, {0x1b42, 0x1b35}: 0x1b43, {0x1e36, 0x0304}: 0x1e38, {0x1e37, 0x0304}: 0x1e39,
+	{0x1e5a, 0x0304}: 0x1e5c, {0x1e5b, 0x0304}: 0x1e5d, {0x1e62, 0x0307}: 0x1e68, {0x1e63, 0x0307}: 0x1e69,
+	{0x1ea0, 0x0302}: 0x1eac, {0x1ea0, 0x0306}: 0x1eb6, {0x1ea1, 0x0302}: 0x1ead, {0x1ea1, 0x0306}: 0x1eb7,
+	{0x1eb8, 0x0302}: 0x1ec6, {0x1eb9, 0x0302}: 0x1ec7, {0x1ecc, 0x0302}: 0x1ed8, {0x1ecd, 0x0302}: 0x1ed9,
+	{0x1f00, 0x0300}: 0x1f02, {0x1f00, 0x0301}: 0x1f04, {0x1f00, 0x0342}: 0x1f06, {0x1f00, 0x0345}: 0x1f80,
+	{0x1f01, 0x0300}: 0x1f03, {0x1f01, 0x0301}: 0x1f05, {0x1f01, 0x0342}: 0x1f07, {0x1f01, 0x0345}: 0x1f81,
+	{0x1f02, 0x0345}: 0x1f82, {0x1f03, 0x0345}: 0x1f83, {0x1f04, 0x0345}: 0x1f84, {0x1f05, 0x0345}: 0x1f85,
+	{0x1f06, 0x0345}: 0x1f86, {0x1f07, 0x0345}: 0x1f87, {0x1f08, 0x0300}: 0x1f0a, {0x1f08, 0x0301}: 0x1f0c,
+	{0x1f08, 0x0342}: 0x1f0e, {0x1f08, 0x0345}: 0x1f88, {0x1f09, 0x0300}: 0x1f0b, {0x1f09, 0x0301}: 0x1f0d,
+	{0x1f09, 0x0342}: 0x1f0f, {0x1f09, 0x0345}: 0x1f89, {0x1f0a, 0x0345}: 0x1f8a, {0x1f0b, 0x0345}: 0x1f8b,
+	{0x1f0c, 0x0345}: 0x1f8c, {0x1f0d, 0x0345}: 0x1f8d, {0x1f0e, 0x0345}: 0x1f8e, {0x1f0f, 0x0345}: 0x1f8f,
+	{0x1f10, 0x0300}: 0x1f12, {0x1f10, 0x0301}: 0x1f14, {0x1f11, 0x0300}: 0x1f13, {0x1f11, 0x0301}: 0x1f15,
+	{0x1f18, 0x0300}: 0x1f1a, {0x1f18, 0x0301}: 0x1f1c, {0x1f19, 0x0300}: 0x1f1b, {0x1f19, 0x0301}: 0x1f1d,
+	{0x1f20, 0x0300}: 0x1f22, {0x1f20, 0x0301}: 0x1f24, {0x1f20, 0x0342}: 0x1f26, {0x1f20, 0x0345}: 0x1f90,
+	{0x1f21, 0x0300}: 0x1f23, {0x1f21, 0x0301}: 0x1f25, {0x1f21, 0x0342}: 0x1f27, {0x1f21, 0x0345}: 0x1f91,
+	{0x1f22, 0x0345}: 0x1f92, {0x1f23, 0x0345}: 0x1f93, {0x1f24, 0x0345}: 0x1f94, {0x1f25, 0x0345}: 0x1f95,
+	{0x1f26, 0x0345}: 0x1f96, {0x1f27, 0x0345}: 0x1f97, {0x1f28, 0x0300}: 0x1f2a, {0x1f28, 0x0301}: 0x1f2c,
+	{0x1f28, 0x0342}: 0x1f2e, {0x1f28, 0x0345}: 0x1f98, {0x1f29, 0x0300}: 0x1f2b, {0x1f29, 0x0301}: 0x1f2d,
+	{0x1f29, 0x0342}: 0x1f2f, {0x1f29, 0x0345}: 0x1f99, {0x1f2a, 0x0345}: 0x1f9a, {0x1f2b, 0x0345}: 0x1f9b,
+	{0x1f2c, 0x0345}: 0x1f9c, {0x1f2d, 0x0345}: 0x1f9d, {0x1f2e, 0x0345}: 0x1f9e, {0x1f2f, 0x0345}: 0x1f9f,
+	{0x1f30, 0x0300}: 0x1f32, {0x1f30, 0x0301}: 0x1f34, {0x1f30, 0x0342}: 0x1f36, {0x1f31, 0x0300}: 0x1f33,
+	{0x1f31, 0x0301}: 0x1f35, {0x1f31, 0x0342}: 0x1f37, {0x1f38, 0x0300}: 0x1f3a, {0x1f38, 0x0301}: 0x1f3c,
+	{0x1f38, 0x0342}: 0x1f3e, {0x1f39, 0x0300}: 0x1f3b, {0x1f39, 0x0301}: 0x1f3d, {0x1f39, 0x0342}: 0x1f3f,
+	{0x1f40, 0x0300}: 0x1f42, {0x1f40, 0x0301}: 0x1f44, {0x1f41, 0x0300}: 0x1f43, {0x1f41, 0x0301}: 0x1f45,
+	{0x1f48, 0x0300}: 0x1f4a, {0x1f48, 0x0301}: 0x1f4c, {0x1f49, 0x0300}: 0x1f4b, {0x1f49, 0x0301}: 0x1f4d,
+	{0x1f50, 0x0300}: 0x1f52, {0x1f50, 0x0301}: 0x1f54, {0x1f50, 0x0342}: 0x1f56, {0x1f51, 0x0300}: 0x1f53,
+	{0x1f51, 0x0301}: 0x1f55, {0x1f51, 0x0342}: 0x1f57, {0x1f59, 0x0300}: 0x1f5b, {0x1f59, 0x0301}: 0x1f5d,
+	{0x1f59, 0x0342}: 0x1f5f, {0x1f60, 0x0300}: 0x1f62, {0x1f60, 0x0301}: 0x1f64, {0x1f60, 0x0342}: 0x1f66,
+	{0x1f60, 0x0345}: 0x1fa0, {0x1f61, 0x0300}: 0x1f63, {0x1f61, 0x0301}: 0x1f65, {0x1f61, 0x0342}: 0x1f67,
+	{0x1f61, 0x0345}: 0x1fa1, {0x1f62, 0x0345}: 0x1fa2, {0x1f63, 0x0345}: 0x1fa3, {0x1f64, 0x0345}: 0x1fa4,
+	{0x1f65, 0x0345}: 0x1fa5, {0x1f66, 0x0345}: 0x1fa6, {0x1f67, 0x0345}: 0x1fa7, {0x1f68, 0x0300}: 0x1f6a,
+	{0x1f68, 0x0301}: 0x1f6c, {0x1f68, 0x0342}: 0x1f6e, {0x1f68, 0x0345}: 0x1fa8, {0x1f69, 0x0300}: 0x1f6b,
+	{0x1f69, 0x0301}: 0x1f6d, {0x1f69, 0x0342}: 0x1f6f, {0x1f69, 0x0345}: 0x1fa9, {0x1f6a, 0x0345}: 0x1faa,
+	{0x1f6b, 0x0345}: 0x1fab, {0x1f6c, 0x0345}: 0x1fac, {0x1f6d, 0x0345}: 0x1fad, {0x1f6e, 0x0345}: 0x1fae,
+	{0x1f6f, 0x0345}: 0x1faf, {0x1f70, 0x0345}: 0x1fb2, {0x1f74, 0x0345}: 0x1fc2, {0x1f7c, 0x0345}: 0x1ff2,
+	{0x1fb6, 0x0345}: 0x1fb7, {0x1fbf, 0x0300}: 0x1fcd, {0x1fbf, 0x0301}: 0x1fce, {0x1fbf, 0x0342}: 0x1fcf,
+	{0x1fc6, 0x0345}: 0x1fc7, {0x1ff6, 0x0345}: 0x1ff7, {0x1ffe, 0x0300}: 0x1fdd, {0x1ffe, 0x0301}: 0x1fde,
+	{0x1ffe, 0x0342}: 0x1fdf, {0x2190, 0x0338}: 0x219a, {0x2192, 0x0338}: 0x219b, {0x2194, 0x0338}: 0x21ae,
+	{0x21d0, 0x0338}: 0x21cd, {0x21d2, 0x0338}: 0x21cf, {0x21d4, 0x0338}: 0x21ce, {0x2203, 0x0338}: 0x2204,
+	{0x2208, 0x0338}: 0x2209, {0x220b, 0x0338}: 0x220c, {0x2223, 0x0338}: 0x2224, {0x2225, 0x0338}: 0x2226,
+	{0x223c, 0x0338}: 0x2241, {0x2243, 0x0338}: 0x2244, {0x2245, 0x0338}: 0x2247, {0x2248, 0x0338}: 0x2249,
+	{0x224d, 0x0338}: 0x226d, {0x2261, 0x0338}: 0x2262, {0x2264, 0x0338}: 0x2270, {0x2265, 0x0338}: 0x2271,
+	{0x2272, 0x0338}: 0x2274, {0x2273, 0x0338}: 0x2275, {0x2276, 0x0338}: 0x2278, {0x2277, 0x0338}: 0x2279,
+	{0x227a, 0x0338}: 0x2280, {0x227b, 0x0338}: 0x2281, {0x227c, 0x0338}: 0x22e0, {0x227d, 0x0338}: 0x22e1,
+	{0x2282, 0x0338}: 0x2284, {0x2283, 0x0338}: 0x2285, {0x2286, 0x0338}: 0x2288, {0x2287, 0x0338}: 0x2289,
+	{0x2291, 0x0338}: 0x22e2, {0x2292, 0x0338}: 0x22e3, {0x22a2, 0x0338}: 0x22ac, {0x22a8, 0x0338}: 0x22ad,
+	{0x22a9, 0x0338}: 0x22ae, {0x22ab, 0x0338}: 0x22af, {0x22b2, 0x0338}: 0x22ea, {0x22b3, 0x0338}: 0x22eb,
+	{0x22b4, 0x0338}: 0x22ec, {0x22b5, 0x0338}: 0x22ed, {0x3046, 0x3099}: 0x3094, {0x304b, 0x3099}: 0x304c,
+	{0x304d, 0x3099}: 0x304e, {0x304f, 0x3099}: 0x3050, {0x3051, 0x3099}: 0x3052, {0x3053, 0x3099}: 0x3054,
+	{0x3055, 0x3099}: 0x3056, {0x3057, 0x3099}: 0x3058, {0x3059, 0x3099}: 0x305a, {0x305b, 0x3099}: 0x305c,
+	{0x305d, 0x3099}: 0x305e, {0x305f, 0x3099}: 0x3060, {0x3061, 0x3099}: 0x3062, {0x3064, 0x3099}: 0x3065,
+	{0x3066, 0x3099}: 0x3067, {0x3068, 0x3099}: 0x3069, {0x306f, 0x3099}: 0x3070, {0x306f, 0x309a}: 0x3071,
+	{0x3072, 0x3099}: 0x3073, {0x3072, 0x309a}: 0x3074, {0x3075, 0x3099}: 0x3076, {0x3075, 0x309a}: 0x3077,
+	{0x3078, 0x3099}: 0x3079, {0x3078, 0x309a}: 0x307a, {0x307b, 0x3099}: 0x307c, {0x307b, 0x309a}: 0x307d,
+	{0x309d, 0x3099}: 0x309e, {0x30a6, 0x3099}: 0x30f4, {0x30ab, 0x3099}: 0x30ac, {0x30ad, 0x3099}: 0x30ae,
+	{0x30af, 0x3099}: 0x30b0, {0x30b1, 0x3099}: 0x30b2, {0x30b3, 0x3099}: 0x30b4, {0x30b5, 0x3099}: 0x30b6,
+	{0x30b7, 0x3099}: 0x30b8, {0x30b9, 0x3099}: 0x30ba, {0x30bb, 0x3099}: 0x30bc, {0x30bd, 0x3099}: 0x30be,
+	{0x30bf, 0x3099}: 0x30c0, {0x30c1, 0x3099}: 0x30c2, {0x30c4, 0x3099}: 0x30c5, {0x30c6, 0x3099}: 0x30c7,
+	{0x30c8, 0x3099}: 0x30c9, {0x30cf, 0x3099}: 0x30d0, {0x30cf, 0x309a}: 0x30d1, {0x30d2, 0x3099}: 0x30d3,
+	{0x30d2, 0x309a}: 0x30d4, {0x30d5, 0x3099}: 0x30d6, {0x30d5, 0x309a}: 0x30d7, {0x30d8, 0x3099}: 0x30d9,
+	{0x30d8, 0x309a}: 0x30da, {0x30db, 0x3099}: 0x30dc, {0x30db, 0x309a}: 0x30dd, {0x30ef, 0x3099}: 0x30f7,
+	{0x30f0, 0x3099}: 0x30f8, {0x30f1, 0x3099}: 0x30f9, {0x30f2, 0x3099}: 0x30fa, {0x30fd, 0x3099}: 0x30fe,
+	{0x11099, 0x110ba}: 0x1109a, {0x1109b, 0x110ba}: 0x1109c, {0x110a5, 0x110ba}: 0x110ab, {0x11131, 0x11127}: 0x1112e,
+	{0x11132, 0x11127}: 0x1112f, {0x11347, 0x1133e}: 0x1134b, {0x11347, 0x11357}: 0x1134c, {0x114b9, 0x114b0}: 0x114bc,
+	{0x114b9, 0x114ba}: 0x114bb, {0x114b9, 0x114bd}: 0x114be, {0x115b8, 0x115af}: 0x115ba, {0x115b9, 0x115af}: 0x115bb,
+	{0x11935, 0x11930}: 0x11938,
+}
@@ -0,0 +1,117 @@
+package pdftotext
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNormalizationForm_Transform(t *testing.T) {
+	tests := []struct {
+		name     string
+		form     NormalizationForm
+		input    string
+		expected string
+	}{
+		{
+			name:     "ascii",
+			form:     NFKC,
+			input:    "plain text\n",
+			expected: "plain text\n",
+		},
+		{
+			name:     "decomposed accents",
+			form:     NFC,
+			input:    "café Zürich",
+			expected: "café Zürich",
+		},
+		{
+			name:     "combining marks reordered",
+			form:     NFC,
+			input:    "ạ́ q̣̇",
+			expected: "ạ́ q̣̇",
+		},
+		{
+			name:     "ligatures kept by NFC",
+			form:     NFC,
+			input:    "ﬁle ﬂoor",
+			expected: "ﬁle ﬂoor",
+		},
+		{
+			name:     "ligatures",
+			form:     NFKC,
+			input:    "ﬁle ﬂoor eﬀort",
+			expected: "file floor effort",
+		},
+		{
+			name:     "compatibility characters",
+			form:     NFKC,
+			input:    "x² ＡＢＣ ① 1 000",
+			expected: "x2 ABC 1 1 000",
+		},
+		{
+			name:     "singletons",
+			form:     NFC,
+			input:    "Å Ω",
+			expected: "Å Ω",
+		},
+		{
+			name:     "hangul",
+			form:     NFC,
+			input:    "한글",
+			expected: "한글",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.form.Transform(1, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %+q, got %+q", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := NormalizationForm("NFX").Transform(1, "text"); err == nil {
+		t.Error("expected error for unknown normalization form")
+	}
+}
+
+func TestConverter_Normalize(t *testing.T) {
+	converter, err := New(WithRunner(fakeRun("ﬁrst café\f", "", 0)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	var seen string
+	record := TransformerFunc(func(page int, text string) (string, error) {
+		seen = text
+		return strings.ToUpper(text), nil
+	})
+	text, err := converter.Convert(ctx, "input.pdf", &Options{Normalize: NFKC, Transformers: []Transformer{record}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen != "first café" {
+		t.Errorf("expected transformers to see normalized text, got %+q", seen)
+	}
+	if text != "FIRST CAFÉ" {
+		t.Errorf("expected %+q, got %+q", "FIRST CAFÉ", text)
+	}
+
+	pages, err := converter.ConvertPages(ctx, "input.pdf", &Options{Normalize: NFC})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pages) != 1 || pages[0].Text != "ﬁrst café" {
+		t.Errorf("unexpected pages %+v", pages)
+	}
+
+	if _, err := converter.Command("input.pdf", "-", &Options{Normalize: NFC}); err == nil {
+		t.Error("expected Command to reject Normalize")
+	}
+}
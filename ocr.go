@@ -0,0 +1,72 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// OCR recognizes the text of scanned pages, which have no text layer for
+// pdftotext to extract
+type OCR interface {
+	// RecognizePage returns the text of a page of a PDF file, numbered from
+	// 1
+	RecognizePage(ctx context.Context, inputPath string, page int) (string, error)
+}
+
+// OCRFunc adapts a function to an OCR
+type OCRFunc func(ctx context.Context, inputPath string, page int) (string, error)
+
+// RecognizePage calls f(ctx, inputPath, page)
+func (f OCRFunc) RecognizePage(ctx context.Context, inputPath string, page int) (string, error) {
+	return f(ctx, inputPath, page)
+}
+
+// PageSource is where the text of a page came from
+type PageSource string
+
+const (
+	// SourceTextLayer is text extracted from the text layer by pdftotext
+	SourceTextLayer PageSource = "text"
+	// SourceOCR is text recognized by Options.OCR on a page without text
+	SourceOCR PageSource = "ocr"
+)
+
+// recoversPages reports whether opts OCRs the pages without text or
+// re-extracts the garbled ones
+func recoversPages(opts *Options) bool {
+	return opts != nil && (opts.OCR != nil || opts.MinQuality > 0 && len(garbledStrategies(opts)) > 0)
+}
+
+// recoverPage sets the source of page and replaces its text with the text
+// recognized by opts.OCR if it has none, or with the text of the
+// opts.GarbledStrategies if it is garbled
+func recoverPage(ctx context.Context, opts *Options, inputPath string, page *Page) error {
+	page.Source = SourceTextLayer
+	if strings.TrimSpace(page.Text) != "" {
+		return reextractGarbled(ctx, opts, inputPath, page)
+	}
+	if opts.OCR == nil {
+		return nil
+	}
+	text, err := opts.OCR.RecognizePage(ctx, inputPath, page.Number)
+	if err != nil {
+		return fmt.Errorf("OCR of page %d: %w", page.Number, err)
+	}
+	page.Text = text
+	page.Source = SourceOCR
+	return nil
+}
+
+// recoverPages calls recoverPage for each page if opts recovers pages
+func recoverPages(ctx context.Context, opts *Options, inputPath string, pages []Page) error {
+	if !recoversPages(opts) {
+		return nil
+	}
+	for i := range pages {
+		if err := recoverPage(ctx, opts, inputPath, &pages[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConverter_OCR(t *testing.T) {
+	// page 2 is a scanned page without a text layer
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		output := "first page\f\n\fthird page\f"
+		if args[len(args)-1] != "-" {
+			return os.WriteFile(args[len(args)-1], []byte(output), 0o644)
+		}
+		_, err := io.WriteString(stdout, output)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	var recognized []int
+	ocr := OCRFunc(func(ctx context.Context, inputPath string, page int) (string, error) {
+		recognized = append(recognized, page)
+		return fmt.Sprintf("scanned page %d", page), nil
+	})
+	opts := &Options{OCR: ocr}
+
+	t.Run("ConvertPages", func(t *testing.T) {
+		recognized = nil
+		pages, err := converter.ConvertPages(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []Page{
+			{Number: 1, Text: "first page", Source: SourceTextLayer},
+			{Number: 2, Text: "scanned page 2", Source: SourceOCR},
+			{Number: 3, Text: "third page", Source: SourceTextLayer},
+		}
+		if fmt.Sprint(pages) != fmt.Sprint(expected) {
+			t.Errorf("expected %v, got %v", expected, pages)
+		}
+		if fmt.Sprint(recognized) != "[2]" {
+			t.Errorf("expected only page 2 to be recognized, got %v", recognized)
+		}
+	})
+
+	t.Run("StreamPages", func(t *testing.T) {
+		var sources []string
+		for page, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sources = append(sources, string(page.Source))
+		}
+		if strings.Join(sources, ",") != "text,ocr,text" {
+			t.Errorf("unexpected sources %v", sources)
+		}
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		text, err := converter.Convert(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != "first page\fscanned page 2\fthird page" {
+			t.Errorf("unexpected text %q", text)
+		}
+	})
+
+	t.Run("PageSeparator", func(t *testing.T) {
+		text, err := converter.Convert(ctx, "input.pdf", &Options{OCR: ocr, PageSeparator: "\n-- {n} --\n"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != "first page\n-- 2 --\nscanned page 2\n-- 3 --\nthird page" {
+			t.Errorf("unexpected text %q", text)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		failing := OCRFunc(func(ctx context.Context, inputPath string, page int) (string, error) {
+			return "", context.DeadlineExceeded
+		})
+		_, err := converter.ConvertPages(ctx, "input.pdf", &Options{OCR: failing})
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "page 2") {
+			t.Errorf("expected the OCR error of page 2, got %v", err)
+		}
+	})
+
+	t.Run("Command", func(t *testing.T) {
+		if _, err := converter.Command("input.pdf", "-", opts); !errors.Is(err, ErrCommandFailed) {
+			t.Errorf("expected ErrCommandFailed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// OutputFS is a writable file system output files are written to, so they
+// can go to an in-memory file system, a test fake or a cloud storage adapter
+// instead of the local disk. Names are the output paths passed to
+// ConvertToFile and its variants. Implementations must be safe for
+// concurrent use.
+type OutputFS interface {
+	// Create creates or truncates the named file. The data is complete once
+	// the returned writer is closed.
+	Create(ctx context.Context, name string) (io.WriteCloser, error)
+	// Open opens the named file for reading
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// Remove removes the named file
+	Remove(ctx context.Context, name string) error
+}
+
+// WithOutputFS sets the file system ConvertToFile and its variants write to
+// instead of the local one. pdftotext then writes to a pipe, which is
+// copied to the file as it is produced.
+func WithOutputFS(fsys OutputFS) ConverterOption {
+	return func(c *Converter) {
+		c.outputFS = fsys
+	}
+}
+
+// OSFS is the OutputFS of the local file system
+type OSFS struct{}
+
+// Create creates or truncates the named file
+func (OSFS) Create(_ context.Context, name string) (io.WriteCloser, error) {
+	return os.Create(name)
+}
+
+// Open opens the named file
+func (OSFS) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+// Remove removes the named file
+func (OSFS) Remove(_ context.Context, name string) error {
+	return os.Remove(name)
+}
+
+// StorageFS returns an OutputFS writing each file to s under its name, which
+// must be a valid storage key. Files are buffered in memory and stored when
+// they are closed. Using a MemoryStorage gives an in-memory file system.
+func StorageFS(s Storage) OutputFS {
+	return storageFS{s}
+}
+
+type storageFS struct {
+	storage Storage
+}
+
+func (f storageFS) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	if err := validateKey(name); err != nil {
+		return nil, err
+	}
+	return &storageFile{ctx: ctx, fs: f, name: name}, nil
+}
+
+func (f storageFS) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	data, err := f.storage.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f storageFS) Remove(ctx context.Context, name string) error {
+	return f.storage.Delete(ctx, name)
+}
+
+// storageFile is a file of a storageFS being written
+type storageFile struct {
+	bytes.Buffer
+	ctx  context.Context
+	fs   storageFS
+	name string
+}
+
+// Close stores the written data
+func (f *storageFile) Close() error {
+	return f.fs.storage.Put(f.ctx, f.name, f.Bytes())
+}
+
+// output returns the file system output files are written to
+func (c *Converter) output() OutputFS {
+	if c.outputFS == nil {
+		return OSFS{}
+	}
+	return c.outputFS
+}
+
+// outputName returns the name of the file ConvertToFile writes for
+// outputPath, with the extension of opts.Compression
+func outputName(outputPath string, opts *Options) string {
+	if opts == nil || opts.Compression == nil {
+		return outputPath
+	}
+	if ext := opts.Compression.Extension(); !strings.HasSuffix(outputPath, ext) {
+		return outputPath + ext
+	}
+	return outputPath
+}
+
+// runToOutput converts to the file named by outputName on the output file
+// system, compressing the text with opts.Compression if it is set. The file
+// is removed if the conversion fails.
+func (c *Converter) runToOutput(ctx context.Context, inputPath, outputPath string, opts *Options) error {
+	name := outputName(outputPath, opts)
+	plain := opts
+	if opts != nil && opts.Compression != nil {
+		uncompressed := *opts
+		uncompressed.Compression = nil
+		plain = &uncompressed
+	}
+	err := c.createOutput(ctx, name, func(w io.Writer) error {
+		return compress(w, opts, func(w io.Writer) error {
+			return c.run(ctx, inputPath, "-", plain, w)
+		})
+	})
+	if err != nil {
+		c.output().Remove(ctx, name)
+	}
+	return err
+}
+
+// compress calls fn with a writer compressing to w with opts.Compression, or
+// with w if it is not set
+func compress(w io.Writer, opts *Options, fn func(w io.Writer) error) error {
+	if opts == nil || opts.Compression == nil {
+		return fn(w)
+	}
+	zw, err := opts.Compression.NewWriter(w)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputFile, err)
+	}
+	if err := fn(zw); err != nil {
+		zw.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputFile, err)
+	}
+	return nil
+}
+
+// createOutput creates the named output file and calls fn with it
+func (c *Converter) createOutput(ctx context.Context, name string, fn func(w io.Writer) error) (err error) {
+	f, err := c.output().Create(ctx, name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputFile, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("%w: %w", ErrOutputFile, closeErr)
+		}
+	}()
+	return fn(f)
+}
+
+// readOutput returns the contents of the named output file
+func (c *Converter) readOutput(ctx context.Context, name string) ([]byte, error) {
+	f, err := c.output().Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
@@ -0,0 +1,61 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestConverter_OutputFS(t *testing.T) {
+	storage := &MemoryStorage{}
+	converter := &Converter{binaryPath: "pdftotext", hash: SHA256, outputFS: StorageFS(storage), runner: runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if args[len(args)-2] == "broken.pdf" {
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, "page 1\fpage 2\f")
+		return err
+	})}
+	ctx := context.Background()
+
+	t.Run("file", func(t *testing.T) {
+		if err := converter.ConvertToFile(ctx, "input.pdf", "out/input.txt", nil); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		data, err := storage.Get(ctx, "out/input.txt")
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		if string(data) != "page 1\fpage 2\f" {
+			t.Errorf("expected the text, got %q", data)
+		}
+	})
+
+	t.Run("sidecar", func(t *testing.T) {
+		s, err := converter.ConvertToFileWithSidecar(ctx, "input.pdf", "out/sidecar.txt", nil)
+		if err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		if s.Pages != 2 {
+			t.Errorf("expected 2 pages read back from the output, got %d", s.Pages)
+		}
+		if _, err := storage.Get(ctx, "out/sidecar.txt"+SidecarSuffix); err != nil {
+			t.Errorf("expected the sidecar in storage, got %v", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		if err := converter.ConvertToFile(ctx, "broken.pdf", "out/broken.txt", nil); !errors.Is(err, ErrPDFOpen) {
+			t.Errorf("expected ErrPDFOpen, got %v", err)
+		}
+		if _, err := storage.Get(ctx, "out/broken.txt"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected no output file, got %v", err)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		if err := converter.ConvertToFile(ctx, "input.pdf", "/abs/input.txt", nil); !errors.Is(err, ErrOutputFile) {
+			t.Errorf("expected ErrOutputFile, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package pdftotext
+
+import (
+	"context"
+	"slices"
+)
+
+// overridesKey is the context key of the overrides of ContextWithOverrides
+type overridesKey struct{}
+
+// optionOverrides are the overrides attached to a context in order
+type optionOverrides []func(*Options)
+
+// ContextWithOverrides returns a copy of ctx carrying override, which the
+// conversions and the pdfinfo-based APIs called with it apply to a copy of
+// their Options before they start. Framework middleware can attach
+// per-request settings such as passwords, page ranges or timeouts this way
+// without threading Options through every layer. The overrides of the parent
+// contexts are applied first.
+func ContextWithOverrides(ctx context.Context, override func(*Options)) context.Context {
+	overrides, _ := ctx.Value(overridesKey{}).(optionOverrides)
+	return context.WithValue(ctx, overridesKey{}, append(slices.Clip(overrides), override))
+}
+
+// applyOverrides applies the overrides of ctx to a copy of opts. It returns
+// a context without them, so the conversions a conversion is made of do not
+// apply them again.
+func applyOverrides(ctx context.Context, opts *Options) (context.Context, *Options) {
+	overrides, _ := ctx.Value(overridesKey{}).(optionOverrides)
+	if len(overrides) == 0 {
+		return ctx, opts
+	}
+	o := Options{}
+	if opts != nil {
+		o = *opts
+	}
+	// appending to the slices of the copy must not change those of opts
+	o.Transformers = slices.Clip(o.Transformers)
+	o.GarbledStrategies = slices.Clip(o.GarbledStrategies)
+	o.PreHooks = slices.Clip(o.PreHooks)
+	o.PostHooks = slices.Clip(o.PostHooks)
+	for _, override := range overrides {
+		override(&o)
+	}
+	return context.WithValue(ctx, overridesKey{}, optionOverrides(nil)), &o
+}
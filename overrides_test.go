@@ -0,0 +1,141 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestContextWithOverrides(t *testing.T) {
+	var mu sync.Mutex
+	var calls [][]string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		mu.Lock()
+		calls = append(calls, args)
+		mu.Unlock()
+		if name == "pdfinfo" {
+			_, err := io.WriteString(stdout, "Pages:          3\n")
+			return err
+		}
+		_, err := io.WriteString(stdout, "page\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	// middleware attaches the password of the request, a handler narrows the
+	// pages
+	ctx := ContextWithOverrides(context.Background(), func(o *Options) {
+		o.UserPassword = "secret"
+	})
+	ctx = ContextWithOverrides(ctx, func(o *Options) {
+		o.FirstPage, o.LastPage = 2, 2
+		o.Transformers = append(o.Transformers, TransformerFunc(func(page int, text string) (string, error) {
+			return strings.ToUpper(text), nil
+		}))
+	})
+	identity := TransformerFunc(func(page int, text string) (string, error) {
+		return text, nil
+	})
+	opts := &Options{Layout: true, Transformers: make([]Transformer, 1, 4)}
+	opts.Transformers[0] = identity
+
+	hasArgs := func(args []string, expected ...string) bool {
+		for i := range args {
+			if slices.Equal(args[i:min(i+len(expected), len(args))], expected) {
+				return true
+			}
+		}
+		return false
+	}
+	tests := []struct {
+		name    string
+		convert func() error
+		tool    string
+		args    [][]string
+	}{
+		{
+			name: "Convert",
+			convert: func() error {
+				text, err := converter.Convert(ctx, "input.pdf", opts)
+				if err == nil && text != "PAGE" {
+					t.Errorf("expected the transformer of the override, got %q", text)
+				}
+				return err
+			},
+			args: [][]string{{"-layout"}, {"-f", "2"}, {"-l", "2"}, {"-upw", "secret"}},
+		},
+		{
+			name: "ConvertPages",
+			convert: func() error {
+				pages, err := converter.ConvertPages(ctx, "input.pdf", opts)
+				if err == nil && (len(pages) != 1 || pages[0].Number != 2) {
+					t.Errorf("expected page 2, got %v", pages)
+				}
+				return err
+			},
+			args: [][]string{{"-f", "2"}, {"-upw", "secret"}},
+		},
+		{
+			name: "Info",
+			convert: func() error {
+				_, err := converter.Info(ctx, "input.pdf", nil)
+				return err
+			},
+			args: [][]string{{"-upw", "secret"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			if err := tt.convert(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(calls) != 1 {
+				t.Fatalf("expected one command, got %q", calls)
+			}
+			for _, args := range tt.args {
+				if !hasArgs(calls[0], args...) {
+					t.Errorf("expected arguments %q in %q", args, calls[0])
+				}
+			}
+		})
+	}
+
+	if opts.FirstPage != 0 || opts.UserPassword != "" || len(opts.Transformers) != 1 {
+		t.Errorf("expected the options to be unchanged, got %+v", opts)
+	}
+	// the spare capacity of the transformers must not have been written to
+	if opts.Transformers[:2][1] != nil {
+		t.Error("expected the override not to append to the transformers of the options")
+	}
+}
+
+func TestContextWithOverrides_CacheResults(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	convert := Chain(func(ctx context.Context, inputPath string, opts *Options) (string, error) {
+		if _, opts = applyOverrides(ctx, opts); opts == nil {
+			return "all pages", nil
+		}
+		return fmt.Sprintf("pages from %d", opts.FirstPage), nil
+	}, CacheResults(NewMemoryCache(10), 0))
+
+	ctx := context.Background()
+	if text, err := convert(ctx, input, nil); err != nil || text != "all pages" {
+		t.Fatalf("unexpected result %q, %v", text, err)
+	}
+	ctx = ContextWithOverrides(ctx, func(o *Options) { o.FirstPage = 5 })
+	if text, err := convert(ctx, input, nil); err != nil || text != "pages from 5" {
+		t.Errorf("expected the overrides to be part of the cache key, got %q, %v", text, err)
+	}
+}
@@ -0,0 +1,62 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// pageFilePattern returns the format of the page file names of outputPath:
+// outputPath itself if it contains a verb for the page number, otherwise
+// outputPath with "_p%04d" before its extension
+func pageFilePattern(outputPath string) string {
+	if strings.Contains(outputPath, "%") {
+		return outputPath
+	}
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "_p%04d" + ext
+}
+
+// PageFilePath returns the file ConvertToFile writes a page to with
+// Options.SplitPages, e.g. "doc_p0001.txt" for page 1 and the output path
+// "doc.txt"
+func PageFilePath(outputPath string, page int) string {
+	return fmt.Sprintf(pageFilePattern(outputPath), page)
+}
+
+// runSplitPages streams the pages selected by opts from a single pdftotext
+// process into a file per page. The files written so far are removed if the
+// conversion fails.
+func (c *Converter) runSplitPages(ctx context.Context, inputPath, outputPath string, opts *Options) (err error) {
+	var written []string
+	defer func() {
+		if err != nil {
+			for _, name := range written {
+				c.output().Remove(ctx, name)
+			}
+		}
+	}()
+
+	plain := *opts
+	plain.SplitPages = false
+	plain.Compression = nil
+	for page, err := range c.StreamPages(ctx, inputPath, &plain) {
+		if err != nil {
+			return err
+		}
+		name := outputName(PageFilePath(outputPath, page.Number), opts)
+		written = append(written, name)
+		err := c.createOutput(ctx, name, func(w io.Writer) error {
+			return compress(w, opts, func(w io.Writer) error {
+				_, err := io.WriteString(w, page.Text)
+				return err
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
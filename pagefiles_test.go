@@ -0,0 +1,102 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPageFilePath(t *testing.T) {
+	tests := []struct {
+		output   string
+		page     int
+		expected string
+	}{
+		{output: "doc.txt", page: 1, expected: "doc_p0001.txt"},
+		{output: "out/doc.txt", page: 12, expected: "out/doc_p0012.txt"},
+		{output: "doc", page: 3, expected: "doc_p0003"},
+		{output: "pages/%03d.txt", page: 7, expected: "pages/007.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if got := PageFilePath(tt.output, tt.page); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConverter_SplitPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		code          int
+		options       *Options
+		expectedFiles map[string]string
+		expectedError error
+	}{
+		{
+			name:    "Pages",
+			output:  "first\f\fthird\f",
+			options: &Options{SplitPages: true},
+			expectedFiles: map[string]string{
+				"doc_p0001.txt": "first",
+				"doc_p0002.txt": "",
+				"doc_p0003.txt": "third",
+			},
+		},
+		{
+			name:          "Page range",
+			output:        "tenth\f",
+			options:       &Options{SplitPages: true, FirstPage: 10, LastPage: 10},
+			expectedFiles: map[string]string{"doc_p0010.txt": "tenth"},
+		},
+		{
+			name:          "Failed conversion",
+			output:        "first\fsecond",
+			code:          -1,
+			options:       &Options{SplitPages: true},
+			expectedFiles: map[string]string{},
+			expectedError: ErrCommandFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+				io.WriteString(stdout, tt.output)
+				if tt.code != 0 {
+					return &ExitError{Code: tt.code}
+				}
+				return nil
+			})))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			dir := t.TempDir()
+			err = converter.ConvertToFile(context.Background(), "input.pdf", filepath.Join(dir, "doc.txt"), tt.options)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("failed to read output directory: %v", err)
+			}
+			files := map[string]string{}
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				if err != nil {
+					t.Fatalf("failed to read %s: %v", entry.Name(), err)
+				}
+				files[entry.Name()] = string(data)
+			}
+			if !reflect.DeepEqual(files, tt.expectedFiles) {
+				t.Errorf("expected %v, got %v", tt.expectedFiles, files)
+			}
+		})
+	}
+}
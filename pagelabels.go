@@ -0,0 +1,209 @@
+package pdftotext
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// LabelStyle is the numbering style of a page label range
+type LabelStyle string
+
+const (
+	// LabelNone labels pages with the prefix only
+	LabelNone LabelStyle = ""
+	// LabelDecimal numbers pages 1, 2, 3
+	LabelDecimal LabelStyle = "D"
+	// LabelUpperRoman numbers pages I, II, III
+	LabelUpperRoman LabelStyle = "R"
+	// LabelLowerRoman numbers pages i, ii, iii
+	LabelLowerRoman LabelStyle = "r"
+	// LabelUpperLetters numbers pages A to Z, then AA to ZZ
+	LabelUpperLetters LabelStyle = "A"
+	// LabelLowerLetters numbers pages a to z, then aa to zz
+	LabelLowerLetters LabelStyle = "a"
+)
+
+// PageLabelRange labels the pages from its first page up to the first page
+// of the next range
+type PageLabelRange struct {
+	// FirstPage is the physical number of the first page of the range
+	FirstPage int `json:"first_page"`
+	// Style is the numbering style
+	Style LabelStyle `json:"style,omitempty"`
+	// Prefix is prepended to the number, e.g. "A-"
+	Prefix string `json:"prefix,omitempty"`
+	// Start is the number of the first page of the range
+	Start int `json:"start"`
+}
+
+// PageLabels are the page label ranges of a document, ordered by first page
+type PageLabels []PageLabelRange
+
+// Label returns the label of the page with the given physical number, or the
+// number itself if the document has no label for it
+func (l PageLabels) Label(page int) string {
+	i := -1
+	for j, r := range l {
+		if r.FirstPage <= page {
+			i = j
+		}
+	}
+	if i < 0 {
+		return strconv.Itoa(page)
+	}
+
+	r := l[i]
+	n := r.Start + page - r.FirstPage
+	switch r.Style {
+	case LabelDecimal:
+		return r.Prefix + strconv.Itoa(n)
+	case LabelUpperRoman:
+		return r.Prefix + strings.ToUpper(roman(n))
+	case LabelLowerRoman:
+		return r.Prefix + roman(n)
+	case LabelUpperLetters:
+		return r.Prefix + strings.ToUpper(letters(n))
+	case LabelLowerLetters:
+		return r.Prefix + letters(n)
+	}
+	return r.Prefix
+}
+
+// roman formats n as a lower case roman numeral
+func roman(n int) string {
+	if n <= 0 || n >= 5000 {
+		return strconv.Itoa(n)
+	}
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	numerals := []string{"m", "cm", "d", "cd", "c", "xc", "l", "xl", "x", "ix", "v", "iv", "i"}
+	var b strings.Builder
+	for i, v := range values {
+		for n >= v {
+			b.WriteString(numerals[i])
+			n -= v
+		}
+	}
+	return b.String()
+}
+
+// letters formats n as a to z, then aa to zz, and so on
+func letters(n int) string {
+	if n <= 0 || n > 26*100 {
+		return strconv.Itoa(n)
+	}
+	return strings.Repeat(string(rune('a'+(n-1)%26)), (n-1)/26+1)
+}
+
+// ReadPageLabels reads the page labels defined in the catalog of a PDF file,
+// e.g. roman numerals for the front matter. It returns nil if the document
+// defines no labels or they cannot be read, as in encrypted documents.
+func ReadPageLabels(path string) (PageLabels, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	return parsePageLabels(data), nil
+}
+
+// parsePageLabels reads the page labels of a PDF file
+func parsePageLabels(data []byte) PageLabels {
+	f := newPDFFile(data)
+	trailer, err := f.trailer()
+	if err != nil || trailer["Encrypt"] != nil {
+		return nil
+	}
+	root, err := f.resolve(trailer["Root"])
+	if err != nil {
+		return nil
+	}
+	catalog, ok := root.(pdfDict)
+	if !ok {
+		return nil
+	}
+
+	var labels PageLabels
+	f.walkNumberTree(catalog["PageLabels"], 0, func(index int, v any) {
+		dict, ok := v.(pdfDict)
+		if !ok || index < 0 {
+			return
+		}
+		r := PageLabelRange{FirstPage: index + 1, Start: 1}
+		if style, ok := dict["S"].(pdfName); ok {
+			r.Style = LabelStyle(style)
+		}
+		if prefix, err := f.resolve(dict["P"]); err == nil {
+			if s, ok := prefix.(string); ok {
+				r.Prefix = decodeTextString(s)
+			}
+		}
+		if start, ok := dict["St"].(int); ok && start > 0 {
+			r.Start = start
+		}
+		labels = append(labels, r)
+	})
+
+	slices.SortStableFunc(labels, func(a, b PageLabelRange) int { return a.FirstPage - b.FirstPage })
+	return labels
+}
+
+// walkNumberTree calls fn with each key and resolved value of a number tree
+func (f *pdfFile) walkNumberTree(node any, depth int, fn func(key int, v any)) {
+	if depth > maxObjectDepth {
+		return
+	}
+	v, err := f.resolve(node)
+	if err != nil {
+		return
+	}
+	dict, ok := v.(pdfDict)
+	if !ok {
+		return
+	}
+
+	if kids, err := f.resolve(dict["Kids"]); err == nil {
+		if kids, ok := kids.([]any); ok {
+			for _, kid := range kids {
+				f.walkNumberTree(kid, depth+1, fn)
+			}
+		}
+	}
+	nums, err := f.resolve(dict["Nums"])
+	if err != nil {
+		return
+	}
+	if nums, ok := nums.([]any); ok {
+		for i := 0; i+1 < len(nums); i += 2 {
+			key, ok := nums[i].(int)
+			if !ok {
+				continue
+			}
+			if value, err := f.resolve(nums[i+1]); err == nil {
+				fn(key, value)
+			}
+		}
+	}
+}
+
+// decodeTextString decodes a PDF text string, which is UTF-16BE with a byte
+// order mark or else PDFDocEncoding, approximated by Latin-1
+func decodeTextString(s string) string {
+	if strings.HasPrefix(s, "\xfe\xff") {
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	if strings.HasPrefix(s, "\xef\xbb\xbf") {
+		return s[3:]
+	}
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,151 @@
+package pdftotext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// labeledPDF is a minimal PDF whose page labels number the front matter with
+// roman numerals and the appendix with letters
+const labeledPDF = `%PDF-1.4
+1 0 obj
+<< /Type /Catalog /Pages 2 0 R /PageLabels 3 0 R >>
+endobj
+3 0 obj
+<< /Nums [0 << /S /r >> 4 << /S /D >> 10 << /S /A /P (App-) /St 1 >> 12 << /P <FEFF00DF> >>] >>
+endobj
+trailer
+<< /Root 1 0 R /Size 4 >>
+%%EOF
+`
+
+// compressedLabeledPDF stores the catalog and a number tree with kids in a
+// Flate compressed object stream, as PDF 1.5 writers do
+func compressedLabeledPDF(t *testing.T) []byte {
+	t.Helper()
+	objects := []string{
+		"<< /Type /Catalog /PageLabels 5 0 R >>",
+		"<< /Kids [6 0 R 7 0 R] >>",
+		"<< /Nums [0 << /S /R /St 3 >>] >>",
+		"<< /Nums [2 << /S /a >>] >>",
+	}
+	var header, body bytes.Buffer
+	for i, obj := range objects {
+		fmt.Fprintf(&header, "%d %d ", []int{1, 5, 6, 7}[i], body.Len())
+		body.WriteString(obj + "\n")
+	}
+
+	var content bytes.Buffer
+	zw := zlib.NewWriter(&content)
+	zw.Write(header.Bytes())
+	zw.Write(body.Bytes())
+	zw.Close()
+
+	var pdf bytes.Buffer
+	fmt.Fprintf(&pdf, "%%PDF-1.5\n4 0 obj\n<< /Type /ObjStm /N 4 /First %d /Filter /FlateDecode /Length %d >>\nstream\n", header.Len(), content.Len())
+	pdf.Write(content.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n8 0 obj\n<< /Type /XRef /Root 1 0 R /Size 9 >>\nstream\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestParsePageLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected map[int]string
+	}{
+		{
+			name:     "Uncompressed",
+			data:     []byte(labeledPDF),
+			expected: map[int]string{1: "i", 4: "iv", 5: "1", 10: "6", 11: "App-A", 12: "App-B", 13: "ß", 14: "ß"},
+		},
+		{
+			name:     "Object stream",
+			data:     compressedLabeledPDF(t),
+			expected: map[int]string{1: "III", 2: "IV", 3: "a", 29: "aa"},
+		},
+		{
+			name:     "No labels",
+			data:     []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n"),
+			expected: map[int]string{1: "1", 7: "7"},
+		},
+		{
+			name:     "Encrypted",
+			data:     []byte("%PDF-1.4\n1 0 obj\n<< /PageLabels << /Nums [0 << /S /r >>] >> >>\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R >>\n"),
+			expected: map[int]string{1: "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := parsePageLabels(tt.data)
+			for page, expected := range tt.expected {
+				if got := labels.Label(page); got != expected {
+					t.Errorf("page %d: expected %q, got %q", page, expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPageLabelFormats(t *testing.T) {
+	for n, expected := range map[int]string{1: "i", 4: "iv", 9: "ix", 14: "xiv", 1994: "mcmxciv", 0: "0"} {
+		if got := roman(n); got != expected {
+			t.Errorf("roman(%d): expected %q, got %q", n, expected, got)
+		}
+	}
+	for n, expected := range map[int]string{1: "a", 26: "z", 27: "aa", 53: "aaa", 0: "0"} {
+		if got := letters(n); got != expected {
+			t.Errorf("letters(%d): expected %q, got %q", n, expected, got)
+		}
+	}
+}
+
+func TestConverter_PageLabels(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "labeled.pdf")
+	if err := os.WriteFile(input, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	var runs atomic.Int32
+	converter, err := New(WithRunner(pagesRunner(6, 0, &runs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	opts := &Options{Pages: "3-5", PageLabels: true}
+	expected := []string{"iii", "iv", "1"}
+
+	pages, err := converter.ConvertPages(context.Background(), input, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var labels []string
+	for _, page := range pages {
+		labels = append(labels, page.Label)
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("expected labels %v, got %v", expected, labels)
+	}
+
+	labels = nil
+	for page, err := range converter.StreamPages(context.Background(), input, opts) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		labels = append(labels, page.Label)
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("expected streamed labels %v, got %v", expected, labels)
+	}
+
+	if _, err := converter.ConvertPages(context.Background(), filepath.Join(t.TempDir(), "missing.pdf"), opts); !errors.Is(err, ErrPDFOpen) {
+		t.Errorf("expected error %v, got %v", ErrPDFOpen, err)
+	}
+}
@@ -0,0 +1,74 @@
+package pdftotext
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultPageNumberPatterns match the usual page-number lines: "Page 12",
+// "Page 12 of 87", "p. 12", "12 / 87", "- 12 -", a bare "12" and lower case
+// roman numerals such as "xii"
+var DefaultPageNumberPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(?:page|pg\.?|p\.|seite|página|pagina)\s*\d+(?:\s*(?:of|/|von|de|di|sur)\s*\d+)?$`),
+	regexp.MustCompile(`^\d+\s*(?:/|of)\s*\d+$`),
+	regexp.MustCompile(`^[-–—]?\s*\d+\s*[-–—]?$`),
+	regexp.MustCompile(`^(?:m{0,3})(?:cm|cd|d?c{0,3})(?:xc|xl|l?x{0,3})(?:ix|iv|v?i{0,3})$`),
+}
+
+// PageNumberStripper is a Transformer removing standalone page-number lines,
+// such as "Page 12 of 87" or a bare "12", from the top and bottom of each
+// page, so indexed text is not polluted with pagination
+type PageNumberStripper struct {
+	// Patterns match the trimmed lines to remove. DefaultPageNumberPatterns
+	// are used if it is empty.
+	Patterns []*regexp.Regexp
+	// Margin is the number of non-blank lines searched at the top and at the
+	// bottom of the page (default 2). If it is negative, the whole page is
+	// searched.
+	Margin int
+}
+
+// Transform removes the page-number lines from text
+func (s PageNumberStripper) Transform(page int, text string) (string, error) {
+	patterns := s.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultPageNumberPatterns
+	}
+	margin := s.Margin
+	if margin == 0 {
+		margin = 2
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	var nonBlank []int
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			nonBlank = append(nonBlank, i)
+		}
+	}
+
+	remove := map[int]bool{}
+	for n, i := range nonBlank {
+		if margin > 0 && n >= margin && n < len(nonBlank)-margin {
+			continue
+		}
+		trimmed := strings.TrimSpace(lines[i])
+		for _, p := range patterns {
+			if p.MatchString(trimmed) {
+				remove[i] = true
+				break
+			}
+		}
+	}
+	if len(remove) == 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		if !remove[i] {
+			b.WriteString(line)
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,75 @@
+package pdftotext
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestPageNumberStripper_Transform(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        PageNumberStripper
+		input    string
+		expected string
+	}{
+		{
+			name:     "page of pages footer",
+			input:    "Title\nBody text\n\n  Page 12 of 87  \n",
+			expected: "Title\nBody text\n\n",
+		},
+		{
+			name:     "bare number header",
+			input:    "12\nChapter One\nBody text\nmore text\nlast line\n",
+			expected: "Chapter One\nBody text\nmore text\nlast line\n",
+		},
+		{
+			name:     "dashes and slashes",
+			input:    "- 3 -\nBody text\n3 / 10",
+			expected: "Body text\n",
+		},
+		{
+			name:     "roman numerals",
+			input:    "Preface\nBody text\nxiv\n",
+			expected: "Preface\nBody text\n",
+		},
+		{
+			name:     "numbers in the body are kept",
+			input:    "Heading\nIntro\n42\nmore text\nlast line\nfinal line\n",
+			expected: "Heading\nIntro\n42\nmore text\nlast line\nfinal line\n",
+		},
+		{
+			name:     "numbers within text are kept",
+			input:    "See page 12 for details\nTotal 42 items\n",
+			expected: "See page 12 for details\nTotal 42 items\n",
+		},
+		{
+			name:     "whole page",
+			s:        PageNumberStripper{Margin: -1},
+			input:    "Heading\nIntro\n42\nmore text\nlast line\nfinal line\n",
+			expected: "Heading\nIntro\nmore text\nlast line\nfinal line\n",
+		},
+		{
+			name:     "custom pattern",
+			s:        PageNumberStripper{Patterns: []*regexp.Regexp{regexp.MustCompile(`^Folio \d+$`)}},
+			input:    "Folio 7\nBody text\n12\n",
+			expected: "Body text\n12\n",
+		},
+		{
+			name:     "crlf",
+			input:    "Body text\r\nPage 2\r\n",
+			expected: "Body text\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Transform(1, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PageRange is a contiguous range of pages
+type PageRange struct {
+	// First is the first page of the range
+	First int `json:"first"`
+	// Last is the last page of the range, or 0 if the range runs to the end
+	// of the document
+	Last int `json:"last"`
+}
+
+// String formats the range like ParsePageRange accepts it
+func (r PageRange) String() string {
+	switch r.Last {
+	case 0:
+		return fmt.Sprintf("%d-", r.First)
+	case r.First:
+		return strconv.Itoa(r.First)
+	default:
+		return fmt.Sprintf("%d-%d", r.First, r.Last)
+	}
+}
+
+// ParsePageRange parses a page range expression such as "1-3,7,12-" into its
+// ranges, in the order given. A range is a single page "7", a closed range
+// "1-3", or an open range "12-" running to the end of the document.
+func ParsePageRange(expr string) ([]PageRange, error) {
+	var ranges []PageRange
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+
+		r := PageRange{}
+		var err error
+		if r.First, err = parsePage(first); err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidRange, part, err)
+		}
+		switch {
+		case !isRange:
+			r.Last = r.First
+		case strings.TrimSpace(last) != "":
+			if r.Last, err = parsePage(last); err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidRange, part, err)
+			}
+			if r.Last < r.First {
+				return nil, fmt.Errorf("%w: %q: first page is after last page", ErrInvalidRange, part)
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parsePage parses a page number
+func parsePage(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid page number")
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("page numbers start at 1")
+	}
+	return n, nil
+}
+
+// multiRange reports whether opts selects pages that need more than one
+// pdftotext process, or a page count to compute the range
+func multiRange(opts *Options) bool {
+	return opts != nil && (opts.Pages != "" || opts.ExcludePages != "" || opts.TailPages > 0)
+}
+
+// rangeOptions returns a copy of opts for each range of pages selected by
+// opts.Pages, opts.TailPages and opts.ExcludePages, with FirstPage and
+// LastPage set to the range. It returns opts itself when a single process
+// converts the pages as given.
+func (c *Converter) rangeOptions(ctx context.Context, inputPath string, opts *Options) ([]*Options, error) {
+	if !multiRange(opts) {
+		return []*Options{opts}, nil
+	}
+
+	ranges := []PageRange{{First: max(opts.FirstPage, 1), Last: opts.LastPage}}
+	switch {
+	case opts.Pages != "" && opts.TailPages > 0:
+		return nil, fmt.Errorf("%w: Pages cannot be combined with TailPages", ErrInvalidRange)
+	case (opts.Pages != "" || opts.TailPages > 0) && (opts.FirstPage != 0 || opts.LastPage != 0):
+		return nil, fmt.Errorf("%w: Pages and TailPages cannot be combined with FirstPage or LastPage", ErrInvalidRange)
+	case opts.Pages != "":
+		var err error
+		if ranges, err = ParsePageRange(opts.Pages); err != nil {
+			return nil, err
+		}
+	case opts.TailPages > 0:
+		count, err := c.PageCount(ctx, inputPath, opts)
+		if err != nil {
+			return nil, err
+		}
+		ranges = []PageRange{{First: max(count-opts.TailPages+1, 1), Last: count}}
+	}
+	if opts.ExcludePages != "" {
+		excluded, err := ParsePageRange(opts.ExcludePages)
+		if err != nil {
+			return nil, err
+		}
+		ranges = excludeRanges(ranges, excluded)
+		if len(ranges) == 0 {
+			return nil, fmt.Errorf("%w: every selected page is excluded", ErrInvalidRange)
+		}
+	}
+
+	rangeOpts := make([]*Options, len(ranges))
+	for i, r := range ranges {
+		o := *opts
+		o.Pages, o.ExcludePages, o.TailPages = "", "", 0
+		o.FirstPage, o.LastPage = r.First, r.Last
+		rangeOpts[i] = &o
+	}
+	return rangeOpts, nil
+}
+
+// excludeRanges removes the excluded pages from ranges, keeping their order.
+// Open-ended ranges stay open-ended, so no page count is needed.
+func excludeRanges(ranges, excluded []PageRange) []PageRange {
+	// an open end is treated as the largest page number while subtracting
+	end := func(r PageRange) int {
+		if r.Last == 0 {
+			return math.MaxInt
+		}
+		return r.Last
+	}
+
+	for _, e := range excluded {
+		var kept []PageRange
+		for _, r := range ranges {
+			if e.First > end(r) || end(e) < r.First {
+				kept = append(kept, r)
+				continue
+			}
+			if e.First > r.First {
+				kept = append(kept, PageRange{First: r.First, Last: e.First - 1})
+			}
+			if end(e) < end(r) {
+				kept = append(kept, PageRange{First: end(e) + 1, Last: r.Last})
+			}
+		}
+		ranges = kept
+	}
+	return ranges
+}
+
+// runPages converts each range selected by opts.Pages, opts.TailPages and
+// opts.ExcludePages with its own pdftotext process, since the binary only
+// supports a single range, and concatenates the output in the order of the
+// ranges
+func (c *Converter) runPages(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, opts)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(outputPath, stdout, func(w io.Writer) error {
+		// hide a *bytes.Buffer from the retry loop, which would reset it and
+		// discard the output of the previous ranges
+		w = struct{ io.Writer }{w}
+
+		for _, o := range rangeOpts {
+			if err := c.run(ctx, inputPath, "-", o, w); err != nil {
+				return fmt.Errorf("pages %s: %w", PageRange{First: o.FirstPage, Last: o.LastPage}, err)
+			}
+		}
+		return nil
+	})
+}
+
+// writeOutput calls fn with the writer for the output of a conversion: stdout,
+// or the output file when outputPath is not "-"
+func writeOutput(outputPath string, stdout io.Writer, fn func(w io.Writer) error) (err error) {
+	if outputPath == "-" {
+		return fn(stdout)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOutputFile, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("%w: %w", ErrOutputFile, closeErr)
+		}
+	}()
+	return fn(f)
+}
@@ -0,0 +1,244 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParsePageRange(t *testing.T) {
+	tests := []struct {
+		expr          string
+		expected      []PageRange
+		expectedError error
+	}{
+		{expr: "1-3,7,12-", expected: []PageRange{{1, 3}, {7, 7}, {12, 0}}},
+		{expr: " 5 - 6 , 2 ", expected: []PageRange{{5, 6}, {2, 2}}},
+		{expr: "4-4", expected: []PageRange{{4, 4}}},
+		{expr: "", expectedError: ErrInvalidRange},
+		{expr: "1,,2", expectedError: ErrInvalidRange},
+		{expr: "0-3", expectedError: ErrInvalidRange},
+		{expr: "5-2", expectedError: ErrInvalidRange},
+		{expr: "-3", expectedError: ErrInvalidRange},
+		{expr: "a-b", expectedError: ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			ranges, err := ParsePageRange(tt.expr)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if !reflect.DeepEqual(ranges, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ranges)
+			}
+		})
+	}
+}
+
+func TestPageRange_String(t *testing.T) {
+	for _, r := range []PageRange{{1, 3}, {7, 7}, {12, 0}} {
+		parsed, err := ParsePageRange(r.String())
+		if err != nil || len(parsed) != 1 || parsed[0] != r {
+			t.Errorf("%v did not round trip: %v, %v", r, parsed, err)
+		}
+	}
+}
+
+func TestExcludeRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		ranges   []PageRange
+		excluded []PageRange
+		expected []PageRange
+	}{
+		{"Cover sheet", []PageRange{{1, 0}}, []PageRange{{1, 1}}, []PageRange{{2, 0}}},
+		{"Middle", []PageRange{{1, 10}}, []PageRange{{4, 5}}, []PageRange{{1, 3}, {6, 10}}},
+		{"Open end", []PageRange{{1, 0}}, []PageRange{{8, 0}}, []PageRange{{1, 7}}},
+		{"Several", []PageRange{{1, 0}}, []PageRange{{2, 2}, {4, 4}}, []PageRange{{1, 1}, {3, 3}, {5, 0}}},
+		{"Disjoint", []PageRange{{3, 4}}, []PageRange{{1, 2}, {5, 0}}, []PageRange{{3, 4}}},
+		{"Everything", []PageRange{{2, 3}}, []PageRange{{1, 5}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := excludeRanges(tt.ranges, tt.excluded); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConverter_ExcludePages(t *testing.T) {
+	var runs atomic.Int32
+	converter, err := New(WithRunner(pagesRunner(6, 0, &runs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		options       *Options
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "Cover sheet",
+			options:  &Options{ExcludePages: "1"},
+			expected: "page 2\n\fpage 3\n\fpage 4\n\fpage 5\n\fpage 6",
+		},
+		{
+			name:     "Within page range",
+			options:  &Options{FirstPage: 2, LastPage: 5, ExcludePages: "3-4"},
+			expected: "page 2\n\fpage 5",
+		},
+		{
+			name:     "Within range expression",
+			options:  &Options{Pages: "1-2,5-", ExcludePages: "2,6"},
+			expected: "page 1\n\fpage 5",
+		},
+		{
+			name:          "Every page",
+			options:       &Options{LastPage: 2, ExcludePages: "1-"},
+			expectedError: ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := converter.Convert(context.Background(), "input.pdf", tt.options)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestConverter_TailPages(t *testing.T) {
+	var runs atomic.Int32
+	converter, err := New(WithRunner(pagesRunner(12, 0, &runs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		options       *Options
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "Last pages",
+			options:  &Options{TailPages: 2},
+			expected: "page 11\n\fpage 12",
+		},
+		{
+			name:     "More than the page count",
+			options:  &Options{TailPages: 20, ExcludePages: "2-"},
+			expected: "page 1",
+		},
+		{
+			name:     "With excluded pages",
+			options:  &Options{TailPages: 3, ExcludePages: "11"},
+			expected: "page 10\n\fpage 12",
+		},
+		{
+			name:          "With first page",
+			options:       &Options{TailPages: 3, FirstPage: 2},
+			expectedError: ErrInvalidRange,
+		},
+		{
+			name:          "With page range expression",
+			options:       &Options{TailPages: 3, Pages: "1"},
+			expectedError: ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := converter.Convert(context.Background(), "input.pdf", tt.options)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestConverter_Pages(t *testing.T) {
+	var runs atomic.Int32
+	converter, err := New(WithRunner(pagesRunner(12, 0, &runs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	opts := &Options{Pages: "1-2,7,11-"}
+
+	text, err := converter.Convert(ctx, "input.pdf", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "page 1\n\fpage 2\n\fpage 7\n\fpage 11\n\fpage 12"
+	if text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+	if runs.Load() != 3 {
+		t.Errorf("expected 3 processes, got %d", runs.Load())
+	}
+
+	pages, err := converter.ConvertPages(ctx, "input.pdf", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var numbers []int
+	for _, page := range pages {
+		numbers = append(numbers, page.Number)
+	}
+	if expected := []int{1, 2, 7, 11, 12}; !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("expected pages %v, got %v", expected, numbers)
+	}
+
+	numbers = nil
+	for page, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		numbers = append(numbers, page.Number)
+	}
+	if expected := []int{1, 2, 7, 11, 12}; !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("expected streamed pages %v, got %v", expected, numbers)
+	}
+
+	parallel, err := converter.ConvertParallel(ctx, "input.pdf", 2, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parallel != expected {
+		t.Errorf("expected %q, got %q", expected, parallel)
+	}
+
+	output := filepath.Join(t.TempDir(), "output.txt")
+	if err := converter.ConvertToFile(ctx, "input.pdf", output, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(data) != expected+"\n\f" {
+		t.Errorf("expected %q, got %q", expected+"\n\f", data)
+	}
+
+	if _, err := converter.Convert(ctx, "input.pdf", &Options{Pages: "1-2", FirstPage: 1}); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("expected error %v, got %v", ErrInvalidRange, err)
+	}
+}
@@ -0,0 +1,178 @@
+package pdftotext
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Paragrapher is a Transformer merging hard-wrapped lines into paragraphs, so
+// text wrapped at the width of the page reads as prose. Paragraphs are
+// separated by a blank line.
+//
+// A paragraph ends at a blank line, at a line noticeably shorter than the
+// typical line of the page, and before a list item or an indented first line.
+// Run a Dehyphenator first to rejoin words hyphenated at the line breaks.
+type Paragrapher struct {
+	// ShortLine is the fraction of the typical line length below which a
+	// line ends its paragraph (default 0.75). Lines ending a sentence end
+	// their paragraph below a slightly higher threshold.
+	ShortLine float64
+	// Geometry optionally returns the word geometry of a page, e.g. from
+	// Session.Geometry. Pages with geometry are split into paragraphs at
+	// vertical gaps and indents between the lines, and their text is rebuilt
+	// from the words.
+	Geometry func(page int) (PageGeometry, error)
+}
+
+// listItemPattern matches lines starting with a bullet or an enumeration
+var listItemPattern = regexp.MustCompile(`^(?:[•◦▪‣*–-]|\(?(?:\d{1,3}|[a-zA-Z]|[ivxIVX]{1,4})[.)])\s`)
+
+// Transform merges the lines of text into paragraphs
+func (p Paragrapher) Transform(page int, text string) (string, error) {
+	if p.Geometry != nil {
+		geometry, err := p.Geometry(page)
+		if err != nil {
+			return "", err
+		}
+		if len(geometry.Words) > 0 {
+			return strings.Join(p.geometryParagraphs(geometry), "\n\n"), nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var lengths []int
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(strings.TrimSpace(line)); n > 0 {
+			lengths = append(lengths, n)
+		}
+	}
+	if len(lengths) == 0 {
+		return text, nil
+	}
+	slices.Sort(lengths)
+	typical := float64(lengths[len(lengths)*4/5])
+	short := p.shortLine()
+
+	var paragraphs []string
+	var current []string
+	prevIndent := 0
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if len(current) > 0 && (listItemPattern.MatchString(trimmed) || indent > prevIndent+1) {
+			flush()
+		}
+		current = append(current, trimmed)
+		prevIndent = indent
+
+		n := float64(utf8.RuneCountInString(trimmed))
+		if n < short*typical || endsSentence(trimmed) && n < (1+short)/2*typical {
+			flush()
+		}
+	}
+	flush()
+
+	out := strings.Join(paragraphs, "\n\n")
+	if strings.HasSuffix(text, "\n") {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// geometryLine is a line of words rebuilt from page geometry
+type geometryLine struct {
+	words []string
+	box   Rect
+}
+
+// geometryParagraphs groups the words of a page into lines and the lines
+// into paragraphs
+func (p Paragrapher) geometryParagraphs(page PageGeometry) []string {
+	var lines []geometryLine
+	for _, word := range page.Words {
+		if n := len(lines); n > 0 {
+			last := &lines[n-1]
+			_, y := word.Box.Center()
+			if y >= last.box.YMin && y <= last.box.YMax && word.Box.XMin >= last.box.XMin {
+				last.words = append(last.words, word.Text)
+				last.box = unionRect(last.box, word.Box)
+				continue
+			}
+		}
+		lines = append(lines, geometryLine{words: []string{word.Text}, box: word.Box})
+	}
+
+	// typical spacing, height and width of the lines
+	var gaps, heights, widths []float64
+	for i, line := range lines {
+		heights = append(heights, line.box.YMax-line.box.YMin)
+		widths = append(widths, line.box.XMax-line.box.XMin)
+		if i > 0 {
+			gaps = append(gaps, line.box.YMin-lines[i-1].box.YMax)
+		}
+	}
+	gap, height, width := median(gaps), median(heights), percentile(widths, 0.8)
+
+	var paragraphs []string
+	var current []string
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			text := strings.Join(line.words, " ")
+			switch {
+			case line.box.YMin-prev.box.YMax > gap+height/2,
+				line.box.YMax < prev.box.YMin,
+				line.box.XMin > prev.box.XMin+height,
+				prev.box.XMax-prev.box.XMin < p.shortLine()*width,
+				listItemPattern.MatchString(text):
+				paragraphs = append(paragraphs, strings.Join(current, " "))
+				current = nil
+			}
+		}
+		current = append(current, strings.Join(line.words, " "))
+	}
+	return append(paragraphs, strings.Join(current, " "))
+}
+
+func (p Paragrapher) shortLine() float64 {
+	if p.ShortLine > 0 {
+		return p.ShortLine
+	}
+	return 0.75
+}
+
+// endsSentence reports whether s ends with sentence punctuation, possibly
+// followed by a closing quote or bracket
+func endsSentence(s string) bool {
+	s = strings.TrimRight(s, `"')]”’»`)
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return strings.ContainsRune(".!?:…。", r)
+}
+
+// median returns the median of values, or 0 if there are none
+func median(values []float64) float64 {
+	return percentile(values, 0.5)
+}
+
+// percentile returns the value below which the fraction p of values lies,
+// or 0 if there are none
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted[min(int(p*float64(len(sorted))), len(sorted)-1)]
+}
@@ -0,0 +1,112 @@
+package pdftotext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParagrapher_Transform(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "hard-wrapped paragraphs",
+			input: "The quick brown fox jumps over the lazy dog and\n" +
+				"keeps running through the field until it reaches\n" +
+				"the river.\n" +
+				"\n" +
+				"A second paragraph starts after a blank line and\n" +
+				"continues here.\n",
+			expected: "The quick brown fox jumps over the lazy dog and keeps running through the field until it reaches the river.\n\n" +
+				"A second paragraph starts after a blank line and continues here.\n",
+		},
+		{
+			name: "short line ends paragraph",
+			input: "Introduction\n" +
+				"The quick brown fox jumps over the lazy dog and\n" +
+				"keeps running through the field until it reaches\n" +
+				"the river.\n" +
+				"Another paragraph follows without a blank line,\n" +
+				"ending here.",
+			expected: "Introduction\n\n" +
+				"The quick brown fox jumps over the lazy dog and keeps running through the field until it reaches the river.\n\n" +
+				"Another paragraph follows without a blank line, ending here.",
+		},
+		{
+			name: "list items and indents",
+			input: "The following steps need to be taken in order to\n" +
+				"finish the installation of the tool on the system:\n" +
+				"1. download the archive from the release page of\n" +
+				"the project\n" +
+				"2. unpack it into a directory on the search path of\n" +
+				"the shell\n" +
+				"    An indented line starts another paragraph that\n" +
+				"wraps around.",
+			expected: "The following steps need to be taken in order to finish the installation of the tool on the system:\n\n" +
+				"1. download the archive from the release page of the project\n\n" +
+				"2. unpack it into a directory on the search path of the shell\n\n" +
+				"An indented line starts another paragraph that wraps around.",
+		},
+		{
+			name:     "empty",
+			input:    "\n\n",
+			expected: "\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Paragrapher{}.Transform(1, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParagrapher_Geometry(t *testing.T) {
+	line := func(y, x float64, words ...string) []Word {
+		var out []Word
+		for _, w := range words {
+			out = append(out, Word{Text: w, Box: Rect{XMin: x, YMin: y, XMax: x + 50, YMax: y + 10}})
+			x += 55
+		}
+		return out
+	}
+	var words []Word
+	words = append(words, line(100, 50, "The", "quick", "brown", "fox", "jumps")...)
+	words = append(words, line(112, 50, "over", "the", "lazy", "dog", "and")...)
+	words = append(words, line(124, 50, "runs.")...)
+	words = append(words, line(150, 50, "After", "a", "gap", "comes", "another")...)
+	words = append(words, line(162, 50, "paragraph", "of", "text", "that", "wraps")...)
+	words = append(words, line(174, 80, "Indented", "first", "line", "of", "the")...)
+	words = append(words, line(186, 50, "third", "paragraph", "on", "the", "page")...)
+
+	p := Paragrapher{Geometry: func(page int) (PageGeometry, error) {
+		return PageGeometry{Number: page, Words: words}, nil
+	}}
+	got, err := p.Transform(1, "ignored")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"The quick brown fox jumps over the lazy dog and runs.",
+		"After a gap comes another paragraph of text that wraps",
+		"Indented first line of the third paragraph on the page",
+	}
+	if got != strings.Join(expected, "\n\n") {
+		t.Errorf("expected:\n%q\ngot:\n%q", strings.Join(expected, "\n\n"), got)
+	}
+
+	errGeometry := errors.New("no geometry")
+	p.Geometry = func(int) (PageGeometry, error) { return PageGeometry{}, errGeometry }
+	if _, err := p.Transform(1, "text"); !errors.Is(err, errGeometry) {
+		t.Errorf("expected error %v, got %v", errGeometry, err)
+	}
+}
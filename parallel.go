@@ -0,0 +1,244 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConvertParallel converts a PDF file to text like Convert, splitting the page
+// range into chunks that are converted by up to workers concurrent pdftotext
+// processes and stitched together in order. The page count is looked up with
+// pdfinfo unless the page range has a last page.
+func (c *Converter) ConvertParallel(ctx context.Context, inputPath string, workers int, opts *Options) (string, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	opts, err := runPreHooks(ctx, inputPath, opts)
+	if err != nil {
+		return "", err
+	}
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, opts)
+	if err != nil {
+		return "", err
+	}
+	chunkOpts := Options{}
+	result := &ConversionResult{InputPath: inputPath, OutputPath: "-"}
+	start := time.Now()
+	var warnings func() []Warning
+	if opts != nil {
+		chunkOpts = *opts
+	}
+	if opts != nil && len(opts.PostHooks) > 0 {
+		// the hooks are called once with the stitched text
+		var collecting *Options
+		collecting, warnings = collectWarnings(opts)
+		chunkOpts = *collecting
+		chunkOpts.PostHooks = nil
+	}
+	chunkOpts.Pages, chunkOpts.ExcludePages, chunkOpts.TailPages = "", "", 0
+	chunkOpts.PageSeparator = ""
+	if opts != nil && opts.PageSeparator != "" {
+		chunkOpts.NoPageBreaks = false
+	}
+
+	chunks, err := c.pageChunks(ctx, inputPath, rangeOpts, opts, func(first, last int) [][2]int {
+		return splitRange(first, last, workers)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	outputs := make([]bytes.Buffer, len(chunks))
+	err = runChunks(ctx, chunks, workers, func(ctx context.Context, i int, chunk [2]int) error {
+		o := chunkOpts
+		o.FirstPage, o.LastPage = chunk[0], chunk[1]
+		return c.run(ctx, inputPath, "-", &o, &outputs[i])
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// every page ends with its own page break, so the outputs concatenate
+	// exactly like the output of a single process
+	var b strings.Builder
+	if opts != nil && opts.PageSeparator != "" {
+		sw := &separatorWriter{w: &b, separator: opts.PageSeparator}
+		for i := range outputs {
+			sw.startRange(chunks[i][0])
+			sw.Write(outputs[i].Bytes())
+		}
+	} else {
+		for i := range outputs {
+			b.Write(outputs[i].Bytes())
+		}
+	}
+	if opts != nil && len(opts.PostHooks) > 0 {
+		result.Text, result.Warnings, result.Duration = b.String(), warnings(), time.Since(start)
+		if err := runPostHooks(ctx, opts.PostHooks, result); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(result.Text), nil
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// ConvertGeometryParallel returns the words of each page with their bounding
+// boxes like ConvertGeometry, converting every page with its own pdftotext
+// process, up to workers at a time, for large image-heavy documents whose
+// coordinate extraction is too slow in a single process. The pages are
+// merged in order and numbered as in the PDF file.
+func (c *Converter) ConvertGeometryParallel(ctx context.Context, inputPath string, workers int, opts *Options) ([]PageGeometry, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	bboxOpts := geometryOptions(opts, false)
+	pageOpts, pages, err := c.singlePages(ctx, inputPath, &bboxOpts)
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]PageGeometry, len(pages))
+	err = runChunks(ctx, pages, workers, func(ctx context.Context, i int, page [2]int) error {
+		var stdout bytes.Buffer
+		if err := c.run(ctx, inputPath, "-", pageOpts(page[0]), &stdout); err != nil {
+			return err
+		}
+		var err error
+		results[i], err = parseBBox(&stdout, page[0])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(results...), nil
+}
+
+// ConvertTSVParallel returns the rows of the -tsv output like ConvertTSV,
+// converting every page with its own pdftotext process, up to workers at a
+// time. The rows are merged in page order and numbered as in the PDF file.
+func (c *Converter) ConvertTSVParallel(ctx context.Context, inputPath string, workers int, opts *Options) ([]TSVRow, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	tsvOpts := tsvOptions(opts)
+	pageOpts, pages, err := c.singlePages(ctx, inputPath, &tsvOpts)
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]TSVRow, len(pages))
+	err = runChunks(ctx, pages, workers, func(ctx context.Context, i int, page [2]int) error {
+		var stdout bytes.Buffer
+		if err := c.run(ctx, inputPath, "-", pageOpts(page[0]), &stdout); err != nil {
+			return err
+		}
+		var err error
+		results[i], err = parseTSV(&stdout, page[0])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(results...), nil
+}
+
+// singlePages runs the pre hooks of opts and returns the pages they select,
+// as chunks of one page, and a function returning the options converting
+// one of them
+func (c *Converter) singlePages(ctx context.Context, inputPath string, opts *Options) (func(page int) *Options, [][2]int, error) {
+	hookOpts, err := runPreHooks(ctx, inputPath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, hookOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	pages, err := c.pageChunks(ctx, inputPath, rangeOpts, hookOpts, func(first, last int) [][2]int {
+		return splitRange(first, last, last-first+1)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	pageOpts := func(page int) *Options {
+		o := *hookOpts
+		o.Pages, o.ExcludePages, o.TailPages = "", "", 0
+		o.FirstPage, o.LastPage = page, page
+		return &o
+	}
+	return pageOpts, pages, nil
+}
+
+// pageChunks splits the page ranges of rangeOpts into chunks with split. The
+// page count is looked up with pdfinfo for ranges without a last page.
+func (c *Converter) pageChunks(ctx context.Context, inputPath string, rangeOpts []*Options, opts *Options, split func(first, last int) [][2]int) ([][2]int, error) {
+	var chunks [][2]int
+	pageCount := 0
+	for _, o := range rangeOpts {
+		first, last := 1, 0
+		if o != nil {
+			first, last = max(o.FirstPage, 1), o.LastPage
+		}
+		if last == 0 {
+			if pageCount == 0 {
+				var err error
+				if pageCount, err = c.PageCount(ctx, inputPath, opts); err != nil {
+					return nil, err
+				}
+			}
+			last = pageCount
+		}
+		if last < first {
+			return nil, fmt.Errorf("%w: first page %d is after last page %d", ErrInvalidRange, first, last)
+		}
+		chunks = append(chunks, split(first, last)...)
+	}
+	return chunks, nil
+}
+
+// runChunks calls fn for each chunk with up to workers calls running at a
+// time. The first failure cancels the context of the other calls and is
+// returned.
+func runChunks(ctx context.Context, chunks [][2]int, workers int, fn func(ctx context.Context, i int, chunk [2]int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, max(workers, 1))
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := fn(ctx, i, chunk); err != nil {
+				// report the failure that canceled the other chunks
+				once.Do(func() {
+					firstErr = fmt.Errorf("pages %d-%d: %w", chunk[0], chunk[1], err)
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// splitRange splits the pages first to last into at most n contiguous
+// chunks of nearly equal size
+func splitRange(first, last, n int) [][2]int {
+	total := last - first + 1
+	n = max(min(n, total), 1)
+
+	chunks := make([][2]int, 0, n)
+	for i := 0; i < n; i++ {
+		size := total / n
+		if i < total%n {
+			size++
+		}
+		chunks = append(chunks, [2]int{first, first + size - 1})
+		first += size
+	}
+	return chunks
+}
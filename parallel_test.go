@@ -0,0 +1,277 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// pagesRunner emulates pdfinfo and pdftotext for a document of n pages whose
+// page i reads "page i"
+func pagesRunner(n int, failPage int, runs *atomic.Int32) Runner {
+	return runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if name == "pdfinfo" {
+			_, err := fmt.Fprintf(stdout, "Pages:           %d\n", n)
+			return err
+		}
+		runs.Add(1)
+
+		first, last := 1, n
+		if v := flagValue(args, "-f"); v != "" {
+			first, _ = strconv.Atoi(v)
+		}
+		if v := flagValue(args, "-l"); v != "" {
+			last, _ = strconv.Atoi(v)
+		}
+		for i := first; i <= min(last, n); i++ {
+			if i == failPage {
+				io.WriteString(stderr, "Syntax Error: bad page")
+				return &ExitError{Code: 99}
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fmt.Fprintf(stdout, "page %d\n\f", i)
+		}
+		return nil
+	})
+}
+
+// flagValue returns the value following flag in args
+func flagValue(args []string, flag string) string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+func TestConverter_ConvertParallel(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         int
+		failPage      int
+		workers       int
+		options       *Options
+		expectedRuns  int32
+		expectedError error
+	}{
+		{name: "Whole document", pages: 10, workers: 3, expectedRuns: 3},
+		{name: "More workers than pages", pages: 2, workers: 8, expectedRuns: 2},
+		{name: "Page range", pages: 10, workers: 2, options: &Options{FirstPage: 3, LastPage: 6}, expectedRuns: 2},
+		{name: "Invalid range", pages: 10, workers: 2, options: &Options{FirstPage: 11}, expectedError: ErrInvalidRange},
+		{name: "Failing chunk", pages: 10, failPage: 9, workers: 3, expectedError: ErrCommandFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs atomic.Int32
+			converter, err := New(WithRunner(pagesRunner(tt.pages, tt.failPage, &runs)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			text, err := converter.ConvertParallel(context.Background(), "input.pdf", tt.workers, tt.options)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var sequentialRuns atomic.Int32
+			sequential, err := New(WithRunner(pagesRunner(tt.pages, 0, &sequentialRuns)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			expected, err := sequential.Convert(context.Background(), "input.pdf", tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if text != expected {
+				t.Errorf("expected %q, got %q", expected, text)
+			}
+			if runs.Load() != tt.expectedRuns {
+				t.Errorf("expected %d processes, got %d", tt.expectedRuns, runs.Load())
+			}
+		})
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	tests := []struct {
+		first, last, n int
+		expected       [][2]int
+	}{
+		{1, 10, 3, [][2]int{{1, 4}, {5, 7}, {8, 10}}},
+		{1, 2, 4, [][2]int{{1, 1}, {2, 2}}},
+		{5, 5, 1, [][2]int{{5, 5}}},
+		{1, 3, 0, [][2]int{{1, 3}}},
+	}
+
+	for _, tt := range tests {
+		if got := splitRange(tt.first, tt.last, tt.n); !slices.Equal(got, tt.expected) {
+			t.Errorf("splitRange(%d, %d, %d): expected %v, got %v", tt.first, tt.last, tt.n, tt.expected, got)
+		}
+	}
+}
+
+// coordinatesRunner emulates pdfinfo and pdftotext -bbox and -tsv for a
+// document of n pages whose page i has the word "wi" at x = i
+func coordinatesRunner(n int, runs *atomic.Int32) Runner {
+	return runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if name == "pdfinfo" {
+			_, err := fmt.Fprintf(stdout, "Pages:           %d\n", n)
+			return err
+		}
+		runs.Add(1)
+		first, _ := strconv.Atoi(flagValue(args, "-f"))
+		last, _ := strconv.Atoi(flagValue(args, "-l"))
+		if slices.Contains(args, "-tsv") {
+			io.WriteString(stdout, "level\tpage_num\tpar_num\tblock_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n")
+			for i := first; i <= last; i++ {
+				// pdftotext numbers the pages of every range from 1
+				fmt.Fprintf(stdout, "1\t%d\t0\t0\t0\t0\t0\t0\t612\t792\t-1\t###PAGE###\n", i-first+1)
+				fmt.Fprintf(stdout, "5\t%d\t0\t0\t0\t0\t%d\t10\t5\t10\t100\tw%d\n", i-first+1, i, i)
+			}
+			return nil
+		}
+		io.WriteString(stdout, "<html><body><doc>\n")
+		for i := first; i <= last; i++ {
+			fmt.Fprintf(stdout, "<page width=\"612\" height=\"792\"><word xMin=\"%d\" yMin=\"10\" xMax=\"%d\" yMax=\"20\">w%d</word></page>\n", i, i+5, i)
+		}
+		io.WriteString(stdout, "</doc></body></html>\n")
+		return nil
+	})
+}
+
+func TestConverter_ConvertParallel_Overrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		override func(*Options)
+		expected string
+	}{
+		{
+			name:     "First page",
+			override: func(o *Options) { o.FirstPage = 3 },
+			expected: "page 3\n\fpage 4\n\fpage 5\n\fpage 6\n\fpage 7\n\fpage 8\n\fpage 9\n\fpage 10",
+		},
+		{
+			name:     "Pages",
+			override: func(o *Options) { o.Pages = "2-3" },
+			expected: "page 2\n\fpage 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs atomic.Int32
+			converter, err := New(WithRunner(pagesRunner(10, 0, &runs)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			ctx := ContextWithOverrides(context.Background(), tt.override)
+
+			text, err := converter.ConvertParallel(ctx, "input.pdf", 4, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+			sequential, err := converter.Convert(ctx, "input.pdf", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != sequential {
+				t.Errorf("expected the text of Convert %q, got %q", sequential, text)
+			}
+		})
+	}
+}
+
+func TestConverter_CoordinatesParallel(t *testing.T) {
+	for _, opts := range []*Options{nil, {Pages: "2-3,5"}} {
+		wantPages := []int{1, 2, 3, 4, 5}
+		if opts != nil {
+			wantPages = []int{2, 3, 5}
+		}
+		var runs atomic.Int32
+		converter, err := New(WithRunner(coordinatesRunner(5, &runs)))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+
+		geometry, err := converter.ConvertGeometryParallel(context.Background(), "input.pdf", 3, opts)
+		if err != nil {
+			t.Fatalf("ConvertGeometryParallel: %v", err)
+		}
+		if int(runs.Load()) != len(wantPages) {
+			t.Errorf("expected one pdftotext run per page, got %d", runs.Load())
+		}
+		var numbers []int
+		for _, page := range geometry {
+			numbers = append(numbers, page.Number)
+			if len(page.Words) != 1 || page.Words[0].Text != "w"+strconv.Itoa(page.Number) || page.Words[0].Box.XMin != float64(page.Number) {
+				t.Errorf("unexpected words of page %d: %+v", page.Number, page.Words)
+			}
+		}
+		if !slices.Equal(numbers, wantPages) {
+			t.Errorf("expected pages %v, got %v", wantPages, numbers)
+		}
+
+		rows, err := converter.ConvertTSVParallel(context.Background(), "input.pdf", 3, opts)
+		if err != nil {
+			t.Fatalf("ConvertTSVParallel: %v", err)
+		}
+		numbers = numbers[:0]
+		for _, row := range rows {
+			if row.Level == TSVWord {
+				numbers = append(numbers, row.Page)
+				if row.Text != "w"+strconv.Itoa(row.Page) {
+					t.Errorf("expected the word of page %d, got %q", row.Page, row.Text)
+				}
+			}
+		}
+		if !slices.Equal(numbers, wantPages) {
+			t.Errorf("expected TSV pages %v, got %v", wantPages, numbers)
+		}
+	}
+}
+
+func TestConverter_ConvertTSVParallel_Overrides(t *testing.T) {
+	var runs atomic.Int32
+	converter, err := New(WithRunner(coordinatesRunner(5, &runs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	for _, override := range []func(*Options){
+		func(o *Options) { o.FirstPage = 3 },
+		func(o *Options) { o.Pages = "3-5" },
+	} {
+		ctx := ContextWithOverrides(context.Background(), override)
+		rows, err := converter.ConvertTSVParallel(ctx, "input.pdf", 2, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var numbers []int
+		for _, row := range rows {
+			if row.Level == TSVWord {
+				numbers = append(numbers, row.Page)
+			}
+		}
+		if expected := []int{3, 4, 5}; !slices.Equal(numbers, expected) {
+			t.Errorf("expected TSV pages %v, got %v", expected, numbers)
+		}
+	}
+}
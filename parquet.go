@@ -0,0 +1,332 @@
+package pdftotext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportWordsParquet converts a PDF file with ConvertGeometry and writes one
+// row per word to a Parquet file below dir, partitioned by document in the
+// Hive layout read by DuckDB and Spark:
+//
+//	dir/document=report.pdf/words-3f2a9c1d.parquet
+//
+// Files with the same name in different directories share a partition but
+// get their own file. The file is written atomically and its path returned.
+func (c *Converter) ExportWordsParquet(ctx context.Context, inputPath, dir string, opts *Options) (string, error) {
+	pages, err := c.ConvertGeometry(ctx, inputPath, opts)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(inputPath))
+	partition := filepath.Join(dir, "document="+escapePartition(filepath.Base(inputPath)))
+	if err := os.MkdirAll(partition, 0o755); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+	name := filepath.Join(partition, "words-"+hex.EncodeToString(sum[:4])+".parquet")
+
+	f, err := os.CreateTemp(partition, tempPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+	defer os.Remove(f.Name())
+	if err := WriteWordsParquet(f, inputPath, pages); err != nil {
+		f.Close()
+		return "", fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOutputFile, err)
+	}
+	return name, nil
+}
+
+// escapePartition escapes the characters Hive escapes in partition values
+func escapePartition(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c == 0x7f || strings.IndexByte("\"#%'*/:=?\\{[]^", c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// WriteWordsParquet writes the words of pages to w as a Parquet file with one
+// row per word and the columns
+//
+//	path, page, word, text, x_min, y_min, x_max, y_max, page_width, page_height
+//
+// where word is the position of the word on its page, counted from 0. The
+// columns are GZIP compressed and written as a single row group.
+func WriteWordsParquet(w io.Writer, path string, pages []PageGeometry) error {
+	var rows int
+	for _, page := range pages {
+		rows += len(page.Words)
+	}
+	if rows > math.MaxInt32 {
+		return fmt.Errorf("too many words for one row group: %d", rows)
+	}
+
+	columns := []parquetColumn{
+		{name: "path", typ: parquetByteArray},
+		{name: "page", typ: parquetInt32},
+		{name: "word", typ: parquetInt32},
+		{name: "text", typ: parquetByteArray},
+		{name: "x_min", typ: parquetDouble},
+		{name: "y_min", typ: parquetDouble},
+		{name: "x_max", typ: parquetDouble},
+		{name: "y_max", typ: parquetDouble},
+		{name: "page_width", typ: parquetDouble},
+		{name: "page_height", typ: parquetDouble},
+	}
+	for _, page := range pages {
+		for i, word := range page.Words {
+			columns[0].appendString(path)
+			columns[1].appendInt32(int32(page.Number))
+			columns[2].appendInt32(int32(i))
+			columns[3].appendString(word.Text)
+			columns[4].appendDouble(word.Box.XMin)
+			columns[5].appendDouble(word.Box.YMin)
+			columns[6].appendDouble(word.Box.XMax)
+			columns[7].appendDouble(word.Box.YMax)
+			columns[8].appendDouble(page.Width)
+			columns[9].appendDouble(page.Height)
+		}
+	}
+	return writeParquet(w, columns, rows)
+}
+
+// Parquet physical types
+const (
+	parquetInt32     = 1
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// Parquet enum values used by writeParquet
+const (
+	parquetRequired     = 0
+	parquetConvertedUTF = 0
+	parquetEncodingRLE  = 3
+	parquetCodecGzip    = 2
+	parquetDataPage     = 0
+)
+
+// parquetColumn is a required column and its PLAIN encoded values
+type parquetColumn struct {
+	name   string
+	typ    int32
+	values []byte
+}
+
+func (c *parquetColumn) appendString(s string) {
+	c.values = binary.LittleEndian.AppendUint32(c.values, uint32(len(s)))
+	c.values = append(c.values, s...)
+}
+
+func (c *parquetColumn) appendInt32(v int32) {
+	c.values = binary.LittleEndian.AppendUint32(c.values, uint32(v))
+}
+
+func (c *parquetColumn) appendDouble(v float64) {
+	c.values = binary.LittleEndian.AppendUint64(c.values, math.Float64bits(v))
+}
+
+// writeParquet writes a Parquet file with one row group holding one data
+// page per column. All columns are required, so the pages hold no
+// repetition or definition levels.
+func writeParquet(w io.Writer, columns []parquetColumn, rows int) error {
+	cw := &countingWriter{w: w}
+	if _, err := io.WriteString(cw, "PAR1"); err != nil {
+		return err
+	}
+
+	type chunk struct {
+		offset, size, uncompressed int64
+	}
+	chunks := make([]chunk, len(columns))
+	var total int64
+	for i, col := range columns {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(col.values); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		if len(col.values) > math.MaxInt32 || compressed.Len() > math.MaxInt32 {
+			return fmt.Errorf("column %s is too large for one page", col.name)
+		}
+
+		header := newCompactWriter()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(col.values)))
+		header.i32(3, int32(compressed.Len()))
+		header.structField(5, func() {
+			header.i32(1, int32(rows))
+			header.i32(2, 0) // PLAIN
+			header.i32(3, parquetEncodingRLE)
+			header.i32(4, parquetEncodingRLE)
+		})
+		header.stop()
+
+		chunks[i].offset = cw.n
+		if _, err := cw.Write(header.b); err != nil {
+			return err
+		}
+		if _, err := cw.Write(compressed.Bytes()); err != nil {
+			return err
+		}
+		chunks[i].size = cw.n - chunks[i].offset
+		chunks[i].uncompressed = int64(len(header.b) + len(col.values))
+		total += chunks[i].uncompressed
+	}
+
+	meta := newCompactWriter()
+	meta.i32(1, 1)
+	meta.list(2, compactStruct, len(columns)+1, func(i int) {
+		meta.structElem(func() {
+			if i == 0 {
+				meta.binary(4, "schema")
+				meta.i32(5, int32(len(columns)))
+				return
+			}
+			col := columns[i-1]
+			meta.i32(1, col.typ)
+			meta.i32(3, parquetRequired)
+			meta.binary(4, col.name)
+			if col.typ == parquetByteArray {
+				meta.i32(6, parquetConvertedUTF)
+			}
+		})
+	})
+	meta.i64(3, int64(rows))
+	meta.list(4, compactStruct, 1, func(int) {
+		meta.structElem(func() {
+			meta.list(1, compactStruct, len(columns), func(i int) {
+				meta.structElem(func() {
+					meta.i64(2, chunks[i].offset)
+					meta.structField(3, func() {
+						meta.i32(1, columns[i].typ)
+						meta.list(2, compactI32, 2, func(j int) { meta.varint(int64([]int{0, parquetEncodingRLE}[j])) })
+						meta.list(3, compactBinary, 1, func(int) { meta.bytes(columns[i].name) })
+						meta.i32(4, parquetCodecGzip)
+						meta.i64(5, int64(rows))
+						meta.i64(6, chunks[i].uncompressed)
+						meta.i64(7, chunks[i].size)
+						meta.i64(9, chunks[i].offset)
+					})
+				})
+			})
+			meta.i64(2, total)
+			meta.i64(3, int64(rows))
+		})
+	})
+	meta.binary(6, "github.com/joeychilson/pdftotext")
+	meta.stop()
+
+	if _, err := cw.Write(meta.b); err != nil {
+		return err
+	}
+	footer := binary.LittleEndian.AppendUint32(nil, uint32(len(meta.b)))
+	_, err := cw.Write(append(footer, "PAR1"...))
+	return err
+}
+
+// Thrift compact protocol types
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes the Thrift compact protocol used by Parquet metadata
+type compactWriter struct {
+	b []byte
+	// last holds the last field id of each open struct
+	last []int16
+}
+
+func newCompactWriter() *compactWriter {
+	return &compactWriter{last: []int16{0}}
+}
+
+func (w *compactWriter) field(id int16, typ byte) {
+	last := &w.last[len(w.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.b = append(w.b, byte(delta)<<4|typ)
+	} else {
+		w.b = append(w.b, typ)
+		w.b = binary.AppendVarint(w.b, int64(id))
+	}
+	*last = id
+}
+
+func (w *compactWriter) varint(v int64) { w.b = binary.AppendVarint(w.b, v) }
+
+func (w *compactWriter) bytes(s string) {
+	w.b = binary.AppendUvarint(w.b, uint64(len(s)))
+	w.b = append(w.b, s...)
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, compactI32)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, compactI64)
+	w.varint(v)
+}
+
+func (w *compactWriter) binary(id int16, s string) {
+	w.field(id, compactBinary)
+	w.bytes(s)
+}
+
+func (w *compactWriter) list(id int16, elem byte, n int, fn func(i int)) {
+	w.field(id, compactList)
+	if n < 15 {
+		w.b = append(w.b, byte(n)<<4|elem)
+	} else {
+		w.b = append(w.b, 0xf0|elem)
+		w.b = binary.AppendUvarint(w.b, uint64(n))
+	}
+	for i := 0; i < n; i++ {
+		fn(i)
+	}
+}
+
+func (w *compactWriter) structField(id int16, fn func()) {
+	w.field(id, compactStruct)
+	w.structElem(fn)
+}
+
+func (w *compactWriter) structElem(fn func()) {
+	w.last = append(w.last, 0)
+	fn()
+	w.stop()
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *compactWriter) stop() { w.b = append(w.b, 0) }
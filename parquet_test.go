@@ -0,0 +1,229 @@
+package pdftotext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// compactReader decodes the Thrift compact protocol into maps of field ids,
+// lists, int64 and string values
+type compactReader struct {
+	r *bytes.Reader
+}
+
+func (c *compactReader) readStruct() (map[int16]any, error) {
+	fields := map[int16]any{}
+	var last int16
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == 0 {
+			return fields, nil
+		}
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := binary.ReadVarint(c.r)
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		if fields[id], err = c.readValue(b & 0x0f); err != nil {
+			return nil, err
+		}
+		last = id
+	}
+}
+
+func (c *compactReader) readValue(typ byte) (any, error) {
+	switch typ {
+	case compactI32, compactI64:
+		return binary.ReadVarint(c.r)
+	case compactBinary:
+		n, err := binary.ReadUvarint(c.r)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(c.r, b)
+		return string(b), err
+	case compactList:
+		h, err := c.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		n := uint64(h >> 4)
+		if n == 15 {
+			if n, err = binary.ReadUvarint(c.r); err != nil {
+				return nil, err
+			}
+		}
+		list := make([]any, n)
+		for i := range list {
+			if list[i], err = c.readValue(h & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case compactStruct:
+		return c.readStruct()
+	}
+	return nil, errors.New("unsupported type")
+}
+
+// readParquet decodes a file written by writeParquet into its column names
+// and raw PLAIN values
+func readParquet(t *testing.T, data []byte) (rows int64, names []string, values [][]byte) {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("missing Parquet magic")
+	}
+	size := binary.LittleEndian.Uint32(data[len(data)-8:])
+	footer := data[len(data)-8-int(size) : len(data)-8]
+	meta, err := (&compactReader{bytes.NewReader(footer)}).readStruct()
+	if err != nil {
+		t.Fatalf("failed to decode footer: %v", err)
+	}
+
+	rows = meta[3].(int64)
+	group := meta[4].([]any)[0].(map[int16]any)
+	for i, element := range meta[2].([]any)[1:] {
+		names = append(names, element.(map[int16]any)[4].(string))
+
+		chunk := group[1].([]any)[i].(map[int16]any)[3].(map[int16]any)
+		offset := chunk[9].(int64)
+		r := &compactReader{bytes.NewReader(data[offset:])}
+		header, err := r.readStruct()
+		if err != nil {
+			t.Fatalf("failed to decode page header: %v", err)
+		}
+		start := offset + int64(len(data[offset:])-r.r.Len())
+		zr, err := gzip.NewReader(bytes.NewReader(data[start : start+header[3].(int64)]))
+		if err != nil {
+			t.Fatalf("failed to decompress column %d: %v", i, err)
+		}
+		v, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("failed to decompress column %d: %v", i, err)
+		}
+		if int64(len(v)) != header[2].(int64) {
+			t.Errorf("expected %d uncompressed bytes, got %d", header[2], len(v))
+		}
+		values = append(values, v)
+	}
+	return rows, names, values
+}
+
+func TestWriteWordsParquet(t *testing.T) {
+	pages := []PageGeometry{
+		{Number: 1, Width: 612, Height: 792, Words: []Word{
+			{Text: "Hello", Box: Rect{XMin: 10, YMin: 20, XMax: 40, YMax: 32}},
+			{Text: "world", Box: Rect{XMin: 45, YMin: 20, XMax: 80, YMax: 32}},
+		}},
+		{Number: 2, Width: 612, Height: 792},
+		{Number: 3, Width: 595, Height: 842, Words: []Word{
+			{Text: "Größe", Box: Rect{XMin: 1.5, YMin: 2.5, XMax: 3.5, YMax: 4.5}},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := WriteWordsParquet(&buf, "docs/report.pdf", pages); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, names, values := readParquet(t, buf.Bytes())
+	if rows != 3 {
+		t.Errorf("expected 3 rows, got %d", rows)
+	}
+	expectedNames := []string{"path", "page", "word", "text", "x_min", "y_min", "x_max", "y_max", "page_width", "page_height"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("expected columns %v, got %v", expectedNames, names)
+	}
+
+	var texts []string
+	for b := values[3]; len(b) > 0; {
+		n := binary.LittleEndian.Uint32(b)
+		texts = append(texts, string(b[4:4+n]))
+		b = b[4+n:]
+	}
+	if expected := []string{"Hello", "world", "Größe"}; !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected texts %v, got %v", expected, texts)
+	}
+
+	var numbers []uint32
+	for i := 0; i < len(values[1]); i += 4 {
+		numbers = append(numbers, binary.LittleEndian.Uint32(values[1][i:]), binary.LittleEndian.Uint32(values[2][i:]))
+	}
+	if expected := []uint32{1, 0, 1, 1, 3, 0}; !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("expected page and word numbers %v, got %v", expected, numbers)
+	}
+
+	if xMax := math.Float64frombits(binary.LittleEndian.Uint64(values[6][16:])); xMax != 3.5 {
+		t.Errorf("expected x_max 3.5, got %v", xMax)
+	}
+	if height := math.Float64frombits(binary.LittleEndian.Uint64(values[9][16:])); height != 842 {
+		t.Errorf("expected page_height 842, got %v", height)
+	}
+}
+
+func TestConverter_ExportWordsParquet(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, bboxOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	dir := t.TempDir()
+
+	name, err := converter.ExportWordsParquet(context.Background(), "in/q3: report.pdf", dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	partition := filepath.Join(dir, "document=q3%3A report.pdf")
+	if filepath.Dir(name) != partition || !strings.HasPrefix(filepath.Base(name), "words-") {
+		t.Errorf("expected a words file in %s, got %s", partition, name)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	if rows, _, _ := readParquet(t, data); rows == 0 {
+		t.Error("expected rows, got none")
+	}
+
+	entries, err := os.ReadDir(partition)
+	if err != nil {
+		t.Fatalf("failed to read partition: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the export in the partition, got %d files", len(entries))
+	}
+}
+
+func TestEscapePartition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"a=b/c.pdf", "a%3Db%2Fc.pdf"},
+		{"50% off?.pdf", "50%25 off%3F.pdf"},
+	}
+	for _, tt := range tests {
+		if got := escapePartition(tt.input); got != tt.expected {
+			t.Errorf("escapePartition(%q): expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
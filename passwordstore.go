@@ -0,0 +1,72 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+)
+
+// DocumentRef identifies a document whose passwords are looked up in a
+// PasswordStore
+type DocumentRef struct {
+	// Path is the input path of the conversion, a temporary file for
+	// ConvertReader
+	Path string
+	// SHA256 is the hex-encoded SHA-256 digest of the file, which identifies
+	// the document wherever it is stored
+	SHA256 string
+}
+
+// Credentials are the passwords of an encrypted document
+type Credentials struct {
+	// User is the user password
+	User string `json:"user,omitempty"`
+	// Owner is the owner password
+	Owner string `json:"owner,omitempty"`
+}
+
+// PasswordStore looks up the passwords of encrypted documents, so corpora
+// with per-document passwords can be converted with credentials kept in a
+// secrets manager such as Vault or a KMS instead of in the options. Lookup
+// returns ErrNotFound for documents it has no passwords for.
+type PasswordStore interface {
+	Lookup(ctx context.Context, doc DocumentRef) (Credentials, error)
+}
+
+// PasswordStoreFunc adapts a function to a PasswordStore
+type PasswordStoreFunc func(ctx context.Context, doc DocumentRef) (Credentials, error)
+
+// Lookup calls f
+func (f PasswordStoreFunc) Lookup(ctx context.Context, doc DocumentRef) (Credentials, error) {
+	return f(ctx, doc)
+}
+
+// MapPasswordStore is a PasswordStore holding the passwords of documents by
+// their SHA-256 digest or their path, for tests and small corpora
+type MapPasswordStore map[string]Credentials
+
+// Lookup returns the passwords stored under the digest of doc, or else under
+// its path
+func (m MapPasswordStore) Lookup(ctx context.Context, doc DocumentRef) (Credentials, error) {
+	if creds, ok := m[doc.SHA256]; ok {
+		return creds, nil
+	}
+	if creds, ok := m[doc.Path]; ok {
+		return creds, nil
+	}
+	return Credentials{}, fmt.Errorf("%w: passwords of %s", ErrNotFound, doc.Path)
+}
+
+// lookupPasswords returns the passwords of an encrypted input from
+// opts.PasswordStore, hashing the file to identify it, or else from
+// opts.PasswordFunc
+func lookupPasswords(ctx context.Context, inputPath string, opts *Options) (Credentials, error) {
+	if opts.PasswordStore == nil {
+		user, owner, err := opts.PasswordFunc(inputPath)
+		return Credentials{User: user, Owner: owner}, err
+	}
+	artifact, err := hashFile(inputPath, SHA256)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return opts.PasswordStore.Lookup(ctx, DocumentRef{Path: inputPath, SHA256: artifact.Digest})
+}
@@ -0,0 +1,72 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPasswordStore(t *testing.T) {
+	dir := t.TempDir()
+	passwords := map[string]string{"a.pdf": "alpha", "b.pdf": "beta", "c.pdf": "gamma"}
+	var inputs []string
+	for name := range passwords {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("%PDF-1.7 "+name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, path)
+	}
+	slices.Sort(inputs)
+
+	// The fake unlocks each file with its own user password
+	runner := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		input := args[len(args)-2]
+		if i := slices.Index(args, "-upw"); i >= 0 && args[i+1] == passwords[filepath.Base(input)] {
+			io.WriteString(stdout, filepath.Base(input))
+			return nil
+		}
+		io.WriteString(stderr, "Command Line Error: Incorrect password\n")
+		return &ExitError{Code: 1}
+	})
+	converter, err := New(WithRunner(runner))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	digest := func(path string) string {
+		artifact, err := hashFile(path, SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return artifact.Digest
+	}
+	store := MapPasswordStore{
+		digest(inputs[0]): {User: "alpha"},
+		inputs[1]:         {User: "beta"},
+	}
+	var lookups []DocumentRef
+	opts := &Options{PasswordStore: PasswordStoreFunc(func(ctx context.Context, doc DocumentRef) (Credentials, error) {
+		lookups = append(lookups, doc)
+		return store.Lookup(ctx, doc)
+	})}
+
+	results := converter.ConvertBatch(context.Background(), inputs, 1, opts)
+	for i, want := range []string{"a.pdf", "b.pdf"} {
+		if results[i].Err != nil || results[i].Text != want {
+			t.Errorf("expected %s to be unlocked, got %q, %v", want, results[i].Text, results[i].Err)
+		}
+	}
+	if err := results[2].Err; !errors.Is(err, ErrEncrypted) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrEncrypted and ErrNotFound for the unknown document, got %v", err)
+	}
+	slices.SortFunc(lookups, func(a, b DocumentRef) int { return cmp.Compare(a.Path, b.Path) })
+	if len(lookups) != 3 || lookups[0].SHA256 != digest(inputs[0]) || lookups[1].Path != inputs[1] {
+		t.Errorf("unexpected lookups %+v", lookups)
+	}
+}
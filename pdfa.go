@@ -0,0 +1,66 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PDFAConformance is the PDF/A conformance a document claims, see ISO 19005
+type PDFAConformance struct {
+	// Part is the part of the standard, 1 to 4
+	Part int `json:"part"`
+	// Level is the conformance level, "A", "B" or "U", or for PDF/A-4 empty
+	// or the profile, "E" or "F"
+	Level string `json:"level,omitempty"`
+}
+
+// String formats the conformance like "PDF/A-2b"
+func (c PDFAConformance) String() string {
+	return "PDF/A-" + strconv.Itoa(c.Part) + strings.ToLower(c.Level)
+}
+
+// parsePDFASubtype parses the PDF subtype printed by pdfinfo, e.g.
+// "PDF/A-1b", returning nil for other subtypes
+func parsePDFASubtype(subtype string) *PDFAConformance {
+	rest, ok := strings.CutPrefix(subtype, "PDF/A-")
+	if !ok || rest == "" || rest[0] < '1' || rest[0] > '9' {
+		return nil
+	}
+	return &PDFAConformance{Part: int(rest[0] - '0'), Level: strings.ToUpper(rest[1:])}
+}
+
+var (
+	xmpPDFAPart        = regexp.MustCompile(`pdfaid:part(?:\s*=\s*["']|>)\s*(\d)`)
+	xmpPDFAConformance = regexp.MustCompile(`pdfaid:conformance(?:\s*=\s*["']|>)\s*([A-Za-z])`)
+)
+
+// parseXMPConformance reads the PDF/A identification schema of XMP metadata,
+// whose properties are written as attributes or as elements
+func parseXMPConformance(xmp string) *PDFAConformance {
+	part := xmpPDFAPart.FindStringSubmatch(xmp)
+	if part == nil {
+		return nil
+	}
+	c := &PDFAConformance{Part: int(part[1][0] - '0')}
+	if level := xmpPDFAConformance.FindStringSubmatch(xmp); level != nil {
+		c.Level = strings.ToUpper(level[1])
+	}
+	return c
+}
+
+// xmpConformance reads the PDF/A conformance from the XMP metadata printed
+// by pdfinfo -meta, for pdfinfo versions that do not report the PDF subtype
+func (c *Converter) xmpConformance(ctx context.Context, inputPath string, opts *Options) (*PDFAConformance, error) {
+	args := append([]string{"-meta"}, passwordArgs(opts)...)
+	args = append(args, escapePath(inputPath))
+
+	var stdout bytes.Buffer
+	stderr := c.newStderr()
+	if err := c.runCommand(ctx, c.toolPath("pdfinfo"), args, &stdout, stderr); err != nil {
+		return nil, c.handleError(err, stderr.String())
+	}
+	return parseXMPConformance(stdout.String()), nil
+}
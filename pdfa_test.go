@@ -0,0 +1,128 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+const pdfinfoPDFAOutput = `Title:           Annual Report
+Tagged:          yes
+Pages:           2
+Encrypted:       no
+PDF version:     1.4
+PDF subtype:     PDF/A-1b
+    Title:         ISO 19005 - Electronic document file format for long-term preservation (PDF/A)
+    Abbreviation:  PDF/A-1b
+    Subtitle:      Use of PDF 1.4
+    Standard:      ISO 19005-1
+    Conformance:   Level B, basic conformance
+`
+
+func TestParseInfo_PDFSubtype(t *testing.T) {
+	info := parseInfo(pdfinfoPDFAOutput)
+	if info.Title != "Annual Report" || info.Subtype != "PDF/A-1b" {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if expected := (&PDFAConformance{Part: 1, Level: "B"}); !reflect.DeepEqual(info.PDFA, expected) {
+		t.Errorf("expected %+v, got %+v", expected, info.PDFA)
+	}
+	if info.Raw["PDF subtype Conformance"] != "Level B, basic conformance" {
+		t.Errorf("expected the subtype details in Raw, got %v", info.Raw)
+	}
+
+	if info := parseInfo("PDF subtype:     None\n"); info.Subtype != "" || info.PDFA != nil {
+		t.Errorf("expected no subtype, got %+v", info)
+	}
+}
+
+func TestParsePDFASubtype(t *testing.T) {
+	tests := []struct {
+		subtype  string
+		expected *PDFAConformance
+	}{
+		{"PDF/A-1b", &PDFAConformance{Part: 1, Level: "B"}},
+		{"PDF/A-2u", &PDFAConformance{Part: 2, Level: "U"}},
+		{"PDF/A-3a", &PDFAConformance{Part: 3, Level: "A"}},
+		{"PDF/A-4", &PDFAConformance{Part: 4}},
+		{"PDF/A-4f", &PDFAConformance{Part: 4, Level: "F"}},
+		{"PDF/UA-1", nil},
+		{"PDF/X-4", nil},
+		{"PDF/A-", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subtype, func(t *testing.T) {
+			got := parsePDFASubtype(tt.subtype)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+			if got != nil && got.String() != tt.subtype {
+				t.Errorf("expected %q, got %q", tt.subtype, got.String())
+			}
+		})
+	}
+}
+
+func TestParseXMPConformance(t *testing.T) {
+	tests := []struct {
+		name     string
+		xmp      string
+		expected *PDFAConformance
+	}{
+		{
+			name:     "Attributes",
+			xmp:      `<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/" pdfaid:part="2" pdfaid:conformance="B"/>`,
+			expected: &PDFAConformance{Part: 2, Level: "B"},
+		},
+		{
+			name:     "Elements",
+			xmp:      `<rdf:Description rdf:about=""><pdfaid:part>1</pdfaid:part><pdfaid:conformance>a</pdfaid:conformance></rdf:Description>`,
+			expected: &PDFAConformance{Part: 1, Level: "A"},
+		},
+		{
+			name:     "PDF/A-4",
+			xmp:      `<rdf:Description pdfaid:part='4' pdfaid:rev="2020"/>`,
+			expected: &PDFAConformance{Part: 4},
+		},
+		{
+			name: "Not PDF/A",
+			xmp:  `<rdf:Description xmlns:dc="http://purl.org/dc/elements/1.1/"/>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseXMPConformance(tt.xmp); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConverter_Info_XMPConformance(t *testing.T) {
+	var runs [][]string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		runs = append(runs, args)
+		if slices.Contains(args, "-meta") {
+			_, err := io.WriteString(stdout, `<x:xmpmeta><rdf:Description pdfaid:part="3" pdfaid:conformance="U"/></x:xmpmeta>`)
+			return err
+		}
+		_, err := io.WriteString(stdout, "Pages:           1\nMetadata:        yes\nPDF version:     1.7\n")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	info, err := converter.Info(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Subtype != "PDF/A-3u" || !reflect.DeepEqual(info.PDFA, &PDFAConformance{Part: 3, Level: "U"}) {
+		t.Errorf("expected PDF/A-3u from the XMP metadata, got %q %+v", info.Subtype, info.PDFA)
+	}
+	if len(runs) != 2 {
+		t.Errorf("expected 2 runs, got %v", runs)
+	}
+}
@@ -0,0 +1,140 @@
+// Package pdfarrow exposes the word geometry and TSV layout rows extracted by
+// pdftotext as Apache Arrow record batches, so query engines running in the
+// same process can consume them without serialization. It is a separate
+// module so the pdftotext package does not depend on Arrow.
+//
+// The columns match those written by pdftotext.WriteWordsParquet:
+//
+//	rec, err := pdfarrow.ConvertGeometry(ctx, converter, nil, "report.pdf", nil)
+//	if err != nil {
+//		return err
+//	}
+//	defer rec.Release()
+package pdfarrow
+
+import (
+	"context"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// GeometrySchema is the schema of records built by GeometryRecord, with one
+// row per word
+var GeometrySchema = arrow.NewSchema([]arrow.Field{
+	{Name: "path", Type: arrow.BinaryTypes.String},
+	{Name: "page", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "word", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "text", Type: arrow.BinaryTypes.String},
+	{Name: "x_min", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "y_min", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "x_max", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "y_max", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "page_width", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "page_height", Type: arrow.PrimitiveTypes.Float64},
+}, nil)
+
+// TSVSchema is the schema of records built by TSVRecord, with one row per
+// page, flow, line and word
+var TSVSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "path", Type: arrow.BinaryTypes.String},
+	{Name: "level", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "page", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "paragraph", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "block", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "line", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "word", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "x_min", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "y_min", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "x_max", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "y_max", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "confidence", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "text", Type: arrow.BinaryTypes.String},
+}, nil)
+
+// ConvertGeometry converts a PDF file with Converter.ConvertGeometry and
+// returns its words as a record. A nil allocator uses the default allocator.
+// The caller must release the record.
+func ConvertGeometry(ctx context.Context, c *pdftotext.Converter, mem memory.Allocator, inputPath string, opts *pdftotext.Options) (arrow.Record, error) {
+	pages, err := c.ConvertGeometry(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return GeometryRecord(mem, inputPath, pages), nil
+}
+
+// ConvertTSV converts a PDF file with Converter.ConvertTSV and returns its
+// rows as a record. A nil allocator uses the default allocator. The caller
+// must release the record.
+func ConvertTSV(ctx context.Context, c *pdftotext.Converter, mem memory.Allocator, inputPath string, opts *pdftotext.Options) (arrow.Record, error) {
+	rows, err := c.ConvertTSV(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return TSVRecord(mem, inputPath, rows), nil
+}
+
+// GeometryRecord builds a record with a row for each word of pages. The
+// caller must release the record.
+func GeometryRecord(mem memory.Allocator, path string, pages []pdftotext.PageGeometry) arrow.Record {
+	b := array.NewRecordBuilder(allocator(mem), GeometrySchema)
+	defer b.Release()
+
+	var n int
+	for _, page := range pages {
+		n += len(page.Words)
+	}
+	b.Reserve(n)
+
+	paths := b.Field(0).(*array.StringBuilder)
+	numbers := b.Field(1).(*array.Int32Builder)
+	words := b.Field(2).(*array.Int32Builder)
+	texts := b.Field(3).(*array.StringBuilder)
+	for _, page := range pages {
+		for i, word := range page.Words {
+			paths.Append(path)
+			numbers.Append(int32(page.Number))
+			words.Append(int32(i))
+			texts.Append(word.Text)
+			appendFloats(b, 4, word.Box.XMin, word.Box.YMin, word.Box.XMax, word.Box.YMax, page.Width, page.Height)
+		}
+	}
+	return b.NewRecord()
+}
+
+// TSVRecord builds a record with a row for each TSV row. The caller must
+// release the record.
+func TSVRecord(mem memory.Allocator, path string, rows []pdftotext.TSVRow) arrow.Record {
+	b := array.NewRecordBuilder(allocator(mem), TSVSchema)
+	defer b.Release()
+	b.Reserve(len(rows))
+
+	paths := b.Field(0).(*array.StringBuilder)
+	texts := b.Field(12).(*array.StringBuilder)
+	for _, row := range rows {
+		paths.Append(path)
+		for i, v := range []int{int(row.Level), row.Page, row.Paragraph, row.Block, row.Line, row.Word} {
+			b.Field(1 + i).(*array.Int32Builder).Append(int32(v))
+		}
+		appendFloats(b, 7, row.Box.XMin, row.Box.YMin, row.Box.XMax, row.Box.YMax, row.Confidence)
+		texts.Append(row.Text)
+	}
+	return b.NewRecord()
+}
+
+// appendFloats appends values to the float64 fields starting at field first
+func appendFloats(b *array.RecordBuilder, first int, values ...float64) {
+	for i, v := range values {
+		b.Field(first + i).(*array.Float64Builder).Append(v)
+	}
+}
+
+func allocator(mem memory.Allocator) memory.Allocator {
+	if mem == nil {
+		return memory.DefaultAllocator
+	}
+	return mem
+}
@@ -0,0 +1,77 @@
+package pdfarrow
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+func TestGeometryRecord(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	pages := []pdftotext.PageGeometry{
+		{Number: 1, Width: 612, Height: 792, Words: []pdftotext.Word{
+			{Text: "Hello", Box: pdftotext.Rect{XMin: 10, YMin: 20, XMax: 40, YMax: 32}},
+			{Text: "world", Box: pdftotext.Rect{XMin: 45, YMin: 20, XMax: 80, YMax: 32}},
+		}},
+		{Number: 2, Width: 595, Height: 842, Words: []pdftotext.Word{
+			{Text: "again", Box: pdftotext.Rect{XMin: 1, YMin: 2, XMax: 3, YMax: 4}},
+		}},
+	}
+	rec := GeometryRecord(mem, "report.pdf", pages)
+	defer rec.Release()
+
+	if rec.NumRows() != 3 || rec.NumCols() != int64(len(GeometrySchema.Fields())) {
+		t.Fatalf("expected 3 rows and %d columns, got %d and %d", len(GeometrySchema.Fields()), rec.NumRows(), rec.NumCols())
+	}
+	if got := rec.Column(0).(*array.String).Value(2); got != "report.pdf" {
+		t.Errorf("expected path report.pdf, got %q", got)
+	}
+	if got := rec.Column(1).(*array.Int32).Value(2); got != 2 {
+		t.Errorf("expected page 2, got %d", got)
+	}
+	if got := rec.Column(2).(*array.Int32).Value(1); got != 1 {
+		t.Errorf("expected word 1, got %d", got)
+	}
+	if got := rec.Column(3).(*array.String).Value(1); got != "world" {
+		t.Errorf("expected text world, got %q", got)
+	}
+	if got := rec.Column(6).(*array.Float64).Value(0); got != 40 {
+		t.Errorf("expected x_max 40, got %v", got)
+	}
+	if got := rec.Column(9).(*array.Float64).Value(2); got != 842 {
+		t.Errorf("expected page_height 842, got %v", got)
+	}
+}
+
+func TestTSVRecord(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rows := []pdftotext.TSVRow{
+		{Level: pdftotext.TSVPage, Page: 1, Box: pdftotext.Rect{XMax: 612, YMax: 792}, Confidence: -1, Text: "###PAGE###"},
+		{Level: pdftotext.TSVWord, Page: 1, Line: 2, Word: 3, Box: pdftotext.Rect{XMin: 5, YMin: 6, XMax: 7, YMax: 8}, Confidence: 100, Text: "word"},
+	}
+	rec := TSVRecord(mem, "report.pdf", rows)
+	defer rec.Release()
+
+	if rec.NumRows() != 2 {
+		t.Fatalf("expected 2 rows, got %d", rec.NumRows())
+	}
+	if got := rec.Column(1).(*array.Int32).Value(1); got != int32(pdftotext.TSVWord) {
+		t.Errorf("expected level %d, got %d", pdftotext.TSVWord, got)
+	}
+	if got := rec.Column(5).(*array.Int32).Value(1); got != 2 {
+		t.Errorf("expected line 2, got %d", got)
+	}
+	if got := rec.Column(11).(*array.Float64).Value(0); got != -1 {
+		t.Errorf("expected confidence -1, got %v", got)
+	}
+	if got := rec.Column(12).(*array.String).Value(1); got != "word" {
+		t.Errorf("expected text word, got %q", got)
+	}
+}
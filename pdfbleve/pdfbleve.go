@@ -0,0 +1,198 @@
+// Package pdfbleve indexes the pages of PDF files into a Bleve index, so a
+// directory of PDFs can be searched locally without a search server. Files
+// are only converted again when their contents change. It is a separate
+// module so the pdftotext package does not depend on Bleve.
+//
+//	index, err := pdfbleve.Open("/var/lib/search/pdfs.bleve")
+//	indexer := &pdfbleve.Indexer{Converter: converter, Index: index}
+//	changed, err := indexer.IndexFile(ctx, "report.pdf")
+//	results, err := index.Search(bleve.NewSearchRequest(bleve.NewMatchQuery("quarterly revenue")))
+package pdfbleve
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// filePrefix prefixes the internal keys holding the fileRecord of each
+// indexed file
+const filePrefix = "pdfbleve:file:"
+
+// fileRecord is what is kept about an indexed file to update it
+// incrementally
+type fileRecord struct {
+	SHA256 string `json:"sha256"`
+	Pages  int    `json:"pages"`
+}
+
+// NewMapping returns the mapping of the indexed pages, pdftotext.PageDocument
+// values: path is a keyword, page a number, text analyzed with the standard
+// analyzer, the metadata fields text and indexed_at a date
+func NewMapping() mapping.IndexMapping {
+	page := bleve.NewDocumentMapping()
+	page.AddFieldMappingsAt("path", bleve.NewKeywordFieldMapping())
+	page.AddFieldMappingsAt("page", bleve.NewNumericFieldMapping())
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = "standard"
+	page.AddFieldMappingsAt("text", text)
+	page.AddFieldMappingsAt("indexed_at", bleve.NewDateTimeFieldMapping())
+	page.AddSubDocumentMapping("metadata", bleve.NewDocumentMapping())
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = page
+	return m
+}
+
+// Open opens the index at path, creating it with NewMapping if it does not
+// exist
+func Open(path string) (bleve.Index, error) {
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, NewMapping())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return index, nil
+}
+
+// Indexer converts PDF files page by page and indexes the pages as
+// pdftotext.PageDocument values with the ids of PageDocument.ID, so the hits
+// of a search name the file and page. The SHA-256 of every indexed file is
+// kept in the index, and files are only converted again when it changes.
+type Indexer struct {
+	// Converter converts the files
+	Converter *pdftotext.Converter
+	// Index is the index the pages are written to
+	Index bleve.Index
+	// Options are the options used to convert the files
+	Options *pdftotext.Options
+	// Metadata adds the document information reported by pdfinfo to the
+	// pages, as pdftotext.Info.Metadata returns it
+	Metadata bool
+}
+
+// IndexFile indexes the pages of the PDF file at path, replacing the pages
+// indexed for an earlier version of it. It reports whether the file was
+// indexed, false if it is unchanged since it was last indexed.
+func (ix *Indexer) IndexFile(ctx context.Context, path string) (bool, error) {
+	digest, err := fileSHA256(path)
+	if err != nil {
+		return false, err
+	}
+	old, err := ix.record(path)
+	if err != nil {
+		return false, err
+	}
+	if old != nil && old.SHA256 == digest {
+		return false, nil
+	}
+
+	doc, err := ix.Converter.ConvertDocument(ctx, path, ix.Options)
+	if err != nil {
+		return false, err
+	}
+	if ix.Metadata {
+		if doc.Metadata, err = ix.Converter.Metadata(ctx, path, ix.Options); err != nil {
+			return false, err
+		}
+	}
+
+	batch := ix.Index.NewBatch()
+	now := time.Now().UTC()
+	for _, page := range doc.Pages {
+		page := pdftotext.PageDocument{Path: path, Page: page.Number, Text: page.Text, Metadata: doc.Metadata, IndexedAt: now}
+		if err := batch.Index(page.ID(), page); err != nil {
+			return false, err
+		}
+	}
+	if old != nil {
+		// pages the new version no longer has
+		for n := len(doc.Pages) + 1; n <= old.Pages; n++ {
+			batch.Delete(pdftotext.PageDocument{Path: path, Page: n}.ID())
+		}
+	}
+	record, err := json.Marshal(fileRecord{SHA256: digest, Pages: len(doc.Pages)})
+	if err != nil {
+		return false, err
+	}
+	batch.SetInternal([]byte(filePrefix+path), record)
+	if err := ix.Index.Batch(batch); err != nil {
+		return false, fmt.Errorf("failed to index %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// IndexFiles indexes the PDF files at paths in order and returns the number
+// of files that changed. It stops at the first error.
+func (ix *Indexer) IndexFiles(ctx context.Context, paths ...string) (int, error) {
+	changed := 0
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+		ok, err := ix.IndexFile(ctx, path)
+		if err != nil {
+			return changed, err
+		}
+		if ok {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// RemoveFile removes the pages of the file at path from the index, e.g.
+// after the file was deleted
+func (ix *Indexer) RemoveFile(path string) error {
+	old, err := ix.record(path)
+	if err != nil || old == nil {
+		return err
+	}
+	batch := ix.Index.NewBatch()
+	for n := 1; n <= old.Pages; n++ {
+		batch.Delete(pdftotext.PageDocument{Path: path, Page: n}.ID())
+	}
+	batch.DeleteInternal([]byte(filePrefix + path))
+	return ix.Index.Batch(batch)
+}
+
+// record returns the record of the indexed file at path, or nil if it has
+// not been indexed
+func (ix *Indexer) record(path string) (*fileRecord, error) {
+	data, err := ix.Index.GetInternal([]byte(filePrefix + path))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var record fileRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("invalid record of %s: %w", path, err)
+	}
+	return &record, nil
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of the file at path
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
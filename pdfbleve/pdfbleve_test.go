@@ -0,0 +1,112 @@
+package pdfbleve
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// fakeRunner answers like pdfinfo and pdftotext for a document of the given
+// pages
+type fakeRunner struct {
+	pages []string
+}
+
+func (r *fakeRunner) Run(_ context.Context, name string, _ []string, stdout, _ io.Writer) error {
+	if name == "pdfinfo" {
+		_, err := io.WriteString(stdout, "Title:           Annual Report\nPages:           "+strconv.Itoa(len(r.pages))+"\n")
+		return err
+	}
+	_, err := io.WriteString(stdout, strings.Join(r.pages, "\f")+"\f")
+	return err
+}
+
+// search returns the ids of the pages matching query
+func search(t *testing.T, index bleve.Index, query string) []string {
+	t.Helper()
+	result, err := index.Search(bleve.NewSearchRequest(bleve.NewMatchQuery(query)))
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	ids := make([]string, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestIndexer_IndexFile(t *testing.T) {
+	runner := &fakeRunner{pages: []string{"quarterly revenue grew", "costs were flat"}}
+	converter, err := pdftotext.New(pdftotext.WithRunner(runner))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	index, err := bleve.NewMemOnly(NewMapping())
+	if err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+	defer index.Close()
+
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.7 first version"), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	indexer := &Indexer{Converter: converter, Index: index, Metadata: true}
+
+	changed, err := indexer.IndexFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Error("expected a new file to be indexed")
+	}
+	if ids := search(t, index, "revenue"); len(ids) != 1 || ids[0] != path+"#1" {
+		t.Errorf("expected a hit on page 1, got %v", ids)
+	}
+	query := bleve.NewMatchQuery("annual")
+	query.SetField("metadata.title")
+	if result, err := index.Search(bleve.NewSearchRequest(query)); err != nil || result.Total != 2 {
+		t.Errorf("expected the title on both pages, got %v, %v", result, err)
+	}
+	doc, err := index.Document(path + "#2")
+	if err != nil || doc == nil {
+		t.Fatalf("expected page 2 to be indexed, got %v, %v", doc, err)
+	}
+
+	if changed, err := indexer.IndexFile(context.Background(), path); err != nil || changed {
+		t.Errorf("expected an unchanged file to be skipped, got %v, %v", changed, err)
+	}
+
+	// a new version with fewer pages replaces the old pages
+	runner.pages = []string{"restated revenue"}
+	if err := os.WriteFile(path, []byte("%PDF-1.7 second version"), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	if changed, err := indexer.IndexFile(context.Background(), path); err != nil || !changed {
+		t.Fatalf("expected a changed file to be indexed again, got %v, %v", changed, err)
+	}
+	if count, _ := index.DocCount(); count != 1 {
+		t.Errorf("expected 1 indexed page, got %d", count)
+	}
+	if ids := search(t, index, "restated"); len(ids) != 1 || ids[0] != path+"#1" {
+		t.Errorf("expected a hit on the new page 1, got %v", ids)
+	}
+
+	if err := indexer.RemoveFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count, _ := index.DocCount(); count != 0 {
+		t.Errorf("expected no indexed pages after removing the file, got %d", count)
+	}
+	if changed, err := indexer.IndexFile(context.Background(), path); err != nil || !changed {
+		t.Errorf("expected a removed file to be indexed again, got %v, %v", changed, err)
+	}
+}
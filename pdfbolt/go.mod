@@ -0,0 +1,12 @@
+module github.com/joeychilson/pdftotext/pdfbolt
+
+go 1.23.2
+
+require (
+	github.com/joeychilson/pdftotext v0.0.0-00010101000000-000000000000
+	go.etcd.io/bbolt v1.3.11
+)
+
+require golang.org/x/sys v0.4.0 // indirect
+
+replace github.com/joeychilson/pdftotext => ../
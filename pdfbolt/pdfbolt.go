@@ -0,0 +1,120 @@
+// Package pdfbolt provides a pdftotext.Storage in a bbolt database file, so
+// the job statuses of a pdftotext.Pool survive restarts of the process and
+// Pool.Recover can queue the interrupted jobs again. It is a separate module
+// so the pdftotext package does not depend on bbolt.
+//
+//	storage, err := pdfbolt.Open("/var/lib/extractor/jobs.db")
+//	pool := &pdftotext.Pool{Converter: converter, Storage: storage}
+//	recovered, err := pool.Recover(ctx)
+//	err = pool.Run(ctx)
+package pdfbolt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// bucket is the bucket holding the keys
+var bucket = []byte("pdftotext")
+
+// Storage is a pdftotext.CreateStorage in a bbolt database. Every write is
+// committed to disk before it returns.
+type Storage struct {
+	db *bolt.DB
+}
+
+// Open opens the database file at path, creating it if needed. Only one
+// process can open the file at a time.
+func Open(path string) (*Storage, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return New(db)
+}
+
+// New returns a Storage keeping its keys in a bucket of db
+func New(db *bolt.DB) (*Storage, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket: %w", err)
+	}
+	return &Storage{db: db}, nil
+}
+
+// Put stores data under key
+func (s *Storage) Put(_ context.Context, key string, data []byte) error {
+	if key == "" {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), data)
+	})
+}
+
+// Create stores data under key, or returns pdftotext.ErrExists if key
+// exists, in one transaction
+func (s *Storage) Create(_ context.Context, key string, data []byte) error {
+	if key == "" {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get([]byte(key)) != nil {
+			return fmt.Errorf("%w: %s", pdftotext.ErrExists, key)
+		}
+		return b.Put([]byte(key), data)
+	})
+}
+
+// Get returns the data stored under key, or pdftotext.ErrNotFound
+func (s *Storage) Get(_ context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucket).Get([]byte(key))
+		if value == nil {
+			return fmt.Errorf("%w: %s", pdftotext.ErrNotFound, key)
+		}
+		// values are only valid during the transaction
+		data = bytes.Clone(value)
+		return nil
+	})
+	return data, err
+}
+
+// Delete removes key
+func (s *Storage) Delete(_ context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete([]byte(key))
+	})
+}
+
+// List returns the keys starting with prefix, sorted as bbolt keeps them
+func (s *Storage) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		p := []byte(prefix)
+		for k, _ := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = c.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// Close closes the database
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
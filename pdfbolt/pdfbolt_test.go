@@ -0,0 +1,73 @@
+package pdfbolt
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+var _ pdftotext.CreateStorage = (*Storage)(nil)
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, pdftotext.ErrNotFound) {
+		t.Errorf("expected error %v, got %v", pdftotext.ErrNotFound, err)
+	}
+	if err := s.Put(ctx, "", []byte("x")); err == nil {
+		t.Error("expected error for an empty key")
+	}
+
+	for _, key := range []string{"jobs/b.json", "jobs/a.json", "cache/c"} {
+		if err := s.Put(ctx, key, []byte("value of "+key)); err != nil {
+			t.Fatalf("failed to put %s: %v", key, err)
+		}
+	}
+	if err := s.Put(ctx, "cache/c", []byte("replaced")); err != nil {
+		t.Fatalf("failed to replace cache/c: %v", err)
+	}
+	if err := s.Create(ctx, "jobs/b.json", []byte("again")); !errors.Is(err, pdftotext.ErrExists) {
+		t.Errorf("expected error %v, got %v", pdftotext.ErrExists, err)
+	}
+
+	keys, err := s.List(ctx, "jobs/")
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if strings.Join(keys, ",") != "jobs/a.json,jobs/b.json" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+
+	if err := s.Delete(ctx, "jobs/a.json"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := s.Delete(ctx, "jobs/a.json"); err != nil {
+		t.Errorf("expected deleting a missing key to succeed, got %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	// the keys survive reopening the file
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	defer s.Close()
+	data, err := s.Get(ctx, "cache/c")
+	if err != nil || string(data) != "replaced" {
+		t.Errorf("expected %q, got %q (%v)", "replaced", data, err)
+	}
+	if keys, _ := s.List(ctx, ""); strings.Join(keys, ",") != "cache/c,jobs/b.json" {
+		t.Errorf("unexpected keys after reopening %v", keys)
+	}
+}
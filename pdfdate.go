@@ -0,0 +1,96 @@
+package pdftotext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDate parses a PDF date string, D:YYYYMMDDHHmmSSOHH'mm' where O is
+// "+", "-" or "Z" and every part after the year is optional, into a
+// time.Time. Dates without a time zone are taken as UTC. It also accepts the
+// malformed variants real-world producers emit: a missing "D:" prefix or
+// closing apostrophe, offsets without apostrophes, zero months and days,
+// years written as 19 followed by the years since 1900 (e.g. "D:191000312"
+// for 12 March 2000), ISO 8601 and asctime dates.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02", time.ANSIC} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	digits := strings.TrimPrefix(s, "D:")
+	n := 0
+	for n < len(digits) && digits[n] >= '0' && digits[n] <= '9' {
+		n++
+	}
+	zone := digits[n:]
+	digits = digits[:n]
+
+	// producers with a Y2K bug wrote 19 followed by the years since 1900
+	yearDigits := 4
+	if strings.HasPrefix(digits, "191") && n%2 == 1 {
+		yearDigits = 5
+	}
+	if n < yearDigits || n > yearDigits+10 || (n-yearDigits)%2 != 0 {
+		return time.Time{}, fmt.Errorf("invalid PDF date %q", s)
+	}
+	year, _ := strconv.Atoi(digits[:yearDigits])
+	if yearDigits == 5 {
+		year = 1900 + year%1000
+	}
+	fields := []int{1, 1, 0, 0, 0}
+	for i := range (n - yearDigits) / 2 {
+		fields[i], _ = strconv.Atoi(digits[yearDigits+2*i : yearDigits+2*i+2])
+	}
+	month, day, hour, minute, second := fields[0], fields[1], fields[2], fields[3], fields[4]
+	month, day = max(month, 1), max(day, 1)
+	if month > 12 || day > 31 || hour > 23 || minute > 59 || second > 60 {
+		return time.Time{}, fmt.Errorf("invalid PDF date %q", s)
+	}
+
+	loc, err := parseDateZone(zone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid PDF date %q: %w", s, err)
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, loc), nil
+}
+
+// parseDateZone parses the time zone of a PDF date: "Z", "+HH'mm'" or
+// "-HH'mm'", with or without the apostrophes and the minutes
+func parseDateZone(zone string) (*time.Location, error) {
+	zone = strings.TrimSpace(zone)
+	if zone == "" || zone[0] == 'Z' || zone[0] == 'z' {
+		return time.UTC, nil
+	}
+	sign := 1
+	switch zone[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return nil, fmt.Errorf("unknown time zone %q", zone)
+	}
+	offset := strings.ReplaceAll(zone[1:], "'", "")
+	if len(offset) != 2 && len(offset) != 4 {
+		return nil, fmt.Errorf("unknown time zone %q", zone)
+	}
+	hours, err := strconv.Atoi(offset[:2])
+	if err != nil || hours > 23 {
+		return nil, fmt.Errorf("unknown time zone %q", zone)
+	}
+	minutes := 0
+	if len(offset) == 4 {
+		if minutes, err = strconv.Atoi(offset[2:]); err != nil || minutes > 59 {
+			return nil, fmt.Errorf("unknown time zone %q", zone)
+		}
+	}
+	seconds := sign * (hours*3600 + minutes*60)
+	if seconds == 0 {
+		return time.UTC, nil
+	}
+	return time.FixedZone("", seconds), nil
+}
@@ -0,0 +1,62 @@
+package pdftotext
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	plusOne := time.FixedZone("", 3600)
+	minusEight := time.FixedZone("", -8*3600)
+	india := time.FixedZone("", 5*3600+30*60)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		invalid  bool
+	}{
+		{name: "Full", input: "D:20240131120000+01'00'", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, plusOne)},
+		{name: "UTC", input: "D:20240201093000Z", expected: time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "UTC with offset", input: "D:20240201093000Z00'00'", expected: time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "Negative offset", input: "D:20231224180000-08'00'", expected: time.Date(2023, 12, 24, 18, 0, 0, 0, minusEight)},
+		{name: "Half hour offset", input: "D:20230615083000+05'30'", expected: time.Date(2023, 6, 15, 8, 30, 0, 0, india)},
+		{name: "Missing closing apostrophe", input: "D:20240131120000+01'00", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, plusOne)},
+		{name: "Offset without apostrophes", input: "D:20240131120000+0100", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, plusOne)},
+		{name: "Offset hours only", input: "D:20240131120000+01", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, plusOne)},
+		{name: "No time zone", input: "D:20240131120000", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{name: "No prefix", input: "20240131120000Z", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{name: "Year only", input: "D:2024", expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "Date only", input: "D:20240131", expected: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "Zero month and day", input: "D:20240000", expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "Y2K bug", input: "D:191000312104500", expected: time.Date(2000, 3, 12, 10, 45, 0, 0, time.UTC)},
+		{name: "ISO 8601", input: "2024-01-31T12:00:00+01:00", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, plusOne)},
+		{name: "asctime", input: "Wed Jan 31 12:00:00 2024", expected: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{name: "Empty", input: "", invalid: true},
+		{name: "Garbage", input: "yesterday", invalid: true},
+		{name: "Odd digits", input: "D:2024013", invalid: true},
+		{name: "Month out of range", input: "D:20241301", invalid: true},
+		{name: "Unknown time zone", input: "D:20240131120000 CET", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDate(tt.input)
+			if tt.invalid {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			_, gotOffset := got.Zone()
+			if _, offset := tt.expected.Zone(); gotOffset != offset {
+				t.Errorf("expected offset %d, got %d", offset, gotOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package pdflangchain provides a LangChainGo document loader backed by a
+// pdftotext.Converter, so Go LLM applications can load PDF files with
+// poppler. It is a separate module so the pdftotext package does not depend
+// on LangChainGo.
+//
+//	loader := pdflangchain.NewLoader(converter, "report.pdf", nil)
+//	docs, err := loader.Load(ctx)
+package pdflangchain
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// Loader is a documentloaders.Loader emitting one document per page of a PDF
+// file, with the metadata keys "source", "page" and "total_pages" used by the
+// LangChainGo PDF loader
+type Loader struct {
+	converter *pdftotext.Converter
+	inputPath string
+	opts      *pdftotext.Options
+}
+
+var _ documentloaders.Loader = Loader{}
+
+// NewLoader returns a Loader converting inputPath with converter and opts
+func NewLoader(converter *pdftotext.Converter, inputPath string, opts *pdftotext.Options) Loader {
+	return Loader{converter: converter, inputPath: inputPath, opts: opts}
+}
+
+// Load converts the PDF file and returns a document per page. total_pages is
+// the page count of the file reported by pdfinfo, even if opts selects fewer
+// pages.
+func (l Loader) Load(ctx context.Context) ([]schema.Document, error) {
+	total, err := l.converter.PageCount(ctx, l.inputPath, l.opts)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := l.converter.ConvertPages(ctx, l.inputPath, l.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, len(pages))
+	for i, page := range pages {
+		metadata := map[string]any{
+			"source":      l.inputPath,
+			"page":        page.Number,
+			"total_pages": total,
+		}
+		if page.Label != "" {
+			metadata["page_label"] = page.Label
+		}
+		docs[i] = schema.Document{PageContent: page.Text, Metadata: metadata}
+	}
+	return docs, nil
+}
+
+// LoadAndSplit converts the PDF file and splits the document of each page
+// with splitter
+func (l Loader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
@@ -0,0 +1,61 @@
+package pdflangchain
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// fakeRunner answers like pdfinfo and pdftotext for a three page document
+type fakeRunner struct{}
+
+func (fakeRunner) Run(_ context.Context, name string, _ []string, stdout, _ io.Writer) error {
+	if name == "pdfinfo" {
+		_, err := io.WriteString(stdout, "Pages:           3\n")
+		return err
+	}
+	_, err := io.WriteString(stdout, "first page\fsecond page\fthird page\f")
+	return err
+}
+
+func TestLoader_Load(t *testing.T) {
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	docs, err := NewLoader(converter, "report.pdf", nil).Load(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []schema.Document{
+		{PageContent: "first page", Metadata: map[string]any{"source": "report.pdf", "page": 1, "total_pages": 3}},
+		{PageContent: "second page", Metadata: map[string]any{"source": "report.pdf", "page": 2, "total_pages": 3}},
+		{PageContent: "third page", Metadata: map[string]any{"source": "report.pdf", "page": 3, "total_pages": 3}},
+	}
+	if !reflect.DeepEqual(docs, expected) {
+		t.Errorf("expected %+v, got %+v", expected, docs)
+	}
+}
+
+func TestLoader_LoadAndSplit(t *testing.T) {
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	splitter := textsplitter.NewRecursiveCharacter(textsplitter.WithChunkSize(6), textsplitter.WithChunkOverlap(0))
+	docs, err := NewLoader(converter, "report.pdf", nil).LoadAndSplit(context.Background(), splitter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 6 || docs[0].PageContent != "first" || docs[0].Metadata["page"] != 1 {
+		t.Errorf("unexpected documents %+v", docs)
+	}
+}
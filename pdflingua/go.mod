@@ -0,0 +1,16 @@
+module github.com/joeychilson/pdftotext/pdflingua
+
+go 1.23.2
+
+require (
+	github.com/joeychilson/pdftotext v0.0.0-00010101000000-000000000000
+	github.com/pemistahl/lingua-go v1.4.0
+)
+
+require (
+	github.com/shopspring/decimal v1.3.1 // indirect
+	golang.org/x/exp v0.0.0-20221106115401-f9659909a136 // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
+)
+
+replace github.com/joeychilson/pdftotext => ../
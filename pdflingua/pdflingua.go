@@ -0,0 +1,45 @@
+// Package pdflingua provides a pdftotext.LanguageDetector backed by
+// lingua-go, which detects 75 languages offline with high accuracy even on
+// short text. It is a separate module so the pdftotext package does not
+// depend on lingua-go and its language models.
+//
+//	opts := &pdftotext.Options{LanguageDetector: pdflingua.New()}
+//	doc, err := converter.ConvertDocument(ctx, "report.pdf", opts)
+package pdflingua
+
+import (
+	"strings"
+
+	"github.com/pemistahl/lingua-go"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// Detector is a pdftotext.LanguageDetector returning ISO 639-1 codes such as
+// "en" or "de"
+type Detector struct {
+	detector lingua.LanguageDetector
+}
+
+var _ pdftotext.LanguageDetector = Detector{}
+
+// New returns a Detector choosing among languages, or among all languages
+// known to lingua-go if none are given. Restricting the languages to those
+// expected in a corpus makes detection faster and more accurate.
+func New(languages ...lingua.Language) Detector {
+	builder := lingua.NewLanguageDetectorBuilder()
+	if len(languages) == 0 {
+		return Detector{detector: builder.FromAllLanguages().Build()}
+	}
+	return Detector{detector: builder.FromLanguages(languages...).Build()}
+}
+
+// DetectLanguage returns the ISO 639-1 code of the language of text, or "" if
+// lingua-go cannot tell
+func (d Detector) DetectLanguage(text string) string {
+	language, ok := d.detector.DetectLanguageOf(text)
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(language.IsoCode639_1().String())
+}
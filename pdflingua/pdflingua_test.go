@@ -0,0 +1,28 @@
+package pdflingua
+
+import (
+	"testing"
+
+	"github.com/pemistahl/lingua-go"
+)
+
+func TestDetector_DetectLanguage(t *testing.T) {
+	detector := New(lingua.English, lingua.German, lingua.French)
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "The quarterly report shows growing revenue in all regions.", expected: "en"},
+		{text: "Der Quartalsbericht zeigt steigende Umsätze in allen Regionen.", expected: "de"},
+		{text: "Le rapport trimestriel montre une hausse du chiffre d'affaires.", expected: "fr"},
+		{text: "12345", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := detector.DetectLanguage(tt.text); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/joeychilson/pdftotext/pdfnats
+
+go 1.23.2
+
+require (
+	github.com/joeychilson/pdftotext v0.0.0-00010101000000-000000000000
+	github.com/nats-io/nats-server/v2 v2.10.22
+	github.com/nats-io/nats.go v1.37.0
+)
+
+require (
+	github.com/klauspost/compress v1.17.11 // indirect
+	github.com/minio/highwayhash v1.0.3 // indirect
+	github.com/nats-io/jwt/v2 v2.5.8 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.28.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+	golang.org/x/time v0.7.0 // indirect
+)
+
+replace github.com/joeychilson/pdftotext => ../
@@ -0,0 +1,101 @@
+// Package pdfnats connects a pdftotext.Pool to NATS, the standard shape of an
+// extraction deployment: conversion requests arrive on a subject, the workers
+// of a queue group share them, and the status of each finished job, with its
+// text or error, is published on a result subject. It is a separate module so
+// the pdftotext package does not depend on the NATS client.
+//
+//	bus, err := pdfnats.New(conn, "pdftotext.jobs", "extractors", "pdftotext.results")
+//	pool := &pdftotext.Pool{Converter: converter, Queue: bus, OnFinish: bus.Publish}
+//	err = pool.Run(ctx)
+package pdfnats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// Bus is a pdftotext.Queue receiving jobs from a NATS subject, and publishes
+// the statuses of finished jobs. Messages are delivered at most once, as
+// with any core NATS subscription.
+type Bus struct {
+	conn    *nats.Conn
+	subject string
+	results string
+	sub     *nats.Subscription
+	msgs    chan *nats.Msg
+	logger  *slog.Logger
+}
+
+// New subscribes to subject in queue, the queue group shared by the workers,
+// and returns a Bus publishing the statuses of finished jobs on results.
+func New(conn *nats.Conn, subject, queue, results string) (*Bus, error) {
+	b := &Bus{
+		conn:    conn,
+		subject: subject,
+		results: results,
+		msgs:    make(chan *nats.Msg, 64),
+		logger:  slog.Default(),
+	}
+	sub, err := conn.ChanQueueSubscribe(subject, queue, b.msgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+	b.sub = sub
+	return b, nil
+}
+
+// Push publishes job on the subject of the bus
+func (b *Bus) Push(_ context.Context, job pdftotext.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+	return b.conn.Publish(b.subject, data)
+}
+
+// Pop returns the next job received from the subject. Messages that are not
+// valid jobs are reported with a failed status on the result subject and
+// skipped.
+func (b *Bus) Pop(ctx context.Context) (pdftotext.Job, error) {
+	for {
+		select {
+		case msg := <-b.msgs:
+			var job pdftotext.Job
+			if err := json.Unmarshal(msg.Data, &job); err != nil {
+				b.Publish(ctx, &pdftotext.JobStatus{
+					State: pdftotext.JobFailed,
+					Error: fmt.Sprintf("invalid job: %v", err),
+				})
+				continue
+			}
+			return job, nil
+		case <-ctx.Done():
+			return pdftotext.Job{}, ctx.Err()
+		}
+	}
+}
+
+// Publish publishes status on the result subject, logging failures since
+// the pool has no one to report them to. It has the signature of
+// pdftotext.Pool.OnFinish.
+func (b *Bus) Publish(_ context.Context, status *pdftotext.JobStatus) {
+	data, err := json.Marshal(status)
+	if err == nil {
+		err = b.conn.Publish(b.results, data)
+	}
+	if err != nil {
+		b.logger.Error("failed to publish job status", "job", status.Job.ID, "subject", b.results, "error", err)
+	}
+}
+
+// Close stops receiving jobs. Jobs received but not yet taken by Pop are
+// dropped.
+func (b *Bus) Close() error {
+	return b.sub.Unsubscribe()
+}
@@ -0,0 +1,93 @@
+package pdfnats
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// connect starts an embedded NATS server and returns a connection to it
+func connect(t *testing.T) *nats.Conn {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("server not ready")
+	}
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func TestBus(t *testing.T) {
+	conn := connect(t)
+	bus, err := New(conn, "pdftotext.jobs", "extractors", "pdftotext.results")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bus.Close()
+	results, err := conn.SubscribeSync("pdftotext.results")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// invalid messages are reported and skipped
+	if err := conn.Publish("pdftotext.jobs", []byte("not json")); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	if err := bus.Push(ctx, pdftotext.Job{ID: "job-1", InputPath: "report.pdf"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job, err := bus.Pop(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.ID != "job-1" || job.InputPath != "report.pdf" {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	msg, err := results.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("expected the invalid message to be reported: %v", err)
+	}
+	var status pdftotext.JobStatus
+	if err := json.Unmarshal(msg.Data, &status); err != nil {
+		t.Fatalf("invalid status: %v", err)
+	}
+	if status.State != pdftotext.JobFailed || status.Error == "" {
+		t.Errorf("expected a failed status, got %+v", status)
+	}
+
+	bus.Publish(ctx, &pdftotext.JobStatus{Job: job, State: pdftotext.JobDone, Text: "hello"})
+	msg, err = results.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("expected the status to be published: %v", err)
+	}
+	if err := json.Unmarshal(msg.Data, &status); err != nil {
+		t.Fatalf("invalid status: %v", err)
+	}
+	if status.Job.ID != "job-1" || status.State != pdftotext.JobDone || status.Text != "hello" {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	cancel()
+	if _, err := bus.Pop(ctx); err == nil {
+		t.Error("expected Pop to return when the context is canceled")
+	}
+}
@@ -0,0 +1,434 @@
+package pdftotext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// This file implements just enough of the PDF object syntax to read document
+// level structures poppler's tools don't print, such as page labels. It
+// indexes objects by scanning for "N G obj" rather than reading the
+// cross-reference table, which also copes with damaged files, and looks into
+// Flate compressed object streams.
+
+// pdfName is a PDF name such as /Type, without the slash
+type pdfName string
+
+// pdfDict is a PDF dictionary
+type pdfDict map[pdfName]any
+
+// pdfRef is an indirect object reference "N G R"
+type pdfRef struct {
+	num, gen int
+}
+
+// errPDFSyntax is returned for malformed PDF objects
+var errPDFSyntax = errors.New("invalid PDF syntax")
+
+// maxObjectDepth limits the nesting of arrays and dictionaries and the length
+// of reference chains
+const maxObjectDepth = 64
+
+// maxStreamSize limits the decompressed size of a stream
+const maxStreamSize = 64 << 20
+
+// pdfFile indexes the objects of a PDF file
+type pdfFile struct {
+	data    []byte
+	objects map[int][]byte
+}
+
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// newPDFFile indexes the objects of data. Objects defined more than once, as
+// in incrementally updated files, resolve to their last definition.
+func newPDFFile(data []byte) *pdfFile {
+	f := &pdfFile{data: data, objects: map[int][]byte{}}
+	var streams []int
+	for _, m := range objectHeader.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		f.objects[num] = data[m[1]:]
+
+		// object streams have small dictionaries naming their type
+		window := data[m[1]:min(m[1]+512, len(data))]
+		if i := bytes.Index(window, []byte("stream")); i >= 0 && bytes.Contains(window[:i], []byte("/ObjStm")) {
+			streams = append(streams, num)
+		}
+	}
+	for _, num := range streams {
+		f.indexObjectStream(num)
+	}
+	return f
+}
+
+// indexObjectStream adds the objects compressed in an object stream, unless
+// they are also defined directly
+func (f *pdfFile) indexObjectStream(num int) {
+	p := &pdfParser{data: f.objects[num]}
+	v, err := p.value(0)
+	if err != nil {
+		return
+	}
+	dict, ok := v.(pdfDict)
+	if !ok {
+		return
+	}
+	content, err := p.stream(dict)
+	if err != nil {
+		return
+	}
+
+	n, _ := dict["N"].(int)
+	first, _ := dict["First"].(int)
+	if first < 0 || first > len(content) {
+		return
+	}
+	header := &pdfParser{data: content[:first]}
+	for range n {
+		objNum, err1 := header.value(0)
+		offset, err2 := header.value(0)
+		objNumInt, ok1 := objNum.(int)
+		offsetInt, ok2 := offset.(int)
+		if err1 != nil || err2 != nil || !ok1 || !ok2 || offsetInt < 0 || first+offsetInt > len(content) {
+			return
+		}
+		if _, ok := f.objects[objNumInt]; !ok {
+			f.objects[objNumInt] = content[first+offsetInt:]
+		}
+	}
+}
+
+// object parses the object with the given number
+func (f *pdfFile) object(num int) (any, error) {
+	data, ok := f.objects[num]
+	if !ok {
+		return nil, fmt.Errorf("%w: object %d not found", errPDFSyntax, num)
+	}
+	p := &pdfParser{data: data}
+	return p.value(0)
+}
+
+// resolve follows indirect references until v is a direct object
+func (f *pdfFile) resolve(v any) (any, error) {
+	for range maxObjectDepth {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v, nil
+		}
+		var err error
+		if v, err = f.object(ref.num); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: reference chain too long", errPDFSyntax)
+}
+
+// trailer returns the last trailer dictionary, or the dictionary of the last
+// cross-reference stream, which both name the document catalog as /Root
+func (f *pdfFile) trailer() (pdfDict, error) {
+	i := bytes.LastIndex(f.data, []byte("/Root"))
+	if i < 0 {
+		return nil, fmt.Errorf("%w: no trailer", errPDFSyntax)
+	}
+	start := bytes.LastIndex(f.data[:i], []byte("<<"))
+	for tries := 0; start >= 0 && tries < maxObjectDepth; tries++ {
+		p := &pdfParser{data: f.data[start:]}
+		if v, err := p.value(0); err == nil {
+			if dict, ok := v.(pdfDict); ok && dict["Root"] != nil {
+				return dict, nil
+			}
+		}
+		start = bytes.LastIndex(f.data[:start], []byte("<<"))
+	}
+	return nil, fmt.Errorf("%w: no trailer", errPDFSyntax)
+}
+
+// pdfParser parses PDF objects
+type pdfParser struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n' || b == '\f' || b == 0
+}
+
+func isPDFDelimiter(b byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), b) >= 0
+}
+
+// skip skips whitespace and comments
+func (p *pdfParser) skip() {
+	for p.pos < len(p.data) {
+		switch b := p.data[p.pos]; {
+		case isPDFWhitespace(b):
+			p.pos++
+		case b == '%':
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// keyword reads a regular token such as a number, "true" or "R"
+func (p *pdfParser) keyword() string {
+	start := p.pos
+	for p.pos < len(p.data) && !isPDFWhitespace(p.data[p.pos]) && !isPDFDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// value parses the next object: an int, float64, bool, nil, string (for
+// literal and hex strings), pdfName, []any, pdfDict or pdfRef
+func (p *pdfParser) value(depth int) (any, error) {
+	if depth > maxObjectDepth {
+		return nil, fmt.Errorf("%w: nesting too deep", errPDFSyntax)
+	}
+	p.skip()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", errPDFSyntax)
+	}
+
+	switch p.data[p.pos] {
+	case '/':
+		p.pos++
+		return pdfName(decodeName(p.keyword())), nil
+	case '(':
+		return p.literalString()
+	case '<':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '<' {
+			return p.dict(depth)
+		}
+		return p.hexString()
+	case '[':
+		p.pos++
+		var array []any
+		for {
+			p.skip()
+			if p.pos < len(p.data) && p.data[p.pos] == ']' {
+				p.pos++
+				return array, nil
+			}
+			v, err := p.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, v)
+		}
+	}
+
+	token := p.keyword()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("%w: unexpected %q", errPDFSyntax, p.data[p.pos])
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	n, err := strconv.Atoi(token)
+	if err != nil {
+		f, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unexpected %q", errPDFSyntax, token)
+		}
+		return f, nil
+	}
+
+	// an integer may start an indirect reference "N G R"
+	save := p.pos
+	p.skip()
+	if gen, err := strconv.Atoi(p.keyword()); err == nil {
+		p.skip()
+		if p.keyword() == "R" {
+			return pdfRef{num: n, gen: gen}, nil
+		}
+	}
+	p.pos = save
+	return n, nil
+}
+
+func (p *pdfParser) dict(depth int) (pdfDict, error) {
+	p.pos += 2
+	dict := pdfDict{}
+	for {
+		p.skip()
+		if bytes.HasPrefix(p.data[p.pos:], []byte(">>")) {
+			p.pos += 2
+			return dict, nil
+		}
+		key, err := p.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			return nil, fmt.Errorf("%w: dictionary key is not a name", errPDFSyntax)
+		}
+		if dict[name], err = p.value(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *pdfParser) literalString() (string, error) {
+	p.pos++
+	var b []byte
+	nesting := 0
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			nesting++
+		case ')':
+			if nesting == 0 {
+				return string(b), nil
+			}
+			nesting--
+		case '\\':
+			if p.pos >= len(p.data) {
+				break
+			}
+			c = p.data[p.pos]
+			p.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// a line continuation
+				if c == '\r' && p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				n := int(c - '0')
+				for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+					n = n*8 + int(p.data[p.pos]-'0')
+					p.pos++
+				}
+				c = byte(n)
+			}
+		}
+		b = append(b, c)
+	}
+	return "", fmt.Errorf("%w: unterminated string", errPDFSyntax)
+}
+
+func (p *pdfParser) hexString() (string, error) {
+	p.pos++
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end < 0 {
+		return "", fmt.Errorf("%w: unterminated hex string", errPDFSyntax)
+	}
+	var digits []byte
+	for _, c := range p.data[p.pos : p.pos+end] {
+		if !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	p.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	b := make([]byte, len(digits)/2)
+	for i := range b {
+		n, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid hex string", errPDFSyntax)
+		}
+		b[i] = byte(n)
+	}
+	return string(b), nil
+}
+
+// stream returns the decoded content of the stream following its dictionary
+func (p *pdfParser) stream(dict pdfDict) ([]byte, error) {
+	p.skip()
+	if p.keyword() != "stream" {
+		return nil, fmt.Errorf("%w: missing stream", errPDFSyntax)
+	}
+	if bytes.HasPrefix(p.data[p.pos:], []byte("\r\n")) {
+		p.pos += 2
+	} else if bytes.HasPrefix(p.data[p.pos:], []byte("\n")) {
+		p.pos++
+	}
+
+	data := p.data[p.pos:]
+	if length, ok := dict["Length"].(int); ok && length >= 0 && length <= len(data) {
+		data = data[:length]
+	} else if end := bytes.Index(data, []byte("endstream")); end >= 0 {
+		data = data[:end]
+	}
+
+	switch filter := dict["Filter"].(type) {
+	case nil:
+		return data, nil
+	case pdfName:
+		if filter == "FlateDecode" {
+			return inflate(data)
+		}
+	case []any:
+		if len(filter) == 1 && filter[0] == pdfName("FlateDecode") {
+			return inflate(data)
+		}
+	}
+	return nil, fmt.Errorf("%w: unsupported stream filter %v", errPDFSyntax, dict["Filter"])
+}
+
+// inflate decompresses zlib data, keeping what could be read from a
+// truncated stream
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	out, err := io.ReadAll(io.LimitReader(r, maxStreamSize))
+	if len(out) > 0 && (errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, zlib.ErrChecksum)) {
+		err = nil
+	}
+	return out, err
+}
+
+// decodeName decodes the #xx escapes of a name
+func decodeName(name string) string {
+	if !bytes.Contains([]byte(name), []byte("#")) {
+		return name
+	}
+	var b []byte
+	for i := 0; i < len(name); i++ {
+		if name[i] == '#' && i+2 < len(name) {
+			if n, err := strconv.ParseUint(name[i+1:i+3], 16, 8); err == nil {
+				b = append(b, byte(n))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, name[i])
+	}
+	return string(b)
+}
@@ -0,0 +1,98 @@
+// Package pdfprometheus registers Prometheus metrics for pdftotext
+// conversions: a histogram of conversion durations, a histogram of output
+// sizes, a counter of failed conversions by error kind and a gauge of the
+// pdftotext child processes running, so extraction services get dashboards
+// and alerts out of the box. It is a separate module so the pdftotext package
+// does not depend on the Prometheus client.
+//
+//	metrics, err := pdfprometheus.New(prometheus.DefaultRegisterer)
+//	converter, err := pdftotext.New(pdftotext.WithRunner(metrics.Runner(pdftotext.ExecRunner{})))
+//	convert := pdftotext.Chain(converter.Convert, metrics.Middleware())
+package pdfprometheus
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// Metrics are the Prometheus metrics of pdftotext conversions
+type Metrics struct {
+	duration   prometheus.Histogram
+	outputSize prometheus.Histogram
+	errors     *prometheus.CounterVec
+	processes  prometheus.Gauge
+}
+
+// New creates the metrics and registers them with reg
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "pdftotext_conversion_duration_seconds",
+			Help:    "Duration of PDF to text conversions.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}),
+		outputSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "pdftotext_output_bytes",
+			Help:    "Size of the text of successful conversions.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 10),
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pdftotext_conversion_errors_total",
+			Help: "Failed conversions by error kind, see pdftotext.ErrorKind.",
+		}, []string{"kind"}),
+		processes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "pdftotext_child_processes",
+			Help: "Number of pdftotext child processes running.",
+		}),
+	}
+	for _, c := range []prometheus.Collector{m.duration, m.outputSize, m.errors, m.processes} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Middleware returns a pdftotext.Middleware observing the duration and the
+// output size of each conversion and counting failed conversions by
+// pdftotext.ErrorKind
+func (m *Metrics) Middleware() pdftotext.Middleware {
+	return func(next pdftotext.ConvertFunc) pdftotext.ConvertFunc {
+		return func(ctx context.Context, inputPath string, opts *pdftotext.Options) (string, error) {
+			start := time.Now()
+			text, err := next(ctx, inputPath, opts)
+			m.duration.Observe(time.Since(start).Seconds())
+			if err != nil {
+				m.errors.WithLabelValues(pdftotext.ErrorKind(err)).Inc()
+				return text, err
+			}
+			m.outputSize.Observe(float64(len(text)))
+			return text, nil
+		}
+	}
+}
+
+// Runner returns a pdftotext.Runner tracking the child processes run by next
+// in the child processes gauge. Converters with a custom runner do not look
+// up the binary in PATH up front, so a missing binary is only reported by the
+// first conversion.
+func (m *Metrics) Runner(next pdftotext.Runner) pdftotext.Runner {
+	return runner{next: next, processes: m.processes}
+}
+
+// runner is the pdftotext.Runner returned by Metrics.Runner
+type runner struct {
+	next      pdftotext.Runner
+	processes prometheus.Gauge
+}
+
+func (r runner) Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	r.processes.Inc()
+	defer r.processes.Dec()
+	return r.next.Run(ctx, name, args, stdout, stderr)
+}
@@ -0,0 +1,72 @@
+package pdfprometheus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// runnerFunc adapts a function to a pdftotext.Runner
+type runnerFunc func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error
+
+func (f runnerFunc) Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	return f(ctx, name, args, stdout, stderr)
+}
+
+func TestMetrics(t *testing.T) {
+	metrics, err := New(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	fail := false
+	converter, err := pdftotext.New(pdftotext.WithRunner(metrics.Runner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if n := testutil.ToFloat64(metrics.processes); n != 1 {
+			t.Errorf("expected 1 child process, got %v", n)
+		}
+		if fail {
+			return errors.New("boom")
+		}
+		_, err := io.WriteString(stdout, "some text")
+		return err
+	}))))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	convert := pdftotext.Chain(converter.Convert, metrics.Middleware())
+
+	ctx := context.Background()
+	if _, err := convert(ctx, "input.pdf", nil); err != nil {
+		t.Fatalf("failed to convert: %v", err)
+	}
+	fail = true
+	if _, err := convert(ctx, "input.pdf", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if n := testutil.CollectAndCount(metrics.duration); n != 1 {
+		t.Errorf("expected the duration histogram, got %d metrics", n)
+	}
+	if n := testutil.ToFloat64(metrics.errors.WithLabelValues("other")); n != 1 {
+		t.Errorf("expected 1 error of kind other, got %v", n)
+	}
+	if n := testutil.ToFloat64(metrics.processes); n != 0 {
+		t.Errorf("expected no child processes, got %v", n)
+	}
+}
+
+func TestNew_AlreadyRegistered(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := New(reg); err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+	if _, err := New(reg); err == nil {
+		t.Error("expected an error registering the metrics twice")
+	}
+}
@@ -2,12 +2,19 @@ package pdftotext
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 var (
@@ -21,10 +28,72 @@ var (
 	ErrInvalidPage = errors.New("invalid page number")
 	// ErrInvalidRange is returned when the page range is invalid
 	ErrInvalidRange = errors.New("invalid page range")
+	// ErrInvalidRegion is returned when a region has no name or no area
+	ErrInvalidRegion = errors.New("invalid region")
 	// ErrCommandFailed is returned when the pdftotext command fails
 	ErrCommandFailed = errors.New("pdftotext command failed")
+	// ErrOutOfMemory is returned, wrapped in ErrCommandFailed, when the
+	// binary reports that it ran out of memory
+	ErrOutOfMemory = errors.New("pdftotext ran out of memory")
 	// ErrBinaryNotFound is returned when the pdftotext binary is not found
 	ErrBinaryNotFound = errors.New("pdftotext binary not found")
+	// ErrEncrypted is returned when the PDF is encrypted and the password is missing or incorrect
+	ErrEncrypted = errors.New("PDF is encrypted and the password is missing or incorrect")
+	// ErrNotFound is returned when a key does not exist in a Storage or a
+	// Cache, or a job does not exist in a Pool
+	ErrNotFound = errors.New("not found")
+	// ErrExists is returned when creating a key that already exists in a
+	// CreateStorage
+	ErrExists = errors.New("already exists")
+	// ErrRejected is returned when a search engine rejects indexed documents
+	ErrRejected = errors.New("documents rejected by the search engine")
+	// ErrMissingLanguageData is returned when an output encoding or the
+	// character collections needed for CJK text are not installed, usually
+	// because the poppler-data package is missing
+	ErrMissingLanguageData = errors.New("poppler language data is missing, install poppler-data")
+	// ErrNotPDF is returned when a file is empty or does not start with a
+	// PDF header
+	ErrNotPDF = errors.New("file is not a PDF")
+	// ErrFileTooLarge is returned when a file exceeds the maximum file size
+	ErrFileTooLarge = errors.New("file is too large")
+	// ErrInputTooLarge is returned when an input exceeds
+	// Options.MaxInputBytes. It is the same error as ErrFileTooLarge.
+	ErrInputTooLarge = ErrFileTooLarge
+	// ErrVetoed is returned when a PreHook stops a conversion
+	ErrVetoed = errors.New("conversion vetoed by a pre hook")
+	// ErrPartial is returned with the text extracted before a conversion
+	// failed partway when Options.AllowPartial is set, wrapping the failure
+	ErrPartial = errors.New("conversion failed partway, the text is incomplete")
+	// ErrJobPending is returned for the result of a job that has not
+	// finished yet
+	ErrJobPending = errors.New("job has not finished")
+	// ErrJobFailed is returned for the result of a job whose conversion
+	// failed
+	ErrJobFailed = errors.New("job failed")
+	// ErrPoolClosed is returned when submitting to or running a Pool that
+	// was shut down
+	ErrPoolClosed = errors.New("pool is shut down")
+	// ErrJobExists is returned when submitting a job whose ID is already
+	// used by another job of the Pool
+	ErrJobExists = errors.New("job ID already exists")
+	// ErrQuotaExceeded is returned when a conversion would exceed the Quota
+	// of its key
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrChecksumMismatch is returned when an output file no longer matches
+	// the checksum recorded in its sidecar
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrPathNotAllowed is returned for paths outside the roots of
+	// WithAllowedRoots
+	ErrPathNotAllowed = errors.New("path is not allowed")
+	// ErrUnsupportedOption is returned for options the pdftotext binary does
+	// not support
+	ErrUnsupportedOption = errors.New("option is not supported by the pdftotext binary")
+	// ErrEmptyOutput is returned by ConvertToFile with Options.CheckOutput
+	// when pdftotext succeeded but the output file is empty
+	ErrEmptyOutput = errors.New("pdftotext produced no output")
+	// ErrKilled is returned when pdftotext was killed by a signal, see
+	// KilledError
+	ErrKilled = errors.New("pdftotext was killed by a signal")
 )
 
 // EOLType represents the end-of-line convention
@@ -37,6 +106,10 @@ const (
 	EOLDos EOLType = "dos"
 	// EOLMac represents the Mac end-of-line convention
 	EOLMac EOLType = "mac"
+	// EOLAuto selects the end-of-line convention of the host: DOS on
+	// Windows, Unix elsewhere, so native editors display the files written
+	// by cross-platform tools correctly
+	EOLAuto EOLType = "auto"
 )
 
 // Options represents the configuration options for the PDF conversion
@@ -45,6 +118,18 @@ type Options struct {
 	FirstPage int
 	// LastPage is the last page to convert
 	LastPage int
+	// Pages is a page range expression such as "1-3,7,12-" selecting the pages
+	// to convert, in order. It cannot be combined with FirstPage and LastPage.
+	Pages string
+	// ExcludePages is a page range expression such as "1,5-6" selecting pages
+	// to skip, e.g. cover sheets or fax headers
+	ExcludePages string
+	// TailPages converts only the last TailPages pages, e.g. signature pages.
+	// The page count is looked up with pdfinfo.
+	TailPages int
+	// PageLabels sets Page.Label in the page-level APIs to the page labels
+	// defined by the PDF, e.g. "iv" or "A-1"
+	PageLabels bool
 	// Resolution is the resolution in DPI (default 72)
 	Resolution int
 	// CropX is the X-coordinate of crop area
@@ -55,12 +140,23 @@ type Options struct {
 	CropWidth int
 	// CropHeight is the height of crop area
 	CropHeight int
+	// RotatedCrop sets how the crop area is applied to rotated pages, see
+	// RotatedCropMode. The rotation of the pages is looked up with pdfinfo.
+	RotatedCrop RotatedCropMode
 	// Layout maintains the original layout
 	Layout bool
 	// FixedPitch keeps the text in a fixed-pitch font
 	FixedPitch float64
 	// Raw keeps text in content stream order
 	Raw bool
+	// XpdfMode selects a layout mode of the Xpdf pdftotext, e.g. XpdfTable,
+	// instead of Layout or Raw. Conversions fail with ErrUnsupportedOption
+	// when WithStartupCheck detected poppler's pdftotext.
+	XpdfMode XpdfMode
+	// ReadingOrder rebuilds the text from the -bbox-layout blocks in reading
+	// order, reading multi-column pages column by column. Output options
+	// such as Layout and Raw are ignored.
+	ReadingOrder bool
 	// NoDiagonal discards diagonal text
 	NoDiagonal bool
 	// HTMLMeta generates HTML with meta information
@@ -77,81 +173,714 @@ type Options struct {
 	ColSpacing float64
 	// Encoding is the text output encoding (default UTF-8)
 	Encoding string
-	// EOL is the end-of-line convention (default Unix)
+	// EncodingFallback converts with UTF-8 when Encoding is not installed,
+	// reporting a warning to OnWarning instead of failing
+	EncodingFallback bool
+	// RequireLanguageData fails the conversion with ErrMissingLanguageData
+	// when pdftotext reports a missing character collection or CMap, which
+	// silently drops the characters of CJK text
+	RequireLanguageData bool
+	// EOL is the end-of-line convention (default Unix), or EOLAuto for the
+	// convention of the host
 	EOL EOLType
 	// NoPageBreaks don't insert page breaks
 	NoPageBreaks bool
+	// PageSeparator replaces the form feed between pages, e.g.
+	// "\n\n--- Page {n} ---\n\n", where {n} is the number of the following page
+	PageSeparator string
+	// DocumentHeader is written by ConvertMany before each document, e.g.
+	// "=== {name} ===\n\n", where {n} is the number of the document, {path}
+	// its path and {name} its file name
+	DocumentHeader string
+	// DocumentSeparator is written by ConvertMany between documents, with
+	// the placeholders of DocumentHeader for the following document. The
+	// documents are concatenated if it is empty, so their pages stay
+	// separated by form feeds.
+	DocumentSeparator string
+	// Transformers post-process the text of each page in order before it is
+	// returned or written
+	Transformers []Transformer
+	// Normalize applies a Unicode normalization form to the text before the
+	// Transformers, e.g. NFKC to replace ligatures such as "ﬁ" with "fi"
+	Normalize NormalizationForm
+	// LanguageDetector detects the language of each page in the page-level
+	// APIs, reported in Page.Language and Document.Language
+	LanguageDetector LanguageDetector
+	// EntityExtractor finds the named entities of each page, reported in
+	// Document.Entities by ConvertDocument and in the records of
+	// ConvertJSONL
+	EntityExtractor EntityExtractor
+	// Classifier labels each converted document of ConvertBatch and
+	// ConvertBatchMatching, reported in BatchResult.Label
+	Classifier Classifier
+	// OCR recognizes the text of the pages without a text layer, e.g.
+	// scanned pages in an otherwise searchable document, which are
+	// otherwise converted to empty pages. The pages keep their order and
+	// Page.Source tells where the text of each came from.
+	OCR OCR
+	// MinQuality is the ScoreQuality score below which the text of a page
+	// counts as garbled, as for fonts with broken ToUnicode maps. Garbled
+	// pages are extracted again with the GarbledStrategies. Zero disables
+	// the check.
+	MinQuality float64
+	// GarbledStrategies extract the garbled pages again, tried in order
+	// until one scores at least MinQuality. The best scoring text is kept,
+	// and Page.Source names the strategy it came from. It defaults to OCR
+	// when OCR is set.
+	GarbledStrategies []GarbledStrategy
+	// StopAfter is called with the number and text of each page after the
+	// Transformers and ends the conversion, killing pdftotext, after the
+	// first page it returns true for, e.g. once a "Signatures" section is
+	// found, saving the work on the rest of long documents
+	StopAfter func(page int, text string) bool
+	// MaxInputBytes rejects input files larger than MaxInputBytes bytes with
+	// ErrInputTooLarge before converting, and stops reading the input of
+	// ConvertReader as soon as it exceeds the limit, so services can enforce
+	// quotas. Zero means no limit.
+	MaxInputBytes int64
+	// MaxOutputBytes stops ConvertEx as soon as the text exceeds
+	// MaxOutputBytes bytes, reporting the page it was cut off at in
+	// ConvertResult.TruncatedAt. The other APIs ignore it. Zero means no
+	// limit.
+	MaxOutputBytes int64
+	// TimeoutPerPage and TimeoutPerMB bound how long a conversion may take
+	// by the number of selected pages and by the size of the input, so huge
+	// documents get proportionally more time while small stuck ones fail
+	// fast. The page count is looked up with pdfinfo unless every selected
+	// range has a last page. The timeout is at least MinTimeout and at most
+	// MaxTimeout when they are set, MaxTimeout or DefaultTimeout when it
+	// cannot be determined, and a conversion exceeding it fails with
+	// context.DeadlineExceeded. The page-level APIs and ConvertParallel bound
+	// each page range they convert separately.
+	TimeoutPerPage time.Duration
+	TimeoutPerMB   time.Duration
+	MinTimeout     time.Duration
+	MaxTimeout     time.Duration
+	// SplitPages makes ConvertToFile write each page to its own file in a
+	// single pass, named by PageFilePath: the output path with the page
+	// number before the extension, "doc_p0001.txt" for "doc.txt", or the
+	// output path formatted with the page number if it contains a verb such
+	// as "%03d". The files hold the page texts of StreamPages, and
+	// PostHooks are not called.
+	SplitPages bool
+	// Compression compresses the file written by ConvertToFile as it is
+	// produced, e.g. Gzip{}, saving archival jobs a pass. The extension of
+	// the compressor is appended to the output path unless it already ends
+	// with it. The other APIs ignore it.
+	Compression Compressor
+	// CheckOutput makes ConvertToFile read back the file it wrote and fail
+	// with ErrEmptyOutput if it is missing or empty, which pdftotext does for
+	// some corrupt PDFs without failing, and with ErrCommandFailed if text
+	// in UTF-8 is not valid UTF-8. It is ignored with SplitPages.
+	CheckOutput bool
+	// PreHooks are called in order with the input file before it is
+	// converted. An error from a hook stops the conversion, which fails with
+	// ErrVetoed wrapping it.
+	PreHooks []PreHook
+	// PostHooks are called in order with the result of a conversion before
+	// it is returned or written, after the Transformers and the
+	// PageSeparator. The text is held in memory until they return. The
+	// page-level APIs do not call them.
+	PostHooks []PostHook
+	// AllowPartial makes Convert and ConvertEx return the text extracted
+	// before the conversion failed partway, e.g. by a timeout, a cancellation
+	// or a crash, along with an error wrapping ErrPartial and the failure,
+	// instead of discarding it. Conversions failing before any text was
+	// extracted return their error as usual.
+	AllowPartial bool
+	// PageFallback converts the pages again one by one when a conversion
+	// crashes, so one corrupt page does not cost the text of the others. The
+	// pages that still fail are left empty and reported to OnPageSkipped.
+	// The text of each page range is held in memory until it is complete.
+	PageFallback bool
+	// OnPageSkipped is called with each page PageFallback skipped and its
+	// error
+	OnPageSkipped func(page int, err error)
+	// Deterministic guarantees byte-identical output for identical inputs
+	// and options across runs and hosts: the encoding and the line endings
+	// are passed explicitly instead of relying on the defaults of the
+	// binary, pdftotext runs with the C locale and in UTC, and the durations
+	// reported by ConvertEx and ConvertWithStats are zero
+	Deterministic bool
 	// OwnerPassword is the PDF owner password
 	OwnerPassword string
 	// UserPassword is the PDF user password
 	UserPassword string
+	// PasswordFunc looks up the passwords for an encrypted PDF. It is only
+	// called when a conversion fails with ErrEncrypted, and the conversion is
+	// retried once with the returned passwords.
+	PasswordFunc func(inputPath string) (user, owner string, err error)
+	// PasswordStore looks up the passwords for an encrypted PDF by the
+	// SHA-256 digest of the file, like PasswordFunc and instead of it
+	PasswordStore PasswordStore
+	// OnWarning is called with each message pdftotext prints to stderr while
+	// converting, such as "Syntax Warning: ...", as soon as it is printed
+	OnWarning func(message string)
 	// Quiet suppresses messages and errors
 	Quiet bool
 }
 
-// Converter represents a PDF to text converter
-type Converter struct{ binaryPath string }
+// TextConverter is the interface implemented by Converter, so code depending
+// on it can be tested with a fake such as pdftotexttest.FakeConverter
+type TextConverter interface {
+	// Convert converts a PDF file to text and returns the result
+	Convert(ctx context.Context, inputPath string, opts *Options) (string, error)
+	// ConvertToFile converts a PDF file to text and saves it to the specified output file
+	ConvertToFile(ctx context.Context, inputPath, outputPath string, opts *Options) error
+}
+
+var _ TextConverter = (*Converter)(nil)
+
+// TextExtractor is the interface implemented by Converter for applications
+// that read text and metadata, so they can depend on it, test with a fake
+// such as pdftotexttest.FakeConverter or swap in another backend
+type TextExtractor interface {
+	// Convert converts a PDF file to text and returns the result
+	Convert(ctx context.Context, inputPath string, opts *Options) (string, error)
+	// ConvertPages converts a PDF file to text and returns the text of each page
+	ConvertPages(ctx context.Context, inputPath string, opts *Options) ([]Page, error)
+	// Metadata returns the document information of a PDF file, such as its
+	// title and author
+	Metadata(ctx context.Context, inputPath string, opts *Options) (map[string]string, error)
+}
+
+var _ TextExtractor = (*Converter)(nil)
+
+// Converter represents a PDF to text converter. A Converter is not changed
+// after New and is safe for concurrent use by multiple goroutines. Its
+// methods never modify the Options passed to them, so one Options value may
+// be shared by concurrent conversions.
+type Converter struct {
+	binaryPath   string
+	runner       Runner
+	retries      int
+	retryBackoff time.Duration
+	retryable    func(error) bool
+	hash         HashAlgorithm
+	maxFileSize  int64
+	logger       *slog.Logger
+	startupCheck bool
+	startupTools []string
+	outputFS     OutputFS
+	tempDir      string
+	limit        semaphore
+	maxStderr    int
+
+	exitCodeTable ExitCodeTable
+	variant       Variant
+	allowedRoots  []string
+	memoryStaging bool
+}
+
+// ConverterOption configures a Converter
+type ConverterOption func(*Converter)
 
 // New creates a new Converter instance
-func New() (*Converter, error) {
-	binaryPath, err := exec.LookPath("pdftotext")
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrBinaryNotFound, err)
+func New(opts ...ConverterOption) (*Converter, error) {
+	c := &Converter{binaryPath: "pdftotext", runner: ExecRunner{}, retryable: IsTransient, hash: SHA256, maxFileSize: DefaultMaxFileSize, maxStderr: DefaultMaxStderr}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.setup(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Clone returns a copy of c with the overrides applied, e.g. with another
+// runner or retry policy for one tenant, leaving c unchanged. The startup
+// check is only run again if the overrides include WithStartupCheck. The
+// clone keeps the Variant detected by c unless the overrides change the
+// binary or the runner.
+func (c *Converter) Clone(overrides ...ConverterOption) (*Converter, error) {
+	clone := *c
+	clone.startupCheck = false
+	clone.startupTools = slices.Clone(c.startupTools)
+	clone.allowedRoots = slices.Clone(c.allowedRoots)
+	// runners may be funcs, which cannot be compared, so a new runner is
+	// detected by the overrides setting one
+	clone.runner = nil
+	for _, opt := range overrides {
+		opt(&clone)
+	}
+	runnerChanged := clone.runner != nil
+	if !runnerChanged {
+		clone.runner = c.runner
+	}
+	if runnerChanged || clone.binaryPath != c.binaryPath {
+		clone.variant = ""
+	}
+	if err := clone.setup(); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// setup looks up the binary and runs the startup check once the options of
+// a new Converter have been applied
+func (c *Converter) setup() error {
+	// a custom runner may not execute binaries from this host at all
+	if _, ok := c.runner.(ExecRunner); ok {
+		binaryPath, err := lookupBinary(c.binaryPath)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrBinaryNotFound, err)
+		}
+		c.binaryPath = binaryPath
+	}
+	if err := c.resolveRoots(); err != nil {
+		return err
+	}
+	if c.startupCheck {
+		return c.checkStartup()
+	}
+	return nil
+}
+
+// WithBinaryPath sets the pdftotext binary to use instead of looking it up in PATH
+func WithBinaryPath(path string) ConverterOption {
+	return func(c *Converter) {
+		c.binaryPath = path
 	}
-	return &Converter{binaryPath: binaryPath}, nil
 }
 
 // Convert converts a PDF file to text and returns the result
 func (c *Converter) Convert(ctx context.Context, inputPath string, opts *Options) (string, error) {
-	var stdout, stderr bytes.Buffer
+	var stdout bytes.Buffer
+	if err := c.ConvertTo(ctx, inputPath, &stdout, opts); err != nil {
+		if text := strings.TrimSpace(stdout.String()); text != "" && opts != nil && opts.AllowPartial {
+			return text, fmt.Errorf("%w: %w", ErrPartial, err)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
 
-	args := c.buildArgs(opts, inputPath, "-")
-	cmd := exec.CommandContext(ctx, c.binaryPath, args...)
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// ConvertTo converts a PDF file to text and streams the result to w as it is
+// produced, without buffering the whole text in memory
+func (c *Converter) ConvertTo(ctx context.Context, inputPath string, w io.Writer, opts *Options) error {
+	return c.run(ctx, inputPath, "-", opts, w)
+}
 
-	if err := cmd.Run(); err != nil {
-		return "", c.handleError(err, stderr.String())
+// ConvertReader converts a PDF read from r to text and returns the result.
+// The PDF is staged in a temporary file for the duration of the conversion,
+// see WithTempDir.
+func (c *Converter) ConvertReader(ctx context.Context, r io.Reader, opts *Options) (string, error) {
+	var limit int64
+	if opts != nil {
+		limit = opts.MaxInputBytes
 	}
-	return strings.TrimSpace(stdout.String()), nil
+	path, remove, err := c.stageReader(ctx, r, limit)
+	if err != nil {
+		return "", err
+	}
+	defer remove()
+	return c.Convert(trusted(ctx), path, opts)
 }
 
 // ConvertToFile converts a PDF file to text and saves it to the specified output file
 func (c *Converter) ConvertToFile(ctx context.Context, inputPath, outputPath string, opts *Options) error {
-	var stderr bytes.Buffer
+	if err := c.run(ctx, inputPath, outputPath, opts, nil); err != nil {
+		return err
+	}
+	if opts != nil && opts.CheckOutput && !opts.SplitPages && outputPath != "-" {
+		return c.checkOutput(ctx, outputPath, opts)
+	}
+	return nil
+}
+
+// Command returns the command ConvertToFile would run with ExecRunner without executing it, so
+// the exact command line can be reviewed. Use "-" as outputPath to get the
+// command Convert would run. The arguments include the passwords of opts, so
+// log CommandLine instead.
+func (c *Converter) Command(inputPath, outputPath string, opts *Options) (*exec.Cmd, error) {
+	if inputPath == "" {
+		return nil, fmt.Errorf("%w: input path is empty", ErrPDFOpen)
+	}
+	if multiRange(opts) {
+		return nil, fmt.Errorf("%w: Pages, ExcludePages and TailPages need the page ranges resolved at conversion time", ErrInvalidRange)
+	}
+	if opts != nil && (opts.PageSeparator != "" || len(transformers(opts)) > 0) {
+		return nil, fmt.Errorf("%w: PageSeparator, Transformers and Normalize are applied to the output of the command", ErrCommandFailed)
+	}
+	if opts != nil && opts.ReadingOrder {
+		return nil, fmt.Errorf("%w: ReadingOrder rebuilds the text from the output of the command", ErrCommandFailed)
+	}
+	if opts != nil && (opts.EncodingFallback || opts.RequireLanguageData) {
+		return nil, fmt.Errorf("%w: EncodingFallback and RequireLanguageData inspect the messages of the command", ErrCommandFailed)
+	}
+	if opts != nil && (len(opts.PreHooks) > 0 || len(opts.PostHooks) > 0) {
+		return nil, fmt.Errorf("%w: PreHooks and PostHooks are called around the command", ErrCommandFailed)
+	}
+	if recoversPages(opts) {
+		return nil, fmt.Errorf("%w: OCR and MinQuality replace the pages the command leaves empty or garbled", ErrCommandFailed)
+	}
+	if opts != nil && opts.StopAfter != nil {
+		return nil, fmt.Errorf("%w: StopAfter stops the command from the pages it has written", ErrCommandFailed)
+	}
+	if opts != nil && opts.RotatedCrop != RotatedCropIgnore && hasCrop(opts) {
+		return nil, fmt.Errorf("%w: RotatedCrop needs the rotation of the pages resolved at conversion time", ErrCommandFailed)
+	}
+	if err := c.checkXpdfMode(opts); err != nil {
+		return nil, err
+	}
+	return exec.Command(c.binaryPath, c.buildArgs(opts, inputPath, outputPath)...), nil
+}
+
+// CommandLine returns the command line of Command with the passwords masked,
+// for dry runs and logs
+func (c *Converter) CommandLine(inputPath, outputPath string, opts *Options) (string, error) {
+	cmd, err := c.Command(inputPath, outputPath, opts)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append([]string{cmd.Path}, maskPasswords(cmd.Args[1:])...), " "), nil
+}
+
+// run executes pdftotext, retrying once with the passwords from
+// opts.PasswordStore or opts.PasswordFunc when the document turns out to be
+// encrypted and with UTF-8 when opts.EncodingFallback is set and the encoding
+// is missing. Pages selected by opts.Pages, opts.TailPages and
+// opts.ExcludePages are converted range by range, pages are normalized and
+// passed through opts.Transformers, page breaks are replaced with
+// opts.PageSeparator, and with opts.ReadingOrder the text is rebuilt from the
+// block layout. The opts.PreHooks are called first and may stop the
+// conversion, the timeout options bound the whole conversion, and the
+// opts.PostHooks are called with the result before it is written. With
+// opts.OCR the pages without text are recognized, with opts.MinQuality the
+// garbled pages are extracted again, and opts.StopAfter ends the conversion
+// early. With opts.PageFallback a crashed conversion is repeated page by page.
+// The overrides attached to ctx with ContextWithOverrides are applied to opts
+// first.
+func (c *Converter) run(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	ctx, opts = applyOverrides(ctx, opts)
+	ctx, err := c.confine(ctx, inputPath, outputPath)
+	if err != nil {
+		return err
+	}
+	if err := c.checkXpdfMode(opts); err != nil {
+		return err
+	}
+	if opts != nil && opts.MaxInputBytes > 0 {
+		if stat, err := os.Stat(inputPath); err == nil && stat.Size() > opts.MaxInputBytes {
+			return inputTooLarge(inputPath, stat.Size(), opts.MaxInputBytes)
+		}
+	}
+	opts, err = runPreHooks(ctx, inputPath, opts)
+	if err != nil {
+		return err
+	}
+	ctx, opts, cancel := c.withTimeout(ctx, inputPath, opts)
+	defer cancel()
+	if opts != nil && opts.Deterministic {
+		ctx = withCommandEnv(ctx, deterministicEnv...)
+	}
+	if opts != nil && opts.SplitPages && outputPath != "-" {
+		return c.runSplitPages(ctx, inputPath, outputPath, opts)
+	}
+	if outputPath != "-" && (c.outputFS != nil || opts != nil && opts.Compression != nil) {
+		return c.runToOutput(ctx, inputPath, outputPath, opts)
+	}
+	if opts != nil && len(opts.PostHooks) > 0 {
+		return c.runPostHooked(ctx, inputPath, outputPath, opts, stdout)
+	}
+	if recoversPages(opts) || opts != nil && opts.StopAfter != nil {
+		return c.runStreamed(ctx, inputPath, outputPath, opts, stdout)
+	}
+	if opts != nil && (opts.PageSeparator != "" || len(transformers(opts)) > 0) {
+		return c.runRewritten(ctx, inputPath, outputPath, opts, stdout)
+	}
+	if opts != nil && opts.ReadingOrder {
+		return c.runReadingOrder(ctx, inputPath, outputPath, opts, stdout)
+	}
+	if multiRange(opts) {
+		return c.runPages(ctx, inputPath, outputPath, opts, stdout)
+	}
+	if opts != nil && opts.RotatedCrop != RotatedCropIgnore && hasCrop(opts) {
+		return c.runRotatedCrop(ctx, inputPath, outputPath, opts, stdout)
+	}
+	if opts != nil && opts.PageFallback {
+		return c.runPageFallback(ctx, inputPath, outputPath, opts, stdout)
+	}
+
+	var warn func(string)
+	if opts != nil {
+		warn = opts.OnWarning
+	}
+	var mu sync.Mutex
+	var missing []string
+	if opts != nil && opts.RequireLanguageData {
+		onWarning := warn
+		warn = func(message string) {
+			if isMissingLanguageData(message) {
+				mu.Lock()
+				missing = append(missing, message)
+				mu.Unlock()
+			}
+			if onWarning != nil {
+				onWarning(message)
+			}
+		}
+	}
 
-	args := c.buildArgs(opts, inputPath, outputPath)
-	cmd := exec.CommandContext(ctx, c.binaryPath, args...)
-	cmd.Stderr = &stderr
+	err = c.exec(ctx, c.buildArgs(opts, inputPath, outputPath), stdout, warn)
+	if errors.Is(err, ErrEncrypted) && opts != nil && (opts.PasswordFunc != nil || opts.PasswordStore != nil) {
+		creds, lookupErr := lookupPasswords(ctx, inputPath, opts)
+		if lookupErr != nil {
+			return fmt.Errorf("%w: password lookup failed: %w", ErrEncrypted, lookupErr)
+		}
+		retryOpts := *opts
+		retryOpts.UserPassword = creds.User
+		retryOpts.OwnerPassword = creds.Owner
+		retryOpts.PasswordFunc = nil
+		retryOpts.PasswordStore = nil
+		err = c.exec(ctx, c.buildArgs(&retryOpts, inputPath, outputPath), stdout, warn)
+	}
+	if errors.Is(err, ErrMissingLanguageData) && opts != nil && opts.EncodingFallback && !strings.EqualFold(opts.Encoding, "UTF-8") {
+		if opts.OnWarning != nil {
+			opts.OnWarning(fmt.Sprintf("encoding %s is not installed, converting with UTF-8", opts.Encoding))
+		}
+		fallbackOpts := *opts
+		fallbackOpts.Encoding = "UTF-8"
+		fallbackOpts.EncodingFallback = false
+		return c.run(ctx, inputPath, outputPath, &fallbackOpts, stdout)
+	}
+	if err == nil && len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingLanguageData, strings.Join(missing, "; "))
+	}
+	// pdftotext only reports a syntax error for files that are not PDFs
+	if errors.Is(err, ErrPDFOpen) && !errors.Is(err, ErrEncrypted) {
+		if kind := sniffFileType(inputPath); kind != "" {
+			return fmt.Errorf("%w: %w", &NotPDFError{Path: inputPath, Type: kind}, err)
+		}
+	}
+	return err
+}
+
+// exec runs pdftotext with args, retrying transient failures as configured by
+// WithRetry. Output already streamed to a writer cannot be taken back, so a
+// failed attempt is only retried if it wrote nothing, unless stdout is a
+// *bytes.Buffer that can be reset. Each run is counted in the expvar
+// counters.
+func (c *Converter) exec(ctx context.Context, args []string, stdout io.Writer, warn func(string)) (err error) {
+	buf, resettable := stdout.(*bytes.Buffer)
+	if stdout != nil && !resettable {
+		stdout = &countingWriter{w: stdout}
+	}
+
+	end := counters.start()
+	offset := 0
+	if resettable {
+		offset = buf.Len()
+	}
+	defer func() {
+		extracted := outputSize(args)
+		if resettable {
+			extracted = int64(max(buf.Len()-offset, 0))
+		} else if counter, ok := stdout.(*countingWriter); ok {
+			extracted = counter.n
+		}
+		end(extracted, err)
+	}()
+
+	// written reports whether the current attempt wrote some output
+	written := func() bool {
+		switch counter, ok := stdout.(*countingWriter); {
+		case resettable:
+			return buf.Len() > offset
+		case ok:
+			return counter.n > 0
+		default:
+			return outputSize(args) > 0
+		}
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.execOnce(ctx, args, stdout, warn, written)
+		if err == nil {
+			c.recordCommand(ctx, args)
+		}
+		if err == nil || attempt >= c.retries || ctx.Err() != nil || !c.retryable(err) {
+			return err
+		}
+		if counter, ok := stdout.(*countingWriter); ok && counter.n > 0 {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		// discard the partial output of the failed attempt
+		if resettable {
+			buf.Reset()
+		}
+	}
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
 
-	if err := cmd.Run(); err != nil {
+func (c *Converter) execOnce(ctx context.Context, args []string, stdout io.Writer, warn func(string), written func() bool) error {
+	stderr := c.newStderr()
+	var errOut io.Writer = stderr
+	if warn != nil {
+		lines := &lineWriter{fn: warn}
+		defer lines.Flush()
+		errOut = io.MultiWriter(stderr, lines)
+	}
+	if err := c.runCommand(ctx, c.binaryPath, args, stdout, errOut); err != nil {
+		// Partial is part of the message, so it is set before the error is
+		// formatted
+		if killed := asKilled(err); killed != nil {
+			killed.Partial = written()
+			err = killed
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+			// the exit code of a killed process means nothing, and is 1 on
+			// Windows, which would read as an unreadable PDF
+			return fmt.Errorf("%w: %w: %w", ErrCommandFailed, ctxErr, err)
+		}
 		return c.handleError(err, stderr.String())
 	}
 	return nil
 }
 
+// lineWriter calls fn with each non-empty line written to it
+type lineWriter struct {
+	fn      func(string)
+	pending []byte
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.pending = append(lw.pending, p...)
+	for {
+		i := bytes.IndexByte(lw.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		lw.emit(lw.pending[:i])
+		lw.pending = lw.pending[i+1:]
+	}
+}
+
+// Flush reports a final line without a trailing newline
+func (lw *lineWriter) Flush() {
+	lw.emit(lw.pending)
+	lw.pending = nil
+}
+
+func (lw *lineWriter) emit(line []byte) {
+	if line := strings.TrimSpace(string(line)); line != "" {
+		lw.fn(line)
+	}
+}
+
+// Version returns the version reported by the pdftotext binary
+func (c *Converter) Version(ctx context.Context) (string, error) {
+	version, _, err := c.versionOutput(ctx)
+	return version, err
+}
+
+// versionOutput runs the binary with -v and returns the version and the
+// output
+func (c *Converter) versionOutput(ctx context.Context) (string, string, error) {
+	var stdout bytes.Buffer
+	stderr := c.newStderr()
+
+	// poppler prints its version to stderr, xpdf to stdout
+	err := c.runCommand(ctx, c.binaryPath, []string{"-v"}, &stdout, stderr)
+	output := stderr.String() + stdout.String()
+	if version := parseVersion(output); version != "" {
+		return version, output, nil
+	}
+	if err != nil {
+		return "", output, c.handleError(err, output)
+	}
+	return "", output, fmt.Errorf("%w: unrecognized version output: %s", ErrCommandFailed, output)
+}
+
+// handleError maps the error of a command and its stderr to the sentinel
+// errors, with the passwords the command was run with masked in the message
 func (c *Converter) handleError(err error, stderr string) error {
-	if exitErr, ok := err.(*exec.ExitError); ok {
-		switch exitErr.ExitCode() {
-		case 1:
+	return redactError(c.classifyError(err, stderr), secretsOf(err))
+}
+
+func (c *Converter) classifyError(err error, stderr string) error {
+	if killed := asKilled(err); killed != nil {
+		return fmt.Errorf("%w: %w: %s", ErrCommandFailed, killed, stderr)
+	}
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) {
+		switch mapped := c.exitCodes()[exitErr.ExitCode()]; mapped {
+		case ErrPDFOpen:
+			if isPasswordError(stderr) {
+				return fmt.Errorf("%w: %w: %s", ErrPDFOpen, ErrEncrypted, stderr)
+			}
 			return fmt.Errorf("%w: %s", ErrPDFOpen, stderr)
-		case 2:
-			return fmt.Errorf("%w: %s", ErrOutputFile, stderr)
-		case 3:
-			return fmt.Errorf("%w: %s", ErrPermissions, stderr)
+		case ErrOutputFile, ErrPermissions:
+			return fmt.Errorf("%w: %s", mapped, stderr)
+		case nil, ErrCommandFailed:
+			if isMissingEncoding(stderr) {
+				return fmt.Errorf("%w: %w: %s", ErrCommandFailed, ErrMissingLanguageData, stderr)
+			}
+			return fmt.Errorf("%w: %w: %s", ErrCommandFailed, exitErr, stderr)
 		default:
-			return fmt.Errorf("%w: %s", ErrCommandFailed, stderr)
+			return fmt.Errorf("%w: %w: %w: %s", ErrCommandFailed, mapped, exitErr, stderr)
 		}
 	}
 	return fmt.Errorf("failed to run pdftotext: %w", err)
 }
 
+// parseVersion extracts the version number from the output of pdftotext -v
+func parseVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields); i++ {
+			if strings.EqualFold(fields[i], "version") {
+				return fields[i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// isPasswordError reports whether stderr indicates a missing or incorrect password
+func isPasswordError(stderr string) bool {
+	return strings.Contains(strings.ToLower(stderr), "incorrect password")
+}
+
 func (c *Converter) buildArgs(opts *Options, inputPath, outputPath string) []string {
 	if opts == nil {
 		opts = &Options{}
 	}
 
+	// deterministic output does not depend on the defaults of the binary
+	// and the platform
+	encoding, eol := opts.Encoding, opts.EOL
+	if eol == EOLAuto {
+		eol = hostEOL
+	}
+	if opts.Deterministic {
+		encoding, eol = cmp.Or(encoding, "UTF-8"), cmp.Or(eol, EOLUnix)
+	}
+
 	var args []string
+	if eol == "" && defaultUnixEOL {
+		args = append(args, "-eol", string(EOLUnix))
+	}
 
 	appendFlag := func(flag string, value any) {
 		switch v := value.(type) {
@@ -184,6 +913,9 @@ func (c *Converter) buildArgs(opts *Options, inputPath, outputPath string) []str
 	appendFlag("-layout", opts.Layout)
 	appendFlag("-fixed", opts.FixedPitch)
 	appendFlag("-raw", opts.Raw)
+	if opts.XpdfMode != "" {
+		args = append(args, "-"+string(opts.XpdfMode))
+	}
 	appendFlag("-nodiag", opts.NoDiagonal)
 	appendFlag("-htmlmeta", opts.HTMLMeta)
 	appendFlag("-bbox", opts.BBox)
@@ -191,16 +923,49 @@ func (c *Converter) buildArgs(opts *Options, inputPath, outputPath string) []str
 	appendFlag("-tsv", opts.TSV)
 	appendFlag("-cropbox", opts.CropBox)
 	appendFlag("-colspacing", opts.ColSpacing)
-	appendFlag("-enc", opts.Encoding)
-	appendFlag("-eol", string(opts.EOL))
+	appendFlag("-enc", encoding)
+	appendFlag("-eol", string(eol))
 	appendFlag("-nopgbrk", opts.NoPageBreaks)
 	appendFlag("-opw", opts.OwnerPassword)
 	appendFlag("-upw", opts.UserPassword)
 	appendFlag("-q", opts.Quiet)
 
-	args = append(args, inputPath)
+	args = append(args, escapePath(inputPath))
 	if outputPath != "" {
-		args = append(args, outputPath)
+		args = append(args, escapePath(outputPath))
 	}
 	return args
 }
+
+// escapePath returns path prefixed with "./" if it starts with "-", so user
+// controlled file names such as "-layout.pdf" are not parsed as flags by the
+// child process. Prefixing works with every pdftotext and poppler tool
+// version, unlike ending the flags with "--". A bare "-" is left alone, since
+// the tools read it as stdin or stdout.
+func escapePath(path string) string {
+	if path != "-" && strings.HasPrefix(path, "-") {
+		return "./" + path
+	}
+	return path
+}
+
+// valueFlags are the pdftotext flags that take a value
+var valueFlags = map[string]bool{
+	"-f": true, "-l": true, "-r": true, "-x": true, "-y": true, "-W": true, "-H": true,
+	"-fixed": true, "-colspacing": true, "-enc": true, "-eol": true, "-opw": true, "-upw": true,
+}
+
+// fileArgs returns the indexes of the input and output file arguments in a
+// pdftotext command line
+func fileArgs(args []string) []int {
+	var indexes []int
+	for i := 0; i < len(args); i++ {
+		switch {
+		case valueFlags[args[i]]:
+			i++
+		case args[i] == "-" || !strings.HasPrefix(args[i], "-"):
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
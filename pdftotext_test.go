@@ -3,8 +3,10 @@ package pdftotext
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -221,12 +223,40 @@ func TestConverter_BuildArgs(t *testing.T) {
 				"output.txt",
 			},
 		},
+		{
+			name:         "Host line endings",
+			options:      &Options{EOL: EOLAuto},
+			inputPath:    "input.pdf",
+			outputPath:   "output.txt",
+			expectedArgs: []string{"-eol", string(hostEOL), "input.pdf", "output.txt"},
+		},
 		{
 			name:         "Minimal options",
 			options:      nil,
 			inputPath:    "input.pdf",
 			outputPath:   "output.txt",
-			expectedArgs: []string{"input.pdf", "output.txt"},
+			expectedArgs: platformArgs("input.pdf", "output.txt"),
+		},
+		{
+			name:         "Paths starting with a dash",
+			options:      nil,
+			inputPath:    "-layout.pdf",
+			outputPath:   "-upw",
+			expectedArgs: platformArgs("./-layout.pdf", "./-upw"),
+		},
+		{
+			name:         "Stdin and stdout",
+			options:      nil,
+			inputPath:    "-",
+			outputPath:   "-",
+			expectedArgs: platformArgs("-", "-"),
+		},
+		{
+			name:         "File named dash",
+			options:      nil,
+			inputPath:    "./-",
+			outputPath:   "-",
+			expectedArgs: platformArgs("./-", "-"),
 		},
 	}
 
@@ -254,3 +284,253 @@ func TestConverter_BuildArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestConverter_Command(t *testing.T) {
+	binary := filepath.Join("usr", "bin", "pdftotext")
+	converter, err := New(WithBinaryPath(binary), WithRunner(runnerFunc(nil)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		options       *Options
+		inputPath     string
+		outputPath    string
+		expectedError error
+		expectedArgs  []string
+	}{
+		{
+			name:          "Empty input path",
+			expectedError: ErrPDFOpen,
+		},
+		{
+			name:         "Convert to stdout",
+			options:      &Options{Layout: true, FirstPage: 2},
+			inputPath:    "input.pdf",
+			outputPath:   "-",
+			expectedArgs: append([]string{binary}, platformArgs("-f", "2", "-layout", "input.pdf", "-")...),
+		},
+		{
+			name:         "Convert to file",
+			inputPath:    "input.pdf",
+			outputPath:   "output.txt",
+			expectedArgs: append([]string{binary}, platformArgs("input.pdf", "output.txt")...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := converter.Command(tt.inputPath, tt.outputPath, tt.options)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cmd.Process != nil {
+				t.Error("expected command not to be started")
+			}
+			if strings.Join(cmd.Args, " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("expected args %v, got %v", tt.expectedArgs, cmd.Args)
+			}
+		})
+	}
+}
+
+func TestConverter_PasswordFunc(t *testing.T) {
+	// The fake behaves like pdftotext on an encrypted PDF whose user password is "secret".
+	binary := writeFakeBinary(t, `for arg in "$@"; do
+	if [ "$prev" = "-upw" ] && [ "$arg" = "secret" ]; then
+		echo "unlocked"
+		exit 0
+	fi
+	prev=$arg
+done
+echo "Command Line Error: Incorrect password" >&2
+exit 1
+`)
+
+	tests := []struct {
+		name          string
+		options       *Options
+		expectedError error
+		expectedText  string
+		expectedCalls int
+	}{
+		{
+			name:          "No password func",
+			options:       nil,
+			expectedError: ErrEncrypted,
+		},
+		{
+			name: "Password func supplies password",
+			options: &Options{
+				PasswordFunc: func(string) (string, string, error) { return "secret", "", nil },
+			},
+			expectedText:  "unlocked",
+			expectedCalls: 1,
+		},
+		{
+			name: "Password func supplies wrong password",
+			options: &Options{
+				PasswordFunc: func(string) (string, string, error) { return "wrong", "", nil },
+			},
+			expectedError: ErrEncrypted,
+			expectedCalls: 1,
+		},
+		{
+			name: "Password func fails",
+			options: &Options{
+				PasswordFunc: func(string) (string, string, error) { return "", "", errors.New("vault unavailable") },
+			},
+			expectedError: ErrEncrypted,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithBinaryPath(binary))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			calls := 0
+			if tt.options != nil && tt.options.PasswordFunc != nil {
+				lookup := tt.options.PasswordFunc
+				tt.options.PasswordFunc = func(inputPath string) (string, string, error) {
+					calls++
+					if inputPath != "encrypted.pdf" {
+						t.Errorf("expected input path %q, got %q", "encrypted.pdf", inputPath)
+					}
+					return lookup(inputPath)
+				}
+			}
+
+			text, err := converter.Convert(context.Background(), "encrypted.pdf", tt.options)
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d password lookups, got %d", tt.expectedCalls, calls)
+			}
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				if !errors.Is(err, ErrPDFOpen) && tt.expectedCalls == 0 {
+					t.Errorf("expected error to also match %v, got %v", ErrPDFOpen, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, text)
+			}
+		})
+	}
+}
+
+// writeFakeBinary writes a shell script standing in for pdftotext and returns its path
+func writeFakeBinary(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of pdftotext")
+	}
+
+	path := filepath.Join(t.TempDir(), "pdftotext")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("failed to write fake binary: %v", err)
+	}
+	return path
+}
+
+func TestConverter_WindowsPaths(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(nil)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	for _, path := range []string{`C:\docs\in.pdf`, `\\server\share\in.pdf`} {
+		t.Run(path, func(t *testing.T) {
+			args := converter.buildArgs(&Options{Layout: true}, path, `D:\out\in.txt`)
+			files := fileArgs(args)
+			if len(files) != 2 || args[files[0]] != path || args[files[1]] != `D:\out\in.txt` {
+				t.Errorf("expected the paths to be passed unchanged, got %v", args)
+			}
+		})
+	}
+}
+
+func TestConverter_CanceledExit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		// a process killed on Windows exits with status 1
+		cancel()
+		return &ExitError{Code: 1}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	_, err = converter.Convert(ctx, "input.pdf", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+	if errors.Is(err, ErrPDFOpen) {
+		t.Errorf("expected error not to match %v, got %v", ErrPDFOpen, err)
+	}
+}
+
+func TestConverter_AllowPartial(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if args[len(args)-2] == "empty.pdf" {
+			return &ExitError{Code: -1}
+		}
+		io.WriteString(stdout, "page one\fpage tw")
+		return &ExitError{Code: -1}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		inputPath    string
+		options      *Options
+		expectedText string
+		partial      bool
+	}{
+		{name: "Discarded", inputPath: "input.pdf"},
+		{name: "Partial", inputPath: "input.pdf", options: &Options{AllowPartial: true}, expectedText: "page one\fpage tw", partial: true},
+		{name: "No text", inputPath: "empty.pdf", options: &Options{AllowPartial: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := converter.Convert(context.Background(), tt.inputPath, tt.options)
+			if !errors.Is(err, ErrCommandFailed) {
+				t.Errorf("expected error %v, got %v", ErrCommandFailed, err)
+			}
+			if errors.Is(err, ErrPartial) != tt.partial {
+				t.Errorf("expected partial %v, got %v", tt.partial, err)
+			}
+			if text != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, text)
+			}
+		})
+	}
+
+	result, err := converter.ConvertEx(context.Background(), "input.pdf", &Options{AllowPartial: true})
+	if !errors.Is(err, ErrPartial) {
+		t.Fatalf("expected error %v, got %v", ErrPartial, err)
+	}
+	if len(result.Pages) != 2 || result.Text != "page one\fpage tw" {
+		t.Errorf("expected the partial pages, got %+v", result)
+	}
+}
@@ -0,0 +1,209 @@
+// Package pdftotexttest provides a fake converter for testing code that
+// depends on pdftotext.TextConverter or pdftotext.TextExtractor without
+// poppler installed.
+package pdftotexttest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+var (
+	_ pdftotext.TextConverter = (*FakeConverter)(nil)
+	_ pdftotext.TextExtractor = (*FakeConverter)(nil)
+)
+
+// Call records a conversion or metadata lookup requested from a
+// FakeConverter
+type Call struct {
+	// InputPath is the path of the PDF file
+	InputPath string
+	// OutputPath is the output file, or "" for the other methods than
+	// ConvertToFile
+	OutputPath string
+	// Options are the conversion options
+	Options *pdftotext.Options
+}
+
+// FakeConverter is a pdftotext.TextConverter and pdftotext.TextExtractor
+// returning canned page text, metadata and errors. It honors the FirstPage,
+// LastPage, Pages, TailPages, ExcludePages, NoPageBreaks, PageSeparator,
+// Transformers and Normalize options. It is safe for concurrent use.
+type FakeConverter struct {
+	// Pages maps input paths to the text of their pages
+	Pages map[string][]string
+	// Meta maps input paths to the metadata Metadata returns
+	Meta map[string]map[string]string
+	// Errors maps input paths to the error their conversion returns
+	Errors map[string]error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Convert returns the canned text of inputPath
+func (f *FakeConverter) Convert(ctx context.Context, inputPath string, opts *pdftotext.Options) (string, error) {
+	text, err := f.convert(ctx, Call{InputPath: inputPath, Options: opts})
+	return strings.TrimSpace(text), err
+}
+
+// ConvertToFile writes the canned text of inputPath to outputPath
+func (f *FakeConverter) ConvertToFile(ctx context.Context, inputPath, outputPath string, opts *pdftotext.Options) error {
+	text, err := f.convert(ctx, Call{InputPath: inputPath, OutputPath: outputPath, Options: opts})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputPath, []byte(text), 0o644); err != nil {
+		return fmt.Errorf("%w: %v", pdftotext.ErrOutputFile, err)
+	}
+	return nil
+}
+
+// ConvertPages returns the canned pages of inputPath
+func (f *FakeConverter) ConvertPages(ctx context.Context, inputPath string, opts *pdftotext.Options) ([]pdftotext.Page, error) {
+	pages, _, err := f.pages(ctx, Call{InputPath: inputPath, Options: opts})
+	return pages, err
+}
+
+// Metadata returns the canned metadata of inputPath, or an empty map if it
+// has canned pages but no metadata
+func (f *FakeConverter) Metadata(ctx context.Context, inputPath string, opts *pdftotext.Options) (map[string]string, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{InputPath: inputPath, Options: opts})
+	err, failed := f.Errors[inputPath]
+	metadata, ok := f.Meta[inputPath]
+	_, hasPages := f.Pages[inputPath]
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if failed {
+		return nil, err
+	}
+	if !ok && !hasPages {
+		return nil, fmt.Errorf("%w: no fake metadata for %s", pdftotext.ErrPDFOpen, inputPath)
+	}
+	return maps.Clone(metadata), nil
+}
+
+// Calls returns the conversions requested so far
+func (f *FakeConverter) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+func (f *FakeConverter) convert(ctx context.Context, call Call) (string, error) {
+	pages, opts, err := f.pages(ctx, call)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i, page := range pages {
+		if opts.PageSeparator != "" {
+			if i > 0 {
+				b.WriteString(strings.ReplaceAll(opts.PageSeparator, "{n}", strconv.Itoa(page.Number)))
+			}
+			b.WriteString(page.Text)
+		} else {
+			b.WriteString(page.Text)
+			if !opts.NoPageBreaks {
+				b.WriteString("\f")
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// pages records call and returns the canned pages it selects with the
+// transformers applied, along with its options
+func (f *FakeConverter) pages(ctx context.Context, call Call) ([]pdftotext.Page, *pdftotext.Options, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	err, failed := f.Errors[call.InputPath]
+	pages, ok := f.Pages[call.InputPath]
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if failed {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no fake pages for %s", pdftotext.ErrPDFOpen, call.InputPath)
+	}
+
+	opts := call.Options
+	if opts == nil {
+		opts = &pdftotext.Options{}
+	}
+	ranges := []pdftotext.PageRange{{First: opts.FirstPage, Last: opts.LastPage}}
+	if opts.TailPages > 0 {
+		ranges[0].First = max(len(pages)-opts.TailPages+1, 1)
+	}
+	if opts.Pages != "" {
+		var err error
+		if ranges, err = pdftotext.ParsePageRange(opts.Pages); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var excluded []pdftotext.PageRange
+	if opts.ExcludePages != "" {
+		var err error
+		if excluded, err = pdftotext.ParsePageRange(opts.ExcludePages); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	selected := []pdftotext.Page{}
+	for _, r := range ranges {
+		first, last := 1, len(pages)
+		if r.First > 0 {
+			first = r.First
+		}
+		if r.Last > 0 && r.Last < last {
+			last = r.Last
+		}
+		if first > last {
+			return nil, nil, fmt.Errorf("%w: first page %d is after last page %d", pdftotext.ErrInvalidRange, first, last)
+		}
+
+		for i, page := range pages[first-1 : last] {
+			if isExcluded(first+i, excluded) {
+				continue
+			}
+			transformers := opts.Transformers
+			if opts.Normalize != "" {
+				transformers = append([]pdftotext.Transformer{opts.Normalize}, transformers...)
+			}
+			for _, t := range transformers {
+				var err error
+				if page, err = t.Transform(first+i, page); err != nil {
+					return nil, nil, err
+				}
+			}
+			selected = append(selected, pdftotext.Page{Number: first + i, Text: page})
+		}
+	}
+	return selected, opts, nil
+}
+
+// isExcluded reports whether page lies in one of the excluded ranges
+func isExcluded(page int, excluded []pdftotext.PageRange) bool {
+	for _, r := range excluded {
+		if page >= r.First && (r.Last == 0 || page <= r.Last) {
+			return true
+		}
+	}
+	return false
+}
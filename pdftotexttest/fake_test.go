@@ -0,0 +1,211 @@
+package pdftotexttest
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+func TestFakeConverter_Convert(t *testing.T) {
+	errBroken := errors.New("broken")
+	fake := &FakeConverter{
+		Pages:  map[string][]string{"doc.pdf": {"page one", "page two", "page three"}},
+		Errors: map[string]error{"broken.pdf": errBroken},
+	}
+
+	tests := []struct {
+		name          string
+		inputPath     string
+		options       *pdftotext.Options
+		expectedError error
+		expectedText  string
+	}{
+		{
+			name:         "All pages",
+			inputPath:    "doc.pdf",
+			expectedText: "page one\fpage two\fpage three",
+		},
+		{
+			name:         "Page range",
+			inputPath:    "doc.pdf",
+			options:      &pdftotext.Options{FirstPage: 2, LastPage: 3},
+			expectedText: "page two\fpage three",
+		},
+		{
+			name:         "Page range expression",
+			inputPath:    "doc.pdf",
+			options:      &pdftotext.Options{Pages: "3,1-2"},
+			expectedText: "page three\fpage one\fpage two",
+		},
+		{
+			name:         "Tail pages",
+			inputPath:    "doc.pdf",
+			options:      &pdftotext.Options{TailPages: 2},
+			expectedText: "page two\fpage three",
+		},
+		{
+			name:         "Excluded pages",
+			inputPath:    "doc.pdf",
+			options:      &pdftotext.Options{ExcludePages: "2"},
+			expectedText: "page one\fpage three",
+		},
+		{
+			name:         "No page breaks",
+			inputPath:    "doc.pdf",
+			options:      &pdftotext.Options{LastPage: 2, NoPageBreaks: true},
+			expectedText: "page onepage two",
+		},
+		{
+			name:      "Transformers",
+			inputPath: "doc.pdf",
+			options: &pdftotext.Options{LastPage: 2, Transformers: []pdftotext.Transformer{
+				pdftotext.TransformerFunc(func(page int, text string) (string, error) {
+					return strings.ToUpper(text), nil
+				}),
+			}},
+			expectedText: "PAGE ONE\fPAGE TWO",
+		},
+		{
+			name:         "Page separator",
+			inputPath:    "doc.pdf",
+			options:      &pdftotext.Options{ExcludePages: "2", PageSeparator: "\n-- {n} --\n"},
+			expectedText: "page one\n-- 3 --\npage three",
+		},
+		{
+			name:          "Invalid range",
+			inputPath:     "doc.pdf",
+			options:       &pdftotext.Options{FirstPage: 4},
+			expectedError: pdftotext.ErrInvalidRange,
+		},
+		{
+			name:          "Canned error",
+			inputPath:     "broken.pdf",
+			expectedError: errBroken,
+		},
+		{
+			name:          "Unknown file",
+			inputPath:     "missing.pdf",
+			expectedError: pdftotext.ErrPDFOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := fake.Convert(context.Background(), tt.inputPath, tt.options)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expectedText {
+				t.Errorf("expected %q, got %q", tt.expectedText, text)
+			}
+		})
+	}
+
+	if calls := fake.Calls(); len(calls) != len(tests) {
+		t.Errorf("expected %d recorded calls, got %d", len(tests), len(calls))
+	}
+}
+
+func TestFakeConverter_ConvertToFile(t *testing.T) {
+	fake := &FakeConverter{Pages: map[string][]string{"doc.pdf": {"page one", "page two"}}}
+	outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+	if err := fake.ConvertToFile(context.Background(), "doc.pdf", outputPath, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if string(content) != "page one\fpage two\f" {
+		t.Errorf("expected %q, got %q", "page one\fpage two\f", content)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].OutputPath != outputPath {
+		t.Errorf("unexpected calls %+v", calls)
+	}
+}
+
+func TestFakeConverter_ConvertPages(t *testing.T) {
+	fake := &FakeConverter{Pages: map[string][]string{"doc.pdf": {"page one", "page two", "page three"}}}
+
+	pages, err := fake.ConvertPages(context.Background(), "doc.pdf", &pdftotext.Options{ExcludePages: "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []pdftotext.Page{{Number: 1, Text: "page one"}, {Number: 3, Text: "page three"}}
+	if !slices.Equal(pages, expected) {
+		t.Errorf("expected %+v, got %+v", expected, pages)
+	}
+}
+
+func TestFakeConverter_Metadata(t *testing.T) {
+	errBroken := errors.New("broken")
+	fake := &FakeConverter{
+		Pages:  map[string][]string{"doc.pdf": {"page one"}, "plain.pdf": {"page one"}},
+		Meta:   map[string]map[string]string{"doc.pdf": {"title": "Report"}},
+		Errors: map[string]error{"broken.pdf": errBroken},
+	}
+
+	tests := []struct {
+		name             string
+		inputPath        string
+		expectedError    error
+		expectedMetadata map[string]string
+	}{
+		{
+			name:             "Canned metadata",
+			inputPath:        "doc.pdf",
+			expectedMetadata: map[string]string{"title": "Report"},
+		},
+		{
+			name:             "Pages without metadata",
+			inputPath:        "plain.pdf",
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:          "Canned error",
+			inputPath:     "broken.pdf",
+			expectedError: errBroken,
+		},
+		{
+			name:          "Unknown file",
+			inputPath:     "missing.pdf",
+			expectedError: pdftotext.ErrPDFOpen,
+		},
+	}
+
+	var extractor pdftotext.TextExtractor = fake
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := extractor.Metadata(context.Background(), tt.inputPath, nil)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !maps.Equal(metadata, tt.expectedMetadata) {
+				t.Errorf("expected %v, got %v", tt.expectedMetadata, metadata)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/joeychilson/pdftotext/pdfwasm
+
+go 1.23.2
+
+require (
+	github.com/joeychilson/pdftotext v0.0.0-00010101000000-000000000000
+	github.com/tetratelabs/wazero v1.8.2
+)
+
+replace github.com/joeychilson/pdftotext => ../
@@ -0,0 +1,178 @@
+// Package pdfwasm is an experimental pdftotext.Runner running a pdftotext
+// compiled to WebAssembly (WASI) under wazero instead of a child process. The
+// converter needs no poppler installation and runs anywhere the Go binary
+// does, and pdftotext runs in a sandbox that only sees the directories of its
+// input and output files, which suits untrusted uploads. It is a separate
+// module so the pdftotext package does not depend on wazero.
+//
+//	wasm, err := os.ReadFile("pdftotext.wasm")
+//	runner, err := pdfwasm.New(ctx, wasm, pdfwasm.WithMemoryLimit(512<<20))
+//	defer runner.Close(ctx)
+//	converter, err := pdftotext.New(pdftotext.WithRunner(runner))
+package pdfwasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// wasmPageSize is the size of a WebAssembly memory page
+const wasmPageSize = 64 << 10
+
+// Guest directories the input and output directories are mounted at
+const (
+	inputDir  = "/input"
+	outputDir = "/output"
+)
+
+// Option configures a Runner
+type Option func(*config)
+
+type config struct {
+	memoryLimit uint64
+}
+
+// WithMemoryLimit limits the memory of each pdftotext run to about bytes, so
+// a malicious PDF cannot exhaust the memory of the host. The default is the
+// 4 GiB a 32-bit WebAssembly module can address.
+func WithMemoryLimit(bytes uint64) Option {
+	return func(c *config) {
+		c.memoryLimit = bytes
+	}
+}
+
+// Runner is a pdftotext.Runner running a WASI build of pdftotext under
+// wazero. It is safe for concurrent use; each run instantiates a new module.
+type Runner struct {
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+}
+
+// New compiles wasm, a pdftotext built for WASI (wasm32-wasi), once for all
+// runs. Close releases the compiled module.
+func New(ctx context.Context, wasm []byte, opts ...Option) (*Runner, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	runtimeConfig := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	if cfg.memoryLimit > 0 {
+		runtimeConfig = runtimeConfig.WithMemoryLimitPages(uint32(max(cfg.memoryLimit/wasmPageSize, 1)))
+	}
+	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate WASI: %w", err)
+	}
+	module, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to compile pdftotext: %w", err)
+	}
+	return &Runner{runtime: runtime, module: module}, nil
+}
+
+// Close releases the runtime and the compiled module
+func (r *Runner) Close(ctx context.Context) error {
+	return r.runtime.Close(ctx)
+}
+
+// Run runs pdftotext with args, mounting the directory of the input file
+// read-only and the directory of the output file writable. Only pdftotext is
+// available; other poppler tools such as pdfinfo fail with exec.ErrNotFound.
+func (r *Runner) Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	tool := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if tool != "pdftotext" {
+		return &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	if stdout == nil {
+		stdout = io.Discard
+	}
+
+	guestArgs, fsConfig, err := sandbox(args)
+	if err != nil {
+		return err
+	}
+	moduleConfig := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(append([]string{"pdftotext"}, guestArgs...)...).
+		WithStdout(stdout).
+		WithStderr(stderr).
+		WithFSConfig(fsConfig)
+
+	module, err := r.runtime.InstantiateModule(ctx, r.module, moduleConfig)
+	if module != nil {
+		module.Close(ctx)
+	}
+	var exitErr *sys.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		case 0:
+			return nil
+		case sys.ExitCodeContextCanceled, sys.ExitCodeDeadlineExceeded:
+			return fmt.Errorf("%w: %w", ctx.Err(), &pdftotext.ExitError{Code: -1})
+		default:
+			return &pdftotext.ExitError{Code: int(exitErr.ExitCode())}
+		}
+	}
+	return err
+}
+
+// sandbox returns args with the input and output files replaced by their
+// paths in the guest, and the mounts of their directories
+func sandbox(args []string) ([]string, wazero.FSConfig, error) {
+	guestArgs := append([]string(nil), args...)
+	fsConfig := wazero.NewFSConfig()
+	for n, i := range fileArgs(args) {
+		if args[i] == "-" {
+			continue
+		}
+		abs, err := filepath.Abs(args[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		dir := inputDir
+		if n == 0 {
+			fsConfig = fsConfig.WithReadOnlyDirMount(filepath.Dir(abs), dir)
+		} else {
+			dir = outputDir
+			fsConfig = fsConfig.WithDirMount(filepath.Dir(abs), dir)
+		}
+		guestArgs[i] = path.Join(dir, filepath.Base(abs))
+	}
+	return guestArgs, fsConfig, nil
+}
+
+// valueFlags are the pdftotext flags followed by a value
+var valueFlags = map[string]bool{
+	"-f": true, "-l": true, "-r": true, "-x": true, "-y": true, "-W": true, "-H": true,
+	"-fixed": true, "-colspacing": true, "-enc": true, "-eol": true, "-opw": true, "-upw": true,
+}
+
+// fileArgs returns the indexes of the input and output file arguments in a
+// pdftotext command line
+func fileArgs(args []string) []int {
+	var indexes []int
+	for i := 0; i < len(args); i++ {
+		switch {
+		case valueFlags[args[i]]:
+			i++
+		case args[i] == "-" || !strings.HasPrefix(args[i], "-"):
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
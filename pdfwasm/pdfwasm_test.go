@@ -0,0 +1,91 @@
+package pdfwasm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+func TestSandbox(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.pdf")
+	output := filepath.Join(dir, "out", "in.txt")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "Stdout",
+			args:     []string{"-f", "2", "-upw", "secret", "-layout", input, "-"},
+			expected: []string{"-f", "2", "-upw", "secret", "-layout", "/input/in.pdf", "-"},
+		},
+		{
+			name:     "File",
+			args:     []string{"-eol", "unix", input, output},
+			expected: []string{"-eol", "unix", "/input/in.pdf", "/output/in.txt"},
+		},
+		{
+			name:     "No files",
+			args:     []string{"-v"},
+			expected: []string{"-v"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, _, err := sandbox(tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(args, tt.expected) {
+				t.Errorf("expected args %v, got %v", tt.expected, args)
+			}
+		})
+	}
+}
+
+// TestRunner converts a PDF with the WASI build of pdftotext named by
+// PDFTOTEXT_WASM
+func TestRunner(t *testing.T) {
+	wasmPath := os.Getenv("PDFTOTEXT_WASM")
+	if wasmPath == "" {
+		t.Skip("PDFTOTEXT_WASM is not set")
+	}
+	wasm, err := os.ReadFile(wasmPath)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", wasmPath, err)
+	}
+
+	ctx := context.Background()
+	runner, err := New(ctx, wasm, WithMemoryLimit(256<<20))
+	if err != nil {
+		t.Fatalf("failed to create runner: %v", err)
+	}
+	defer runner.Close(ctx)
+
+	converter, err := pdftotext.New(pdftotext.WithRunner(runner))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if err := converter.HealthCheck(ctx); err != nil {
+		t.Errorf("health check failed: %v", err)
+	}
+
+	_, err = converter.Convert(ctx, filepath.Join(t.TempDir(), "missing.pdf"), nil)
+	if !errors.Is(err, pdftotext.ErrPDFOpen) {
+		t.Errorf("expected error %v, got %v", pdftotext.ErrPDFOpen, err)
+	}
+
+	err = runner.Run(ctx, "pdfinfo", []string{"in.pdf"}, nil, nil)
+	if !errors.Is(err, exec.ErrNotFound) || !strings.Contains(err.Error(), "pdfinfo") {
+		t.Errorf("expected error %v, got %v", exec.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,201 @@
+package pdftotext
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PIIMatch is personal data found in a text
+type PIIMatch struct {
+	// Kind is the kind of data, e.g. "email"
+	Kind string
+	// Start and End are the byte offsets of the data in the text
+	Start, End int
+}
+
+// PIIDetector finds personal data in text
+type PIIDetector interface {
+	// DetectPII returns the personal data in text
+	DetectPII(text string) []PIIMatch
+}
+
+// PIIPattern is a PIIDetector finding the matches of a regular expression
+// that are not part of a longer word or number
+type PIIPattern struct {
+	// Kind is the kind of the matches
+	Kind string
+	// Regexp matches the data
+	Regexp *regexp.Regexp
+	// Valid optionally checks a match, e.g. its check digits
+	Valid func(match string) bool
+}
+
+// DetectPII returns the valid matches of p.Regexp in text
+func (p PIIPattern) DetectPII(text string) []PIIMatch {
+	var matches []PIIMatch
+	for _, loc := range p.Regexp.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+		if p.Valid != nil && !p.Valid(text[start:end]) {
+			continue
+		}
+		matches = append(matches, PIIMatch{Kind: p.Kind, Start: start, End: end})
+	}
+	return matches
+}
+
+// isWordRune reports whether r is a letter or a digit
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+var (
+	// EmailDetector finds email addresses
+	EmailDetector = PIIPattern{
+		Kind:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	}
+	// PhoneDetector finds phone numbers of 10 to 15 digits, or of 8 to 15
+	// digits with an international prefix, grouped by spaces, periods,
+	// hyphens or parentheses
+	PhoneDetector = PIIPattern{
+		Kind:   "phone",
+		Regexp: regexp.MustCompile(`\+?\(?\d[\d ().-]{6,}\d`),
+		Valid:  validPhone,
+	}
+	// SSNDetector finds US social security numbers written as 123-45-6789,
+	// skipping numbers that are never issued
+	SSNDetector = PIIPattern{
+		Kind:   "ssn",
+		Regexp: regexp.MustCompile(`\d{3}-\d{2}-\d{4}`),
+		Valid:  validSSN,
+	}
+	// IBANDetector finds international bank account numbers, with or
+	// without spaces, with valid check digits
+	IBANDetector = PIIPattern{
+		Kind:   "iban",
+		Regexp: regexp.MustCompile(`[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?`),
+		Valid:  validIBAN,
+	}
+)
+
+// DefaultPIIDetectors are the detectors of a Redactor without Detectors
+var DefaultPIIDetectors = []PIIDetector{IBANDetector, SSNDetector, EmailDetector, PhoneDetector}
+
+// validPhone reports whether s has the number of digits of a phone number
+func validPhone(s string) bool {
+	digits := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if strings.HasPrefix(s, "+") {
+		return digits >= 8 && digits <= 15
+	}
+	return digits >= 10 && digits <= 15
+}
+
+// validSSN reports whether s, formatted as 123-45-6789, is a social security
+// number that can be issued
+func validSSN(s string) bool {
+	area, group, serial := s[0:3], s[4:6], s[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// validIBAN reports whether the check digits of an IBAN are valid
+func validIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	// move the country code and check digits to the end, replace the
+	// letters with numbers, A = 10 to Z = 35, and take the result modulo 97
+	remainder := 0
+	for _, r := range s[4:] + s[:4] {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			remainder = (remainder*100 + int(r-'A'+10)) % 97
+		case r >= '0' && r <= '9':
+			remainder = (remainder*10 + int(r-'0')) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// Redaction is personal data masked by a Redactor
+type Redaction struct {
+	// Page is the number of the page
+	Page int `json:"page"`
+	// Kind is the kind of the data, e.g. "email"
+	Kind string `json:"kind"`
+	// Offset is the byte offset of the mask in the redacted text of the
+	// page
+	Offset int `json:"offset"`
+}
+
+// Redactor is a Transformer masking personal data, such as email addresses,
+// phone numbers, social security numbers and IBANs, before the text is
+// indexed. Where the matches of several detectors overlap, the first
+// starting and then the longest one is masked.
+type Redactor struct {
+	// Detectors find the data to mask (default DefaultPIIDetectors)
+	Detectors []PIIDetector
+	// Mask replaces the data, with the placeholder {kind} replaced with its
+	// kind (default "[REDACTED]")
+	Mask string
+	// OnRedact is optionally called with each masked piece of data, e.g. to
+	// keep a report of what was redacted on which page
+	OnRedact func(Redaction)
+}
+
+// Transform masks the personal data in text
+func (r Redactor) Transform(page int, text string) (string, error) {
+	detectors := r.Detectors
+	if detectors == nil {
+		detectors = DefaultPIIDetectors
+	}
+	mask := r.Mask
+	if mask == "" {
+		mask = "[REDACTED]"
+	}
+
+	var matches []PIIMatch
+	for _, d := range detectors {
+		matches = append(matches, d.DetectPII(text)...)
+	}
+	if len(matches) == 0 {
+		return text, nil
+	}
+	slices.SortStableFunc(matches, func(a, b PIIMatch) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return b.End - a.End
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m.Start < last {
+			continue
+		}
+		b.WriteString(text[last:m.Start])
+		if r.OnRedact != nil {
+			r.OnRedact(Redaction{Page: page, Kind: m.Kind, Offset: b.Len()})
+		}
+		b.WriteString(strings.ReplaceAll(mask, "{kind}", m.Kind))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
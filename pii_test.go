@@ -0,0 +1,85 @@
+package pdftotext
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	tests := []struct {
+		name     string
+		redactor Redactor
+		text     string
+		expected string
+	}{
+		{
+			name:     "Email",
+			text:     "Contact jane.doe@example.co.uk for details.",
+			expected: "Contact [REDACTED] for details.",
+		},
+		{
+			name:     "Phone",
+			text:     "Call (555) 123-4567 or +49 30 1234567.",
+			expected: "Call [REDACTED] or [REDACTED].",
+		},
+		{
+			name:     "SSN",
+			text:     "SSN 123-45-6789, not 000-12-3456 or 666-12-3456",
+			expected: "SSN [REDACTED], not 000-12-3456 or 666-12-3456",
+		},
+		{
+			name:     "IBAN",
+			text:     "Pay to DE89 3704 0044 0532 0130 00 or GB82WEST12345698765432, not DE00 3704 0044 0532 0130 00",
+			expected: "Pay to [REDACTED] or [REDACTED], not DE00 3704 0044 0532 0130 00",
+		},
+		{
+			name:     "NotPhone",
+			text:     "Invoice 2024-01-15, order 12345678, version 1.2.3",
+			expected: "Invoice 2024-01-15, order 12345678, version 1.2.3",
+		},
+		{
+			name:     "Mask",
+			redactor: Redactor{Mask: "<{kind}>"},
+			text:     "Mail a@b.io or call 555.123.4567",
+			expected: "Mail <email> or call <phone>",
+		},
+		{
+			name: "CustomDetector",
+			redactor: Redactor{Detectors: []PIIDetector{
+				PIIPattern{Kind: "employee", Regexp: regexp.MustCompile(`EMP-\d{5}`)},
+			}},
+			text:     "Approved by EMP-12345 (a@b.io)",
+			expected: "Approved by [REDACTED] (a@b.io)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.redactor.Transform(1, tt.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestRedactor_OnRedact(t *testing.T) {
+	var redactions []Redaction
+	redactor := Redactor{Mask: "[{kind}]", OnRedact: func(r Redaction) {
+		redactions = append(redactions, r)
+	}}
+	text, err := redactor.Transform(3, "a@b.io, 123-45-6789")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "[email], [ssn]" {
+		t.Errorf("unexpected text %q", text)
+	}
+	expected := []Redaction{{Page: 3, Kind: "email", Offset: 0}, {Page: 3, Kind: "ssn", Offset: 9}}
+	if fmt.Sprint(redactions) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, redactions)
+	}
+}
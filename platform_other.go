@@ -0,0 +1,18 @@
+//go:build !windows
+
+package pdftotext
+
+import "os/exec"
+
+// defaultUnixEOL is set where pdftotext ends lines with CRLF by default, so
+// the output matches the Unix line endings of the other platforms unless
+// Options.EOL asks for others
+const defaultUnixEOL = false
+
+// hostEOL is the end-of-line convention EOLAuto selects, Unix here
+const hostEOL = EOLUnix
+
+// lookupBinary looks up a binary in PATH
+func lookupBinary(name string) (string, error) {
+	return exec.LookPath(name)
+}
@@ -0,0 +1,37 @@
+package pdftotext
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// defaultUnixEOL is set where pdftotext ends lines with CRLF by default, so
+// the output matches the Unix line endings of the other platforms unless
+// Options.EOL asks for others
+const defaultUnixEOL = true
+
+// hostEOL is the end-of-line convention EOLAuto selects, DOS here
+const hostEOL = EOLDos
+
+// lookupBinary looks up a binary in PATH and then next to the executable,
+// where Windows applications usually bundle the poppler binaries
+func lookupBinary(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err == nil {
+		return path, nil
+	}
+	if filepath.Base(name) != name {
+		return "", err
+	}
+	exe, exeErr := os.Executable()
+	if exeErr != nil {
+		return "", err
+	}
+	bundled, bundledErr := exec.LookPath(filepath.Join(filepath.Dir(exe), name))
+	if bundledErr != nil {
+		return "", errors.Join(err, bundledErr)
+	}
+	return bundled, nil
+}
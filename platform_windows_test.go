@@ -0,0 +1,68 @@
+package pdftotext
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestConverter_BuildArgsWindowsEOL(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(nil)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		options  *Options
+		expected []string
+	}{
+		{name: "Default", expected: []string{"-eol", "unix", "in.pdf", "-"}},
+		{name: "Explicit", options: &Options{EOL: EOLDos}, expected: []string{"-eol", "dos", "in.pdf", "-"}},
+		{name: "Auto", options: &Options{EOL: EOLAuto}, expected: []string{"-eol", "dos", "in.pdf", "-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if args := converter.buildArgs(tt.options, "in.pdf", "-"); !slices.Equal(args, tt.expected) {
+				t.Errorf("expected args %v, got %v", tt.expected, args)
+			}
+		})
+	}
+}
+
+func TestLookupBinary_NextToExecutable(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to find the test executable: %v", err)
+	}
+	path := filepath.Join(filepath.Dir(exe), "pdftotext-bundled.exe")
+	if err := os.WriteFile(path, nil, 0o755); err != nil {
+		t.Fatalf("failed to write bundled binary: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(path) })
+
+	got, err := lookupBinary("pdftotext-bundled")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sameFile(t, got, path) {
+		t.Errorf("expected %q, got %q", path, got)
+	}
+	if _, err := lookupBinary(`C:\missing\pdftotext-bundled`); err == nil {
+		t.Error("expected an error for a missing path outside the executable directory")
+	}
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	sa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	sb, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(sa, sb)
+}
@@ -0,0 +1,573 @@
+package pdftotext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job is a conversion submitted to a Pool. Jobs only hold plain data, so
+// queues and storages outside the process can serialize them as JSON.
+type Job struct {
+	// ID identifies the job. Submit assigns a random ID if it is empty.
+	ID string `json:"id"`
+	// InputPath is the PDF file to convert, which the workers must be able
+	// to read
+	InputPath string `json:"input_path"`
+	// Profile selects the options of the job from Pool.Profiles, or
+	// Pool.Options if it is empty
+	Profile string `json:"profile,omitempty"`
+	// Class is the priority class of the job (default ClassBatch)
+	Class JobClass `json:"class,omitempty"`
+	// Tenant is the key the job is charged to by Pool.Quota
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// JobClass is the priority class of a job, so a user waiting for a document
+// is not stuck behind a backfill of thousands
+type JobClass string
+
+const (
+	// ClassInteractive is a job someone is waiting for, taken ahead of
+	// the batch jobs
+	ClassInteractive JobClass = "interactive"
+	// ClassBatch is a background job
+	ClassBatch JobClass = "batch"
+)
+
+// Queue holds the jobs submitted to a Pool until a worker takes them. The
+// Pool defaults to an in-memory queue; implementations backed by Redis, SQS
+// or similar let web servers and workers run in separate processes.
+// Implementations must be safe for concurrent use.
+type Queue interface {
+	// Push adds a job to the queue
+	Push(ctx context.Context, job Job) error
+	// Pop removes the next job from the queue, waiting until there is one
+	// or ctx is done
+	Pop(ctx context.Context) (Job, error)
+}
+
+// MemoryQueue is an unbounded Queue in memory, first-in, first-out within
+// each priority class. While both classes have jobs waiting, Pop takes
+// InteractiveShare interactive jobs for each batch job, so interactive jobs
+// go first without starving the batch ones. The zero value is an empty queue.
+type MemoryQueue struct {
+	// InteractiveShare is the number of interactive jobs taken for each
+	// batch job while both are waiting (default 4)
+	InteractiveShare int
+
+	mu          sync.Mutex
+	interactive []Job
+	batch       []Job
+	streak      int
+	ready       chan struct{}
+}
+
+// signal returns the channel signaling that jobs are waiting, with mu held
+func (q *MemoryQueue) signal() chan struct{} {
+	if q.ready == nil {
+		q.ready = make(chan struct{}, 1)
+	}
+	return q.ready
+}
+
+// Push appends job to the queue of its priority class
+func (q *MemoryQueue) Push(_ context.Context, job Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job.Class == ClassInteractive {
+		q.interactive = append(q.interactive, job)
+	} else {
+		q.batch = append(q.batch, job)
+	}
+	select {
+	case q.signal() <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pop removes the next job from the queue
+func (q *MemoryQueue) Pop(ctx context.Context) (Job, error) {
+	for {
+		q.mu.Lock()
+		ready := q.signal()
+		if job, ok := q.next(); ok {
+			// wake the next waiting worker for the remaining jobs
+			if len(q.interactive)+len(q.batch) > 0 {
+				select {
+				case ready <- struct{}{}:
+				default:
+				}
+			}
+			q.mu.Unlock()
+			return job, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return Job{}, ctx.Err()
+		}
+	}
+}
+
+// next removes the job to run next, with mu held
+func (q *MemoryQueue) next() (Job, bool) {
+	share := q.InteractiveShare
+	if share <= 0 {
+		share = 4
+	}
+	if len(q.interactive) > 0 && (len(q.batch) == 0 || q.streak < share) {
+		job := q.interactive[0]
+		q.interactive = q.interactive[1:]
+		q.streak++
+		return job, true
+	}
+	if len(q.batch) > 0 {
+		job := q.batch[0]
+		q.batch = q.batch[1:]
+		q.streak = 0
+		return job, true
+	}
+	return Job{}, false
+}
+
+// JobState is the state of a job in a Pool
+type JobState string
+
+const (
+	// JobQueued is a job waiting for a worker
+	JobQueued JobState = "queued"
+	// JobRunning is a job being converted
+	JobRunning JobState = "running"
+	// JobDone is a job converted successfully
+	JobDone JobState = "done"
+	// JobFailed is a job whose conversion failed
+	JobFailed JobState = "failed"
+)
+
+// JobStatus is the state and, once it finished, the result of a job
+type JobStatus struct {
+	// Job is the submitted job
+	Job Job `json:"job"`
+	// State is the state of the job
+	State JobState `json:"state"`
+	// Text is the converted text of a done job
+	Text string `json:"text,omitempty"`
+	// Error is the error message of a failed job
+	Error string `json:"error,omitempty"`
+	// SubmittedAt, StartedAt and FinishedAt are the times the job was
+	// submitted, taken by a worker and finished, zero until then
+	SubmittedAt time.Time `json:"submitted_at"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// Pool converts jobs asynchronously, so web handlers can accept uploads
+// instantly with Submit and let clients poll Status or Result while Run
+// converts the jobs in the background. The statuses are kept in Storage
+// under "jobs/<id>.json", so any process sharing the Queue and the Storage
+// can answer the polls.
+type Pool struct {
+	// Converter performs the conversions
+	Converter *Converter
+	// Options are the options of jobs without a Profile
+	Options *Options
+	// Profiles are the options of jobs by their Profile
+	Profiles map[string]*Options
+	// Workers is the number of concurrent conversions of Run (default
+	// GOMAXPROCS)
+	Workers int
+	// Queue holds the submitted jobs (default a MemoryQueue)
+	Queue Queue
+	// Storage keeps the statuses of the jobs (default a MemoryStorage)
+	Storage Storage
+	// OnFinish is optionally called with the status of each job once it is
+	// done or failed, e.g. to publish the result to a message bus
+	OnFinish func(ctx context.Context, status *JobStatus)
+	// Quota optionally limits the pages and bytes submitted per Tenant
+	Quota *Quota
+
+	init     sync.Once
+	mu       sync.Mutex
+	creating sync.Mutex
+	closed   bool
+	killed   bool
+	stopping chan struct{}
+	kill     chan struct{}
+	workers  sync.WaitGroup
+	running  map[string]Job
+}
+
+// setDefaults fills in the defaults of the unset fields
+func (p *Pool) setDefaults() {
+	p.init.Do(func() {
+		if p.Queue == nil {
+			p.Queue = &MemoryQueue{}
+		}
+		if p.Storage == nil {
+			p.Storage = &MemoryStorage{}
+		}
+		p.stopping = make(chan struct{})
+		p.kill = make(chan struct{})
+		p.running = make(map[string]Job)
+	})
+}
+
+// Submit queues job and returns its ID, or ErrPoolClosed once the pool was
+// shut down. The ID is reserved by creating the status of the job, so a job
+// with an ID already in Storage is rejected with ErrJobExists rather than
+// overwriting the status of the other job. The reservation is atomic across
+// processes with a CreateStorage, and within the pool otherwise. With a
+// Quota, the job is then charged to its Tenant, for the pages its options
+// select, and ErrQuotaExceeded is returned if that exceeds the quota. The
+// charge is refunded if the job cannot be queued.
+func (p *Pool) Submit(ctx context.Context, job Job) (string, error) {
+	p.setDefaults()
+	if p.isClosed() {
+		return "", ErrPoolClosed
+	}
+	if job.ID == "" {
+		id, err := newJobID()
+		if err != nil {
+			return "", err
+		}
+		job.ID = id
+	}
+	if err := validateKey(jobKey(job.ID)); err != nil {
+		return "", err
+	}
+	status := &JobStatus{Job: job, State: JobQueued, SubmittedAt: time.Now()}
+	if err := p.createStatus(ctx, status); err != nil {
+		return "", err
+	}
+	// release frees the ID of a job that is not queued after all
+	release := func() {
+		p.Storage.Delete(context.WithoutCancel(ctx), jobKey(job.ID))
+	}
+
+	var charged QuotaCharge
+	if p.Quota != nil {
+		opts, err := p.jobOptions(job)
+		if err == nil {
+			charged, err = p.Quota.ChargeFile(ctx, p.Converter, job.Tenant, job.InputPath, opts)
+		}
+		if err != nil {
+			release()
+			return "", err
+		}
+	}
+	if err := p.Queue.Push(ctx, job); err != nil {
+		release()
+		err = fmt.Errorf("failed to queue job %s: %w", job.ID, err)
+		if p.Quota != nil {
+			// the job will not run, so it must not count against the quota
+			err = errors.Join(err, p.Quota.Refund(context.WithoutCancel(ctx), charged))
+		}
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// Status returns the status of the job with the given ID, or ErrNotFound
+func (p *Pool) Status(ctx context.Context, id string) (*JobStatus, error) {
+	p.setDefaults()
+	data, err := p.Storage.Get(ctx, jobKey(id))
+	if err != nil {
+		return nil, err
+	}
+	var status JobStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to decode status of job %s: %w", id, err)
+	}
+	return &status, nil
+}
+
+// Result returns the text of the job with the given ID once it is done. It
+// returns ErrJobPending while the job is queued or running, and ErrJobFailed
+// with the error message of a failed job.
+func (p *Pool) Result(ctx context.Context, id string) (string, error) {
+	status, err := p.Status(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	switch status.State {
+	case JobDone:
+		return status.Text, nil
+	case JobFailed:
+		return "", fmt.Errorf("%w: %s", ErrJobFailed, status.Error)
+	default:
+		return "", fmt.Errorf("%w: job %s is %s", ErrJobPending, id, status.State)
+	}
+}
+
+// Run converts the queued jobs with Workers concurrent conversions until ctx
+// is done or the pool is shut down, then waits for the workers to stop. It
+// returns the error of ctx, nil after a Shutdown, or ErrPoolClosed if the
+// pool was already shut down. Conversions still running when ctx is done
+// are canceled and fail.
+func (p *Pool) Run(ctx context.Context) error {
+	p.setDefaults()
+	workers := p.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	p.workers.Add(workers)
+	p.mu.Unlock()
+
+	// taking jobs stops when the pool is shut down, while the conversions
+	// in flight are only canceled when Shutdown gives up waiting for them
+	popCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	convertCtx, kill := context.WithCancel(ctx)
+	defer kill()
+	go func() {
+		select {
+		case <-p.stopping:
+			stop()
+		case <-popCtx.Done():
+			return
+		}
+		select {
+		case <-p.kill:
+			kill()
+		case <-convertCtx.Done():
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			defer p.workers.Done()
+			// a queue may return a waiting job even though ctx is done
+			for popCtx.Err() == nil {
+				job, err := p.Queue.Pop(popCtx)
+				if err != nil {
+					if popCtx.Err() != nil {
+						return
+					}
+					// a failing queue is retried after a pause
+					select {
+					case <-time.After(time.Second):
+						continue
+					case <-popCtx.Done():
+						return
+					}
+				}
+				p.runJob(convertCtx, job)
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// Shutdown stops the pool from accepting and taking jobs, and waits for the
+// conversions in flight to finish or ctx to be done, for example at the
+// deadline of a rollout. The conversions still running then are canceled,
+// killing their pdftotext processes, and Shutdown returns their jobs, marked
+// failed, with the error of ctx. Jobs left in the queue stay queued for the
+// next pool to take.
+func (p *Pool) Shutdown(ctx context.Context) ([]Job, error) {
+	p.setDefaults()
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.stopping)
+	}
+	p.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil, nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	abandoned := make([]Job, 0, len(p.running))
+	for _, job := range p.running {
+		abandoned = append(abandoned, job)
+	}
+	if !p.killed {
+		p.killed = true
+		close(p.kill)
+	}
+	p.mu.Unlock()
+	slices.SortFunc(abandoned, func(a, b Job) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	<-stopped
+	return abandoned, ctx.Err()
+}
+
+// Recover queues again the jobs whose statuses in Storage are queued or
+// running, and returns them. Calling it before Run when a service restarts
+// resumes the jobs interrupted by a crash or a deploy instead of losing them,
+// provided the Storage outlives the process, e.g. a DirStorage or the bbolt
+// storage of the pdfbolt module. It must only be called while no other pool
+// shares the Storage, since it cannot tell their running jobs from
+// interrupted ones, and only with a Queue that lost its jobs, such as a
+// MemoryQueue, since jobs still in the queue would run twice.
+func (p *Pool) Recover(ctx context.Context) ([]Job, error) {
+	p.setDefaults()
+	keys, err := p.Storage.List(ctx, "jobs/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	var recovered []Job
+	for _, key := range keys {
+		id := strings.TrimSuffix(strings.TrimPrefix(key, "jobs/"), ".json")
+		status, err := p.Status(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return recovered, err
+		}
+		if status.State != JobQueued && status.State != JobRunning {
+			continue
+		}
+		status.State, status.StartedAt = JobQueued, time.Time{}
+		if err := p.saveStatus(ctx, status); err != nil {
+			return recovered, err
+		}
+		if err := p.Queue.Push(ctx, status.Job); err != nil {
+			return recovered, fmt.Errorf("failed to queue job %s: %w", id, err)
+		}
+		recovered = append(recovered, status.Job)
+	}
+	return recovered, nil
+}
+
+// isClosed reports whether the pool was shut down
+func (p *Pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// runJob converts job and records its status
+func (p *Pool) runJob(ctx context.Context, job Job) {
+	p.mu.Lock()
+	p.running[job.ID] = job
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.running, job.ID)
+		p.mu.Unlock()
+	}()
+
+	status := &JobStatus{Job: job, State: JobRunning, StartedAt: time.Now()}
+	if previous, err := p.Status(ctx, job.ID); err == nil {
+		status.SubmittedAt = previous.SubmittedAt
+	}
+	// a status that cannot be saved is saved again when the job finishes
+	_ = p.saveStatus(ctx, status)
+
+	var text string
+	opts, err := p.jobOptions(job)
+	if err == nil {
+		text, err = p.Converter.Convert(ctx, job.InputPath, opts)
+	}
+	status.FinishedAt = time.Now()
+	if err != nil {
+		status.State, status.Error = JobFailed, err.Error()
+	} else {
+		status.State, status.Text = JobDone, text
+	}
+	// record the outcome even if ctx was canceled during the conversion
+	ctx = context.WithoutCancel(ctx)
+	_ = p.saveStatus(ctx, status)
+	if p.OnFinish != nil {
+		p.OnFinish(ctx, status)
+	}
+}
+
+// jobOptions returns the options of the profile of job
+func (p *Pool) jobOptions(job Job) (*Options, error) {
+	if job.Profile == "" {
+		return p.Options, nil
+	}
+	opts, ok := p.Profiles[job.Profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", job.Profile)
+	}
+	return opts, nil
+}
+
+// createStatus stores status under the key of its job, or returns
+// ErrJobExists if the key exists. Without a CreateStorage, the check and the
+// write are made atomic for the submissions of this pool only.
+func (p *Pool) createStatus(ctx context.Context, status *JobStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode status of job %s: %w", status.Job.ID, err)
+	}
+	key := jobKey(status.Job.ID)
+	if s, ok := p.Storage.(CreateStorage); ok {
+		err = s.Create(ctx, key, data)
+	} else {
+		p.creating.Lock()
+		defer p.creating.Unlock()
+		if _, err = p.Storage.Get(ctx, key); err == nil {
+			err = ErrExists
+		} else if errors.Is(err, ErrNotFound) {
+			err = p.Storage.Put(ctx, key, data)
+		}
+	}
+	if errors.Is(err, ErrExists) {
+		return fmt.Errorf("%w: %s", ErrJobExists, status.Job.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save status of job %s: %w", status.Job.ID, err)
+	}
+	return nil
+}
+
+// saveStatus stores status under the key of its job
+func (p *Pool) saveStatus(ctx context.Context, status *JobStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode status of job %s: %w", status.Job.ID, err)
+	}
+	if err := p.Storage.Put(ctx, jobKey(status.Job.ID), data); err != nil {
+		return fmt.Errorf("failed to save status of job %s: %w", status.Job.ID, err)
+	}
+	return nil
+}
+
+// jobKey returns the storage key of the status of a job
+func jobKey(id string) string {
+	return "jobs/" + id + ".json"
+}
+
+// newJobID returns a random job ID
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,394 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryQueue(t *testing.T) {
+	q := &MemoryQueue{}
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := q.Push(ctx, Job{ID: id}); err != nil {
+			t.Fatalf("failed to push: %v", err)
+		}
+	}
+	var ids []string
+	for range 3 {
+		job, err := q.Pop(ctx)
+		if err != nil {
+			t.Fatalf("failed to pop: %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+	if strings.Join(ids, "") != "abc" {
+		t.Errorf("expected the jobs in order, got %v", ids)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Pop(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected Pop of an empty queue to wait for the context, got %v", err)
+	}
+
+	t.Run("Priority", func(t *testing.T) {
+		q := &MemoryQueue{InteractiveShare: 2}
+		for _, id := range []string{"b1", "b2", "b3"} {
+			q.Push(ctx, Job{ID: id, Class: ClassBatch})
+		}
+		for _, id := range []string{"i1", "i2", "i3", "i4", "i5"} {
+			q.Push(ctx, Job{ID: id, Class: ClassInteractive})
+		}
+		var ids []string
+		for range 8 {
+			job, err := q.Pop(ctx)
+			if err != nil {
+				t.Fatalf("failed to pop: %v", err)
+			}
+			ids = append(ids, job.ID)
+		}
+		expected := "i1 i2 b1 i3 i4 b2 i5 b3"
+		if strings.Join(ids, " ") != expected {
+			t.Errorf("expected %s, got %v", expected, ids)
+		}
+	})
+}
+
+func TestPool(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		input := args[len(args)-2]
+		if input == "broken.pdf" {
+			io.WriteString(stderr, "Syntax Error: Couldn't read xref table")
+			return &ExitError{Code: 1}
+		}
+		text := "text of " + input
+		if args[0] == "-layout" {
+			text = "layout of " + input
+		}
+		_, err := io.WriteString(stdout, text)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	finished := make(chan *JobStatus, 4)
+	pool := &Pool{
+		Converter: converter,
+		Workers:   2,
+		Profiles:  map[string]*Options{"layout": {Layout: true}},
+		OnFinish: func(ctx context.Context, status *JobStatus) {
+			finished <- status
+		},
+	}
+	ctx := context.Background()
+
+	jobs := []Job{
+		{InputPath: "report.pdf"},
+		{ID: "custom-id", InputPath: "table.pdf", Profile: "layout"},
+		{InputPath: "broken.pdf"},
+		{InputPath: "report.pdf", Profile: "unknown"},
+	}
+	var ids []string
+	for _, job := range jobs {
+		id, err := pool.Submit(ctx, job)
+		if err != nil {
+			t.Fatalf("failed to submit: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if ids[1] != "custom-id" || ids[0] == "" || ids[0] == ids[2] {
+		t.Errorf("unexpected job IDs %v", ids)
+	}
+	if _, err := pool.Submit(ctx, Job{ID: "custom-id", InputPath: "report.pdf"}); !errors.Is(err, ErrJobExists) {
+		t.Errorf("expected ErrJobExists for a duplicate ID, got %v", err)
+	}
+	if status, err := pool.Status(ctx, "custom-id"); err != nil || status.Job.InputPath != "table.pdf" {
+		t.Errorf("expected the status of the first job to be kept, got %+v, %v", status, err)
+	}
+	if _, err := pool.Result(ctx, ids[0]); !errors.Is(err, ErrJobPending) {
+		t.Errorf("expected ErrJobPending before Run, got %v", err)
+	}
+	if _, err := pool.Status(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- pool.Run(runCtx) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for _, id := range ids {
+		for {
+			status, err := pool.Status(ctx, id)
+			if err != nil {
+				t.Fatalf("failed to get status: %v", err)
+			}
+			if status.State == JobDone || status.State == JobFailed {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("job %s did not finish", id)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Run to return the error of the context, got %v", err)
+	}
+	if len(finished) != len(jobs) {
+		t.Errorf("expected OnFinish to be called for %d jobs, got %d", len(jobs), len(finished))
+	}
+
+	tests := []struct {
+		id       string
+		text     string
+		expected error
+		message  string
+	}{
+		{id: ids[0], text: "text of report.pdf"},
+		{id: ids[1], text: "layout of table.pdf"},
+		{id: ids[2], expected: ErrJobFailed, message: "xref"},
+		{id: ids[3], expected: ErrJobFailed, message: `unknown profile "unknown"`},
+	}
+	for _, tt := range tests {
+		text, err := pool.Result(ctx, tt.id)
+		if !errors.Is(err, tt.expected) || text != tt.text {
+			t.Errorf("expected %q and %v for job %s, got %q and %v", tt.text, tt.expected, tt.id, text, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("expected the error of job %s to contain %q, got %v", tt.id, tt.message, err)
+		}
+	}
+
+	status, err := pool.Status(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("failed to get status: %v", err)
+	}
+	if status.SubmittedAt.IsZero() || status.StartedAt.Before(status.SubmittedAt) || status.FinishedAt.Before(status.StartedAt) {
+		t.Errorf("unexpected times %+v", status)
+	}
+}
+
+// plainStorage hides the Create method of a Storage, and slows Get down to
+// widen the window between checking for a key and writing it
+type plainStorage struct{ Storage }
+
+func (s plainStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.Storage.Get(ctx, key)
+	time.Sleep(time.Millisecond)
+	return data, err
+}
+
+func TestPool_SubmitDuplicateID(t *testing.T) {
+	converter, err := New(WithRunner(fakeRun("text", "", 0)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	tests := []struct {
+		name    string
+		storage Storage
+	}{
+		{"CreateStorage", &MemoryStorage{}},
+		{"Storage", plainStorage{&MemoryStorage{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &Pool{Converter: converter, Storage: tt.storage}
+			ctx := context.Background()
+			const submitters = 16
+			var wg sync.WaitGroup
+			var succeeded, duplicates atomic.Int32
+			for i := range submitters {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := pool.Submit(ctx, Job{ID: "upload-1", InputPath: fmt.Sprintf("%d.pdf", i)})
+					switch {
+					case err == nil:
+						succeeded.Add(1)
+					case errors.Is(err, ErrJobExists):
+						duplicates.Add(1)
+					default:
+						t.Errorf("unexpected error: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+			if succeeded.Load() != 1 || duplicates.Load() != submitters-1 {
+				t.Errorf("expected exactly one success, got %d successes and %d duplicates", succeeded.Load(), duplicates.Load())
+			}
+		})
+	}
+}
+
+func TestPool_Shutdown(t *testing.T) {
+	release := make(chan struct{})
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		input := args[len(args)-2]
+		switch input {
+		case "slow.pdf":
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case "stuck.pdf":
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		_, err := io.WriteString(stdout, "text of "+input)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	// waitRunning waits until the job with the given ID is running
+	waitRunning := func(t *testing.T, pool *Pool, id string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			status, err := pool.Status(ctx, id)
+			if err == nil && status.State == JobRunning {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("job %s did not start", id)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("Drain", func(t *testing.T) {
+		pool := &Pool{Converter: converter, Workers: 1}
+		done := make(chan error)
+		go func() { done <- pool.Run(ctx) }()
+		id, err := pool.Submit(ctx, Job{InputPath: "slow.pdf"})
+		if err != nil {
+			t.Fatalf("failed to submit: %v", err)
+		}
+		waitRunning(t, pool, id)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+		}()
+		abandoned, err := pool.Shutdown(ctx)
+		if err != nil || len(abandoned) != 0 {
+			t.Errorf("expected the job to drain, got %v, %v", abandoned, err)
+		}
+		if err := <-done; err != nil {
+			t.Errorf("expected Run to return nil after Shutdown, got %v", err)
+		}
+		if text, err := pool.Result(ctx, id); err != nil || text != "text of slow.pdf" {
+			t.Errorf("expected the drained job to be done, got %q, %v", text, err)
+		}
+		if _, err := pool.Submit(ctx, Job{InputPath: "report.pdf"}); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("expected ErrPoolClosed from Submit, got %v", err)
+		}
+		if err := pool.Run(ctx); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("expected ErrPoolClosed from Run, got %v", err)
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		pool := &Pool{Converter: converter, Workers: 1}
+		done := make(chan error)
+		go func() { done <- pool.Run(ctx) }()
+		id, err := pool.Submit(ctx, Job{ID: "stuck", InputPath: "stuck.pdf"})
+		if err != nil {
+			t.Fatalf("failed to submit: %v", err)
+		}
+		if _, err := pool.Submit(ctx, Job{ID: "queued", InputPath: "report.pdf"}); err != nil {
+			t.Fatalf("failed to submit: %v", err)
+		}
+		waitRunning(t, pool, id)
+
+		timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		abandoned, err := pool.Shutdown(timeout)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the error of the context, got %v", err)
+		}
+		if len(abandoned) != 1 || abandoned[0].ID != "stuck" {
+			t.Errorf("expected the stuck job to be abandoned, got %v", abandoned)
+		}
+		<-done
+		if _, err := pool.Result(ctx, id); !errors.Is(err, ErrJobFailed) {
+			t.Errorf("expected the abandoned job to fail, got %v", err)
+		}
+		if status, err := pool.Status(ctx, "queued"); err != nil || status.State != JobQueued {
+			t.Errorf("expected the queued job to stay queued, got %v, %v", status, err)
+		}
+	})
+}
+
+func TestPool_Recover(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "text of "+args[len(args)-2])
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	storage := &MemoryStorage{}
+
+	// the statuses left behind by a process that crashed
+	crashed := &Pool{Converter: converter, Storage: storage}
+	for _, status := range []*JobStatus{
+		{Job: Job{ID: "queued", InputPath: "a.pdf"}, State: JobQueued},
+		{Job: Job{ID: "running", InputPath: "b.pdf", Class: ClassInteractive}, State: JobRunning, StartedAt: time.Now()},
+		{Job: Job{ID: "done", InputPath: "c.pdf"}, State: JobDone, Text: "text of c.pdf"},
+		{Job: Job{ID: "failed", InputPath: "d.pdf"}, State: JobFailed, Error: "broken"},
+	} {
+		if err := crashed.saveStatus(ctx, status); err != nil {
+			t.Fatalf("failed to save status: %v", err)
+		}
+	}
+	storage.Put(ctx, "jobs/notes.txt", []byte("not a status"))
+
+	pool := &Pool{Converter: converter, Storage: storage, Workers: 1}
+	recovered, err := pool.Recover(ctx)
+	if err != nil {
+		t.Fatalf("failed to recover: %v", err)
+	}
+	var ids []string
+	for _, job := range recovered {
+		ids = append(ids, job.ID)
+	}
+	if strings.Join(ids, ",") != "queued,running" {
+		t.Errorf("expected the queued and running jobs to be recovered, got %v", ids)
+	}
+	if status, err := pool.Status(ctx, "running"); err != nil || status.State != JobQueued || status.Job.Class != ClassInteractive {
+		t.Errorf("expected the running job to be queued again, got %+v, %v", status, err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go pool.Run(runCtx)
+	deadline := time.Now().Add(5 * time.Second)
+	for _, id := range ids {
+		for {
+			text, err := pool.Result(ctx, id)
+			if err == nil {
+				if text != "text of "+map[string]string{"queued": "a.pdf", "running": "b.pdf"}[id] {
+					t.Errorf("unexpected text of job %s: %q", id, text)
+				}
+				break
+			}
+			if !errors.Is(err, ErrJobPending) || time.Now().After(deadline) {
+				t.Fatalf("job %s did not finish: %v", id, err)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
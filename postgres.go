@@ -0,0 +1,174 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PostgresSink writes documents and their pages into PostgreSQL tables with
+// generated tsvector columns, so full-text search can live in the primary
+// database. It works with any database/sql driver for PostgreSQL, such as
+// pgx's stdlib package or lib/pq; this package does not import one.
+//
+// The tables are created by Migrate, or by running the statements returned
+// by Schema with a migration tool:
+//
+//	documents (path, metadata, pages, indexed_at, search)
+//	pages     (path, page, label, text, search)
+//
+// The search columns are indexed with GIN and can be queried with the
+// standard text search operators:
+//
+//	SELECT path, page FROM pdf_pages
+//	WHERE search @@ websearch_to_tsquery('english', 'quarterly revenue')
+type PostgresSink struct {
+	// DB is the database the pages are written to
+	DB *sql.DB
+	// DocumentsTable is the name of the documents table (default "pdf_documents")
+	DocumentsTable string
+	// PagesTable is the name of the pages table (default "pdf_pages")
+	PagesTable string
+	// Language is the text search configuration used to build the search
+	// columns (default "english")
+	Language string
+}
+
+// identifierPattern matches the table and configuration names accepted by
+// PostgresSink, optionally qualified with a schema
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Schema returns the statements creating the tables and their suggested
+// indexes. The statements are idempotent.
+func (s *PostgresSink) Schema() ([]string, error) {
+	documents, pages, language, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+	index := func(table, suffix string) string {
+		return strings.ReplaceAll(table, ".", "_") + "_" + suffix
+	}
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	path text PRIMARY KEY,
+	metadata jsonb NOT NULL DEFAULT '{}',
+	pages integer NOT NULL,
+	indexed_at timestamptz NOT NULL DEFAULT now(),
+	search tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('%[2]s', coalesce(metadata->>'title', '')), 'A') ||
+		setweight(to_tsvector('%[2]s', coalesce(metadata->>'subject', '') || ' ' || coalesce(metadata->>'keywords', '')), 'B') ||
+		setweight(to_tsvector('%[2]s', coalesce(metadata->>'author', '')), 'C')
+	) STORED
+)`, documents, language),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	path text NOT NULL REFERENCES %[2]s (path) ON DELETE CASCADE,
+	page integer NOT NULL,
+	label text,
+	text text NOT NULL,
+	search tsvector GENERATED ALWAYS AS (to_tsvector('%[3]s', text)) STORED,
+	PRIMARY KEY (path, page)
+)`, pages, documents, language),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (search)", index(documents, "search_idx"), documents),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (search)", index(pages, "search_idx"), pages),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (indexed_at)", index(documents, "indexed_at_idx"), documents),
+	}, nil
+}
+
+// Migrate creates the tables and indexes returned by Schema
+func (s *PostgresSink) Migrate(ctx context.Context) error {
+	statements, err := s.Schema()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range statements {
+		if _, err := s.DB.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to migrate: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteDocument writes doc and its pages in one transaction, replacing the
+// pages previously written for the same path
+func (s *PostgresSink) WriteDocument(ctx context.Context, doc *Document) (err error) {
+	documents, pages, _, err := s.names()
+	if err != nil {
+		return err
+	}
+	metadata := doc.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (path, metadata, pages, indexed_at) VALUES ($1, $2, $3, now())
+ON CONFLICT (path) DO UPDATE SET metadata = EXCLUDED.metadata, pages = EXCLUDED.pages, indexed_at = EXCLUDED.indexed_at`, documents),
+		doc.Path, string(data), len(doc.Pages))
+	if err != nil {
+		return fmt.Errorf("failed to write document %s: %w", doc.Path, err)
+	}
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE path = $1", pages), doc.Path); err != nil {
+		return fmt.Errorf("failed to write document %s: %w", doc.Path, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (path, page, label, text) VALUES ($1, $2, $3, $4)", pages))
+	if err != nil {
+		return fmt.Errorf("failed to write document %s: %w", doc.Path, err)
+	}
+	defer stmt.Close()
+	for _, page := range doc.Pages {
+		var label sql.NullString
+		if page.Label != "" {
+			label = sql.NullString{String: page.Label, Valid: true}
+		}
+		// PostgreSQL text cannot hold NUL bytes
+		text := strings.ReplaceAll(page.Text, "\x00", "")
+		if _, err = stmt.ExecContext(ctx, doc.Path, page.Number, label, text); err != nil {
+			return fmt.Errorf("failed to write page %d of %s: %w", page.Number, doc.Path, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteDocument removes the document and its pages
+func (s *PostgresSink) DeleteDocument(ctx context.Context, path string) error {
+	documents, _, _, err := s.names()
+	if err != nil {
+		return err
+	}
+	if _, err := s.DB.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE path = $1", documents), path); err != nil {
+		return fmt.Errorf("failed to delete document %s: %w", path, err)
+	}
+	return nil
+}
+
+// names returns the validated table and configuration names. They are
+// interpolated into the statements, so only plain identifiers are accepted.
+func (s *PostgresSink) names() (documents, pages, language string, err error) {
+	documents = cmp.Or(s.DocumentsTable, "pdf_documents")
+	pages = cmp.Or(s.PagesTable, "pdf_pages")
+	language = cmp.Or(s.Language, "english")
+	for _, name := range []string{documents, pages, language} {
+		if !identifierPattern.MatchString(name) {
+			return "", "", "", fmt.Errorf("invalid identifier %q", name)
+		}
+	}
+	return documents, pages, language, nil
+}
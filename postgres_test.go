@@ -0,0 +1,188 @@
+package pdftotext
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingDriver is a database/sql driver recording the executed
+// statements and their arguments
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+	commits    int
+	rollbacks  int
+	failOn     string
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d}, nil }
+
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) {
+	return &recordingConn{d}, nil
+}
+
+func (d *recordingDriver) Driver() driver.Driver { return d }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{c.d, query}, nil
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return &recordingTx{c.d}, nil }
+
+type recordingTx struct{ d *recordingDriver }
+
+func (tx *recordingTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.commits++
+	return nil
+}
+
+func (tx *recordingTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	statement := strings.Fields(s.query)[0] + " " + fmt.Sprint(args)
+	if s.d.failOn != "" && strings.Contains(statement, s.d.failOn) {
+		return nil, errors.New("constraint violation")
+	}
+	s.d.statements = append(s.d.statements, s.query+" "+fmt.Sprint(args))
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+// openRecordingDB opens a database backed by a new recordingDriver
+func openRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	db := sql.OpenDB(d)
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestPostgresSink_Schema(t *testing.T) {
+	tests := []struct {
+		name     string
+		sink     *PostgresSink
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "defaults",
+			sink:     &PostgresSink{},
+			expected: []string{"CREATE TABLE IF NOT EXISTS pdf_documents", "REFERENCES pdf_documents (path)", "to_tsvector('english', text)", "pdf_pages_search_idx ON pdf_pages USING GIN (search)"},
+		},
+		{
+			name:     "custom names",
+			sink:     &PostgresSink{DocumentsTable: "search.docs", PagesTable: "search.pages", Language: "simple"},
+			expected: []string{"CREATE TABLE IF NOT EXISTS search.docs", "to_tsvector('simple', text)", "search_pages_search_idx ON search.pages"},
+		},
+		{
+			name: "invalid table",
+			sink: &PostgresSink{PagesTable: "pages; DROP TABLE users"},
+			err:  true,
+		},
+		{
+			name: "invalid language",
+			sink: &PostgresSink{Language: "english')"},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := tt.sink.Schema()
+			if tt.err {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			schema := strings.Join(statements, ";\n")
+			for _, s := range tt.expected {
+				if !strings.Contains(schema, s) {
+					t.Errorf("expected schema to contain %q, got:\n%s", s, schema)
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresSink_WriteDocument(t *testing.T) {
+	db, d := openRecordingDB(t)
+	sink := &PostgresSink{DB: db}
+	ctx := context.Background()
+
+	if err := sink.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.statements) != 5 {
+		t.Fatalf("expected 5 migration statements, got %d", len(d.statements))
+	}
+
+	d.statements = nil
+	doc := &Document{
+		Path:     "report.pdf",
+		Metadata: map[string]string{"title": "Report"},
+		Pages:    []Page{{Number: 1, Label: "i", Text: "one\x00"}, {Number: 2, Text: "two"}},
+	}
+	if err := sink.WriteDocument(ctx, doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		`INSERT INTO pdf_documents`,
+		`DELETE FROM pdf_pages WHERE path = $1 [report.pdf]`,
+		`INSERT INTO pdf_pages (path, page, label, text) VALUES ($1, $2, $3, $4) [report.pdf 1 i one]`,
+		`INSERT INTO pdf_pages (path, page, label, text) VALUES ($1, $2, $3, $4) [report.pdf 2 <nil> two]`,
+	}
+	if len(d.statements) != len(expected) {
+		t.Fatalf("expected %d statements, got %q", len(expected), d.statements)
+	}
+	for i, s := range expected {
+		if !strings.HasPrefix(d.statements[i], s) {
+			t.Errorf("expected statement %d to start with %q, got %q", i, s, d.statements[i])
+		}
+	}
+	if !strings.HasSuffix(d.statements[0], `[report.pdf {"title":"Report"} 2]`) {
+		t.Errorf("unexpected document arguments: %q", d.statements[0])
+	}
+	if d.commits != 1 || d.rollbacks != 0 {
+		t.Errorf("expected 1 commit and no rollback, got %d and %d", d.commits, d.rollbacks)
+	}
+
+	d.failOn = "INSERT [report.pdf 2"
+	if err := sink.WriteDocument(ctx, doc); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if d.commits != 1 || d.rollbacks != 1 {
+		t.Errorf("expected the failed write to roll back, got %d commits and %d rollbacks", d.commits, d.rollbacks)
+	}
+}
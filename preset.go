@@ -0,0 +1,53 @@
+package pdftotext
+
+// PresetLayout returns Options keeping the physical layout of the pages, with
+// columns and tables aligned by spaces, in UTF-8 with Unix line endings
+func PresetLayout() *Options {
+	return &Options{
+		Layout:   true,
+		Encoding: "UTF-8",
+		EOL:      EOLUnix,
+	}
+}
+
+// PresetRawFast returns Options for the fastest conversion, keeping the text
+// in content stream order without analyzing the layout and discarding
+// pdftotext's messages
+func PresetRawFast() *Options {
+	return &Options{
+		Raw:      true,
+		Encoding: "UTF-8",
+		Quiet:    true,
+	}
+}
+
+// PresetLLM returns Options producing clean prose for language models and
+// embeddings: UTF-8 in reading order without page breaks, NFKC-normalized,
+// with broken control characters removed, words hyphenated at line breaks
+// rejoined and whitespace collapsed
+func PresetLLM() *Options {
+	return &Options{
+		Encoding:     "UTF-8",
+		EOL:          EOLUnix,
+		NoPageBreaks: true,
+		Normalize:    NFKC,
+		Transformers: []Transformer{
+			ControlStripper{},
+			Dehyphenator{},
+			WhitespaceNormalizer{CollapseSpaces: true, TrimTrailing: true, MaxBlankLines: 1, TrimPage: true},
+		},
+	}
+}
+
+// PresetArchival returns Options for long-term storage, keeping as much of
+// the original as possible: the physical layout, page breaks, and the
+// characters themselves, only canonically composed with NFC, in UTF-8 with
+// Unix line endings
+func PresetArchival() *Options {
+	return &Options{
+		Layout:    true,
+		Encoding:  "UTF-8",
+		EOL:       EOLUnix,
+		Normalize: NFC,
+	}
+}
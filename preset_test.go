@@ -0,0 +1,63 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *Options
+		expected []string
+	}{
+		{name: "Layout", options: PresetLayout(), expected: []string{"-layout", "-enc", "UTF-8", "-eol", "unix"}},
+		{name: "RawFast", options: PresetRawFast(), expected: []string{"-raw", "-enc", "UTF-8", "-q"}},
+		{name: "LLM", options: PresetLLM(), expected: []string{"-enc", "UTF-8", "-eol", "unix", "-nopgbrk"}},
+		{name: "Archival", options: PresetArchival(), expected: []string{"-layout", "-enc", "UTF-8", "-eol", "unix"}},
+	}
+
+	converter, err := New()
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := converter.buildArgs(tt.options, "input.pdf", "-")
+			for _, arg := range tt.expected {
+				if !slices.Contains(args, arg) {
+					t.Errorf("expected %q in args %v", arg, args)
+				}
+			}
+		})
+	}
+
+	t.Run("Independent", func(t *testing.T) {
+		opts := PresetLLM()
+		opts.Transformers = opts.Transformers[:0]
+		if len(PresetLLM().Transformers) == 0 {
+			t.Error("expected each call to return new options")
+		}
+	})
+}
+
+func TestPresetLLM_Convert(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "The quick ﬁsh   swims\nac-\nross the sea.  \n\n\n\nThe end\x00.\n")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	text, err := converter.Convert(context.Background(), "input.pdf", PresetLLM())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "The quick fish swims\nacross\nthe sea.\n\nThe end."; strings.TrimSpace(text) != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+}
@@ -0,0 +1,58 @@
+package pdftotext
+
+import (
+	"context"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// PreviewResult is the first page of a document sampled by Preview
+type PreviewResult struct {
+	// Path is the path of the PDF file
+	Path string `json:"path"`
+	// Text is the text of the first page
+	Text string `json:"text"`
+	// Err is the error converting the document, if any
+	Err error `json:"-"`
+}
+
+// Preview converts only the first page of a random sample of n of the input
+// files, giving a fast sense of the content and extraction quality of a
+// large corpus before committing to a full run. Up to GOMAXPROCS documents
+// are converted concurrently. Failed conversions are reported in the
+// results, which are in the order of inputs; only a canceled context fails
+// the whole preview.
+func (c *Converter) Preview(ctx context.Context, inputs []string, n int) ([]PreviewResult, error) {
+	n = max(min(n, len(inputs)), 0)
+	sample := rand.Perm(len(inputs))[:n]
+	slices.Sort(sample)
+
+	results := make([]PreviewResult, n)
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, index := range sample {
+		results[i].Path = inputs[index]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+
+			text, err := c.Convert(ctx, inputs[index], &Options{FirstPage: 1, LastPage: 1})
+			results[i].Text, results[i].Err = text, err
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,79 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestConverter_Preview(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if flagValue(args, "-f") != "1" || flagValue(args, "-l") != "1" {
+			return fmt.Errorf("expected only the first page, got %v", args)
+		}
+		input := args[len(args)-2]
+		if input == "doc3.pdf" {
+			io.WriteString(stderr, "Syntax Error: broken")
+			return &ExitError{Code: 1}
+		}
+		_, err := fmt.Fprintf(stdout, "first page of %s\n\f", input)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	var inputs []string
+	for i := 0; i < 20; i++ {
+		inputs = append(inputs, fmt.Sprintf("doc%d.pdf", i))
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		expected int
+	}{
+		{name: "sample", n: 5, expected: 5},
+		{name: "more than inputs", n: 50, expected: 20},
+		{name: "none", n: 0, expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := converter.Preview(context.Background(), inputs, tt.n)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != tt.expected {
+				t.Fatalf("expected %d results, got %d", tt.expected, len(results))
+			}
+
+			last := -1
+			for _, r := range results {
+				index := slices.Index(inputs, r.Path)
+				if index <= last {
+					t.Errorf("expected distinct results in input order, got %s after %s", r.Path, inputs[max(last, 0)])
+				}
+				last = index
+
+				if r.Path == "doc3.pdf" {
+					if !errors.Is(r.Err, ErrPDFOpen) {
+						t.Errorf("expected error %v, got %v", ErrPDFOpen, r.Err)
+					}
+					continue
+				}
+				if r.Err != nil || r.Text != "first page of "+r.Path {
+					t.Errorf("unexpected result %+v", r)
+				}
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := converter.Preview(ctx, inputs, 5); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+}
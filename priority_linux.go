@@ -0,0 +1,33 @@
+package pdftotext
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// I/O scheduling classes and the target of ioprio_set, see ioprio_set(2)
+const (
+	ioprioClassBestEffort = 2
+	ioprioClassIdle       = 3
+	ioprioClassShift      = 13
+	ioprioWhoProcess      = 1
+)
+
+// prepare configures cmd before it is started
+func (p Priority) prepare(cmd *exec.Cmd) {}
+
+// apply lowers the CPU and I/O priority of the started process pid. Failures
+// are ignored, since the conversion works at any priority.
+func (p Priority) apply(pid int) {
+	var nice, ioprio int
+	switch p {
+	case PriorityLow:
+		nice, ioprio = 10, ioprioClassBestEffort<<ioprioClassShift|7
+	case PriorityIdle:
+		nice, ioprio = 19, ioprioClassIdle<<ioprioClassShift
+	default:
+		return
+	}
+	syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+	syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(pid), uintptr(ioprio))
+}
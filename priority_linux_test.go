@@ -0,0 +1,32 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExecRunner_Priority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority Priority
+		expected string
+	}{
+		{name: "Low", priority: PriorityLow, expected: "10"},
+		{name: "Idle", priority: PriorityIdle, expected: "19"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			// the priority is set just after the process started
+			err := ExecRunner{Priority: tt.priority}.Run(context.Background(), "sh", []string{"-c", "sleep 0.2; nice"}, &stdout, nil)
+			if err != nil {
+				t.Skipf("failed to run nice: %v", err)
+			}
+			if got := strings.TrimSpace(stdout.String()); got != tt.expected {
+				t.Errorf("expected niceness %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
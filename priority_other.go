@@ -0,0 +1,22 @@
+//go:build unix && !linux
+
+package pdftotext
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// prepare configures cmd before it is started
+func (p Priority) prepare(cmd *exec.Cmd) {}
+
+// apply lowers the CPU priority of the started process pid. Failures are
+// ignored, since the conversion works at any priority.
+func (p Priority) apply(pid int) {
+	switch p {
+	case PriorityLow:
+		syscall.Setpriority(syscall.PRIO_PROCESS, pid, 10)
+	case PriorityIdle:
+		syscall.Setpriority(syscall.PRIO_PROCESS, pid, 19)
+	}
+}
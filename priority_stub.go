@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package pdftotext
+
+import "os/exec"
+
+// prepare configures cmd before it is started
+func (p Priority) prepare(cmd *exec.Cmd) {}
+
+// apply does nothing, since the platform has no process priorities
+func (p Priority) apply(pid int) {}
@@ -0,0 +1,29 @@
+package pdftotext
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Process priority classes, see SetPriorityClass
+const (
+	belowNormalPriorityClass = 0x00004000
+	idlePriorityClass        = 0x00000040
+)
+
+// prepare creates the process of cmd in the priority class of p
+func (p Priority) prepare(cmd *exec.Cmd) {
+	var class uint32
+	switch p {
+	case PriorityLow:
+		class = belowNormalPriorityClass
+	case PriorityIdle:
+		class = idlePriorityClass
+	default:
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: class}
+}
+
+// apply does nothing, the priority class is set when the process is created
+func (p Priority) apply(pid int) {}
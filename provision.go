@@ -0,0 +1,119 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// BinaryRelease is a pdftotext executable EnsureBinary can download
+type BinaryRelease struct {
+	// URL is the location of the executable itself, not of an archive
+	URL string
+	// SHA256 is the hex encoded SHA-256 hash of the executable
+	SHA256 string
+}
+
+// EnsureBinary returns the path of a pdftotext binary for WithBinaryPath,
+// downloading the release pinned in releases for the current platform into
+// dir when none is installed in PATH or in dir, so bare containers need no
+// poppler package. The releases are keyed by "GOOS/GOARCH", e.g.
+// "linux/amd64". Poppler does not publish static builds, so deployments pin
+// the builds they host. The download is verified against the pinned checksum
+// before it is made executable, and so is a binary already in dir, which is
+// downloaded again if it does not match, e.g. after an interrupted download
+// or a change of the pinned release. Failures are reported with
+// ErrBinaryNotFound.
+func EnsureBinary(ctx context.Context, dir string, releases map[string]BinaryRelease) (string, error) {
+	name := "pdftotext"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if path, err := exec.LookPath("pdftotext"); err == nil {
+		return path, nil
+	}
+
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	release, ok := releases[platform]
+	if !ok {
+		return "", fmt.Errorf("%w: no release pinned for %s", ErrBinaryNotFound, platform)
+	}
+	want, err := hex.DecodeString(release.SHA256)
+	if err != nil || len(want) != sha256.Size {
+		return "", fmt.Errorf("%w: invalid SHA-256 checksum %q pinned for %s", ErrBinaryNotFound, release.SHA256, platform)
+	}
+	target := filepath.Join(dir, name)
+	if path, err := exec.LookPath(target); err == nil {
+		if sum, err := fileSHA256(path); err == nil && bytes.Equal(sum, want) {
+			return path, nil
+		}
+	}
+	if err := download(ctx, release.URL, want, target); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrBinaryNotFound, err)
+	}
+	return target, nil
+}
+
+// fileSHA256 returns the SHA-256 hash of the file at path
+func fileSHA256(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// download downloads the executable at url to target, replacing it
+// atomically only if its SHA-256 hash is want
+func download(ctx context.Context, url string, want []byte, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(target), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", url, err)
+	}
+	if sum := h.Sum(nil); !bytes.Equal(sum, want) {
+		return fmt.Errorf("checksum of %s is %x, expected %x", url, sum, want)
+	}
+	if err := os.Chmod(f.Name(), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), target)
+}
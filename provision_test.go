@@ -0,0 +1,99 @@
+package pdftotext
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestEnsureBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test binary is a shell script")
+	}
+	// hide any installed pdftotext
+	t.Setenv("PATH", t.TempDir())
+
+	script := []byte("#!/bin/sh\necho 'pdftotext version 24.02.0' >&2\n")
+	sum := sha256.Sum256(script)
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		w.Write(script)
+	}))
+	defer server.Close()
+
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	ctx := context.Background()
+
+	t.Run("not pinned", func(t *testing.T) {
+		if _, err := EnsureBinary(ctx, t.TempDir(), nil); !errors.Is(err, ErrBinaryNotFound) {
+			t.Errorf("expected ErrBinaryNotFound, got %v", err)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		dir := t.TempDir()
+		releases := map[string]BinaryRelease{platform: {URL: server.URL, SHA256: strings.Repeat("00", sha256.Size)}}
+		if _, err := EnsureBinary(ctx, dir, releases); !errors.Is(err, ErrBinaryNotFound) {
+			t.Errorf("expected ErrBinaryNotFound, got %v", err)
+		}
+		releases[platform] = BinaryRelease{URL: server.URL, SHA256: "00"}
+		if _, err := EnsureBinary(ctx, dir, releases); !errors.Is(err, ErrBinaryNotFound) {
+			t.Errorf("expected ErrBinaryNotFound for an invalid checksum, got %v", err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("expected no files to be left, got %d", len(entries))
+		}
+	})
+
+	t.Run("download", func(t *testing.T) {
+		dir := t.TempDir()
+		downloads = 0
+		releases := map[string]BinaryRelease{platform: {URL: server.URL, SHA256: hex.EncodeToString(sum[:])}}
+		path, err := EnsureBinary(ctx, dir, releases)
+		if err != nil {
+			t.Fatalf("failed to ensure binary: %v", err)
+		}
+		if path != filepath.Join(dir, "pdftotext") {
+			t.Errorf("expected the binary in %s, got %s", dir, path)
+		}
+		converter, err := New(WithBinaryPath(path))
+		if err != nil {
+			t.Fatalf("failed to create converter: %v", err)
+		}
+		if version, err := converter.Version(ctx); err != nil || version != "24.02.0" {
+			t.Errorf("expected version 24.02.0, got %q, %v", version, err)
+		}
+
+		// the downloaded binary is reused
+		if _, err := EnsureBinary(ctx, dir, releases); err != nil || downloads != 1 {
+			t.Errorf("expected 1 download, got %d, %v", downloads, err)
+		}
+
+		// a binary that no longer matches the checksum is replaced
+		if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := EnsureBinary(ctx, dir, releases); err != nil || downloads != 2 {
+			t.Errorf("expected the tampered binary to be downloaded again, got %d downloads, %v", downloads, err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != string(script) {
+			t.Errorf("expected the pinned binary, got %q", data)
+		}
+	})
+
+	t.Run("uppercase checksum", func(t *testing.T) {
+		releases := map[string]BinaryRelease{platform: {URL: server.URL, SHA256: strings.ToUpper(hex.EncodeToString(sum[:]))}}
+		if _, err := EnsureBinary(ctx, t.TempDir(), releases); err != nil {
+			t.Errorf("expected an uppercase checksum to match, got %v", err)
+		}
+	})
+}
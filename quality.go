@@ -0,0 +1,215 @@
+package pdftotext
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QualityScore rates how well the text layer of a page was extracted, to flag
+// documents whose text is broken and should be re-OCRed
+type QualityScore struct {
+	// Score combines the other measures into a score from 0 for unusable
+	// text to 1 for clean text. It is 0 for text without words.
+	Score float64 `json:"score"`
+	// Words is the number of whitespace-separated words
+	Words int `json:"words"`
+	// WordRatio is the fraction of the words that look like dictionary words
+	WordRatio float64 `json:"word_ratio"`
+	// ReplacementRatio is the fraction of the characters that are U+FFFD
+	// replacement characters, control characters, private use characters or
+	// invalid UTF-8, as emitted for fonts without a usable encoding
+	ReplacementRatio float64 `json:"replacement_ratio"`
+	// MeanWordLength is the mean number of characters per word. Very short
+	// words indicate letters spaced apart, very long ones missing spaces.
+	MeanWordLength float64 `json:"mean_word_length"`
+	// GibberishRatio is the fraction of the words mixing letters and symbols
+	// or repeating a character, as produced by broken font encodings
+	GibberishRatio float64 `json:"gibberish_ratio"`
+}
+
+// QualityScorer computes QualityScores
+type QualityScorer struct {
+	// IsWord optionally reports whether a word, in lower case and without
+	// surrounding punctuation, is in a dictionary. Without it, words of
+	// letters that look pronounceable count as dictionary words.
+	IsWord func(word string) bool
+}
+
+// ScoreQuality scores text with a QualityScorer without a dictionary
+func ScoreQuality(text string) QualityScore {
+	return QualityScorer{}.Score(text)
+}
+
+const (
+	// minMeanWordLength and maxMeanWordLength bound the mean word length of
+	// normal text in languages separating words with spaces
+	minMeanWordLength = 3
+	maxMeanWordLength = 10
+	// replacementPenalty scales the ReplacementRatio, so text with a tenth
+	// of its characters replaced scores 0
+	replacementPenalty = 10
+)
+
+// Score computes the QualityScore of text
+func (s QualityScorer) Score(text string) QualityScore {
+	var q QualityScore
+	chars, replaced, unspaced := 0, 0, 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if unicode.IsSpace(r) {
+			continue
+		}
+		chars++
+		if r == utf8.RuneError || unicode.IsControl(r) || unicode.Is(unicode.Co, r) {
+			replaced++
+		}
+		if isUnspacedScript(r) {
+			unspaced++
+		}
+	}
+
+	words, gibberish := 0, 0
+	letters := 0
+	for _, field := range strings.Fields(text) {
+		q.Words++
+		letters += utf8.RuneCountInString(field)
+		word := strings.TrimFunc(field, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) })
+		switch {
+		case isGibberish(field):
+			gibberish++
+		case word == "":
+		case s.IsWord != nil:
+			if s.IsWord(strings.ToLower(word)) {
+				words++
+			}
+		case looksLikeWord(word):
+			words++
+		}
+	}
+	if q.Words == 0 {
+		return q
+	}
+
+	n := float64(q.Words)
+	q.WordRatio = float64(words) / n
+	q.GibberishRatio = float64(gibberish) / n
+	q.ReplacementRatio = float64(replaced) / float64(chars)
+	q.MeanWordLength = float64(letters) / n
+
+	// scripts written without spaces have long "words" by nature
+	lengthFactor := 1.0
+	if 2*unspaced < chars {
+		switch {
+		case q.MeanWordLength < minMeanWordLength:
+			lengthFactor = q.MeanWordLength / minMeanWordLength
+		case q.MeanWordLength > maxMeanWordLength:
+			lengthFactor = maxMeanWordLength / q.MeanWordLength
+		}
+	} else {
+		q.WordRatio = 1 - q.GibberishRatio
+	}
+	q.Score = q.WordRatio * (1 - q.GibberishRatio) * math.Max(0, 1-replacementPenalty*q.ReplacementRatio) * lengthFactor
+	q.Score = math.Round(q.Score*1000) / 1000
+	return q
+}
+
+// DocumentQuality is the quality of the text of a Document
+type DocumentQuality struct {
+	// Score is the mean score of the pages, weighted by their words
+	Score float64 `json:"score"`
+	// Pages are the scores of the pages in order
+	Pages []QualityScore `json:"pages"`
+}
+
+// Quality scores the text of each page of d with ScoreQuality
+func (d *Document) Quality() DocumentQuality {
+	var dq DocumentQuality
+	total, words := 0.0, 0
+	for _, page := range d.Pages {
+		q := ScoreQuality(page.Text)
+		dq.Pages = append(dq.Pages, q)
+		total += q.Score * float64(q.Words)
+		words += q.Words
+	}
+	if words > 0 {
+		dq.Score = math.Round(total/float64(words)*1000) / 1000
+	}
+	return dq
+}
+
+// looksLikeWord reports whether word consists of letters, apostrophes and
+// inner hyphens with a plausible case pattern, and, in the Latin script,
+// contains a vowel and no long run of consonants
+func looksLikeWord(word string) bool {
+	upper, lower, vowels, consonants, maxConsonants := 0, 0, 0, 0, 0
+	latin := false
+	for i, r := range []rune(word) {
+		switch {
+		case unicode.IsLetter(r) || unicode.Is(unicode.Mn, r):
+		case (r == '-' || r == '\'' || r == '’' || r == '.') && i > 0:
+			consonants = 0
+			continue
+		default:
+			return false
+		}
+		if unicode.IsUpper(r) {
+			upper++
+			// an upper-case letter after a lower-case one, as in "tHe"
+			if lower > 0 && upper == 1 && i > 0 && unicode.IsLower([]rune(word)[i-1]) {
+				return false
+			}
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+		if r < unicode.MaxLatin1 || unicode.Is(unicode.Latin, r) {
+			latin = true
+			if strings.ContainsRune("aeiouyäöüàáâãåæèéêëìíîïòóôõøùúûýÿœ", unicode.ToLower(r)) {
+				vowels++
+				consonants = 0
+			} else {
+				consonants++
+				maxConsonants = max(maxConsonants, consonants)
+			}
+		}
+	}
+	if !latin {
+		return true
+	}
+	// acronyms such as "PDF" need no vowels
+	if lower == 0 && upper <= 5 {
+		return true
+	}
+	return vowels > 0 && maxConsonants <= 5
+}
+
+// isGibberish reports whether word mixes letters with symbols, or repeats a
+// character more than three times
+func isGibberish(word string) bool {
+	runes := []rune(strings.TrimFunc(word, unicode.IsPunct))
+	letters, others, repeat := 0, 0, 1
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsSymbol(r) || r < utf8.RuneSelf && unicode.IsPunct(r) && !strings.ContainsRune("-'.,/:;!?()\"", r):
+			others++
+		}
+		if i > 0 && r == runes[i-1] && !unicode.IsDigit(r) {
+			if repeat++; repeat > 3 {
+				return true
+			}
+		} else {
+			repeat = 1
+		}
+	}
+	return letters > 0 && others > 0
+}
+
+// isUnspacedScript reports whether r belongs to a script written without
+// spaces between words
+func isUnspacedScript(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
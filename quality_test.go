@@ -0,0 +1,89 @@
+package pdftotext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScoreQuality(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		min  float64
+		max  float64
+	}{
+		{
+			name: "Clean prose",
+			text: "The quarterly report shows that revenue grew in all regions, driven by strong demand for the PDF tools.",
+			min:  0.95,
+			max:  1,
+		},
+		{
+			name: "Spaced letters",
+			text: "T h e  q u a r t e r l y  r e p o r t  s h o w s  g r o w t h",
+			min:  0,
+			max:  0.4,
+		},
+		{
+			name: "Missing spaces",
+			text: "Thequarterlyreportshowsthatrevenuegrewinallregions drivenbystrongdemand",
+			min:  0,
+			max:  0.4,
+		},
+		{
+			name: "Broken encoding",
+			text: "Tþe qu#rt@rly r%p&rt sh*ws th$t r~v=nue gr+w ���",
+			min:  0,
+			max:  0.2,
+		},
+		{
+			name: "Consonant soup",
+			text: "Xkrtz bvnmq plkjh wrtzp qwrtp zxcvb mnbvc lkjhg",
+			min:  0,
+			max:  0.2,
+		},
+		{
+			name: "Japanese",
+			text: "四半期報告書によると、すべての地域で売上が増加しました。",
+			min:  0.95,
+			max:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ScoreQuality(tt.text)
+			if q.Score < tt.min || q.Score > tt.max {
+				t.Errorf("expected score in [%g, %g], got %+v", tt.min, tt.max, q)
+			}
+		})
+	}
+
+	if q := ScoreQuality("  \n "); q.Score != 0 || q.Words != 0 {
+		t.Errorf("expected a zero score for blank text, got %+v", q)
+	}
+}
+
+func TestQualityScorer_IsWord(t *testing.T) {
+	dictionary := map[string]bool{"the": true, "report": true}
+	scorer := QualityScorer{IsWord: func(word string) bool { return dictionary[word] }}
+	q := scorer.Score("The report, the rapport.")
+	if q.WordRatio != 0.75 {
+		t.Errorf("expected word ratio 0.75, got %+v", q)
+	}
+}
+
+func TestDocument_Quality(t *testing.T) {
+	doc := &Document{Pages: []Page{
+		{Number: 1, Text: strings.Repeat("clean words here ", 3)},
+		{Number: 2, Text: ""},
+		{Number: 3, Text: "x#9@q"},
+	}}
+	dq := doc.Quality()
+	if len(dq.Pages) != 3 || dq.Pages[0].Score != 1 || dq.Pages[2].Score != 0 {
+		t.Fatalf("unexpected page scores %+v", dq.Pages)
+	}
+	if dq.Score != 0.9 {
+		t.Errorf("expected the score weighted by words to be 0.9, got %g", dq.Score)
+	}
+}
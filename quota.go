@@ -0,0 +1,214 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// QuotaUsage is an amount of conversion work, or a limit on it
+type QuotaUsage struct {
+	// Pages is the number of pages converted
+	Pages int64 `json:"pages"`
+	// Bytes is the size of the converted files
+	Bytes int64 `json:"bytes"`
+}
+
+// exceeds reports whether u exceeds limit, whose zero fields are unlimited
+func (u QuotaUsage) exceeds(limit QuotaUsage) bool {
+	return limit.Pages > 0 && u.Pages > limit.Pages || limit.Bytes > 0 && u.Bytes > limit.Bytes
+}
+
+// QuotaStore keeps the usage of quota keys per window. Implementations backed
+// by Redis or a database share the quotas between processes. They must be
+// safe for concurrent use.
+type QuotaStore interface {
+	// Charge adds usage to the usage of key in the window starting at
+	// window, unless the total would exceed limit, whose zero fields are
+	// unlimited. It returns the usage of the window and whether usage was
+	// added, atomically. Refunds charge a negative usage with no limit.
+	Charge(ctx context.Context, key string, window time.Time, usage, limit QuotaUsage) (QuotaUsage, bool, error)
+	// Usage returns the usage of key in the window starting at window
+	Usage(ctx context.Context, key string, window time.Time) (QuotaUsage, error)
+}
+
+// MemoryQuotaStore is a QuotaStore in memory, keeping only the latest window
+// of each key, so charges to earlier windows are dropped. The zero value is an
+// empty store.
+type MemoryQuotaStore struct {
+	mu    sync.Mutex
+	usage map[string]windowUsage
+}
+
+// windowUsage is the usage of a key in a window
+type windowUsage struct {
+	window time.Time
+	usage  QuotaUsage
+}
+
+// Charge adds usage to the usage of key unless it would exceed limit
+func (s *MemoryQuotaStore) Charge(_ context.Context, key string, window time.Time, usage, limit QuotaUsage) (QuotaUsage, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.usage[key]; ok && window.Before(u.window) {
+		return QuotaUsage{}, true, nil
+	}
+	current := s.current(key, window)
+	total := QuotaUsage{Pages: max(current.Pages+usage.Pages, 0), Bytes: max(current.Bytes+usage.Bytes, 0)}
+	if total.exceeds(limit) {
+		return current, false, nil
+	}
+	if s.usage == nil {
+		s.usage = make(map[string]windowUsage)
+	}
+	s.usage[key] = windowUsage{window: window, usage: total}
+	return total, true, nil
+}
+
+// Usage returns the usage of key in the window starting at window
+func (s *MemoryQuotaStore) Usage(_ context.Context, key string, window time.Time) (QuotaUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current(key, window), nil
+}
+
+// current returns the usage of key in window, with mu held
+func (s *MemoryQuotaStore) current(key string, window time.Time) QuotaUsage {
+	if u, ok := s.usage[key]; ok && u.window.Equal(window) {
+		return u.usage
+	}
+	return QuotaUsage{}
+}
+
+// QuotaCharge is a charge made by a Quota, for refunding it
+type QuotaCharge struct {
+	// Key is the key charged
+	Key string
+	// Window is the start of the window the usage was added to
+	Window time.Time
+	// Usage is the usage added
+	Usage QuotaUsage
+}
+
+// Quota limits the pages and bytes converted per key, typically a tenant,
+// per window of time, for multi-tenant deployments. A Pool charges the jobs
+// to their Tenant when they are submitted; other servers call ChargeFile
+// before converting.
+type Quota struct {
+	// Store keeps the usage (default a MemoryQuotaStore)
+	Store QuotaStore
+	// Window is the accounting period, aligned to multiples of it since
+	// the zero time, so a window of 24 hours starts at midnight UTC
+	// (default 24 hours)
+	Window time.Duration
+	// Limit is the usage allowed per key and window, whose zero fields are
+	// unlimited
+	Limit QuotaUsage
+	// Limits are the usages allowed to specific keys instead of Limit, e.g.
+	// for tenants on a larger plan
+	Limits map[string]QuotaUsage
+
+	init sync.Once
+	now  func() time.Time
+}
+
+// setDefaults fills in the defaults of the unset fields
+func (q *Quota) setDefaults() {
+	q.init.Do(func() {
+		if q.Store == nil {
+			q.Store = &MemoryQuotaStore{}
+		}
+		if q.Window <= 0 {
+			q.Window = 24 * time.Hour
+		}
+		if q.now == nil {
+			q.now = time.Now
+		}
+	})
+}
+
+// window returns the start of the current window
+func (q *Quota) window() time.Time {
+	return q.now().UTC().Truncate(q.Window)
+}
+
+// limit returns the usage allowed to key
+func (q *Quota) limit(key string) QuotaUsage {
+	if limit, ok := q.Limits[key]; ok {
+		return limit
+	}
+	return q.Limit
+}
+
+// Charge adds usage to the usage of key in the current window, or returns
+// ErrQuotaExceeded, charging nothing, if it would exceed the limit of key
+func (q *Quota) Charge(ctx context.Context, key string, usage QuotaUsage) (QuotaCharge, error) {
+	q.setDefaults()
+	limit := q.limit(key)
+	window := q.window()
+	current, ok, err := q.Store.Charge(ctx, key, window, usage, limit)
+	if err != nil {
+		return QuotaCharge{}, fmt.Errorf("failed to charge quota of %q: %w", key, err)
+	}
+	if !ok {
+		return QuotaCharge{}, fmt.Errorf("%w: %q used %d of %d pages and %d of %d bytes, %d pages and %d bytes requested",
+			ErrQuotaExceeded, key, current.Pages, limit.Pages, current.Bytes, limit.Bytes, usage.Pages, usage.Bytes)
+	}
+	return QuotaCharge{Key: key, Window: window, Usage: usage}, nil
+}
+
+// Refund subtracts a charge from the usage of the window it was added to,
+// e.g. when the work it was charged for is not done after all
+func (q *Quota) Refund(ctx context.Context, charge QuotaCharge) error {
+	q.setDefaults()
+	refund := QuotaUsage{Pages: -charge.Usage.Pages, Bytes: -charge.Usage.Bytes}
+	if _, _, err := q.Store.Charge(ctx, charge.Key, charge.Window, refund, QuotaUsage{}); err != nil {
+		return fmt.Errorf("failed to refund quota of %q: %w", charge.Key, err)
+	}
+	return nil
+}
+
+// ChargeFile charges the size of a PDF file and the pages opts selects in it
+// to key, counting its pages with pdfinfo
+func (q *Quota) ChargeFile(ctx context.Context, c *Converter, key, inputPath string, opts *Options) (QuotaCharge, error) {
+	usage, err := fileUsage(ctx, c, inputPath, opts)
+	if err != nil {
+		return QuotaCharge{}, err
+	}
+	return q.Charge(ctx, key, usage)
+}
+
+// fileUsage returns the size of a PDF file and the number of pages selected
+// by the page options of opts, at most its page count
+func fileUsage(ctx context.Context, c *Converter, inputPath string, opts *Options) (QuotaUsage, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	if opts == nil {
+		opts = &Options{}
+	}
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+	stat, err := os.Stat(inputPath)
+	if err != nil {
+		return QuotaUsage{}, fmt.Errorf("failed to stat %s: %w", inputPath, err)
+	}
+	count, err := c.PageCount(ctx, inputPath, opts)
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+	pages, err := c.selectedPages(ctx, inputPath, opts, count)
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+	return QuotaUsage{Pages: int64(pages), Bytes: stat.Size()}, nil
+}
+
+// Usage returns the usage of key in the current window, for accounting and
+// billing
+func (q *Quota) Usage(ctx context.Context, key string) (QuotaUsage, error) {
+	q.setDefaults()
+	return q.Store.Usage(ctx, key, q.window())
+}
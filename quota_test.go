@@ -0,0 +1,166 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	quota := &Quota{
+		Limit:  QuotaUsage{Pages: 10},
+		Limits: map[string]QuotaUsage{"enterprise": {Pages: 100, Bytes: 1000}},
+		now:    func() time.Time { return now },
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		usage    QuotaUsage
+		exceeded bool
+	}{
+		{"FirstCharge", "acme", QuotaUsage{Pages: 6, Bytes: 1 << 20}, false},
+		{"UpToTheLimit", "acme", QuotaUsage{Pages: 4}, false},
+		{"OverTheLimit", "acme", QuotaUsage{Pages: 1}, true},
+		{"OtherKey", "globex", QuotaUsage{Pages: 10}, false},
+		{"KeyLimit", "enterprise", QuotaUsage{Pages: 50, Bytes: 900}, false},
+		{"KeyBytesLimit", "enterprise", QuotaUsage{Pages: 1, Bytes: 200}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := quota.Charge(ctx, tt.key, tt.usage)
+			if tt.exceeded != errors.Is(err, ErrQuotaExceeded) {
+				t.Errorf("expected exceeded %v, got %v", tt.exceeded, err)
+			}
+		})
+	}
+
+	if usage, err := quota.Usage(ctx, "acme"); err != nil || usage != (QuotaUsage{Pages: 10, Bytes: 1 << 20}) {
+		t.Errorf("expected the rejected charge not to count, got %+v, %v", usage, err)
+	}
+
+	// the usage starts over in the next window
+	now = now.Add(14 * time.Hour)
+	if usage, _ := quota.Usage(ctx, "acme"); usage != (QuotaUsage{}) {
+		t.Errorf("expected no usage in a new window, got %+v", usage)
+	}
+	if _, err := quota.Charge(ctx, "acme", QuotaUsage{Pages: 10}); err != nil {
+		t.Errorf("expected the quota to be reset, got %v", err)
+	}
+}
+
+func TestPool_Quota(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if strings.Contains(name, "pdfinfo") {
+			_, err := io.WriteString(stdout, "Pages:          3\n")
+			return err
+		}
+		_, err := io.WriteString(stdout, "text")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte("%PDF-1.7"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	quota := &Quota{Limit: QuotaUsage{Pages: 5}}
+	pool := &Pool{Converter: converter, Quota: quota}
+
+	if _, err := pool.Submit(ctx, Job{InputPath: input, Tenant: "acme"}); err != nil {
+		t.Fatalf("failed to submit: %v", err)
+	}
+	if _, err := pool.Submit(ctx, Job{InputPath: input, Tenant: "acme"}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := pool.Submit(ctx, Job{InputPath: input, Tenant: "globex"}); err != nil {
+		t.Errorf("expected other tenants to be unaffected, got %v", err)
+	}
+	if usage, _ := quota.Usage(ctx, "acme"); usage != (QuotaUsage{Pages: 3, Bytes: 8}) {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+
+	// only the selected pages are charged, at most the page count
+	pool.Profiles = map[string]*Options{
+		"first":   {LastPage: 1},
+		"pages":   {Pages: "2-3", ExcludePages: "3"},
+		"beyond":  {Pages: "2-100"},
+		"exclude": {ExcludePages: "1"},
+	}
+	for profile, pages := range map[string]int64{"first": 1, "pages": 1, "beyond": 2, "exclude": 2} {
+		if _, err := pool.Submit(ctx, Job{InputPath: input, Tenant: profile, Profile: profile}); err != nil {
+			t.Fatalf("failed to submit with profile %s: %v", profile, err)
+		}
+		if usage, _ := quota.Usage(ctx, profile); usage.Pages != pages {
+			t.Errorf("expected %d pages charged with profile %s, got %d", pages, profile, usage.Pages)
+		}
+	}
+}
+
+type failingQueue struct{ MemoryQueue }
+
+func (*failingQueue) Push(ctx context.Context, job Job) error {
+	return errors.New("queue unavailable")
+}
+
+func TestPool_QuotaRefund(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, "Pages:          3\n")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte("%PDF-1.7"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	quota := &Quota{Limit: QuotaUsage{Pages: 5}}
+	pool := &Pool{Converter: converter, Quota: quota, Queue: &failingQueue{}}
+	if _, err := quota.Charge(ctx, "acme", QuotaUsage{Pages: 1, Bytes: 1}); err != nil {
+		t.Fatalf("failed to charge: %v", err)
+	}
+
+	if _, err := pool.Submit(ctx, Job{ID: "lost", InputPath: input, Tenant: "acme"}); err == nil {
+		t.Fatal("expected the error of the queue")
+	}
+	if usage, _ := quota.Usage(ctx, "acme"); usage != (QuotaUsage{Pages: 1, Bytes: 1}) {
+		t.Errorf("expected the charge of the job to be refunded, got %+v", usage)
+	}
+	if _, err := pool.Status(ctx, "lost"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected no status for the job that was not queued, got %v", err)
+	}
+}
+
+func TestQuota_RefundWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	quota := &Quota{Limit: QuotaUsage{Pages: 10}, now: func() time.Time { return now }}
+
+	charge, err := quota.Charge(ctx, "acme", QuotaUsage{Pages: 3})
+	if err != nil {
+		t.Fatalf("failed to charge: %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := quota.Charge(ctx, "acme", QuotaUsage{Pages: 2}); err != nil {
+		t.Fatalf("failed to charge: %v", err)
+	}
+
+	// the refund goes to the window of the charge, not the current one
+	if err := quota.Refund(ctx, charge); err != nil {
+		t.Fatalf("failed to refund: %v", err)
+	}
+	if usage, _ := quota.Usage(ctx, "acme"); usage != (QuotaUsage{Pages: 2}) {
+		t.Errorf("expected the current window to keep its usage, got %+v", usage)
+	}
+}
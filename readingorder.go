@@ -0,0 +1,185 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// minColumnGap is the narrowest gap in points between two columns
+const minColumnGap = 5
+
+// runReadingOrder converts the pages selected by opts with -bbox-layout and
+// writes the text of their blocks in reading order
+func (c *Converter) runReadingOrder(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	pages, err := c.geometry(ctx, inputPath, opts, true)
+	if err != nil {
+		return err
+	}
+	return writeOutput(outputPath, stdout, func(w io.Writer) error {
+		for _, page := range pages {
+			text := readingOrderText(page)
+			if !opts.NoPageBreaks {
+				text += "\f"
+			}
+			if _, err := io.WriteString(w, text); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// readingOrderText returns the text of the blocks of page in reading order,
+// with a line break after each line and a blank line between blocks
+func readingOrderText(page PageGeometry) string {
+	var b strings.Builder
+	for i, block := range ReadingOrder(page.Blocks) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, line := range block.Lines {
+			b.WriteString(line.Text)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// ReadingOrder returns blocks sorted into reading order with a recursive
+// XY-cut: blocks are split into columns at vertical gaps running through all
+// of them, read left to right, and otherwise into bands at horizontal gaps,
+// read top to bottom. Bands sharing a column gap are kept together, so a
+// two-column body between a full-width title and footer is read column by
+// column.
+func ReadingOrder(blocks []Block) []Block {
+	if len(blocks) <= 1 {
+		return blocks
+	}
+
+	if columns := splitColumns(blocks); len(columns) > 1 {
+		var ordered []Block
+		for _, column := range columns {
+			ordered = append(ordered, ReadingOrder(column)...)
+		}
+		return ordered
+	}
+
+	bands := mergeBands(splitBands(blocks))
+	if len(bands) > 1 {
+		var ordered []Block
+		for _, band := range bands {
+			ordered = append(ordered, ReadingOrder(band)...)
+		}
+		return ordered
+	}
+
+	ordered := slices.Clone(blocks)
+	slices.SortStableFunc(ordered, func(a, b Block) int {
+		if a.Box.YMin != b.Box.YMin {
+			return cmp.Compare(a.Box.YMin, b.Box.YMin)
+		}
+		return cmp.Compare(a.Box.XMin, b.Box.XMin)
+	})
+	return ordered
+}
+
+// gap is an interval not covered by any block
+type gap struct {
+	min, max float64
+}
+
+// columnGaps returns the vertical gaps of at least minColumnGap running
+// through all blocks
+func columnGaps(blocks []Block) []gap {
+	sorted := slices.Clone(blocks)
+	slices.SortFunc(sorted, func(a, b Block) int { return cmp.Compare(a.Box.XMin, b.Box.XMin) })
+
+	var gaps []gap
+	right := sorted[0].Box.XMax
+	for _, block := range sorted[1:] {
+		if block.Box.XMin-right >= minColumnGap {
+			gaps = append(gaps, gap{right, block.Box.XMin})
+		}
+		right = max(right, block.Box.XMax)
+	}
+	return gaps
+}
+
+// splitColumns splits blocks at their column gaps, from left to right
+func splitColumns(blocks []Block) [][]Block {
+	gaps := columnGaps(blocks)
+	columns := make([][]Block, len(gaps)+1)
+	for _, block := range blocks {
+		i := 0
+		for i < len(gaps) && block.Box.XMin >= gaps[i].max {
+			i++
+		}
+		columns[i] = append(columns[i], block)
+	}
+	return columns
+}
+
+// splitBands splits blocks at the horizontal gaps between them, from top to
+// bottom
+func splitBands(blocks []Block) [][]Block {
+	sorted := slices.Clone(blocks)
+	slices.SortStableFunc(sorted, func(a, b Block) int { return cmp.Compare(a.Box.YMin, b.Box.YMin) })
+
+	var bands [][]Block
+	bottom := 0.0
+	for i, block := range sorted {
+		if i == 0 || block.Box.YMin > bottom {
+			bands = append(bands, nil)
+		}
+		bands[len(bands)-1] = append(bands[len(bands)-1], block)
+		bottom = max(bottom, block.Box.YMax)
+	}
+	return bands
+}
+
+// mergeBands merges consecutive bands whose gaps overlap in a column gap, so
+// the paragraphs of a column are not interleaved with those of its neighbor.
+// The space left and right of a band counts as a gap, so a band holding a
+// single column merges with the columns around it.
+func mergeBands(bands [][]Block) [][]Block {
+	var merged [][]Block
+	var mergedGaps []gap
+	for _, band := range bands {
+		left, right := math.Inf(1), math.Inf(-1)
+		for _, block := range band {
+			left, right = min(left, block.Box.XMin), max(right, block.Box.XMax)
+		}
+		gaps := append([]gap{{math.Inf(-1), left}}, columnGaps(band)...)
+		gaps = append(gaps, gap{right, math.Inf(1)})
+
+		if len(merged) > 0 {
+			if common := intersectGaps(mergedGaps, gaps); len(common) > 0 {
+				merged[len(merged)-1] = append(merged[len(merged)-1], band...)
+				mergedGaps = common
+				continue
+			}
+		}
+		merged = append(merged, band)
+		mergedGaps = gaps
+	}
+	return merged
+}
+
+// intersectGaps returns the finite overlaps of at least minColumnGap between
+// a and b
+func intersectGaps(a, b []gap) []gap {
+	var common []gap
+	for _, x := range a {
+		for _, y := range b {
+			lo, hi := max(x.min, y.min), min(x.max, y.max)
+			if !math.IsInf(lo, 0) && !math.IsInf(hi, 0) && hi-lo >= minColumnGap {
+				common = append(common, gap{lo, hi})
+			}
+		}
+	}
+	return common
+}
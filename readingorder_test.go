@@ -0,0 +1,117 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// textBlock returns a block with a single line of text
+func textBlock(text string, xMin, yMin, xMax, yMax float64) Block {
+	box := Rect{XMin: xMin, YMin: yMin, XMax: xMax, YMax: yMax}
+	return Block{Box: box, Lines: []Line{{Text: text, Box: box}}}
+}
+
+// paperBlocks are the blocks of a two-column paper page in the order
+// pdftotext finds them, with the paragraph gaps of both columns aligned
+var paperBlocks = []Block{
+	textBlock("Title", 50, 40, 560, 70),
+	textBlock("L1", 50, 100, 290, 200),
+	textBlock("R1", 320, 100, 560, 200),
+	textBlock("L2", 50, 210, 290, 400),
+	textBlock("R2", 320, 210, 560, 300),
+	textBlock("L3", 50, 410, 290, 600),
+	textBlock("Footer", 50, 750, 560, 760),
+}
+
+func TestReadingOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		blocks   []Block
+		expected []string
+	}{
+		{
+			name:     "single column",
+			blocks:   []Block{textBlock("B", 50, 200, 500, 300), textBlock("A", 50, 100, 500, 190)},
+			expected: []string{"A", "B"},
+		},
+		{
+			name:     "two columns between title and footer",
+			blocks:   paperBlocks,
+			expected: []string{"Title", "L1", "L2", "L3", "R1", "R2", "Footer"},
+		},
+		{
+			name: "three columns",
+			blocks: []Block{
+				textBlock("C1", 400, 100, 560, 300),
+				textBlock("A1", 50, 100, 200, 300),
+				textBlock("B1", 225, 100, 375, 300),
+				textBlock("A2", 50, 310, 200, 500),
+				textBlock("B2", 225, 310, 375, 400),
+			},
+			expected: []string{"A1", "A2", "B1", "B2", "C1"},
+		},
+		{
+			name: "full-width figure between column sections",
+			blocks: []Block{
+				textBlock("L1", 50, 100, 290, 200),
+				textBlock("R1", 320, 100, 560, 200),
+				textBlock("Figure", 50, 220, 560, 300),
+				textBlock("L2", 50, 320, 290, 400),
+				textBlock("R2", 320, 320, 560, 400),
+			},
+			expected: []string{"L1", "R1", "Figure", "L2", "R2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var texts []string
+			for _, block := range ReadingOrder(tt.blocks) {
+				texts = append(texts, block.Lines[0].Text)
+			}
+			if !slices.Equal(texts, tt.expected) {
+				t.Errorf("expected order %v, got %v", tt.expected, texts)
+			}
+		})
+	}
+}
+
+func TestConverter_ReadingOrder(t *testing.T) {
+	layout := blockLayoutOutput([][]Block{paperBlocks, {textBlock("Last page", 50, 100, 500, 200)}})
+	runner := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if !slices.Contains(args, "-bbox-layout") {
+			return errors.New("expected -bbox-layout")
+		}
+		_, err := io.WriteString(stdout, layout)
+		return err
+	})
+	converter, err := New(WithRunner(runner))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	text, err := converter.Convert(context.Background(), "input.pdf", &Options{ReadingOrder: true, Layout: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Title\n\nL1\n\nL2\n\nL3\n\nR1\n\nR2\n\nFooter\n\fLast page"
+	if text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+
+	pages, err := converter.ConvertPages(context.Background(), "input.pdf", &Options{ReadingOrder: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pages) != 2 || !strings.HasPrefix(pages[0].Text, "Title\n\nL1") {
+		t.Errorf("unexpected pages %+v", pages)
+	}
+
+	if _, err := converter.Command("input.pdf", "-", &Options{ReadingOrder: true}); !errors.Is(err, ErrCommandFailed) {
+		t.Errorf("expected error %v, got %v", ErrCommandFailed, err)
+	}
+}
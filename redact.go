@@ -0,0 +1,75 @@
+package pdftotext
+
+import (
+	"errors"
+	"strings"
+)
+
+// passwordMask replaces passwords in arguments, errors and logs
+const passwordMask = "***"
+
+// maskPasswords returns a copy of args with password values replaced
+func maskPasswords(args []string) []string {
+	masked := make([]string, len(args))
+	copy(masked, args)
+	for i := 0; i+1 < len(masked); i++ {
+		if masked[i] == "-opw" || masked[i] == "-upw" {
+			masked[i+1] = passwordMask
+		}
+	}
+	return masked
+}
+
+// passwordValues returns the password values of args
+func passwordValues(args []string) []string {
+	var secrets []string
+	for i := 0; i+1 < len(args); i++ {
+		if (args[i] == "-opw" || args[i] == "-upw") && args[i+1] != "" {
+			secrets = append(secrets, args[i+1])
+			i++
+		}
+	}
+	return secrets
+}
+
+// redact replaces the secrets in s
+func redact(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, passwordMask)
+	}
+	return s
+}
+
+// redactedError is an error of a command run with passwords, whose message
+// has the passwords replaced. The passwords travel with the error so the
+// messages built from it and the stderr of the command can be redacted too.
+type redactedError struct {
+	err     error
+	secrets []string
+}
+
+// redactError returns err with the secrets replaced in its message, or err
+// itself if there are no secrets
+func redactError(err error, secrets []string) error {
+	if err == nil || len(secrets) == 0 {
+		return err
+	}
+	return &redactedError{err: err, secrets: secrets}
+}
+
+func (e *redactedError) Error() string {
+	return redact(e.err.Error(), e.secrets)
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// secretsOf returns the passwords of the command err was returned by
+func secretsOf(err error) []string {
+	var redacted *redactedError
+	if errors.As(err, &redacted) {
+		return redacted.secrets
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPasswordRedaction(t *testing.T) {
+	const password = "password"
+	echo := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Command Line Error: Incorrect password\nargs: "+strings.Join(args, " ")+"\n")
+		return &ExitError{Code: 1}
+	})
+	failing := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		return fmt.Errorf("remote run of %s failed", strings.Join(args, " "))
+	})
+
+	tests := []struct {
+		name   string
+		runner Runner
+		want   error
+	}{
+		{"stderr", echo, ErrEncrypted},
+		{"runner error", failing, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			converter, err := New(WithRunner(tt.runner), WithLogger(logger))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			opts := &Options{UserPassword: password, OwnerPassword: "owner-secret"}
+			_, err = converter.Convert(context.Background(), "input.pdf", opts)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if msg := err.Error(); strings.Contains(msg, "-upw "+password) || strings.Contains(msg, "owner-secret") {
+				t.Errorf("expected the passwords masked in the error, got %q", msg)
+			}
+			if !strings.Contains(err.Error(), "-upw ***") {
+				t.Errorf("expected the masked arguments in the error, got %q", err)
+			}
+			if strings.Contains(logs.String(), "owner-secret") || strings.Contains(logs.String(), "-upw "+password) {
+				t.Errorf("expected the passwords masked in the log, got %s", logs.String())
+			}
+
+			_, err = converter.Info(context.Background(), "input.pdf", opts)
+			if err == nil || strings.Contains(err.Error(), "owner-secret") {
+				t.Errorf("expected the passwords masked in the Info error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	converter, err := New(WithRunner(fakeRun("", "", 0)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	line, err := converter.CommandLine("input.pdf", "-", &Options{OwnerPassword: "owner-secret", UserPassword: "-upw"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(line, "owner-secret") || !strings.Contains(line, "-opw *** -upw *** ") {
+		t.Errorf("expected the passwords masked, got %q", line)
+	}
+	if _, err := converter.CommandLine("input.pdf", "-", &Options{ReadingOrder: true}); !errors.Is(err, ErrCommandFailed) {
+		t.Errorf("expected ErrCommandFailed, got %v", err)
+	}
+}
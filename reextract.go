@@ -0,0 +1,173 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionChange is the smallest backend version change that triggers a re-extraction
+type VersionChange int
+
+const (
+	// VersionAny re-extracts on every run regardless of the backend version
+	VersionAny VersionChange = iota
+	// VersionPatch re-extracts when any part of the version changes
+	VersionPatch
+	// VersionMinor re-extracts when the minor or major version changes
+	VersionMinor
+	// VersionMajor re-extracts when the major version changes
+	VersionMajor
+)
+
+// ArchivedExtraction is a previously stored conversion result
+type ArchivedExtraction struct {
+	// ID identifies the extraction in the archive
+	ID string
+	// InputPath is the path of the archived PDF file
+	InputPath string
+	// Options are the options the text was extracted with
+	Options *Options
+	// BackendVersion is the pdftotext version the text was extracted with
+	BackendVersion string
+	// Text is the stored text
+	Text string
+}
+
+// Archive provides stored conversion results for re-extraction
+type Archive interface {
+	// List returns the archived extractions
+	List(ctx context.Context) ([]ArchivedExtraction, error)
+}
+
+// ReextractionResult is the outcome of re-extracting an archived document
+type ReextractionResult struct {
+	// Extraction is the archived extraction
+	Extraction ArchivedExtraction
+	// BackendVersion is the pdftotext version used for the re-extraction
+	BackendVersion string
+	// Text is the re-extracted text
+	Text string
+	// Similarity is the similarity between the stored and re-extracted text
+	Similarity float64
+	// Changed reports whether the text changed materially and needs review
+	Changed bool
+	// Err is the error of the re-extraction, if any
+	Err error
+}
+
+// Reextractor re-extracts archived documents when the backend version
+// changes, or on every run when Threshold is VersionAny, and flags
+// extractions that changed materially
+type Reextractor struct {
+	// Converter performs the re-extractions
+	Converter *Converter
+	// Archive provides the stored extractions
+	Archive Archive
+	// Threshold is the smallest version change that triggers a re-extraction
+	Threshold VersionChange
+	// MinSimilarity is the similarity below which a result is flagged as
+	// changed (default 1, i.e. any change in the words is flagged)
+	MinSimilarity float64
+	// OnResult is called with every re-extraction result
+	OnResult func(ReextractionResult)
+}
+
+// RunOnce re-extracts the archived documents that are due and returns the results
+func (r *Reextractor) RunOnce(ctx context.Context) ([]ReextractionResult, error) {
+	version, err := r.Converter.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	extractions, err := r.Archive.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive: %w", err)
+	}
+
+	minSimilarity := r.MinSimilarity
+	if minSimilarity == 0 {
+		minSimilarity = 1
+	}
+
+	var results []ReextractionResult
+	for _, extraction := range extractions {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		if r.Threshold != VersionAny && compareVersions(extraction.BackendVersion, version) < r.Threshold {
+			continue
+		}
+
+		result := ReextractionResult{Extraction: extraction, BackendVersion: version}
+		result.Text, result.Err = r.Converter.Convert(ctx, extraction.InputPath, extraction.Options)
+		if result.Err == nil {
+			result.Similarity = Similarity(extraction.Text, result.Text)
+			result.Changed = result.Similarity < minSimilarity
+		}
+		if r.OnResult != nil {
+			r.OnResult(result)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Run calls RunOnce every interval until ctx is done
+func (r *Reextractor) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// compareVersions returns the most significant part in which two dotted
+// versions differ, or VersionAny if they are equal. Versions that cannot be
+// parsed are treated as a major change when they differ.
+func compareVersions(a, b string) VersionChange {
+	if a == b {
+		return VersionAny
+	}
+	partsA, okA := parseVersionParts(a)
+	partsB, okB := parseVersionParts(b)
+	if !okA || !okB {
+		return VersionMajor
+	}
+
+	for i, change := range []VersionChange{VersionMajor, VersionMinor} {
+		if partsA[i] != partsB[i] {
+			return change
+		}
+	}
+	if partsA != partsB {
+		return VersionPatch
+	}
+	return VersionAny
+}
+
+// parseVersionParts parses a dotted version into its major, minor and patch numbers
+func parseVersionParts(version string) ([3]int, bool) {
+	var parts [3]int
+	fields := strings.Split(version, ".")
+	if len(fields) > len(parts) {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,114 @@
+package pdftotext
+
+import (
+	"context"
+	"testing"
+)
+
+type sliceArchive []ArchivedExtraction
+
+func (a sliceArchive) List(context.Context) ([]ArchivedExtraction, error) { return a, nil }
+
+func TestReextractor_RunOnce(t *testing.T) {
+	binary := writeFakeBinary(t, `if [ "$1" = "-v" ]; then
+	echo "pdftotext version 24.02.1" >&2
+	exit 0
+fi
+echo "the quick brown fox"
+`)
+	converter, err := New(WithBinaryPath(binary))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	archive := sliceArchive{
+		{ID: "same", BackendVersion: "24.02.1", Text: "the quick brown fox"},
+		{ID: "patch", BackendVersion: "24.02.0", Text: "the quick brown fox"},
+		{ID: "minor", BackendVersion: "24.01.0", Text: "the quick brown fox"},
+		{ID: "major", BackendVersion: "23.12.0", Text: "the quick brown dog"},
+	}
+
+	tests := []struct {
+		name          string
+		threshold     VersionChange
+		minSimilarity float64
+		expectedIDs   []string
+		expectedFlags []bool
+	}{
+		{
+			name:          "Schedule re-extracts everything",
+			threshold:     VersionAny,
+			expectedIDs:   []string{"same", "patch", "minor", "major"},
+			expectedFlags: []bool{false, false, false, true},
+		},
+		{
+			name:          "Minor version threshold",
+			threshold:     VersionMinor,
+			expectedIDs:   []string{"minor", "major"},
+			expectedFlags: []bool{false, true},
+		},
+		{
+			name:          "Tolerated similarity",
+			threshold:     VersionMajor,
+			minSimilarity: 0.7,
+			expectedIDs:   []string{"major"},
+			expectedFlags: []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called int
+			r := &Reextractor{
+				Converter:     converter,
+				Archive:       archive,
+				Threshold:     tt.threshold,
+				MinSimilarity: tt.minSimilarity,
+				OnResult:      func(ReextractionResult) { called++ },
+			}
+
+			results, err := r.RunOnce(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != len(tt.expectedIDs) || called != len(tt.expectedIDs) {
+				t.Fatalf("expected %d results, got %d (%d callbacks)", len(tt.expectedIDs), len(results), called)
+			}
+
+			for i, result := range results {
+				if result.Err != nil {
+					t.Errorf("unexpected error for %s: %v", result.Extraction.ID, result.Err)
+				}
+				if result.Extraction.ID != tt.expectedIDs[i] {
+					t.Errorf("result %d: expected %q, got %q", i, tt.expectedIDs[i], result.Extraction.ID)
+				}
+				if result.Changed != tt.expectedFlags[i] {
+					t.Errorf("result %s: expected changed %v, got %v (similarity %v)", result.Extraction.ID, tt.expectedFlags[i], result.Changed, result.Similarity)
+				}
+				if result.BackendVersion != "24.02.1" {
+					t.Errorf("expected backend version %q, got %q", "24.02.1", result.BackendVersion)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected VersionChange
+	}{
+		{"24.02.0", "24.02.0", VersionAny},
+		{"24.02.0", "24.02.1", VersionPatch},
+		{"24.02", "24.02.1", VersionPatch},
+		{"24.02.0", "24.03.0", VersionMinor},
+		{"23.12.0", "24.02.0", VersionMajor},
+		{"4.04", "4.05", VersionMinor},
+		{"unknown", "24.02.0", VersionMajor},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("compareVersions(%q, %q): expected %v, got %v", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
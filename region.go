@@ -0,0 +1,115 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Region is a named area of the pages of a document, e.g. the invoice number
+// box of a form, in PDF points with the origin at the top-left corner of the
+// page like the crop area
+type Region struct {
+	// Name identifies the text of the region in the results
+	Name string `json:"name"`
+	// X and Y are the top-left corner of the region
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	// W and H are the width and height of the region
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+	// Pages is a page range expression such as "1" or "2-" selecting the
+	// pages the region applies to, every converted page if it is empty
+	Pages string `json:"pages,omitempty"`
+}
+
+// Rect returns the rectangle of the region
+func (r Region) Rect() Rect {
+	return Rect{XMin: r.X, YMin: r.Y, XMax: r.X + r.W, YMax: r.Y + r.H}
+}
+
+// RegionText is the text of a region on a page
+type RegionText struct {
+	// Name is the name of the region
+	Name string `json:"name"`
+	// Page is the page number
+	Page int `json:"page"`
+	// Text is the text of the words whose centers lie inside the region
+	Text string `json:"text"`
+}
+
+// ExtractRegions returns the text of each region on each page it applies to,
+// ordered by page and then like regions. The word geometry of the pages is
+// converted once for all regions, instead of running pdftotext with a crop
+// area for each. The pages are selected by opts like in ConvertGeometry.
+func (c *Converter) ExtractRegions(ctx context.Context, inputPath string, regions []Region, opts *Options) ([]RegionText, error) {
+	ranges, err := regionPageRanges(regions)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := c.ConvertGeometry(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	results := []RegionText{}
+	for _, page := range pages {
+		for i, r := range regions {
+			if r.Pages != "" && !inPageRanges(ranges[i], page.Number) {
+				continue
+			}
+			results = append(results, RegionText{Name: r.Name, Page: page.Number, Text: regionText(page, r.Rect())})
+		}
+	}
+	return results, nil
+}
+
+// regionPageRanges validates regions and returns their parsed page ranges
+func regionPageRanges(regions []Region) ([][]PageRange, error) {
+	ranges := make([][]PageRange, len(regions))
+	for i, r := range regions {
+		if r.Name == "" || r.W <= 0 || r.H <= 0 {
+			return nil, fmt.Errorf("%w: %q needs a name and a positive size", ErrInvalidRegion, r.Name)
+		}
+		if r.Pages == "" {
+			continue
+		}
+		var err error
+		if ranges[i], err = ParsePageRange(r.Pages); err != nil {
+			return nil, fmt.Errorf("region %q: %w", r.Name, err)
+		}
+	}
+	return ranges, nil
+}
+
+// inPageRanges reports whether page lies in one of ranges
+func inPageRanges(ranges []PageRange, page int) bool {
+	for _, r := range ranges {
+		if page >= r.First && (r.Last == 0 || page <= r.Last) {
+			return true
+		}
+	}
+	return false
+}
+
+// regionText returns the text of the words of a page whose centers lie
+// inside r, in reading order with a line break wherever a word starts below
+// the previous one
+func regionText(g PageGeometry, r Rect) string {
+	var b strings.Builder
+	var prev *Word
+	for i, word := range g.Words {
+		if !r.Contains(word.Box.Center()) {
+			continue
+		}
+		if prev != nil {
+			if word.Box.YMin >= prev.Box.YMax {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(word.Text)
+		prev = &g.Words[i]
+	}
+	return b.String()
+}
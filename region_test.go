@@ -0,0 +1,79 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestConverter_ExtractRegions(t *testing.T) {
+	var runs int
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		runs++
+		_, err := io.WriteString(stdout, bboxOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		regions       []Region
+		expected      []RegionText
+		expectedError error
+		expectedRuns  int
+	}{
+		{
+			name: "Regions",
+			regions: []Region{
+				{Name: "greeting", X: 50, Y: 50, W: 100, H: 20},
+				{Name: "first column", X: 0, Y: 0, W: 91, H: 792, Pages: "1"},
+			},
+			expected: []RegionText{
+				{Name: "greeting", Page: 1, Text: "Hello world"},
+				{Name: "first column", Page: 1, Text: "Hello\nSecond"},
+				{Name: "greeting", Page: 2, Text: "Fish & chips"},
+			},
+			expectedRuns: 1,
+		},
+		{
+			name:         "Open page range",
+			regions:      []Region{{Name: "rest", X: 0, Y: 0, W: 612, H: 792, Pages: "2-"}},
+			expected:     []RegionText{{Name: "rest", Page: 2, Text: "Fish & chips"}},
+			expectedRuns: 1,
+		},
+		{
+			name:          "Missing name",
+			regions:       []Region{{X: 0, Y: 0, W: 10, H: 10}},
+			expectedError: ErrInvalidRegion,
+		},
+		{
+			name:          "Empty area",
+			regions:       []Region{{Name: "total", X: 10, Y: 10}},
+			expectedError: ErrInvalidRegion,
+		},
+		{
+			name:          "Invalid pages",
+			regions:       []Region{{Name: "total", W: 10, H: 10, Pages: "3-1"}},
+			expectedError: ErrInvalidRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs = 0
+			results, err := converter.ExtractRegions(context.Background(), "input.pdf", tt.regions, nil)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && !reflect.DeepEqual(results, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, results)
+			}
+			if runs != tt.expectedRuns {
+				t.Errorf("expected %d runs, got %d", tt.expectedRuns, runs)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RegionTemplate maps the fixed layout of a document type, such as the
+// invoices of one supplier, to named fields. Templates are plain JSON, so
+// they can be kept in files or a database and edited without a rebuild:
+//
+//	{
+//	  "document_type": "acme-invoice",
+//	  "fields": [
+//	    {"name": "number", "x": 400, "y": 60, "w": 150, "h": 20, "pages": "1"},
+//	    {"name": "total", "x": 400, "y": 700, "w": 150, "h": 30, "pages": "1"}
+//	  ]
+//	}
+type RegionTemplate struct {
+	// DocumentType is the key of the template
+	DocumentType string `json:"document_type"`
+	// Fields are the regions holding the fields, named by the field names
+	Fields []Region `json:"fields"`
+}
+
+// Validate checks that the template has a document type and that its fields
+// are valid regions with unique names
+func (t *RegionTemplate) Validate() error {
+	if t.DocumentType == "" {
+		return fmt.Errorf("%w: template without a document type", ErrInvalidRegion)
+	}
+	if _, err := regionPageRanges(t.Fields); err != nil {
+		return fmt.Errorf("template %q: %w", t.DocumentType, err)
+	}
+	seen := map[string]bool{}
+	for _, field := range t.Fields {
+		if seen[field.Name] {
+			return fmt.Errorf("template %q: %w: duplicate field %q", t.DocumentType, ErrInvalidRegion, field.Name)
+		}
+		seen[field.Name] = true
+	}
+	return nil
+}
+
+// LoadRegionTemplates reads a JSON array of region templates and returns them
+// keyed by document type, after validating each
+func LoadRegionTemplates(r io.Reader) (map[string]*RegionTemplate, error) {
+	var list []*RegionTemplate
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode region templates: %w", err)
+	}
+	templates := make(map[string]*RegionTemplate, len(list))
+	for _, t := range list {
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+		if templates[t.DocumentType] != nil {
+			return nil, fmt.Errorf("%w: duplicate template %q", ErrInvalidRegion, t.DocumentType)
+		}
+		templates[t.DocumentType] = t
+	}
+	return templates, nil
+}
+
+// ExtractFields extracts the fields of a template from a PDF file with
+// ExtractRegions and returns their text by field name. A field on several
+// pages gets the texts of the pages joined by newlines, skipping empty ones,
+// and a field on none of the converted pages an empty string.
+func (c *Converter) ExtractFields(ctx context.Context, inputPath string, tmpl *RegionTemplate, opts *Options) (map[string]string, error) {
+	if err := tmpl.Validate(); err != nil {
+		return nil, err
+	}
+	results, err := c.ExtractRegions(ctx, inputPath, tmpl.Fields, opts)
+	if err != nil {
+		return nil, err
+	}
+	texts := make(map[string][]string, len(tmpl.Fields))
+	for _, r := range results {
+		if r.Text != "" {
+			texts[r.Name] = append(texts[r.Name], r.Text)
+		}
+	}
+	fields := make(map[string]string, len(tmpl.Fields))
+	for _, field := range tmpl.Fields {
+		fields[field.Name] = strings.Join(texts[field.Name], "\n")
+	}
+	return fields, nil
+}
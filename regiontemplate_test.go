@@ -0,0 +1,93 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadRegionTemplates(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedTypes []string
+		expectedError error
+	}{
+		{
+			name: "Templates",
+			input: `[
+				{"document_type": "letter", "fields": [{"name": "greeting", "x": 50, "y": 50, "w": 100, "h": 20}]},
+				{"document_type": "menu", "fields": [{"name": "dish", "x": 0, "y": 0, "w": 612, "h": 792, "pages": "2"}]}
+			]`,
+			expectedTypes: []string{"letter", "menu"},
+		},
+		{
+			name:          "Missing document type",
+			input:         `[{"fields": []}]`,
+			expectedError: ErrInvalidRegion,
+		},
+		{
+			name:          "Duplicate template",
+			input:         `[{"document_type": "letter"}, {"document_type": "letter"}]`,
+			expectedError: ErrInvalidRegion,
+		},
+		{
+			name:          "Duplicate field",
+			input:         `[{"document_type": "letter", "fields": [{"name": "a", "w": 1, "h": 1}, {"name": "a", "w": 2, "h": 2}]}]`,
+			expectedError: ErrInvalidRegion,
+		},
+		{
+			name:          "Invalid pages",
+			input:         `[{"document_type": "letter", "fields": [{"name": "a", "w": 1, "h": 1, "pages": "x"}]}]`,
+			expectedError: ErrInvalidRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := LoadRegionTemplates(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			for _, typ := range tt.expectedTypes {
+				if templates[typ] == nil || templates[typ].DocumentType != typ {
+					t.Errorf("expected template %q, got %+v", typ, templates)
+				}
+			}
+		})
+	}
+
+	if _, err := LoadRegionTemplates(strings.NewReader("{")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestConverter_ExtractFields(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, bboxOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tmpl := &RegionTemplate{DocumentType: "letter", Fields: []Region{
+		{Name: "greeting", X: 50, Y: 50, W: 100, H: 20},
+		{Name: "second", X: 0, Y: 70, W: 612, H: 14, Pages: "1"},
+		{Name: "signature", X: 0, Y: 700, W: 612, H: 92},
+	}}
+	fields, err := converter.ExtractFields(context.Background(), "input.pdf", tmpl, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"greeting":  "Hello world\nFish & chips",
+		"second":    "Second line",
+		"signature": "",
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+}
@@ -0,0 +1,137 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ConvertResult is the text of a conversion with its diagnostics, returned by
+// ConvertEx
+type ConvertResult struct {
+	// Text is the converted text, as Convert returns it
+	Text string `json:"text"`
+	// Pages are the converted pages in order
+	Pages []Page `json:"pages"`
+	// Warnings are the messages pdftotext printed while converting
+	Warnings []Warning `json:"warnings,omitempty"`
+	// Stats are the statistics of the output
+	Stats *Stats `json:"stats"`
+	// SkippedPages are the numbers of the pages Options.PageFallback left
+	// empty because they could not be converted
+	SkippedPages []int `json:"skipped_pages,omitempty"`
+	// TruncatedAt is the number of the page at which the text was cut off
+	// because it exceeded Options.MaxOutputBytes, or 0 if the text is
+	// complete. The page is the last of Pages and holds the text up to the
+	// limit.
+	TruncatedAt int `json:"truncated_at,omitempty"`
+	// InputSHA256 is the hex encoded SHA-256 of the input file, unless it
+	// could not be read
+	InputSHA256 string `json:"input_sha256,omitempty"`
+	// TextSHA256 is the hex encoded SHA-256 of Text, so downstream systems
+	// can verify it and detect drift when documents are converted again
+	TextSHA256 string `json:"text_sha256"`
+}
+
+// ConvertEx converts a PDF file page by page like StreamPages and returns the
+// text along with the pages, the warnings and the statistics of the output,
+// for callers that need more than the text. With Options.MaxOutputBytes the
+// conversion stops as soon as the text exceeds the limit. The PostHooks are
+// not called. With Options.AllowPartial the result holds the pages extracted
+// before a failure, returned with an error wrapping ErrPartial.
+func (c *Converter) ConvertEx(ctx context.Context, inputPath string, opts *Options) (*ConvertResult, error) {
+	start := time.Now()
+	collecting, warnings := collectWarnings(opts)
+	result := &ConvertResult{Pages: []Page{}}
+	var mu sync.Mutex
+	var skipped []int
+	onPageSkipped := collecting.OnPageSkipped
+	collecting.OnPageSkipped = func(page int, err error) {
+		mu.Lock()
+		skipped = append(skipped, page)
+		mu.Unlock()
+		if onPageSkipped != nil {
+			onPageSkipped(page, err)
+		}
+	}
+	size := 0
+	var partialErr error
+	var timings []PageTiming
+	last := start
+	for page, err := range c.StreamPages(ctx, inputPath, collecting) {
+		if err != nil {
+			if !collecting.AllowPartial || len(result.Pages) == 0 {
+				return nil, err
+			}
+			partialErr = fmt.Errorf("%w: %w", ErrPartial, err)
+			break
+		}
+		now := time.Now()
+		timings = append(timings, PageTiming{Page: page.Number, Duration: now.Sub(last)})
+		last = now
+		if limit := collecting.MaxOutputBytes; limit > 0 && int64(size+len(page.Text)) > limit {
+			page.Text = truncateUTF8(page.Text, int(limit)-size)
+			result.Pages = append(result.Pages, page)
+			result.TruncatedAt = page.Number
+			break
+		}
+		size += len(page.Text)
+		result.Pages = append(result.Pages, page)
+	}
+
+	output := make([]string, len(result.Pages))
+	for i, page := range result.Pages {
+		output[i] = page.Text + "\f"
+	}
+	result.Stats = ComputeStats(strings.Join(output, ""))
+	if !collecting.Deterministic {
+		result.Stats.Duration = time.Since(start)
+		result.Stats.PageTimings = timings
+		result.Stats.SlowestPages = slowestPages(timings, slowestPagesCount)
+	}
+	result.Text = joinPages(result.Pages, collecting)
+	result.TextSHA256 = sha256Hex([]byte(result.Text))
+	if input, err := hashFile(inputPath, SHA256); err == nil {
+		result.InputSHA256 = input.Digest
+	}
+	result.Warnings = warnings()
+	mu.Lock()
+	result.SkippedPages = skipped
+	mu.Unlock()
+	return result, partialErr
+}
+
+// joinPages joins the text of pages like a conversion with opts writes it:
+// separated by form feeds, opts.PageSeparator or nothing with
+// opts.NoPageBreaks, with the surrounding whitespace trimmed
+func joinPages(pages []Page, opts *Options) string {
+	var b strings.Builder
+	for i, page := range pages {
+		if i > 0 {
+			switch {
+			case opts.PageSeparator != "":
+				b.WriteString(strings.ReplaceAll(opts.PageSeparator, "{n}", strconv.Itoa(page.Number)))
+			case !opts.NoPageBreaks:
+				b.WriteByte('\f')
+			}
+		}
+		b.WriteString(page.Text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// truncateUTF8 returns the longest prefix of s of at most n bytes that does
+// not split a character
+func truncateUTF8(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:max(n, 0)]
+}
@@ -0,0 +1,134 @@
+package pdftotext
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestConverter_ConvertEx(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Syntax Warning: Invalid Font Weight\n")
+		_, err := io.WriteString(stdout, "one\f\ftwo ünd three\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		options           *Options
+		expectedText      string
+		expectedPages     int
+		expectedTruncated int
+	}{
+		{
+			name:          "Default",
+			expectedText:  "one\f\ftwo ünd three",
+			expectedPages: 3,
+		},
+		{
+			name:          "Page separator",
+			options:       &Options{PageSeparator: "\n--- {n} ---\n"},
+			expectedText:  "one\n--- 2 ---\n\n--- 3 ---\ntwo ünd three",
+			expectedPages: 3,
+		},
+		{
+			name:          "No page breaks",
+			options:       &Options{NoPageBreaks: true},
+			expectedText:  "onetwo ünd three",
+			expectedPages: 3,
+		},
+		{
+			name:              "Truncated",
+			options:           &Options{MaxOutputBytes: 7},
+			expectedText:      "one\f\ftwo",
+			expectedPages:     3,
+			expectedTruncated: 3,
+		},
+		{
+			name:              "Truncated within a character",
+			options:           &Options{MaxOutputBytes: 8},
+			expectedText:      "one\f\ftwo",
+			expectedPages:     3,
+			expectedTruncated: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.ConvertEx(context.Background(), "input.pdf", tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Text != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, result.Text)
+			}
+			if len(result.Pages) != tt.expectedPages || result.Stats.Pages != tt.expectedPages {
+				t.Errorf("expected %d pages, got %d with stats %d", tt.expectedPages, len(result.Pages), result.Stats.Pages)
+			}
+			if sum := sha256.Sum256([]byte(tt.expectedText)); result.TextSHA256 != hex.EncodeToString(sum[:]) {
+				t.Errorf("expected the SHA-256 of the text, got %s", result.TextSHA256)
+			}
+			if result.TruncatedAt != tt.expectedTruncated {
+				t.Errorf("expected truncation at %d, got %d", tt.expectedTruncated, result.TruncatedAt)
+			}
+			if len(result.Warnings) != 1 || result.Warnings[0].Message != "Invalid Font Weight" {
+				t.Errorf("expected the warning, got %+v", result.Warnings)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertEx_PageTimings(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		// page 3 takes much longer than the others
+		for i, delay := range []time.Duration{0, 0, 100 * time.Millisecond, 0} {
+			time.Sleep(delay)
+			if _, err := fmt.Fprintf(stdout, "page %d\f", i+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	result, err := converter.ConvertEx(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	timings := result.Stats.PageTimings
+	if len(timings) != 4 || timings[2].Page != 3 {
+		t.Fatalf("unexpected page timings %v", timings)
+	}
+	slowest := result.Stats.SlowestPages
+	if len(slowest) != 4 || slowest[0].Page != 3 || slowest[0].Duration < 100*time.Millisecond {
+		t.Errorf("expected page 3 to be the slowest, got %v", slowest)
+	}
+
+	result, err = converter.ConvertEx(context.Background(), "input.pdf", &Options{Deterministic: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Stats.PageTimings != nil || result.Stats.SlowestPages != nil {
+		t.Errorf("expected no timings in deterministic mode, got %v", result.Stats.PageTimings)
+	}
+}
+
+func TestSlowestPages(t *testing.T) {
+	timings := []PageTiming{{1, 3}, {2, 9}, {3, 1}, {4, 9}, {5, 5}}
+	expected := []PageTiming{{2, 9}, {4, 9}, {5, 5}}
+	if slowest := slowestPages(timings, 3); !slices.Equal(slowest, expected) {
+		t.Errorf("expected %v, got %v", expected, slowest)
+	}
+	if timings[0].Page != 1 {
+		t.Error("expected the timings to be left unsorted")
+	}
+}
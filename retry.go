@@ -0,0 +1,39 @@
+package pdftotext
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// WithRetry retries conversions that fail with a transient error up to n
+// times, waiting backoff before the first retry and doubling it after each one
+func WithRetry(n int, backoff time.Duration) ConverterOption {
+	return func(c *Converter) {
+		c.retries = n
+		c.retryBackoff = backoff
+	}
+}
+
+// WithRetryClassifier sets the function deciding which errors are transient
+// and worth retrying (default IsTransient)
+func WithRetryClassifier(fn func(error) bool) ConverterOption {
+	return func(c *Converter) {
+		c.retryable = fn
+	}
+}
+
+// IsTransient reports whether err is a failure that may succeed when retried,
+// i.e. the process was killed by a signal or a temporary I/O error occurred
+func IsTransient(err error) bool {
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == -1 {
+		return true
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return errno.Temporary() || isTextBusy(errno)
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package pdftotext
+
+import "syscall"
+
+// isTextBusy reports false, since only unix systems refuse to run a binary
+// that is being written
+func isTextBusy(errno syscall.Errno) bool {
+	return false
+}
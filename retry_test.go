@@ -0,0 +1,100 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestConverter_Retry(t *testing.T) {
+	// The fake is killed by a signal on its first two runs and succeeds afterwards.
+	binary := writeFakeBinary(t, `count=$(cat "$0.count" 2>/dev/null || echo 0)
+echo $((count + 1)) > "$0.count"
+if [ "$count" -lt 2 ]; then
+	echo "partial"
+	kill -9 $$
+fi
+echo "extracted text"
+`)
+
+	tests := []struct {
+		name          string
+		options       []ConverterOption
+		expectedError error
+		expectedText  string
+	}{
+		{
+			name:          "No retries",
+			expectedError: ErrCommandFailed,
+		},
+		{
+			name:          "Too few retries",
+			options:       []ConverterOption{WithRetry(1, time.Millisecond)},
+			expectedError: ErrCommandFailed,
+		},
+		{
+			name:         "Enough retries",
+			options:      []ConverterOption{WithRetry(2, time.Millisecond)},
+			expectedText: "extracted text",
+		},
+		{
+			name: "Classifier rejects error",
+			options: []ConverterOption{
+				WithRetry(2, time.Millisecond),
+				WithRetryClassifier(func(error) bool { return false }),
+			},
+			expectedError: ErrCommandFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Remove(binary + ".count"); err != nil && !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("failed to reset fake binary: %v", err)
+			}
+
+			converter, err := New(append([]ConverterOption{WithBinaryPath(binary)}, tt.options...)...)
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			text, err := converter.Convert(context.Background(), "input.pdf", nil)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, text)
+			}
+		})
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Nil error", err: nil, expected: false},
+		{name: "Open error", err: ErrPDFOpen, expected: false},
+		{name: "Interrupted system call", err: errors.Join(ErrCommandFailed, syscall.EINTR), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package pdftotext
+
+import "syscall"
+
+// isTextBusy reports whether errno is ETXTBSY, returned when the binary is
+// started while it is still being written, e.g. right after provisioning it
+func isTextBusy(errno syscall.Errno) bool {
+	return errno == syscall.ETXTBSY
+}
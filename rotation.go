@@ -0,0 +1,205 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RotatedCropMode sets how a crop area is applied to rotated pages.
+// pdftotext crops a page after rotating it for display, so an area measured
+// on the unrotated page, e.g. from its MediaBox, selects the wrong part of a
+// page rotated to landscape.
+type RotatedCropMode string
+
+const (
+	// RotatedCropIgnore applies the crop area to the displayed page, the
+	// behavior of pdftotext
+	RotatedCropIgnore RotatedCropMode = ""
+	// RotatedCropWarn applies the crop area like RotatedCropIgnore and
+	// reports each rotated page to OnWarning
+	RotatedCropWarn RotatedCropMode = "warn"
+	// RotatedCropAdjust takes the crop area in the coordinates of the
+	// unrotated page and maps it onto each rotated page, converting runs of
+	// pages with the same orientation with their own pdftotext process
+	RotatedCropAdjust RotatedCropMode = "adjust"
+)
+
+// PageOrientation is the size and rotation of a page as reported by pdfinfo
+type PageOrientation struct {
+	// Number is the page number
+	Number int `json:"number"`
+	// Width and Height are the size of the unrotated page in points
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	// Rotation is the clockwise rotation of the page for display in degrees,
+	// 0, 90, 180 or 270
+	Rotation int `json:"rotation"`
+}
+
+// Landscape reports whether the displayed page is wider than high
+func (p PageOrientation) Landscape() bool {
+	if p.Rotation%180 != 0 {
+		return p.Height > p.Width
+	}
+	return p.Width > p.Height
+}
+
+// DisplayRect maps r from the coordinates of the unrotated page to the
+// coordinates of the displayed page, which pdftotext crops and reports
+// bounding boxes in. Both have the origin at the top-left corner.
+func (p PageOrientation) DisplayRect(r Rect) Rect {
+	x1, y1 := p.displayPoint(r.XMin, r.YMin)
+	x2, y2 := p.displayPoint(r.XMax, r.YMax)
+	return Rect{XMin: min(x1, x2), YMin: min(y1, y2), XMax: max(x1, x2), YMax: max(y1, y2)}
+}
+
+// displayPoint maps a point of the unrotated page to the displayed page
+func (p PageOrientation) displayPoint(x, y float64) (float64, float64) {
+	switch p.Rotation {
+	case 90:
+		return p.Height - y, x
+	case 180:
+		return p.Width - x, p.Height - y
+	case 270:
+		return y, p.Width - x
+	}
+	return x, y
+}
+
+// PageOrientations returns the size and rotation of the pages of a PDF file
+// from FirstPage to LastPage of opts using pdfinfo, so callers can detect
+// landscape and rotated pages before cropping. Only the page and password
+// options of opts are used.
+func (c *Converter) PageOrientations(ctx context.Context, inputPath string, opts *Options) ([]PageOrientation, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return nil, err
+	}
+	// pdfinfo lowers the last page to the page count
+	first, last := 1, math.MaxInt32
+	if opts != nil {
+		first = max(opts.FirstPage, 1)
+		if opts.LastPage > 0 {
+			last = opts.LastPage
+		}
+	}
+	args := append([]string{"-f", strconv.Itoa(first), "-l", strconv.Itoa(last)}, passwordArgs(opts)...)
+	args = append(args, escapePath(inputPath))
+
+	var stdout bytes.Buffer
+	stderr := c.newStderr()
+	if err := c.runCommand(ctx, c.toolPath("pdfinfo"), args, &stdout, stderr); err != nil {
+		return nil, c.handleError(err, stderr.String())
+	}
+	return parsePageOrientations(stdout.String()), nil
+}
+
+// parsePageOrientations parses the "Page N size:" and "Page N rot:" lines
+// printed by pdfinfo for a page range
+func parsePageOrientations(output string) []PageOrientation {
+	var pages []PageOrientation
+	index := map[int]int{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] != "Page" {
+			continue
+		}
+		number, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		i, ok := index[number]
+		if !ok {
+			i = len(pages)
+			index[number] = i
+			pages = append(pages, PageOrientation{Number: number})
+		}
+		switch fields[2] {
+		case "size:":
+			if len(fields) >= 6 {
+				pages[i].Width, _ = strconv.ParseFloat(fields[3], 64)
+				pages[i].Height, _ = strconv.ParseFloat(fields[5], 64)
+			}
+		case "rot:":
+			rotation, _ := strconv.Atoi(fields[3])
+			pages[i].Rotation = ((rotation % 360) + 360) % 360
+		}
+	}
+	return pages
+}
+
+// hasCrop reports whether opts sets a crop area
+func hasCrop(opts *Options) bool {
+	return opts.CropX != 0 || opts.CropY != 0 || opts.CropWidth != 0 || opts.CropHeight != 0
+}
+
+// runRotatedCrop converts with the crop area of opts after checking the
+// rotation of the selected pages as opts.RotatedCrop sets
+func (c *Converter) runRotatedCrop(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	pages, err := c.PageOrientations(ctx, inputPath, opts)
+	if err != nil {
+		return err
+	}
+	plain := *opts
+	plain.RotatedCrop = RotatedCropIgnore
+
+	rotated := false
+	for _, page := range pages {
+		if page.Rotation == 0 {
+			continue
+		}
+		rotated = true
+		if opts.RotatedCrop == RotatedCropWarn && opts.OnWarning != nil {
+			opts.OnWarning(fmt.Sprintf("page %d is rotated by %d degrees, the crop area applies to the rotated page", page.Number, page.Rotation))
+		}
+	}
+	if !rotated || opts.RotatedCrop != RotatedCropAdjust {
+		return c.run(ctx, inputPath, outputPath, &plain, stdout)
+	}
+
+	return writeOutput(outputPath, stdout, func(w io.Writer) error {
+		// hide a *bytes.Buffer from the retry loop, like runPages
+		w = struct{ io.Writer }{w}
+		for i := 0; i < len(pages); {
+			j := i + 1
+			for j < len(pages) && pages[j].Rotation == pages[i].Rotation && pages[j].Width == pages[i].Width && pages[j].Height == pages[i].Height {
+				j++
+			}
+			o := plain
+			o.FirstPage, o.LastPage = pages[i].Number, pages[j-1].Number
+			setCrop(&o, pages[i])
+			if err := c.run(ctx, inputPath, "-", &o, w); err != nil {
+				return err
+			}
+			i = j
+		}
+		return nil
+	})
+}
+
+// setCrop maps the crop area of opts, in pixels at the resolution of opts,
+// from the unrotated page onto the displayed page
+func setCrop(opts *Options, page PageOrientation) {
+	scale := 1.0
+	if opts.Resolution > 0 {
+		scale = float64(opts.Resolution) / 72
+	}
+	page.Width, page.Height = page.Width*scale, page.Height*scale
+	r := Rect{XMin: float64(opts.CropX), YMin: float64(opts.CropY), XMax: float64(opts.CropX + opts.CropWidth), YMax: float64(opts.CropY + opts.CropHeight)}
+	// a zero width or height extends the area to the edge of the page
+	if opts.CropWidth == 0 {
+		r.XMax = page.Width
+	}
+	if opts.CropHeight == 0 {
+		r.YMax = page.Height
+	}
+	r = page.DisplayRect(r)
+	opts.CropX, opts.CropY = int(math.Round(r.XMin)), int(math.Round(r.YMin))
+	opts.CropWidth, opts.CropHeight = int(math.Round(r.XMax-r.XMin)), int(math.Round(r.YMax-r.YMin))
+}
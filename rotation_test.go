@@ -0,0 +1,130 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const pdfinfoPages = `Pages:          4
+Page    1 size: 612 x 792 pts (letter)
+Page    1 rot:  0
+Page    2 size: 612 x 792 pts (letter)
+Page    2 rot:  90
+Page    3 size: 612 x 792 pts (letter)
+Page    3 rot:  90
+Page    4 size: 612 x 792 pts (letter)
+Page    4 rot:  -90
+`
+
+func TestParsePageOrientations(t *testing.T) {
+	expected := []PageOrientation{
+		{Number: 1, Width: 612, Height: 792},
+		{Number: 2, Width: 612, Height: 792, Rotation: 90},
+		{Number: 3, Width: 612, Height: 792, Rotation: 90},
+		{Number: 4, Width: 612, Height: 792, Rotation: 270},
+	}
+	pages := parsePageOrientations(pdfinfoPages)
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("expected %+v, got %+v", expected, pages)
+	}
+	if pages[0].Landscape() || !pages[1].Landscape() {
+		t.Errorf("expected page 2 in landscape only")
+	}
+}
+
+func TestPageOrientation_DisplayRect(t *testing.T) {
+	r := Rect{XMin: 10, YMin: 20, XMax: 110, YMax: 70}
+	tests := []struct {
+		rotation int
+		expected Rect
+	}{
+		{0, r},
+		{90, Rect{XMin: 722, YMin: 10, XMax: 772, YMax: 110}},
+		{180, Rect{XMin: 502, YMin: 722, XMax: 602, YMax: 772}},
+		{270, Rect{XMin: 20, YMin: 502, XMax: 70, YMax: 602}},
+	}
+	for _, tt := range tests {
+		page := PageOrientation{Width: 612, Height: 792, Rotation: tt.rotation}
+		if got := page.DisplayRect(r); got != tt.expected {
+			t.Errorf("rotation %d: expected %+v, got %+v", tt.rotation, tt.expected, got)
+		}
+	}
+}
+
+func TestConverter_RotatedCrop(t *testing.T) {
+	var runs [][]string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if name == "pdfinfo" {
+			_, err := io.WriteString(stdout, pdfinfoPages)
+			return err
+		}
+		runs = append(runs, args)
+		_, err := io.WriteString(stdout, "text\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	crop := func(args []string) []string {
+		var flags []string
+		for _, flag := range []string{"-f", "-l", "-x", "-y", "-W", "-H"} {
+			if i := slices.Index(args, flag); i >= 0 {
+				flags = append(flags, flag, args[i+1])
+			}
+		}
+		return flags
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		runs = nil
+		var warnings []string
+		opts := &Options{CropX: 10, CropY: 20, CropWidth: 100, CropHeight: 50, RotatedCrop: RotatedCropWarn, OnWarning: func(message string) {
+			warnings = append(warnings, message)
+		}}
+		if _, err := converter.Convert(context.Background(), "input.pdf", opts); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		if len(runs) != 1 || len(warnings) != 3 || !strings.HasPrefix(warnings[0], "page 2 is rotated by 90 degrees") {
+			t.Errorf("expected a run and 3 warnings, got %v and %q", runs, warnings)
+		}
+	})
+
+	t.Run("adjust", func(t *testing.T) {
+		runs = nil
+		opts := &Options{CropX: 10, CropY: 20, CropWidth: 100, CropHeight: 50, RotatedCrop: RotatedCropAdjust}
+		var buf bytes.Buffer
+		if err := converter.ConvertTo(context.Background(), "input.pdf", &buf, opts); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		expected := [][]string{
+			{"-f", "1", "-l", "1", "-x", "10", "-y", "20", "-W", "100", "-H", "50"},
+			{"-f", "2", "-l", "3", "-x", "722", "-y", "10", "-W", "50", "-H", "100"},
+			{"-f", "4", "-l", "4", "-x", "20", "-y", "502", "-W", "50", "-H", "100"},
+		}
+		var got [][]string
+		for _, args := range runs {
+			got = append(got, crop(args))
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+		if buf.String() != "text\ftext\ftext\f" {
+			t.Errorf("expected the text of each run, got %q", buf.String())
+		}
+	})
+
+	t.Run("no crop", func(t *testing.T) {
+		runs = nil
+		if _, err := converter.Convert(context.Background(), "input.pdf", &Options{RotatedCrop: RotatedCropAdjust}); err != nil {
+			t.Fatalf("failed to convert: %v", err)
+		}
+		if len(runs) != 1 || slices.Contains(runs[0], "-x") {
+			t.Errorf("expected a single run without a crop area, got %v", runs)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// Runner executes external commands on behalf of a Converter. A custom Runner
+// lets tests simulate exit codes, stderr contents, timeouts and huge outputs
+// without a real pdftotext binary.
+type Runner interface {
+	// Run runs the command name with args, writing its output to stdout and
+	// stderr. A non-zero exit must be reported with an error that has an
+	// ExitCode() int method, such as *exec.ExitError or *ExitError, where -1
+	// means the process was killed by a signal.
+	Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error
+}
+
+// WithRunner sets the Runner used to execute pdftotext. The binary is not
+// looked up in PATH when a custom runner is used.
+func WithRunner(r Runner) ConverterOption {
+	return func(c *Converter) {
+		c.runner = r
+	}
+}
+
+// streamBufferSize is the size of the buffer used to stream stdout
+const streamBufferSize = 32 * 1024
+
+// Priority is the CPU and I/O scheduling priority of the child processes
+type Priority int
+
+const (
+	// PriorityNormal runs child processes with the priority of the caller
+	PriorityNormal Priority = iota
+	// PriorityLow runs child processes with a lower CPU and I/O priority, so
+	// background bulk extraction does not slow down the latency-sensitive
+	// services sharing the host: nice 10 and the lowest best-effort I/O
+	// priority on Linux, the below normal priority class on Windows
+	PriorityLow
+	// PriorityIdle runs child processes only when the host is otherwise idle:
+	// nice 19 and the idle I/O class on Linux, the idle priority class on
+	// Windows
+	PriorityIdle
+)
+
+// ExecRunner is the default Runner, executing commands with os/exec
+type ExecRunner struct {
+	// Priority is the priority of the child processes. I/O priorities are
+	// only set on Linux. On other Unix systems the priority is set just
+	// after the process started.
+	Priority Priority
+}
+
+// Run runs the command as a child process, streaming its stdout to stdout
+// through a fixed size buffer
+func (r ExecRunner) Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = stderr
+	if env, ok := ctx.Value(envKey{}).([]string); ok {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	r.Priority.prepare(cmd)
+	if stdout == nil {
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		r.Priority.apply(cmd.Process.Pid)
+		return cmd.Wait()
+	}
+
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	r.Priority.apply(cmd.Process.Pid)
+
+	_, copyErr := io.CopyBuffer(stdout, pipe, make([]byte, streamBufferSize))
+	if copyErr != nil {
+		// the process would block on the full pipe forever
+		cmd.Process.Kill()
+	}
+	waitErr := cmd.Wait()
+	if copyErr != nil {
+		return copyErr
+	}
+	return waitErr
+}
+
+// envKey is the context key of the environment variables ExecRunner adds to
+// the environment of the commands
+type envKey struct{}
+
+// deterministicEnv fixes the locale and the time zone of the commands
+var deterministicEnv = []string{"LC_ALL=C", "TZ=UTC"}
+
+// withCommandEnv returns ctx making ExecRunner add env to the environment of
+// the commands it runs. Custom runners do not see it.
+func withCommandEnv(ctx context.Context, env ...string) context.Context {
+	return context.WithValue(ctx, envKey{}, env)
+}
+
+// ExitError reports the exit code of a command run by a custom Runner
+type ExitError struct {
+	// Code is the exit code, or -1 if the process was killed by a signal
+	Code int
+}
+
+// Error returns the exit status
+func (e *ExitError) Error() string {
+	if e.Code == -1 {
+		return "signal: killed"
+	}
+	return "exit status " + strconv.Itoa(e.Code)
+}
+
+// ExitCode returns the exit code
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// exitCoder is implemented by errors reporting a process exit code
+type exitCoder interface {
+	error
+	ExitCode() int
+}
@@ -0,0 +1,137 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runnerFunc is a Runner backed by a function; a nil runnerFunc succeeds without output
+type runnerFunc func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error
+
+func (f runnerFunc) Run(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+	if f == nil {
+		return nil
+	}
+	return f(ctx, name, args, stdout, stderr)
+}
+
+// platformArgs returns the arguments expected for args on the platform the
+// tests run on, which on Windows start with the default -eol unix
+func platformArgs(args ...string) []string {
+	if defaultUnixEOL {
+		return append([]string{"-eol", string(EOLUnix)}, args...)
+	}
+	return args
+}
+
+// fakeRun returns a Runner writing output to stdout and stderrText to stderr
+// and exiting with code
+func fakeRun(output, stderrText string, code int) Runner {
+	return runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if stdout != nil {
+			io.WriteString(stdout, output)
+		}
+		io.WriteString(stderr, stderrText)
+		if code != 0 {
+			return &ExitError{Code: code}
+		}
+		return nil
+	})
+}
+
+func TestConverter_Runner(t *testing.T) {
+	tests := []struct {
+		name          string
+		runner        Runner
+		timeout       time.Duration
+		expectedError error
+		expectedText  string
+	}{
+		{
+			name:         "Success",
+			runner:       fakeRun("extracted text\f", "", 0),
+			expectedText: "extracted text",
+		},
+		{
+			name:          "Open error",
+			runner:        fakeRun("", "I/O Error: Couldn't open file", 1),
+			expectedError: ErrPDFOpen,
+		},
+		{
+			name:          "Incorrect password",
+			runner:        fakeRun("", "Command Line Error: Incorrect password", 1),
+			expectedError: ErrEncrypted,
+		},
+		{
+			name:          "Output error",
+			runner:        fakeRun("", "I/O Error: Couldn't open text file", 2),
+			expectedError: ErrOutputFile,
+		},
+		{
+			name:          "Permission error",
+			runner:        fakeRun("", "Copying of text from this document is not allowed.", 3),
+			expectedError: ErrPermissions,
+		},
+		{
+			name:          "Other exit code",
+			runner:        fakeRun("", "", 99),
+			expectedError: ErrCommandFailed,
+		},
+		{
+			name: "Timeout",
+			runner: runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+			timeout:       10 * time.Millisecond,
+			expectedError: context.DeadlineExceeded,
+		},
+		{
+			name:         "Huge output",
+			runner:       fakeRun(strings.Repeat("word ", 1<<20), "", 0),
+			expectedText: strings.TrimSpace(strings.Repeat("word ", 1<<20)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(tt.runner))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			text, err := converter.Convert(ctx, "input.pdf", nil)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expectedText {
+				t.Errorf("expected %d bytes of text, got %d", len(tt.expectedText), len(text))
+			}
+		})
+	}
+}
+
+func TestNew_BinaryNotFound(t *testing.T) {
+	_, err := New(WithBinaryPath("/nonexistent/pdftotext"))
+	if !errors.Is(err, ErrBinaryNotFound) {
+		t.Errorf("expected error %v, got %v", ErrBinaryNotFound, err)
+	}
+}
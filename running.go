@@ -0,0 +1,119 @@
+package pdftotext
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// RunningLine is a line repeating at the same position across the pages of a
+// document, such as a running header, a footer or a confidentiality banner
+type RunningLine struct {
+	// Pattern is the text of the line with its whitespace collapsed and
+	// digits replaced with "#", so page numbers match across pages
+	Pattern string `json:"pattern"`
+	// Box is the position of the line on the first page it appears on
+	Box Rect `json:"box"`
+	// Pages are the pages the line appears on
+	Pages []int `json:"pages"`
+}
+
+// DetectRunningLines converts a PDF file with -bbox-layout and returns the
+// lines repeating at the same position on at least half of its pages, see
+// FindRunningLines. Remove them from the text with a RunningLineRemover.
+func (c *Converter) DetectRunningLines(ctx context.Context, inputPath string, opts *Options) ([]RunningLine, error) {
+	pages, err := c.geometry(ctx, inputPath, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return FindRunningLines(pages, 0.5), nil
+}
+
+// FindRunningLines returns the lines of pages, as produced by -bbox-layout,
+// repeating at the same vertical position on at least the fraction
+// minFraction of the pages, and on at least two pages. Lines match if their
+// patterns are equal and their centers lie within 1% of the page height.
+func FindRunningLines(pages []PageGeometry, minFraction float64) []RunningLine {
+	minPages := max(int(math.Ceil(minFraction*float64(len(pages)))), 2)
+
+	var candidates []RunningLine
+	var centers []float64
+	byPattern := map[string][]int{}
+	for _, page := range pages {
+		tolerance := max(page.Height/100, 2)
+		for _, line := range page.Lines {
+			pattern := runningPattern(line.Text)
+			if pattern == "" {
+				continue
+			}
+			_, y := line.Box.Center()
+			k := slices.IndexFunc(byPattern[pattern], func(i int) bool {
+				return math.Abs(centers[i]-y) <= tolerance
+			})
+			i := len(candidates)
+			if k >= 0 {
+				i = byPattern[pattern][k]
+			} else {
+				candidates = append(candidates, RunningLine{Pattern: pattern, Box: line.Box})
+				centers = append(centers, y)
+				byPattern[pattern] = append(byPattern[pattern], i)
+			}
+			if pages := candidates[i].Pages; len(pages) == 0 || pages[len(pages)-1] != page.Number {
+				candidates[i].Pages = append(pages, page.Number)
+			}
+		}
+	}
+
+	var lines []RunningLine
+	for _, r := range candidates {
+		if len(r.Pages) >= minPages {
+			lines = append(lines, r)
+		}
+	}
+	return lines
+}
+
+// runningPattern returns the pattern of a line of text, with its whitespace
+// collapsed and digits replaced with "#"
+func runningPattern(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return '#'
+		}
+		return r
+	}, strings.Join(strings.Fields(text), " "))
+}
+
+// RunningLineRemover is a Transformer removing running lines, such as those
+// found by DetectRunningLines, from the pages they appear on. Each running
+// line removes the first line of the page text matching its pattern.
+type RunningLineRemover struct {
+	// Lines are the running lines to remove
+	Lines []RunningLine
+}
+
+// Transform removes the running lines of the page from text
+func (r RunningLineRemover) Transform(page int, text string) (string, error) {
+	remove := map[string]int{}
+	for _, line := range r.Lines {
+		if slices.Contains(line.Pages, page) {
+			remove[line.Pattern]++
+		}
+	}
+	if len(remove) == 0 {
+		return text, nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if pattern := runningPattern(line); remove[pattern] > 0 {
+			remove[pattern]--
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, ""), nil
+}
@@ -0,0 +1,184 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// layoutOutput returns -bbox-layout output with the given lines on each
+// page, in a single block
+func layoutOutput(pages [][]Line) string {
+	var blocks [][]Block
+	for _, lines := range pages {
+		blocks = append(blocks, []Block{{Lines: lines}})
+	}
+	return blockLayoutOutput(blocks)
+}
+
+// blockLayoutOutput returns -bbox-layout output with the given blocks on
+// each page. The words of a line share its box.
+func blockLayoutOutput(pages [][]Block) string {
+	var b strings.Builder
+	b.WriteString("<html><body><doc>\n")
+	for _, blocks := range pages {
+		b.WriteString(`<page width="612" height="792"><flow>` + "\n")
+		for _, block := range blocks {
+			box := block.Box
+			fmt.Fprintf(&b, `<block xMin="%g" yMin="%g" xMax="%g" yMax="%g">`+"\n", box.XMin, box.YMin, box.XMax, box.YMax)
+			for _, line := range block.Lines {
+				box := line.Box
+				fmt.Fprintf(&b, `<line xMin="%g" yMin="%g" xMax="%g" yMax="%g">`, box.XMin, box.YMin, box.XMax, box.YMax)
+				for _, word := range strings.Fields(line.Text) {
+					fmt.Fprintf(&b, `<word xMin="%g" yMin="%g" xMax="%g" yMax="%g">%s</word>`, box.XMin, box.YMin, box.XMax, box.YMax, word)
+				}
+				b.WriteString("</line>\n")
+			}
+			b.WriteString("</block>\n")
+		}
+		b.WriteString("</flow></page>\n")
+	}
+	b.WriteString("</doc></body></html>\n")
+	return b.String()
+}
+
+// bodies are the body text of the pages of runningPages
+var bodies = []string{"Alpha section", "Beta section", "Gamma section", "Delta section"}
+
+// runningPages returns four pages with a header, body text and a page number
+// footer, whose position shifts slightly on the last page
+func runningPages() [][]Line {
+	var pages [][]Line
+	for i, body := range bodies {
+		footerY := 760.0
+		if i == 3 {
+			footerY = 762
+		}
+		pages = append(pages, []Line{
+			{Text: "ACME  Corp Confidential", Box: Rect{XMin: 50, YMin: 20, XMax: 200, YMax: 32}},
+			{Text: body, Box: Rect{XMin: 50, YMin: 100, XMax: 300, YMax: 112}},
+			{Text: fmt.Sprintf("Page %d of 4", i+1), Box: Rect{XMin: 280, YMin: footerY, XMax: 330, YMax: footerY + 12}},
+		})
+	}
+	// the header text in the body of a page is not a running line
+	pages[1] = append(pages[1], Line{Text: "ACME Corp Confidential", Box: Rect{XMin: 50, YMin: 400, XMax: 200, YMax: 412}})
+	return pages
+}
+
+func TestParseBBoxLayout(t *testing.T) {
+	pages, err := parseBBox(strings.NewReader(layoutOutput(runningPages())), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pages) != 4 || len(pages[1].Lines) != 4 || len(pages[1].Words) != 12 {
+		t.Fatalf("unexpected pages %+v", pages)
+	}
+	expected := Line{Text: "Page 2 of 4", Box: Rect{XMin: 280, YMin: 760, XMax: 330, YMax: 772}}
+	if pages[1].Lines[2] != expected {
+		t.Errorf("expected line %+v, got %+v", expected, pages[1].Lines[2])
+	}
+}
+
+func TestFindRunningLines(t *testing.T) {
+	pages, err := parseBBox(strings.NewReader(layoutOutput(runningPages())), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		pages       []PageGeometry
+		minFraction float64
+		expected    []string
+	}{
+		{
+			name:        "header and footer",
+			pages:       pages,
+			minFraction: 0.5,
+			expected:    []string{"ACME Corp Confidential", "Page # of #"},
+		},
+		{
+			name:        "all pages",
+			pages:       pages,
+			minFraction: 1,
+			expected:    []string{"ACME Corp Confidential", "Page # of #"},
+		},
+		{
+			name:        "single page",
+			pages:       pages[:1],
+			minFraction: 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patterns []string
+			for _, line := range FindRunningLines(tt.pages, tt.minFraction) {
+				patterns = append(patterns, line.Pattern)
+				if len(tt.pages) == 4 && !slices.Equal(line.Pages, []int{1, 2, 3, 4}) {
+					t.Errorf("expected %q on all pages, got %v", line.Pattern, line.Pages)
+				}
+			}
+			if !slices.Equal(patterns, tt.expected) {
+				t.Errorf("expected running lines %q, got %q", tt.expected, patterns)
+			}
+		})
+	}
+}
+
+func TestConverter_DetectRunningLines(t *testing.T) {
+	layout := layoutOutput(runningPages())
+	runner := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if slices.Contains(args, "-bbox-layout") {
+			_, err := io.WriteString(stdout, layout)
+			return err
+		}
+		var text strings.Builder
+		for i, body := range bodies {
+			fmt.Fprintf(&text, "ACME Corp Confidential\n%s\n", body)
+			if i == 1 {
+				text.WriteString("ACME Corp Confidential\n")
+			}
+			fmt.Fprintf(&text, "Page %d of 4\n\f", i+1)
+		}
+		_, err := io.WriteString(stdout, text.String())
+		return err
+	})
+	converter, err := New(WithRunner(runner))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+
+	lines, err := converter.DetectRunningLines(ctx, "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 running lines, got %+v", lines)
+	}
+
+	pages, err := converter.ConvertPages(ctx, "input.pdf", &Options{
+		Transformers: []Transformer{RunningLineRemover{Lines: lines}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var texts []string
+	for _, page := range pages {
+		texts = append(texts, page.Text)
+	}
+	expected := []string{
+		"Alpha section\n",
+		"Beta section\nACME Corp Confidential\n",
+		"Gamma section\n",
+		"Delta section\n",
+	}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected pages %q, got %q", expected, texts)
+	}
+}
@@ -0,0 +1,348 @@
+package pdftotext
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchOptions configures Search
+type SearchOptions struct {
+	// Options are the options used to convert the PDF file
+	Options *Options
+	// CaseSensitive matches the case of the query. By default case is
+	// ignored.
+	CaseSensitive bool
+	// Stem matches English inflections of the query words, e.g. "invoices"
+	// and "invoiced" for "invoice", by reducing words to their stems. Stemmed
+	// words are always compared ignoring case.
+	Stem bool
+	// SnippetContext is the number of characters of context on each side of
+	// a match in its snippet (default 40). A negative value leaves out
+	// snippets.
+	SnippetContext int
+}
+
+// PageHit is a page containing a search query
+type PageHit struct {
+	// Page is the page number
+	Page int `json:"page"`
+	// Label is the page label, when requested with Options.PageLabels
+	Label string `json:"label,omitempty"`
+	// Matches are the occurrences of the query in the page, in order
+	Matches []SearchMatch `json:"matches"`
+}
+
+// SearchMatch is an occurrence of a search query in a page
+type SearchMatch struct {
+	// Start and End are the byte offsets of the match in the page text
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Text is the matched text
+	Text string `json:"text"`
+	// Snippet is the match with its surrounding text, whitespace collapsed
+	// and an ellipsis where the page text was cut
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// defaultSnippetContext is the default SearchOptions.SnippetContext
+const defaultSnippetContext = 40
+
+// Search converts a PDF file page by page and returns the pages containing
+// query, for simple "which pages mention X" lookups without a search index.
+// The query matches whole words: a phrase of several words matches the same
+// words in order, separated by any whitespace or punctuation, so a phrase
+// may span lines.
+func (c *Converter) Search(ctx context.Context, inputPath, query string, opts *SearchOptions) ([]PageHit, error) {
+	searchOpts := SearchOptions{}
+	if opts != nil {
+		searchOpts = *opts
+	}
+	hits := []PageHit{}
+	for page, err := range c.StreamPages(ctx, inputPath, searchOpts.Options) {
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, SearchPages([]Page{page}, query, searchOpts)...)
+	}
+	return hits, nil
+}
+
+// SearchPages returns the pages containing query, see Converter.Search.
+// opts.Options is ignored.
+func SearchPages(pages []Page, query string, opts SearchOptions) []PageHit {
+	if opts.SnippetContext == 0 {
+		opts.SnippetContext = defaultSnippetContext
+	}
+	terms := searchTokens(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	for i, term := range terms {
+		terms[i].text = opts.normalize(term.text)
+	}
+
+	var hits []PageHit
+	for _, page := range pages {
+		words := searchTokens(page.Text)
+		for i := range words {
+			words[i].text = opts.normalize(words[i].text)
+		}
+
+		var matches []SearchMatch
+		for i := 0; i+len(terms) <= len(words); i++ {
+			if !matchTerms(words[i:i+len(terms)], terms) {
+				continue
+			}
+			start, end := words[i].start, words[i+len(terms)-1].end
+			match := SearchMatch{Start: start, End: end, Text: page.Text[start:end]}
+			if opts.SnippetContext > 0 {
+				match.Snippet = snippet(page.Text, start, end, opts.SnippetContext)
+			}
+			matches = append(matches, match)
+			i += len(terms) - 1
+		}
+		if len(matches) > 0 {
+			hits = append(hits, PageHit{Page: page.Number, Label: page.Label, Matches: matches})
+		}
+	}
+	return hits
+}
+
+// searchToken is a word of a text at its byte offsets
+type searchToken struct {
+	text       string
+	start, end int
+}
+
+// searchTokens returns the runs of letters, digits and marks of text
+func searchTokens(text string) []searchToken {
+	var tokens []searchToken
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			tokens = append(tokens, searchToken{text[start:i], start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, searchToken{text[start:], start, len(text)})
+	}
+	return tokens
+}
+
+// matchTerms reports whether the normalized words equal the terms
+func matchTerms(words, terms []searchToken) bool {
+	for i, term := range terms {
+		if words[i].text != term.text {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize folds the case of word and reduces it to its stem, as configured
+func (o SearchOptions) normalize(word string) string {
+	if !o.CaseSensitive || o.Stem {
+		word = strings.ToLower(word)
+	}
+	if o.Stem {
+		word = stem(word)
+	}
+	return word
+}
+
+// stemSuffixes are the inflectional suffixes removed by stem, in the order
+// they are tried, with their replacements. Suffixes replaced by themselves
+// protect words such as "class" and "status" from losing their final "s".
+var stemSuffixes = []struct{ suffix, replacement string }{
+	{"sses", "ss"}, {"ies", "y"}, {"ches", "ch"}, {"shes", "sh"}, {"xes", "x"}, {"zes", "z"},
+	{"ss", "ss"}, {"us", "us"}, {"is", "is"}, {"s", ""},
+	{"ingly", ""}, {"edly", ""}, {"ing", ""}, {"ed", ""}, {"ly", ""},
+}
+
+// stem reduces a lower-case English word to a stem shared by its
+// inflections, with a few suffix stripping rules. The stem need not be a
+// word: "invoice", "invoices" and "invoiced" all become "invoic".
+func stem(word string) string {
+	for _, s := range stemSuffixes {
+		base, ok := strings.CutSuffix(word, s.suffix)
+		if !ok {
+			continue
+		}
+		if s.replacement == s.suffix || !hasSyllable(base+s.replacement) {
+			break
+		}
+		word = base + s.replacement
+		// "running" and "stopped" double their final consonant
+		if s.replacement == "" && (s.suffix == "ing" || s.suffix == "ed") {
+			if n := len(word); n >= 2 && word[n-1] == word[n-2] && !strings.ContainsRune("aeiouylsz", rune(word[n-1])) {
+				word = word[:n-1]
+			}
+		}
+		break
+	}
+	if len(word) > 3 {
+		word = strings.TrimSuffix(word, "e")
+	}
+	return word
+}
+
+// hasSyllable reports whether word contains a vowel followed by a consonant,
+// so removing a suffix from it leaves a plausible stem: "speed" does not
+// become "spe"
+func hasSyllable(word string) bool {
+	vowel := false
+	for _, r := range word {
+		if strings.ContainsRune("aeiouy", r) {
+			vowel = true
+		} else if vowel {
+			return true
+		}
+	}
+	return false
+}
+
+// snippet returns text[start:end] with up to width characters of the text on
+// each side, cut at word boundaries
+func snippet(text string, start, end, width int) string {
+	from, to := snippetBounds(text, start, end, width)
+	prefix, suffix := ellipses(text, from, to)
+	return prefix + strings.Join(strings.Fields(text[from:to]), " ") + suffix
+}
+
+// snippetBounds returns the byte offsets of text[start:end] extended by up to
+// width characters on each side, leaving out words cut in half
+func snippetBounds(text string, start, end, width int) (from, to int) {
+	from = start
+	for n := 0; n < width && from > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	if strings.TrimSpace(text[:from]) != "" {
+		if r, _ := utf8.DecodeLastRuneInString(text[:from]); !unicode.IsSpace(r) {
+			if i := strings.IndexFunc(text[from:start], unicode.IsSpace); i >= 0 {
+				from += i
+			}
+		}
+	}
+
+	to = end
+	for n := 0; n < width && to < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+	if strings.TrimSpace(text[to:]) != "" {
+		if r, _ := utf8.DecodeRuneInString(text[to:]); !unicode.IsSpace(r) {
+			if i := strings.LastIndexFunc(text[end:to], unicode.IsSpace); i >= 0 {
+				to = end + i
+			}
+		}
+	}
+	return from, to
+}
+
+// ellipses returns the ellipses marking where text[from:to] cuts text
+func ellipses(text string, from, to int) (prefix, suffix string) {
+	if strings.TrimSpace(text[:from]) != "" {
+		prefix = "…"
+	}
+	if strings.TrimSpace(text[to:]) != "" {
+		suffix = "…"
+	}
+	return prefix, suffix
+}
+
+// RegexpMatch is a match of a regular expression in a page
+type RegexpMatch struct {
+	// Page is the page number
+	Page int `json:"page"`
+	// Label is the page label, when requested with Options.PageLabels
+	Label string `json:"label,omitempty"`
+	// Start and End are the byte offsets of the match in the page text
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Text is the matched text
+	Text string `json:"text"`
+	// Groups are the texts of the capture groups, in the order of their
+	// opening parentheses. A group that did not participate is empty.
+	Groups []string `json:"groups,omitempty"`
+	// Names are the names of the capture groups, empty for unnamed ones
+	Names []string `json:"names,omitempty"`
+	// Snippet is the match with its surrounding text, see SearchMatch
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Group returns the text of the capture group with the given name, or an
+// empty string if there is no such group
+func (m RegexpMatch) Group(name string) string {
+	for i, n := range m.Names {
+		if n == name && n != "" {
+			return m.Groups[i]
+		}
+	}
+	return ""
+}
+
+// SearchRegexp converts a PDF file page by page and returns every match of
+// re in the page texts with its capture groups, e.g. to pull all invoice
+// numbers with the pages they appear on. Only the Options and
+// SnippetContext of opts apply.
+func (c *Converter) SearchRegexp(ctx context.Context, inputPath string, re *regexp.Regexp, opts *SearchOptions) ([]RegexpMatch, error) {
+	searchOpts := SearchOptions{}
+	if opts != nil {
+		searchOpts = *opts
+	}
+	matches := []RegexpMatch{}
+	for page, err := range c.StreamPages(ctx, inputPath, searchOpts.Options) {
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, SearchPagesRegexp([]Page{page}, re, searchOpts)...)
+	}
+	return matches, nil
+}
+
+// SearchPagesRegexp returns every match of re in the pages, see
+// Converter.SearchRegexp. opts.Options is ignored.
+func SearchPagesRegexp(pages []Page, re *regexp.Regexp, opts SearchOptions) []RegexpMatch {
+	if opts.SnippetContext == 0 {
+		opts.SnippetContext = defaultSnippetContext
+	}
+	names := re.SubexpNames()[1:]
+	var matches []RegexpMatch
+	for _, page := range pages {
+		for _, loc := range re.FindAllStringSubmatchIndex(page.Text, -1) {
+			match := RegexpMatch{
+				Page:  page.Number,
+				Label: page.Label,
+				Start: loc[0],
+				End:   loc[1],
+				Text:  page.Text[loc[0]:loc[1]],
+			}
+			if len(names) > 0 {
+				match.Groups = make([]string, len(names))
+				for i := range names {
+					if start := loc[2*i+2]; start >= 0 {
+						match.Groups[i] = page.Text[start:loc[2*i+3]]
+					}
+				}
+				if slices.ContainsFunc(names, func(name string) bool { return name != "" }) {
+					match.Names = names
+				}
+			}
+			if opts.SnippetContext > 0 {
+				match.Snippet = snippet(page.Text, loc[0], loc[1], opts.SnippetContext)
+			}
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,208 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSearchPages(t *testing.T) {
+	pages := []Page{
+		{Number: 1, Label: "i", Text: "Invoice INV-1\nThe invoice total is due."},
+		{Number: 2, Text: "No match here."},
+		{Number: 3, Text: "Invoices were invoiced\nin March. Pay the\ninvoice  total."},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		opts     SearchOptions
+		expected []PageHit
+	}{
+		{
+			name:  "Case folding",
+			query: "invoice",
+			opts:  SearchOptions{SnippetContext: -1},
+			expected: []PageHit{
+				{Page: 1, Label: "i", Matches: []SearchMatch{{Start: 0, End: 7, Text: "Invoice"}, {Start: 18, End: 25, Text: "invoice"}}},
+				{Page: 3, Matches: []SearchMatch{{Start: 41, End: 48, Text: "invoice"}}},
+			},
+		},
+		{
+			name:  "Case sensitive",
+			query: "Invoice",
+			opts:  SearchOptions{CaseSensitive: true, SnippetContext: -1},
+			expected: []PageHit{
+				{Page: 1, Label: "i", Matches: []SearchMatch{{Start: 0, End: 7, Text: "Invoice"}}},
+			},
+		},
+		{
+			name:  "Stemming",
+			query: "invoice",
+			opts:  SearchOptions{Stem: true, SnippetContext: -1},
+			expected: []PageHit{
+				{Page: 1, Label: "i", Matches: []SearchMatch{{Start: 0, End: 7, Text: "Invoice"}, {Start: 18, End: 25, Text: "invoice"}}},
+				{Page: 3, Matches: []SearchMatch{{Start: 0, End: 8, Text: "Invoices"}, {Start: 14, End: 22, Text: "invoiced"}, {Start: 41, End: 48, Text: "invoice"}}},
+			},
+		},
+		{
+			name:  "Phrase across lines",
+			query: "the invoice total",
+			opts:  SearchOptions{SnippetContext: 10},
+			expected: []PageHit{
+				{Page: 1, Label: "i", Matches: []SearchMatch{{Start: 14, End: 31, Text: "The invoice total", Snippet: "…INV-1 The invoice total is due."}}},
+				{Page: 3, Matches: []SearchMatch{{Start: 37, End: 55, Text: "the\ninvoice  total", Snippet: "…Pay the invoice total."}}},
+			},
+		},
+		{
+			name:  "Punctuation in query",
+			query: "inv 1",
+			opts:  SearchOptions{},
+			expected: []PageHit{
+				{Page: 1, Label: "i", Matches: []SearchMatch{{Start: 8, End: 13, Text: "INV-1", Snippet: "Invoice INV-1 The invoice total is due."}}},
+			},
+		},
+		{
+			name:  "Empty query",
+			query: " - ",
+			opts:  SearchOptions{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchPages(pages, tt.query, tt.opts); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"invoice":  "invoic",
+		"invoices": "invoic",
+		"invoiced": "invoic",
+		"taxes":    "tax",
+		"taxed":    "tax",
+		"running":  "run",
+		"stopped":  "stop",
+		"policies": "policy",
+		"class":    "class",
+		"status":   "status",
+		"speed":    "speed",
+		"is":       "is",
+	}
+	for word, expected := range tests {
+		if got := stem(word); got != expected {
+			t.Errorf("stem(%q): expected %q, got %q", word, expected, got)
+		}
+	}
+}
+
+func TestConverter_Search(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if args[len(args)-2] == "missing.pdf" {
+			return &ExitError{Code: 1}
+		}
+		io.WriteString(stdout, "Summary\fRevenue grew.\fRevenue fell.\f")
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	hits, err := converter.Search(context.Background(), "input.pdf", "REVENUE", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []PageHit{
+		{Page: 2, Matches: []SearchMatch{{Start: 0, End: 7, Text: "Revenue", Snippet: "Revenue grew."}}},
+		{Page: 3, Matches: []SearchMatch{{Start: 0, End: 7, Text: "Revenue", Snippet: "Revenue fell."}}},
+	}
+	if !reflect.DeepEqual(hits, expected) {
+		t.Errorf("expected %+v, got %+v", expected, hits)
+	}
+
+	_, err = converter.Search(context.Background(), "missing.pdf", "revenue", nil)
+	if !errors.Is(err, ErrPDFOpen) {
+		t.Errorf("expected ErrPDFOpen, got %v", err)
+	}
+}
+
+func TestSearchPagesRegexp(t *testing.T) {
+	pages := []Page{
+		{Number: 1, Text: "Invoice INV-0042 dated 2024-03-01"},
+		{Number: 2, Label: "ii", Text: "See INV-0042 and INV-0107."},
+	}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		opts     SearchOptions
+		expected []RegexpMatch
+	}{
+		{
+			name:    "Named groups",
+			pattern: `INV-(?P<number>\d+)`,
+			opts:    SearchOptions{SnippetContext: -1},
+			expected: []RegexpMatch{
+				{Page: 1, Start: 8, End: 16, Text: "INV-0042", Groups: []string{"0042"}, Names: []string{"number"}},
+				{Page: 2, Label: "ii", Start: 4, End: 12, Text: "INV-0042", Groups: []string{"0042"}, Names: []string{"number"}},
+				{Page: 2, Label: "ii", Start: 17, End: 25, Text: "INV-0107", Groups: []string{"0107"}, Names: []string{"number"}},
+			},
+		},
+		{
+			name:    "Unnamed and optional groups",
+			pattern: `(\d{4})-(\d\d)-(\d\d)(T\d\d)?`,
+			opts:    SearchOptions{SnippetContext: 6},
+			expected: []RegexpMatch{
+				{Page: 1, Start: 23, End: 33, Text: "2024-03-01", Groups: []string{"2024", "03", "01", ""}, Snippet: "…dated 2024-03-01"},
+			},
+		},
+		{
+			name:     "No groups",
+			pattern:  `(?i)invoice`,
+			opts:     SearchOptions{SnippetContext: -1},
+			expected: []RegexpMatch{{Page: 1, Start: 0, End: 7, Text: "Invoice"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SearchPagesRegexp(pages, regexp.MustCompile(tt.pattern), tt.opts)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	match := SearchPagesRegexp(pages, regexp.MustCompile(`INV-(?P<number>\d+)`), SearchOptions{})[0]
+	if got := match.Group("number"); got != "0042" {
+		t.Errorf("expected group 0042, got %q", got)
+	}
+	if got := match.Group("missing"); got != "" {
+		t.Errorf("expected no group, got %q", got)
+	}
+}
+
+func TestConverter_SearchRegexp(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stdout, "Total: $12.50\fTotal: $7.00\f")
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	matches, err := converter.SearchRegexp(context.Background(), "input.pdf", regexp.MustCompile(`\$(?P<amount>[\d.]+)`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Page != 1 || matches[1].Page != 2 || matches[1].Group("amount") != "7.00" {
+		t.Errorf("unexpected matches %+v", matches)
+	}
+}
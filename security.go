@@ -0,0 +1,217 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Permissions are the operations the permission bits of a PDF file allow
+type Permissions struct {
+	// Print allows printing
+	Print bool `json:"print"`
+	// Copy allows copying text and graphics
+	Copy bool `json:"copy"`
+	// Modify allows changing the document
+	Modify bool `json:"modify"`
+	// Annotate allows adding annotations and filling forms
+	Annotate bool `json:"annotate"`
+}
+
+// Signature is a digital signature of a PDF file as reported by pdfsig
+type Signature struct {
+	// Field is the name of the signature field
+	Field string `json:"field,omitempty"`
+	// Signer is the common name of the signer certificate
+	Signer string `json:"signer,omitempty"`
+	// SigningTime is the signing time as printed by pdfsig
+	SigningTime string `json:"signing_time,omitempty"`
+	// HashAlgorithm is the hash algorithm, e.g. "SHA-256"
+	HashAlgorithm string `json:"hash_algorithm,omitempty"`
+	// Type is the signature type, e.g. "adbe.pkcs7.detached"
+	Type string `json:"type,omitempty"`
+	// WholeDocument reports whether the signature covers the whole file,
+	// i.e. the file was not changed after signing
+	WholeDocument bool `json:"whole_document"`
+	// Validation is the result of validating the signature, e.g.
+	// "Signature is Valid."
+	Validation string `json:"validation,omitempty"`
+	// CertificateValidation is the result of validating the certificate,
+	// e.g. "Certificate is Trusted."
+	CertificateValidation string `json:"certificate_validation,omitempty"`
+}
+
+// SecurityInfo describes the encryption and the digital signatures of a PDF
+// file
+type SecurityInfo struct {
+	// Encrypted reports whether the PDF is encrypted
+	Encrypted bool `json:"encrypted"`
+	// Algorithm is the encryption algorithm, e.g. "AES-256", empty if the
+	// PDF is not encrypted
+	Algorithm string `json:"algorithm,omitempty"`
+	// Permissions are the allowed operations. Everything is allowed in
+	// files that are not encrypted.
+	Permissions Permissions `json:"permissions"`
+	// SignaturesChecked reports whether pdfsig was available to check for
+	// digital signatures
+	SignaturesChecked bool `json:"signatures_checked"`
+	// Signatures are the digital signatures
+	Signatures []Signature `json:"signatures,omitempty"`
+}
+
+// SecurityInfo returns the encryption algorithm and permissions of a PDF file
+// using pdfinfo, and its digital signatures using pdfsig when it is
+// installed, for compliance workflows to record next to the extracted text.
+// Only the password options of opts are used.
+func (c *Converter) SecurityInfo(ctx context.Context, inputPath string, opts *Options) (*SecurityInfo, error) {
+	ctx, opts = applyOverrides(ctx, opts)
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.Info(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	security := parseEncryption(info.Raw["Encrypted"])
+
+	args := append(passwordArgs(opts), escapePath(inputPath))
+
+	var stdout bytes.Buffer
+	stderr := c.newStderr()
+	err = c.runCommand(ctx, c.toolPath("pdfsig"), args, &stdout, stderr)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return security, nil
+	case strings.Contains(stdout.String(), "does not contain any signatures"):
+		// pdfsig exits with an error status for files without signatures
+	case err != nil:
+		return nil, c.handleError(err, stderr.String())
+	default:
+		security.Signatures = parseSignatures(stdout.String())
+	}
+	security.SignaturesChecked = true
+	return security, nil
+}
+
+// parseEncryption parses the Encrypted field printed by pdfinfo, e.g. "yes
+// (print:yes copy:no change:no addNotes:no algorithm:AES-256)"
+func parseEncryption(value string) *SecurityInfo {
+	security := &SecurityInfo{Permissions: Permissions{Print: true, Copy: true, Modify: true, Annotate: true}}
+	if !strings.HasPrefix(value, "yes") {
+		return security
+	}
+	security.Encrypted = true
+	details := strings.Trim(strings.TrimPrefix(value, "yes"), " ()")
+	for _, field := range strings.Fields(details) {
+		key, v, _ := strings.Cut(field, ":")
+		switch key {
+		case "print":
+			security.Permissions.Print = v == "yes"
+		case "copy":
+			security.Permissions.Copy = v == "yes"
+		case "change":
+			security.Permissions.Modify = v == "yes"
+		case "addNotes":
+			security.Permissions.Annotate = v == "yes"
+		case "algorithm":
+			security.Algorithm = v
+		}
+	}
+	return security
+}
+
+// parseSignatures parses the output of pdfsig, a "Signature #N:" line for
+// each signature followed by "  - Key: value" lines
+func parseSignatures(output string) []Signature {
+	var signatures []Signature
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Signature #") {
+			signatures = append(signatures, Signature{})
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok || len(signatures) == 0 {
+			continue
+		}
+		sig := &signatures[len(signatures)-1]
+		key, value, _ := strings.Cut(item, ":")
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Signature Field Name":
+			sig.Field = value
+		case "Signer Certificate Common Name":
+			sig.Signer = value
+		case "Signing Time":
+			sig.SigningTime = value
+		case "Signing Hash Algorithm":
+			sig.HashAlgorithm = value
+		case "Signature Type":
+			sig.Type = value
+		case "Total document signed":
+			sig.WholeDocument = true
+		case "Signature Validation":
+			sig.Validation = value
+		case "Certificate Validation":
+			sig.CertificateValidation = value
+		}
+	}
+	return signatures
+}
+
+// encryptionTailSize is the size of the end of a file IsEncrypted reads to
+// find the trailer
+const encryptionTailSize = 64 << 10
+
+// IsEncrypted reports whether a PDF file is encrypted, so upload endpoints
+// can ask for a password before queuing a conversion that would fail. It
+// reads the trailer at the end of the file, which names the encryption
+// dictionary, and only runs pdfinfo if the trailer cannot be found there.
+func (c *Converter) IsEncrypted(ctx context.Context, inputPath string) (bool, error) {
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return false, err
+	}
+	tail, err := readTail(inputPath, encryptionTailSize)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	f := &pdfFile{data: tail}
+	if trailer, err := f.trailer(); err == nil {
+		return trailer["Encrypt"] != nil, nil
+	}
+
+	info, err := c.Info(ctx, inputPath, nil)
+	if errors.Is(err, ErrEncrypted) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return info.Encrypted, nil
+}
+
+// readTail reads up to the last size bytes of a file
+func readTail(path string, size int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(stat.Size()-size, 0)
+	tail := make([]byte, stat.Size()-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return tail, nil
+}
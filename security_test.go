@@ -0,0 +1,175 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const pdfsigOutput = `Digital Signature Info of: input.pdf
+Signature #1:
+  - Signature Field Name: Signature1
+  - Signer Certificate Common Name: Jane Doe
+  - Signer full Distinguished Name: CN=Jane Doe,O=Example
+  - Signing Time: Jan 31 2024 12:00:00
+  - Signing Hash Algorithm: SHA-256
+  - Signature Type: adbe.pkcs7.detached
+  - Signed Ranges: [0 - 1234], [5678 - 9999]
+  - Total document signed
+  - Signature Validation: Signature is Valid.
+  - Certificate Validation: Certificate issuer isn't Trusted.
+Signature #2:
+  - Signature Field Name: Approval
+  - Not total document signed
+`
+
+func TestConverter_SecurityInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		pdfsig   func(stdout io.Writer) error
+		expected *SecurityInfo
+		err      error
+	}{
+		{
+			name: "Signed",
+			pdfsig: func(stdout io.Writer) error {
+				_, err := io.WriteString(stdout, pdfsigOutput)
+				return err
+			},
+			expected: &SecurityInfo{
+				Encrypted:         true,
+				Algorithm:         "AES-256",
+				Permissions:       Permissions{Print: true},
+				SignaturesChecked: true,
+				Signatures: []Signature{
+					{
+						Field:                 "Signature1",
+						Signer:                "Jane Doe",
+						SigningTime:           "Jan 31 2024 12:00:00",
+						HashAlgorithm:         "SHA-256",
+						Type:                  "adbe.pkcs7.detached",
+						WholeDocument:         true,
+						Validation:            "Signature is Valid.",
+						CertificateValidation: "Certificate issuer isn't Trusted.",
+					},
+					{Field: "Approval"},
+				},
+			},
+		},
+		{
+			name: "Unsigned",
+			pdfsig: func(stdout io.Writer) error {
+				io.WriteString(stdout, "File 'input.pdf' does not contain any signatures\n")
+				return &ExitError{Code: 2}
+			},
+			expected: &SecurityInfo{Encrypted: true, Algorithm: "AES-256", Permissions: Permissions{Print: true}, SignaturesChecked: true},
+		},
+		{
+			name: "Without pdfsig",
+			pdfsig: func(stdout io.Writer) error {
+				return &exec.Error{Name: "pdfsig", Err: exec.ErrNotFound}
+			},
+			expected: &SecurityInfo{Encrypted: true, Algorithm: "AES-256", Permissions: Permissions{Print: true}},
+		},
+		{
+			name: "Failure",
+			pdfsig: func(stdout io.Writer) error {
+				return &ExitError{Code: 1}
+			},
+			err: ErrPDFOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+				if name == "pdfsig" {
+					return tt.pdfsig(stdout)
+				}
+				_, err := io.WriteString(stdout, pdfinfoOutput)
+				return err
+			})))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			security, err := converter.SecurityInfo(context.Background(), "input.pdf", nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if !reflect.DeepEqual(security, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, security)
+			}
+		})
+	}
+}
+
+func TestParseEncryption(t *testing.T) {
+	expected := &SecurityInfo{Permissions: Permissions{Print: true, Copy: true, Modify: true, Annotate: true}}
+	if got := parseEncryption("no"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestConverter_IsEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"plain.pdf":     labeledPDF,
+		"encrypted.pdf": "%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R /ID [<01> <01>] >>\n%%EOF\n",
+		"damaged.pdf":   "%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\n",
+		"locked.pdf":    "%PDF-1.7\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatalf("failed to write input: %v", err)
+		}
+	}
+
+	var pdfinfoRuns int
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		pdfinfoRuns++
+		if filepath.Base(args[len(args)-1]) == "locked.pdf" {
+			io.WriteString(stderr, "Command Line Error: Incorrect password")
+			return &ExitError{Code: 1}
+		}
+		_, err := io.WriteString(stdout, pdfinfoOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		expected bool
+		runs     int
+		err      error
+	}{
+		{name: "Plain", file: "plain.pdf"},
+		{name: "Encrypted", file: "encrypted.pdf", expected: true},
+		{name: "Without trailer", file: "damaged.pdf", expected: true, runs: 1},
+		{name: "Password required", file: "locked.pdf", expected: true, runs: 1},
+		{name: "Missing", file: "missing.pdf", err: ErrPDFOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdfinfoRuns = 0
+			encrypted, err := converter.IsEncrypted(context.Background(), filepath.Join(dir, tt.file))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if encrypted != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, encrypted)
+			}
+			if pdfinfoRuns != tt.runs {
+				t.Errorf("expected %d pdfinfo runs, got %d", tt.runs, pdfinfoRuns)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package pdftotext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sentence is a sentence of a page
+type Sentence struct {
+	// Text is the sentence with its whitespace collapsed to single spaces
+	Text string `json:"text"`
+	// Page is the number of the page of the sentence
+	Page int `json:"page"`
+	// Start and End are the byte offsets of the sentence in the page text
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// abbreviations end with a period that does not end a sentence, in lower case
+// and without the period. Single letters, such as initials, and words with
+// inner periods, such as "e.g", are abbreviations as well.
+var abbreviations = map[string]bool{
+	"al": true, "approx": true, "art": true, "ca": true, "cf": true, "ch": true, "co": true,
+	"corp": true, "dept": true, "dr": true, "eq": true, "eqs": true, "est": true, "fig": true,
+	"figs": true, "gen": true, "inc": true, "jr": true, "ltd": true, "mr": true, "mrs": true,
+	"ms": true, "mt": true, "no": true, "nos": true, "nr": true, "p": true, "pp": true,
+	"prof": true, "ref": true, "refs": true, "rev": true, "sec": true, "sr": true, "st": true,
+	"vgl": true, "vol": true, "vols": true, "vs": true,
+	"jan": true, "feb": true, "mar": true, "apr": true, "jun": true, "jul": true, "aug": true,
+	"sep": true, "sept": true, "oct": true, "nov": true, "dec": true,
+}
+
+// Sentences splits text into sentences, with their whitespace collapsed to
+// single spaces. Sentences end at a period, question mark, exclamation mark
+// or ellipsis followed by whitespace and an upper-case letter, a digit or an
+// opening quote, unless the period ends a common abbreviation or an initial,
+// and never span a blank line or page break. Run a Dehyphenator first to
+// rejoin words hyphenated at line breaks.
+func Sentences(text string) []string {
+	var sentences []string
+	for _, span := range sentenceSpans(text) {
+		sentences = append(sentences, strings.Join(strings.Fields(text[span.start:span.end]), " "))
+	}
+	return sentences
+}
+
+// Sentences splits the text of each page into sentences, see Sentences
+func (d *Document) Sentences() []Sentence {
+	var sentences []Sentence
+	for _, page := range d.Pages {
+		for _, span := range sentenceSpans(page.Text) {
+			sentences = append(sentences, Sentence{
+				Text:  strings.Join(strings.Fields(page.Text[span.start:span.end]), " "),
+				Page:  page.Number,
+				Start: span.start,
+				End:   span.end,
+			})
+		}
+	}
+	return sentences
+}
+
+// sentenceSpans returns the spans of the sentences of text, without the
+// whitespace around them
+func sentenceSpans(text string) []textSpan {
+	var spans []textSpan
+	for _, para := range splitSpans(text, textSpan{0, len(text)}, paragraphBreakPattern) {
+		start := skipSpace(text, para.start, para.end)
+		for i := start; i < para.end; {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			if !strings.ContainsRune(".!?…", r) {
+				continue
+			}
+
+			// the sentence ends after any further punctuation and closing
+			// quotes, and the next one starts after the whitespace
+			end := i
+			for end < para.end {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !strings.ContainsRune(".!?…\"'”’)]»", r) {
+					break
+				}
+				end += size
+			}
+			next := skipSpace(text, end, para.end)
+			if next == end || next == para.end {
+				continue
+			}
+			if n, _ := utf8.DecodeRuneInString(text[next:]); !unicode.IsUpper(n) && !unicode.IsDigit(n) && !strings.ContainsRune("\"'“‘([«¿¡", n) {
+				continue
+			}
+			if r == '.' && isAbbreviation(text[start:i-1]) {
+				continue
+			}
+			spans = append(spans, textSpan{start, end})
+			start, i = next, next
+		}
+		if end := len(strings.TrimRightFunc(text[:para.end], unicode.IsSpace)); end > start {
+			spans = append(spans, textSpan{start, end})
+		}
+	}
+	return spans
+}
+
+// isAbbreviation reports whether the last word of text is an abbreviation
+// when followed by a period
+func isAbbreviation(text string) bool {
+	word := text[strings.LastIndexFunc(text, unicode.IsSpace)+1:]
+	word = strings.TrimLeft(word, "\"'“‘([«")
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+		return unicode.IsLetter(r)
+	}
+	return strings.Contains(word, ".") || abbreviations[strings.ToLower(word)]
+}
+
+// skipSpace returns the offset of the first non-space character of
+// text[start:end], or end
+func skipSpace(text string, start, end int) int {
+	for start < end {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		start += size
+	}
+	return start
+}
@@ -0,0 +1,72 @@
+package pdftotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSentences(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "Simple",
+			text:     "The first sentence. The second one! Is this the third? Yes.",
+			expected: []string{"The first sentence.", "The second one!", "Is this the third?", "Yes."},
+		},
+		{
+			name:     "Abbreviations",
+			text:     "Dr. Smith met Mr. J. Doe at 5 p.m. on Jan. 3. They talked, e.g. about Fig. 2 and the U.S. Army.",
+			expected: []string{"Dr. Smith met Mr. J. Doe at 5 p.m. on Jan. 3.", "They talked, e.g. about Fig. 2 and the U.S. Army."},
+		},
+		{
+			name:     "Numbers and lower case",
+			text:     "Pi is 3.14 or so. the next word is lower case. It scored 5. Then it stopped.",
+			expected: []string{"Pi is 3.14 or so. the next word is lower case.", "It scored 5.", "Then it stopped."},
+		},
+		{
+			name:     "Quotes and ellipses",
+			text:     "He said \"Stop.\" Then he left... \"Why?\" she asked.",
+			expected: []string{"He said \"Stop.\"", "Then he left...", "\"Why?\" she asked."},
+		},
+		{
+			name:     "Wrapped lines",
+			text:     "  A sentence wrapped\n  over two lines. And\nanother one.\n",
+			expected: []string{"A sentence wrapped over two lines.", "And another one."},
+		},
+		{
+			name:     "Paragraphs and pages",
+			text:     "A heading\n\nA paragraph without a period\fContinued on the next page.",
+			expected: []string{"A heading", "A paragraph without a period", "Continued on the next page."},
+		},
+		{
+			name: "Empty",
+			text: " \n\n ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sentences(tt.text); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDocument_Sentences(t *testing.T) {
+	doc := &Document{Pages: []Page{
+		{Number: 1, Text: "One. Two\nlines.\n"},
+		{Number: 2, Text: "\n  Three."},
+	}}
+	expected := []Sentence{
+		{Text: "One.", Page: 1, Start: 0, End: 4},
+		{Text: "Two lines.", Page: 1, Start: 5, End: 15},
+		{Text: "Three.", Page: 2, Start: 3, End: 9},
+	}
+	if got := doc.Sentences(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
@@ -0,0 +1,105 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// runRewritten converts the pages selected by opts, passing the text of each
+// page through the normalization and opts.Transformers and replacing the form feeds between pages
+// with opts.PageSeparator
+func (c *Converter) runRewritten(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	rewriteOpts := *opts
+	rewriteOpts.PageSeparator = ""
+	rewriteOpts.Transformers = nil
+	rewriteOpts.Normalize = ""
+	rewriteOpts.NoPageBreaks = false
+
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, &rewriteOpts)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(outputPath, stdout, func(w io.Writer) error {
+		var sw *separatorWriter
+		if opts.PageSeparator != "" {
+			sw = &separatorWriter{w: w, separator: opts.PageSeparator}
+			w = sw
+		}
+		var tw *transformWriter
+		if ts := transformers(opts); len(ts) > 0 {
+			tw = &transformWriter{w: w, transformers: ts, breaks: !opts.NoPageBreaks || sw != nil}
+			w = tw
+		}
+
+		for _, o := range rangeOpts {
+			if sw != nil {
+				sw.startRange(o.FirstPage)
+			}
+			if tw != nil {
+				tw.startRange(o.FirstPage)
+			}
+			err := c.run(ctx, inputPath, "-", o, w)
+			if tw != nil && tw.err != nil {
+				return tw.err
+			}
+			if err != nil {
+				return err
+			}
+			if tw != nil {
+				if err := tw.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// separatorWriter replaces the form feed ending each page with a separator
+// written before the next page, so no separator follows the last page. The
+// placeholder {n} in the separator is replaced with the number of the next
+// page.
+type separatorWriter struct {
+	w         io.Writer
+	separator string
+	// page is the number of the page being written, or of the next page
+	// while a separator is pending
+	page    int
+	pending bool
+}
+
+// startRange sets the number of the first page of a page range
+func (sw *separatorWriter) startRange(firstPage int) {
+	sw.page = max(firstPage, 1)
+}
+
+func (sw *separatorWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if sw.pending {
+			if _, err := io.WriteString(sw.w, strings.ReplaceAll(sw.separator, "{n}", strconv.Itoa(sw.page))); err != nil {
+				return 0, err
+			}
+			sw.pending = false
+		}
+
+		i := bytes.IndexByte(p, '\f')
+		if i < 0 {
+			if _, err := sw.w.Write(p); err != nil {
+				return 0, err
+			}
+			break
+		}
+		if _, err := sw.w.Write(p[:i]); err != nil {
+			return 0, err
+		}
+		sw.pending = true
+		sw.page++
+		p = p[i+1:]
+	}
+	return n, nil
+}
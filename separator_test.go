@@ -0,0 +1,119 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConverter_PageSeparator(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *Options
+		expected string
+	}{
+		{
+			name:     "all pages",
+			options:  &Options{PageSeparator: "\n--- Page {n} ---\n"},
+			expected: "page 1\n\n--- Page 2 ---\npage 2\n\n--- Page 3 ---\npage 3\n\n--- Page 4 ---\npage 4",
+		},
+		{
+			name:     "page range",
+			options:  &Options{FirstPage: 2, LastPage: 3, PageSeparator: "[{n}]"},
+			expected: "page 2\n[3]page 3",
+		},
+		{
+			name:     "multiple ranges",
+			options:  &Options{Pages: "1,3-4", PageSeparator: "[{n}]"},
+			expected: "page 1\n[3]page 3\n[4]page 4",
+		},
+		{
+			name:     "excluded pages",
+			options:  &Options{ExcludePages: "2", PageSeparator: "[{n}]", NoPageBreaks: true},
+			expected: "page 1\n[3]page 3\n[4]page 4",
+		},
+		{
+			name:     "no placeholder",
+			options:  &Options{LastPage: 2, PageSeparator: "---"},
+			expected: "page 1\n---page 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs atomic.Int32
+			converter, err := New(WithRunner(pagesRunner(4, 0, &runs)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			ctx := context.Background()
+
+			text, err := converter.Convert(ctx, "input.pdf", tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+
+			parallel, err := converter.ConvertParallel(ctx, "input.pdf", 3, tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parallel != tt.expected {
+				t.Errorf("expected parallel %q, got %q", tt.expected, parallel)
+			}
+
+			// nothing follows the last page
+			output := filepath.Join(t.TempDir(), "output.txt")
+			if err := converter.ConvertToFile(ctx, "input.pdf", output, tt.options); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if string(data) != tt.expected+"\n" {
+				t.Errorf("expected %q, got %q", tt.expected+"\n", data)
+			}
+
+			pages, err := converter.ConvertPages(ctx, "input.pdf", tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, page := range pages {
+				if strings.ContainsAny(page.Text, "[-") {
+					t.Errorf("expected page %d without separator, got %q", page.Number, page.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestSeparatorWriter(t *testing.T) {
+	var b bytes.Buffer
+	sw := &separatorWriter{w: &b, separator: "<{n}>"}
+	sw.startRange(5)
+	for _, chunk := range []string{"a\f", "b", "\fc\f", "\f"} {
+		if _, err := sw.Write([]byte(chunk)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if expected := "a<6>b<7>c<8>"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestConverter_CommandPageSeparator(t *testing.T) {
+	converter, err := New()
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if _, err := converter.Command("input.pdf", "-", &Options{PageSeparator: "---"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,231 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// Session holds a document that was converted once, so interactive
+// applications such as viewers can run many cheap queries against it. A
+// Session is safe for concurrent use and must be closed to remove its staged
+// copy of the PDF file.
+type Session struct {
+	converter *Converter
+	path      string
+	remove    func() error
+	opts      Options
+	info      *Info
+	pages     []Page
+	geometry  []PageGeometry
+	spans     [][]wordSpan
+	index     map[int]int
+}
+
+// wordSpan locates a word of the page geometry in the page text
+type wordSpan struct {
+	start, end int
+	box        Rect
+}
+
+// Match is an occurrence of a search query in a page
+type Match struct {
+	// Page is the page number
+	Page int `json:"page"`
+	// Offset is the byte offset of the match in the page text
+	Offset int `json:"offset"`
+	// Text is the matched text
+	Text string `json:"text"`
+}
+
+// OpenSession stages a copy of the PDF file and converts it once: the
+// document info, the text of every selected page and the word geometry. The
+// staged copy keeps later queries consistent if the original file changes.
+func (c *Converter) OpenSession(ctx context.Context, inputPath string, opts *Options) (*Session, error) {
+	s := &Session{converter: c, index: map[int]int{}}
+	if opts != nil {
+		s.opts = *opts
+	}
+	s.opts.NoPageBreaks = false
+
+	ctx, err := c.confine(ctx, inputPath, "")
+	if err != nil {
+		return nil, err
+	}
+	in, err := os.Open(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	defer in.Close()
+	if s.path, s.remove, err = c.stageReader(ctx, in, 0); err != nil {
+		return nil, err
+	}
+
+	opened := false
+	defer func() {
+		// also runs when loading panics
+		if !opened {
+			s.Close()
+		}
+	}()
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	opened = true
+	return s, nil
+}
+
+func (s *Session) load(ctx context.Context) error {
+	var err error
+	if s.info, err = s.converter.Info(ctx, s.path, &s.opts); err != nil {
+		return err
+	}
+	if s.pages, err = s.converter.ConvertPages(ctx, s.path, &s.opts); err != nil {
+		return err
+	}
+	if s.geometry, err = s.converter.ConvertGeometry(ctx, s.path, &s.opts); err != nil {
+		return err
+	}
+	for i, page := range s.pages {
+		s.index[page.Number] = i
+	}
+	s.spans = make([][]wordSpan, len(s.pages))
+	for _, g := range s.geometry {
+		if i, ok := s.index[g.Number]; ok {
+			s.spans[i] = alignWords(s.pages[i].Text, g.Words)
+		}
+	}
+	return nil
+}
+
+// alignWords finds the words in text in order, skipping words the text
+// doesn't contain, such as words dropped by different output options
+func alignWords(text string, words []Word) []wordSpan {
+	spans := make([]wordSpan, 0, len(words))
+	cursor := 0
+	for _, word := range words {
+		i := strings.Index(text[cursor:], word.Text)
+		if word.Text == "" || i < 0 {
+			continue
+		}
+		start := cursor + i
+		cursor = start + len(word.Text)
+		spans = append(spans, wordSpan{start: start, end: cursor, box: word.Box})
+	}
+	return spans
+}
+
+// Close removes the staged copy of the PDF file
+func (s *Session) Close() error {
+	if err := s.remove(); err != nil && !os.IsNotExist(err) && !errors.Is(err, os.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+// Info returns the document info
+func (s *Session) Info() *Info {
+	return s.info
+}
+
+// PageCount returns the number of pages in the document
+func (s *Session) PageCount() int {
+	return s.info.Pages
+}
+
+// Pages returns the converted pages in order
+func (s *Session) Pages() []Page {
+	return s.pages
+}
+
+// Page returns the page with the given number
+func (s *Session) Page(number int) (Page, error) {
+	i, ok := s.index[number]
+	if !ok {
+		return Page{}, fmt.Errorf("%w: %d", ErrInvalidPage, number)
+	}
+	return s.pages[i], nil
+}
+
+// Geometry returns the size and words of the page with the given number
+func (s *Session) Geometry(number int) (PageGeometry, error) {
+	for _, g := range s.geometry {
+		if g.Number == number {
+			return g, nil
+		}
+	}
+	return PageGeometry{}, fmt.Errorf("%w: %d", ErrInvalidPage, number)
+}
+
+// Search returns every occurrence of query in the converted pages, ignoring
+// case
+func (s *Session) Search(query string) []Match {
+	var matches []Match
+	if query == "" {
+		return matches
+	}
+	for _, page := range s.pages {
+		for offset := 0; offset+len(query) <= len(page.Text); offset++ {
+			if text := page.Text[offset : offset+len(query)]; strings.EqualFold(text, query) {
+				matches = append(matches, Match{Page: page.Number, Offset: offset, Text: text})
+			}
+		}
+	}
+	return matches
+}
+
+// TextRects returns the bounding boxes of the words of a page overlapping the
+// text from offset to offset+length, e.g. to highlight a search match in the
+// rendered page. A zero length selects the word at offset.
+func (s *Session) TextRects(number, offset, length int) ([]Rect, error) {
+	i, ok := s.index[number]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidPage, number)
+	}
+	end := offset + max(length, 1)
+
+	rects := []Rect{}
+	for _, span := range s.spans[i] {
+		if span.start < end && span.end > offset {
+			rects = append(rects, span.box)
+		}
+	}
+	return rects, nil
+}
+
+// TextOffset returns the offset in the page text of the word containing the
+// point x, y, or of the word nearest to it, e.g. to scroll the text to a
+// position clicked in the rendered page. It returns 0 for a page without
+// words.
+func (s *Session) TextOffset(number int, x, y float64) (int, error) {
+	i, ok := s.index[number]
+	if !ok {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidPage, number)
+	}
+
+	offset, nearest := 0, math.Inf(1)
+	for _, span := range s.spans[i] {
+		if span.box.Contains(x, y) {
+			return span.start, nil
+		}
+		cx, cy := span.box.Center()
+		if d := math.Hypot(cx-x, cy-y); d < nearest {
+			offset, nearest = span.start, d
+		}
+	}
+	return offset, nil
+}
+
+// Region returns the text of the words of a page whose centers lie inside r,
+// in reading order with a line break wherever a word starts below the
+// previous one
+func (s *Session) Region(number int, r Rect) (string, error) {
+	g, err := s.Geometry(number)
+	if err != nil {
+		return "", err
+	}
+	return regionText(g, r), nil
+}
@@ -0,0 +1,136 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// sessionRunner answers like pdfinfo, pdftotext and pdftotext -bbox for the
+// two page document of bboxOutput, recording the inputs it was asked to open
+func sessionRunner(inputs *[]string) Runner {
+	return runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		if name == "pdfinfo" {
+			*inputs = append(*inputs, args[len(args)-1])
+			_, err := io.WriteString(stdout, "Title:           Greeting\nPages:           2\n")
+			return err
+		}
+		*inputs = append(*inputs, args[len(args)-2])
+		if slices.Contains(args, "-bbox") {
+			_, err := io.WriteString(stdout, bboxOutput)
+			return err
+		}
+		_, err := io.WriteString(stdout, "Hello world\nSecond line\n\fFish & chips, hello\n\f")
+		return err
+	})
+}
+
+func TestConverter_OpenSession(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte("%PDF-1.7"), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	var inputs []string
+	converter, err := New(WithRunner(sessionRunner(&inputs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	session, err := converter.OpenSession(context.Background(), input, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 processes, got %d", len(inputs))
+	}
+	staged := inputs[0]
+	for _, path := range inputs {
+		if path != staged || path == input {
+			t.Errorf("expected every process to read the staged copy, got %v", inputs)
+		}
+	}
+
+	if session.Info().Title != "Greeting" || session.PageCount() != 2 {
+		t.Errorf("unexpected info %+v", session.Info())
+	}
+	page, err := session.Page(2)
+	if err != nil || page.Text != "Fish & chips, hello\n" {
+		t.Errorf("unexpected page %+v, %v", page, err)
+	}
+	if _, err := session.Page(3); !errors.Is(err, ErrInvalidPage) {
+		t.Errorf("expected error %v, got %v", ErrInvalidPage, err)
+	}
+
+	expectedMatches := []Match{{Page: 1, Offset: 0, Text: "Hello"}, {Page: 2, Offset: 14, Text: "hello"}}
+	if matches := session.Search("HELLO"); !reflect.DeepEqual(matches, expectedMatches) {
+		t.Errorf("expected %+v, got %+v", expectedMatches, matches)
+	}
+
+	tests := []struct {
+		name     string
+		rect     Rect
+		expected string
+	}{
+		{"Whole page", Rect{0, 0, 612, 792}, "Hello world\nSecond line"},
+		{"First column", Rect{0, 0, 91, 792}, "Hello\nSecond"},
+		{"Second line", Rect{0, 70, 612, 84}, "Second line"},
+		{"Empty", Rect{300, 300, 400, 400}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := session.Region(1, tt.rect)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+		})
+	}
+
+	rects, err := session.TextRects(1, 6, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedRects := []Rect{{92.44, 57.208, 125.752, 69.208}, {56.8, 71.008, 96.1, 83.008}}
+	if !reflect.DeepEqual(rects, expectedRects) {
+		t.Errorf("expected %v, got %v", expectedRects, rects)
+	}
+	for _, tt := range []struct {
+		x, y     float64
+		expected int
+	}{{100, 60, 6}, {60, 75, 12}, {110, 300, 19}} {
+		if offset, err := session.TextOffset(1, tt.x, tt.y); err != nil || offset != tt.expected {
+			t.Errorf("TextOffset(1, %v, %v): expected %d, got %d, %v", tt.x, tt.y, tt.expected, offset, err)
+		}
+	}
+	if _, err := session.TextRects(5, 0, 1); !errors.Is(err, ErrInvalidPage) {
+		t.Errorf("expected error %v, got %v", ErrInvalidPage, err)
+	}
+
+	if err := session.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(staged); !os.IsNotExist(err) {
+		t.Errorf("expected the staged copy to be removed, got %v", err)
+	}
+	if _, err := os.Stat(input); err != nil {
+		t.Errorf("expected the input to be kept, got %v", err)
+	}
+}
+
+func TestConverter_OpenSession_MissingFile(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(nil)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	if _, err := converter.OpenSession(context.Background(), "missing.pdf", nil); !errors.Is(err, ErrPDFOpen) {
+		t.Errorf("expected error %v, got %v", ErrPDFOpen, err)
+	}
+}
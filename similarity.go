@@ -0,0 +1,26 @@
+package pdftotext
+
+import "strings"
+
+// Similarity returns how similar two texts are as the Dice coefficient of
+// their word multisets, from 0 (no words in common) to 1 (same words).
+// Whitespace and line breaks are ignored, so layout-only differences score 1.
+func Similarity(a, b string) float64 {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+
+	counts := make(map[string]int, len(wordsA))
+	for _, w := range wordsA {
+		counts[w]++
+	}
+	common := 0
+	for _, w := range wordsB {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+}
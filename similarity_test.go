@@ -0,0 +1,29 @@
+package pdftotext
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected float64
+	}{
+		{name: "Both empty", a: "", b: "  ", expected: 1},
+		{name: "Identical", a: "the quick fox", b: "the quick fox", expected: 1},
+		{name: "Layout only", a: "the   quick\nfox", b: "the quick fox", expected: 1},
+		{name: "One word differs", a: "the quick brown fox", b: "the quick brown dog", expected: 0.75},
+		{name: "Nothing in common", a: "alpha beta", b: "gamma", expected: 0},
+		{name: "Repeated words", a: "a a a b", b: "a b", expected: 2 * 2.0 / 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package pdftotext
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Snippet returns the first maxChars characters of the text of a PDF file,
+// with runs of whitespace and page breaks collapsed into single spaces, for
+// list views of document management UIs. It converts the pages one at a time
+// and stops pdftotext as soon as it has enough text, so long documents are
+// not converted in full.
+func (c *Converter) Snippet(ctx context.Context, inputPath string, maxChars int) (string, error) {
+	if maxChars <= 0 {
+		return "", nil
+	}
+	var words []string
+	chars := -1
+	for page, err := range c.StreamPages(ctx, inputPath, nil) {
+		if err != nil {
+			return "", err
+		}
+		for _, word := range strings.Fields(page.Text) {
+			words = append(words, word)
+			chars += 1 + len([]rune(word))
+		}
+		if chars >= maxChars {
+			break
+		}
+	}
+	snippet := []rune(strings.Join(words, " "))
+	if len(snippet) > maxChars {
+		snippet = snippet[:maxChars]
+	}
+	return strings.TrimSpace(string(snippet)), nil
+}
+
+// SnippetOptions configures Snippets
+type SnippetOptions struct {
+	// Window is the number of characters of context on each side of a match
+	// (default 40)
+	Window int
+	// MaxPerPage is the maximum number of snippets of a page, 0 for no limit
+	MaxPerPage int
+	// CaseSensitive and Stem configure matching the query as for Search
+	CaseSensitive bool
+	Stem          bool
+}
+
+// TextSnippet is an excerpt of a page around one or more matches of a query
+type TextSnippet struct {
+	// Page is the page number
+	Page int `json:"page"`
+	// Label is the page label, when requested with Options.PageLabels
+	Label string `json:"label,omitempty"`
+	// Text is the excerpt, whitespace collapsed and with an ellipsis where
+	// the page text was cut
+	Text string `json:"text"`
+	// Start and End are the byte offsets of the excerpt in the page text
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Highlights are the matches in the excerpt, in order
+	Highlights []Highlight `json:"highlights"`
+}
+
+// Highlight is a match of a query in a TextSnippet
+type Highlight struct {
+	// Start and End are the byte offsets of the match in the snippet text
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Snippets returns excerpts of the pages of doc around the matches of query,
+// with the offsets of the matches for highlighting them in search results.
+// The query matches whole words as for Search. Matches whose context
+// overlaps share a snippet, and offsets always fall on character
+// boundaries.
+func Snippets(doc *Document, query string, opts SnippetOptions) []TextSnippet {
+	if opts.Window <= 0 {
+		opts.Window = defaultSnippetContext
+	}
+	hits := SearchPages(doc.Pages, query, SearchOptions{CaseSensitive: opts.CaseSensitive, Stem: opts.Stem, SnippetContext: -1})
+
+	snippets := []TextSnippet{}
+	texts := map[int]string{}
+	for _, page := range doc.Pages {
+		texts[page.Number] = page.Text
+	}
+	for _, hit := range hits {
+		text := texts[hit.Page]
+		var pageSnippets []TextSnippet
+		for i := 0; i < len(hit.Matches); {
+			if opts.MaxPerPage > 0 && len(pageSnippets) == opts.MaxPerPage {
+				break
+			}
+			from, to := snippetBounds(text, hit.Matches[i].Start, hit.Matches[i].End, opts.Window)
+			j := i + 1
+			for ; j < len(hit.Matches); j++ {
+				next, end := snippetBounds(text, hit.Matches[j].Start, hit.Matches[j].End, opts.Window)
+				if next > to {
+					break
+				}
+				to = end
+			}
+			excerpt := excerpt(text, from, to, hit.Matches[i:j])
+			excerpt.Page, excerpt.Label = hit.Page, hit.Label
+			pageSnippets = append(pageSnippets, excerpt)
+			i = j
+		}
+		snippets = append(snippets, pageSnippets...)
+	}
+	return snippets
+}
+
+// excerpt returns text[from:to] with its whitespace collapsed and the
+// matches in it highlighted
+func excerpt(text string, from, to int, matches []SearchMatch) TextSnippet {
+	prefix, suffix := ellipses(text, from, to)
+
+	// offsets maps the byte offsets of text[from:to] to the excerpt
+	offsets := make([]int, to-from+1)
+	var b strings.Builder
+	b.WriteString(prefix)
+	space := false
+	for i := from; i < to; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			space = b.Len() > len(prefix)
+			offsets[i-from] = b.Len()
+			i += size
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		offsets[i-from] = b.Len()
+		b.WriteString(text[i : i+size])
+		i += size
+		offsets[i-from] = b.Len()
+	}
+	b.WriteString(suffix)
+
+	s := TextSnippet{Text: b.String(), Start: from, End: to}
+	for _, m := range matches {
+		s.Highlights = append(s.Highlights, Highlight{Start: offsets[m.Start-from], End: offsets[m.End-from]})
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package pdftotext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestConverter_Snippet(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		// the runner writes pages until it is stopped
+		for i := 1; ; i++ {
+			if _, err := fmt.Fprintf(stdout, "Über  page\n%d\f", i); err != nil {
+				return err
+			}
+		}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		maxChars int
+		expected string
+	}{
+		{name: "Within the first page", maxChars: 8, expected: "Über pag"},
+		{name: "Across pages", maxChars: 25, expected: "Über page 1 Über page 2 Ü"},
+		{name: "Trailing space", maxChars: 12, expected: "Über page 1"},
+		{name: "Zero", maxChars: 0, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snippet, err := converter.Snippet(context.Background(), "input.pdf", tt.maxChars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if snippet != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, snippet)
+			}
+		})
+	}
+}
+
+func TestSnippets(t *testing.T) {
+	doc := &Document{Pages: []Page{
+		{Number: 1, Text: "Die Größe der Straße ist   wichtig.\nDie Straße führt zum Markt, und die Straße endet dort."},
+		{Number: 2, Text: "Keine Treffer hier."},
+		{Number: 3, Text: "straße"},
+	}}
+
+	tests := []struct {
+		name     string
+		query    string
+		opts     SnippetOptions
+		expected []string
+	}{
+		{
+			name:     "Separate",
+			query:    "straße",
+			opts:     SnippetOptions{Window: 12},
+			expected: []string{"1:…Größe der [Straße] ist…", "1:…Die [Straße] führt zum…", "1:…und die [Straße] endet dort.", "3:[straße]"},
+		},
+		{
+			name:     "Merged",
+			query:    "straße",
+			opts:     SnippetOptions{Window: 20},
+			expected: []string{"1:Die Größe der [Straße] ist wichtig. Die [Straße] führt zum Markt, und die [Straße] endet dort.", "3:[straße]"},
+		},
+		{
+			name:     "Max per page",
+			query:    "straße",
+			opts:     SnippetOptions{Window: 5, MaxPerPage: 1},
+			expected: []string{"1:…der [Straße] ist…", "3:[straße]"},
+		},
+		{
+			name:     "Case sensitive",
+			query:    "Straße",
+			opts:     SnippetOptions{Window: 200, CaseSensitive: true},
+			expected: []string{"1:Die Größe der [Straße] ist wichtig. Die [Straße] führt zum Markt, und die [Straße] endet dort."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range Snippets(doc, tt.query, tt.opts) {
+				// mark the highlights in the snippet text
+				text := s.Text
+				for _, h := range slices.Backward(s.Highlights) {
+					text = text[:h.Start] + "[" + text[h.Start:h.End] + "]" + text[h.End:]
+				}
+				got = append(got, fmt.Sprintf("%d:%s", s.Page, text))
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SQLiteSink writes documents, their pages and their words with bounding
+// boxes into FTS5 tables of an SQLite database, so a batch run leaves a
+// single file that can be searched with the sqlite3 shell. It works with any
+// database/sql driver for SQLite built with FTS5, such as modernc.org/sqlite
+// or mattn/go-sqlite3 with the sqlite_fts5 tag; this package does not import
+// one.
+//
+// The tables are created by Migrate, or by running the statements returned
+// by Schema:
+//
+//	documents (path, title, author, subject, keywords, metadata, pages, indexed_at)
+//	pages     (path, page, label, text)
+//	words     (path, page, word, x_min, y_min, x_max, y_max)
+//
+// Only the text columns are indexed, and they can be queried with MATCH:
+//
+//	SELECT path, page, snippet(pdf_pages, 3, '[', ']', '…', 10)
+//	FROM pdf_pages WHERE pdf_pages MATCH 'quarterly revenue' ORDER BY rank
+type SQLiteSink struct {
+	// DB is the database the documents are written to
+	DB *sql.DB
+	// DocumentsTable is the name of the documents table (default "pdf_documents")
+	DocumentsTable string
+	// PagesTable is the name of the pages table (default "pdf_pages")
+	PagesTable string
+	// WordsTable is the name of the words table (default "pdf_words")
+	WordsTable string
+	// Tokenizer is the FTS5 tokenizer of the tables (default "unicode61"),
+	// e.g. "porter unicode61" to match English inflections
+	Tokenizer string
+}
+
+// tokenizerPattern matches the FTS5 tokenizer specifications accepted by
+// SQLiteSink
+var tokenizerPattern = regexp.MustCompile(`^[A-Za-z0-9_]+( [A-Za-z0-9_]+)*$`)
+
+// Schema returns the statements creating the tables. The statements are
+// idempotent.
+func (s *SQLiteSink) Schema() ([]string, error) {
+	documents, pages, words, tokenizer, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(path UNINDEXED, title, author, subject, keywords, metadata UNINDEXED, pages UNINDEXED, indexed_at UNINDEXED, tokenize = '%s')", documents, tokenizer),
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(path UNINDEXED, page UNINDEXED, label UNINDEXED, text, tokenize = '%s')", pages, tokenizer),
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(path UNINDEXED, page UNINDEXED, word, x_min UNINDEXED, y_min UNINDEXED, x_max UNINDEXED, y_max UNINDEXED, tokenize = '%s')", words, tokenizer),
+	}, nil
+}
+
+// Migrate creates the tables returned by Schema
+func (s *SQLiteSink) Migrate(ctx context.Context) error {
+	statements, err := s.Schema()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range statements {
+		if _, err := s.DB.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to migrate: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteDocument writes doc and its pages in one transaction, replacing the
+// document and pages previously written for the same path
+func (s *SQLiteSink) WriteDocument(ctx context.Context, doc *Document) error {
+	documents, pages, _, _, err := s.names()
+	if err != nil {
+		return err
+	}
+	metadata := doc.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, table := range []string{documents, pages} {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE path = ?", table), doc.Path); err != nil {
+				return fmt.Errorf("failed to write document %s: %w", doc.Path, err)
+			}
+		}
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (path, title, author, subject, keywords, metadata, pages, indexed_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', 'now'))`, documents),
+			doc.Path, metadata["title"], metadata["author"], metadata["subject"], metadata["keywords"], string(data), len(doc.Pages))
+		if err != nil {
+			return fmt.Errorf("failed to write document %s: %w", doc.Path, err)
+		}
+
+		stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (path, page, label, text) VALUES (?, ?, ?, ?)", pages))
+		if err != nil {
+			return fmt.Errorf("failed to write document %s: %w", doc.Path, err)
+		}
+		defer stmt.Close()
+		for _, page := range doc.Pages {
+			var label sql.NullString
+			if page.Label != "" {
+				label = sql.NullString{String: page.Label, Valid: true}
+			}
+			// SQLite string functions and tokenizers stop at NUL bytes
+			text := strings.ReplaceAll(page.Text, "\x00", "")
+			if _, err := stmt.ExecContext(ctx, doc.Path, page.Number, label, text); err != nil {
+				return fmt.Errorf("failed to write page %d of %s: %w", page.Number, doc.Path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// WriteWords writes the words of pages, as returned by ConvertGeometry, for
+// the document at path in one transaction, replacing the words previously
+// written for it
+func (s *SQLiteSink) WriteWords(ctx context.Context, path string, pages []PageGeometry) error {
+	_, _, words, _, err := s.names()
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE path = ?", words), path); err != nil {
+			return fmt.Errorf("failed to write words of %s: %w", path, err)
+		}
+		stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (path, page, word, x_min, y_min, x_max, y_max) VALUES (?, ?, ?, ?, ?, ?, ?)", words))
+		if err != nil {
+			return fmt.Errorf("failed to write words of %s: %w", path, err)
+		}
+		defer stmt.Close()
+		for _, page := range pages {
+			for _, w := range page.Words {
+				if _, err := stmt.ExecContext(ctx, path, page.Number, w.Text, w.Box.XMin, w.Box.YMin, w.Box.XMax, w.Box.YMax); err != nil {
+					return fmt.Errorf("failed to write words of page %d of %s: %w", page.Number, path, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteDocument removes the document, its pages and its words
+func (s *SQLiteSink) DeleteDocument(ctx context.Context, path string) error {
+	documents, pages, words, _, err := s.names()
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, table := range []string{documents, pages, words} {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE path = ?", table), path); err != nil {
+				return fmt.Errorf("failed to delete document %s: %w", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// inTx calls fn in a transaction, committed if fn succeeds and rolled back
+// otherwise
+func (s *SQLiteSink) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// names returns the validated table names and tokenizer. They are
+// interpolated into the statements, so only plain identifiers are accepted.
+func (s *SQLiteSink) names() (documents, pages, words, tokenizer string, err error) {
+	documents = cmp.Or(s.DocumentsTable, "pdf_documents")
+	pages = cmp.Or(s.PagesTable, "pdf_pages")
+	words = cmp.Or(s.WordsTable, "pdf_words")
+	tokenizer = cmp.Or(s.Tokenizer, "unicode61")
+	for _, name := range []string{documents, pages, words} {
+		if !identifierPattern.MatchString(name) {
+			return "", "", "", "", fmt.Errorf("invalid identifier %q", name)
+		}
+	}
+	if !tokenizerPattern.MatchString(tokenizer) {
+		return "", "", "", "", fmt.Errorf("invalid tokenizer %q", tokenizer)
+	}
+	return documents, pages, words, tokenizer, nil
+}
@@ -0,0 +1,116 @@
+package pdftotext
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSQLiteSink_Schema(t *testing.T) {
+	tests := []struct {
+		name     string
+		sink     *SQLiteSink
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "defaults",
+			sink:     &SQLiteSink{},
+			expected: []string{"CREATE VIRTUAL TABLE IF NOT EXISTS pdf_documents USING fts5(", "pdf_pages USING fts5(path UNINDEXED, page UNINDEXED, label UNINDEXED, text,", "pdf_words USING fts5(", "tokenize = 'unicode61'"},
+		},
+		{
+			name:     "custom names",
+			sink:     &SQLiteSink{DocumentsTable: "docs", PagesTable: "pages", WordsTable: "words", Tokenizer: "porter unicode61"},
+			expected: []string{"EXISTS docs USING", "EXISTS pages USING", "EXISTS words USING", "tokenize = 'porter unicode61'"},
+		},
+		{
+			name: "invalid table",
+			sink: &SQLiteSink{WordsTable: "words; DROP TABLE users"},
+			err:  true,
+		},
+		{
+			name: "invalid tokenizer",
+			sink: &SQLiteSink{Tokenizer: "unicode61')"},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := tt.sink.Schema()
+			if tt.err {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			schema := strings.Join(statements, ";\n")
+			for _, s := range tt.expected {
+				if !strings.Contains(schema, s) {
+					t.Errorf("expected schema to contain %q, got:\n%s", s, schema)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteSink_Write(t *testing.T) {
+	db, d := openRecordingDB(t)
+	sink := &SQLiteSink{DB: db}
+	ctx := context.Background()
+
+	if err := sink.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.statements) != 3 {
+		t.Fatalf("expected 3 migration statements, got %d", len(d.statements))
+	}
+
+	d.statements = nil
+	doc := &Document{
+		Path:     "report.pdf",
+		Metadata: map[string]string{"title": "Report"},
+		Pages:    []Page{{Number: 1, Label: "i", Text: "one\x00"}, {Number: 2, Text: "two"}},
+	}
+	if err := sink.WriteDocument(ctx, doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	geometry := []PageGeometry{{Number: 1, Words: []Word{{Text: "one", Box: Rect{XMin: 1, YMin: 2, XMax: 3, YMax: 4}}}}}
+	if err := sink.WriteWords(ctx, "report.pdf", geometry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		`DELETE FROM pdf_documents WHERE path = ? [report.pdf]`,
+		`DELETE FROM pdf_pages WHERE path = ? [report.pdf]`,
+		`INSERT INTO pdf_documents`,
+		`INSERT INTO pdf_pages (path, page, label, text) VALUES (?, ?, ?, ?) [report.pdf 1 i one]`,
+		`INSERT INTO pdf_pages (path, page, label, text) VALUES (?, ?, ?, ?) [report.pdf 2 <nil> two]`,
+		`DELETE FROM pdf_words WHERE path = ? [report.pdf]`,
+		`INSERT INTO pdf_words (path, page, word, x_min, y_min, x_max, y_max) VALUES (?, ?, ?, ?, ?, ?, ?) [report.pdf 1 one 1 2 3 4]`,
+	}
+	if len(d.statements) != len(expected) {
+		t.Fatalf("expected %d statements, got %q", len(expected), d.statements)
+	}
+	for i, s := range expected {
+		if !strings.HasPrefix(d.statements[i], s) {
+			t.Errorf("expected statement %d to start with %q, got %q", i, s, d.statements[i])
+		}
+	}
+	if !strings.HasSuffix(d.statements[2], `[report.pdf Report    {"title":"Report"} 2]`) {
+		t.Errorf("unexpected document arguments: %q", d.statements[2])
+	}
+	if d.commits != 2 || d.rollbacks != 0 {
+		t.Errorf("expected 2 commits and no rollback, got %d and %d", d.commits, d.rollbacks)
+	}
+
+	d.failOn = "INSERT [report.pdf 2"
+	if err := sink.WriteDocument(ctx, doc); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if d.commits != 2 || d.rollbacks != 1 {
+		t.Errorf("expected the failed write to roll back, got %d commits and %d rollbacks", d.commits, d.rollbacks)
+	}
+}
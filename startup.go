@@ -0,0 +1,68 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// startupCheckTimeout bounds the commands run by the startup check
+const startupCheckTimeout = 10 * time.Second
+
+// WithStartupCheck makes New verify the environment instead of only looking
+// up the binary: that the binary runs, that the temporary directory is
+// writable, and that the poppler companion tools, e.g. "pdfinfo" for the
+// metadata APIs, are installed and run. New returns all failed checks joined
+// in one error.
+func WithStartupCheck(tools ...string) ConverterOption {
+	return func(c *Converter) {
+		c.startupCheck = true
+		c.startupTools = append(c.startupTools, tools...)
+	}
+}
+
+// checkStartup runs the checks of WithStartupCheck
+func (c *Converter) checkStartup() error {
+	ctx, cancel := context.WithTimeout(context.Background(), startupCheckTimeout)
+	defer cancel()
+
+	var errs []error
+	if _, output, err := c.versionOutput(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("%s -v failed: %w", c.binaryPath, err))
+	} else {
+		c.variant = parseVariant(output)
+	}
+	if f, err := c.createTemp("pdftotext-check-*"); err != nil {
+		errs = append(errs, fmt.Errorf("temporary directory is not writable: %w", err))
+	} else {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	for _, tool := range c.startupTools {
+		if err := c.checkTool(ctx, tool); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("startup check failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// checkTool runs a companion tool with -v and verifies that it prints its
+// version
+func (c *Converter) checkTool(ctx context.Context, tool string) error {
+	path := c.toolPath(tool)
+	var stdout, stderr bytes.Buffer
+	err := c.runCommand(ctx, path, []string{"-v"}, &stdout, &stderr)
+	if parseVersion(stderr.String()+stdout.String()) != "" {
+		return nil
+	}
+	if err == nil {
+		err = errors.New("unrecognized version output")
+	}
+	return fmt.Errorf("%s is not usable: %w", tool, err)
+}
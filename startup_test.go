@@ -0,0 +1,45 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithStartupCheck(t *testing.T) {
+	runner := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		switch name {
+		case "pdftotext", "pdfsig":
+			_, err := io.WriteString(stderr, name+" version 24.02.0\n")
+			return err
+		default:
+			return exec.ErrNotFound
+		}
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		if _, err := New(WithRunner(runner), WithStartupCheck("pdfsig")); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("aggregated", func(t *testing.T) {
+		t.Setenv("TMPDIR", filepath.Join(t.TempDir(), "missing"))
+		_, err := New(WithRunner(runner), WithStartupCheck("pdfinfo", "pdfsig"))
+		if !errors.Is(err, exec.ErrNotFound) {
+			t.Errorf("expected exec.ErrNotFound for pdfinfo, got %v", err)
+		}
+		for _, want := range []string{"startup check failed", "temporary directory is not writable", "pdfinfo is not usable"} {
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("expected the error to contain %q, got %v", want, err)
+			}
+		}
+		if err != nil && strings.Contains(err.Error(), "pdfsig") {
+			t.Errorf("expected pdfsig to pass, got %v", err)
+		}
+	})
+}
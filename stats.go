@@ -0,0 +1,359 @@
+package pdftotext
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SidecarSuffix is appended to the output path to name the sidecar written by
+// ConvertToFileWithSidecar
+const SidecarSuffix = ".conversion.json"
+
+// Sidecar records the outcome of a conversion next to its output, so large
+// ingestion runs can be summarized with AggregateSidecars
+type Sidecar struct {
+	// Input is the converted PDF file
+	Input string `json:"input"`
+	// Output is the produced text file
+	Output string `json:"output"`
+	// Pages is the number of converted pages
+	Pages int `json:"pages"`
+	// Language is the dominant language of the text as a BCP 47 tag, when known
+	Language string `json:"language,omitempty"`
+	// Quality is the extraction quality score from 0 to 1, see ScoreQuality,
+	// unless the output has no words
+	Quality *float64 `json:"quality,omitempty"`
+	// Error is the error message of a failed conversion
+	Error string `json:"error,omitempty"`
+	// ErrorKind classifies a failed conversion, see ErrorKind
+	ErrorKind string `json:"error_kind,omitempty"`
+	// ConvertedAt is the time the conversion finished
+	ConvertedAt time.Time `json:"converted_at"`
+	// DurationMS is the duration of the conversion in milliseconds
+	DurationMS int64 `json:"duration_ms"`
+	// InputSHA256 is the hex encoded SHA-256 of the input file, unless it
+	// could not be read
+	InputSHA256 string `json:"input_sha256,omitempty"`
+	// OutputSHA256 is the hex encoded SHA-256 of the output file of a
+	// successful conversion, see VerifyOutput
+	OutputSHA256 string `json:"output_sha256,omitempty"`
+}
+
+// ErrorKind classifies err by the sentinel errors of this package, returning
+// "encrypted", "permissions", "not_pdf", "file_too_large", "vetoed",
+// "pdf_open", "output_file", "invalid_page", "invalid_range",
+// "language_data", "path_not_allowed", "unsupported_option", "empty_output",
+// "out_of_memory", "killed", "command_failed", "canceled" or "other"
+func ErrorKind(err error) string {
+	kinds := []struct {
+		err  error
+		kind string
+	}{
+		{ErrEncrypted, "encrypted"},
+		{ErrPermissions, "permissions"},
+		{ErrNotPDF, "not_pdf"},
+		{ErrFileTooLarge, "file_too_large"},
+		{ErrVetoed, "vetoed"},
+		{ErrPDFOpen, "pdf_open"},
+		{ErrOutputFile, "output_file"},
+		{ErrInvalidPage, "invalid_page"},
+		{ErrInvalidRange, "invalid_range"},
+		{ErrMissingLanguageData, "language_data"},
+		{ErrPathNotAllowed, "path_not_allowed"},
+		{ErrUnsupportedOption, "unsupported_option"},
+		{ErrEmptyOutput, "empty_output"},
+		{ErrOutOfMemory, "out_of_memory"},
+		{ErrKilled, "killed"},
+		{ErrCommandFailed, "command_failed"},
+		{context.Canceled, "canceled"},
+		{context.DeadlineExceeded, "canceled"},
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			return k.kind
+		}
+	}
+	return "other"
+}
+
+// ConvertToFileWithSidecar converts a PDF file to text like ConvertToFile and
+// writes a Sidecar next to the output file (outputPath + SidecarSuffix). The
+// sidecar is also written when the conversion fails, recording the error,
+// which is returned as well.
+func (c *Converter) ConvertToFileWithSidecar(ctx context.Context, inputPath, outputPath string, opts *Options) (*Sidecar, error) {
+	start := time.Now()
+	err := c.ConvertToFile(ctx, inputPath, outputPath, opts)
+	s := &Sidecar{
+		Input:       inputPath,
+		Output:      outputPath,
+		ConvertedAt: time.Now().UTC(),
+		DurationMS:  time.Since(start).Milliseconds(),
+	}
+	if input, hashErr := hashFile(inputPath, SHA256); hashErr == nil {
+		s.InputSHA256 = input.Digest
+	}
+	if err != nil {
+		s.Error, s.ErrorKind = err.Error(), ErrorKind(err)
+	} else if data, readErr := c.readOutput(ctx, outputPath); readErr == nil {
+		s.OutputSHA256 = sha256Hex(data)
+		s.Pages = countPages(data)
+		if q := ScoreQuality(string(data)); q.Words > 0 {
+			s.Quality = &q.Score
+		}
+		if opts != nil && opts.LanguageDetector != nil {
+			var pages []Page
+			for _, text := range strings.Split(string(data), "\f") {
+				pages = append(pages, Page{Text: text, Language: pageLanguage(opts.LanguageDetector, text)})
+			}
+			s.Language = documentLanguage(pages)
+		}
+	}
+
+	if writeErr := writeJSON(ctx, c.output(), outputPath+SidecarSuffix, s); writeErr != nil && err == nil {
+		return s, writeErr
+	}
+	return s, err
+}
+
+// VerifyOutput checks the output file of ConvertToFileWithSidecar against
+// the checksum in its sidecar, returning ErrChecksumMismatch if it was
+// modified or truncated since it was written
+func (c *Converter) VerifyOutput(ctx context.Context, outputPath string) error {
+	data, err := c.readOutput(ctx, outputPath+SidecarSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to read sidecar of %s: %w", outputPath, err)
+	}
+	var s Sidecar
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("failed to decode sidecar of %s: %w", outputPath, err)
+	}
+	if s.OutputSHA256 == "" {
+		return fmt.Errorf("%w: sidecar of %s has no output checksum", ErrChecksumMismatch, outputPath)
+	}
+	output, err := c.readOutput(ctx, outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", outputPath, err)
+	}
+	if sum := sha256Hex(output); sum != s.OutputSHA256 {
+		return fmt.Errorf("%w: %s has SHA-256 %s, expected %s", ErrChecksumMismatch, outputPath, sum, s.OutputSHA256)
+	}
+	return nil
+}
+
+// countPages counts the pages of pdftotext output, each ended by a form feed
+// except possibly the last
+func countPages(data []byte) int {
+	n := bytes.Count(data, []byte("\f"))
+	if i := bytes.LastIndexByte(data, '\f'); len(bytes.TrimSpace(data[i+1:])) > 0 {
+		n++
+	}
+	return n
+}
+
+// CorpusStats are the statistics of a corpus of conversions
+type CorpusStats struct {
+	// Documents is the number of conversions
+	Documents int `json:"documents"`
+	// Failed is the number of failed conversions
+	Failed int `json:"failed"`
+	// FailureRate is Failed divided by Documents
+	FailureRate float64 `json:"failure_rate"`
+	// Failures counts the failed conversions by error kind
+	Failures map[string]int `json:"failures,omitempty"`
+	// Pages is the total number of converted pages
+	Pages int `json:"pages"`
+	// Languages counts the converted documents by language, with "und" for
+	// documents whose language is unknown
+	Languages map[string]int `json:"languages,omitempty"`
+	// Quality is the distribution of the quality scores
+	Quality QualityStats `json:"quality"`
+	// Duration is the total conversion time
+	Duration time.Duration `json:"duration"`
+	// Invalid lists the sidecar files that could not be read
+	Invalid []string `json:"invalid,omitempty"`
+}
+
+// QualityStats is the distribution of the quality scores of a corpus
+type QualityStats struct {
+	// Scored is the number of documents with a quality score
+	Scored int `json:"scored"`
+	// Min, Mean and Max summarize the scores
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+	// Histogram counts the scores in ten buckets of width 0.1
+	Histogram [10]int `json:"histogram"`
+}
+
+// Add adds a conversion to the statistics
+func (cs *CorpusStats) Add(s *Sidecar) {
+	if cs.Failures == nil {
+		cs.Failures = map[string]int{}
+	}
+	if cs.Languages == nil {
+		cs.Languages = map[string]int{}
+	}
+
+	cs.Documents++
+	cs.Duration += time.Duration(s.DurationMS) * time.Millisecond
+	if s.Error != "" {
+		cs.Failed++
+		cs.Failures[cmp.Or(s.ErrorKind, "other")]++
+	} else {
+		cs.Pages += s.Pages
+		cs.Languages[cmp.Or(s.Language, "und")]++
+	}
+	cs.FailureRate = float64(cs.Failed) / float64(cs.Documents)
+
+	if s.Quality != nil && !math.IsNaN(*s.Quality) {
+		q := min(max(*s.Quality, 0), 1)
+		if cs.Quality.Scored == 0 || q < cs.Quality.Min {
+			cs.Quality.Min = q
+		}
+		if cs.Quality.Scored == 0 || q > cs.Quality.Max {
+			cs.Quality.Max = q
+		}
+		cs.Quality.Mean = (cs.Quality.Mean*float64(cs.Quality.Scored) + q) / float64(cs.Quality.Scored+1)
+		cs.Quality.Scored++
+		cs.Quality.Histogram[min(int(q*10), 9)]++
+	}
+}
+
+// AggregateSidecars walks dir and returns the statistics of the sidecars
+// found in it. Sidecars that cannot be decoded are listed in Invalid rather
+// than failing the whole report.
+func AggregateSidecars(ctx context.Context, dir string) (*CorpusStats, error) {
+	stats := &CorpusStats{Failures: map[string]int{}, Languages: map[string]int{}}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, SidecarSuffix) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var s Sidecar
+		if err := json.Unmarshal(data, &s); err != nil {
+			stats.Invalid = append(stats.Invalid, path)
+			return nil
+		}
+		stats.Add(&s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// RedactedStats are corpus statistics that can be shared outside a
+// data-restricted team. They hold only counts and distributions, without
+// file paths or values of single documents, and suppress every group
+// smaller than MinGroupSize.
+type RedactedStats struct {
+	// MinGroupSize is the smallest group reported
+	MinGroupSize int `json:"min_group_size"`
+	// Documents is the number of conversions
+	Documents int `json:"documents"`
+	// Pages is the total number of converted pages
+	Pages int `json:"pages"`
+	// Failed is the number of failed conversions, or 0 if suppressed
+	Failed int `json:"failed"`
+	// FailureRate is Failed divided by Documents, or 0 if suppressed
+	FailureRate float64 `json:"failure_rate"`
+	// Failures counts the failed conversions by error kind, with small
+	// groups merged into "other"
+	Failures map[string]int `json:"failures,omitempty"`
+	// Languages counts the converted documents by language, with small
+	// groups merged into "other"
+	Languages map[string]int `json:"languages,omitempty"`
+	// QualityScored is the number of documents with a quality score
+	QualityScored int `json:"quality_scored"`
+	// QualityMean is the mean quality score, or 0 if suppressed
+	QualityMean float64 `json:"quality_mean"`
+	// QualityHistogram counts the scores in ten buckets of width 0.1, with
+	// small buckets set to 0
+	QualityHistogram [10]int `json:"quality_histogram"`
+	// Suppressed names the statistics left out because their group was too
+	// small
+	Suppressed []string `json:"suppressed,omitempty"`
+}
+
+// Redacted returns the statistics with every group smaller than
+// minGroupSize suppressed. If the whole corpus is smaller, only its size is
+// withheld as well.
+func (cs *CorpusStats) Redacted(minGroupSize int) *RedactedStats {
+	k := max(minGroupSize, 1)
+	r := &RedactedStats{MinGroupSize: k}
+	if cs.Documents < k {
+		r.Suppressed = []string{"documents"}
+		return r
+	}
+	r.Documents, r.Pages = cs.Documents, cs.Pages
+
+	if cs.Failed == 0 || cs.Failed >= k {
+		r.Failed, r.FailureRate = cs.Failed, cs.FailureRate
+	} else {
+		r.Suppressed = append(r.Suppressed, "failed")
+	}
+	var suppressed bool
+	if r.Failures, suppressed = redactGroups(cs.Failures, k); suppressed {
+		r.Suppressed = append(r.Suppressed, "failures")
+	}
+	if r.Languages, suppressed = redactGroups(cs.Languages, k); suppressed {
+		r.Suppressed = append(r.Suppressed, "languages")
+	}
+
+	q := cs.Quality
+	if q.Scored > 0 && q.Scored < k {
+		r.Suppressed = append(r.Suppressed, "quality")
+		return r
+	}
+	r.QualityScored, r.QualityMean = q.Scored, q.Mean
+	for i, n := range q.Histogram {
+		if n >= k {
+			r.QualityHistogram[i] = n
+		} else if n > 0 {
+			suppressed = true
+		}
+	}
+	if suppressed {
+		r.Suppressed = append(r.Suppressed, "quality_histogram")
+	}
+	return r
+}
+
+// redactGroups merges the groups smaller than k into "other", dropping it
+// as well if it is still smaller, and reports whether any group was dropped
+func redactGroups(groups map[string]int, k int) (map[string]int, bool) {
+	out := map[string]int{}
+	other := 0
+	for name, n := range groups {
+		if n >= k && name != "other" {
+			out[name] = n
+		} else {
+			other += n
+		}
+	}
+	if other >= k {
+		out["other"] = other
+	}
+	return out, other > 0 && other < k
+}
@@ -0,0 +1,243 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{fmt.Errorf("%w: wrapped", ErrEncrypted), "encrypted"},
+		{ErrPDFOpen, "pdf_open"},
+		{fmt.Errorf("%w: empty", ErrNotPDF), "not_pdf"},
+		{fmt.Errorf("%w: infected", ErrVetoed), "vetoed"},
+		{context.DeadlineExceeded, "canceled"},
+		{errors.New("boom"), "other"},
+	}
+	for _, tt := range tests {
+		if got := ErrorKind(tt.err); got != tt.expected {
+			t.Errorf("ErrorKind(%v): expected %q, got %q", tt.err, tt.expected, got)
+		}
+	}
+}
+
+func TestCountPages(t *testing.T) {
+	for input, expected := range map[string]int{"": 0, "a\fb\f": 2, "a\fb": 2, "a\f \n": 1} {
+		if got := countPages([]byte(input)); got != expected {
+			t.Errorf("countPages(%q): expected %d, got %d", input, expected, got)
+		}
+	}
+}
+
+func TestConverter_ConvertToFileWithSidecar(t *testing.T) {
+	// pdftotext writes the output file named by the last argument
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		return os.WriteFile(args[len(args)-1], []byte("one\ftwo\fthree\f"), 0o644)
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output.txt")
+
+	s, err := converter.ConvertToFileWithSidecar(context.Background(), "input.pdf", output, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Pages != 3 || s.Error != "" || s.ConvertedAt.IsZero() {
+		t.Errorf("unexpected sidecar %+v", s)
+	}
+	if s.OutputSHA256 != sha256Hex([]byte("one\ftwo\fthree\f")) || s.InputSHA256 != "" {
+		t.Errorf("unexpected checksums %+v", s)
+	}
+	if _, err := os.Stat(output + SidecarSuffix); err != nil {
+		t.Errorf("expected sidecar file: %v", err)
+	}
+	if err := converter.VerifyOutput(context.Background(), output); err != nil {
+		t.Errorf("expected the output to verify, got %v", err)
+	}
+	if err := os.WriteFile(output, []byte("one\ftwo\f"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := converter.VerifyOutput(context.Background(), output); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch for a modified output, got %v", err)
+	}
+
+	failing, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Command Line Error: Incorrect password")
+		return &ExitError{Code: 1}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	s, err = failing.ConvertToFileWithSidecar(context.Background(), "input.pdf", filepath.Join(dir, "failed.txt"), nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if s.Error == "" || s.ErrorKind != "encrypted" {
+		t.Errorf("expected the error in the sidecar, got %+v", s)
+	}
+
+	stats, err := AggregateSidecars(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Documents != 2 || stats.Failed != 1 || stats.Pages != 3 || stats.FailureRate != 0.5 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestAggregateSidecars(t *testing.T) {
+	dir := t.TempDir()
+	score := func(q float64) *float64 { return &q }
+	sidecars := map[string]*Sidecar{
+		"a/one.txt":   {Pages: 10, Language: "en", Quality: score(0.95), DurationMS: 1000},
+		"a/two.txt":   {Pages: 4, Language: "de", Quality: score(0.4), DurationMS: 500},
+		"b/three.txt": {Pages: 2, Quality: score(1)},
+		"b/four.txt":  {Error: "encrypted", ErrorKind: "encrypted"},
+	}
+	for name, s := range sidecars {
+		path := filepath.Join(dir, name+SidecarSuffix)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeJSON(context.Background(), OSFS{}, path, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	invalid := filepath.Join(dir, "b", "broken.txt"+SidecarSuffix)
+	if err := os.WriteFile(invalid, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a", "one.txt"), []byte("not a sidecar"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := AggregateSidecars(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Documents != 4 || stats.Failed != 1 || stats.Pages != 16 || stats.FailureRate != 0.25 {
+		t.Errorf("unexpected totals %+v", stats)
+	}
+	if stats.Failures["encrypted"] != 1 {
+		t.Errorf("expected 1 encrypted failure, got %v", stats.Failures)
+	}
+	if stats.Languages["en"] != 1 || stats.Languages["de"] != 1 || stats.Languages["und"] != 1 {
+		t.Errorf("unexpected languages %v", stats.Languages)
+	}
+
+	q := stats.Quality
+	if q.Scored != 3 || q.Min != 0.4 || q.Max != 1 || q.Histogram[4] != 1 || q.Histogram[9] != 2 {
+		t.Errorf("unexpected quality %+v", q)
+	}
+	if mean := (0.95 + 0.4 + 1) / 3; q.Mean < mean-1e-9 || q.Mean > mean+1e-9 {
+		t.Errorf("expected mean %v, got %v", mean, q.Mean)
+	}
+	if stats.Duration.Seconds() != 1.5 {
+		t.Errorf("expected 1.5s, got %v", stats.Duration)
+	}
+	if len(stats.Invalid) != 1 || stats.Invalid[0] != invalid {
+		t.Errorf("expected invalid %s, got %v", invalid, stats.Invalid)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := AggregateSidecars(ctx, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestCorpusStats_Redacted(t *testing.T) {
+	score := func(q float64) *float64 { return &q }
+	var stats CorpusStats
+	for range 5 {
+		stats.Add(&Sidecar{Pages: 2, Language: "en", Quality: score(0.95)})
+	}
+	for range 3 {
+		stats.Add(&Sidecar{Pages: 1, Language: "de", Quality: score(0.55)})
+	}
+	stats.Add(&Sidecar{Pages: 1, Language: "fr", Quality: score(0.1)})
+	stats.Add(&Sidecar{Error: "encrypted", ErrorKind: "encrypted"})
+	stats.Invalid = []string{"/data/secret.txt" + SidecarSuffix}
+
+	tests := []struct {
+		name       string
+		k          int
+		want       RedactedStats
+		suppressed []string
+	}{
+		{
+			name: "no suppression",
+			k:    1,
+			want: RedactedStats{
+				Documents: 10, Pages: 14, Failed: 1, FailureRate: 0.1,
+				Failures:         map[string]int{"encrypted": 1},
+				Languages:        map[string]int{"en": 5, "de": 3, "fr": 1},
+				QualityScored:    9,
+				QualityHistogram: [10]int{1: 1, 5: 3, 9: 5},
+			},
+		},
+		{
+			name: "small groups merged",
+			k:    3,
+			want: RedactedStats{
+				Documents: 10, Pages: 14,
+				Failures:         map[string]int{},
+				Languages:        map[string]int{"en": 5, "de": 3},
+				QualityScored:    9,
+				QualityHistogram: [10]int{5: 3, 9: 5},
+			},
+			suppressed: []string{"failed", "failures", "languages", "quality_histogram"},
+		},
+		{
+			name: "merged into other",
+			k:    4,
+			want: RedactedStats{
+				Documents: 10, Pages: 14,
+				Failures:         map[string]int{},
+				Languages:        map[string]int{"en": 5, "other": 4},
+				QualityScored:    9,
+				QualityHistogram: [10]int{9: 5},
+			},
+			suppressed: []string{"failed", "failures", "quality_histogram"},
+		},
+		{
+			name:       "small quality",
+			k:          10,
+			want:       RedactedStats{Documents: 10, Pages: 14, Failures: map[string]int{}, Languages: map[string]int{}},
+			suppressed: []string{"failed", "failures", "languages", "quality"},
+		},
+		{
+			name:       "small corpus",
+			k:          11,
+			suppressed: []string{"documents"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stats.Redacted(tt.k)
+			if got.MinGroupSize != tt.k {
+				t.Errorf("expected min group size %d, got %d", tt.k, got.MinGroupSize)
+			}
+			if !slices.Equal(got.Suppressed, tt.suppressed) {
+				t.Errorf("expected suppressed %v, got %v", tt.suppressed, got.Suppressed)
+			}
+			got.MinGroupSize, got.Suppressed, got.QualityMean = 0, nil, 0
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package pdftotext
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DefaultMaxStderr is the default number of bytes of the stderr of a command
+// kept for its error message
+const DefaultMaxStderr = 64 << 10
+
+// WithMaxStderr sets the number of bytes of the stderr of each command kept
+// for error messages (default DefaultMaxStderr). Corrupt files can make
+// poppler print millions of warnings; the lines beyond the limit are only
+// counted, and the error message ends with the number of suppressed lines.
+// Options.OnWarning still receives every line. A size of zero or less
+// removes the limit.
+func WithMaxStderr(size int) ConverterOption {
+	return func(c *Converter) {
+		c.maxStderr = size
+	}
+}
+
+// stderrBuffer keeps the whole lines of stderr up to a limit and counts the
+// lines written beyond it
+type stderrBuffer struct {
+	buf        bytes.Buffer
+	limit      int
+	full       bool
+	suppressed int
+	midLine    bool
+}
+
+// newStderr returns a buffer for the stderr of a command
+func (c *Converter) newStderr() *stderrBuffer {
+	return &stderrBuffer{limit: c.maxStderr}
+}
+
+func (sb *stderrBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if !sb.full {
+		if sb.limit <= 0 || sb.buf.Len()+len(p) <= sb.limit {
+			sb.buf.Write(p)
+			return n, nil
+		}
+		// keep the lines that fit entirely
+		cut := bytes.LastIndexByte(p[:sb.limit-sb.buf.Len()], '\n') + 1
+		sb.buf.Write(p[:cut])
+		p = p[cut:]
+		sb.full = true
+	}
+	if len(p) > 0 {
+		sb.suppressed += bytes.Count(p, []byte("\n"))
+		sb.midLine = p[len(p)-1] != '\n'
+	}
+	return n, nil
+}
+
+// String returns the kept lines, followed by a marker with the number of
+// suppressed lines if the limit was reached
+func (sb *stderrBuffer) String() string {
+	if !sb.full {
+		return sb.buf.String()
+	}
+	suppressed := sb.suppressed
+	if sb.midLine {
+		suppressed++
+	}
+	kept := sb.buf.String()
+	if kept != "" && kept[len(kept)-1] != '\n' {
+		kept += "\n"
+	}
+	return kept + fmt.Sprintf("[%d more lines of stderr suppressed]", suppressed)
+}
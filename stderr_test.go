@@ -0,0 +1,61 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStderrBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		writes   []string
+		expected string
+	}{
+		{"UnderLimit", 100, []string{"a\n", "b\n"}, "a\nb\n"},
+		{"NoLimit", 0, []string{strings.Repeat("x", 1000)}, strings.Repeat("x", 1000)},
+		{"WholeLines", 10, []string{"one\ntwo\nthree\nfour\n"}, "one\ntwo\n[2 more lines of stderr suppressed]"},
+		{"AcrossWrites", 10, []string{"one\n", "two\n", "three\n", "four\n", "five"}, "one\ntwo\n[3 more lines of stderr suppressed]"},
+		{"LongFirstLine", 4, []string{"a very long line\nshort\n"}, "[2 more lines of stderr suppressed]"},
+		{"PartialKeptLine", 6, []string{"abc", "defgh\n"}, "abc\n[1 more lines of stderr suppressed]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &stderrBuffer{limit: tt.limit}
+			for _, w := range tt.writes {
+				if n, err := sb.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("unexpected write result %d, %v", n, err)
+				}
+			}
+			if got := sb.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConverter_MaxStderr(t *testing.T) {
+	var warnings int
+	converter, err := New(WithMaxStderr(64), WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		for range 10000 {
+			io.WriteString(stderr, "Syntax Error (123): Illegal character in content stream\n")
+		}
+		return &ExitError{Code: 1}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	_, err = converter.Convert(context.Background(), "input.pdf", &Options{OnWarning: func(string) { warnings++ }})
+	if !errors.Is(err, ErrPDFOpen) {
+		t.Fatalf("expected ErrPDFOpen, got %v", err)
+	}
+	if len(err.Error()) > 200 || !strings.Contains(err.Error(), "[9999 more lines of stderr suppressed]") {
+		t.Errorf("expected a truncated error message, got %q", err)
+	}
+	if warnings != 10000 {
+		t.Errorf("expected OnWarning to receive every line, got %d", warnings)
+	}
+}
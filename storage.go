@@ -0,0 +1,248 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Storage is a key-value store for the state deployments keep outside the
+// process: the job statuses of a Pool, cache entries with a StorageCache,
+// manifests with SaveManifest, and the files written by ConvertToFile with
+// StorageFS. MemoryCache, DiskCache and the local OutputFS do not use it.
+// Keys are slash-separated paths. Implementations must be safe for
+// concurrent use.
+type Storage interface {
+	// Put stores data under key, replacing any existing value
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns the data stored under key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// List returns the sorted keys starting with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// CreateStorage is a Storage that can store a key only if it does not exist
+// yet, atomically, so the processes sharing it can reserve keys such as the
+// IDs of the jobs of a Pool. DirStorage and MemoryStorage implement it.
+type CreateStorage interface {
+	Storage
+	// Create stores data under key, or returns ErrExists if key exists
+	Create(ctx context.Context, key string, data []byte) error
+}
+
+var (
+	_ CreateStorage = (*DirStorage)(nil)
+	_ CreateStorage = (*MemoryStorage)(nil)
+)
+
+// SaveManifest stores m as JSON under key
+func SaveManifest(ctx context.Context, s Storage, key string, m *Manifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return s.Put(ctx, key, data)
+}
+
+// LoadManifest loads the manifest stored under key
+func LoadManifest(ctx context.Context, s Storage, key string) (*Manifest, error) {
+	data, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %s: %w", key, err)
+	}
+	return &m, nil
+}
+
+// validateKey rejects keys that are empty or escape the storage root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	return nil
+}
+
+// DirStorage is a Storage keeping each key in a file below a local directory
+type DirStorage struct {
+	// Dir is the root directory
+	Dir string
+}
+
+// tempPrefix marks files being written by DirStorage.Put
+const tempPrefix = ".tmp-"
+
+// Put writes data atomically to the file for key
+func (s *DirStorage) Put(_ context.Context, key string, data []byte) error {
+	return s.write(key, data, os.Rename)
+}
+
+// Create writes data atomically to the file for key, or returns ErrExists if
+// the file exists. The file is linked into place, which fails if it exists.
+func (s *DirStorage) Create(_ context.Context, key string, data []byte) error {
+	err := s.write(key, data, os.Link)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%w: %s", ErrExists, key)
+	}
+	return err
+}
+
+// write writes data to a temporary file next to the file for key, and moves
+// it into place with place
+func (s *DirStorage) write(key string, data []byte, place func(oldname, newname string) error) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	name := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(name), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return place(f.Name(), name)
+}
+
+// Get reads the file for key
+func (s *DirStorage) Get(_ context.Context, key string) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return data, err
+}
+
+// Delete removes the file for key
+func (s *DirStorage) Delete(_ context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.Dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List walks the directory for keys starting with prefix
+func (s *DirStorage) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.Dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.Dir, name)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
+
+// MemoryStorage is a Storage keeping everything in memory, useful for tests
+// and single-process deployments
+type MemoryStorage struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// Put stores a copy of data under key
+func (s *MemoryStorage) Put(_ context.Context, key string, data []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		s.data = make(map[string][]byte)
+	}
+	s.data[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Create stores a copy of data under key, or returns ErrExists if key exists
+func (s *MemoryStorage) Create(_ context.Context, key string, data []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[key]; ok {
+		return fmt.Errorf("%w: %s", ErrExists, key)
+	}
+	if s.data == nil {
+		s.data = make(map[string][]byte)
+	}
+	s.data[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Get returns a copy of the data stored under key
+func (s *MemoryStorage) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.data[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Delete removes key
+func (s *MemoryStorage) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// List returns the keys starting with prefix
+func (s *MemoryStorage) List(_ context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var keys []string
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
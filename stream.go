@@ -0,0 +1,207 @@
+package pdftotext
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"strings"
+)
+
+// ConvertStream converts a PDF file to text and returns a reader streaming the
+// text as it is produced. Closing the reader before the end stops the
+// conversion.
+func (c *Converter) ConvertStream(ctx context.Context, inputPath string, opts *Options) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.ConvertTo(ctx, inputPath, pw, opts))
+	}()
+	return &stream{PipeReader: pr, cancel: cancel}
+}
+
+// stream is the reader returned by ConvertStream
+type stream struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+// Close stops the conversion
+func (s *stream) Close() error {
+	s.cancel()
+	return s.PipeReader.Close()
+}
+
+// StreamPages converts a PDF file to text and yields each page as soon as it
+// has been produced, holding only one page in memory. Breaking out of the
+// loop, or opts.StopAfter returning true, stops the conversion.
+//
+//	for page, err := range converter.StreamPages(ctx, "input.pdf", nil) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(page.Number, len(page.Text))
+//	}
+func (c *Converter) StreamPages(ctx context.Context, inputPath string, opts *Options) iter.Seq2[Page, error] {
+	return func(yield func(Page, error) bool) {
+		ctx, opts := applyOverrides(ctx, opts)
+		ctx, err := c.confine(ctx, inputPath, "")
+		if err != nil {
+			yield(Page{}, err)
+			return
+		}
+		pageOpts := Options{}
+		if opts != nil {
+			pageOpts = *opts
+		}
+		pageOpts.NoPageBreaks = false
+		pageOpts.PageSeparator = ""
+		pageOpts.Transformers = nil
+		pageOpts.Normalize = ""
+		pageOpts.PostHooks = nil
+		pageOpts.OCR = nil
+		pageOpts.MinQuality = 0
+		pageOpts.GarbledStrategies = nil
+		pageOpts.StopAfter = nil
+
+		hookOpts, err := runPreHooks(ctx, inputPath, &pageOpts)
+		if err != nil {
+			yield(Page{}, err)
+			return
+		}
+		rangeOpts, err := c.rangeOptions(ctx, inputPath, hookOpts)
+		if err != nil {
+			yield(Page{}, err)
+			return
+		}
+		if opts != nil && opts.StopAfter != nil {
+			next := yield
+			yield = func(page Page, err error) bool {
+				if !next(page, err) {
+					return false
+				}
+				return err != nil || !opts.StopAfter(page.Number, page.Text)
+			}
+		}
+
+		if pageOpts.PageLabels {
+			labels, err := ReadPageLabels(inputPath)
+			if err != nil {
+				yield(Page{}, err)
+				return
+			}
+			next := yield
+			yield = func(page Page, err error) bool {
+				if err == nil {
+					page.Label = labels.Label(page.Number)
+				}
+				return next(page, err)
+			}
+		}
+
+		if ts := transformers(opts); len(ts) > 0 {
+			next := yield
+			yield = func(page Page, err error) bool {
+				if err == nil {
+					if page.Text, err = transform(ts, page.Number, page.Text); err != nil {
+						next(Page{}, err)
+						return false
+					}
+				}
+				return next(page, err)
+			}
+		}
+
+		if opts != nil && opts.LanguageDetector != nil {
+			next := yield
+			yield = func(page Page, err error) bool {
+				if err == nil {
+					page.Language = pageLanguage(opts.LanguageDetector, page.Text)
+				}
+				return next(page, err)
+			}
+		}
+
+		if recoversPages(opts) {
+			next := yield
+			yield = func(page Page, err error) bool {
+				if err == nil {
+					if err = recoverPage(ctx, opts, inputPath, &page); err != nil {
+						next(Page{}, err)
+						return false
+					}
+				}
+				return next(page, err)
+			}
+		}
+
+		for _, o := range rangeOpts {
+			if !c.streamRange(ctx, inputPath, o, yield) {
+				return
+			}
+		}
+	}
+}
+
+// streamRange yields the pages of a single page range and reports whether
+// streaming should continue
+func (c *Converter) streamRange(ctx context.Context, inputPath string, opts *Options, yield func(Page, error) bool) bool {
+	r := c.ConvertStream(ctx, inputPath, opts)
+	defer r.Close()
+
+	br := bufio.NewReaderSize(r, streamBufferSize)
+	number := max(opts.FirstPage, 1)
+	for {
+		text, err := br.ReadString('\f')
+		if err == nil {
+			if !yield(Page{Number: number, Text: strings.TrimSuffix(text, "\f")}, nil) {
+				return false
+			}
+			number++
+			continue
+		}
+
+		// text after the last form feed is a final page without a page break
+		if strings.TrimSpace(text) != "" {
+			if !yield(Page{Number: number, Text: text}, nil) {
+				return false
+			}
+		}
+		if !errors.Is(err, io.EOF) {
+			yield(Page{}, err)
+			return false
+		}
+		return true
+	}
+}
+
+// runStreamed converts a PDF file with StreamPages, which OCRs the pages
+// without text, re-extracts the garbled ones and stops at opts.StopAfter,
+// and writes the pages in order with the page breaks or the separator of opts
+func (c *Converter) runStreamed(ctx context.Context, inputPath, outputPath string, opts *Options, stdout io.Writer) error {
+	return writeOutput(outputPath, stdout, func(w io.Writer) error {
+		var sw *separatorWriter
+		if opts.PageSeparator != "" {
+			sw = &separatorWriter{w: w, separator: opts.PageSeparator}
+			w = sw
+		}
+		for page, err := range c.StreamPages(ctx, inputPath, opts) {
+			if err != nil {
+				return err
+			}
+			if sw != nil {
+				sw.page = page.Number
+			}
+			if _, err := io.WriteString(w, page.Text); err != nil {
+				return err
+			}
+			if !opts.NoPageBreaks || sw != nil {
+				if _, err := io.WriteString(w, "\f"); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
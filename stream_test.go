@@ -0,0 +1,215 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConverter_StreamPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		code          int
+		options       *Options
+		expectedPages []Page
+		expectedError error
+	}{
+		{
+			name:   "Pages",
+			output: "first\n\f\fthird\n\f",
+			expectedPages: []Page{
+				{Number: 1, Text: "first\n"},
+				{Number: 2, Text: ""},
+				{Number: 3, Text: "third\n"},
+			},
+		},
+		{
+			name:          "Page numbers start at first page",
+			output:        "tenth\f",
+			options:       &Options{FirstPage: 10},
+			expectedPages: []Page{{Number: 10, Text: "tenth"}},
+		},
+		{
+			name:          "Truncated output",
+			output:        "first\fsecond without page break",
+			code:          -1,
+			expectedPages: []Page{{Number: 1, Text: "first"}, {Number: 2, Text: "second without page break"}},
+			expectedError: ErrCommandFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(fakeRun(tt.output, "", tt.code)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+
+			var pages []Page
+			var streamErr error
+			for page, err := range converter.StreamPages(context.Background(), "input.pdf", tt.options) {
+				if err != nil {
+					streamErr = err
+					break
+				}
+				pages = append(pages, page)
+			}
+
+			if !slices.Equal(pages, tt.expectedPages) {
+				t.Errorf("expected pages %q, got %q", tt.expectedPages, pages)
+			}
+			if !errors.Is(streamErr, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, streamErr)
+			}
+		})
+	}
+}
+
+func TestConverter_StreamPages_Break(t *testing.T) {
+	stopped := make(chan error, 1)
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		for i := 1; ; i++ {
+			if _, err := fmt.Fprintf(stdout, "page %d\f", i); err != nil {
+				stopped <- err
+				return err
+			}
+		}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	for page := range converter.StreamPages(context.Background(), "input.pdf", nil) {
+		if page.Number == 3 {
+			break
+		}
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected conversion to stop after breaking out of the loop")
+	}
+}
+
+func TestConverter_StopAfter(t *testing.T) {
+	// the runner writes pages until it is killed
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		for i := 1; ; i++ {
+			if _, err := fmt.Fprintf(stdout, "page %d\f", i); err != nil {
+				return err
+			}
+		}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	upper := TransformerFunc(func(page int, text string) (string, error) {
+		return strings.ToUpper(text), nil
+	})
+	opts := &Options{
+		Transformers: []Transformer{upper},
+		StopAfter: func(page int, text string) bool {
+			return text == "PAGE 3"
+		},
+	}
+
+	t.Run("Convert", func(t *testing.T) {
+		text, err := converter.Convert(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text != "PAGE 1\fPAGE 2\fPAGE 3" {
+			t.Errorf("unexpected text %q", text)
+		}
+	})
+
+	t.Run("ConvertPages", func(t *testing.T) {
+		pages, err := converter.ConvertPages(ctx, "input.pdf", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pages) != 3 || pages[2].Text != "PAGE 3" {
+			t.Errorf("expected 3 pages, got %q", pages)
+		}
+	})
+
+	t.Run("StreamPages", func(t *testing.T) {
+		var numbers []int
+		for page, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			numbers = append(numbers, page.Number)
+		}
+		if !slices.Equal(numbers, []int{1, 2, 3}) {
+			t.Errorf("expected pages 1 to 3, got %v", numbers)
+		}
+	})
+
+	t.Run("Command", func(t *testing.T) {
+		if _, err := converter.Command("input.pdf", "-", &Options{StopAfter: opts.StopAfter}); !errors.Is(err, ErrCommandFailed) {
+			t.Errorf("expected ErrCommandFailed, got %v", err)
+		}
+	})
+}
+
+func TestConverter_ConvertTo_NoRetryAfterOutput(t *testing.T) {
+	attempts := 0
+	converter, err := New(
+		WithRetry(3, time.Millisecond),
+		WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+			attempts++
+			io.WriteString(stdout, "partial")
+			return &ExitError{Code: -1}
+		})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	var b strings.Builder
+	if err := converter.ConvertTo(context.Background(), "input.pdf", &b, nil); !errors.Is(err, ErrCommandFailed) {
+		t.Errorf("expected error %v, got %v", ErrCommandFailed, err)
+	}
+	if attempts != 1 || b.String() != "partial" {
+		t.Errorf("expected a single attempt writing %q, got %d attempts writing %q", "partial", attempts, b.String())
+	}
+}
+
+func TestExecRunner_StreamClose(t *testing.T) {
+	binary := writeFakeBinary(t, `while :; do echo "an endless stream of text"; done
+`)
+	converter, err := New(WithBinaryPath(binary))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	r := converter.ConvertStream(context.Background(), "input.pdf", nil)
+	buf := make([]byte, 1024)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("failed to read stream: %v", err)
+	}
+	if !strings.HasPrefix(string(buf), "an endless stream of text\n") {
+		t.Errorf("unexpected stream contents %q", buf)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Close to stop the conversion")
+	}
+}
@@ -0,0 +1,134 @@
+package pdftotext
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Section is a node of the structure tree of a document. The root has no
+// title and level 0, and holds the text before the first heading.
+type Section struct {
+	// Title is the text of the heading
+	Title string `json:"title,omitempty"`
+	// Level is the heading level, 1 for the top-level headings
+	Level int `json:"level"`
+	// Page is the page number of the heading
+	Page int `json:"page,omitempty"`
+	// Box is the bounding box of the heading
+	Box Rect `json:"box"`
+	// Body is the text between the heading and the first subsection, with
+	// blocks separated by blank lines
+	Body string `json:"body,omitempty"`
+	// Sections are the subsections
+	Sections []*Section `json:"sections,omitempty"`
+}
+
+// Structure converts a PDF file with -bbox-layout and returns its structure
+// tree, see BuildStructure
+func (c *Converter) Structure(ctx context.Context, inputPath string, opts *Options) (*Section, error) {
+	pages, err := c.geometry(ctx, inputPath, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return BuildStructure(pages), nil
+}
+
+// numberedHeadingPattern matches headings numbered like "2", "2.1" or "A.3"
+var numberedHeadingPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*|[A-Z](?:\.\d+)+)\.?\s+\p{Lu}`)
+
+const (
+	// headingScale is the smallest ratio of the line height of a heading to
+	// that of the body text
+	headingScale = 1.15
+	// maxHeadingWords is the largest number of words of a heading
+	maxHeadingWords = 15
+)
+
+// BuildStructure classifies the blocks of pages, as produced by
+// -bbox-layout, into headings and body text and nests the headings into a
+// tree. pdftotext reports no fonts per word, so the font size of a line is
+// estimated from its height: blocks of at most three short lines noticeably
+// taller than the body text are headings, ranked by size, and so are single
+// numbered lines such as "2.1 Results", nested by their numbering. Running
+// headers and footers are left out.
+func BuildStructure(pages []PageGeometry) *Section {
+	root := &Section{}
+	stack := []*Section{root}
+	for _, blk := range classifyBlocks(pages) {
+		var lines []string
+		for _, line := range blk.block.Lines {
+			lines = append(lines, line.Text)
+		}
+		if blk.level == 0 {
+			current := stack[len(stack)-1]
+			if current.Body != "" {
+				current.Body += "\n\n"
+			}
+			current.Body += strings.Join(lines, "\n")
+			continue
+		}
+
+		section := &Section{Title: strings.Join(lines, " "), Level: blk.level, Page: blk.page, Box: blk.block.Box}
+		for len(stack) > 1 && stack[len(stack)-1].Level >= blk.level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		parent.Sections = append(parent.Sections, section)
+		stack = append(stack, section)
+	}
+	return root
+}
+
+// classifiedBlock is a block of a page in reading order, without running
+// lines, with its heading level or 0 for body text
+type classifiedBlock struct {
+	page  int
+	block Block
+	level int
+}
+
+// classifyBlocks groups the runs of the layout of pages that are not
+// running lines back into their blocks, see BuildLayout
+func classifyBlocks(pages []PageGeometry) []classifiedBlock {
+	var classified []classifiedBlock
+	last := -1
+	for _, run := range BuildLayout(pages).Runs {
+		if run.Running {
+			continue
+		}
+		if run.Block != last {
+			classified = append(classified, classifiedBlock{page: run.Page, block: Block{Box: run.BlockBox}, level: run.HeadingLevel})
+			last = run.Block
+		}
+		blk := &classified[len(classified)-1]
+		blk.block.Lines = append(blk.block.Lines, Line{Text: run.Text, Box: run.Box})
+	}
+	return classified
+}
+
+// isRunningLine reports whether line on page is one of the running lines
+func isRunningLine(running []RunningLine, page int, line Line) bool {
+	pattern := runningPattern(line.Text)
+	for _, r := range running {
+		if r.Pattern == pattern && slices.Contains(r.Pages, page) {
+			return true
+		}
+	}
+	return false
+}
+
+// Walk calls fn for s and each of its subsections in document order,
+// stopping when fn returns false
+func (s *Section) Walk(fn func(*Section) bool) bool {
+	if !fn(s) {
+		return false
+	}
+	for _, sub := range s.Sections {
+		if !sub.Walk(fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+// linesBlock returns a block of lines of the given height, starting at y
+func linesBlock(y, height float64, lines ...string) Block {
+	block := Block{Box: Rect{XMin: 50, YMin: y, XMax: 500, YMax: y + height*float64(len(lines))}}
+	for i, text := range lines {
+		top := y + height*float64(i)
+		block.Lines = append(block.Lines, Line{Text: text, Box: Rect{XMin: 50, YMin: top, XMax: 500, YMax: top + height}})
+	}
+	return block
+}
+
+// paperPages are the blocks of a short paper with a title, numbered
+// sections and a running footer
+var paperPages = [][]Block{
+	{
+		linesBlock(40, 24, "A Study of Things"),
+		linesBlock(100, 10, "This paper studies things in", "great detail and at length."),
+		linesBlock(140, 14, "1 Introduction"),
+		linesBlock(160, 10, "Things are everywhere and we", "want to know more about them."),
+		linesBlock(200, 10, "1.1 Background"),
+		linesBlock(215, 10, "Earlier work looked at fewer", "things than we do here."),
+		linesBlock(760, 10, "Page 1"),
+	},
+	{
+		linesBlock(40, 14, "2 Results"),
+		linesBlock(60, 10, "We found many things, all of", "them interesting in some way."),
+		linesBlock(760, 10, "Page 2"),
+	},
+}
+
+func TestBuildStructure(t *testing.T) {
+	pages, err := parseBBox(strings.NewReader(blockLayoutOutput(paperPages)), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root := BuildStructure(pages)
+
+	var outline []string
+	root.Walk(func(s *Section) bool {
+		outline = append(outline, strings.Repeat("  ", s.Level)+s.Title)
+		return true
+	})
+	expected := []string{
+		"",
+		"  A Study of Things",
+		"    1 Introduction",
+		"      1.1 Background",
+		"    2 Results",
+	}
+	if strings.Join(outline, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected outline\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(outline, "\n"))
+	}
+
+	title := root.Sections[0]
+	if title.Page != 1 || title.Body != "This paper studies things in\ngreat detail and at length." {
+		t.Errorf("unexpected title section %+v", title)
+	}
+	results := title.Sections[1]
+	if results.Page != 2 || strings.Contains(results.Body, "Page") {
+		t.Errorf("unexpected results section %+v", results)
+	}
+	if background := title.Sections[0].Sections[0]; background.Body != "Earlier work looked at fewer\nthings than we do here." {
+		t.Errorf("unexpected background body %q", background.Body)
+	}
+}
+
+func TestConverter_Structure(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, blockLayoutOutput(paperPages))
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	root, err := converter.Structure(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(root.Sections) != 1 || root.Sections[0].Title != "A Study of Things" {
+		t.Errorf("unexpected structure %+v", root)
+	}
+}
@@ -0,0 +1,193 @@
+package pdftotext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SyncChange is how a file changed since the last SyncDir
+type SyncChange int
+
+const (
+	// SyncAdded is a new file
+	SyncAdded SyncChange = iota + 1
+	// SyncUpdated is a file whose contents changed
+	SyncUpdated
+	// SyncDeleted is a file that no longer exists
+	SyncDeleted
+)
+
+// String returns "added", "updated" or "deleted"
+func (c SyncChange) String() string {
+	switch c {
+	case SyncAdded:
+		return "added"
+	case SyncUpdated:
+		return "updated"
+	case SyncDeleted:
+		return "deleted"
+	}
+	return "SyncChange(" + fmt.Sprint(int(c)) + ")"
+}
+
+// SyncResult is a file SyncDir found added, updated or deleted
+type SyncResult struct {
+	// Path is the path of the file
+	Path string `json:"path"`
+	// Change is how the file changed
+	Change SyncChange `json:"change"`
+	// Digest is the hex encoded content hash of the file, with the hash
+	// algorithm of the converter, empty for deleted files
+	Digest string `json:"digest,omitempty"`
+	// Text is the converted text of an added or updated file
+	Text string `json:"text,omitempty"`
+	// Err is the error of a failed conversion. The file is reported again by
+	// the next SyncDir.
+	Err error `json:"-"`
+}
+
+// SyncReport is the result of SyncDir
+type SyncReport struct {
+	// Results are the added, updated and deleted files sorted by path
+	Results []SyncResult `json:"results"`
+	// Unchanged is the number of files that did not change
+	Unchanged int `json:"unchanged"`
+}
+
+// syncPrefix prefixes the storage keys of the file states of SyncDir
+const syncPrefix = "sync/"
+
+// syncState is the state of a file kept by SyncDir
+type syncState struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Digest  string    `json:"digest"`
+}
+
+// SyncDir converts the PDF files below dir that were added or changed since
+// the last SyncDir with the same store and reports the files that were
+// deleted, the core loop of an indexing daemon. The size, modification time
+// and content hash of every converted file are kept in store under
+// "sync/<relative path>", so store should hold the state of one directory.
+// Files whose size and modification time are unchanged are not read, and
+// files that were only touched are not converted again. A file that fails to
+// convert is reported with its error and retried by the next SyncDir.
+func (c *Converter) SyncDir(ctx context.Context, dir string, store Storage, opts *Options) (*SyncReport, error) {
+	seen := map[string]bool{}
+	report := &SyncReport{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".pdf") {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		key := syncPrefix + filepath.ToSlash(rel)
+		seen[key] = true
+
+		result, err := c.syncFile(ctx, path, key, d, store, opts)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			report.Unchanged++
+		} else {
+			report.Results = append(report.Results, *result)
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	keys, err := store.List(ctx, syncPrefix)
+	if err != nil {
+		return report, err
+	}
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			return report, err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(key, syncPrefix)))
+		report.Results = append(report.Results, SyncResult{Path: path, Change: SyncDeleted})
+	}
+	slices.SortFunc(report.Results, func(a, b SyncResult) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return report, nil
+}
+
+// syncFile converts the file at path if it changed since the state stored
+// under key and returns its result, or nil if it is unchanged
+func (c *Converter) syncFile(ctx context.Context, path, key string, d fs.DirEntry, store Storage, opts *Options) (*SyncResult, error) {
+	info, err := d.Info()
+	if err != nil {
+		return nil, err
+	}
+	state := syncState{Size: info.Size(), ModTime: info.ModTime().UTC()}
+
+	var old *syncState
+	data, err := store.Get(ctx, key)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		old = &syncState{}
+		if err := json.Unmarshal(data, old); err != nil {
+			return nil, fmt.Errorf("invalid sync state %s: %w", key, err)
+		}
+		if old.Size == state.Size && old.ModTime.Equal(state.ModTime) {
+			return nil, nil
+		}
+	}
+
+	result := &SyncResult{Path: path, Change: SyncAdded}
+	artifact, err := hashFile(path, c.hash)
+	if err != nil {
+		result.Err = err
+		return result, nil
+	}
+	state.Digest, result.Digest = artifact.Digest, artifact.Digest
+	if old != nil {
+		if old.Digest == state.Digest {
+			// only touched, the new modification time saves reading it again
+			return nil, c.putSyncState(ctx, store, key, state)
+		}
+		result.Change = SyncUpdated
+	}
+
+	if result.Text, result.Err = c.Convert(ctx, path, opts); result.Err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return result, nil
+	}
+	return result, c.putSyncState(ctx, store, key, state)
+}
+
+// putSyncState stores the state of a file under key
+func (c *Converter) putSyncState(ctx context.Context, store Storage, key string, state syncState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, key, data)
+}
@@ -0,0 +1,128 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConverter_SyncDir(t *testing.T) {
+	var conversions atomic.Int32
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		conversions.Add(1)
+		data, err := os.ReadFile(args[len(args)-2])
+		if err != nil {
+			return err
+		}
+		if string(data) == "broken" {
+			io.WriteString(stderr, "Syntax Error: Couldn't read xref table")
+			return errors.New("exit status 1")
+		}
+		_, err = stdout.Write(data)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.pdf", "alpha")
+	write("sub/b.PDF", "beta")
+	write("notes.txt", "not a pdf")
+	store := &MemoryStorage{}
+	ctx := context.Background()
+
+	// sync runs SyncDir and returns the changes as "change path text"
+	sync := func(t *testing.T) ([]string, int) {
+		t.Helper()
+		report, err := converter.SyncDir(ctx, dir, store, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var changes []string
+		for _, r := range report.Results {
+			rel, _ := filepath.Rel(dir, r.Path)
+			change := r.Change.String() + " " + filepath.ToSlash(rel) + " " + r.Text
+			if r.Err != nil {
+				change += "error"
+			}
+			changes = append(changes, change)
+		}
+		return changes, report.Unchanged
+	}
+
+	tests := []struct {
+		name        string
+		prepare     func()
+		changes     []string
+		unchanged   int
+		conversions int32
+	}{
+		{
+			name:        "Initial",
+			changes:     []string{"added a.pdf alpha", "added sub/b.PDF beta"},
+			conversions: 2,
+		},
+		{
+			name:      "Unchanged",
+			unchanged: 2,
+		},
+		{
+			name: "Touched",
+			prepare: func() {
+				later := time.Now().Add(time.Hour)
+				os.Chtimes(filepath.Join(dir, "a.pdf"), later, later)
+			},
+			unchanged: 2,
+		},
+		{
+			name: "Updated, added and deleted",
+			prepare: func() {
+				write("sub/b.PDF", "beta 2")
+				write("c.pdf", "broken")
+				os.Remove(filepath.Join(dir, "a.pdf"))
+			},
+			changes:     []string{"deleted a.pdf ", "added c.pdf error", "updated sub/b.PDF beta 2"},
+			conversions: 2,
+		},
+		{
+			name:        "Failed conversion retried",
+			changes:     []string{"added c.pdf error"},
+			unchanged:   1,
+			conversions: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+			conversions.Store(0)
+			changes, unchanged := sync(t)
+			if !slices.Equal(changes, tt.changes) {
+				t.Errorf("expected changes %q, got %q", tt.changes, changes)
+			}
+			if unchanged != tt.unchanged {
+				t.Errorf("expected %d unchanged files, got %d", tt.unchanged, unchanged)
+			}
+			if n := conversions.Load(); n != tt.conversions {
+				t.Errorf("expected %d conversions, got %d", tt.conversions, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package pdftotext
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Table is a table found on a page
+type Table struct {
+	// Page is the page number in the PDF file
+	Page int `json:"page"`
+	// Box is the bounding box of the table
+	Box Rect `json:"box"`
+	// Columns are the horizontal extents of the columns, from left to right
+	Columns []Rect `json:"columns"`
+	// Rows are the cells of each row, one per column, empty where a row has
+	// no text in a column
+	Rows [][]string `json:"rows"`
+}
+
+// WriteCSV writes the rows of the table to w as CSV
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// Tables converts a PDF file with -tsv and returns the tables found in it,
+// see FindTables
+func (c *Converter) Tables(ctx context.Context, inputPath string, opts *Options) ([]Table, error) {
+	rows, err := c.ConvertTSV(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return FindTables(rows), nil
+}
+
+// maxCellWords is the largest mean number of words per cell of a table, so
+// the lines of multi-column prose are not taken for table rows
+const maxCellWords = 4
+
+// FindTables finds the tables in pdftotext -tsv output. The words of each
+// page are grouped into visual lines, which are split into cells at gaps
+// wider than the line height. Consecutive lines with at least two cells form
+// a table if the cells cluster into at least two aligned columns, whether
+// left aligned, right aligned or centered.
+func FindTables(rows []TSVRow) []Table {
+	var tables []Table
+	var words []TSVRow
+	for i, row := range rows {
+		if row.Level == TSVWord && strings.TrimSpace(row.Text) != "" {
+			words = append(words, row)
+		}
+		if i == len(rows)-1 || rows[i+1].Page != row.Page {
+			tables = append(tables, pageTables(row.Page, words)...)
+			words = nil
+		}
+	}
+	return tables
+}
+
+// tableCell is a run of words on a line
+type tableCell struct {
+	box   Rect
+	words []string
+}
+
+// tableLine is a visual line of a page split into cells
+type tableLine struct {
+	box   Rect
+	cells []tableCell
+}
+
+// pageTables finds the tables among the words of a page
+func pageTables(page int, words []TSVRow) []Table {
+	var tables []Table
+	var region []tableLine
+	flush := func() {
+		if t, ok := regionTable(page, region); ok {
+			tables = append(tables, t)
+		}
+		region = nil
+	}
+	for _, line := range visualLines(words) {
+		height := line.box.YMax - line.box.YMin
+		if len(line.cells) < 2 {
+			flush()
+			continue
+		}
+		if n := len(region); n > 0 && line.box.YMin-region[n-1].box.YMax > 1.5*height {
+			flush()
+		}
+		region = append(region, line)
+	}
+	flush()
+	return tables
+}
+
+// visualLines groups words into lines by their vertical position, from top
+// to bottom, and splits each line into cells
+func visualLines(words []TSVRow) []tableLine {
+	sorted := slices.Clone(words)
+	slices.SortStableFunc(sorted, func(a, b TSVRow) int {
+		_, ya := a.Box.Center()
+		_, yb := b.Box.Center()
+		return cmp.Compare(ya, yb)
+	})
+
+	var groups [][]TSVRow
+	var box Rect
+	for _, word := range sorted {
+		if _, y := word.Box.Center(); len(groups) > 0 && y >= box.YMin && y <= box.YMax {
+			groups[len(groups)-1] = append(groups[len(groups)-1], word)
+			box = unionRect(box, word.Box)
+			continue
+		}
+		groups = append(groups, []TSVRow{word})
+		box = word.Box
+	}
+
+	lines := make([]tableLine, 0, len(groups))
+	for _, group := range groups {
+		slices.SortStableFunc(group, func(a, b TSVRow) int { return cmp.Compare(a.Box.XMin, b.Box.XMin) })
+		var heights []float64
+		for _, word := range group {
+			heights = append(heights, word.Box.YMax-word.Box.YMin)
+		}
+		height := median(heights)
+
+		line := tableLine{box: group[0].Box}
+		for i, word := range group {
+			line.box = unionRect(line.box, word.Box)
+			if i == 0 || word.Box.XMin-group[i-1].Box.XMax > height {
+				line.cells = append(line.cells, tableCell{box: word.Box})
+			}
+			cell := &line.cells[len(line.cells)-1]
+			cell.box = unionRect(cell.box, word.Box)
+			cell.words = append(cell.words, word.Text)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// regionTable builds a table from consecutive lines with several cells, if
+// their cells align into columns
+func regionTable(page int, lines []tableLine) (Table, bool) {
+	if len(lines) < 2 {
+		return Table{}, false
+	}
+
+	// the columns are the overlapping horizontal extents of the cells
+	var cells []Rect
+	words := 0
+	for _, line := range lines {
+		for _, cell := range line.cells {
+			cells = append(cells, cell.box)
+			words += len(cell.words)
+		}
+	}
+	if words > maxCellWords*len(cells) {
+		return Table{}, false
+	}
+	slices.SortFunc(cells, func(a, b Rect) int { return cmp.Compare(a.XMin, b.XMin) })
+	var columns []Rect
+	for _, cell := range cells {
+		if n := len(columns); n > 0 && cell.XMin <= columns[n-1].XMax {
+			columns[n-1] = unionRect(columns[n-1], cell)
+			continue
+		}
+		columns = append(columns, cell)
+	}
+	if len(columns) < 2 {
+		return Table{}, false
+	}
+
+	t := Table{Page: page, Box: lines[0].box, Columns: columns}
+	for _, line := range lines {
+		t.Box = unionRect(t.Box, line.box)
+		row := make([]string, len(columns))
+		for _, cell := range line.cells {
+			i := slices.IndexFunc(columns, func(c Rect) bool { return cell.box.XMin <= c.XMax })
+			text := strings.Join(cell.words, " ")
+			if row[i] != "" {
+				text = row[i] + " " + text
+			}
+			row[i] = text
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	for i := range t.Columns {
+		t.Columns[i].YMin, t.Columns[i].YMax = t.Box.YMin, t.Box.YMax
+	}
+	return t, true
+}
+
+// unionRect returns the smallest rectangle containing a and b
+func unionRect(a, b Rect) Rect {
+	return Rect{
+		XMin: min(a.XMin, b.XMin),
+		YMin: min(a.YMin, b.YMin),
+		XMax: max(a.XMax, b.XMax),
+		YMax: max(a.YMax, b.YMax),
+	}
+}
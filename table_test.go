@@ -0,0 +1,96 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tsvLines returns -tsv output for pages of lines, each line a y position
+// and its words at x positions, with words 10 points high
+func tsvLines(pages ...map[float64]map[float64]string) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(tsvColumns, "\t") + "\n")
+	for i, lines := range pages {
+		fmt.Fprintf(&b, "1\t%d\t0\t0\t0\t0\t0\t0\t612\t792\t-1\t###PAGE###\n", i+1)
+		for y, words := range lines {
+			for x, text := range words {
+				width := 6 * float64(len(text))
+				fmt.Fprintf(&b, "5\t%d\t0\t0\t0\t0\t%g\t%g\t%g\t10\t100\t%s\n", i+1, x, y, width, text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// invoicePage has a paragraph, a table with right-aligned numbers and a
+// footer line
+var invoicePage = map[float64]map[float64]string{
+	50:  {50: "Invoice", 104: "for", 128: "services"},
+	100: {50: "Item", 200: "Qty", 300: "Price"},
+	115: {50: "Widget", 206: "2", 300: "$10.00"},
+	130: {50: "Large", 86: "gadget", 200: "10", 306: "$5.00"},
+	145: {50: "Bolt", 206: "1", 312: "$.50"},
+	200: {50: "Thank", 90: "you"},
+}
+
+// prosePage has two columns of prose, whose lines are not table rows
+var prosePage = map[float64]map[float64]string{
+	100: {50: "The", 74: "quick", 110: "brown", 146: "fox", 170: "jumps", 330: "over", 360: "the", 384: "lazy", 414: "dog", 438: "today"},
+	115: {50: "and", 74: "then", 104: "runs", 134: "far", 158: "away", 330: "into", 360: "the", 384: "deep", 414: "dark", 444: "woods"},
+}
+
+func TestFindTables(t *testing.T) {
+	rows, err := parseTSV(strings.NewReader(tsvLines(prosePage, invoicePage)), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tables := FindTables(rows)
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %+v", tables)
+	}
+	table := tables[0]
+	expected := [][]string{
+		{"Item", "Qty", "Price"},
+		{"Widget", "2", "$10.00"},
+		{"Large gadget", "10", "$5.00"},
+		{"Bolt", "1", "$.50"},
+	}
+	if table.Page != 2 || !reflect.DeepEqual(table.Rows, expected) {
+		t.Errorf("expected rows %q on page 2, got %q on page %d", expected, table.Rows, table.Page)
+	}
+	if len(table.Columns) != 3 || table.Box != (Rect{XMin: 50, YMin: 100, XMax: 336, YMax: 155}) {
+		t.Errorf("unexpected geometry %+v %+v", table.Box, table.Columns)
+	}
+
+	var csv bytes.Buffer
+	if err := table.WriteCSV(&csv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Item,Qty,Price\nWidget,2,$10.00\nLarge gadget,10,$5.00\nBolt,1,$.50\n"; csv.String() != want {
+		t.Errorf("expected csv %q, got %q", want, csv.String())
+	}
+}
+
+func TestConverter_Tables(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		_, err := io.WriteString(stdout, tsvLines(invoicePage))
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tables, err := converter.Tables(context.Background(), "input.pdf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tables) != 1 || len(tables[0].Rows) != 4 {
+		t.Errorf("expected a table with 4 rows, got %+v", tables)
+	}
+}
@@ -0,0 +1,129 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WithTempDir sets the directory the PDFs read from an io.Reader and the
+// copies staged by sessions and health checks are written to, instead of
+// the default temporary directory, e.g. a dedicated volume when /tmp is
+// small. The files are removed when the call using them returns, also when
+// it fails, is canceled or panics.
+func WithTempDir(dir string) ConverterOption {
+	return func(c *Converter) {
+		c.tempDir = dir
+	}
+}
+
+// WithMemoryStaging stages the PDFs read from an io.Reader and the copies
+// of sessions in anonymous memory files created with memfd_create instead of
+// temporary files, on Linux on amd64 and arm64, avoiding disk I/O and
+// leftover files for services converting many small PDFs. pdftotext opens
+// them through /proc, so it only applies with ExecRunner; elsewhere and when
+// memfd_create fails the temporary files of WithTempDir are used.
+func WithMemoryStaging() ConverterOption {
+	return func(c *Converter) {
+		c.memoryStaging = true
+	}
+}
+
+// errMemFileUnsupported is returned by createMemFile on platforms without
+// memfd_create
+var errMemFileUnsupported = errors.New("memory files are not supported on this platform")
+
+// createTemp creates a new temporary file in the temporary directory of c
+func (c *Converter) createTemp(pattern string) (*os.File, error) {
+	return os.CreateTemp(c.tempDir, pattern)
+}
+
+// stageReader copies r to a new temporary PDF file, or a memory file with
+// WithMemoryStaging, and returns its path and a function removing it. The
+// copy stops when ctx is done, and if it fails more than limit bytes are
+// read, with limit 0 for no limit. The file is removed unless the copy
+// succeeds.
+func (c *Converter) stageReader(ctx context.Context, r io.Reader, limit int64) (path string, remove func() error, err error) {
+	if c.memoryStaging {
+		if _, ok := c.runner.(ExecRunner); ok {
+			if f, err := createMemFile("pdftotext.pdf"); err == nil {
+				return c.stageMemFile(ctx, f, r, limit)
+			}
+		}
+	}
+	f, err := c.createTemp("pdftotext-*.pdf")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	staged := false
+	defer func() {
+		// also runs when r panics
+		if !staged {
+			os.Remove(f.Name())
+		}
+	}()
+
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	n, err := io.Copy(f, contextReader{ctx, r})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err := stageError(ctx, err, n, limit); err != nil {
+		return "", nil, err
+	}
+	staged = true
+	path = f.Name()
+	return path, func() error { return os.Remove(path) }, nil
+}
+
+// stageMemFile copies r to the memory file f, which is closed to remove it
+func (c *Converter) stageMemFile(ctx context.Context, f *os.File, r io.Reader, limit int64) (path string, remove func() error, err error) {
+	staged := false
+	defer func() {
+		// also runs when r panics
+		if !staged {
+			f.Close()
+		}
+	}()
+
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	n, err := io.Copy(f, contextReader{ctx, r})
+	if err := stageError(ctx, err, n, limit); err != nil {
+		return "", nil, err
+	}
+	staged = true
+	return memFilePath(f), f.Close, nil
+}
+
+// stageError returns the error of staging n bytes, copied with err
+func stageError(ctx context.Context, err error, n, limit int64) error {
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if limit > 0 && n > limit {
+		return fmt.Errorf("%w: the input exceeds the maximum of %d bytes", ErrInputTooLarge, limit)
+	}
+	return nil
+}
+
+// contextReader is a reader failing with the error of ctx once it is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
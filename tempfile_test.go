@@ -0,0 +1,114 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// panicReader panics when it is read
+type panicReader struct{}
+
+func (panicReader) Read([]byte) (int, error) {
+	panic("read failed")
+}
+
+func TestConverter_WithTempDir(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		input         io.Reader
+		options       *Options
+		runErr        error
+		panics        bool
+		expectedError error
+	}{
+		{
+			name:  "Success",
+			input: strings.NewReader(labeledPDF),
+		},
+		{
+			name:          "Failed conversion",
+			input:         strings.NewReader(labeledPDF),
+			runErr:        &ExitError{Code: 1},
+			expectedError: ErrPDFOpen,
+		},
+		{
+			name:          "Input too large",
+			input:         strings.NewReader(labeledPDF),
+			options:       &Options{MaxInputBytes: 16},
+			expectedError: ErrInputTooLarge,
+		},
+		{
+			name:          "Canceled",
+			ctx:           canceled,
+			input:         strings.NewReader(labeledPDF),
+			expectedError: context.Canceled,
+		},
+		{
+			name:   "Panicking reader",
+			input:  panicReader{},
+			panics: true,
+		},
+		{
+			name:   "Panicking runner",
+			input:  strings.NewReader(labeledPDF),
+			panics: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			converter, err := New(WithTempDir(dir), WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+				if filepath.Dir(args[len(args)-2]) != dir {
+					t.Errorf("expected the input in %s, got %s", dir, args[len(args)-2])
+				}
+				if tt.panics {
+					panic("conversion failed")
+				}
+				if tt.runErr != nil {
+					return tt.runErr
+				}
+				_, err := io.WriteString(stdout, "text")
+				return err
+			})))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.panics {
+						t.Errorf("expected panic %v, got %v", tt.panics, r)
+					}
+				}()
+				text, err := converter.ConvertReader(ctx, tt.input, tt.options)
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error %v, got %v", tt.expectedError, err)
+				}
+				if err == nil && text != "text" {
+					t.Errorf("expected %q, got %q", "text", text)
+				}
+			}()
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("failed to read temporary directory: %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("expected the temporary file to be removed, got %v", entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package pdftotext
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// TextStats are simple statistics of a text
+type TextStats struct {
+	// Chars is the number of characters
+	Chars int `json:"chars"`
+	// Words is the number of whitespace separated words
+	Words int `json:"words"`
+	// Lines is the number of non-empty lines
+	Lines int `json:"lines"`
+}
+
+// ComputeTextStats returns the statistics of text
+func ComputeTextStats(text string) TextStats {
+	stats := TextStats{Chars: len([]rune(text)), Words: len(strings.Fields(text))}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			stats.Lines++
+		}
+	}
+	return stats
+}
+
+// TemplateDocument is the data an output template is executed with
+type TemplateDocument struct {
+	// Path is the path of the PDF file
+	Path string
+	// Metadata is the document metadata, if known
+	Metadata map[string]string
+	// PageCount is the number of converted pages
+	PageCount int
+	// Pages are the converted pages
+	Pages []TemplatePage
+	// Stats are the statistics of the whole document
+	Stats TextStats
+}
+
+// TemplatePage is the data of a page in a TemplateDocument
+type TemplatePage struct {
+	// Number is the page number in the PDF file
+	Number int
+	// Text is the extracted text of the page
+	Text string
+	// Stats are the statistics of the page
+	Stats TextStats
+}
+
+// Render executes tmpl with the document and writes the result to w. The
+// template receives a TemplateDocument and ranges over .Pages to render each
+// page, e.g.
+//
+//	{{range .Pages}}--- Page {{.Number}} of {{$.PageCount}} ---
+//	{{.Text}}{{end}}
+func (d *Document) Render(w io.Writer, tmpl *template.Template) error {
+	data := TemplateDocument{
+		Path:      d.Path,
+		Metadata:  d.Metadata,
+		PageCount: len(d.Pages),
+		Pages:     make([]TemplatePage, len(d.Pages)),
+		Stats:     ComputeTextStats(d.Text()),
+	}
+	for i, page := range d.Pages {
+		data.Pages[i] = TemplatePage{Number: page.Number, Text: page.Text, Stats: ComputeTextStats(page.Text)}
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	return nil
+}
+
+// ConvertToFileWithTemplate converts a PDF file to text and writes it to the
+// output file rendered with tmpl, see Document.Render
+func (c *Converter) ConvertToFileWithTemplate(ctx context.Context, inputPath, outputPath string, opts *Options, tmpl *template.Template) error {
+	doc, err := c.ConvertDocument(ctx, inputPath, opts)
+	if err != nil {
+		return err
+	}
+
+	return c.createOutput(ctx, outputPath, func(f io.Writer) error {
+		w := bufio.NewWriter(f)
+		if err := doc.Render(w, tmpl); err != nil {
+			return err
+		}
+		return w.Flush()
+	})
+}
@@ -0,0 +1,80 @@
+package pdftotext
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestDocument_Render(t *testing.T) {
+	doc := &Document{
+		Path:     "report.pdf",
+		Metadata: map[string]string{"Title": "Annual Report"},
+		Pages: []Page{
+			{Number: 1, Text: "Hello world\n"},
+			{Number: 2, Text: "Goodbye\nworld\n"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "Per page",
+			template: "{{range .Pages}}--- Page {{.Number}} of {{$.PageCount}} ({{.Stats.Words}} words) ---\n{{.Text}}{{end}}",
+			expected: "--- Page 1 of 2 (2 words) ---\nHello world\n--- Page 2 of 2 (2 words) ---\nGoodbye\nworld\n",
+		},
+		{
+			name:     "Document",
+			template: "# {{.Metadata.Title}} ({{.Path}}, {{.Stats.Lines}} lines)",
+			expected: "# Annual Report (report.pdf, 3 lines)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("output").Parse(tt.template))
+
+			var b strings.Builder
+			if err := doc.Render(&b, tmpl); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if b.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertToFileWithTemplate(t *testing.T) {
+	converter, err := New(WithRunner(fakeRun("first\f\fthird\f", "", 0)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	tmpl := template.Must(template.New("output").Parse("{{range .Pages}}[{{.Number}}] {{.Text}}\n{{end}}"))
+	outputPath := filepath.Join(t.TempDir(), "output.txt")
+
+	if err := converter.ConvertToFileWithTemplate(context.Background(), "input.pdf", outputPath, nil, tmpl); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if expected := "[1] first\n[2] \n[3] third\n"; string(content) != expected {
+		t.Errorf("expected %q, got %q", expected, content)
+	}
+}
+
+func TestComputeTextStats(t *testing.T) {
+	stats := ComputeTextStats("Grüße aus\n\n  Köln \n")
+	if stats != (TextStats{Chars: 19, Words: 3, Lines: 2}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
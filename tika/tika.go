@@ -0,0 +1,140 @@
+// Package tika serves a subset of the Apache Tika server HTTP API backed by
+// pdftotext, so clients built against a Tika server can switch to a lighter
+// poppler based service without changes.
+//
+// Supported endpoints:
+//
+//	GET  /tika         greeting, commonly used as a health check
+//	GET  /version      server version
+//	PUT  /tika         extracted text (text/plain, or application/json)
+//	PUT  /rmeta        recursive metadata and content as JSON
+//	PUT  /rmeta/text   same as /rmeta
+package tika
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// Version is reported by GET /version
+const Version = "pdftotext Tika compatibility server"
+
+// Handler is an http.Handler implementing the Tika endpoints
+type Handler struct {
+	converter *pdftotext.Converter
+	opts      *pdftotext.Options
+	mux       *http.ServeMux
+	logger    *slog.Logger
+}
+
+// NewHandler returns a Handler converting uploaded documents with converter
+// and opts. Passwords sent in Tika's "Password" header override opts. Failed
+// conversions are answered with the status text only, since the error holds
+// temporary paths and the stderr of pdftotext, and logged with the default
+// slog logger.
+func NewHandler(converter *pdftotext.Converter, opts *pdftotext.Options) *Handler {
+	h := &Handler{converter: converter, opts: opts, mux: http.NewServeMux(), logger: slog.Default()}
+	h.mux.HandleFunc("GET /tika", h.greeting)
+	h.mux.HandleFunc("GET /version", h.version)
+	h.mux.HandleFunc("PUT /tika", h.tika)
+	h.mux.HandleFunc("PUT /rmeta", h.rmeta)
+	h.mux.HandleFunc("PUT /rmeta/{handler}", h.rmeta)
+	return h
+}
+
+// ServeHTTP dispatches the request to the matching endpoint
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) greeting(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, "This is Tika Server (pdftotext). Please PUT\n")
+}
+
+func (h *Handler) version(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, Version)
+}
+
+func (h *Handler) tika(w http.ResponseWriter, r *http.Request) {
+	text, _, ok := h.convert(w, r)
+	if !ok {
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		writeJSON(w, map[string]any{"X-TIKA:content": text})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	fmt.Fprint(w, text)
+}
+
+func (h *Handler) rmeta(w http.ResponseWriter, r *http.Request) {
+	text, elapsed, ok := h.convert(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, []map[string]any{{
+		"Content-Type":             "application/pdf",
+		"X-TIKA:Parsed-By":         []string{"org.apache.tika.parser.DefaultParser", "pdftotext"},
+		"X-TIKA:parse_time_millis": strconv.FormatInt(elapsed.Milliseconds(), 10),
+		"X-TIKA:content":           text,
+		"X-TIKA:content_handler":   "ToTextContentHandler",
+		"X-TIKA:embedded_depth":    "0",
+		"resourceName":             resourceName(r),
+		"Content-Length":           strconv.FormatInt(r.ContentLength, 10),
+	}})
+}
+
+// convert converts the request body, writing an error response on failure
+func (h *Handler) convert(w http.ResponseWriter, r *http.Request) (string, time.Duration, bool) {
+	opts := &pdftotext.Options{}
+	if h.opts != nil {
+		*opts = *h.opts
+	}
+	if password := r.Header.Get("Password"); password != "" {
+		opts.UserPassword = password
+		opts.OwnerPassword = password
+	}
+
+	start := time.Now()
+	text, err := h.converter.ConvertReader(r.Context(), r.Body, opts)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, pdftotext.ErrInputTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, pdftotext.ErrPDFOpen) || errors.Is(err, pdftotext.ErrPermissions):
+			// Tika answers documents it cannot parse with 422
+			status = http.StatusUnprocessableEntity
+		}
+		h.logger.ErrorContext(r.Context(), "failed to convert document", "path", r.URL.Path, "status", status, "error", err)
+		http.Error(w, http.StatusText(status), status)
+		return "", 0, false
+	}
+	return text, time.Since(start), true
+}
+
+// resourceName returns the file name the client sent, if any
+func resourceName(r *http.Request) string {
+	if name := r.Header.Get("File-Name"); name != "" {
+		return name
+	}
+	return r.Header.Get("resourceName")
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,204 @@
+package tika
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// fakeRunner answers like pdftotext, requiring the user password "secret"
+// when the uploaded document is "encrypted"
+type fakeRunner struct{}
+
+func (fakeRunner) Run(_ context.Context, _ string, args []string, stdout, stderr io.Writer) error {
+	data, err := readInput(args)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(data, "encrypted") && !containsPair(args, "-upw", "secret") {
+		io.WriteString(stderr, "Command Line Error: Incorrect password")
+		return &pdftotext.ExitError{Code: 1}
+	}
+	io.WriteString(stdout, "Hello from "+data+"\f")
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	server := httptest.NewServer(NewHandler(converter, nil))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		headers        map[string]string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Greeting",
+			method:         http.MethodGet,
+			path:           "/tika",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "This is Tika Server",
+		},
+		{
+			name:           "Plain text",
+			method:         http.MethodPut,
+			path:           "/tika",
+			body:           "report",
+			headers:        map[string]string{"Accept": "text/plain"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Hello from report",
+		},
+		{
+			name:           "JSON",
+			method:         http.MethodPut,
+			path:           "/tika",
+			body:           "report",
+			headers:        map[string]string{"Accept": "application/json"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"X-TIKA:content":"Hello from report"}`,
+		},
+		{
+			name:           "Encrypted without password",
+			method:         http.MethodPut,
+			path:           "/tika",
+			body:           "encrypted",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Encrypted with password",
+			method:         http.MethodPut,
+			path:           "/tika",
+			body:           "encrypted",
+			headers:        map[string]string{"Password": "secret"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Hello from encrypted",
+		},
+		{
+			name:           "Recursive metadata",
+			method:         http.MethodPut,
+			path:           "/rmeta/text",
+			body:           "report",
+			headers:        map[string]string{"File-Name": "report.pdf"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"X-TIKA:content":"Hello from report"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, resp.StatusCode, body)
+			}
+			if !strings.Contains(string(body), tt.expectedBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestHandler_RecursiveMetadata(t *testing.T) {
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/rmeta", strings.NewReader("report"))
+	req.Header.Set("File-Name", "report.pdf")
+	rec := httptest.NewRecorder()
+	NewHandler(converter, nil).ServeHTTP(rec, req)
+
+	var metadata []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &metadata); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(metadata) != 1 {
+		t.Fatalf("expected one metadata object, got %d", len(metadata))
+	}
+	if metadata[0]["resourceName"] != "report.pdf" || metadata[0]["Content-Type"] != "application/pdf" {
+		t.Errorf("unexpected metadata %v", metadata[0])
+	}
+}
+
+func TestHandler_TooLarge(t *testing.T) {
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/tika", strings.NewReader("a large report"))
+	rec := httptest.NewRecorder()
+	NewHandler(converter, &pdftotext.Options{MaxInputBytes: 8}).ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, rec.Code, rec.Body)
+	}
+}
+
+// readInput returns the contents of the input file passed to pdftotext
+func readInput(args []string) (string, error) {
+	data, err := os.ReadFile(args[len(args)-2])
+	return string(data), err
+}
+
+func containsPair(args []string, flag, value string) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag && args[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHandler_ErrorDetails(t *testing.T) {
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	var logs bytes.Buffer
+	handler := NewHandler(converter, nil)
+	handler.logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	req := httptest.NewRequest(http.MethodPut, "/tika", strings.NewReader("encrypted"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusUnprocessableEntity, rec.Code, rec.Body)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != http.StatusText(http.StatusUnprocessableEntity) {
+		t.Errorf("expected the status text only, got %q", body)
+	}
+	if !strings.Contains(logs.String(), "Incorrect password") {
+		t.Errorf("expected the error to be logged, got %q", logs.String())
+	}
+}
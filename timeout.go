@@ -0,0 +1,103 @@
+package pdftotext
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// withTimeout bounds ctx by the timeout opts allow for converting inputPath
+// and returns opts without the timeout options, so the conversions a
+// conversion is made of share its timeout
+func (c *Converter) withTimeout(ctx context.Context, inputPath string, opts *Options) (context.Context, *Options, context.CancelFunc) {
+	if opts == nil || (opts.TimeoutPerPage <= 0 && opts.TimeoutPerMB <= 0 && opts.MinTimeout <= 0 && opts.MaxTimeout <= 0) {
+		return ctx, opts, func() {}
+	}
+	timeout := c.conversionTimeout(ctx, inputPath, opts)
+	untimed := *opts
+	untimed.TimeoutPerPage = 0
+	untimed.TimeoutPerMB = 0
+	untimed.MinTimeout = 0
+	untimed.MaxTimeout = 0
+	if timeout <= 0 {
+		return ctx, &untimed, func() {}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, &untimed, cancel
+}
+
+// DefaultTimeout bounds a conversion with timeout options when its timeout
+// cannot be determined, e.g. when pdfinfo fails on a damaged file, and no
+// MaxTimeout is set
+const DefaultTimeout = 5 * time.Minute
+
+// pageCountTimeout bounds the pdfinfo run looking up the page count for
+// TimeoutPerPage
+const pageCountTimeout = 30 * time.Second
+
+// conversionTimeout returns opts.TimeoutPerPage for each converted page plus
+// opts.TimeoutPerMB for each MiB of the input, clamped to opts.MinTimeout and
+// opts.MaxTimeout. The terms that cannot be determined, e.g. the page count
+// of a damaged file, are left out, and a timeout that is still zero falls
+// back to opts.MaxTimeout or DefaultTimeout.
+func (c *Converter) conversionTimeout(ctx context.Context, inputPath string, opts *Options) time.Duration {
+	var timeout time.Duration
+	if opts.TimeoutPerMB > 0 {
+		if stat, err := os.Stat(inputPath); err == nil {
+			timeout += time.Duration(float64(opts.TimeoutPerMB) * float64(stat.Size()) / (1 << 20))
+		}
+	}
+	if opts.TimeoutPerPage > 0 {
+		if pages, err := c.selectedPages(ctx, inputPath, opts, 0); err == nil {
+			timeout += time.Duration(pages) * opts.TimeoutPerPage
+		}
+	}
+	timeout = max(timeout, opts.MinTimeout)
+	if opts.MaxTimeout > 0 {
+		timeout = min(timeout, opts.MaxTimeout)
+	}
+	if timeout <= 0 {
+		timeout = opts.MaxTimeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return timeout
+}
+
+// selectedPages returns the number of pages selected by the page range,
+// Pages, TailPages and ExcludePages options, looking up the page count with
+// pdfinfo unless every selected range has a last page or count is the page
+// count already. The lookup takes at most pageCountTimeout, or
+// opts.MaxTimeout if it is shorter. The ranges are cut at the page count
+// when it is known.
+func (c *Converter) selectedPages(ctx context.Context, inputPath string, opts *Options, count int) (int, error) {
+	bound := pageCountTimeout
+	if opts.MaxTimeout > 0 {
+		bound = min(bound, opts.MaxTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, bound)
+	defer cancel()
+
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, opts)
+	if err != nil {
+		return 0, err
+	}
+	pages := 0
+	for _, o := range rangeOpts {
+		first, last := max(o.FirstPage, 1), o.LastPage
+		if count > 0 && last > count {
+			last = count
+		}
+		if last <= 0 {
+			if count == 0 {
+				if count, err = c.PageCount(ctx, inputPath, opts); err != nil {
+					return 0, err
+				}
+			}
+			last = count
+		}
+		pages += max(last-first+1, 0)
+	}
+	return pages, nil
+}
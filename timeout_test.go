@@ -0,0 +1,162 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConverter_ConversionTimeout(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, make([]byte, 2<<20), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	pdfinfoCalls := 0
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		pdfinfoCalls++
+		_, err := io.WriteString(stdout, "Pages:          10\n")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		options       *Options
+		expected      time.Duration
+		expectedCalls int
+	}{
+		{
+			name:          "Per page",
+			options:       &Options{TimeoutPerPage: time.Second},
+			expected:      10 * time.Second,
+			expectedCalls: 1,
+		},
+		{
+			name:     "Page range",
+			options:  &Options{TimeoutPerPage: time.Second, FirstPage: 3, LastPage: 4},
+			expected: 2 * time.Second,
+		},
+		{
+			name:          "Tail pages",
+			options:       &Options{TimeoutPerPage: time.Second, TailPages: 3},
+			expected:      3 * time.Second,
+			expectedCalls: 1,
+		},
+		{
+			name:     "Per MB",
+			options:  &Options{TimeoutPerMB: 5 * time.Second},
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "Floor",
+			options:  &Options{TimeoutPerMB: time.Second, MinTimeout: 30 * time.Second},
+			expected: 30 * time.Second,
+		},
+		{
+			name:          "Ceiling",
+			options:       &Options{TimeoutPerPage: time.Minute, TimeoutPerMB: time.Minute, MaxTimeout: 5 * time.Minute},
+			expected:      5 * time.Minute,
+			expectedCalls: 1,
+		},
+		{
+			name:     "Ceiling only",
+			options:  &Options{MaxTimeout: 5 * time.Minute},
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "Pages",
+			options:  &Options{TimeoutPerPage: time.Second, Pages: "2-3,5"},
+			expected: 3 * time.Second,
+		},
+		{
+			name:          "Excluded pages",
+			options:       &Options{TimeoutPerPage: time.Second, ExcludePages: "1-4"},
+			expected:      6 * time.Second,
+			expectedCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdfinfoCalls = 0
+			if got := converter.conversionTimeout(context.Background(), input, tt.options); got != tt.expected {
+				t.Errorf("expected timeout %v, got %v", tt.expected, got)
+			}
+			if pdfinfoCalls != tt.expectedCalls {
+				t.Errorf("expected %d pdfinfo calls, got %d", tt.expectedCalls, pdfinfoCalls)
+			}
+		})
+	}
+}
+
+func TestConverter_ConversionTimeout_UnknownPageCount(t *testing.T) {
+	damaged := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Syntax Error: Couldn't find trailer dictionary")
+		return &ExitError{Code: 1}
+	})
+	hanging := runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	tests := []struct {
+		name     string
+		runner   Runner
+		options  *Options
+		expected time.Duration
+	}{
+		{
+			name:     "Damaged file",
+			runner:   damaged,
+			options:  &Options{TimeoutPerPage: time.Second},
+			expected: DefaultTimeout,
+		},
+		{
+			name:     "Damaged file with ceiling",
+			runner:   damaged,
+			options:  &Options{TimeoutPerPage: time.Second, MaxTimeout: time.Minute},
+			expected: time.Minute,
+		},
+		{
+			name:     "Hanging pdfinfo",
+			runner:   hanging,
+			options:  &Options{TimeoutPerPage: time.Second, MaxTimeout: 20 * time.Millisecond},
+			expected: 20 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := New(WithRunner(tt.runner))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			if got := converter.conversionTimeout(context.Background(), "input.pdf", tt.options); got != tt.expected {
+				t.Errorf("expected timeout %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertTimeout(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		<-ctx.Done()
+		return &ExitError{Code: -1}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	opts := &Options{TimeoutPerPage: time.Hour, LastPage: 1, MaxTimeout: 10 * time.Millisecond}
+	if _, err := converter.Convert(context.Background(), "input.pdf", opts); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error %v, got %v", context.DeadlineExceeded, err)
+	}
+	if opts.MaxTimeout != 10*time.Millisecond {
+		t.Error("expected the options to be left unchanged")
+	}
+}
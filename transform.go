@@ -0,0 +1,125 @@
+package pdftotext
+
+import (
+	"bytes"
+	"io"
+)
+
+// Transformer post-processes the extracted text of a page, e.g. to rejoin
+// hyphenated words or normalize whitespace. Transformers run in the order of
+// Options.Transformers, each receiving the output of the previous one.
+type Transformer interface {
+	// Transform returns the new text of the page with the given number
+	Transform(page int, text string) (string, error)
+}
+
+// TransformerFunc adapts a function to a Transformer
+type TransformerFunc func(page int, text string) (string, error)
+
+// Transform calls f(page, text)
+func (f TransformerFunc) Transform(page int, text string) (string, error) {
+	return f(page, text)
+}
+
+// transformers returns the transformers applied to the output for opts: the
+// normalization of opts.Normalize followed by opts.Transformers
+func transformers(opts *Options) []Transformer {
+	if opts == nil {
+		return nil
+	}
+	if opts.Normalize == "" {
+		return opts.Transformers
+	}
+	return append([]Transformer{opts.Normalize}, opts.Transformers...)
+}
+
+// transform passes text through transformers in order
+func transform(transformers []Transformer, page int, text string) (string, error) {
+	for _, t := range transformers {
+		var err error
+		if text, err = t.Transform(page, text); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+// transformPages passes the text of each page through transformers
+func transformPages(transformers []Transformer, pages []Page) error {
+	for i := range pages {
+		text, err := transform(transformers, pages[i].Number, pages[i].Text)
+		if err != nil {
+			return err
+		}
+		pages[i].Text = text
+	}
+	return nil
+}
+
+// transformWriter buffers the output of pdftotext page by page and writes
+// each page through the transformers once its form feed arrives. The first
+// error is kept in err and fails all later writes.
+type transformWriter struct {
+	w            io.Writer
+	transformers []Transformer
+	// breaks keeps the form feed after each transformed page
+	breaks bool
+	page   int
+	buf    []byte
+	err    error
+}
+
+// startRange sets the number of the first page of a page range
+func (tw *transformWriter) startRange(firstPage int) {
+	tw.page = max(firstPage, 1)
+}
+
+func (tw *transformWriter) Write(p []byte) (int, error) {
+	if tw.err != nil {
+		return 0, tw.err
+	}
+	n := len(p)
+	for {
+		i := bytes.IndexByte(p, '\f')
+		if i < 0 {
+			tw.buf = append(tw.buf, p...)
+			return n, nil
+		}
+		tw.buf = append(tw.buf, p[:i]...)
+		if err := tw.writePage(true); err != nil {
+			return 0, err
+		}
+		p = p[i+1:]
+	}
+}
+
+// Flush writes the text after the last form feed, which is only present if
+// the output ended without a page break
+func (tw *transformWriter) Flush() error {
+	if tw.err != nil {
+		return tw.err
+	}
+	if len(bytes.TrimSpace(tw.buf)) == 0 {
+		tw.buf = tw.buf[:0]
+		return nil
+	}
+	return tw.writePage(false)
+}
+
+func (tw *transformWriter) writePage(pageBreak bool) error {
+	text, err := transform(tw.transformers, tw.page, string(tw.buf))
+	tw.buf = tw.buf[:0]
+	tw.page++
+	if err != nil {
+		tw.err = err
+		return err
+	}
+	if pageBreak && tw.breaks {
+		text += "\f"
+	}
+	if _, err := io.WriteString(tw.w, text); err != nil {
+		tw.err = err
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// numberPages prefixes the text of each page with its number
+var numberPages = TransformerFunc(func(page int, text string) (string, error) {
+	return fmt.Sprintf("%d:%s", page, text), nil
+})
+
+func TestConverter_Transformers(t *testing.T) {
+	upper := TransformerFunc(func(page int, text string) (string, error) {
+		return strings.ToUpper(text), nil
+	})
+
+	tests := []struct {
+		name     string
+		options  *Options
+		expected string
+	}{
+		{
+			name:     "single transformer",
+			options:  &Options{LastPage: 2, Transformers: []Transformer{upper}},
+			expected: "PAGE 1\n\fPAGE 2",
+		},
+		{
+			name:     "applied in order",
+			options:  &Options{FirstPage: 2, LastPage: 3, Transformers: []Transformer{numberPages, upper}},
+			expected: "2:PAGE 2\n\f3:PAGE 3",
+		},
+		{
+			name:     "multiple ranges",
+			options:  &Options{Pages: "1,3-4", Transformers: []Transformer{numberPages}},
+			expected: "1:page 1\n\f3:page 3\n\f4:page 4",
+		},
+		{
+			name:     "no page breaks",
+			options:  &Options{LastPage: 2, NoPageBreaks: true, Transformers: []Transformer{numberPages}},
+			expected: "1:page 1\n2:page 2",
+		},
+		{
+			name:     "page separator",
+			options:  &Options{ExcludePages: "2", PageSeparator: "[{n}]", Transformers: []Transformer{numberPages}},
+			expected: "1:page 1\n[3]3:page 3\n[4]4:page 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs atomic.Int32
+			converter, err := New(WithRunner(pagesRunner(4, 0, &runs)))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			ctx := context.Background()
+
+			text, err := converter.Convert(ctx, "input.pdf", tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+
+			parallel, err := converter.ConvertParallel(ctx, "input.pdf", 2, tt.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parallel != tt.expected {
+				t.Errorf("expected parallel %q, got %q", tt.expected, parallel)
+			}
+
+			output := filepath.Join(t.TempDir(), "output.txt")
+			if err := converter.ConvertToFile(ctx, "input.pdf", output, tt.options); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if strings.TrimSpace(string(data)) != tt.expected {
+				t.Errorf("expected file %q, got %q", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestConverter_TransformersPages(t *testing.T) {
+	var runs atomic.Int32
+	converter, err := New(WithRunner(pagesRunner(4, 0, &runs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	opts := &Options{Pages: "2-3", Transformers: []Transformer{numberPages}}
+	expected := []string{"2:page 2\n", "3:page 3\n"}
+
+	pages, err := converter.ConvertPages(ctx, "input.pdf", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var texts []string
+	for _, page := range pages {
+		texts = append(texts, page.Text)
+	}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected pages %q, got %q", expected, texts)
+	}
+
+	texts = nil
+	for page, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		texts = append(texts, page.Text)
+	}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("expected streamed pages %q, got %q", expected, texts)
+	}
+}
+
+func TestConverter_TransformerError(t *testing.T) {
+	errRejected := errors.New("rejected page")
+	failing := TransformerFunc(func(page int, text string) (string, error) {
+		if page == 3 {
+			return "", errRejected
+		}
+		return text, nil
+	})
+
+	var runs atomic.Int32
+	converter, err := New(WithRunner(pagesRunner(4, 0, &runs)))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	opts := &Options{Transformers: []Transformer{failing}}
+
+	if _, err := converter.Convert(ctx, "input.pdf", opts); !errors.Is(err, errRejected) {
+		t.Errorf("expected error %v, got %v", errRejected, err)
+	}
+	if _, err := converter.ConvertPages(ctx, "input.pdf", opts); !errors.Is(err, errRejected) {
+		t.Errorf("expected error %v, got %v", errRejected, err)
+	}
+
+	var numbers []int
+	var streamErr error
+	for page, err := range converter.StreamPages(ctx, "input.pdf", opts) {
+		if err != nil {
+			streamErr = err
+			continue
+		}
+		numbers = append(numbers, page.Number)
+	}
+	if !errors.Is(streamErr, errRejected) {
+		t.Errorf("expected error %v, got %v", errRejected, streamErr)
+	}
+	if !reflect.DeepEqual(numbers, []int{1, 2}) {
+		t.Errorf("expected pages [1 2] before the error, got %v", numbers)
+	}
+}
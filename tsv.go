@@ -0,0 +1,163 @@
+package pdftotext
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TSVLevel is the layout level of a row of pdftotext -tsv output, using the
+// levels of Tesseract's TSV format
+type TSVLevel int
+
+const (
+	// TSVPage rows span a page
+	TSVPage TSVLevel = 1
+	// TSVBlock rows span a block
+	TSVBlock TSVLevel = 2
+	// TSVParagraph rows span a paragraph or text flow
+	TSVParagraph TSVLevel = 3
+	// TSVLine rows span a line
+	TSVLine TSVLevel = 4
+	// TSVWord rows hold a word
+	TSVWord TSVLevel = 5
+)
+
+// TSVRow is a row of pdftotext -tsv output
+type TSVRow struct {
+	// Level is the layout level of the row
+	Level TSVLevel `json:"level"`
+	// Page is the page number in the PDF file
+	Page int `json:"page"`
+	// Paragraph, Block, Line and Word number the row within its page
+	Paragraph int `json:"paragraph"`
+	Block     int `json:"block"`
+	Line      int `json:"line"`
+	Word      int `json:"word"`
+	// Box is the bounding box of the row
+	Box Rect `json:"box"`
+	// Confidence is 100 for words and -1 for the other levels
+	Confidence float64 `json:"confidence"`
+	// Text is the word, or a marker such as "###LINE###" for the other levels
+	Text string `json:"text"`
+}
+
+// ConvertTSV converts a PDF file with -tsv and returns the rows with the
+// layout structure and bounding box of every page, flow, line and word.
+// Output options such as Layout and BBox are ignored.
+func (c *Converter) ConvertTSV(ctx context.Context, inputPath string, opts *Options) ([]TSVRow, error) {
+	tsvOpts := tsvOptions(opts)
+	hookOpts, err := runPreHooks(ctx, inputPath, &tsvOpts)
+	if err != nil {
+		return nil, err
+	}
+	rangeOpts, err := c.rangeOptions(ctx, inputPath, hookOpts)
+	if err != nil {
+		return nil, err
+	}
+	var rows []TSVRow
+	for _, o := range rangeOpts {
+		var stdout bytes.Buffer
+		if err := c.run(ctx, inputPath, "-", o, &stdout); err != nil {
+			return nil, err
+		}
+		rangeRows, err := parseTSV(&stdout, o.FirstPage)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, rangeRows...)
+	}
+	return rows, nil
+}
+
+// tsvOptions returns opts converting with -tsv, without the options
+// rewriting the text
+func tsvOptions(opts *Options) Options {
+	tsvOpts := Options{}
+	if opts != nil {
+		tsvOpts = *opts
+	}
+	tsvOpts.TSV, tsvOpts.BBox, tsvOpts.BBoxLayout, tsvOpts.HTMLMeta = true, false, false, false
+	tsvOpts.PageSeparator = ""
+	tsvOpts.Transformers = nil
+	tsvOpts.Normalize = ""
+	tsvOpts.PostHooks = nil
+	tsvOpts.ReadingOrder = false
+	return tsvOpts
+}
+
+// tsvColumns are the columns of pdftotext -tsv output
+var tsvColumns = []string{"level", "page_num", "par_num", "block_num", "line_num", "word_num", "left", "top", "width", "height", "conf", "text"}
+
+// parseTSV parses the output of pdftotext -tsv. Pages are numbered from
+// firstPage in the order they appear, since pdftotext numbers them from 1
+// for every page range.
+func parseTSV(r io.Reader, firstPage int) ([]TSVRow, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []TSVRow
+	page := max(firstPage, 1)
+	header, started := true, false
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if header {
+			if line != strings.Join(tsvColumns, "\t") {
+				return nil, fmt.Errorf("invalid tsv output: unexpected header %q", line)
+			}
+			header = false
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		// the text is the last column and may contain tabs
+		fields := strings.SplitN(line, "\t", len(tsvColumns))
+		if len(fields) != len(tsvColumns) {
+			return nil, fmt.Errorf("invalid tsv output: line %d has %d columns", n, len(fields))
+		}
+		ints := make([]int, 6)
+		for i := range ints {
+			v, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid tsv output: line %d: %s: %w", n, tsvColumns[i], err)
+			}
+			ints[i] = v
+		}
+		floats := make([]float64, 5)
+		for i := range floats {
+			v, err := strconv.ParseFloat(fields[6+i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tsv output: line %d: %s: %w", n, tsvColumns[6+i], err)
+			}
+			floats[i] = v
+		}
+
+		// rows before the first page row belong to the first page
+		level := TSVLevel(ints[0])
+		if level == TSVPage && started {
+			page++
+		}
+		started = true
+		rows = append(rows, TSVRow{
+			Level:      level,
+			Page:       page,
+			Paragraph:  ints[2],
+			Block:      ints[3],
+			Line:       ints[4],
+			Word:       ints[5],
+			Box:        Rect{XMin: floats[0], YMin: floats[1], XMax: floats[0] + floats[2], YMax: floats[1] + floats[3]},
+			Confidence: floats[4],
+			Text:       fields[11],
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("invalid tsv output: %w", err)
+	}
+	return rows, nil
+}
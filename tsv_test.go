@@ -0,0 +1,89 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const tsvOutput = "level\tpage_num\tpar_num\tblock_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0.000000\t0.000000\t612.000000\t792.000000\t-1\t###PAGE###\n" +
+	"3\t1\t0\t0\t0\t0\t56.800000\t57.208000\t68.952000\t12.000000\t-1\t###FLOW###\n" +
+	"4\t1\t0\t0\t0\t0\t56.800000\t57.208000\t68.952000\t12.000000\t-1\t###LINE###\n" +
+	"5\t1\t0\t0\t0\t0\t56.800000\t57.208000\t32.640000\t12.000000\t100\tHello\n" +
+	"5\t1\t0\t0\t0\t1\t92.440000\t57.208000\t33.312000\t12.000000\t100\tworld\n" +
+	"1\t2\t0\t0\t0\t0\t0.000000\t0.000000\t612.000000\t792.000000\t-1\t###PAGE###\n" +
+	"5\t2\t0\t0\t0\t0\t56.800000\t57.208000\t43.200000\t12.000000\t100\tFish\tchips\n"
+
+func TestParseTSV(t *testing.T) {
+	rows, err := parseTSV(strings.NewReader(tsvOutput), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 7 {
+		t.Fatalf("expected 7 rows, got %d", len(rows))
+	}
+
+	expected := TSVRow{
+		Level:      TSVWord,
+		Page:       3,
+		Word:       1,
+		Box:        Rect{XMin: 92.44, YMin: 57.208, XMax: 92.44 + 33.312, YMax: 57.208 + 12},
+		Confidence: 100,
+		Text:       "world",
+	}
+	if !reflect.DeepEqual(rows[4], expected) {
+		t.Errorf("expected %+v, got %+v", expected, rows[4])
+	}
+	if rows[0].Level != TSVPage || rows[0].Page != 3 || rows[0].Confidence != -1 {
+		t.Errorf("unexpected page row %+v", rows[0])
+	}
+	if rows[6].Page != 4 || rows[6].Text != "Fish\tchips" {
+		t.Errorf("unexpected last row %+v", rows[6])
+	}
+}
+
+func TestParseTSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing header", input: "1\t1\t0\t0\t0\t0\t0\t0\t612\t792\t-1\t###PAGE###\n"},
+		{name: "short row", input: strings.Join(tsvColumns, "\t") + "\n5\t1\t0\n"},
+		{name: "invalid number", input: strings.Join(tsvColumns, "\t") + "\n5\t1\t0\t0\t0\tx\t0\t0\t1\t1\t100\tword\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTSV(strings.NewReader(tt.input), 1); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertTSV(t *testing.T) {
+	var gotArgs []string
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		gotArgs = args
+		_, err := io.WriteString(stdout, tsvOutput)
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	rows, err := converter.ConvertTSV(context.Background(), "input.pdf", &Options{BBox: true, FirstPage: 2, LastPage: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 7 || rows[0].Page != 2 || rows[6].Page != 3 {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+
+	args := strings.Join(gotArgs, " ")
+	if !strings.Contains(args, "-tsv") || strings.Contains(args, "-bbox") {
+		t.Errorf("expected -tsv without -bbox, got %q", args)
+	}
+}
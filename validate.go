@@ -0,0 +1,190 @@
+package pdftotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultMaxFileSize is the default largest PDF file Validate accepts
+const DefaultMaxFileSize = 2 << 30
+
+// pdfHeaderWindow is how far into a file Validate looks for the PDF header.
+// Like PDF readers, it tolerates junk before the header, as added by some
+// mail gateways.
+const pdfHeaderWindow = 1024
+
+// WithMaxFileSize sets the largest PDF file Validate accepts (default
+// DefaultMaxFileSize). A size of zero or less removes the limit.
+func WithMaxFileSize(size int64) ConverterOption {
+	return func(c *Converter) {
+		c.maxFileSize = size
+	}
+}
+
+// Validate checks that a file exists, is a readable regular file, is not
+// empty or larger than the maximum file size and starts with a PDF header,
+// without running pdftotext, so uploads can be rejected with a clear error
+// before a conversion is queued. It returns ErrPDFOpen if the file cannot
+// be read, ErrNotPDF if it is empty or has no PDF header and
+// ErrFileTooLarge if it is too large, and ErrPathNotAllowed for paths outside
+// the roots of WithAllowedRoots.
+func (c *Converter) Validate(ctx context.Context, inputPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := c.confine(ctx, inputPath, ""); err != nil {
+		return err
+	}
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	if !stat.Mode().IsRegular() {
+		return fmt.Errorf("%w: %s is not a regular file", ErrPDFOpen, inputPath)
+	}
+	if stat.Size() == 0 {
+		return fmt.Errorf("%w: %s is empty", ErrNotPDF, inputPath)
+	}
+	if c.maxFileSize > 0 && stat.Size() > c.maxFileSize {
+		return inputTooLarge(inputPath, stat.Size(), c.maxFileSize)
+	}
+
+	header := make([]byte, pdfHeaderWindow)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrPDFOpen, err)
+	}
+	if !bytes.Contains(header[:n], []byte("%PDF-")) {
+		return &NotPDFError{Path: inputPath, Type: detectFileType(header[:n], file, stat.Size())}
+	}
+	return nil
+}
+
+// inputTooLarge returns ErrFileTooLarge for a file of size bytes exceeding
+// limit
+func inputTooLarge(path string, size, limit int64) error {
+	return fmt.Errorf("%w: %s has %d bytes, the maximum is %d", ErrFileTooLarge, path, size, limit)
+}
+
+// NotPDFError is returned for files that are not PDFs, naming the type of
+// file detected by its magic bytes, e.g. a Word document renamed to .pdf. It
+// matches ErrNotPDF.
+type NotPDFError struct {
+	// Path is the path of the file
+	Path string
+	// Type is the detected type, e.g. "DOCX" or "PNG image", empty if it is
+	// unknown
+	Type string
+}
+
+// Error returns the detected type
+func (e *NotPDFError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("%s: %s has no PDF header", ErrNotPDF, e.Path)
+	}
+	return fmt.Sprintf("%s: detected %s in %s", ErrNotPDF, e.Type, e.Path)
+}
+
+// Unwrap returns ErrNotPDF
+func (e *NotPDFError) Unwrap() error {
+	return ErrNotPDF
+}
+
+// fileSignatures are the magic bytes of the files most often uploaded in
+// place of PDFs, with their types
+var fileSignatures = []struct {
+	magic, kind string
+}{
+	{"\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1", "legacy Office (DOC, XLS or PPT)"},
+	{"\x89PNG\r\n\x1a\n", "PNG image"},
+	{"\xff\xd8\xff", "JPEG image"},
+	{"GIF87a", "GIF image"},
+	{"GIF89a", "GIF image"},
+	{"II*\x00", "TIFF image"},
+	{"MM\x00*", "TIFF image"},
+	{"{\\rtf", "RTF"},
+	{"%!PS", "PostScript"},
+	{"\x1f\x8b", "gzip"},
+	{"Rar!", "RAR"},
+	{"7z\xbc\xaf\x27\x1c", "7z"},
+}
+
+// zipSignatures identify Office Open XML and OpenDocument files among ZIP
+// archives by the paths or media types they contain
+var zipSignatures = []struct {
+	marker, kind string
+}{
+	{"word/", "DOCX"},
+	{"xl/", "XLSX"},
+	{"ppt/", "PPTX"},
+	{"application/vnd.oasis.opendocument.text", "ODT"},
+	{"application/vnd.oasis.opendocument.spreadsheet", "ODS"},
+	{"application/epub+zip", "EPUB"},
+}
+
+// detectFileType returns the type of a file from its first bytes, and from
+// the central directory at its end for ZIP archives, or an empty string if
+// it is unknown
+func detectFileType(header []byte, file io.ReaderAt, size int64) string {
+	if bytes.HasPrefix(header, []byte("PK\x03\x04")) {
+		tail := make([]byte, min(size, encryptionTailSize))
+		n, _ := file.ReadAt(tail, size-int64(len(tail)))
+		for _, z := range zipSignatures {
+			if bytes.Contains(header, []byte(z.marker)) || bytes.Contains(tail[:n], []byte(z.marker)) {
+				return z.kind
+			}
+		}
+		return "ZIP"
+	}
+	if bytes.HasPrefix(header, []byte("RIFF")) && len(header) >= 12 && string(header[8:12]) == "WEBP" {
+		return "WebP image"
+	}
+	for _, s := range fileSignatures {
+		if bytes.HasPrefix(header, []byte(s.magic)) {
+			return s.kind
+		}
+	}
+
+	text := bytes.ToLower(bytes.TrimLeft(bytes.TrimPrefix(header, []byte("\xef\xbb\xbf")), " \t\r\n"))
+	switch {
+	case bytes.HasPrefix(text, []byte("<!doctype html")) || bytes.HasPrefix(text, []byte("<html")):
+		return "HTML"
+	case bytes.HasPrefix(text, []byte("<?xml")):
+		if bytes.Contains(text, []byte("<svg")) {
+			return "SVG image"
+		}
+		return "XML"
+	case bytes.HasPrefix(text, []byte("<svg")):
+		return "SVG image"
+	}
+	return ""
+}
+
+// sniffFileType returns the type of a file that is not a PDF, or an empty
+// string if it is a PDF, of an unknown type or cannot be read
+func sniffFileType(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil || !stat.Mode().IsRegular() {
+		return ""
+	}
+	header := make([]byte, pdfHeaderWindow)
+	n, _ := io.ReadFull(file, header)
+	if bytes.Contains(header[:n], []byte("%PDF-")) {
+		return ""
+	}
+	return detectFileType(header[:n], file, stat.Size())
+}
@@ -0,0 +1,155 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConverter_Validate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"valid.pdf":  labeledPDF,
+		"junk.pdf":   "X-Mailer: gateway\r\n" + labeledPDF,
+		"empty.pdf":  "",
+		"html.pdf":   "<!DOCTYPE html><html></html>",
+		"large.pdf":  labeledPDF + string(make([]byte, 1024)),
+		"header.pdf": "%PDF-1.7",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatalf("failed to write input: %v", err)
+		}
+	}
+	converter, err := New(WithRunner(runnerFunc(nil)), WithMaxFileSize(1024))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected error
+	}{
+		{name: "Valid", path: "valid.pdf"},
+		{name: "Junk before header", path: "junk.pdf"},
+		{name: "Header only", path: "header.pdf"},
+		{name: "Empty", path: "empty.pdf", expected: ErrNotPDF},
+		{name: "No header", path: "html.pdf", expected: ErrNotPDF},
+		{name: "Too large", path: "large.pdf", expected: ErrFileTooLarge},
+		{name: "Missing", path: "missing.pdf", expected: ErrPDFOpen},
+		{name: "Directory", path: ".", expected: ErrPDFOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := converter.Validate(context.Background(), filepath.Join(dir, tt.path))
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestDetectFileType(t *testing.T) {
+	docx := "PK\x03\x04\x14\x00\x06\x00[Content_Types].xml" + string(make([]byte, 2048)) + "PK\x01\x02word/document.xml"
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "DOCX", data: docx, expected: "DOCX"},
+		{name: "ODT", data: "PK\x03\x04\x0a\x00mimetypeapplication/vnd.oasis.opendocument.text", expected: "ODT"},
+		{name: "ZIP", data: "PK\x03\x04\x14\x00notes.txt", expected: "ZIP"},
+		{name: "Legacy Office", data: "\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1\x00", expected: "legacy Office (DOC, XLS or PPT)"},
+		{name: "PNG", data: "\x89PNG\r\n\x1a\n\x00\x00", expected: "PNG image"},
+		{name: "JPEG", data: "\xff\xd8\xff\xe0\x00\x10JFIF", expected: "JPEG image"},
+		{name: "WebP", data: "RIFF\x24\x00\x00\x00WEBPVP8 ", expected: "WebP image"},
+		{name: "HTML", data: "\xef\xbb\xbf\n  <!DOCTYPE HTML><html>", expected: "HTML"},
+		{name: "SVG", data: "<?xml version=\"1.0\"?>\n<svg xmlns=\"http://www.w3.org/2000/svg\">", expected: "SVG image"},
+		{name: "Unknown", data: "plain text", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := []byte(tt.data)[:min(len(tt.data), pdfHeaderWindow)]
+			if got := detectFileType(header, strings.NewReader(tt.data), int64(len(tt.data))); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConverter_NotPDF(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "scan.pdf")
+	if err := os.WriteFile(input, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Syntax Warning: May not be a PDF file (continuing anyway)\nSyntax Error: Couldn't find trailer dictionary")
+		return &ExitError{Code: 1}
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	for name, check := range map[string]func() error{
+		"Validate": func() error { return converter.Validate(context.Background(), input) },
+		"Convert": func() error {
+			_, err := converter.Convert(context.Background(), input, nil)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := check()
+			var notPDF *NotPDFError
+			if !errors.As(err, &notPDF) || notPDF.Type != "PNG image" || !errors.Is(err, ErrNotPDF) {
+				t.Errorf("expected a NotPDFError for a PNG image, got %v", err)
+			}
+		})
+	}
+
+	_, err = converter.Convert(context.Background(), filepath.Join(dir, "missing.pdf"), nil)
+	if !errors.Is(err, ErrPDFOpen) || errors.Is(err, ErrNotPDF) {
+		t.Errorf("expected ErrPDFOpen only, got %v", err)
+	}
+}
+
+func TestConverter_MaxInputBytes(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte(labeledPDF), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	var runs int
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		runs++
+		_, err := io.WriteString(stdout, "text")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	ctx := context.Background()
+	small := &Options{MaxInputBytes: 16}
+	large := &Options{MaxInputBytes: 1 << 20}
+
+	if _, err := converter.Convert(ctx, input, small); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("expected ErrInputTooLarge, got %v", err)
+	}
+	if _, err := converter.ConvertReader(ctx, strings.NewReader(labeledPDF), small); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("expected ErrInputTooLarge, got %v", err)
+	}
+	if runs != 0 {
+		t.Errorf("expected no runs, got %d", runs)
+	}
+
+	if text, err := converter.Convert(ctx, input, large); err != nil || text != "text" {
+		t.Errorf("expected text, got %q, %v", text, err)
+	}
+	if text, err := converter.ConvertReader(ctx, strings.NewReader(labeledPDF), large); err != nil || text != "text" {
+		t.Errorf("expected text, got %q, %v", text, err)
+	}
+}
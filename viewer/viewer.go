@@ -0,0 +1,151 @@
+// Package viewer serves the mapping between the text of a pdftotext.Session
+// and positions on its rendered pages over a small WebSocket JSON protocol, so
+// web viewers can scroll the extracted text and the PDF in sync.
+//
+// Clients send requests as text messages and receive one response per
+// request, carrying the same id:
+//
+//	{"id": 1, "method": "page", "page": 2}
+//	{"id": 1, "result": {"number": 2, "text": "...", "width": 612, "height": 792}}
+//
+//	{"id": 2, "method": "rects", "page": 2, "offset": 120, "length": 14}
+//	{"id": 2, "result": {"rects": [{"x_min": 56.8, "y_min": 57.2, "x_max": 89.4, "y_max": 69.2}]}}
+//
+//	{"id": 3, "method": "offset", "page": 2, "x": 100, "y": 60}
+//	{"id": 3, "result": {"offset": 120}}
+//
+//	{"id": 4, "method": "search", "query": "total"}
+//	{"id": 4, "result": {"matches": [{"page": 2, "offset": 120, "text": "Total", "rects": [...]}]}}
+//
+// Failed requests are answered with {"id": ..., "error": "..."}. Offsets are
+// byte offsets in the page text and rectangles are in PDF points from the
+// top-left corner of the page.
+package viewer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+// Handler is an http.Handler serving the protocol for a session
+type Handler struct {
+	session *pdftotext.Session
+}
+
+// NewHandler returns a Handler answering requests about session
+func NewHandler(session *pdftotext.Session) *Handler {
+	return &Handler{session: session}
+}
+
+// Request is a request sent by a viewer
+type Request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Page   int             `json:"page,omitempty"`
+	Offset int             `json:"offset,omitempty"`
+	Length int             `json:"length,omitempty"`
+	X      float64         `json:"x,omitempty"`
+	Y      float64         `json:"y,omitempty"`
+	Query  string          `json:"query,omitempty"`
+}
+
+// Response answers a Request
+type Response struct {
+	ID     json.RawMessage `json:"id"`
+	Result any             `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// SearchMatch is a search match with the boxes to highlight
+type SearchMatch struct {
+	pdftotext.Match
+	Rects []pdftotext.Rect `json:"rects"`
+}
+
+// ServeHTTP upgrades the connection to a WebSocket and answers requests until
+// the client disconnects
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isUpgrade(r) {
+		w.Header().Set("Upgrade", "websocket")
+		http.Error(w, "expected a WebSocket connection", http.StatusUpgradeRequired)
+		return
+	}
+	c, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+
+	for {
+		message, err := c.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				c.WriteFrame(opClose, nil)
+			}
+			return
+		}
+
+		var resp Response
+		var req Request
+		if err := json.Unmarshal(message, &req); err != nil {
+			resp.Error = "invalid request: " + err.Error()
+		} else {
+			resp.ID = req.ID
+			if resp.Result, err = h.handle(req); err != nil {
+				resp.Error = err.Error()
+			}
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return
+		}
+		if err := c.WriteFrame(opText, data); err != nil {
+			return
+		}
+	}
+}
+
+// handle answers a request
+func (h *Handler) handle(req Request) (any, error) {
+	switch req.Method {
+	case "page":
+		page, err := h.session.Page(req.Page)
+		if err != nil {
+			return nil, err
+		}
+		geometry, err := h.session.Geometry(req.Page)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"number": page.Number, "text": page.Text, "width": geometry.Width, "height": geometry.Height}, nil
+	case "rects":
+		rects, err := h.session.TextRects(req.Page, req.Offset, req.Length)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"rects": rects}, nil
+	case "offset":
+		offset, err := h.session.TextOffset(req.Page, req.X, req.Y)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"offset": offset}, nil
+	case "search":
+		matches := []SearchMatch{}
+		for _, match := range h.session.Search(req.Query) {
+			rects, err := h.session.TextRects(match.Page, match.Offset, len(match.Text))
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, SearchMatch{Match: match, Rects: rects})
+		}
+		return map[string]any{"matches": matches}, nil
+	}
+	return nil, fmt.Errorf("unknown method %q", req.Method)
+}
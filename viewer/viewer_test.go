@@ -0,0 +1,187 @@
+package viewer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joeychilson/pdftotext"
+)
+
+const bboxOutput = `<doc>
+  <page width="612" height="792">
+    <word xMin="10" yMin="10" xMax="40" yMax="20">Invoice</word>
+    <word xMin="50" yMin="10" xMax="80" yMax="20">total</word>
+  </page>
+</doc>`
+
+// fakeRunner answers like pdfinfo, pdftotext and pdftotext -bbox for a one
+// page document
+type fakeRunner struct{}
+
+func (fakeRunner) Run(_ context.Context, name string, args []string, stdout, _ io.Writer) error {
+	switch {
+	case name == "pdfinfo":
+		io.WriteString(stdout, "Pages:           1\n")
+	case slices.Contains(args, "-bbox"):
+		io.WriteString(stdout, bboxOutput)
+	default:
+		io.WriteString(stdout, "Invoice total\n\f")
+	}
+	return nil
+}
+
+// dial opens a WebSocket connection to the server
+func dial(t *testing.T, url string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	nc, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { nc.Close() })
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	io.WriteString(nc, "GET / HTTP/1.1\r\nHost: viewer\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: "+key+"\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("failed to read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected status %d, got %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+	// the accept key of the RFC 6455 example
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected accept key %q", accept)
+	}
+	return nc, br
+}
+
+func TestHandler(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "input.pdf")
+	if err := os.WriteFile(input, []byte("%PDF-1.7"), 0o644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	converter, err := pdftotext.New(pdftotext.WithRunner(fakeRunner{}))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+	session, err := converter.OpenSession(context.Background(), input, nil)
+	if err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
+	defer session.Close()
+
+	server := httptest.NewServer(NewHandler(session))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("expected status %d, got %d", http.StatusUpgradeRequired, resp.StatusCode)
+	}
+
+	nc, br := dial(t, server.URL)
+	mask := []byte{1, 2, 3, 4}
+
+	tests := []struct {
+		name     string
+		request  string
+		expected string
+	}{
+		{
+			name:     "Page",
+			request:  `{"id":1,"method":"page","page":1}`,
+			expected: `{"id":1,"result":{"height":792,"number":1,"text":"Invoice total\n","width":612}}`,
+		},
+		{
+			name:     "Rects",
+			request:  `{"id":2,"method":"rects","page":1,"offset":8,"length":5}`,
+			expected: `{"id":2,"result":{"rects":[{"x_min":50,"y_min":10,"x_max":80,"y_max":20}]}}`,
+		},
+		{
+			name:     "Offset",
+			request:  `{"id":"three","method":"offset","page":1,"x":60,"y":15}`,
+			expected: `{"id":"three","result":{"offset":8}}`,
+		},
+		{
+			name:     "Search",
+			request:  `{"id":4,"method":"search","query":"TOTAL"}`,
+			expected: `{"id":4,"result":{"matches":[{"page":1,"offset":8,"text":"total","rects":[{"x_min":50,"y_min":10,"x_max":80,"y_max":20}]}]}}`,
+		},
+		{
+			name:     "Unknown page",
+			request:  `{"id":5,"method":"page","page":9}`,
+			expected: `{"id":5,"error":"invalid page number: 9"}`,
+		},
+		{
+			name:     "Unknown method",
+			request:  `{"id":6,"method":"render"}`,
+			expected: `{"id":6,"error":"unknown method \"render\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := writeFrame(nc, opText, []byte(tt.request), mask); err != nil {
+				t.Fatalf("failed to write request: %v", err)
+			}
+			_, opcode, payload, err := readFrame(br, false)
+			if err != nil {
+				t.Fatalf("failed to read response: %v", err)
+			}
+			if opcode != opText || string(payload) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, payload)
+			}
+		})
+	}
+
+	// pings are answered with pongs carrying the same payload
+	writeFrame(nc, opPing, []byte("hi"), mask)
+	if _, opcode, payload, err := readFrame(br, false); err != nil || opcode != opPong || string(payload) != "hi" {
+		t.Errorf("expected pong, got %d %q %v", opcode, payload, err)
+	}
+
+	writeFrame(nc, opClose, nil, mask)
+	if _, opcode, _, err := readFrame(br, false); err != nil || opcode != opClose {
+		t.Errorf("expected close, got %d %v", opcode, err)
+	}
+}
+
+func TestReadFrame_Fragmented(t *testing.T) {
+	var stream strings.Builder
+	mask := []byte{9, 8, 7, 6}
+	// a text frame without FIN followed by its final continuation
+	writeFrame(&stream, opText, []byte(`{"id":`), mask)
+	data := []byte(stream.String())
+	data[0] &^= 0x80
+	stream.Reset()
+	writeFrame(&stream, opContinuation, []byte(`1}`), mask)
+	data = append(data, stream.String()...)
+
+	server, client := net.Pipe()
+	defer client.Close()
+	go client.Write(data)
+
+	c := &conn{Conn: server, rw: bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server))}
+	message, err := c.ReadMessage()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var req Request
+	if err := json.Unmarshal(message, &req); err != nil || string(req.ID) != "1" {
+		t.Errorf("expected reassembled request, got %q, %v", message, err)
+	}
+}
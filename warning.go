@@ -0,0 +1,95 @@
+package pdftotext
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Warning is a message pdftotext printed to stderr while converting, such as
+// "Syntax Warning: Invalid Font Weight" or "Syntax Error (1234): Illegal
+// character". Poppler prints them for damaged or unusual documents even when
+// the conversion succeeds, so pipelines can use them to flag suspicious
+// documents.
+type Warning struct {
+	// Category is the category poppler printed, e.g. "Syntax Warning",
+	// "Syntax Error" or "Internal Error", empty if the message has none
+	Category string `json:"category,omitempty"`
+	// Offset is the byte offset in the PDF file the message refers to, or
+	// -1 if it refers to none
+	Offset int64 `json:"offset"`
+	// Message is the message without the category and the offset
+	Message string `json:"message"`
+}
+
+// warningCategories are the error categories of poppler
+var warningCategories = []string{
+	"Syntax Warning",
+	"Syntax Error",
+	"Config Error",
+	"Command Line Error",
+	"I/O Error",
+	"Permission Error",
+	"Unimplemented Feature",
+	"Internal Error",
+}
+
+// ParseWarning parses a message printed by pdftotext, "Category (offset):
+// message" or "Category: message"
+func ParseWarning(line string) Warning {
+	line = strings.TrimSpace(line)
+	for _, category := range warningCategories {
+		rest, ok := strings.CutPrefix(line, category)
+		if !ok {
+			continue
+		}
+		offset := int64(-1)
+		if inner, after, ok := strings.Cut(strings.TrimPrefix(rest, " ("), "):"); ok && strings.HasPrefix(rest, " (") {
+			if n, err := strconv.ParseInt(inner, 10, 64); err == nil {
+				offset, rest = n, ":"+after
+			}
+		}
+		if message, ok := strings.CutPrefix(rest, ":"); ok {
+			return Warning{Category: category, Offset: offset, Message: strings.TrimSpace(message)}
+		}
+	}
+	return Warning{Offset: -1, Message: line}
+}
+
+// String returns the message as pdftotext printed it
+func (w Warning) String() string {
+	switch {
+	case w.Category == "":
+		return w.Message
+	case w.Offset >= 0:
+		return w.Category + " (" + strconv.FormatInt(w.Offset, 10) + "): " + w.Message
+	default:
+		return w.Category + ": " + w.Message
+	}
+}
+
+// collectWarnings returns a copy of opts collecting the messages pdftotext
+// prints, which are still passed to opts.OnWarning, and a function returning
+// the collected warnings
+func collectWarnings(opts *Options) (*Options, func() []Warning) {
+	collecting := Options{}
+	if opts != nil {
+		collecting = *opts
+	}
+	var mu sync.Mutex
+	var warnings []Warning
+	onWarning := collecting.OnWarning
+	collecting.OnWarning = func(message string) {
+		mu.Lock()
+		warnings = append(warnings, ParseWarning(message))
+		mu.Unlock()
+		if onWarning != nil {
+			onWarning(message)
+		}
+	}
+	return &collecting, func() []Warning {
+		mu.Lock()
+		defer mu.Unlock()
+		return warnings
+	}
+}
@@ -0,0 +1,77 @@
+package pdftotext
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestParseWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected Warning
+	}{
+		{
+			name:     "Without offset",
+			line:     "Syntax Warning: Invalid Font Weight",
+			expected: Warning{Category: "Syntax Warning", Offset: -1, Message: "Invalid Font Weight"},
+		},
+		{
+			name:     "With offset",
+			line:     "Syntax Error (1234): Illegal character <2f> in hex string",
+			expected: Warning{Category: "Syntax Error", Offset: 1234, Message: "Illegal character <2f> in hex string"},
+		},
+		{
+			name:     "CRLF",
+			line:     "Internal Error: xref num 12 not found\r\n",
+			expected: Warning{Category: "Internal Error", Offset: -1, Message: "xref num 12 not found"},
+		},
+		{
+			name:     "Unknown category",
+			line:     "Mismatch between font type and embedded font file",
+			expected: Warning{Offset: -1, Message: "Mismatch between font type and embedded font file"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseWarning(tt.line)
+			if got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+			if again := ParseWarning(got.String()); again != got {
+				t.Errorf("expected %q to parse back to %+v, got %+v", got.String(), got, again)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertDocumentWarnings(t *testing.T) {
+	converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, args []string, stdout, stderr io.Writer) error {
+		io.WriteString(stderr, "Syntax Warning: Invalid Font Weight\nSyntax Error (42): Unknown operator 'Tx'\n")
+		_, err := io.WriteString(stdout, "page one\f")
+		return err
+	})))
+	if err != nil {
+		t.Fatalf("failed to create converter: %v", err)
+	}
+
+	var messages []string
+	doc, err := converter.ConvertDocument(context.Background(), "input.pdf", &Options{OnWarning: func(message string) {
+		messages = append(messages, message)
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Warning{
+		{Category: "Syntax Warning", Offset: -1, Message: "Invalid Font Weight"},
+		{Category: "Syntax Error", Offset: 42, Message: "Unknown operator 'Tx'"},
+	}
+	if !reflect.DeepEqual(doc.Warnings, expected) {
+		t.Errorf("expected warnings %+v, got %+v", expected, doc.Warnings)
+	}
+	if len(messages) != 2 {
+		t.Errorf("expected OnWarning to be called twice, got %q", messages)
+	}
+}
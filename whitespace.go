@@ -0,0 +1,83 @@
+package pdftotext
+
+import (
+	"strings"
+)
+
+// WhitespaceNormalizer is a Transformer cleaning up the whitespace of each
+// page. Each option is off by default.
+type WhitespaceNormalizer struct {
+	// CollapseSpaces replaces runs of spaces and tabs inside a line with a
+	// single space, keeping the indentation of the line
+	CollapseSpaces bool
+	// TrimTrailing removes the whitespace at the end of each line
+	TrimTrailing bool
+	// MaxBlankLines limits runs of blank lines to this length, if positive
+	MaxBlankLines int
+	// TrimPage removes the whitespace at the start and end of the page
+	TrimPage bool
+}
+
+// Transform normalizes the whitespace of text
+func (n WhitespaceNormalizer) Transform(page int, text string) (string, error) {
+	body, newline := strings.CutSuffix(text, "\n")
+	lines := strings.Split(body, "\n")
+	out := lines[:0]
+	blank := 0
+	for _, line := range lines {
+		cr := strings.HasSuffix(line, "\r")
+		line = strings.TrimSuffix(line, "\r")
+
+		if n.CollapseSpaces {
+			body := strings.TrimLeft(line, " \t")
+			line = line[:len(line)-len(body)] + collapseSpaces(body)
+		}
+		if n.TrimTrailing {
+			line = strings.TrimRight(line, " \t")
+		}
+
+		if strings.TrimSpace(line) == "" {
+			blank++
+			if n.MaxBlankLines > 0 && blank > n.MaxBlankLines {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		if cr {
+			line += "\r"
+		}
+		out = append(out, line)
+	}
+
+	text = strings.Join(out, "\n")
+	if newline {
+		text += "\n"
+	}
+	if n.TrimPage {
+		text = strings.TrimSpace(text)
+	}
+	return text, nil
+}
+
+// collapseSpaces replaces runs of spaces and tabs in s with a single space
+func collapseSpaces(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package pdftotext
+
+import "testing"
+
+func TestWhitespaceNormalizer_Transform(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        WhitespaceNormalizer
+		input    string
+		expected string
+	}{
+		{
+			name:     "disabled",
+			input:    "  a   b  \n\n\n\nc\n",
+			expected: "  a   b  \n\n\n\nc\n",
+		},
+		{
+			name:     "collapse spaces",
+			n:        WhitespaceNormalizer{CollapseSpaces: true},
+			input:    "    indented  \t text   here\nnext\t\tline",
+			expected: "    indented text here\nnext line",
+		},
+		{
+			name:     "trim trailing",
+			n:        WhitespaceNormalizer{TrimTrailing: true},
+			input:    "a  \t\nb \r\n  \nc",
+			expected: "a\nb\r\n\nc",
+		},
+		{
+			name:     "max blank lines",
+			n:        WhitespaceNormalizer{MaxBlankLines: 1},
+			input:    "a\n\n\n\nb\n \n\t\nc\n\n\n",
+			expected: "a\n\nb\n \nc\n\n",
+		},
+		{
+			name:     "trim page",
+			n:        WhitespaceNormalizer{TrimPage: true},
+			input:    "\n\n  title\nbody  \n\n",
+			expected: "title\nbody",
+		},
+		{
+			name:     "all",
+			n:        WhitespaceNormalizer{CollapseSpaces: true, TrimTrailing: true, MaxBlankLines: 1, TrimPage: true},
+			input:    "\n  a  b  \n\n\n\n  c   d\n\n",
+			expected: "a b\n\n  c d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.n.Transform(1, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package pdftotext
+
+import "fmt"
+
+// XpdfMode is a text layout mode of the Xpdf pdftotext, which poppler's
+// pdftotext does not have
+type XpdfMode string
+
+const (
+	// XpdfSimple is -simple, simple one-column page layout
+	XpdfSimple XpdfMode = "simple"
+	// XpdfSimple2 is -simple2, simple one-column page layout that also
+	// handles rotated text
+	XpdfSimple2 XpdfMode = "simple2"
+	// XpdfTable is -table, the layout for tabular data, keeping rows and
+	// columns aligned
+	XpdfTable XpdfMode = "table"
+	// XpdfLinePrinter is -lineprinter, strict fixed-pitch, fixed-height
+	// layout, usually with FixedPitch set
+	XpdfLinePrinter XpdfMode = "lineprinter"
+)
+
+// checkXpdfMode returns ErrUnsupportedOption if opts.XpdfMode is unknown,
+// combined with another layout mode, or set for a binary detected to be
+// poppler's pdftotext
+func (c *Converter) checkXpdfMode(opts *Options) error {
+	if opts == nil || opts.XpdfMode == "" {
+		return nil
+	}
+	switch opts.XpdfMode {
+	case XpdfSimple, XpdfSimple2, XpdfTable, XpdfLinePrinter:
+	default:
+		return fmt.Errorf("%w: unknown XpdfMode %q", ErrUnsupportedOption, opts.XpdfMode)
+	}
+	if opts.Layout || opts.Raw {
+		return fmt.Errorf("%w: XpdfMode %s replaces Layout and Raw", ErrUnsupportedOption, opts.XpdfMode)
+	}
+	if c.variant == VariantPoppler {
+		return fmt.Errorf("%w: XpdfMode %s needs the Xpdf pdftotext, the binary is poppler's", ErrUnsupportedOption, opts.XpdfMode)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestXpdfMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		opts    *Options
+		flag    string
+		wantErr error
+	}{
+		{"table", VariantXpdf, &Options{XpdfMode: XpdfTable}, "-table", nil},
+		{"lineprinter", VariantXpdf, &Options{XpdfMode: XpdfLinePrinter, FixedPitch: 7.2}, "-lineprinter", nil},
+		{"simple2 without startup check", "", &Options{XpdfMode: XpdfSimple2}, "-simple2", nil},
+		{"poppler", VariantPoppler, &Options{XpdfMode: XpdfSimple}, "", ErrUnsupportedOption},
+		{"unknown mode", VariantXpdf, &Options{XpdfMode: "columns"}, "", ErrUnsupportedOption},
+		{"with layout", VariantXpdf, &Options{XpdfMode: XpdfTable, Layout: true}, "", ErrUnsupportedOption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			converter, err := New(WithRunner(runnerFunc(func(ctx context.Context, name string, a []string, stdout, stderr io.Writer) error {
+				args = a
+				return nil
+			})))
+			if err != nil {
+				t.Fatalf("failed to create converter: %v", err)
+			}
+			converter.variant = tt.variant
+
+			_, err = converter.Convert(context.Background(), "input.pdf", tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if args != nil {
+					t.Errorf("expected no command to run, got %v", args)
+				}
+				if _, err := converter.Command("input.pdf", "-", tt.opts); !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected Command to fail with %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if !slices.Contains(args, tt.flag) || slices.Contains(args, "-layout") {
+				t.Errorf("expected %s in %v", tt.flag, args)
+			}
+		})
+	}
+}